@@ -86,6 +86,29 @@ func TestReadinessCheckValidate(t *testing.T) {
 				},
 			},
 		},
+		"ValidTypeMatchExpression": {
+			reason: "Type matchExpression should be valid",
+			args: args{
+				r: &ReadinessCheck{
+					Type:            ReadinessCheckTypeMatchExpression,
+					MatchExpression: "object.status.ready",
+				},
+			},
+		},
+		"InvalidTypeMatchExpressionMissingExpression": {
+			reason: "Type matchExpression without an expression should be invalid",
+			args: args{
+				r: &ReadinessCheck{
+					Type: ReadinessCheckTypeMatchExpression,
+				},
+			},
+			want: want{
+				output: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "matchExpression",
+				},
+			},
+		},
 		"InvalidType": {
 			reason: "Invalid type",
 			args: args{
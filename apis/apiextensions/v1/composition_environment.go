@@ -22,6 +22,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 
 	"github.com/crossplane/crossplane/internal/validation/errors"
 )
@@ -49,6 +50,17 @@ type EnvironmentConfiguration struct {
 	// +optional
 	EnvironmentConfigs []EnvironmentSource `json:"environmentConfigs,omitempty"`
 
+	// MergeStrategies configures how the values at specific keys of the
+	// selected EnvironmentConfigs are merged. Values at keys without a merge
+	// strategy are deep merged, meaning objects are merged recursively and
+	// values of EnvironmentConfigs with a larger index take priority over ones
+	// with smaller indices. Merge strategies don't apply to DefaultData, which
+	// is always overwritten by the selected EnvironmentConfigs.
+	// +optional
+	// +listType=map
+	// +listMapKey=key
+	MergeStrategies []EnvironmentKeyMergeStrategy `json:"mergeStrategies,omitempty"`
+
 	// Patches is a list of environment patches that are executed before a
 	// composition's resources are composed.
 	Patches []EnvironmentPatch `json:"patches,omitempty"`
@@ -75,6 +87,18 @@ func (e *EnvironmentConfiguration) Validate() field.ErrorList {
 		}
 	}
 
+	keys := make(map[string]bool, len(e.MergeStrategies))
+	for i, ms := range e.MergeStrategies {
+		if err := errors.WrapFieldError(ms.Validate(), field.NewPath("mergeStrategies").Index(i)); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if keys[ms.Key] {
+			errs = append(errs, field.Duplicate(field.NewPath("mergeStrategies").Index(i).Child("key"), ms.Key))
+		}
+		keys[ms.Key] = true
+	}
+
 	return errs
 }
 
@@ -100,6 +124,56 @@ func (e *EnvironmentConfiguration) IsRequired() bool {
 	return !e.Policy.IsResolutionPolicyOptional()
 }
 
+// An EnvironmentMergeStrategy specifies how the values of selected
+// EnvironmentConfigs at a key are merged.
+type EnvironmentMergeStrategy string
+
+const (
+	// EnvironmentMergeStrategyDeepMerge merges objects recursively. Any other
+	// value is replaced by the value of the later EnvironmentConfig.
+	EnvironmentMergeStrategyDeepMerge EnvironmentMergeStrategy = "DeepMerge"
+
+	// EnvironmentMergeStrategyLastWins replaces the value with the value of
+	// the later EnvironmentConfig, without merging objects.
+	EnvironmentMergeStrategyLastWins EnvironmentMergeStrategy = "LastWins"
+
+	// EnvironmentMergeStrategyFirstWins keeps the value of the first
+	// EnvironmentConfig that has one, without merging objects.
+	EnvironmentMergeStrategyFirstWins EnvironmentMergeStrategy = "FirstWins"
+
+	// EnvironmentMergeStrategyConcat concatenates lists. Any other value is
+	// replaced by the value of the later EnvironmentConfig.
+	EnvironmentMergeStrategyConcat EnvironmentMergeStrategy = "Concat"
+)
+
+// An EnvironmentKeyMergeStrategy specifies how the values of selected
+// EnvironmentConfigs at a key are merged.
+type EnvironmentKeyMergeStrategy struct {
+	// Key is the field path of the value within the data of the
+	// EnvironmentConfigs, for example `tags` or `network.subnets`.
+	Key string `json:"key"`
+
+	// Strategy specifies how the values at the key are merged.
+	// +kubebuilder:validation:Enum=DeepMerge;LastWins;FirstWins;Concat
+	Strategy EnvironmentMergeStrategy `json:"strategy"`
+}
+
+// Validate the EnvironmentKeyMergeStrategy.
+func (e *EnvironmentKeyMergeStrategy) Validate() *field.Error {
+	if e.Key == "" {
+		return field.Required(field.NewPath("key"), "key is required")
+	}
+	if _, err := fieldpath.Parse(e.Key); err != nil {
+		return field.Invalid(field.NewPath("key"), e.Key, err.Error())
+	}
+	switch e.Strategy {
+	case EnvironmentMergeStrategyDeepMerge, EnvironmentMergeStrategyLastWins, EnvironmentMergeStrategyFirstWins, EnvironmentMergeStrategyConcat:
+	default:
+		return field.Invalid(field.NewPath("strategy"), e.Strategy, "invalid strategy")
+	}
+	return nil
+}
+
 // EnvironmentSourceType specifies the way the EnvironmentConfig is selected.
 type EnvironmentSourceType string
 
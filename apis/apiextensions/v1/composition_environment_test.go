@@ -210,3 +210,74 @@ func TestEnvironmentSourceSelectorValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestEnvironmentKeyMergeStrategyValidate(t *testing.T) {
+
+	type args struct {
+		e *EnvironmentKeyMergeStrategy
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   *field.Error
+	}{
+		"Valid": {
+			reason: "Should accept a valid key and strategy",
+			args: args{
+				e: &EnvironmentKeyMergeStrategy{
+					Key:      "network.subnets",
+					Strategy: EnvironmentMergeStrategyConcat,
+				},
+			},
+		},
+		"ErrorEmptyKey": {
+			reason: "Should error out when key is empty",
+			args: args{
+				e: &EnvironmentKeyMergeStrategy{
+					Strategy: EnvironmentMergeStrategyConcat,
+				},
+			},
+			want: &field.Error{
+				Type:  field.ErrorTypeRequired,
+				Field: "key",
+			},
+		},
+		"ErrorInvalidKey": {
+			reason: "Should error out when key is not a valid field path",
+			args: args{
+				e: &EnvironmentKeyMergeStrategy{
+					Key:      "network[subnets",
+					Strategy: EnvironmentMergeStrategyConcat,
+				},
+			},
+			want: &field.Error{
+				Type:  field.ErrorTypeInvalid,
+				Field: "key",
+			},
+		},
+		"ErrorInvalidStrategy": {
+			reason: "Should error out when strategy is unknown",
+			args: args{
+				e: &EnvironmentKeyMergeStrategy{
+					Key:      "tags",
+					Strategy: "Shuffle",
+				},
+			},
+			want: &field.Error{
+				Type:  field.ErrorTypeInvalid,
+				Field: "strategy",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+
+		t.Run(name, func(t *testing.T) {
+			got := tc.args.e.Validate()
+			if diff := cmp.Diff(tc.want, got, cmpopts.IgnoreFields(field.Error{}, "Detail", "BadValue")); diff != "" {
+				t.Errorf("%s\nValidate(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
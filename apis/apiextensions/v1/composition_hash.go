@@ -40,7 +40,12 @@ func (c Composition) Hash() string {
 		return "unknown"
 	}
 
-	s, err := yaml.Marshal(c.Spec)
+	// The rollout controls which composite resources use a revision, not what
+	// the revision does, so changing it doesn't produce a new revision.
+	spec := c.Spec
+	spec.Rollout = nil
+
+	s, err := yaml.Marshal(spec)
 	if err != nil {
 		return "unknown"
 	}
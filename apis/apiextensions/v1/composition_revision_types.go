@@ -95,6 +95,33 @@ type CompositionRevisionSpec struct {
 	// +optional
 	Pipeline []PipelineStep `json:"pipeline,omitempty"`
 
+	// DeletionOrder declares which composed resources must be deleted before
+	// others when a composite resource is deleted. Composed resources are
+	// identified by their names, i.e. their crossplane.io/composition-resource-name
+	// annotation. When it is specified Crossplane deletes the composed
+	// resources before it removes the composite resource's finalizer, and only
+	// deletes each once the resources it must be deleted after are gone.
+	// Composed resources that aren't mentioned are deleted immediately. The
+	// order is not honored when the composite resource is deleted using
+	// foreground cascading deletion.
+	// +optional
+	DeletionOrder []DeletionDependency `json:"deletionOrder,omitempty"`
+
+	// ConnectionDetailTemplates render composite resource connection details
+	// from the connection details of several composed resources, for example
+	// to build a connection string from one resource's endpoint and another's
+	// credentials. They're rendered after the connection details of composed
+	// resources are extracted, and take precedence over extracted connection
+	// details with the same name. The names must be listed in the composite
+	// resource definition's connectionSecretKeys, if it declares any.
+	//
+	// ConnectionDetailTemplates are only used by the "Resources" mode of
+	// Composition. They are ignored by other modes.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	ConnectionDetailTemplates []ConnectionDetailTemplate `json:"connectionDetailTemplates,omitempty"`
+
 	// WriteConnectionSecretsToNamespace specifies the namespace in which the
 	// connection secrets of composite resource dynamically provisioned using
 	// this composition will be created.
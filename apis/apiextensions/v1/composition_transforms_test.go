@@ -266,6 +266,24 @@ func TestTransformValidate(t *testing.T) {
 				},
 			},
 		},
+		"InvalidStringTemplate": {
+			reason: "String transform with a template that can't be parsed should be invalid",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeString,
+					String: &StringTransform{
+						Type:     StringTransformTypeTemplate,
+						Template: ptr.To("{{ .host "),
+					},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "string.template",
+				},
+			},
+		},
 		"InvalidConvertMissingConvert": {
 			reason: "Convert transform missing Convert should be invalid",
 			args: args{
@@ -81,6 +81,33 @@ type CompositionSpec struct {
 	// +optional
 	Pipeline []PipelineStep `json:"pipeline,omitempty"`
 
+	// DeletionOrder declares which composed resources must be deleted before
+	// others when a composite resource is deleted. Composed resources are
+	// identified by their names, i.e. their crossplane.io/composition-resource-name
+	// annotation. When it is specified Crossplane deletes the composed
+	// resources before it removes the composite resource's finalizer, and only
+	// deletes each once the resources it must be deleted after are gone.
+	// Composed resources that aren't mentioned are deleted immediately. The
+	// order is not honored when the composite resource is deleted using
+	// foreground cascading deletion.
+	// +optional
+	DeletionOrder []DeletionDependency `json:"deletionOrder,omitempty"`
+
+	// ConnectionDetailTemplates render composite resource connection details
+	// from the connection details of several composed resources, for example
+	// to build a connection string from one resource's endpoint and another's
+	// credentials. They're rendered after the connection details of composed
+	// resources are extracted, and take precedence over extracted connection
+	// details with the same name. The names must be listed in the composite
+	// resource definition's connectionSecretKeys, if it declares any.
+	//
+	// ConnectionDetailTemplates are only used by the "Resources" mode of
+	// Composition. A "Pipeline" mode Composition can't specify them.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	ConnectionDetailTemplates []ConnectionDetailTemplate `json:"connectionDetailTemplates,omitempty"`
+
 	// WriteConnectionSecretsToNamespace specifies the namespace in which the
 	// connection secrets of composite resource dynamically provisioned using
 	// this composition will be created.
@@ -102,6 +129,47 @@ type CompositionSpec struct {
 	// +optional
 	// +kubebuilder:default={"name": "default"}
 	PublishConnectionDetailsWithStoreConfigRef *StoreConfigReference `json:"publishConnectionDetailsWithStoreConfigRef,omitempty"`
+
+	// Rollout controls which composite resources with an Automatic
+	// composition update policy are updated to the latest CompositionRevision.
+	// By default they are all updated as soon as a new CompositionRevision is
+	// created. Changing the rollout doesn't create a new CompositionRevision.
+	// +optional
+	Rollout *CompositionRollout `json:"rollout,omitempty"`
+}
+
+// A CompositionRollout controls which composite resources are updated to the
+// latest CompositionRevision. A composite resource is updated if it's selected
+// by either the percentage or the selector. Composite resources that aren't
+// updated keep using their current CompositionRevision. New composite
+// resources always use the latest CompositionRevision.
+type CompositionRollout struct {
+	// Percentage of composite resources to update to the latest
+	// CompositionRevision. Composite resources are consistently assigned to a
+	// percentile by their UID, so increasing the percentage only ever updates
+	// more composite resources.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Percentage *int32 `json:"percentage,omitempty"`
+
+	// Selector selects composite resources to update to the latest
+	// CompositionRevision by their labels.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// PauseOnError pauses the rollout while any composite resource that uses
+	// the latest CompositionRevision fails to sync, for example because it
+	// can't apply its composed resources.
+	// +optional
+	// +kubebuilder:default=true
+	PauseOnError *bool `json:"pauseOnError,omitempty"`
+}
+
+// ShouldPauseOnError returns true if the rollout should pause while composite
+// resources that use the latest CompositionRevision fail to sync.
+func (r *CompositionRollout) ShouldPauseOnError() bool {
+	return r.PauseOnError == nil || *r.PauseOnError
 }
 
 // +kubebuilder:object:root=true
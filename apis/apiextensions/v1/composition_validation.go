@@ -17,6 +17,9 @@ limitations under the License.
 package v1
 
 import (
+	"sort"
+	"text/template"
+
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
@@ -33,6 +36,8 @@ func (c *Composition) Validate() (warns []string, errs field.ErrorList) {
 		c.validateResources,
 		c.validatePipeline,
 		c.validateEnvironment,
+		c.validateDeletionOrder,
+		c.validateConnectionDetailTemplates,
 	}
 	for _, f := range validations {
 		errs = append(errs, f()...)
@@ -57,6 +62,9 @@ func (c *Composition) validateMode() (errs field.ErrorList) {
 		if len(c.Spec.Pipeline) == 0 {
 			errs = append(errs, field.Required(field.NewPath("spec", "pipeline"), "an array of pipeline steps is required in Pipeline mode"))
 		}
+		if len(c.Spec.ConnectionDetailTemplates) > 0 {
+			errs = append(errs, field.Forbidden(field.NewPath("spec", "connectionDetailTemplates"), "connection detail templates are only supported in Resources mode"))
+		}
 	}
 
 	return errs
@@ -184,3 +192,110 @@ func (c *Composition) validateEnvironment() field.ErrorList {
 	}
 	return nil
 }
+
+// validateDeletionOrder checks that:
+//   - every deletion dependency names a composed resource
+//   - in Resources mode, only composed resources that are templated are named
+//   - there are no cycles, which would prevent composed resources from ever
+//     being deleted
+func (c *Composition) validateDeletionOrder() (errs field.ErrorList) {
+	if len(c.Spec.DeletionOrder) == 0 {
+		return nil
+	}
+
+	// We only know the names of composed resources up front in Resources
+	// mode. In Pipeline mode they're only known once the functions have run.
+	var names map[string]bool
+	if c.Spec.Mode == nil || *c.Spec.Mode == CompositionModeResources {
+		names = make(map[string]bool, len(c.Spec.Resources))
+		for _, r := range c.Spec.Resources {
+			names[r.GetName()] = true
+		}
+	}
+
+	after := make(map[string][]string, len(c.Spec.DeletionOrder))
+	for i, d := range c.Spec.DeletionOrder {
+		p := field.NewPath("spec", "deletionOrder").Index(i)
+		if d.Resource == "" {
+			errs = append(errs, field.Required(p.Child("resource"), "must be the name of a composed resource"))
+			continue
+		}
+		if names != nil && !names[d.Resource] {
+			errs = append(errs, field.Invalid(p.Child("resource"), d.Resource, "must be the name of a composed resource"))
+		}
+		for j, a := range d.After {
+			if a == "" || (names != nil && !names[a]) {
+				errs = append(errs, field.Invalid(p.Child("after").Index(j), a, "must be the name of a composed resource"))
+			}
+		}
+		after[d.Resource] = append(after[d.Resource], d.After...)
+	}
+
+	if r := deletionCycle(after); r != "" {
+		errs = append(errs, field.Invalid(field.NewPath("spec", "deletionOrder"), r, "composed resource must not (transitively) be deleted after itself"))
+	}
+
+	return errs
+}
+
+// validateConnectionDetailTemplates checks that connection detail templates
+// have unique names, and templates that can be parsed.
+func (c *Composition) validateConnectionDetailTemplates() (errs field.ErrorList) {
+	seen := make(map[string]bool, len(c.Spec.ConnectionDetailTemplates))
+	for i, t := range c.Spec.ConnectionDetailTemplates {
+		p := field.NewPath("spec", "connectionDetailTemplates").Index(i)
+		if t.Name == "" {
+			errs = append(errs, field.Required(p.Child("name"), "must be the name of a connection detail"))
+		}
+		if seen[t.Name] {
+			errs = append(errs, field.Duplicate(p.Child("name"), t.Name))
+		}
+		seen[t.Name] = true
+		if _, err := template.New(t.Name).Parse(t.Template); err != nil {
+			errs = append(errs, field.Invalid(p.Child("template"), t.Template, err.Error()))
+		}
+	}
+	return errs
+}
+
+// deletionCycle returns the name of a composed resource that is part of a
+// cycle in the supplied deletion dependencies, or an empty string if there
+// are no cycles.
+func deletionCycle(after map[string][]string) string {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int, len(after))
+
+	var visit func(n string) string
+	visit = func(n string) string {
+		switch state[n] {
+		case visiting:
+			return n
+		case visited:
+			return ""
+		}
+		state[n] = visiting
+		for _, a := range after[n] {
+			if r := visit(a); r != "" {
+				return r
+			}
+		}
+		state[n] = visited
+		return ""
+	}
+
+	// Iterate in a stable order so we always report the same resource.
+	resources := make([]string, 0, len(after))
+	for n := range after {
+		resources = append(resources, n)
+	}
+	sort.Strings(resources)
+	for _, n := range resources {
+		if r := visit(n); r != "" {
+			return r
+		}
+	}
+	return ""
+}
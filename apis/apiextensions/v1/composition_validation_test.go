@@ -124,6 +124,25 @@ func TestCompositionValidateMode(t *testing.T) {
 				output: field.ErrorList{field.Required(field.NewPath("spec", "pipeline"), "this test ignores this field")},
 			},
 		},
+		"InvalidPipelineConnectionDetailTemplates": {
+			reason: "A Pipeline mode Composition with connection detail templates is invalid",
+			args: args{
+				spec: CompositionSpec{
+					Mode: &pipeline,
+					Pipeline: []PipelineStep{
+						{
+							Step: "razor",
+						},
+					},
+					ConnectionDetailTemplates: []ConnectionDetailTemplate{
+						{Name: "url", Template: "https://{{ .db.endpoint }}"},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{field.Forbidden(field.NewPath("spec", "connectionDetailTemplates"), "this test ignores this field")},
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -521,6 +540,113 @@ func TestCompositionValidatePipeline(t *testing.T) {
 	}
 }
 
+func TestCompositionValidateDeletionOrder(t *testing.T) {
+	type args struct {
+		comp *Composition
+	}
+	type want struct {
+		output field.ErrorList
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ValidNoDeletionOrder": {
+			reason: "no deletion order should be valid",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{},
+				},
+			},
+		},
+		"ValidDeletionOrder": {
+			reason: "a deletion order that names templated resources should be valid",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						Resources: []ComposedTemplate{
+							{Name: ptr.To("zone")},
+							{Name: ptr.To("record-a")},
+							{Name: ptr.To("record-b")},
+						},
+						DeletionOrder: []DeletionDependency{
+							{Resource: "zone", After: []string{"record-a", "record-b"}},
+						},
+					},
+				},
+			},
+		},
+		"ValidPipelineDeletionOrder": {
+			reason: "a deletion order may name any resource in Pipeline mode",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						Mode: ptr.To(CompositionModePipeline),
+						DeletionOrder: []DeletionDependency{
+							{Resource: "zone", After: []string{"record"}},
+						},
+					},
+				},
+			},
+		},
+		"InvalidUnknownResource": {
+			reason: "a deletion order must only name templated resources in Resources mode",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						Resources: []ComposedTemplate{
+							{Name: ptr.To("zone")},
+						},
+						DeletionOrder: []DeletionDependency{
+							{Resource: "zone", After: []string{"record"}},
+						},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeInvalid,
+						Field: "spec.deletionOrder[0].after[0]",
+					},
+				},
+			},
+		},
+		"InvalidCycle": {
+			reason: "a deletion order must not contain cycles",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						Mode: ptr.To(CompositionModePipeline),
+						DeletionOrder: []DeletionDependency{
+							{Resource: "a", After: []string{"b"}},
+							{Resource: "b", After: []string{"c"}},
+							{Resource: "c", After: []string{"a"}},
+						},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeInvalid,
+						Field: "spec.deletionOrder",
+					},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			gotErrs := tc.args.comp.validateDeletionOrder()
+			if diff := cmp.Diff(tc.want.output, gotErrs, sortFieldErrors(), cmpopts.IgnoreFields(field.Error{}, "Detail", "BadValue")); diff != "" {
+				t.Errorf("%s\nvalidateDeletionOrder(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestCompositionValidateResources(t *testing.T) {
 	type args struct {
 		comp *Composition
@@ -910,3 +1036,78 @@ func TestCompositionValidateEnvironment(t *testing.T) {
 		})
 	}
 }
+
+func TestCompositionValidateConnectionDetailTemplates(t *testing.T) {
+	type args struct {
+		comp *Composition
+	}
+	type want struct {
+		output field.ErrorList
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ValidNoConnectionDetailTemplates": {
+			reason: "no connection detail templates should be valid",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{},
+				},
+			},
+		},
+		"ValidConnectionDetailTemplates": {
+			reason: "connection detail templates with unique names and valid templates should be valid",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						ConnectionDetailTemplates: []ConnectionDetailTemplate{
+							{Name: "endpoint", Template: "{{ .db.host }}:{{ .db.port }}"},
+							{Name: "username", Template: "{{ .user.username }}"},
+						},
+					},
+				},
+			},
+		},
+		"InvalidConnectionDetailTemplates": {
+			reason: "connection detail templates must have unique names and templates that can be parsed",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						ConnectionDetailTemplates: []ConnectionDetailTemplate{
+							{Name: "endpoint", Template: "{{ .db.host }}"},
+							{Name: "endpoint", Template: "{{ .db.host "},
+							{Template: "{{ .db.port }}"},
+						},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeDuplicate,
+						Field: "spec.connectionDetailTemplates[1].name",
+					},
+					{
+						Type:  field.ErrorTypeInvalid,
+						Field: "spec.connectionDetailTemplates[1].template",
+					},
+					{
+						Type:  field.ErrorTypeRequired,
+						Field: "spec.connectionDetailTemplates[2].name",
+					},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			gotErrs := tc.args.comp.validateConnectionDetailTemplates()
+			if diff := cmp.Diff(tc.want.output, gotErrs, sortFieldErrors(), cmpopts.IgnoreFields(field.Error{}, "Detail", "BadValue")); diff != "" {
+				t.Errorf("%s\nvalidateConnectionDetailTemplates(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
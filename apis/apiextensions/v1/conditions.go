@@ -43,6 +43,11 @@ const (
 	ReasonTerminatingClaim     xpv1.ConditionReason = "TerminatingCompositeResourceClaim"
 )
 
+// Reasons a composite resource is not ready.
+const (
+	ReasonDegraded xpv1.ConditionReason = "Degraded"
+)
+
 // WatchingComposite indicates that Crossplane has defined and is watching for a
 // new kind of composite resource.
 func WatchingComposite() xpv1.Condition {
@@ -86,3 +91,14 @@ func TerminatingClaim() xpv1.Condition {
 		Reason:             ReasonTerminatingClaim,
 	}
 }
+
+// Degraded indicates that a composite resource has composed resources that
+// have been unready for longer than their readiness timeout.
+func Degraded() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               xpv1.TypeReady,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonDegraded,
+	}
+}
@@ -50,6 +50,14 @@ type CompositeResourceDefinitionSpec struct {
 	// +optional
 	ClaimNames *extv1.CustomResourceDefinitionNames `json:"claimNames,omitempty"`
 
+	// ClaimAdmissionRules are evaluated by Crossplane's admission webhook when
+	// a composite resource claim is created or updated. A claim is only
+	// admitted if every rule evaluates to true. This is an alpha feature, and
+	// is ignored unless Crossplane is started with claim admission rules
+	// enabled.
+	// +optional
+	ClaimAdmissionRules []ClaimAdmissionRule `json:"claimAdmissionRules,omitempty"`
+
 	// ConnectionSecretKeys is the list of keys that will be exposed to the end
 	// user of the defined kind.
 	// If the list is empty, all keys will be published.
@@ -101,6 +109,21 @@ type CompositeResourceDefinitionSpec struct {
 	Metadata *CompositeResourceDefinitionSpecMetadata `json:"metadata,omitempty"`
 }
 
+// A ClaimAdmissionRule is a CEL expression that must evaluate to true for a
+// composite resource claim to be admitted.
+type ClaimAdmissionRule struct {
+	// Rule is a CEL expression that must evaluate to a boolean. It may
+	// reference the claim being admitted as 'object', the existing claim as
+	// 'oldObject' (null on create), the requesting user as 'request.userInfo',
+	// and the claim's namespace as 'namespaceObject'. For example:
+	// "object.spec.size != 'xlarge' || namespaceObject.metadata.labels.tier == 'gold'"
+	Rule string `json:"rule"`
+
+	// Message returned to the user when the rule evaluates to false.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
 // A CompositionReference references a Composition.
 type CompositionReference struct {
 	// Name of the Composition.
@@ -4,10 +4,12 @@
 package v1
 
 import (
-	v11 "github.com/crossplane/crossplane-runtime/apis/common/v1"
-	v12 "k8s.io/api/core/v1"
-	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	v12 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	v13 "k8s.io/api/core/v1"
+	v11 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"time"
 )
 
 type GeneratedRevisionSpecConverter struct{}
@@ -46,6 +48,22 @@ func (c *GeneratedRevisionSpecConverter) FromRevisionSpec(source CompositionRevi
 		}
 	}
 	v1CompositionSpec.Pipeline = v1PipelineStepList
+	var v1DeletionDependencyList []DeletionDependency
+	if source.DeletionOrder != nil {
+		v1DeletionDependencyList = make([]DeletionDependency, len(source.DeletionOrder))
+		for l := 0; l < len(source.DeletionOrder); l++ {
+			v1DeletionDependencyList[l] = c.v1DeletionDependencyToV1DeletionDependency(source.DeletionOrder[l])
+		}
+	}
+	v1CompositionSpec.DeletionOrder = v1DeletionDependencyList
+	var v1ConnectionDetailTemplateList []ConnectionDetailTemplate
+	if source.ConnectionDetailTemplates != nil {
+		v1ConnectionDetailTemplateList = make([]ConnectionDetailTemplate, len(source.ConnectionDetailTemplates))
+		for m := 0; m < len(source.ConnectionDetailTemplates); m++ {
+			v1ConnectionDetailTemplateList[m] = c.v1ConnectionDetailTemplateToV1ConnectionDetailTemplate(source.ConnectionDetailTemplates[m])
+		}
+	}
+	v1CompositionSpec.ConnectionDetailTemplates = v1ConnectionDetailTemplateList
 	var pString *string
 	if source.WriteConnectionSecretsToNamespace != nil {
 		xstring := *source.WriteConnectionSecretsToNamespace
@@ -89,6 +107,22 @@ func (c *GeneratedRevisionSpecConverter) ToRevisionSpec(source CompositionSpec)
 		}
 	}
 	v1CompositionRevisionSpec.Pipeline = v1PipelineStepList
+	var v1DeletionDependencyList []DeletionDependency
+	if source.DeletionOrder != nil {
+		v1DeletionDependencyList = make([]DeletionDependency, len(source.DeletionOrder))
+		for l := 0; l < len(source.DeletionOrder); l++ {
+			v1DeletionDependencyList[l] = c.v1DeletionDependencyToV1DeletionDependency(source.DeletionOrder[l])
+		}
+	}
+	v1CompositionRevisionSpec.DeletionOrder = v1DeletionDependencyList
+	var v1ConnectionDetailTemplateList []ConnectionDetailTemplate
+	if source.ConnectionDetailTemplates != nil {
+		v1ConnectionDetailTemplateList = make([]ConnectionDetailTemplate, len(source.ConnectionDetailTemplates))
+		for m := 0; m < len(source.ConnectionDetailTemplates); m++ {
+			v1ConnectionDetailTemplateList[m] = c.v1ConnectionDetailTemplateToV1ConnectionDetailTemplate(source.ConnectionDetailTemplates[m])
+		}
+	}
+	v1CompositionRevisionSpec.ConnectionDetailTemplates = v1ConnectionDetailTemplateList
 	var pString *string
 	if source.WriteConnectionSecretsToNamespace != nil {
 		xstring := *source.WriteConnectionSecretsToNamespace
@@ -124,6 +158,15 @@ func (c *GeneratedRevisionSpecConverter) pV1CombineToPV1Combine(source *Combine)
 	}
 	return pV1Combine
 }
+func (c *GeneratedRevisionSpecConverter) pV1ComposedResourceAdoptionToPV1ComposedResourceAdoption(source *ComposedResourceAdoption) *ComposedResourceAdoption {
+	var pV1ComposedResourceAdoption *ComposedResourceAdoption
+	if source != nil {
+		var v1ComposedResourceAdoption ComposedResourceAdoption
+		v1ComposedResourceAdoption.MatchBy = AdoptionMatchType((*source).MatchBy)
+		pV1ComposedResourceAdoption = &v1ComposedResourceAdoption
+	}
+	return pV1ComposedResourceAdoption
+}
 func (c *GeneratedRevisionSpecConverter) pV1ConvertTransformToPV1ConvertTransform(source *ConvertTransform) *ConvertTransform {
 	var pV1ConvertTransform *ConvertTransform
 	if source != nil {
@@ -139,13 +182,22 @@ func (c *GeneratedRevisionSpecConverter) pV1ConvertTransformToPV1ConvertTransfor
 	}
 	return pV1ConvertTransform
 }
+func (c *GeneratedRevisionSpecConverter) pV1DurationToPV1Duration(source *v1.Duration) *v1.Duration {
+	var pV1Duration *v1.Duration
+	if source != nil {
+		var v1Duration v1.Duration
+		v1Duration.Duration = time.Duration((*source).Duration)
+		pV1Duration = &v1Duration
+	}
+	return pV1Duration
+}
 func (c *GeneratedRevisionSpecConverter) pV1EnvironmentConfigurationToPV1EnvironmentConfiguration(source *EnvironmentConfiguration) *EnvironmentConfiguration {
 	var pV1EnvironmentConfiguration *EnvironmentConfiguration
 	if source != nil {
 		var v1EnvironmentConfiguration EnvironmentConfiguration
-		var mapStringV1JSON map[string]v1.JSON
+		var mapStringV1JSON map[string]v11.JSON
 		if (*source).DefaultData != nil {
-			mapStringV1JSON = make(map[string]v1.JSON, len((*source).DefaultData))
+			mapStringV1JSON = make(map[string]v11.JSON, len((*source).DefaultData))
 			for key, value := range (*source).DefaultData {
 				mapStringV1JSON[key] = c.v1JSONToV1JSON(value)
 			}
@@ -159,11 +211,19 @@ func (c *GeneratedRevisionSpecConverter) pV1EnvironmentConfigurationToPV1Environ
 			}
 		}
 		v1EnvironmentConfiguration.EnvironmentConfigs = v1EnvironmentSourceList
+		var v1EnvironmentKeyMergeStrategyList []EnvironmentKeyMergeStrategy
+		if (*source).MergeStrategies != nil {
+			v1EnvironmentKeyMergeStrategyList = make([]EnvironmentKeyMergeStrategy, len((*source).MergeStrategies))
+			for j := 0; j < len((*source).MergeStrategies); j++ {
+				v1EnvironmentKeyMergeStrategyList[j] = c.v1EnvironmentKeyMergeStrategyToV1EnvironmentKeyMergeStrategy((*source).MergeStrategies[j])
+			}
+		}
+		v1EnvironmentConfiguration.MergeStrategies = v1EnvironmentKeyMergeStrategyList
 		var v1EnvironmentPatchList []EnvironmentPatch
 		if (*source).Patches != nil {
 			v1EnvironmentPatchList = make([]EnvironmentPatch, len((*source).Patches))
-			for j := 0; j < len((*source).Patches); j++ {
-				v1EnvironmentPatchList[j] = c.v1EnvironmentPatchToV1EnvironmentPatch((*source).Patches[j])
+			for k := 0; k < len((*source).Patches); k++ {
+				v1EnvironmentPatchList[k] = c.v1EnvironmentPatchToV1EnvironmentPatch((*source).Patches[k])
 			}
 		}
 		v1EnvironmentConfiguration.Patches = v1EnvironmentPatchList
@@ -215,9 +275,9 @@ func (c *GeneratedRevisionSpecConverter) pV1MapTransformToPV1MapTransform(source
 	var pV1MapTransform *MapTransform
 	if source != nil {
 		var v1MapTransform MapTransform
-		var mapStringV1JSON map[string]v1.JSON
+		var mapStringV1JSON map[string]v11.JSON
 		if (*source).Pairs != nil {
-			mapStringV1JSON = make(map[string]v1.JSON, len((*source).Pairs))
+			mapStringV1JSON = make(map[string]v11.JSON, len((*source).Pairs))
 			for key, value := range (*source).Pairs {
 				mapStringV1JSON[key] = c.v1JSONToV1JSON(value)
 			}
@@ -231,8 +291,8 @@ func (c *GeneratedRevisionSpecConverter) pV1MatchConditionReadinessCheckToPV1Mat
 	var pV1MatchConditionReadinessCheck *MatchConditionReadinessCheck
 	if source != nil {
 		var v1MatchConditionReadinessCheck MatchConditionReadinessCheck
-		v1MatchConditionReadinessCheck.Type = v11.ConditionType((*source).Type)
-		v1MatchConditionReadinessCheck.Status = v12.ConditionStatus((*source).Status)
+		v1MatchConditionReadinessCheck.Type = v12.ConditionType((*source).Type)
+		v1MatchConditionReadinessCheck.Status = v13.ConditionStatus((*source).Status)
 		pV1MatchConditionReadinessCheck = &v1MatchConditionReadinessCheck
 	}
 	return pV1MatchConditionReadinessCheck
@@ -282,10 +342,10 @@ func (c *GeneratedRevisionSpecConverter) pV1MathTransformToPV1MathTransform(sour
 	}
 	return pV1MathTransform
 }
-func (c *GeneratedRevisionSpecConverter) pV1MergeOptionsToPV1MergeOptions(source *v11.MergeOptions) *v11.MergeOptions {
-	var pV1MergeOptions *v11.MergeOptions
+func (c *GeneratedRevisionSpecConverter) pV1MergeOptionsToPV1MergeOptions(source *v12.MergeOptions) *v12.MergeOptions {
+	var pV1MergeOptions *v12.MergeOptions
 	if source != nil {
-		var v1MergeOptions v11.MergeOptions
+		var v1MergeOptions v12.MergeOptions
 		var pBool *bool
 		if (*source).KeepMapValues != nil {
 			xbool := *(*source).KeepMapValues
@@ -317,19 +377,19 @@ func (c *GeneratedRevisionSpecConverter) pV1PatchPolicyToPV1PatchPolicy(source *
 	}
 	return pV1PatchPolicy
 }
-func (c *GeneratedRevisionSpecConverter) pV1PolicyToPV1Policy(source *v11.Policy) *v11.Policy {
-	var pV1Policy *v11.Policy
+func (c *GeneratedRevisionSpecConverter) pV1PolicyToPV1Policy(source *v12.Policy) *v12.Policy {
+	var pV1Policy *v12.Policy
 	if source != nil {
-		var v1Policy v11.Policy
-		var pV1ResolvePolicy *v11.ResolvePolicy
+		var v1Policy v12.Policy
+		var pV1ResolvePolicy *v12.ResolvePolicy
 		if (*source).Resolve != nil {
-			v1ResolvePolicy := v11.ResolvePolicy(*(*source).Resolve)
+			v1ResolvePolicy := v12.ResolvePolicy(*(*source).Resolve)
 			pV1ResolvePolicy = &v1ResolvePolicy
 		}
 		v1Policy.Resolve = pV1ResolvePolicy
-		var pV1ResolutionPolicy *v11.ResolutionPolicy
+		var pV1ResolutionPolicy *v12.ResolutionPolicy
 		if (*source).Resolution != nil {
-			v1ResolutionPolicy := v11.ResolutionPolicy(*(*source).Resolution)
+			v1ResolutionPolicy := v12.ResolutionPolicy(*(*source).Resolution)
 			pV1ResolutionPolicy = &v1ResolutionPolicy
 		}
 		v1Policy.Resolution = pV1ResolutionPolicy
@@ -404,6 +464,12 @@ func (c *GeneratedRevisionSpecConverter) pV1StringTransformToPV1StringTransform(
 		v1StringTransform.Trim = pString2
 		v1StringTransform.Regexp = c.pV1StringTransformRegexpToPV1StringTransformRegexp((*source).Regexp)
 		v1StringTransform.Join = c.pV1StringTransformJoinToPV1StringTransformJoin((*source).Join)
+		var pString3 *string
+		if (*source).Template != nil {
+			xstring3 := *(*source).Template
+			pString3 = &xstring3
+		}
+		v1StringTransform.Template = pString3
 		pV1StringTransform = &v1StringTransform
 	}
 	return pV1StringTransform
@@ -446,8 +512,16 @@ func (c *GeneratedRevisionSpecConverter) v1ComposedTemplateToV1ComposedTemplate(
 		}
 	}
 	v1ComposedTemplate.ReadinessChecks = v1ReadinessCheckList
+	v1ComposedTemplate.ReadinessTimeout = c.pV1DurationToPV1Duration(source.ReadinessTimeout)
+	v1ComposedTemplate.Adoption = c.pV1ComposedResourceAdoptionToPV1ComposedResourceAdoption(source.Adoption)
 	return v1ComposedTemplate
 }
+func (c *GeneratedRevisionSpecConverter) v1ConnectionDetailTemplateToV1ConnectionDetailTemplate(source ConnectionDetailTemplate) ConnectionDetailTemplate {
+	var v1ConnectionDetailTemplate ConnectionDetailTemplate
+	v1ConnectionDetailTemplate.Name = source.Name
+	v1ConnectionDetailTemplate.Template = source.Template
+	return v1ConnectionDetailTemplate
+}
 func (c *GeneratedRevisionSpecConverter) v1ConnectionDetailToV1ConnectionDetail(source ConnectionDetail) ConnectionDetail {
 	var v1ConnectionDetail ConnectionDetail
 	var pString *string
@@ -482,6 +556,25 @@ func (c *GeneratedRevisionSpecConverter) v1ConnectionDetailToV1ConnectionDetail(
 	v1ConnectionDetail.Value = pString4
 	return v1ConnectionDetail
 }
+func (c *GeneratedRevisionSpecConverter) v1DeletionDependencyToV1DeletionDependency(source DeletionDependency) DeletionDependency {
+	var v1DeletionDependency DeletionDependency
+	v1DeletionDependency.Resource = source.Resource
+	var stringList []string
+	if source.After != nil {
+		stringList = make([]string, len(source.After))
+		for i := 0; i < len(source.After); i++ {
+			stringList[i] = source.After[i]
+		}
+	}
+	v1DeletionDependency.After = stringList
+	return v1DeletionDependency
+}
+func (c *GeneratedRevisionSpecConverter) v1EnvironmentKeyMergeStrategyToV1EnvironmentKeyMergeStrategy(source EnvironmentKeyMergeStrategy) EnvironmentKeyMergeStrategy {
+	var v1EnvironmentKeyMergeStrategy EnvironmentKeyMergeStrategy
+	v1EnvironmentKeyMergeStrategy.Key = source.Key
+	v1EnvironmentKeyMergeStrategy.Strategy = EnvironmentMergeStrategy(source.Strategy)
+	return v1EnvironmentKeyMergeStrategy
+}
 func (c *GeneratedRevisionSpecConverter) v1EnvironmentPatchToV1EnvironmentPatch(source EnvironmentPatch) EnvironmentPatch {
 	var v1EnvironmentPatch EnvironmentPatch
 	v1EnvironmentPatch.Type = PatchType(source.Type)
@@ -545,8 +638,8 @@ func (c *GeneratedRevisionSpecConverter) v1FunctionReferenceToV1FunctionReferenc
 	v1FunctionReference.Name = source.Name
 	return v1FunctionReference
 }
-func (c *GeneratedRevisionSpecConverter) v1JSONToV1JSON(source v1.JSON) v1.JSON {
-	var v1JSON v1.JSON
+func (c *GeneratedRevisionSpecConverter) v1JSONToV1JSON(source v11.JSON) v11.JSON {
+	var v1JSON v11.JSON
 	var byteList []uint8
 	if source.Raw != nil {
 		byteList = make([]uint8, len(source.Raw))
@@ -626,6 +719,12 @@ func (c *GeneratedRevisionSpecConverter) v1PipelineStepToV1PipelineStep(source P
 	v1PipelineStep.Step = source.Step
 	v1PipelineStep.FunctionRef = c.v1FunctionReferenceToV1FunctionReference(source.FunctionRef)
 	v1PipelineStep.Input = c.pRuntimeRawExtensionToPRuntimeRawExtension(source.Input)
+	var pString *string
+	if source.Condition != nil {
+		xstring := *source.Condition
+		pString = &xstring
+	}
+	v1PipelineStep.Condition = pString
 	return v1PipelineStep
 }
 func (c *GeneratedRevisionSpecConverter) v1ReadinessCheckToV1ReadinessCheck(source ReadinessCheck) ReadinessCheck {
@@ -635,6 +734,7 @@ func (c *GeneratedRevisionSpecConverter) v1ReadinessCheckToV1ReadinessCheck(sour
 	v1ReadinessCheck.MatchString = source.MatchString
 	v1ReadinessCheck.MatchInteger = source.MatchInteger
 	v1ReadinessCheck.MatchCondition = c.pV1MatchConditionReadinessCheckToPV1MatchConditionReadinessCheck(source.MatchCondition)
+	v1ReadinessCheck.MatchExpression = source.MatchExpression
 	return v1ReadinessCheck
 }
 func (c *GeneratedRevisionSpecConverter) v1TransformToV1Transform(source Transform) Transform {
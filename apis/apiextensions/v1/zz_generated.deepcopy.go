@@ -23,9 +23,25 @@ package v1
 import (
 	commonv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClaimAdmissionRule) DeepCopyInto(out *ClaimAdmissionRule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClaimAdmissionRule.
+func (in *ClaimAdmissionRule) DeepCopy() *ClaimAdmissionRule {
+	if in == nil {
+		return nil
+	}
+	out := new(ClaimAdmissionRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Combine) DeepCopyInto(out *Combine) {
 	*out = *in
@@ -66,6 +82,21 @@ func (in *CombineVariable) DeepCopy() *CombineVariable {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComposedResourceAdoption) DeepCopyInto(out *ComposedResourceAdoption) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComposedResourceAdoption.
+func (in *ComposedResourceAdoption) DeepCopy() *ComposedResourceAdoption {
+	if in == nil {
+		return nil
+	}
+	out := new(ComposedResourceAdoption)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ComposedTemplate) DeepCopyInto(out *ComposedTemplate) {
 	*out = *in
@@ -96,6 +127,16 @@ func (in *ComposedTemplate) DeepCopyInto(out *ComposedTemplate) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ReadinessTimeout != nil {
+		in, out := &in.ReadinessTimeout, &out.ReadinessTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Adoption != nil {
+		in, out := &in.Adoption, &out.Adoption
+		*out = new(ComposedResourceAdoption)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComposedTemplate.
@@ -193,6 +234,11 @@ func (in *CompositeResourceDefinitionSpec) DeepCopyInto(out *CompositeResourceDe
 		*out = new(apiextensionsv1.CustomResourceDefinitionNames)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ClaimAdmissionRules != nil {
+		in, out := &in.ClaimAdmissionRules, &out.ClaimAdmissionRules
+		*out = make([]ClaimAdmissionRule, len(*in))
+		copy(*out, *in)
+	}
 	if in.ConnectionSecretKeys != nil {
 		in, out := &in.ConnectionSecretKeys, &out.ConnectionSecretKeys
 		*out = make([]string, len(*in))
@@ -511,6 +557,18 @@ func (in *CompositionRevisionSpec) DeepCopyInto(out *CompositionRevisionSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.DeletionOrder != nil {
+		in, out := &in.DeletionOrder, &out.DeletionOrder
+		*out = make([]DeletionDependency, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ConnectionDetailTemplates != nil {
+		in, out := &in.ConnectionDetailTemplates, &out.ConnectionDetailTemplates
+		*out = make([]ConnectionDetailTemplate, len(*in))
+		copy(*out, *in)
+	}
 	if in.WriteConnectionSecretsToNamespace != nil {
 		in, out := &in.WriteConnectionSecretsToNamespace, &out.WriteConnectionSecretsToNamespace
 		*out = new(string)
@@ -549,6 +607,36 @@ func (in *CompositionRevisionStatus) DeepCopy() *CompositionRevisionStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CompositionRollout) DeepCopyInto(out *CompositionRollout) {
+	*out = *in
+	if in.Percentage != nil {
+		in, out := &in.Percentage, &out.Percentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PauseOnError != nil {
+		in, out := &in.PauseOnError, &out.PauseOnError
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompositionRollout.
+func (in *CompositionRollout) DeepCopy() *CompositionRollout {
+	if in == nil {
+		return nil
+	}
+	out := new(CompositionRollout)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CompositionSpec) DeepCopyInto(out *CompositionSpec) {
 	*out = *in
@@ -584,6 +672,18 @@ func (in *CompositionSpec) DeepCopyInto(out *CompositionSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.DeletionOrder != nil {
+		in, out := &in.DeletionOrder, &out.DeletionOrder
+		*out = make([]DeletionDependency, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ConnectionDetailTemplates != nil {
+		in, out := &in.ConnectionDetailTemplates, &out.ConnectionDetailTemplates
+		*out = make([]ConnectionDetailTemplate, len(*in))
+		copy(*out, *in)
+	}
 	if in.WriteConnectionSecretsToNamespace != nil {
 		in, out := &in.WriteConnectionSecretsToNamespace, &out.WriteConnectionSecretsToNamespace
 		*out = new(string)
@@ -594,6 +694,11 @@ func (in *CompositionSpec) DeepCopyInto(out *CompositionSpec) {
 		*out = new(StoreConfigReference)
 		**out = **in
 	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(CompositionRollout)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompositionSpec.
@@ -646,6 +751,21 @@ func (in *ConnectionDetail) DeepCopy() *ConnectionDetail {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectionDetailTemplate) DeepCopyInto(out *ConnectionDetailTemplate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectionDetailTemplate.
+func (in *ConnectionDetailTemplate) DeepCopy() *ConnectionDetailTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectionDetailTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConvertTransform) DeepCopyInto(out *ConvertTransform) {
 	*out = *in
@@ -666,6 +786,26 @@ func (in *ConvertTransform) DeepCopy() *ConvertTransform {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeletionDependency) DeepCopyInto(out *DeletionDependency) {
+	*out = *in
+	if in.After != nil {
+		in, out := &in.After, &out.After
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeletionDependency.
+func (in *DeletionDependency) DeepCopy() *DeletionDependency {
+	if in == nil {
+		return nil
+	}
+	out := new(DeletionDependency)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EnvironmentConfiguration) DeepCopyInto(out *EnvironmentConfiguration) {
 	*out = *in
@@ -683,6 +823,11 @@ func (in *EnvironmentConfiguration) DeepCopyInto(out *EnvironmentConfiguration)
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.MergeStrategies != nil {
+		in, out := &in.MergeStrategies, &out.MergeStrategies
+		*out = make([]EnvironmentKeyMergeStrategy, len(*in))
+		copy(*out, *in)
+	}
 	if in.Patches != nil {
 		in, out := &in.Patches, &out.Patches
 		*out = make([]EnvironmentPatch, len(*in))
@@ -707,6 +852,21 @@ func (in *EnvironmentConfiguration) DeepCopy() *EnvironmentConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvironmentKeyMergeStrategy) DeepCopyInto(out *EnvironmentKeyMergeStrategy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvironmentKeyMergeStrategy.
+func (in *EnvironmentKeyMergeStrategy) DeepCopy() *EnvironmentKeyMergeStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvironmentKeyMergeStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EnvironmentPatch) DeepCopyInto(out *EnvironmentPatch) {
 	*out = *in
@@ -1100,6 +1260,11 @@ func (in *PipelineStep) DeepCopyInto(out *PipelineStep) {
 		*out = new(runtime.RawExtension)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Condition != nil {
+		in, out := &in.Condition, &out.Condition
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineStep.
@@ -1190,6 +1355,11 @@ func (in *StringTransform) DeepCopyInto(out *StringTransform) {
 		*out = new(StringTransformJoin)
 		**out = **in
 	}
+	if in.Template != nil {
+		in, out := &in.Template, &out.Template
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StringTransform.
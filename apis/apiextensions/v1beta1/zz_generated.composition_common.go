@@ -20,6 +20,7 @@ package v1beta1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -114,6 +115,75 @@ type ComposedTemplate struct {
 	// +optional
 	// +kubebuilder:default={{type:"MatchCondition",matchCondition:{type:"Ready",status:"True"}}}
 	ReadinessChecks []ReadinessCheck `json:"readinessChecks,omitempty"`
+
+	// ReadinessTimeout is how long the composed resource may remain unready
+	// before the composite resource is considered degraded. The composed
+	// resource is identified in the composite resource's Ready condition. It
+	// is measured from when the composed resource was created, or when it
+	// last stopped being ready. The composite resource is never considered
+	// degraded if no timeout is specified.
+	// +optional
+	ReadinessTimeout *metav1.Duration `json:"readinessTimeout,omitempty"`
+
+	// Adoption configures this template to adopt an existing composed
+	// resource, rather than creating a new one. This allows resources that
+	// were created outside of Composition to be brought under the control of
+	// a composite resource without being recreated. Only resources that
+	// aren't controlled by another resource, and that are labelled to opt in
+	// to adoption by the composite resource or its claim, may be adopted.
+	// Adoption is only supported by Compositions in Resources mode.
+	// +optional
+	Adoption *ComposedResourceAdoption `json:"adoption,omitempty"`
+}
+
+// An AdoptionMatchType determines how an existing composed resource is matched
+// for adoption.
+type AdoptionMatchType string
+
+// Adoption match types.
+const (
+	// AdoptionMatchTypeExternalName matches an existing composed resource of
+	// the same kind whose crossplane.io/external-name annotation is the same
+	// as the rendered composed resource's.
+	AdoptionMatchTypeExternalName AdoptionMatchType = "ExternalName"
+
+	// AdoptionMatchTypeName matches an existing composed resource of the same
+	// kind with the same name as the rendered composed resource.
+	AdoptionMatchTypeName AdoptionMatchType = "Name"
+)
+
+// ComposedResourceAdoption configures how a composed resource template adopts
+// an existing composed resource.
+//
+// A template is matched with an existing resource only when the composite
+// resource doesn't already reference a composed resource for it. The external
+// name or name to match is usually patched from the composite resource. An
+// existing resource may also be adopted by adding a reference to it to the
+// composite resource's spec.resourceRefs.
+//
+// An existing resource must opt in to adoption. Label it
+// crossplane.io/adoptable-by-composite with the name of the composite
+// resource that may adopt it, or label it
+// crossplane.io/adoptable-by-claim-name and
+// crossplane.io/adoptable-by-claim-namespace with the name and namespace of
+// the claim whose composite resource may adopt it.
+type ComposedResourceAdoption struct {
+	// MatchBy determines how an existing composed resource is matched. Use
+	// ExternalName to match a resource by its crossplane.io/external-name
+	// annotation, or Name to match a resource by its name.
+	// +optional
+	// +kubebuilder:validation:Enum=ExternalName;Name
+	// +kubebuilder:default=ExternalName
+	MatchBy AdoptionMatchType `json:"matchBy,omitempty"`
+}
+
+// GetMatchBy returns how an existing composed resource should be matched,
+// defaulting to ExternalName.
+func (a *ComposedResourceAdoption) GetMatchBy() AdoptionMatchType {
+	if a == nil || a.MatchBy == "" {
+		return AdoptionMatchTypeExternalName
+	}
+	return a.MatchBy
 }
 
 // GetName returns the name of the composed template or an empty string if it is nil.
@@ -129,19 +199,24 @@ type ReadinessCheckType string
 
 // The possible values for readiness check type.
 const (
-	ReadinessCheckTypeNonEmpty       ReadinessCheckType = "NonEmpty"
-	ReadinessCheckTypeMatchString    ReadinessCheckType = "MatchString"
-	ReadinessCheckTypeMatchInteger   ReadinessCheckType = "MatchInteger"
-	ReadinessCheckTypeMatchTrue      ReadinessCheckType = "MatchTrue"
-	ReadinessCheckTypeMatchFalse     ReadinessCheckType = "MatchFalse"
-	ReadinessCheckTypeMatchCondition ReadinessCheckType = "MatchCondition"
-	ReadinessCheckTypeNone           ReadinessCheckType = "None"
+	ReadinessCheckTypeNonEmpty        ReadinessCheckType = "NonEmpty"
+	ReadinessCheckTypeMatchString     ReadinessCheckType = "MatchString"
+	ReadinessCheckTypeMatchInteger    ReadinessCheckType = "MatchInteger"
+	ReadinessCheckTypeMatchTrue       ReadinessCheckType = "MatchTrue"
+	ReadinessCheckTypeMatchFalse      ReadinessCheckType = "MatchFalse"
+	ReadinessCheckTypeMatchCondition  ReadinessCheckType = "MatchCondition"
+	ReadinessCheckTypeMatchExpression ReadinessCheckType = "MatchExpression"
+	ReadinessCheckTypeNone            ReadinessCheckType = "None"
 )
 
+// ReadinessCheckVarObject is the variable a MatchExpression readiness check
+// uses to reference the composed resource.
+const ReadinessCheckVarObject = "object"
+
 // IsValid returns nil if the readiness check type is valid, or an error otherwise.
 func (t *ReadinessCheckType) IsValid() bool {
 	switch *t {
-	case ReadinessCheckTypeNonEmpty, ReadinessCheckTypeMatchString, ReadinessCheckTypeMatchInteger, ReadinessCheckTypeMatchTrue, ReadinessCheckTypeMatchFalse, ReadinessCheckTypeMatchCondition, ReadinessCheckTypeNone:
+	case ReadinessCheckTypeNonEmpty, ReadinessCheckTypeMatchString, ReadinessCheckTypeMatchInteger, ReadinessCheckTypeMatchTrue, ReadinessCheckTypeMatchFalse, ReadinessCheckTypeMatchCondition, ReadinessCheckTypeMatchExpression, ReadinessCheckTypeNone:
 		return true
 	}
 	return false
@@ -155,7 +230,7 @@ type ReadinessCheck struct {
 	// or 0?
 
 	// Type indicates the type of probe you'd like to use.
-	// +kubebuilder:validation:Enum="MatchString";"MatchInteger";"NonEmpty";"MatchCondition";"MatchTrue";"MatchFalse";"None"
+	// +kubebuilder:validation:Enum="MatchString";"MatchInteger";"NonEmpty";"MatchCondition";"MatchExpression";"MatchTrue";"MatchFalse";"None"
 	Type ReadinessCheckType `json:"type"`
 
 	// FieldPath shows the path of the field whose value will be used.
@@ -173,6 +248,13 @@ type ReadinessCheck struct {
 	// MatchCondition specifies the condition you'd like to match if you're using "MatchCondition" type.
 	// +optional
 	MatchCondition *MatchConditionReadinessCheck `json:"matchCondition,omitempty"`
+
+	// MatchExpression is the CEL expression you'd like to match if you're
+	// using "MatchExpression" type. The composed resource is available as
+	// `object`. The expression must evaluate to a boolean, for example
+	// `has(object.status.phase) && object.status.phase == 'Running'`.
+	// +optional
+	MatchExpression string `json:"matchExpression,omitempty"`
 }
 
 // MatchConditionReadinessCheck is used to indicate how to tell whether a resource is ready
@@ -224,6 +306,11 @@ func (r *ReadinessCheck) Validate() *field.Error { //nolint:gocyclo // This func
 			return errors.WrapFieldError(err, field.NewPath("matchCondition"))
 		}
 		return nil
+	case ReadinessCheckTypeMatchExpression:
+		if r.MatchExpression == "" {
+			return field.Required(field.NewPath("matchExpression"), "cannot be empty for type MatchExpression")
+		}
+		return nil
 	case ReadinessCheckTypeNonEmpty, ReadinessCheckTypeMatchFalse, ReadinessCheckTypeMatchTrue:
 		// No specific validation required.
 	}
@@ -300,6 +387,45 @@ type PipelineStep struct {
 	// +kubebuilder:pruning:PreserveUnknownFields
 	// +kubebuilder:validation:EmbeddedResource
 	Input *runtime.RawExtension `json:"input,omitempty"`
+
+	// Condition is an optional CEL expression that determines whether this
+	// step runs. The step is skipped if the condition evaluates to false. The
+	// observed composite resource may be referenced as 'xr', and the
+	// Composition environment, if any, as 'environment'. For example
+	// has(xr.spec.backup) && xr.spec.backup.enabled. The composite resource
+	// can't be reconciled if the condition can't be evaluated, for example
+	// because it references a field that doesn't exist.
+	// +optional
+	Condition *string `json:"condition,omitempty"`
+}
+
+// A DeletionDependency declares that a composed resource must not be deleted
+// until other composed resources have been deleted.
+type DeletionDependency struct {
+	// Resource is the name of a composed resource.
+	Resource string `json:"resource"`
+
+	// After is a list of names of composed resources that must be deleted
+	// before the resource is deleted. For example a DNS zone's records must
+	// be deleted before the zone.
+	// +kubebuilder:validation:MinItems=1
+	After []string `json:"after"`
+}
+
+// A ConnectionDetailTemplate renders a composite resource connection detail
+// from the connection details of composed resources.
+type ConnectionDetailTemplate struct {
+	// Name of the composite resource connection detail.
+	Name string `json:"name"`
+
+	// Template is a Go template that renders the value of the connection
+	// detail. The connection details of composed resources are available by
+	// composed resource name, e.g. `{{ .db.host }}:{{ .db.port }}`, or
+	// `{{ index . "my-db" "host" }}` for names that aren't valid identifiers.
+	// A connection detail that references connection details that don't
+	// exist yet using the former syntax isn't published until they do. See
+	// https://pkg.go.dev/text/template for details.
+	Template string `json:"template"`
 }
 
 // A FunctionReference references a Composition Function that may be used in a
@@ -21,6 +21,7 @@ package v1beta1
 import (
 	"encoding/json"
 	"regexp"
+	"text/template"
 
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -342,6 +343,7 @@ const (
 	StringTransformTypeTrimSuffix StringTransformType = "TrimSuffix"
 	StringTransformTypeRegexp     StringTransformType = "Regexp"
 	StringTransformTypeJoin       StringTransformType = "Join"
+	StringTransformTypeTemplate   StringTransformType = "Template"
 )
 
 // StringConversionType converts a string.
@@ -365,7 +367,7 @@ type StringTransform struct {
 
 	// Type of the string transform to be run.
 	// +optional
-	// +kubebuilder:validation:Enum=Format;Convert;TrimPrefix;TrimSuffix;Regexp;Join
+	// +kubebuilder:validation:Enum=Format;Convert;TrimPrefix;TrimSuffix;Regexp;Join;Template
 	// +kubebuilder:default=Format
 	Type StringTransformType `json:"type,omitempty"`
 
@@ -395,6 +397,14 @@ type StringTransform struct {
 	// Join defines parameters to join a slice of values to a string.
 	// +optional
 	Join *StringTransformJoin `json:"join,omitempty"`
+
+	// Template renders the input using a Go template. The input is available
+	// as the template's data, so when the input is an object the template can
+	// access several of its fields, e.g. `{{ .host }}:{{ .port }}`. The
+	// transform fails if the template references a field the input doesn't
+	// have. See https://pkg.go.dev/text/template for details.
+	// +optional
+	Template *string `json:"template,omitempty"`
 }
 
 // Validate checks this StringTransform is valid.
@@ -428,6 +438,13 @@ func (s *StringTransform) Validate() *field.Error {
 		if s.Join == nil {
 			return field.Required(field.NewPath("join"), "join transform requires a join")
 		}
+	case StringTransformTypeTemplate:
+		if s.Template == nil {
+			return field.Required(field.NewPath("template"), "template transform requires a template")
+		}
+		if _, err := template.New("").Parse(*s.Template); err != nil {
+			return field.Invalid(field.NewPath("template"), *s.Template, "invalid template")
+		}
 	default:
 		return field.Invalid(field.NewPath("type"), s.Type, "unknown string transform type")
 	}
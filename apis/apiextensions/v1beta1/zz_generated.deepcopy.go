@@ -22,7 +22,8 @@ package v1beta1
 
 import (
 	commonv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
-	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -66,6 +67,21 @@ func (in *CombineVariable) DeepCopy() *CombineVariable {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComposedResourceAdoption) DeepCopyInto(out *ComposedResourceAdoption) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComposedResourceAdoption.
+func (in *ComposedResourceAdoption) DeepCopy() *ComposedResourceAdoption {
+	if in == nil {
+		return nil
+	}
+	out := new(ComposedResourceAdoption)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ComposedTemplate) DeepCopyInto(out *ComposedTemplate) {
 	*out = *in
@@ -96,6 +112,16 @@ func (in *ComposedTemplate) DeepCopyInto(out *ComposedTemplate) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ReadinessTimeout != nil {
+		in, out := &in.ReadinessTimeout, &out.ReadinessTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Adoption != nil {
+		in, out := &in.Adoption, &out.Adoption
+		*out = new(ComposedResourceAdoption)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComposedTemplate.
@@ -202,6 +228,18 @@ func (in *CompositionRevisionSpec) DeepCopyInto(out *CompositionRevisionSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.DeletionOrder != nil {
+		in, out := &in.DeletionOrder, &out.DeletionOrder
+		*out = make([]DeletionDependency, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ConnectionDetailTemplates != nil {
+		in, out := &in.ConnectionDetailTemplates, &out.ConnectionDetailTemplates
+		*out = make([]ConnectionDetailTemplate, len(*in))
+		copy(*out, *in)
+	}
 	if in.WriteConnectionSecretsToNamespace != nil {
 		in, out := &in.WriteConnectionSecretsToNamespace, &out.WriteConnectionSecretsToNamespace
 		*out = new(string)
@@ -280,6 +318,21 @@ func (in *ConnectionDetail) DeepCopy() *ConnectionDetail {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectionDetailTemplate) DeepCopyInto(out *ConnectionDetailTemplate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectionDetailTemplate.
+func (in *ConnectionDetailTemplate) DeepCopy() *ConnectionDetailTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectionDetailTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConvertTransform) DeepCopyInto(out *ConvertTransform) {
 	*out = *in
@@ -300,12 +353,32 @@ func (in *ConvertTransform) DeepCopy() *ConvertTransform {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeletionDependency) DeepCopyInto(out *DeletionDependency) {
+	*out = *in
+	if in.After != nil {
+		in, out := &in.After, &out.After
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeletionDependency.
+func (in *DeletionDependency) DeepCopy() *DeletionDependency {
+	if in == nil {
+		return nil
+	}
+	out := new(DeletionDependency)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EnvironmentConfiguration) DeepCopyInto(out *EnvironmentConfiguration) {
 	*out = *in
 	if in.DefaultData != nil {
 		in, out := &in.DefaultData, &out.DefaultData
-		*out = make(map[string]v1.JSON, len(*in))
+		*out = make(map[string]apiextensionsv1.JSON, len(*in))
 		for key, val := range *in {
 			(*out)[key] = *val.DeepCopy()
 		}
@@ -317,6 +390,11 @@ func (in *EnvironmentConfiguration) DeepCopyInto(out *EnvironmentConfiguration)
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.MergeStrategies != nil {
+		in, out := &in.MergeStrategies, &out.MergeStrategies
+		*out = make([]EnvironmentKeyMergeStrategy, len(*in))
+		copy(*out, *in)
+	}
 	if in.Patches != nil {
 		in, out := &in.Patches, &out.Patches
 		*out = make([]EnvironmentPatch, len(*in))
@@ -341,6 +419,21 @@ func (in *EnvironmentConfiguration) DeepCopy() *EnvironmentConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvironmentKeyMergeStrategy) DeepCopyInto(out *EnvironmentKeyMergeStrategy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvironmentKeyMergeStrategy.
+func (in *EnvironmentKeyMergeStrategy) DeepCopy() *EnvironmentKeyMergeStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvironmentKeyMergeStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EnvironmentPatch) DeepCopyInto(out *EnvironmentPatch) {
 	*out = *in
@@ -505,7 +598,7 @@ func (in *MapTransform) DeepCopyInto(out *MapTransform) {
 	*out = *in
 	if in.Pairs != nil {
 		in, out := &in.Pairs, &out.Pairs
-		*out = make(map[string]v1.JSON, len(*in))
+		*out = make(map[string]apiextensionsv1.JSON, len(*in))
 		for key, val := range *in {
 			(*out)[key] = *val.DeepCopy()
 		}
@@ -719,6 +812,11 @@ func (in *PipelineStep) DeepCopyInto(out *PipelineStep) {
 		*out = new(runtime.RawExtension)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Condition != nil {
+		in, out := &in.Condition, &out.Condition
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineStep.
@@ -809,6 +907,11 @@ func (in *StringTransform) DeepCopyInto(out *StringTransform) {
 		*out = new(StringTransformJoin)
 		**out = **in
 	}
+	if in.Template != nil {
+		in, out := &in.Template, &out.Template
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StringTransform.
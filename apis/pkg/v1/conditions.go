@@ -30,18 +30,61 @@ const (
 
 	// A TypeHealthy indicates whether a package is healthy.
 	TypeHealthy xpv1.ConditionType = "Healthy"
+
+	// A TypeRevisionApproved indicates whether the current revision of a
+	// package that requires approval has been approved.
+	TypeRevisionApproved xpv1.ConditionType = "RevisionApproved"
+
+	// A TypeRuntimeHealthy indicates whether the runtime of a package with a
+	// runtime, e.g. a provider's Deployment, is healthy.
+	TypeRuntimeHealthy xpv1.ConditionType = "RuntimeHealthy"
+
+	// A TypeUpgradeSafe indicates whether the CRDs of a package revision can
+	// safely replace the CRDs that are already installed.
+	TypeUpgradeSafe xpv1.ConditionType = "UpgradeSafe"
 )
 
 // Reasons a package is or is not installed.
 const (
 	ReasonUnpacking     xpv1.ConditionReason = "UnpackingPackage"
 	ReasonInactive      xpv1.ConditionReason = "InactivePackageRevision"
+	ReasonSoaking       xpv1.ConditionReason = "SoakingPackageRevision"
 	ReasonActive        xpv1.ConditionReason = "ActivePackageRevision"
 	ReasonUnhealthy     xpv1.ConditionReason = "UnhealthyPackageRevision"
 	ReasonHealthy       xpv1.ConditionReason = "HealthyPackageRevision"
 	ReasonUnknownHealth xpv1.ConditionReason = "UnknownPackageRevisionHealth"
 )
 
+// Reasons a package is not healthy before it has a revision.
+const (
+	ReasonVerificationFailed xpv1.ConditionReason = "SignatureVerificationFailed"
+)
+
+// Reasons the package manager couldn't fetch a package from its registry.
+const (
+	ReasonRegistryRateLimited xpv1.ConditionReason = "RegistryRateLimited"
+)
+
+// Reasons a package revision is or is not approved.
+const (
+	ReasonPendingApproval xpv1.ConditionReason = "PendingApproval"
+	ReasonApproved        xpv1.ConditionReason = "ApprovedPackageRevision"
+)
+
+// Reasons a package's runtime is or is not healthy.
+const (
+	ReasonRuntimeHealthy       xpv1.ConditionReason = "HealthyPackageRuntime"
+	ReasonRuntimeUnhealthy     xpv1.ConditionReason = "UnhealthyPackageRuntime"
+	ReasonRuntimeUnknownHealth xpv1.ConditionReason = "UnknownPackageRuntimeHealth"
+)
+
+// Reasons a package revision's CRDs are or are not safe to upgrade.
+const (
+	ReasonUpgradeSafe       xpv1.ConditionReason = "SafeCRDUpgrade"
+	ReasonUpgradeUnsafe     xpv1.ConditionReason = "UnsafeCRDUpgrade"
+	ReasonUpgradeOverridden xpv1.ConditionReason = "UnsafeCRDUpgradeAllowed"
+)
+
 // Unpacking indicates that the package manager is waiting for a package
 // revision to be unpacked.
 func Unpacking() xpv1.Condition {
@@ -64,6 +107,17 @@ func Inactive() xpv1.Condition {
 	}
 }
 
+// Soaking indicates that the package manager is waiting for a new package
+// revision to be healthy for the package's soak period before it activates it.
+func Soaking() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeInstalled,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonSoaking,
+	}
+}
+
 // Active indicates that the package manager has installed and activated
 // a package revision.
 func Active() xpv1.Condition {
@@ -95,6 +149,29 @@ func Healthy() xpv1.Condition {
 	}
 }
 
+// VerificationFailed indicates that the package manager couldn't verify the
+// signature of the package's image, so it didn't create a revision for it.
+func VerificationFailed() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeHealthy,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonVerificationFailed,
+	}
+}
+
+// RegistryRateLimited indicates that the package manager couldn't fetch a
+// package because its registry is rate limiting requests. The package manager
+// backs off from the registry before trying again.
+func RegistryRateLimited() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeHealthy,
+		Status:             corev1.ConditionUnknown,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonRegistryRateLimited,
+	}
+}
+
 // UnknownHealth indicates that the health of the current revision is unknown.
 func UnknownHealth() xpv1.Condition {
 	return xpv1.Condition{
@@ -104,3 +181,93 @@ func UnknownHealth() xpv1.Condition {
 		Reason:             ReasonUnknownHealth,
 	}
 }
+
+// PendingApproval indicates that the current revision of a package that
+// requires approval is waiting to be approved before it's activated.
+func PendingApproval() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeRevisionApproved,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonPendingApproval,
+	}
+}
+
+// Approved indicates that the current revision of a package that requires
+// approval has been approved.
+func Approved() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeRevisionApproved,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonApproved,
+	}
+}
+
+// RuntimeHealthy indicates that all of the replicas of the current revision's
+// runtime are available.
+func RuntimeHealthy() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeRuntimeHealthy,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonRuntimeHealthy,
+	}
+}
+
+// RuntimeUnhealthy indicates that the current revision's runtime is
+// unavailable or failing, for example because its pods are crash looping.
+func RuntimeUnhealthy() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeRuntimeHealthy,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonRuntimeUnhealthy,
+	}
+}
+
+// RuntimeUnknownHealth indicates that the health of the current revision's
+// runtime is unknown.
+func RuntimeUnknownHealth() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeRuntimeHealthy,
+		Status:             corev1.ConditionUnknown,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonRuntimeUnknownHealth,
+	}
+}
+
+// UpgradeSafe indicates that the CRDs of a package revision can safely replace
+// the CRDs that are already installed.
+func UpgradeSafe() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeUpgradeSafe,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonUpgradeSafe,
+	}
+}
+
+// UpgradeUnsafe indicates that the CRDs of a package revision can't safely
+// replace the CRDs that are already installed, for example because they stop
+// serving a version that existing objects are stored at.
+func UpgradeUnsafe() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeUpgradeSafe,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonUpgradeUnsafe,
+	}
+}
+
+// UpgradeOverridden indicates that the CRDs of a package revision can't safely
+// replace the CRDs that are already installed, but that the package allows an
+// unsafe upgrade.
+func UpgradeOverridden() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeUpgradeSafe,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonUpgradeOverridden,
+	}
+}
@@ -17,7 +17,10 @@ limitations under the License.
 package v1
 
 import (
+	"time"
+
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
@@ -41,8 +44,105 @@ const (
 	// revisions, and can be used to select all provider revisions that belong
 	// to a particular family. It is not added to providers, only revisions.
 	LabelProviderFamily = "pkg.crossplane.io/provider-family"
+
+	// AnnotationDisableWebhooks can be set to "true" on a package to disable
+	// the admission webhooks it ships. Its webhooks are configured to ignore
+	// failures, so that a broken webhook can't block API requests, and they
+	// no longer affect the package's health. It is propagated from packages
+	// to their revisions.
+	AnnotationDisableWebhooks = "pkg.crossplane.io/disable-webhooks"
+
+	// AnnotationRequireApproval can be set to "true" on a package with an
+	// automatic revision activation policy to gate the activation of each new
+	// revision on an external approval, for example from a progressive
+	// delivery controller. Until a revision is approved the package manager
+	// leaves the previously active revision active, and reports that the
+	// package is pending approval.
+	AnnotationRequireApproval = "pkg.crossplane.io/require-approval"
+
+	// AnnotationApprovedRevision approves the activation of the named package
+	// revision when set on a package that requires approval.
+	AnnotationApprovedRevision = "pkg.crossplane.io/approved-revision"
+
+	// AnnotationSoakPeriod can be set to a duration, e.g. "2h", on a package
+	// with an automatic revision activation policy to delay the activation of
+	// each new revision until it has been healthy for that long while
+	// inactive. Until then the package manager leaves the previously active
+	// revision active. This gives canary clusters time to surface issues
+	// before the rest of a fleet activates the revision.
+	AnnotationSoakPeriod = "pkg.crossplane.io/soak-period"
+
+	// AnnotationPinned can be set to "true" on a package revision to retain
+	// it regardless of the package's revision history limit or maximum
+	// revision age.
+	AnnotationPinned = "pkg.crossplane.io/pinned"
+
+	// AnnotationRevisionMaxAge can be set to a duration, e.g. "720h", on a
+	// package to garbage collect its inactive revisions once they're older
+	// than that, even when the package's revision history limit hasn't been
+	// reached.
+	AnnotationRevisionMaxAge = "pkg.crossplane.io/revision-max-age"
+
+	// AnnotationAllowUnsafeUpgrade can be set to "true" on a package to allow
+	// a revision to be activated even though its CRDs can't safely replace
+	// the CRDs that are already installed, for example because they remove
+	// a field that existing objects set. It is propagated from packages to
+	// their revisions.
+	AnnotationAllowUnsafeUpgrade = "pkg.crossplane.io/allow-unsafe-upgrade"
 )
 
+// WebhooksDisabled returns true if the supplied package or package revision's
+// webhooks are disabled.
+func WebhooksDisabled(o metav1.Object) bool {
+	return o.GetAnnotations()[AnnotationDisableWebhooks] == "true"
+}
+
+// ApprovalRequired returns true if the supplied package requires each of its
+// revisions to be approved before they're activated.
+func ApprovalRequired(o metav1.Object) bool {
+	return o.GetAnnotations()[AnnotationRequireApproval] == "true"
+}
+
+// RevisionApproved returns true if the supplied package approves activation
+// of the named revision.
+func RevisionApproved(o metav1.Object, revision string) bool {
+	return o.GetAnnotations()[AnnotationApprovedRevision] == revision
+}
+
+// SoakPeriod returns how long a new revision of the supplied package must be
+// healthy before it's activated. It returns zero if the package doesn't have a
+// soak period.
+func SoakPeriod(o metav1.Object) (time.Duration, error) {
+	v, ok := o.GetAnnotations()[AnnotationSoakPeriod]
+	if !ok {
+		return 0, nil
+	}
+	return time.ParseDuration(v)
+}
+
+// Pinned returns true if the supplied package revision must never be garbage
+// collected.
+func Pinned(o metav1.Object) bool {
+	return o.GetAnnotations()[AnnotationPinned] == "true"
+}
+
+// RevisionMaxAge returns how old an inactive revision of the supplied package
+// may be before it's garbage collected. It returns zero if the package's
+// revisions don't have a maximum age.
+func RevisionMaxAge(o metav1.Object) (time.Duration, error) {
+	v, ok := o.GetAnnotations()[AnnotationRevisionMaxAge]
+	if !ok {
+		return 0, nil
+	}
+	return time.ParseDuration(v)
+}
+
+// UnsafeUpgradeAllowed returns true if the supplied package or package
+// revision allows its CRDs to be upgraded even when it isn't safe to do so.
+func UnsafeUpgradeAllowed(o metav1.Object) bool {
+	return o.GetAnnotations()[AnnotationAllowUnsafeUpgrade] == "true"
+}
+
 var (
 	// AutomaticActivation indicates that package should automatically activate
 	// package revisions.
@@ -109,11 +209,22 @@ type Package interface {
 	GetCurrentIdentifier() string
 	SetCurrentIdentifier(r string)
 
+	GetLastGarbageCollection() *RevisionGarbageCollection
+	SetLastGarbageCollection(gc *RevisionGarbageCollection)
+
+	GetUpdatePolicy() *UpdatePolicy
+
+	GetUpdateCheck() *PackageUpdateCheck
+	SetUpdateCheck(c *PackageUpdateCheck)
+
 	GetSkipDependencyResolution() *bool
 	SetSkipDependencyResolution(*bool)
 
 	GetCommonLabels() map[string]string
 	SetCommonLabels(l map[string]string)
+
+	GetExternallyManagedObjects() []ExternallyManagedObject
+	SetExternallyManagedObjects(o []ExternallyManagedObject)
 }
 
 // GetCondition of this Provider.
@@ -241,6 +352,31 @@ func (p *Provider) SetCurrentIdentifier(s string) {
 	p.Status.CurrentIdentifier = s
 }
 
+// GetLastGarbageCollection of this Provider.
+func (p *Provider) GetLastGarbageCollection() *RevisionGarbageCollection {
+	return p.Status.LastGarbageCollection
+}
+
+// SetLastGarbageCollection of this Provider.
+func (p *Provider) SetLastGarbageCollection(gc *RevisionGarbageCollection) {
+	p.Status.LastGarbageCollection = gc
+}
+
+// GetUpdatePolicy of this Provider.
+func (p *Provider) GetUpdatePolicy() *UpdatePolicy {
+	return p.Spec.UpdatePolicy
+}
+
+// GetUpdateCheck of this Provider.
+func (p *Provider) GetUpdateCheck() *PackageUpdateCheck {
+	return p.Status.UpdateCheck
+}
+
+// SetUpdateCheck of this Provider.
+func (p *Provider) SetUpdateCheck(c *PackageUpdateCheck) {
+	p.Status.UpdateCheck = c
+}
+
 // GetCommonLabels of this Provider.
 func (p *Provider) GetCommonLabels() map[string]string {
 	return p.Spec.CommonLabels
@@ -251,6 +387,16 @@ func (p *Provider) SetCommonLabels(l map[string]string) {
 	p.Spec.CommonLabels = l
 }
 
+// GetExternallyManagedObjects of this Provider.
+func (p *Provider) GetExternallyManagedObjects() []ExternallyManagedObject {
+	return p.Spec.ExternallyManagedObjects
+}
+
+// SetExternallyManagedObjects of this Provider.
+func (p *Provider) SetExternallyManagedObjects(o []ExternallyManagedObject) {
+	p.Spec.ExternallyManagedObjects = o
+}
+
 // GetTLSServerSecretName of this Provider.
 func (p *Provider) GetTLSServerSecretName() *string {
 	return GetSecretNameWithSuffix(p.GetName(), TLSServerSecretNameSuffix)
@@ -366,6 +512,31 @@ func (p *Configuration) SetCurrentIdentifier(s string) {
 	p.Status.CurrentIdentifier = s
 }
 
+// GetLastGarbageCollection of this Configuration.
+func (p *Configuration) GetLastGarbageCollection() *RevisionGarbageCollection {
+	return p.Status.LastGarbageCollection
+}
+
+// SetLastGarbageCollection of this Configuration.
+func (p *Configuration) SetLastGarbageCollection(gc *RevisionGarbageCollection) {
+	p.Status.LastGarbageCollection = gc
+}
+
+// GetUpdatePolicy of this Configuration.
+func (p *Configuration) GetUpdatePolicy() *UpdatePolicy {
+	return p.Spec.UpdatePolicy
+}
+
+// GetUpdateCheck of this Configuration.
+func (p *Configuration) GetUpdateCheck() *PackageUpdateCheck {
+	return p.Status.UpdateCheck
+}
+
+// SetUpdateCheck of this Configuration.
+func (p *Configuration) SetUpdateCheck(c *PackageUpdateCheck) {
+	p.Status.UpdateCheck = c
+}
+
 // GetCommonLabels of this Configuration.
 func (p *Configuration) GetCommonLabels() map[string]string {
 	return p.Spec.CommonLabels
@@ -376,6 +547,16 @@ func (p *Configuration) SetCommonLabels(l map[string]string) {
 	p.Spec.CommonLabels = l
 }
 
+// GetExternallyManagedObjects of this Configuration.
+func (p *Configuration) GetExternallyManagedObjects() []ExternallyManagedObject {
+	return p.Spec.ExternallyManagedObjects
+}
+
+// SetExternallyManagedObjects of this Configuration.
+func (p *Configuration) SetExternallyManagedObjects(o []ExternallyManagedObject) {
+	p.Spec.ExternallyManagedObjects = o
+}
+
 // PackageRevisionWithRuntime is the interface satisfied by revision of packages
 // with runtime types.
 // +k8s:deepcopy-gen=false
@@ -430,8 +611,17 @@ type PackageRevision interface {
 	GetDependencyStatus() (found, installed, invalid int64)
 	SetDependencyStatus(found, installed, invalid int64)
 
+	GetDependencyConflicts() []DependencyConflict
+	SetDependencyConflicts(c []DependencyConflict)
+
+	GetChanges() *RevisionChanges
+	SetChanges(c *RevisionChanges)
+
 	GetCommonLabels() map[string]string
 	SetCommonLabels(l map[string]string)
+
+	GetExternallyManagedObjects() []ExternallyManagedObject
+	SetExternallyManagedObjects(o []ExternallyManagedObject)
 }
 
 // GetCondition of this ProviderRevision.
@@ -521,6 +711,26 @@ func (p *ProviderRevision) SetDependencyStatus(found, installed, invalid int64)
 	p.Status.InvalidDependencies = invalid
 }
 
+// GetDependencyConflicts of this ProviderRevision.
+func (p *ProviderRevision) GetDependencyConflicts() []DependencyConflict {
+	return p.Status.DependencyConflicts
+}
+
+// SetDependencyConflicts of this ProviderRevision.
+func (p *ProviderRevision) SetDependencyConflicts(c []DependencyConflict) {
+	p.Status.DependencyConflicts = c
+}
+
+// GetChanges of this ProviderRevision.
+func (p *ProviderRevision) GetChanges() *RevisionChanges {
+	return p.Status.Changes
+}
+
+// SetChanges of this ProviderRevision.
+func (p *ProviderRevision) SetChanges(c *RevisionChanges) {
+	p.Status.Changes = c
+}
+
 // GetIgnoreCrossplaneConstraints of this ProviderRevision.
 func (p *ProviderRevision) GetIgnoreCrossplaneConstraints() *bool {
 	return p.Spec.IgnoreCrossplaneConstraints
@@ -591,6 +801,16 @@ func (p *ProviderRevision) SetCommonLabels(l map[string]string) {
 	p.Spec.CommonLabels = l
 }
 
+// GetExternallyManagedObjects of this ProviderRevision.
+func (p *ProviderRevision) GetExternallyManagedObjects() []ExternallyManagedObject {
+	return p.Spec.ExternallyManagedObjects
+}
+
+// SetExternallyManagedObjects of this ProviderRevision.
+func (p *ProviderRevision) SetExternallyManagedObjects(o []ExternallyManagedObject) {
+	p.Spec.ExternallyManagedObjects = o
+}
+
 // GetCondition of this ConfigurationRevision.
 func (p *ConfigurationRevision) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
 	return p.Status.GetCondition(ct)
@@ -678,6 +898,26 @@ func (p *ConfigurationRevision) SetDependencyStatus(found, installed, invalid in
 	p.Status.InvalidDependencies = invalid
 }
 
+// GetDependencyConflicts of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetDependencyConflicts() []DependencyConflict {
+	return p.Status.DependencyConflicts
+}
+
+// SetDependencyConflicts of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetDependencyConflicts(c []DependencyConflict) {
+	p.Status.DependencyConflicts = c
+}
+
+// GetChanges of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetChanges() *RevisionChanges {
+	return p.Status.Changes
+}
+
+// SetChanges of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetChanges(c *RevisionChanges) {
+	p.Status.Changes = c
+}
+
 // GetIgnoreCrossplaneConstraints of this ConfigurationRevision.
 func (p *ConfigurationRevision) GetIgnoreCrossplaneConstraints() *bool {
 	return p.Spec.IgnoreCrossplaneConstraints
@@ -708,6 +948,16 @@ func (p *ConfigurationRevision) SetCommonLabels(l map[string]string) {
 	p.Spec.CommonLabels = l
 }
 
+// GetExternallyManagedObjects of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetExternallyManagedObjects() []ExternallyManagedObject {
+	return p.Spec.ExternallyManagedObjects
+}
+
+// SetExternallyManagedObjects of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetExternallyManagedObjects(o []ExternallyManagedObject) {
+	p.Spec.ExternallyManagedObjects = o
+}
+
 // PackageRevisionList is the interface satisfied by package revision list
 // types.
 // +k8s:deepcopy-gen=false
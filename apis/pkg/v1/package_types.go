@@ -16,7 +16,10 @@ limitations under the License.
 
 package v1
 
-import corev1 "k8s.io/api/core/v1"
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
 
 // RevisionActivationPolicy indicates how a package should activate its
 // revisions.
@@ -73,6 +76,63 @@ type PackageSpec struct {
 	// More info: http://kubernetes.io/docs/user-guide/labels
 	// +optional
 	CommonLabels map[string]string `json:"commonLabels,omitempty"`
+
+	// UpdatePolicy configures the package manager to periodically update the
+	// package to the newest version that satisfies the policy.
+	// +optional
+	UpdatePolicy *UpdatePolicy `json:"updatePolicy,omitempty"`
+
+	// ExternallyManagedObjects selects objects in the package that are
+	// managed outside of the package manager, for example by a GitOps tool.
+	// The package manager checks that they exist and serve the versions the
+	// package needs, but never creates, updates, or deletes them.
+	// +optional
+	ExternallyManagedObjects []ExternallyManagedObject `json:"externallyManagedObjects,omitempty"`
+}
+
+// An ExternallyManagedObject selects objects in a package that are managed
+// outside of the package manager.
+type ExternallyManagedObject struct {
+	// Kind of the objects.
+	// +kubebuilder:validation:Enum=CustomResourceDefinition;CompositeResourceDefinition
+	Kind string `json:"kind"`
+
+	// Name of the object. All objects of the kind are selected if omitted.
+	// +optional
+	Name *string `json:"name,omitempty"`
+}
+
+// ExternallyManaged returns true if any of the supplied selectors selects the
+// object of the supplied kind and name.
+func ExternallyManaged(sel []ExternallyManagedObject, kind, name string) bool {
+	for _, s := range sel {
+		if s.Kind == kind && (s.Name == nil || *s.Name == name) {
+			return true
+		}
+	}
+	return false
+}
+
+// An UpdatePolicy configures how the package manager automatically updates a
+// package. Exactly one of channel and version must be set.
+// +kubebuilder:validation:XValidation:rule="has(self.channel) != has(self.version)",message="exactly one of channel or version must be set"
+type UpdatePolicy struct {
+	// Channel is a tag of the package's repository to track, e.g. stable. The
+	// package is updated to the digest the tag refers to whenever it changes.
+	// +optional
+	Channel *string `json:"channel,omitempty"`
+
+	// Version is a semantic version constraint, e.g. ">=1.2.0, <2.0.0". The
+	// package is updated to the greatest tag of its repository that
+	// satisfies it. The package is never downgraded.
+	// +optional
+	Version *string `json:"version,omitempty"`
+
+	// Interval is how often to check for a newer version.
+	// Default is 1h.
+	// +optional
+	// +kubebuilder:default="1h"
+	Interval *metav1.Duration `json:"interval,omitempty"`
 }
 
 // PackageStatus represents the observed state of a Package.
@@ -89,4 +149,42 @@ type PackageStatus struct {
 	// will cause the package manager to check that the current revision is
 	// correct for the given package source.
 	CurrentIdentifier string `json:"currentIdentifier,omitempty"`
+
+	// LastGarbageCollection reports the package revisions that the package
+	// manager most recently garbage collected.
+	// +optional
+	LastGarbageCollection *RevisionGarbageCollection `json:"lastGarbageCollection,omitempty"`
+
+	// UpdateCheck reports the result of the package manager's most recent
+	// check for a newer version that satisfies the package's update policy.
+	// +optional
+	UpdateCheck *PackageUpdateCheck `json:"updateCheck,omitempty"`
+}
+
+// PackageUpdateCheck reports the result of checking for a newer version of a
+// package that satisfies its update policy.
+type PackageUpdateCheck struct {
+	// LastCheckTime is when the package manager last checked for a newer
+	// version.
+	LastCheckTime metav1.Time `json:"lastCheckTime"`
+
+	// LatestVersion is the newest version that satisfies the update policy.
+	// It's a digest for a package that tracks a channel.
+	// +optional
+	LatestVersion string `json:"latestVersion,omitempty"`
+
+	// AvailableVersions are the versions that satisfy the update policy,
+	// newest first. At most ten versions are reported.
+	// +optional
+	AvailableVersions []string `json:"availableVersions,omitempty"`
+}
+
+// RevisionGarbageCollection reports package revisions that were garbage
+// collected.
+type RevisionGarbageCollection struct {
+	// Time at which the revisions were garbage collected.
+	Time metav1.Time `json:"time"`
+
+	// Revisions are the names of the revisions that were garbage collected.
+	Revisions []string `json:"revisions,omitempty"`
 }
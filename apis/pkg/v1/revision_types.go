@@ -17,6 +17,9 @@ limitations under the License.
 package v1
 
 import (
+	"fmt"
+	"strings"
+
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 
@@ -81,6 +84,13 @@ type PackageRevisionSpec struct {
 	// More info: http://kubernetes.io/docs/user-guide/labels
 	// +optional
 	CommonLabels map[string]string `json:"commonLabels,omitempty"`
+
+	// ExternallyManagedObjects selects objects in the package that are
+	// managed outside of the package manager, for example by a GitOps tool.
+	// The package manager checks that they exist and serve the versions the
+	// package needs, but never creates, updates, or deletes them.
+	// +optional
+	ExternallyManagedObjects []ExternallyManagedObject `json:"externallyManagedObjects,omitempty"`
 }
 
 // PackageRevisionStatus represents the observed state of a PackageRevision.
@@ -95,12 +105,116 @@ type PackageRevisionStatus struct {
 	InstalledDependencies int64 `json:"installedDependencies,omitempty"`
 	InvalidDependencies   int64 `json:"invalidDependencies,omitempty"`
 
+	// DependencyConflicts are the dependencies of this package whose
+	// installed version doesn't satisfy the constraints of every package
+	// that depends on them.
+	DependencyConflicts []DependencyConflict `json:"dependencyConflicts,omitempty"`
+
+	// Changes summarizes how this revision changed the objects installed by
+	// its package, compared to the revision it replaced.
+	// +optional
+	Changes *RevisionChanges `json:"changes,omitempty"`
+
 	// PermissionRequests made by this package. The package declares that its
 	// controller needs these permissions to run. The RBAC manager is
 	// responsible for granting them.
 	PermissionRequests []rbacv1.PolicyRule `json:"permissionRequests,omitempty"`
 }
 
+// RevisionChanges summarizes how a package revision changed the objects
+// installed by its package, compared to the revision it replaced.
+type RevisionChanges struct {
+	// PreviousRevision is the name of the revision this revision replaced.
+	PreviousRevision string `json:"previousRevision"`
+
+	// Added are the objects this revision installs that the previous
+	// revision didn't.
+	// +optional
+	Added []xpv1.TypedReference `json:"added,omitempty"`
+
+	// Changed are the objects both revisions install that this revision
+	// changed.
+	// +optional
+	Changed []xpv1.TypedReference `json:"changed,omitempty"`
+
+	// Removed are the objects the previous revision installed that this
+	// revision doesn't. They're not deleted.
+	// +optional
+	Removed []xpv1.TypedReference `json:"removed,omitempty"`
+}
+
+// String summarizes the changes, e.g. "Replaced revision example-abc: 2
+// added (1 CustomResourceDefinition, 1 Composition), 1 changed (1
+// CustomResourceDefinition), 0 removed".
+func (c RevisionChanges) String() string {
+	return fmt.Sprintf("Replaced revision %s: %s added, %s changed, %s removed", c.PreviousRevision, countKinds(c.Added), countKinds(c.Changed), countKinds(c.Removed))
+}
+
+// countKinds counts the supplied objects, and how many there are of each kind
+// in the order each kind first appears, e.g. "3 (2 Composition, 1
+// CustomResourceDefinition)".
+func countKinds(refs []xpv1.TypedReference) string {
+	if len(refs) == 0 {
+		return "0"
+	}
+	kinds := []string{}
+	count := map[string]int{}
+	for _, ref := range refs {
+		if count[ref.Kind] == 0 {
+			kinds = append(kinds, ref.Kind)
+		}
+		count[ref.Kind]++
+	}
+	cs := make([]string, len(kinds))
+	for i, k := range kinds {
+		cs[i] = fmt.Sprintf("%d %s", count[k], k)
+	}
+	return fmt.Sprintf("%d (%s)", len(refs), strings.Join(cs, ", "))
+}
+
+// A DependencyConstraint is a version constraint a package places on one of
+// its dependencies.
+type DependencyConstraint struct {
+	// Package is the OCI image name, without a tag or digest, of the package
+	// that places the constraint.
+	Package string `json:"package"`
+
+	// Constraints is the semver range the dependency's version must satisfy.
+	Constraints string `json:"constraints"`
+}
+
+// A DependencyConflict is a dependency whose version can't satisfy the
+// constraints of every package that depends on it.
+type DependencyConflict struct {
+	// Package is the OCI image name of the dependency, without a tag or
+	// digest.
+	Package string `json:"package"`
+
+	// Version is the installed version of the dependency. It's empty if the
+	// dependency isn't installed.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Constraints are the version constraints each package that depends on
+	// the dependency places on it.
+	Constraints []DependencyConstraint `json:"constraints"`
+}
+
+// String describes the conflict, e.g. "example.org/provider@v1.0.0:
+// example.org/config-a requires >=v2.0.0, example.org/config-b requires
+// <v2.0.0".
+func (c DependencyConflict) String() string {
+	pkg := c.Package
+	if c.Version != "" {
+		pkg = pkg + "@" + c.Version
+	}
+	cs := make([]string, len(c.Constraints))
+	for i, dc := range c.Constraints {
+		cs[i] = fmt.Sprintf("%s requires %s", dc.Package, dc.Constraints)
+	}
+	return fmt.Sprintf("%s: %s", pkg, strings.Join(cs, ", "))
+}
+
 // A ControllerReference references the controller (e.g. Deployment), if any,
 // that is responsible for reconciling the types a package revision installs.
 type ControllerReference struct {
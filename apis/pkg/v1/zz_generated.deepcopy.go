@@ -24,6 +24,7 @@ import (
 	commonv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -165,7 +166,7 @@ func (in *ConfigurationSpec) DeepCopy() *ConfigurationSpec {
 func (in *ConfigurationStatus) DeepCopyInto(out *ConfigurationStatus) {
 	*out = *in
 	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
-	out.PackageStatus = in.PackageStatus
+	in.PackageStatus.DeepCopyInto(&out.PackageStatus)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigurationStatus.
@@ -208,6 +209,61 @@ func (in *ControllerReference) DeepCopy() *ControllerReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DependencyConflict) DeepCopyInto(out *DependencyConflict) {
+	*out = *in
+	if in.Constraints != nil {
+		in, out := &in.Constraints, &out.Constraints
+		*out = make([]DependencyConstraint, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DependencyConflict.
+func (in *DependencyConflict) DeepCopy() *DependencyConflict {
+	if in == nil {
+		return nil
+	}
+	out := new(DependencyConflict)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DependencyConstraint) DeepCopyInto(out *DependencyConstraint) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DependencyConstraint.
+func (in *DependencyConstraint) DeepCopy() *DependencyConstraint {
+	if in == nil {
+		return nil
+	}
+	out := new(DependencyConstraint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternallyManagedObject) DeepCopyInto(out *ExternallyManagedObject) {
+	*out = *in
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternallyManagedObject.
+func (in *ExternallyManagedObject) DeepCopy() *ExternallyManagedObject {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternallyManagedObject)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PackageRevisionRuntimeSpec) DeepCopyInto(out *PackageRevisionRuntimeSpec) {
 	*out = *in
@@ -264,6 +320,13 @@ func (in *PackageRevisionSpec) DeepCopyInto(out *PackageRevisionSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.ExternallyManagedObjects != nil {
+		in, out := &in.ExternallyManagedObjects, &out.ExternallyManagedObjects
+		*out = make([]ExternallyManagedObject, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageRevisionSpec.
@@ -285,6 +348,18 @@ func (in *PackageRevisionStatus) DeepCopyInto(out *PackageRevisionStatus) {
 		*out = make([]commonv1.TypedReference, len(*in))
 		copy(*out, *in)
 	}
+	if in.DependencyConflicts != nil {
+		in, out := &in.DependencyConflicts, &out.DependencyConflicts
+		*out = make([]DependencyConflict, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Changes != nil {
+		in, out := &in.Changes, &out.Changes
+		*out = new(RevisionChanges)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.PermissionRequests != nil {
 		in, out := &in.PermissionRequests, &out.PermissionRequests
 		*out = make([]rbacv1.PolicyRule, len(*in))
@@ -369,6 +444,18 @@ func (in *PackageSpec) DeepCopyInto(out *PackageSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.UpdatePolicy != nil {
+		in, out := &in.UpdatePolicy, &out.UpdatePolicy
+		*out = new(UpdatePolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExternallyManagedObjects != nil {
+		in, out := &in.ExternallyManagedObjects, &out.ExternallyManagedObjects
+		*out = make([]ExternallyManagedObject, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageSpec.
@@ -384,6 +471,16 @@ func (in *PackageSpec) DeepCopy() *PackageSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PackageStatus) DeepCopyInto(out *PackageStatus) {
 	*out = *in
+	if in.LastGarbageCollection != nil {
+		in, out := &in.LastGarbageCollection, &out.LastGarbageCollection
+		*out = new(RevisionGarbageCollection)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UpdateCheck != nil {
+		in, out := &in.UpdateCheck, &out.UpdateCheck
+		*out = new(PackageUpdateCheck)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageStatus.
@@ -396,6 +493,27 @@ func (in *PackageStatus) DeepCopy() *PackageStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageUpdateCheck) DeepCopyInto(out *PackageUpdateCheck) {
+	*out = *in
+	in.LastCheckTime.DeepCopyInto(&out.LastCheckTime)
+	if in.AvailableVersions != nil {
+		in, out := &in.AvailableVersions, &out.AvailableVersions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageUpdateCheck.
+func (in *PackageUpdateCheck) DeepCopy() *PackageUpdateCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageUpdateCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Provider) DeepCopyInto(out *Provider) {
 	*out = *in
@@ -552,7 +670,7 @@ func (in *ProviderSpec) DeepCopy() *ProviderSpec {
 func (in *ProviderStatus) DeepCopyInto(out *ProviderStatus) {
 	*out = *in
 	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
-	out.PackageStatus = in.PackageStatus
+	in.PackageStatus.DeepCopyInto(&out.PackageStatus)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderStatus.
@@ -565,6 +683,57 @@ func (in *ProviderStatus) DeepCopy() *ProviderStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RevisionChanges) DeepCopyInto(out *RevisionChanges) {
+	*out = *in
+	if in.Added != nil {
+		in, out := &in.Added, &out.Added
+		*out = make([]commonv1.TypedReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.Changed != nil {
+		in, out := &in.Changed, &out.Changed
+		*out = make([]commonv1.TypedReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.Removed != nil {
+		in, out := &in.Removed, &out.Removed
+		*out = make([]commonv1.TypedReference, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RevisionChanges.
+func (in *RevisionChanges) DeepCopy() *RevisionChanges {
+	if in == nil {
+		return nil
+	}
+	out := new(RevisionChanges)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RevisionGarbageCollection) DeepCopyInto(out *RevisionGarbageCollection) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	if in.Revisions != nil {
+		in, out := &in.Revisions, &out.Revisions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RevisionGarbageCollection.
+func (in *RevisionGarbageCollection) DeepCopy() *RevisionGarbageCollection {
+	if in == nil {
+		return nil
+	}
+	out := new(RevisionGarbageCollection)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RuntimeConfigReference) DeepCopyInto(out *RuntimeConfigReference) {
 	*out = *in
@@ -589,3 +758,33 @@ func (in *RuntimeConfigReference) DeepCopy() *RuntimeConfigReference {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpdatePolicy) DeepCopyInto(out *UpdatePolicy) {
+	*out = *in
+	if in.Channel != nil {
+		in, out := &in.Channel, &out.Channel
+		*out = new(string)
+		**out = **in
+	}
+	if in.Version != nil {
+		in, out := &in.Version, &out.Version
+		*out = new(string)
+		**out = **in
+	}
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpdatePolicy.
+func (in *UpdatePolicy) DeepCopy() *UpdatePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(UpdatePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -18,6 +18,7 @@ package v1beta1
 
 import (
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -68,6 +69,47 @@ type ServiceAccountTemplate struct {
 	Metadata *ObjectMeta `json:"metadata,omitempty"`
 }
 
+// HorizontalPodAutoscalerTemplate is the template for the
+// HorizontalPodAutoscaler object.
+type HorizontalPodAutoscalerTemplate struct {
+	// Metadata contains the configurable metadata fields for the
+	// HorizontalPodAutoscaler. Its name is always the name of the Deployment
+	// it scales.
+	// +optional
+	Metadata *ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec contains the configurable spec fields for the
+	// HorizontalPodAutoscaler object.
+	Spec HorizontalPodAutoscalerSpec `json:"spec"`
+}
+
+// HorizontalPodAutoscalerSpec contains the configurable spec fields for the
+// HorizontalPodAutoscaler object. The HorizontalPodAutoscaler always scales the
+// package's Deployment.
+type HorizontalPodAutoscalerSpec struct {
+	// MinReplicas is the lower limit for the number of replicas to which the
+	// autoscaler can scale down. It defaults to 1 pod.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// MaxReplicas is the upper limit for the number of replicas to which the
+	// autoscaler can scale up. It cannot be less that minReplicas.
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicas int32 `json:"maxReplicas"`
+
+	// Metrics contains the specifications for which to use to calculate the
+	// desired replica count. If not set, the default metric will be set to 80%
+	// average CPU utilization.
+	// +optional
+	Metrics []autoscalingv2.MetricSpec `json:"metrics,omitempty"`
+
+	// Behavior configures the scaling behavior of the target in both Up and
+	// Down directions.
+	// +optional
+	Behavior *autoscalingv2.HorizontalPodAutoscalerBehavior `json:"behavior,omitempty"`
+}
+
 // DeploymentRuntimeConfigSpec specifies the configuration for a packaged controller.
 // Values provided will override package manager defaults. Labels and
 // annotations are passed to both the controller Deployment and ServiceAccount.
@@ -81,6 +123,17 @@ type DeploymentRuntimeConfigSpec struct {
 	// ServiceAccountTemplate is the template for the ServiceAccount object.
 	// +optional
 	ServiceAccountTemplate *ServiceAccountTemplate `json:"serviceAccountTemplate,omitempty"`
+	// HorizontalPodAutoscalerTemplate is the template for a
+	// HorizontalPodAutoscaler that scales the Deployment. It's only honored
+	// for Function packages, whose runtimes can serve requests from more than
+	// one replica. The HorizontalPodAutoscaler manages the Deployment's
+	// replicas when it's set.
+	//
+	// THIS IS AN ALPHA FIELD. Do not use it in production. It is not honored
+	// unless the relevant Crossplane feature flag is enabled, and may be
+	// changed or removed without notice.
+	// +optional
+	HorizontalPodAutoscalerTemplate *HorizontalPodAutoscalerTemplate `json:"horizontalPodAutoscalerTemplate,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -147,6 +147,31 @@ func (f *Function) SetCurrentIdentifier(s string) {
 	f.Status.CurrentIdentifier = s
 }
 
+// GetLastGarbageCollection of this Function.
+func (f *Function) GetLastGarbageCollection() *v1.RevisionGarbageCollection {
+	return f.Status.LastGarbageCollection
+}
+
+// SetLastGarbageCollection of this Function.
+func (f *Function) SetLastGarbageCollection(gc *v1.RevisionGarbageCollection) {
+	f.Status.LastGarbageCollection = gc
+}
+
+// GetUpdatePolicy of this Function.
+func (f *Function) GetUpdatePolicy() *v1.UpdatePolicy {
+	return f.Spec.UpdatePolicy
+}
+
+// GetUpdateCheck of this Function.
+func (f *Function) GetUpdateCheck() *v1.PackageUpdateCheck {
+	return f.Status.UpdateCheck
+}
+
+// SetUpdateCheck of this Function.
+func (f *Function) SetUpdateCheck(c *v1.PackageUpdateCheck) {
+	f.Status.UpdateCheck = c
+}
+
 // GetCommonLabels of this Function.
 func (f *Function) GetCommonLabels() map[string]string {
 	return f.Spec.CommonLabels
@@ -157,6 +182,16 @@ func (f *Function) SetCommonLabels(l map[string]string) {
 	f.Spec.CommonLabels = l
 }
 
+// GetExternallyManagedObjects of this Function.
+func (f *Function) GetExternallyManagedObjects() []v1.ExternallyManagedObject {
+	return f.Spec.ExternallyManagedObjects
+}
+
+// SetExternallyManagedObjects of this Function.
+func (f *Function) SetExternallyManagedObjects(o []v1.ExternallyManagedObject) {
+	f.Spec.ExternallyManagedObjects = o
+}
+
 // GetTLSServerSecretName of this Function.
 func (f *Function) GetTLSServerSecretName() *string {
 	return v1.GetSecretNameWithSuffix(f.GetName(), v1.TLSServerSecretNameSuffix)
@@ -254,6 +289,26 @@ func (r *FunctionRevision) SetDependencyStatus(found, installed, invalid int64)
 	r.Status.InvalidDependencies = invalid
 }
 
+// GetDependencyConflicts of this FunctionRevision.
+func (r *FunctionRevision) GetDependencyConflicts() []v1.DependencyConflict {
+	return r.Status.DependencyConflicts
+}
+
+// SetDependencyConflicts of this FunctionRevision.
+func (r *FunctionRevision) SetDependencyConflicts(c []v1.DependencyConflict) {
+	r.Status.DependencyConflicts = c
+}
+
+// GetChanges of this FunctionRevision.
+func (r *FunctionRevision) GetChanges() *v1.RevisionChanges {
+	return r.Status.Changes
+}
+
+// SetChanges of this FunctionRevision.
+func (r *FunctionRevision) SetChanges(c *v1.RevisionChanges) {
+	r.Status.Changes = c
+}
+
 // GetIgnoreCrossplaneConstraints of this FunctionRevision.
 func (r *FunctionRevision) GetIgnoreCrossplaneConstraints() *bool {
 	return r.Spec.IgnoreCrossplaneConstraints
@@ -324,6 +379,16 @@ func (r *FunctionRevision) SetCommonLabels(l map[string]string) {
 	r.Spec.CommonLabels = l
 }
 
+// GetExternallyManagedObjects of this FunctionRevision.
+func (r *FunctionRevision) GetExternallyManagedObjects() []v1.ExternallyManagedObject {
+	return r.Spec.ExternallyManagedObjects
+}
+
+// SetExternallyManagedObjects of this FunctionRevision.
+func (r *FunctionRevision) SetExternallyManagedObjects(o []v1.ExternallyManagedObject) {
+	r.Spec.ExternallyManagedObjects = o
+}
+
 // GetRevisions of this ConfigurationRevisionList.
 func (p *FunctionRevisionList) GetRevisions() []v1.PackageRevision {
 	prs := make([]v1.PackageRevision, len(p.Items))
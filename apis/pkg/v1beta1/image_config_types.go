@@ -0,0 +1,155 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MatchType is the method used to match the image.
+type MatchType string
+
+const (
+	// Prefix is used to match the prefix of the image.
+	Prefix MatchType = "Prefix"
+)
+
+// ImageMatch defines a rule for matching image.
+type ImageMatch struct {
+	// Type is the type of match.
+	// +optional
+	// +kubebuilder:validation:Enum=Prefix
+	// +kubebuilder:default=Prefix
+	Type MatchType `json:"type,omitempty"`
+
+	// Prefix is used to match the prefix of the image name, for example
+	// xpkg.upbound.io/crossplane-contrib. The registry must be included.
+	// +kubebuilder:validation:MinLength=1
+	Prefix string `json:"prefix"`
+}
+
+// ImageVerificationProvider is the provider used to verify image signatures.
+type ImageVerificationProvider string
+
+const (
+	// ImageVerificationProviderCosign verifies signatures made by cosign.
+	ImageVerificationProviderCosign ImageVerificationProvider = "Cosign"
+)
+
+// LocalSecretKeySelector selects a key of a Secret in Crossplane's namespace.
+type LocalSecretKeySelector struct {
+	// Name of the Secret.
+	Name string `json:"name"`
+
+	// Key of the Secret to select.
+	Key string `json:"key"`
+}
+
+// PublicKeyAuthority is a public key that package images may be signed with.
+type PublicKeyAuthority struct {
+	// SecretRef selects a PEM encoded ECDSA, RSA, or Ed25519 public key.
+	SecretRef LocalSecretKeySelector `json:"secretRef"`
+}
+
+// CosignAuthority is an authority whose signatures are trusted.
+type CosignAuthority struct {
+	// Name is the name of the authority.
+	Name string `json:"name"`
+
+	// Key configures the public key that package images may be signed with.
+	Key PublicKeyAuthority `json:"key"`
+}
+
+// CosignVerificationConfig configures verification of signatures made by
+// cosign.
+type CosignVerificationConfig struct {
+	// Authorities whose signatures are trusted. An image is verified if it
+	// has a valid signature from any of these authorities.
+	// +kubebuilder:validation:MinItems=1
+	Authorities []CosignAuthority `json:"authorities"`
+}
+
+// ImageVerification configures verification of package image signatures.
+type ImageVerification struct {
+	// Provider is the provider used to verify signatures.
+	// +kubebuilder:validation:Enum=Cosign
+	Provider ImageVerificationProvider `json:"provider"`
+
+	// Cosign configures verification of signatures made by cosign.
+	// +optional
+	Cosign *CosignVerificationConfig `json:"cosign,omitempty"`
+}
+
+// ImageRewrite configures how a package image is rewritten before it's pulled.
+type ImageRewrite struct {
+	// Prefix replaces the matched prefix of the image name, for example
+	// registry.example.org/mirror. The registry must be included.
+	// +kubebuilder:validation:MinLength=1
+	Prefix string `json:"prefix"`
+}
+
+// ImageConfigSpec contains the configuration for matching images.
+type ImageConfigSpec struct {
+	// MatchImages is a list of rules used to match package images. If more
+	// than one ImageConfig matches an image, the one with the longest
+	// matching prefix is used.
+	// +kubebuilder:validation:MinItems=1
+	MatchImages []ImageMatch `json:"matchImages"`
+
+	// Verification configures how package images matching this ImageConfig
+	// are verified. Images aren't verified if it's omitted. This can be used
+	// to exempt images from a less specific ImageConfig that requires
+	// verification.
+	// +optional
+	Verification *ImageVerification `json:"verification,omitempty"`
+
+	// RewriteImage configures how package images matching this ImageConfig
+	// are rewritten before they're pulled, for example to pull them from an
+	// internal mirror. Only the package manager's own requests are
+	// rewritten. Package names, dependencies, and the images run by package
+	// runtimes are unchanged. If more than one ImageConfig that configures
+	// rewriting matches an image, the one with the longest matching prefix
+	// is used. Rewritten images aren't matched again.
+	// +optional
+	RewriteImage *ImageRewrite `json:"rewriteImage,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +genclient
+// +genclient:nonNamespaced
+
+// ImageConfig configures how the package manager handles package images that
+// match its rules. Crossplane must be running with
+// --enable-signature-verification for verification to be enforced, and with
+// --enable-image-rewrites for images to be rewritten.
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories={crossplane}
+type ImageConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ImageConfigSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ImageConfigList contains a list of ImageConfig.
+type ImageConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ImageConfig `json:"items"`
+}
@@ -17,8 +17,11 @@ limitations under the License.
 package v1beta1
 
 import (
+	"sort"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
 	"github.com/crossplane/crossplane/internal/dag"
 )
 
@@ -116,6 +119,97 @@ func (d *Dependency) AddNeighbors(...dag.Node) error {
 	return nil
 }
 
+// LockStatus represents the status of the Lock.
+type LockStatus struct {
+	// DependencyConflicts are the dependencies in the Lock that have no
+	// version that satisfies the constraints of every package that depends on
+	// them, either because their installed version doesn't, or because no
+	// version that does could be found to install.
+	DependencyConflicts []v1.DependencyConflict `json:"dependencyConflicts,omitempty"`
+
+	// Graph is the resolved dependency graph of the packages in the Lock,
+	// sorted by source. Each package lists the constraints it places on its
+	// dependencies, and the versions of them that are installed.
+	// +optional
+	Graph []ResolvedPackage `json:"graph,omitempty"`
+}
+
+// A ResolvedPackage is a package in the resolved dependency graph of a Lock.
+type ResolvedPackage struct {
+	// Source is the OCI image name without a tag or digest.
+	Source string `json:"source"`
+
+	// Type is the type of package.
+	Type PackageType `json:"type"`
+
+	// Version is the installed tag or digest of the OCI image.
+	Version string `json:"version"`
+
+	// Dependencies are the dependencies of this package.
+	// +optional
+	Dependencies []ResolvedDependency `json:"dependencies,omitempty"`
+}
+
+// A ResolvedDependency is a dependency of a package in the resolved
+// dependency graph of a Lock.
+type ResolvedDependency struct {
+	// Package is the OCI image name without a tag or digest.
+	Package string `json:"package"`
+
+	// Type is the type of package.
+	Type PackageType `json:"type"`
+
+	// Constraints is the semver range the depending package requires the
+	// dependency's version to satisfy.
+	Constraints string `json:"constraints"`
+
+	// Version is the installed tag or digest of the dependency. It's empty
+	// if the dependency isn't installed.
+	// +optional
+	Version string `json:"version,omitempty"`
+}
+
+// Graph returns the resolved dependency graph of the packages in the Lock,
+// sorted by source.
+func (l *Lock) Graph() []ResolvedPackage {
+	installed := make(map[string]string, len(l.Packages))
+	for _, lp := range l.Packages {
+		installed[lp.Source] = lp.Version
+	}
+
+	out := make([]ResolvedPackage, len(l.Packages))
+	for i, lp := range l.Packages {
+		rp := ResolvedPackage{Source: lp.Source, Type: lp.Type, Version: lp.Version}
+		for _, dep := range lp.Dependencies {
+			rp.Dependencies = append(rp.Dependencies, ResolvedDependency{
+				Package:     dep.Package,
+				Type:        dep.Type,
+				Constraints: dep.Constraints,
+				Version:     installed[dep.Package],
+			})
+		}
+		out[i] = rp
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Source < out[j].Source })
+	return out
+}
+
+// Constraints returns the version constraints each package in the Lock places
+// on the supplied package source, sorted by the source of the package that
+// places them.
+func (l *Lock) Constraints(source string) []v1.DependencyConstraint {
+	out := []v1.DependencyConstraint{}
+	for _, lp := range l.Packages {
+		for _, dep := range lp.Dependencies {
+			if dep.Identifier() == source {
+				out = append(out, v1.DependencyConstraint{Package: lp.Source, Constraints: dep.Constraints})
+			}
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Package < out[j].Package })
+	return out
+}
+
 // +kubebuilder:object:root=true
 // +genclient
 // +genclient:nonNamespaced
@@ -130,6 +224,8 @@ type Lock struct {
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
 	Packages []LockPackage `json:"packages,omitempty"`
+
+	Status LockStatus `json:"status,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -21,10 +21,48 @@ limitations under the License.
 package v1beta1
 
 import (
+	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
 	"k8s.io/api/apps/v1"
+	"k8s.io/api/autoscaling/v2"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CosignAuthority) DeepCopyInto(out *CosignAuthority) {
+	*out = *in
+	out.Key = in.Key
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CosignAuthority.
+func (in *CosignAuthority) DeepCopy() *CosignAuthority {
+	if in == nil {
+		return nil
+	}
+	out := new(CosignAuthority)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CosignVerificationConfig) DeepCopyInto(out *CosignVerificationConfig) {
+	*out = *in
+	if in.Authorities != nil {
+		in, out := &in.Authorities, &out.Authorities
+		*out = make([]CosignAuthority, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CosignVerificationConfig.
+func (in *CosignVerificationConfig) DeepCopy() *CosignVerificationConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(CosignVerificationConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Dependency) DeepCopyInto(out *Dependency) {
 	*out = *in
@@ -116,6 +154,11 @@ func (in *DeploymentRuntimeConfigSpec) DeepCopyInto(out *DeploymentRuntimeConfig
 		*out = new(ServiceAccountTemplate)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.HorizontalPodAutoscalerTemplate != nil {
+		in, out := &in.HorizontalPodAutoscalerTemplate, &out.HorizontalPodAutoscalerTemplate
+		*out = new(HorizontalPodAutoscalerTemplate)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeploymentRuntimeConfigSpec.
@@ -325,7 +368,7 @@ func (in *FunctionSpec) DeepCopy() *FunctionSpec {
 func (in *FunctionStatus) DeepCopyInto(out *FunctionStatus) {
 	*out = *in
 	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
-	out.PackageStatus = in.PackageStatus
+	in.PackageStatus.DeepCopyInto(&out.PackageStatus)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FunctionStatus.
@@ -338,6 +381,212 @@ func (in *FunctionStatus) DeepCopy() *FunctionStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HorizontalPodAutoscalerSpec) DeepCopyInto(out *HorizontalPodAutoscalerSpec) {
+	*out = *in
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make([]v2.MetricSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Behavior != nil {
+		in, out := &in.Behavior, &out.Behavior
+		*out = new(v2.HorizontalPodAutoscalerBehavior)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HorizontalPodAutoscalerSpec.
+func (in *HorizontalPodAutoscalerSpec) DeepCopy() *HorizontalPodAutoscalerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HorizontalPodAutoscalerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HorizontalPodAutoscalerTemplate) DeepCopyInto(out *HorizontalPodAutoscalerTemplate) {
+	*out = *in
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = new(ObjectMeta)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HorizontalPodAutoscalerTemplate.
+func (in *HorizontalPodAutoscalerTemplate) DeepCopy() *HorizontalPodAutoscalerTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(HorizontalPodAutoscalerTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageConfig) DeepCopyInto(out *ImageConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageConfig.
+func (in *ImageConfig) DeepCopy() *ImageConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ImageConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageConfigList) DeepCopyInto(out *ImageConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ImageConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageConfigList.
+func (in *ImageConfigList) DeepCopy() *ImageConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ImageConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageConfigSpec) DeepCopyInto(out *ImageConfigSpec) {
+	*out = *in
+	if in.MatchImages != nil {
+		in, out := &in.MatchImages, &out.MatchImages
+		*out = make([]ImageMatch, len(*in))
+		copy(*out, *in)
+	}
+	if in.Verification != nil {
+		in, out := &in.Verification, &out.Verification
+		*out = new(ImageVerification)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RewriteImage != nil {
+		in, out := &in.RewriteImage, &out.RewriteImage
+		*out = new(ImageRewrite)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageConfigSpec.
+func (in *ImageConfigSpec) DeepCopy() *ImageConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageMatch) DeepCopyInto(out *ImageMatch) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageMatch.
+func (in *ImageMatch) DeepCopy() *ImageMatch {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageMatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageRewrite) DeepCopyInto(out *ImageRewrite) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageRewrite.
+func (in *ImageRewrite) DeepCopy() *ImageRewrite {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageRewrite)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageVerification) DeepCopyInto(out *ImageVerification) {
+	*out = *in
+	if in.Cosign != nil {
+		in, out := &in.Cosign, &out.Cosign
+		*out = new(CosignVerificationConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageVerification.
+func (in *ImageVerification) DeepCopy() *ImageVerification {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageVerification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LocalSecretKeySelector) DeepCopyInto(out *LocalSecretKeySelector) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalSecretKeySelector.
+func (in *LocalSecretKeySelector) DeepCopy() *LocalSecretKeySelector {
+	if in == nil {
+		return nil
+	}
+	out := new(LocalSecretKeySelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Lock) DeepCopyInto(out *Lock) {
 	*out = *in
@@ -350,6 +599,7 @@ func (in *Lock) DeepCopyInto(out *Lock) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Lock.
@@ -422,6 +672,35 @@ func (in *LockPackage) DeepCopy() *LockPackage {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LockStatus) DeepCopyInto(out *LockStatus) {
+	*out = *in
+	if in.DependencyConflicts != nil {
+		in, out := &in.DependencyConflicts, &out.DependencyConflicts
+		*out = make([]pkgv1.DependencyConflict, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Graph != nil {
+		in, out := &in.Graph, &out.Graph
+		*out = make([]ResolvedPackage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LockStatus.
+func (in *LockStatus) DeepCopy() *LockStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LockStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ObjectMeta) DeepCopyInto(out *ObjectMeta) {
 	*out = *in
@@ -456,6 +735,57 @@ func (in *ObjectMeta) DeepCopy() *ObjectMeta {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PublicKeyAuthority) DeepCopyInto(out *PublicKeyAuthority) {
+	*out = *in
+	out.SecretRef = in.SecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PublicKeyAuthority.
+func (in *PublicKeyAuthority) DeepCopy() *PublicKeyAuthority {
+	if in == nil {
+		return nil
+	}
+	out := new(PublicKeyAuthority)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResolvedDependency) DeepCopyInto(out *ResolvedDependency) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResolvedDependency.
+func (in *ResolvedDependency) DeepCopy() *ResolvedDependency {
+	if in == nil {
+		return nil
+	}
+	out := new(ResolvedDependency)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResolvedPackage) DeepCopyInto(out *ResolvedPackage) {
+	*out = *in
+	if in.Dependencies != nil {
+		in, out := &in.Dependencies, &out.Dependencies
+		*out = make([]ResolvedDependency, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResolvedPackage.
+func (in *ResolvedPackage) DeepCopy() *ResolvedPackage {
+	if in == nil {
+		return nil
+	}
+	out := new(ResolvedPackage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceAccountTemplate) DeepCopyInto(out *ServiceAccountTemplate) {
 	*out = *in
@@ -0,0 +1,371 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package apply contains the apply command.
+package apply
+
+import (
+	"context"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/alecthomas/kong"
+	"github.com/spf13/afero"
+	corev1 "k8s.io/api/core/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/cmd/crank/output"
+	"github.com/crossplane/crossplane/pkg/diff"
+	"github.com/crossplane/crossplane/pkg/render"
+)
+
+const (
+	errInitKubeClient       = "cannot init kubeclient"
+	errCrossValidation      = "cross-validation failed, nothing was applied"
+	errDryRunFailed         = "server-side dry-run failed, nothing was applied"
+	errDeferredDryRunFailed = "server-side dry-run failed, only definitions were applied"
+	errWriteOutput          = "cannot write output"
+	errFmtLoad              = "cannot load resources from %q"
+	errFmtApply             = "cannot apply %s %q"
+	errFmtWaitEstablished   = "cannot wait for %s %q to become established"
+
+	warnFmtNotDryRun    = "%s %q wasn't dry-run because its type is defined by a definition that isn't applied yet"
+	warnFmtGenerateName = "%s with generateName %q is created every time it's applied"
+
+	// How often to check whether an applied definition is established.
+	establishedPollInterval = 1 * time.Second
+)
+
+// Cmd arguments and flags for apply subcommand.
+type Cmd struct {
+	// Flags. Keep them in alphabetical order.
+	DryRun       bool          `help:"Validate and show what would change, but don't apply anything."`
+	FieldManager string        `help:"Name of the manager used to track field ownership." default:"crossplane-cli"`
+	Files        []string      `short:"f" name:"filename" required:"" type:"path" help:"A YAML file or directory of YAML files to apply. May be repeated."`
+	Timeout      time.Duration `help:"How long to run before timing out." default:"1m"`
+
+	fs afero.Fs
+}
+
+// Help prints out the help for the apply command.
+func (c *Cmd) Help() string {
+	return `
+This command applies Crossplane resources to the cluster, in a way that's
+aware of Crossplane types. It's intended to be used by pipelines that apply
+Compositions and CompositeResourceDefinitions (XRDs) from a Git repository.
+
+Before applying anything it:
+
+  1. Cross-validates Compositions against the XRDs being applied, falling
+     back to the CRDs in the cluster. Compositions must compose a type defined
+     by an XRD, and their patches must be valid against the relevant schemas.
+  2. Server-side dry-run applies every resource, so that defaulting,
+     validation, and admission webhooks run.
+  3. Prints a semantic diff of what would change for each resource.
+
+Nothing is applied unless every resource passes all of these checks.
+
+CustomResourceDefinitions and XRDs are applied first. The command waits for
+them to become established before it applies anything else. Resources of a
+type defined by a CRD or XRD that isn't in the cluster yet can't be dry-run
+until it is, so they're dry-run once their definition is established. If
+one of them fails its dry-run, only the definitions are applied.
+
+Resources with a generateName but no name are created, not applied, so a new
+resource is created every time they're applied.
+
+Examples:
+
+  # Validate, diff, and apply all resources in the apis directory.
+  crossplane beta apply -f apis/
+
+  # Validate and diff, but don't apply.
+  crossplane beta apply -f apis/ -f compositions/ --dry-run
+`
+}
+
+// AfterApply implements kong.AfterApply.
+func (c *Cmd) AfterApply() error {
+	c.fs = afero.NewOsFs()
+	return nil
+}
+
+// Run apply.
+func (c *Cmd) Run(k *kong.Context, logger logging.Logger, kubeconfig *rest.Config, p *output.Printer) error {
+	logger = logger.WithValues("cmd", "apply")
+
+	objs := make([]*unstructured.Unstructured, 0)
+	for _, f := range c.Files {
+		o, err := LoadResources(c.fs, f)
+		if err != nil {
+			return errors.Wrapf(err, errFmtLoad, f)
+		}
+		objs = append(objs, o...)
+	}
+	SortForApply(objs)
+	logger.Debug("Loaded resources", "count", len(objs))
+
+	s := runtime.NewScheme()
+	_ = scheme.AddToScheme(s)
+	_ = extv1.AddToScheme(s)
+	kube, err := client.New(kubeconfig, client.Options{Scheme: s})
+	if err != nil {
+		return errors.Wrap(err, errInitKubeClient)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+
+	return c.apply(ctx, kube, k.Stdout, p, objs)
+}
+
+// apply the supplied resources, which must be sorted by SortForApply.
+// Definitions (CRDs and XRDs) are applied first, and must become established
+// before the resources that may use the types they define are applied.
+func (c *Cmd) apply(ctx context.Context, kube client.Client, w io.Writer, p *output.Printer, objs []*unstructured.Unstructured) error { //nolint:gocyclo // Only a touch over.
+	warns, errs := CrossValidate(ctx, kube, objs)
+	for _, w := range warns {
+		p.Warnf("%s", w)
+	}
+	if len(errs) > 0 {
+		return errors.Wrap(errors.Join(errs...), errCrossValidation)
+	}
+
+	defs, rest := splitDefinitions(objs)
+	defined := definedKinds(defs)
+
+	// We dry-run everything we can before we apply anything. Resources of
+	// a type defined by a definition that isn't in the cluster yet can't be
+	// dry-run until their definition is applied and established.
+	d := diff.NewDiffer(kube)
+	defDiffs, errs := diffAll(ctx, d, defs, c.FieldManager, nil)
+	restDiffs, rerrs := diffAll(ctx, d, rest, c.FieldManager, defined)
+	if errs = append(errs, rerrs...); len(errs) > 0 {
+		return errors.Wrap(errors.Join(errs...), errDryRunFailed)
+	}
+
+	// The indices of rest that were deferred.
+	deferred := make([]int, 0)
+	for i, rd := range restDiffs {
+		if rd.Type == "" {
+			deferred = append(deferred, i)
+		}
+	}
+	diffs := append(defDiffs, dryRun(restDiffs)...)
+	if err := diff.PrintDiffs(w, diffs, false); err != nil {
+		return errors.Wrap(err, errWriteOutput)
+	}
+	p.Infof("%d of %d resources would change.", diff.Changed(diffs), len(diffs))
+
+	if c.DryRun {
+		for _, i := range deferred {
+			p.Warnf(warnFmtNotDryRun, rest[i].GetKind(), rest[i].GetName())
+		}
+		return nil
+	}
+
+	if err := c.applyAll(ctx, kube, p, defs, defDiffs); err != nil {
+		return err
+	}
+	for _, o := range defs {
+		if err := waitForEstablished(ctx, kube, o); err != nil {
+			return errors.Wrapf(err, errFmtWaitEstablished, o.GetKind(), o.GetName())
+		}
+	}
+
+	// Now that their definitions are established we can dry-run the
+	// resources we couldn't before. The definitions have already been
+	// applied, but nothing else has.
+	if len(deferred) > 0 {
+		objs := make([]*unstructured.Unstructured, len(deferred))
+		for n, i := range deferred {
+			objs[n] = rest[i]
+		}
+		dd, errs := diffAll(ctx, d, objs, c.FieldManager, nil)
+		if len(errs) > 0 {
+			return errors.Wrap(errors.Join(errs...), errDeferredDryRunFailed)
+		}
+		if err := diff.PrintDiffs(w, dd, false); err != nil {
+			return errors.Wrap(err, errWriteOutput)
+		}
+		p.Infof("%d of %d resources would change.", diff.Changed(dd), len(dd))
+		for n, i := range deferred {
+			restDiffs[i] = dd[n]
+		}
+	}
+
+	return c.applyAll(ctx, kube, p, rest, restDiffs)
+}
+
+// applyAll applies the supplied resources that the supplied diffs show would
+// change. Resources without a name are created using their generateName.
+func (c *Cmd) applyAll(ctx context.Context, kube client.Client, p *output.Printer, objs []*unstructured.Unstructured, diffs []diff.ResourceDiff) error {
+	for i, rd := range diffs {
+		if rd.Type == diff.ChangeTypeUnchanged {
+			continue
+		}
+		o := objs[i]
+		if o.GetName() == "" {
+			p.Warnf(warnFmtGenerateName, o.GetKind(), o.GetGenerateName())
+			if err := kube.Create(ctx, o, client.FieldOwner(c.FieldManager)); err != nil {
+				return errors.Wrapf(err, errFmtApply, o.GetKind(), o.GetGenerateName())
+			}
+			p.Infof("%s/%s created", o.GetKind(), o.GetName())
+			continue
+		}
+		if err := kube.Patch(ctx, o, client.Apply, diff.ApplyOptions(c.FieldManager)...); err != nil {
+			return errors.Wrapf(err, errFmtApply, o.GetKind(), o.GetName())
+		}
+		p.Infof("%s/%s applied", o.GetKind(), o.GetName())
+	}
+	return nil
+}
+
+// diffAll diffs the supplied resources. Resources that can't be dry-run
+// because their type isn't known to the API server, but is one of the supplied
+// defined kinds, are deferred. Their diff has an empty Type.
+func diffAll(ctx context.Context, d *diff.Differ, objs []*unstructured.Unstructured, fieldOwner string, defined map[schema.GroupKind]bool) ([]diff.ResourceDiff, []error) {
+	diffs := make([]diff.ResourceDiff, len(objs))
+	errs := make([]error, 0)
+	for i, o := range objs {
+		rd, err := d.Diff(ctx, "", o, fieldOwner)
+		if meta.IsNoMatchError(err) && defined[o.GroupVersionKind().GroupKind()] {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "%s %q", o.GetKind(), o.GetName()))
+			continue
+		}
+		diffs[i] = rd
+	}
+	return diffs, errs
+}
+
+// dryRun returns the supplied diffs, except those that were deferred.
+func dryRun(diffs []diff.ResourceDiff) []diff.ResourceDiff {
+	out := make([]diff.ResourceDiff, 0, len(diffs))
+	for _, rd := range diffs {
+		if rd.Type != "" {
+			out = append(out, rd)
+		}
+	}
+	return out
+}
+
+// LoadResources loads resources from a YAML file, or a directory of YAML
+// files.
+func LoadResources(fs afero.Fs, fileOrDir string) ([]*unstructured.Unstructured, error) {
+	stream, err := render.LoadYAMLStream(fs, fileOrDir)
+	if err != nil {
+		return nil, err
+	}
+	objs := make([]*unstructured.Unstructured, 0, len(stream))
+	for _, y := range stream {
+		u := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal(y, u); err != nil {
+			return nil, errors.Wrap(err, "cannot parse YAML manifest")
+		}
+		// Skip empty documents.
+		if len(u.Object) == 0 {
+			continue
+		}
+		objs = append(objs, u)
+	}
+	return objs, nil
+}
+
+// splitDefinitions splits the supplied resources into definitions (CRDs and
+// XRDs) and everything else, preserving their order.
+func splitDefinitions(objs []*unstructured.Unstructured) (defs, rest []*unstructured.Unstructured) {
+	for _, o := range objs {
+		switch o.GroupVersionKind().GroupKind() {
+		case extv1.SchemeGroupVersion.WithKind("CustomResourceDefinition").GroupKind(), v1.CompositeResourceDefinitionGroupVersionKind.GroupKind():
+			defs = append(defs, o)
+		default:
+			rest = append(rest, o)
+		}
+	}
+	return defs, rest
+}
+
+// definedKinds returns the kinds defined by the supplied definitions. An XRD
+// defines its composite resource kind, and its claim kind if it offers one.
+func definedKinds(defs []*unstructured.Unstructured) map[schema.GroupKind]bool {
+	kinds := map[schema.GroupKind]bool{}
+	for _, o := range defs {
+		group, _, _ := unstructured.NestedString(o.Object, "spec", "group")
+		for _, path := range [][]string{{"spec", "names", "kind"}, {"spec", "claimNames", "kind"}} {
+			if kind, _, _ := unstructured.NestedString(o.Object, path...); kind != "" {
+				kinds[schema.GroupKind{Group: group, Kind: kind}] = true
+			}
+		}
+	}
+	return kinds
+}
+
+// waitForEstablished waits until the supplied definition has an Established
+// condition with status True. CRDs and XRDs both report this condition.
+func waitForEstablished(ctx context.Context, c client.Reader, def *unstructured.Unstructured) error {
+	return wait.PollUntilContextCancel(ctx, establishedPollInterval, true, func(ctx context.Context) (bool, error) {
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(def.GroupVersionKind())
+		if err := c.Get(ctx, types.NamespacedName{Name: def.GetName()}, u); err != nil {
+			return false, err
+		}
+		conditions, _, _ := unstructured.NestedSlice(u.Object, "status", "conditions")
+		for _, cond := range conditions {
+			m, ok := cond.(map[string]any)
+			if ok && m["type"] == "Established" && m["status"] == string(corev1.ConditionTrue) {
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+}
+
+// SortForApply sorts the supplied resources in the order they should be
+// applied. CustomResourceDefinitions and XRDs are applied before Compositions,
+// which are applied before everything else. The order is otherwise stable.
+func SortForApply(objs []*unstructured.Unstructured) {
+	rank := func(u *unstructured.Unstructured) int {
+		switch u.GroupVersionKind().GroupKind() {
+		case extv1.SchemeGroupVersion.WithKind("CustomResourceDefinition").GroupKind():
+			return 0
+		case v1.CompositeResourceDefinitionGroupVersionKind.GroupKind():
+			return 1
+		case v1.CompositionGroupVersionKind.GroupKind():
+			return 2
+		default:
+			return 3
+		}
+	}
+	sort.SliceStable(objs, func(i, j int) bool { return rank(objs[i]) < rank(objs[j]) })
+}
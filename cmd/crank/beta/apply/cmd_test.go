@@ -0,0 +1,236 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apply
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/crossplane/cmd/crank/output"
+)
+
+const crd = `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: buckets.example.org
+spec:
+  group: example.org
+  names:
+    kind: Bucket
+    plural: buckets
+  scope: Cluster
+  versions:
+  - name: v1
+    served: true
+    storage: true
+    schema:
+      openAPIV3Schema:
+        type: object
+`
+
+const bucket = `
+apiVersion: example.org/v1
+kind: Bucket
+metadata:
+  name: cool-bucket
+`
+
+const generatedBucket = `
+apiVersion: example.org/v1
+kind: Bucket
+metadata:
+  generateName: cool-bucket-
+`
+
+// A fakeCluster is a MockClient backed cluster that only knows the Bucket
+// kind once its CRD has been applied.
+type fakeCluster struct {
+	// The resources that were really applied or created, in order.
+	applied []string
+
+	// Whether the Bucket CRD has been applied.
+	crd bool
+
+	// An error to return when dry-running a Bucket.
+	errDryRunBucket error
+}
+
+func (f *fakeCluster) client() client.Client {
+	noMatch := func(gvk schema.GroupVersionKind) error {
+		return &meta.NoKindMatchError{GroupKind: gvk.GroupKind(), SearchedVersions: []string{gvk.Version}}
+	}
+
+	return &test.MockClient{
+		MockList: test.NewMockListFn(nil),
+		MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+			u := obj.(*unstructured.Unstructured)
+			switch u.GetKind() {
+			case "CustomResourceDefinition":
+				if !f.crd {
+					return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+				}
+				_ = unstructured.SetNestedSlice(u.Object, []any{map[string]any{"type": "Established", "status": "True"}}, "status", "conditions")
+				return nil
+			case "Bucket":
+				if !f.crd {
+					return noMatch(u.GroupVersionKind())
+				}
+			}
+			return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+		},
+		MockPatch: func(_ context.Context, obj client.Object, _ client.Patch, opts ...client.PatchOption) error {
+			po := &client.PatchOptions{}
+			po.ApplyOptions(opts)
+			u := obj.(*unstructured.Unstructured)
+
+			if u.GetKind() == "Bucket" && !f.crd {
+				return noMatch(u.GroupVersionKind())
+			}
+			if len(po.DryRun) > 0 {
+				if u.GetKind() == "Bucket" {
+					return f.errDryRunBucket
+				}
+				return nil
+			}
+
+			// The real apply must use the same options as the dry-run.
+			if po.Force == nil || !*po.Force || po.FieldManager != "crossplane-cli" {
+				return errors.New("apply options don't match dry-run options")
+			}
+			f.applied = append(f.applied, u.GetKind()+"/"+u.GetName())
+			if u.GetKind() == "CustomResourceDefinition" {
+				f.crd = true
+			}
+			return nil
+		},
+		MockCreate: func(_ context.Context, obj client.Object, opts ...client.CreateOption) error {
+			co := &client.CreateOptions{}
+			co.ApplyOptions(opts)
+			u := obj.(*unstructured.Unstructured)
+
+			if u.GetKind() == "Bucket" && !f.crd {
+				return noMatch(u.GroupVersionKind())
+			}
+			u.SetName(u.GetGenerateName() + "abcde")
+			if len(co.DryRun) > 0 {
+				return nil
+			}
+			f.applied = append(f.applied, u.GetKind()+"/"+u.GetName())
+			return nil
+		},
+	}
+}
+
+func TestApply(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type args struct {
+		dryRun  bool
+		cluster *fakeCluster
+		objs    []string
+	}
+	type want struct {
+		applied []string
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"CRDAndCustomResource": {
+			reason: "A CRD should be applied and established before a custom resource of the type it defines is dry-run and applied.",
+			args: args{
+				cluster: &fakeCluster{},
+				objs:    []string{bucket, crd},
+			},
+			want: want{
+				applied: []string{"CustomResourceDefinition/buckets.example.org", "Bucket/cool-bucket"},
+			},
+		},
+		"CRDAndCustomResourceDryRun": {
+			reason: "Nothing should be applied in dry-run mode, even if a custom resource can't be dry-run until its CRD is applied.",
+			args: args{
+				dryRun:  true,
+				cluster: &fakeCluster{},
+				objs:    []string{crd, bucket},
+			},
+			want: want{
+				applied: nil,
+			},
+		},
+		"DeferredDryRunError": {
+			reason: "Only the CRD should be applied if a custom resource of the type it defines fails its dry-run.",
+			args: args{
+				cluster: &fakeCluster{errDryRunBucket: errBoom},
+				objs:    []string{crd, bucket},
+			},
+			want: want{
+				applied: []string{"CustomResourceDefinition/buckets.example.org"},
+				err:     errors.Wrap(errors.Join(errors.Wrapf(errors.Wrap(errBoom, "cannot server-side dry-run apply resource"), "%s %q", "Bucket", "cool-bucket")), errDeferredDryRunFailed),
+			},
+		},
+		"GenerateName": {
+			reason: "A resource with only a generateName should be created.",
+			args: args{
+				cluster: &fakeCluster{crd: true},
+				objs:    []string{generatedBucket},
+			},
+			want: want{
+				applied: []string{"Bucket/cool-bucket-abcde"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			objs := make([]*unstructured.Unstructured, len(tc.args.objs))
+			for i, y := range tc.args.objs {
+				objs[i] = &unstructured.Unstructured{}
+				if err := yaml.Unmarshal([]byte(y), objs[i]); err != nil {
+					t.Fatal(err)
+				}
+			}
+			SortForApply(objs)
+
+			c := &Cmd{DryRun: tc.args.dryRun, FieldManager: "crossplane-cli"}
+			p := output.NewPrinter(output.FormatText, "crossplane", io.Discard, io.Discard)
+			err := c.apply(context.Background(), tc.args.cluster.client(), io.Discard, p, objs)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\napply(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.applied, tc.args.cluster.applied); diff != "" {
+				t.Errorf("\n%s\napply(...): -want applied, +got applied:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apply
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/xcrd"
+	"github.com/crossplane/crossplane/pkg/validation/apiextensions/v1/composition"
+)
+
+const (
+	errListCRDs       = "cannot list CustomResourceDefinitions"
+	errConvertCRD     = "cannot convert CustomResourceDefinition"
+	errFmtConvertXRD  = "cannot convert CompositeResourceDefinition %q"
+	errFmtConvertComp = "cannot convert Composition %q"
+	errFmtRenderCRD   = "cannot render CustomResourceDefinition for CompositeResourceDefinition %q"
+	errFmtInvalidXRD  = "invalid CompositeResourceDefinition %q"
+	errFmtInvalidComp = "invalid Composition %q"
+	errFmtNoXRD       = "Composition %q composes %s, which isn't defined by any CompositeResourceDefinition in the supplied files or the cluster"
+	errFmtSchemas     = "Composition %q is invalid against the schemas of the resources it composes"
+	errFmtMissingCRD  = "Composition %q composes %s, which isn't defined in the cluster; skipping schema validation"
+)
+
+// CrossValidate validates the supplied Compositions and
+// CompositeResourceDefinitions (XRDs) against each other. Compositions are
+// validated against the schemas of the XRDs in the supplied objects, falling
+// back to the CustomResourceDefinitions in the cluster. This catches
+// Compositions that would be rejected (or misbehave) once their XRD is
+// applied, before anything is applied.
+func CrossValidate(ctx context.Context, c client.Reader, objs []*unstructured.Unstructured) (warns []string, errs []error) { //nolint:gocyclo // Mostly a flat sequence of checks.
+	crds := map[schema.GroupKind]apiextensions.CustomResourceDefinition{}
+
+	if c != nil {
+		l := &extv1.CustomResourceDefinitionList{}
+		if err := c.List(ctx, l); err != nil {
+			return nil, []error{errors.Wrap(err, errListCRDs)}
+		}
+		for i := range l.Items {
+			if err := addCRD(crds, &l.Items[i]); err != nil {
+				return nil, []error{err}
+			}
+		}
+	}
+
+	comps := make([]*v1.Composition, 0)
+	for _, o := range objs {
+		switch o.GroupVersionKind() {
+		case v1.CompositeResourceDefinitionGroupVersionKind:
+			xrd := &v1.CompositeResourceDefinition{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(o.Object, xrd); err != nil {
+				errs = append(errs, errors.Wrapf(err, errFmtConvertXRD, o.GetName()))
+				continue
+			}
+			w, ferrs := xrd.Validate()
+			warns = append(warns, w...)
+			if len(ferrs) > 0 {
+				errs = append(errs, errors.Wrapf(ferrs.ToAggregate(), errFmtInvalidXRD, xrd.GetName()))
+				continue
+			}
+			crd, err := xcrd.ForCompositeResource(xrd)
+			if err != nil {
+				errs = append(errs, errors.Wrapf(err, errFmtRenderCRD, xrd.GetName()))
+				continue
+			}
+			// The XRD being applied supersedes any CRD already in the cluster.
+			if err := addCRD(crds, crd); err != nil {
+				errs = append(errs, err)
+			}
+		case v1.CompositionGroupVersionKind:
+			comp := &v1.Composition{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(o.Object, comp); err != nil {
+				errs = append(errs, errors.Wrapf(err, errFmtConvertComp, o.GetName()))
+				continue
+			}
+			comps = append(comps, comp)
+		}
+	}
+
+	for _, comp := range comps {
+		w, e := validateComposition(ctx, crds, comp)
+		warns = append(warns, w...)
+		errs = append(errs, e...)
+	}
+
+	return warns, errs
+}
+
+func validateComposition(ctx context.Context, crds map[schema.GroupKind]apiextensions.CustomResourceDefinition, comp *v1.Composition) (warns []string, errs []error) {
+	w, ferrs := comp.Validate()
+	warns = append(warns, w...)
+	if len(ferrs) > 0 {
+		return warns, []error{errors.Wrapf(ferrs.ToAggregate(), errFmtInvalidComp, comp.GetName())}
+	}
+
+	xrGK := schema.FromAPIVersionAndKind(comp.Spec.CompositeTypeRef.APIVersion, comp.Spec.CompositeTypeRef.Kind).GroupKind()
+	if _, ok := crds[xrGK]; !ok {
+		return warns, []error{errors.Errorf(errFmtNoXRD, comp.GetName(), xrGK)}
+	}
+
+	// Like the Composition webhook, we skip schema validation if we don't
+	// know the schema of every composed resource.
+	for i := range comp.Spec.Resources {
+		gvk, err := composition.GetBaseObjectGVK(&comp.Spec.Resources[i])
+		if err != nil {
+			return warns, []error{errors.Wrapf(err, errFmtInvalidComp, comp.GetName())}
+		}
+		if _, ok := crds[gvk.GroupKind()]; !ok {
+			return append(warns, fmt.Sprintf(errFmtMissingCRD, comp.GetName(), gvk.GroupKind())), nil
+		}
+	}
+
+	v, err := composition.NewValidator(
+		composition.WithCRDGetterFromMap(crds),
+		// We already did logical validation above.
+		composition.WithoutLogicalValidation(),
+	)
+	if err != nil {
+		return warns, []error{err}
+	}
+	w, ferrs = v.Validate(ctx, comp)
+	warns = append(warns, w...)
+	if len(ferrs) > 0 {
+		errs = append(errs, errors.Wrapf(ferrs.ToAggregate(), errFmtSchemas, comp.GetName()))
+	}
+	return warns, errs
+}
+
+func addCRD(crds map[schema.GroupKind]apiextensions.CustomResourceDefinition, crd *extv1.CustomResourceDefinition) error {
+	internal := apiextensions.CustomResourceDefinition{}
+	if err := extv1.Convert_v1_CustomResourceDefinition_To_apiextensions_CustomResourceDefinition(crd, &internal, nil); err != nil {
+		return errors.Wrap(err, errConvertCRD)
+	}
+	crds[schema.GroupKind{Group: crd.Spec.Group, Kind: crd.Spec.Names.Kind}] = internal
+	return nil
+}
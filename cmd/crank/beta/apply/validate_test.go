@@ -0,0 +1,159 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apply
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+const xrd = `
+apiVersion: apiextensions.crossplane.io/v1
+kind: CompositeResourceDefinition
+metadata:
+  name: xbuckets.example.org
+spec:
+  group: example.org
+  names:
+    kind: XBucket
+    plural: xbuckets
+  versions:
+  - name: v1
+    served: true
+    referenceable: true
+    schema:
+      openAPIV3Schema:
+        type: object
+        properties:
+          spec:
+            type: object
+            properties:
+              region:
+                type: string
+`
+
+const comp = `
+apiVersion: apiextensions.crossplane.io/v1
+kind: Composition
+metadata:
+  name: xbuckets
+spec:
+  compositeTypeRef:
+    apiVersion: example.org/v1
+    kind: XBucket
+  mode: Pipeline
+  pipeline:
+  - step: compose
+    functionRef:
+      name: function-patch-and-transform
+`
+
+func mustParse(t *testing.T, y string) *unstructured.Unstructured {
+	t.Helper()
+	u := &unstructured.Unstructured{}
+	if err := yaml.Unmarshal([]byte(y), u); err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
+
+func TestCrossValidate(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type args struct {
+		client client.Reader
+		objs   []string
+	}
+	type want struct {
+		errs int
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ListCRDsError": {
+			reason: "We should return any error encountered listing CRDs.",
+			args: args{
+				client: &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+			},
+			want: want{
+				errs: 1,
+			},
+		},
+		"CompositionAndXRD": {
+			reason: "A Composition that composes a type defined by a supplied XRD should be valid.",
+			args: args{
+				client: &test.MockClient{MockList: test.NewMockListFn(nil)},
+				objs:   []string{xrd, comp},
+			},
+			want: want{
+				errs: 0,
+			},
+		},
+		"CompositionWithoutXRD": {
+			reason: "A Composition that composes a type no XRD defines should be invalid.",
+			args: args{
+				client: &test.MockClient{MockList: test.NewMockListFn(nil)},
+				objs:   []string{comp},
+			},
+			want: want{
+				errs: 1,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			objs := make([]*unstructured.Unstructured, 0, len(tc.args.objs))
+			for _, y := range tc.args.objs {
+				objs = append(objs, mustParse(t, y))
+			}
+			_, errs := CrossValidate(context.Background(), tc.args.client, objs)
+			if diff := cmp.Diff(tc.want.errs, len(errs)); diff != "" {
+				t.Errorf("\n%s\nCrossValidate(...): -want errors, +got errors:\n%s\n%v", tc.reason, diff, errs)
+			}
+		})
+	}
+}
+
+func TestSortForApply(t *testing.T) {
+	cm := mustParse(t, "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm\n")
+	x := mustParse(t, xrd)
+	c := mustParse(t, comp)
+
+	objs := []*unstructured.Unstructured{cm, c, x}
+	SortForApply(objs)
+
+	want := []string{"CompositeResourceDefinition", "Composition", "ConfigMap"}
+	got := make([]string, 0, len(objs))
+	for _, o := range objs {
+		got = append(got, o.GetKind())
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("SortForApply(...): -want, +got:\n%s", diff)
+	}
+}
@@ -20,11 +20,17 @@ limitations under the License.
 package beta
 
 import (
+	"github.com/crossplane/crossplane/cmd/crank/beta/apply"
 	"github.com/crossplane/crossplane/cmd/crank/beta/convert"
+	"github.com/crossplane/crossplane/cmd/crank/beta/diff"
+	"github.com/crossplane/crossplane/cmd/crank/beta/doctor"
+	"github.com/crossplane/crossplane/cmd/crank/beta/migrate"
+	"github.com/crossplane/crossplane/cmd/crank/beta/reconcile"
 	"github.com/crossplane/crossplane/cmd/crank/beta/render"
 	"github.com/crossplane/crossplane/cmd/crank/beta/top"
 	"github.com/crossplane/crossplane/cmd/crank/beta/trace"
 	"github.com/crossplane/crossplane/cmd/crank/beta/validate"
+	"github.com/crossplane/crossplane/cmd/crank/beta/whoowns"
 	"github.com/crossplane/crossplane/cmd/crank/beta/xpkg"
 )
 
@@ -32,12 +38,18 @@ import (
 type Cmd struct {
 	// Subcommands and flags will appear in the CLI help output in the same
 	// order they're specified here. Keep them in alphabetical order.
-	Convert  convert.Cmd  `cmd:"" help:"Convert a Crossplane resource to a newer version or kind."`
-	Render   render.Cmd   `cmd:"" help:"Render a composite resource (XR)."`
-	Top      top.Cmd      `cmd:"" help:"Display resource (CPU/memory) usage by Crossplane related pods."`
-	Trace    trace.Cmd    `cmd:"" help:"Trace a Crossplane resource to get a detailed output of its relationships, helpful for troubleshooting."`
-	XPKG     xpkg.Cmd     `cmd:"" help:"Manage Crossplane packages."`
-	Validate validate.Cmd `cmd:"" help:"Validate Crossplane resources."`
+	Apply     apply.Cmd     `cmd:"" help:"Validate, diff, and apply Crossplane resources."`
+	Convert   convert.Cmd   `cmd:"" help:"Convert a Crossplane resource to a newer version or kind."`
+	Diff      diff.Cmd      `cmd:"" help:"Show what would change in the cluster if an XR were composed."`
+	Doctor    doctor.Cmd    `cmd:"" help:"Check a Crossplane control plane for common problems."`
+	Migrate   migrate.Cmd   `cmd:"" help:"Migrate Crossplane manifests that use deprecated APIs."`
+	Reconcile reconcile.Cmd `cmd:"" help:"Trigger immediate reconciliation of Crossplane resources."`
+	Render    render.Cmd    `cmd:"" help:"Render a composite resource (XR)."`
+	Top       top.Cmd       `cmd:"" help:"Display resource (CPU/memory) usage by Crossplane related pods."`
+	Trace     trace.Cmd     `cmd:"" help:"Trace a Crossplane resource to get a detailed output of its relationships, helpful for troubleshooting."`
+	XPKG      xpkg.Cmd      `cmd:"" help:"Manage Crossplane packages."`
+	Validate  validate.Cmd  `cmd:"" help:"Validate Crossplane resources."`
+	WhoOwns   whoowns.Cmd   `cmd:"" name:"who-owns" help:"Find the composite resource and claim that own a cloud resource."`
 }
 
 // Help output for crossplane beta.
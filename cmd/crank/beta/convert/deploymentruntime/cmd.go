@@ -19,9 +19,15 @@ limitations under the License.
 package deploymentruntime
 
 import (
+	"bufio"
+	"bytes"
+	"fmt"
+	goio "io"
+
 	"github.com/spf13/afero"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/util/yaml"
 	"k8s.io/client-go/kubernetes/scheme"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
@@ -29,12 +35,13 @@ import (
 	"github.com/crossplane/crossplane/apis/pkg/v1alpha1"
 	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
 	"github.com/crossplane/crossplane/cmd/crank/beta/convert/io"
+	"github.com/crossplane/crossplane/cmd/crank/output"
 )
 
 // Cmd arguments and flags for convert deployment-runtime subcommand.
 type Cmd struct {
 	// Arguments.
-	InputFile string `arg:"" type:"path" optional:"" default:"-" help:"The ControllerConfig file to be Converted. If not specified or '-', stdin will be used."`
+	InputFile string `arg:"" type:"path" optional:"" default:"-" help:"The ControllerConfig file to be Converted. May contain multiple ControllerConfigs, or a ControllerConfigList. If not specified or '-', stdin will be used."`
 
 	// Flags.
 	OutputFile string `short:"o" type:"path" placeholder:"PATH" help:"The file to write the generated DeploymentRuntimeConfig to. If not specified, stdout will be used."`
@@ -50,6 +57,11 @@ This command converts a Crossplane ControllerConfig to a DeploymentRuntimeConfig
 DeploymentRuntimeConfig was introduced in Crossplane 1.14 and ControllerConfig is
 deprecated.
 
+The input may contain multiple ControllerConfigs separated by '---', or a
+ControllerConfigList like the one output by 'kubectl get controllerconfigs -o
+yaml'. A DeploymentRuntimeConfig is written for each ControllerConfig. Any
+fields that can't be translated are reported as warnings on stderr.
+
 Examples:
 
   # Write out a DeploymentRuntimeConfigFile from a ControllerConfig
@@ -58,6 +70,9 @@ Examples:
   # Create a new DeploymentRuntimeConfig via Stdout
   crossplane beta convert deployment-runtime cc.yaml | grep -v creationTimestamp | kubectl apply -f - 
 
+  # Convert all ControllerConfigs in the cluster
+  kubectl get controllerconfigs -o yaml | crossplane beta convert deployment-runtime -o drcs.yaml
+
 `
 }
 
@@ -68,30 +83,104 @@ func (c *Cmd) AfterApply() error {
 }
 
 // Run converts a ControllerConfig to a DeploymentRuntimeConfig.
-func (c *Cmd) Run() error {
+func (c *Cmd) Run(p *output.Printer) error {
 	data, err := io.Read(c.fs, c.InputFile)
 	if err != nil {
 		return err
 	}
 
+	drcs, warns, err := Convert(data)
+	if err != nil {
+		return err
+	}
+
+	for _, w := range warns {
+		p.Warnf("%s", w)
+	}
+
+	objs := make([]runtime.Object, 0, len(drcs))
+	for _, drc := range drcs {
+		objs = append(objs, drc)
+	}
+	return io.WriteObjectsYAML(c.fs, c.OutputFile, objs...)
+}
+
+// Convert all ControllerConfigs in the supplied YAML stream to
+// DeploymentRuntimeConfigs. It also returns warnings describing each field
+// that couldn't be translated.
+func Convert(data []byte) ([]*v1beta1.DeploymentRuntimeConfig, []string, error) {
+	ccs, warns, err := decodeControllerConfigs(data)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Decode Error")
+	}
+
+	drcs := make([]*v1beta1.DeploymentRuntimeConfig, 0, len(ccs))
+	for _, cc := range ccs {
+		drc, err := controllerConfigToDeploymentRuntimeConfig(cc)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "Cannot migrate to Deployment Runtime")
+		}
+		for _, w := range untranslatableFields(cc) {
+			warns = append(warns, fmt.Sprintf("ControllerConfig %q: %s", cc.GetName(), w))
+		}
+		drcs = append(drcs, drc)
+	}
+	return drcs, warns, nil
+}
+
+// decodeControllerConfigs decodes all ControllerConfigs in the supplied YAML
+// stream. Fields that aren't part of the ControllerConfig schema can't be
+// translated, so they're returned as warnings rather than errors.
+func decodeControllerConfigs(data []byte) ([]*v1alpha1.ControllerConfig, []string, error) { //nolint:gocyclo // Only a touch over.
 	// Set up schemes for our API types
 	sch := runtime.NewScheme()
 	_ = scheme.AddToScheme(sch)
 	_ = v1alpha1.AddToScheme(sch)
 	_ = v1beta1.AddToScheme(sch)
 
-	decode := serializer.NewCodecFactory(sch).UniversalDeserializer().Decode
-
-	cc := &v1alpha1.ControllerConfig{}
-	_, _, err = decode(data, &v1alpha1.ControllerConfigGroupVersionKind, cc)
-	if err != nil {
-		return errors.Wrap(err, "Decode Error")
+	decode := serializer.NewCodecFactory(sch, serializer.EnableStrict).UniversalDeserializer().Decode
+
+	var ccs []*v1alpha1.ControllerConfig
+	var warns []string
+	r := yaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(data)))
+	for {
+		doc, err := r.Read()
+		if errors.Is(err, goio.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "cannot read YAML document")
+		}
+		// Skip empty documents.
+		if len(bytes.TrimSpace(bytes.TrimPrefix(bytes.TrimSpace(doc), []byte("---")))) == 0 {
+			continue
+		}
+
+		obj, _, err := decode(doc, &v1alpha1.ControllerConfigGroupVersionKind, nil)
+		if se, ok := runtime.AsStrictDecodingError(err); ok {
+			for _, e := range se.Errors() {
+				warns = append(warns, fmt.Sprintf("%s (not translated)", e))
+			}
+			err = nil
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		switch o := obj.(type) {
+		case *v1alpha1.ControllerConfig:
+			ccs = append(ccs, o)
+		case *v1alpha1.ControllerConfigList:
+			for i := range o.Items {
+				ccs = append(ccs, &o.Items[i])
+			}
+		default:
+			return nil, nil, errors.Errorf("unsupported kind %q, expected a ControllerConfig", obj.GetObjectKind().GroupVersionKind().Kind)
+		}
 	}
 
-	drc, err := controllerConfigToDeploymentRuntimeConfig(cc)
-	if err != nil {
-		return errors.Wrap(err, "Cannot migrate to Deployment Runtime")
+	if len(ccs) == 0 {
+		return nil, nil, errors.New("no ControllerConfigs found")
 	}
-
-	return io.WriteObjectYAML(c.fs, c.OutputFile, drc)
+	return ccs, warns, nil
 }
@@ -0,0 +1,129 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deploymentruntime
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDecodeControllerConfigs(t *testing.T) {
+	type want struct {
+		names []string
+		warns int
+		err   bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		data   string
+		want   want
+	}{
+		"Single": {
+			reason: "A single ControllerConfig should be decoded",
+			data: `
+apiVersion: pkg.crossplane.io/v1alpha1
+kind: ControllerConfig
+metadata:
+  name: a
+`,
+			want: want{names: []string{"a"}},
+		},
+		"Stream": {
+			reason: "All ControllerConfigs in a YAML stream should be decoded",
+			data: `
+apiVersion: pkg.crossplane.io/v1alpha1
+kind: ControllerConfig
+metadata:
+  name: a
+---
+apiVersion: pkg.crossplane.io/v1alpha1
+kind: ControllerConfig
+metadata:
+  name: b
+`,
+			want: want{names: []string{"a", "b"}},
+		},
+		"List": {
+			reason: "All ControllerConfigs in a ControllerConfigList should be decoded",
+			data: `
+apiVersion: pkg.crossplane.io/v1alpha1
+kind: ControllerConfigList
+items:
+- apiVersion: pkg.crossplane.io/v1alpha1
+  kind: ControllerConfig
+  metadata:
+    name: a
+- apiVersion: pkg.crossplane.io/v1alpha1
+  kind: ControllerConfig
+  metadata:
+    name: b
+`,
+			want: want{names: []string{"a", "b"}},
+		},
+		"UnknownField": {
+			reason: "Fields that aren't part of the ControllerConfig schema should produce a warning",
+			data: `
+apiVersion: pkg.crossplane.io/v1alpha1
+kind: ControllerConfig
+metadata:
+  name: a
+spec:
+  coolField: true
+`,
+			want: want{names: []string{"a"}, warns: 1},
+		},
+		"WrongKind": {
+			reason: "Kinds other than ControllerConfig should return an error",
+			data: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: a
+`,
+			want: want{err: true},
+		},
+		"Empty": {
+			reason: "An input without any ControllerConfigs should return an error",
+			data:   "---\n",
+			want:   want{err: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ccs, warns, err := decodeControllerConfigs([]byte(tc.data))
+			if (err != nil) != tc.want.err {
+				t.Fatalf("%s\ndecodeControllerConfigs(...): want error %t, got %v", tc.reason, tc.want.err, err)
+			}
+			names := make([]string, 0, len(ccs))
+			for _, cc := range ccs {
+				names = append(names, cc.GetName())
+			}
+			if tc.want.err {
+				return
+			}
+			if diff := cmp.Diff(tc.want.names, names); diff != "" {
+				t.Errorf("%s\ndecodeControllerConfigs(...): -want names, +got names:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.warns, len(warns)); diff != "" {
+				t.Errorf("%s\ndecodeControllerConfigs(...): -want warnings, +got warnings:\n%s\n%v", tc.reason, diff, warns)
+			}
+		})
+	}
+}
@@ -34,6 +34,10 @@ const (
 	runtimeContainerName = "package-runtime"
 
 	errNilControllerConfig = "ControllerConfig is nil"
+
+	// Crossplane creates and manages a DeploymentRuntimeConfig with this
+	// name, and uses it for all packages that don't reference another.
+	defaultRuntimeConfigName = "default"
 )
 
 var timeNow = time.Now()
@@ -57,6 +61,20 @@ func controllerConfigToDeploymentRuntimeConfig(cc *v1alpha1.ControllerConfig) (*
 	return drc, nil
 }
 
+// untranslatableFields returns a description of each part of the supplied
+// ControllerConfig that can't be faithfully translated to a
+// DeploymentRuntimeConfig.
+func untranslatableFields(cc *v1alpha1.ControllerConfig) []string {
+	if cc == nil {
+		return nil
+	}
+	var warns []string
+	if cc.GetName() == defaultRuntimeConfigName {
+		warns = append(warns, "metadata.name: Crossplane uses the DeploymentRuntimeConfig named \"default\" for all packages that don't reference a runtime config. Applying it would affect every such package, not only those that referenced this ControllerConfig. Consider renaming it.")
+	}
+	return warns
+}
+
 func deploymentTemplateFromControllerConfig(cc *v1alpha1.ControllerConfig) *v1beta1.DeploymentTemplate { //nolint:gocyclo // Just a lot of if, then set field
 	if cc == nil || !shouldCreateDeploymentTemplate(cc) {
 		return nil
@@ -382,3 +382,47 @@ func TestNewContainerFromControllerConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestUntranslatableFields(t *testing.T) {
+	type args struct {
+		cc *v1alpha1.ControllerConfig
+	}
+	type want struct {
+		warns int
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NilControllerConfig": {
+			reason: "A nil ControllerConfig has nothing to translate",
+			args:   args{},
+			want:   want{warns: 0},
+		},
+		"Translatable": {
+			reason: "A ControllerConfig that can be translated shouldn't produce warnings",
+			args: args{
+				cc: &v1alpha1.ControllerConfig{ObjectMeta: metav1.ObjectMeta{Name: "test"}},
+			},
+			want: want{warns: 0},
+		},
+		"DefaultName": {
+			reason: "A ControllerConfig named default would replace the default DeploymentRuntimeConfig",
+			args: args{
+				cc: &v1alpha1.ControllerConfig{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+			},
+			want: want{warns: 1},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := untranslatableFields(tc.args.cc)
+			if diff := cmp.Diff(tc.want.warns, len(got)); diff != "" {
+				t.Errorf("%s\nuntranslatableFields(...): -want warnings, +got warnings:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -19,6 +19,7 @@ limitations under the License.
 package io
 
 import (
+	"fmt"
 	"io"
 	"os"
 
@@ -49,6 +50,13 @@ func Read(fs afero.Fs, inputFile string) ([]byte, error) {
 // WriteObjectYAML writes the given object to the given file or stdout if no
 // file is given. The output format is YAML.
 func WriteObjectYAML(fs afero.Fs, outputFile string, o runtime.Object) error {
+	return WriteObjectsYAML(fs, outputFile, o)
+}
+
+// WriteObjectsYAML writes the given objects to the given file or stdout if no
+// file is given. The output format is YAML. Multiple objects are written as a
+// YAML stream, separated by '---'.
+func WriteObjectsYAML(fs afero.Fs, outputFile string, objs ...runtime.Object) error {
 	s := json.NewSerializerWithOptions(json.DefaultMetaFactory, scheme.Scheme, scheme.Scheme, json.SerializerOptions{Yaml: true})
 
 	var output io.Writer
@@ -64,9 +72,15 @@ func WriteObjectYAML(fs afero.Fs, outputFile string, o runtime.Object) error {
 		output = os.Stdout
 	}
 
-	err := s.Encode(o, output)
-	if err != nil {
-		return errors.Wrap(err, "Unable to encode output")
+	for _, o := range objs {
+		if len(objs) > 1 {
+			if _, err := fmt.Fprintln(output, "---"); err != nil {
+				return errors.Wrap(err, "Unable to write output")
+			}
+		}
+		if err := s.Encode(o, output); err != nil {
+			return errors.Wrap(err, "Unable to encode output")
+		}
 	}
 	return nil
 }
@@ -90,12 +90,7 @@ func (c *Cmd) Run() error {
 		return errors.Wrap(err, "Decoding Error")
 	}
 
-	_, errs := oc.Validate()
-	if len(errs) > 0 {
-		return errors.Wrap(errs.ToAggregate(), "Existing Composition Validation error")
-	}
-
-	pc, err := convertPnTToPipeline(oc, c.FunctionName)
+	pc, err := Convert(oc, c.FunctionName)
 	if err != nil {
 		return errors.Wrap(err, "Error generating new Composition")
 	}
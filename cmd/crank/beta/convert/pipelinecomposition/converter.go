@@ -35,6 +35,20 @@ const (
 	errNilComposition      = "provided Composition is empty"
 )
 
+// Convert the supplied patch-and-transform Composition to a function pipeline
+// Composition that uses the supplied patch-and-transform Function. The
+// Composition is validated before it's converted. Compositions that are
+// already in Pipeline mode are returned unchanged.
+func Convert(c *v1.Composition, functionRefName string) (*v1.Composition, error) {
+	if c == nil {
+		return nil, errors.New(errNilComposition)
+	}
+	if _, errs := c.Validate(); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid Composition: %w", errs.ToAggregate())
+	}
+	return convertPnTToPipeline(c, functionRefName)
+}
+
 // convertPnTToPipeline takes a patch-and-transform composition and returns
 // a composition where the built-in patch & transform has been moved to a
 // function. If the existing composition has PipelineMode enabled, it will
@@ -0,0 +1,145 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package diff implements the diff command. Diffing itself is implemented by
+// github.com/crossplane/crossplane/pkg/diff.
+package diff
+
+import (
+	"context"
+	"time"
+
+	"github.com/alecthomas/kong"
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/cmd/crank/output"
+	"github.com/crossplane/crossplane/pkg/diff"
+	"github.com/crossplane/crossplane/pkg/render"
+)
+
+const (
+	errInitKubeClient = "cannot init kubeclient"
+	errWriteOutput    = "cannot write output"
+)
+
+// Cmd arguments and flags for diff subcommand.
+type Cmd struct {
+	// Arguments.
+	CompositeResource string `arg:"" type:"existingfile" help:"A YAML file specifying the composite resource (XR) to diff."`
+	Composition       string `arg:"" type:"existingfile" help:"A YAML file specifying the Composition to use to render the XR. Must be mode: Pipeline."`
+	Functions         string `arg:"" type:"path" help:"A YAML file or directory of YAML files specifying the Composition Functions to use to render the XR."`
+
+	// Flags. Keep them in alphabetical order.
+	ExtraResources string        `short:"e" placeholder:"PATH" type:"path" help:"A YAML file or directory of YAML files specifying extra resources to pass to the Function pipeline."`
+	ShowUnchanged  bool          `help:"Also list composed resources that wouldn't change."`
+	Timeout        time.Duration `help:"How long to run before timing out." default:"1m"`
+
+	fs afero.Fs
+}
+
+// Help prints out the help for the diff command.
+func (c *Cmd) Help() string {
+	return `
+This command shows you what would change in the cluster if Crossplane composed
+the supplied XR using the supplied Composition. It's similar to kubectl diff,
+but Composition aware.
+
+The Composition Function pipeline is run locally, the same way the render
+command runs it. If the XR already exists in the cluster, its composed
+resources are read from the cluster and passed to the pipeline as observed
+state. Each desired composed resource is then server-side dry-run applied to
+the cluster, and the result is compared to what's currently in the cluster.
+
+Examples:
+
+  # Show what would change if my-composition.yaml were used to compose xr.yaml.
+  crossplane beta diff xr.yaml my-composition.yaml functions.yaml
+
+  # Also list composed resources that wouldn't change.
+  crossplane beta diff xr.yaml my-composition.yaml functions.yaml --show-unchanged
+`
+}
+
+// AfterApply implements kong.AfterApply.
+func (c *Cmd) AfterApply() error {
+	c.fs = afero.NewOsFs()
+	return nil
+}
+
+// Run diff.
+func (c *Cmd) Run(k *kong.Context, logger logging.Logger, kubeconfig *rest.Config, p *output.Printer) error {
+	logger = logger.WithValues("cmd", "diff")
+
+	xr, err := render.LoadCompositeResource(c.fs, c.CompositeResource)
+	if err != nil {
+		return errors.Wrapf(err, "cannot load composite resource from %q", c.CompositeResource)
+	}
+
+	comp, err := render.LoadComposition(c.fs, c.Composition)
+	if err != nil {
+		return errors.Wrapf(err, "cannot load Composition from %q", c.Composition)
+	}
+	if m := comp.Spec.Mode; m == nil || *m != v1.CompositionModePipeline {
+		return errors.Errorf("diff only supports Composition Function pipelines: Composition %q must use spec.mode: Pipeline", comp.GetName())
+	}
+
+	fns, err := render.LoadFunctions(c.fs, c.Functions)
+	if err != nil {
+		return errors.Wrapf(err, "cannot load functions from %q", c.Functions)
+	}
+
+	ers := []unstructured.Unstructured{}
+	if c.ExtraResources != "" {
+		ers, err = render.LoadExtraResources(c.fs, c.ExtraResources)
+		if err != nil {
+			return errors.Wrapf(err, "cannot load extra resources from %q", c.ExtraResources)
+		}
+	}
+
+	kube, err := client.New(kubeconfig, client.Options{Scheme: scheme.Scheme})
+	if err != nil {
+		return errors.Wrap(err, errInitKubeClient)
+	}
+	logger.Debug("Built client")
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+
+	diffs, err := diff.DiffCompositeResource(ctx, kube, render.Inputs{
+		CompositeResource: xr,
+		Composition:       comp,
+		Functions:         fns,
+		ExtraResources:    ers,
+	})
+	if err != nil {
+		return err
+	}
+	logger.Debug("Diffed composed resources", "count", len(diffs))
+
+	if err := diff.PrintDiffs(k.Stdout, diffs, c.ShowUnchanged); err != nil {
+		return errors.Wrap(err, errWriteOutput)
+	}
+	p.Infof("%d of %d resources would change.", diff.Changed(diffs), len(diffs))
+	return nil
+}
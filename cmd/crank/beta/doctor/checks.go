@@ -0,0 +1,371 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package doctor
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/semver"
+	corev1 "k8s.io/api/core/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+)
+
+const (
+	errListProviders      = "cannot list Providers"
+	errListConfigurations = "cannot list Configurations"
+	errListFunctions      = "cannot list Functions"
+	errGetLock            = "cannot get package Lock"
+	errGetSecret          = "cannot get webhook TLS Secret"
+	errListCRDs           = "cannot list CustomResourceDefinitions"
+	errFmtListResources   = "cannot list %s"
+)
+
+// Check names.
+const (
+	checkPackages = "packages"
+	checkLock     = "lock"
+	checkWebhook  = "webhook-certificate"
+	checkCRDs     = "crds"
+	checkPaused   = "paused"
+)
+
+// lockName is the name of the package Lock.
+const lockName = "lock"
+
+// certExpiryWarning is how long before a webhook TLS certificate expires we
+// start warning about it.
+const certExpiryWarning = 30 * 24 * time.Hour
+
+// Categories of Crossplane resources that can be paused.
+var pausableCategories = map[string]bool{
+	"managed":   true,
+	"composite": true,
+	"claim":     true,
+}
+
+type pkg struct {
+	kind string
+	v1.Package
+}
+
+func (p pkg) ref() string {
+	return p.kind + "/" + p.GetName()
+}
+
+func listPackages(ctx context.Context, c client.Reader) ([]pkg, error) {
+	pkgs := make([]pkg, 0)
+
+	prvs := &v1.ProviderList{}
+	if err := c.List(ctx, prvs); err != nil {
+		return nil, errors.Wrap(err, errListProviders)
+	}
+	for i := range prvs.Items {
+		pkgs = append(pkgs, pkg{kind: v1.ProviderKind, Package: &prvs.Items[i]})
+	}
+
+	cfgs := &v1.ConfigurationList{}
+	if err := c.List(ctx, cfgs); err != nil {
+		return nil, errors.Wrap(err, errListConfigurations)
+	}
+	for i := range cfgs.Items {
+		pkgs = append(pkgs, pkg{kind: v1.ConfigurationKind, Package: &cfgs.Items[i]})
+	}
+
+	fns := &v1beta1.FunctionList{}
+	if err := c.List(ctx, fns); err != nil {
+		return nil, errors.Wrap(err, errListFunctions)
+	}
+	for i := range fns.Items {
+		pkgs = append(pkgs, pkg{kind: v1beta1.FunctionKind, Package: &fns.Items[i]})
+	}
+
+	return pkgs, nil
+}
+
+// CheckPackages checks that all packages are installed and healthy.
+func CheckPackages() Check {
+	return Check{Name: checkPackages, Run: func(ctx context.Context, c client.Reader) ([]Finding, error) {
+		pkgs, err := listPackages(ctx, c)
+		if err != nil {
+			return nil, err
+		}
+		findings := make([]Finding, 0)
+		for _, p := range pkgs {
+			for _, ct := range []xpv1.ConditionType{v1.TypeInstalled, v1.TypeHealthy} {
+				cond := p.GetCondition(ct)
+				if cond.Status == corev1.ConditionTrue {
+					continue
+				}
+				findings = append(findings, Finding{
+					Severity:    SeverityCritical,
+					Check:       checkPackages,
+					Resource:    p.ref(),
+					Problem:     fmt.Sprintf("package is not %s%s", conditionAdjective(ct), describe(cond)),
+					Remediation: fmt.Sprintf("Run 'crossplane beta trace %s %s' to find out why.", p.kind, p.GetName()),
+				})
+				// A package that isn't installed can't be healthy.
+				break
+			}
+		}
+		return findings, nil
+	}}
+}
+
+// CheckLock checks that the current revision of every package is in the
+// package Lock, and that all dependencies in the Lock are satisfied.
+func CheckLock() Check { //nolint:gocognit // Only slightly over.
+	return Check{Name: checkLock, Run: func(ctx context.Context, c client.Reader) ([]Finding, error) {
+		pkgs, err := listPackages(ctx, c)
+		if err != nil {
+			return nil, err
+		}
+		l := &v1beta1.Lock{}
+		if err := c.Get(ctx, client.ObjectKey{Name: lockName}, l); client.IgnoreNotFound(err) != nil {
+			return nil, errors.Wrap(err, errGetLock)
+		}
+
+		findings := make([]Finding, 0)
+		locked := make(map[string]v1beta1.LockPackage, len(l.Packages))
+		bySource := make(map[string]v1beta1.LockPackage, len(l.Packages))
+		for _, lp := range l.Packages {
+			locked[lp.Name] = lp
+			bySource[lp.Source] = lp
+		}
+
+		for _, p := range pkgs {
+			rev := p.GetCurrentRevision()
+			if rev == "" {
+				continue
+			}
+			if _, ok := locked[rev]; !ok {
+				findings = append(findings, Finding{
+					Severity:    SeverityWarning,
+					Check:       checkLock,
+					Resource:    p.ref(),
+					Problem:     fmt.Sprintf("current revision %q is missing from the package Lock", rev),
+					Remediation: "Check the Crossplane logs for errors resolving package dependencies. Crossplane adds the revision back to the Lock when it next reconciles it.",
+				})
+			}
+		}
+
+		for _, lp := range l.Packages {
+			for _, dep := range lp.Dependencies {
+				dp, ok := bySource[dep.Package]
+				if !ok {
+					findings = append(findings, Finding{
+						Severity:    SeverityCritical,
+						Check:       checkLock,
+						Resource:    fmt.Sprintf("%s/%s", lp.Type, lp.Name),
+						Problem:     fmt.Sprintf("dependency %s (%s) is not installed", dep.Package, dep.Constraints),
+						Remediation: fmt.Sprintf("Install a version of %s that satisfies %q, or allow Crossplane to resolve dependencies.", dep.Package, dep.Constraints),
+					})
+					continue
+				}
+				cs, err := semver.NewConstraint(dep.Constraints)
+				if err != nil {
+					continue
+				}
+				v, err := semver.NewVersion(dp.Version)
+				if err != nil {
+					continue
+				}
+				if !cs.Check(v) {
+					findings = append(findings, Finding{
+						Severity:    SeverityCritical,
+						Check:       checkLock,
+						Resource:    fmt.Sprintf("%s/%s", lp.Type, lp.Name),
+						Problem:     fmt.Sprintf("dependency %s requires %q, but version %s is installed", dep.Package, dep.Constraints, dp.Version),
+						Remediation: fmt.Sprintf("Update %s to a version that satisfies %q.", dep.Package, dep.Constraints),
+					})
+				}
+			}
+		}
+		return findings, nil
+	}}
+}
+
+// CheckWebhookCertificate checks that the webhook TLS server certificate in
+// the supplied Secret exists, and isn't expired or about to expire.
+func CheckWebhookCertificate(namespace, name string, now time.Time) Check {
+	return Check{Name: checkWebhook, Run: func(ctx context.Context, c client.Reader) ([]Finding, error) {
+		res := fmt.Sprintf("Secret/%s/%s", namespace, name)
+		regenerate := fmt.Sprintf("Delete Secret %s in namespace %s, then restart the Crossplane pods to generate a new certificate.", name, namespace)
+
+		s := &corev1.Secret{}
+		err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, s)
+		if kerrors.IsNotFound(err) {
+			return []Finding{{
+				Severity:    SeverityWarning,
+				Check:       checkWebhook,
+				Resource:    res,
+				Problem:     "webhook TLS Secret not found",
+				Remediation: "If webhooks are enabled, restart the Crossplane pods to generate a certificate. Use --namespace if Crossplane isn't installed in crossplane-system.",
+			}}, nil
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, errGetSecret)
+		}
+
+		block, _ := pem.Decode(s.Data[corev1.TLSCertKey])
+		if block == nil {
+			return []Finding{{Severity: SeverityCritical, Check: checkWebhook, Resource: res, Problem: "Secret doesn't contain a PEM encoded certificate", Remediation: regenerate}}, nil
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return []Finding{{Severity: SeverityCritical, Check: checkWebhook, Resource: res, Problem: fmt.Sprintf("cannot parse certificate: %s", err), Remediation: regenerate}}, nil
+		}
+
+		switch {
+		case now.After(cert.NotAfter):
+			return []Finding{{Severity: SeverityCritical, Check: checkWebhook, Resource: res, Problem: fmt.Sprintf("certificate expired at %s", cert.NotAfter.Format(time.RFC3339)), Remediation: regenerate}}, nil
+		case now.Add(certExpiryWarning).After(cert.NotAfter):
+			return []Finding{{Severity: SeverityWarning, Check: checkWebhook, Resource: res, Problem: fmt.Sprintf("certificate expires at %s", cert.NotAfter.Format(time.RFC3339)), Remediation: regenerate}}, nil
+		}
+		return nil, nil
+	}}
+}
+
+// CheckCRDs checks that all CustomResourceDefinitions are established.
+func CheckCRDs() Check {
+	return Check{Name: checkCRDs, Run: func(ctx context.Context, c client.Reader) ([]Finding, error) {
+		l := &extv1.CustomResourceDefinitionList{}
+		if err := c.List(ctx, l); err != nil {
+			return nil, errors.Wrap(err, errListCRDs)
+		}
+		findings := make([]Finding, 0)
+		for _, crd := range l.Items {
+			established := false
+			msg := ""
+			for _, cond := range crd.Status.Conditions {
+				if cond.Type != extv1.Established {
+					continue
+				}
+				established = cond.Status == extv1.ConditionTrue
+				msg = cond.Message
+			}
+			if established {
+				continue
+			}
+			p := "CustomResourceDefinition is not established"
+			if msg != "" {
+				p += ": " + msg
+			}
+			findings = append(findings, Finding{
+				Severity:    SeverityCritical,
+				Check:       checkCRDs,
+				Resource:    "CustomResourceDefinition/" + crd.GetName(),
+				Problem:     p,
+				Remediation: fmt.Sprintf("Run 'kubectl describe crd %s' and check its NamesAccepted condition for conflicts with other CRDs.", crd.GetName()),
+			})
+		}
+		return findings, nil
+	}}
+}
+
+// CheckPaused checks for managed resources, composite resources, and claims
+// whose reconciliation is paused.
+func CheckPaused() Check {
+	return Check{Name: checkPaused, Run: func(ctx context.Context, c client.Reader) ([]Finding, error) {
+		l := &extv1.CustomResourceDefinitionList{}
+		if err := c.List(ctx, l); err != nil {
+			return nil, errors.Wrap(err, errListCRDs)
+		}
+		findings := make([]Finding, 0)
+		for _, crd := range l.Items {
+			if !pausable(crd) {
+				continue
+			}
+			gvk := schema.GroupVersionKind{Group: crd.Spec.Group, Version: storageVersion(crd), Kind: crd.Spec.Names.ListKind}
+			if gvk.Kind == "" {
+				gvk.Kind = crd.Spec.Names.Kind + "List"
+			}
+			ol := &metav1.PartialObjectMetadataList{}
+			ol.SetGroupVersionKind(gvk)
+			if err := c.List(ctx, ol); err != nil {
+				return nil, errors.Wrapf(err, errFmtListResources, crd.GetName())
+			}
+			for i := range ol.Items {
+				o := &ol.Items[i]
+				if !meta.IsPaused(o) {
+					continue
+				}
+				res := crd.Spec.Names.Kind + "/" + o.GetName()
+				if o.GetNamespace() != "" {
+					res = crd.Spec.Names.Kind + "/" + o.GetNamespace() + "/" + o.GetName()
+				}
+				findings = append(findings, Finding{
+					Severity:    SeverityInfo,
+					Check:       checkPaused,
+					Resource:    res,
+					Problem:     "reconciliation is paused",
+					Remediation: fmt.Sprintf("Remove the %s annotation to resume reconciliation, if the pause is no longer needed.", meta.AnnotationKeyReconciliationPaused),
+				})
+			}
+		}
+		return findings, nil
+	}}
+}
+
+func pausable(crd extv1.CustomResourceDefinition) bool {
+	for _, c := range crd.Spec.Names.Categories {
+		if pausableCategories[c] {
+			return true
+		}
+	}
+	return false
+}
+
+func storageVersion(crd extv1.CustomResourceDefinition) string {
+	for _, v := range crd.Spec.Versions {
+		if v.Storage {
+			return v.Name
+		}
+	}
+	return ""
+}
+
+func conditionAdjective(ct xpv1.ConditionType) string {
+	if ct == v1.TypeInstalled {
+		return "installed"
+	}
+	return "healthy"
+}
+
+func describe(c xpv1.Condition) string {
+	switch {
+	case c.Message != "":
+		return ": " + c.Message
+	case c.Reason != "":
+		return ": " + string(c.Reason)
+	}
+	return ""
+}
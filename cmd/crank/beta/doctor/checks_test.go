@@ -0,0 +1,352 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+)
+
+func TestCheckPackages(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	prv := func(conds ...xpv1.Condition) v1.Provider {
+		p := v1.Provider{ObjectMeta: metav1.ObjectMeta{Name: "provider-aws"}}
+		p.SetConditions(conds...)
+		return p
+	}
+	list := func(prvs ...v1.Provider) test.MockListFn {
+		return func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+			if l, ok := obj.(*v1.ProviderList); ok {
+				l.Items = prvs
+			}
+			return nil
+		}
+	}
+
+	type want struct {
+		findings []Finding
+		err      error
+	}
+
+	cases := map[string]struct {
+		reason string
+		client client.Reader
+		want   want
+	}{
+		"ListError": {
+			reason: "We should return any error encountered listing packages.",
+			client: &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+			want: want{
+				err: errors.Wrap(errBoom, errListProviders),
+			},
+		},
+		"Healthy": {
+			reason: "A package that is installed and healthy has no problems.",
+			client: &test.MockClient{MockList: list(prv(v1.Active(), v1.Healthy()))},
+			want: want{
+				findings: []Finding{},
+			},
+		},
+		"NotInstalled": {
+			reason: "A package that isn't installed should only be reported once.",
+			client: &test.MockClient{MockList: list(prv(v1.Inactive(), v1.Unhealthy()))},
+			want: want{
+				findings: []Finding{{
+					Severity:    SeverityCritical,
+					Check:       checkPackages,
+					Resource:    "Provider/provider-aws",
+					Problem:     "package is not installed: " + string(v1.Inactive().Reason),
+					Remediation: "Run 'crossplane beta trace Provider provider-aws' to find out why.",
+				}},
+			},
+		},
+		"Unhealthy": {
+			reason: "A package that is installed but unhealthy should be reported.",
+			client: &test.MockClient{MockList: list(prv(v1.Active(), v1.Unhealthy().WithMessage("boom")))},
+			want: want{
+				findings: []Finding{{
+					Severity:    SeverityCritical,
+					Check:       checkPackages,
+					Resource:    "Provider/provider-aws",
+					Problem:     "package is not healthy: boom",
+					Remediation: "Run 'crossplane beta trace Provider provider-aws' to find out why.",
+				}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := CheckPackages().Run(context.Background(), tc.client)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nRun(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.findings, got); diff != "" {
+				t.Errorf("\n%s\nRun(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCheckLock(t *testing.T) {
+	cfg := v1.Configuration{ObjectMeta: metav1.ObjectMeta{Name: "platform"}}
+	cfg.SetCurrentRevision("platform-abc")
+
+	list := func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+		if l, ok := obj.(*v1.ConfigurationList); ok {
+			l.Items = []v1.Configuration{cfg}
+		}
+		return nil
+	}
+	lock := func(pkgs ...v1beta1.LockPackage) test.MockGetFn {
+		return func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+			obj.(*v1beta1.Lock).Packages = pkgs
+			return nil
+		}
+	}
+	platform := func(constraints string) v1beta1.LockPackage {
+		return v1beta1.LockPackage{
+			Name:   "platform-abc",
+			Type:   v1beta1.ConfigurationPackageType,
+			Source: "xpkg.upbound.io/acme/platform",
+			Dependencies: []v1beta1.Dependency{
+				{Package: "xpkg.upbound.io/upbound/provider-aws", Type: v1beta1.ProviderPackageType, Constraints: constraints},
+			},
+		}
+	}
+	aws := v1beta1.LockPackage{
+		Name:    "provider-aws-def",
+		Type:    v1beta1.ProviderPackageType,
+		Source:  "xpkg.upbound.io/upbound/provider-aws",
+		Version: "v1.0.0",
+	}
+
+	cases := map[string]struct {
+		reason string
+		client client.Reader
+		want   []Finding
+	}{
+		"Satisfied": {
+			reason: "A Lock with every revision and satisfied dependencies has no problems.",
+			client: &test.MockClient{MockList: list, MockGet: lock(platform(">=v1.0.0"), aws)},
+			want:   []Finding{},
+		},
+		"MissingEntryAndDependency": {
+			reason: "We should report revisions missing from the Lock, and dependencies that aren't installed.",
+			client: &test.MockClient{MockList: list, MockGet: lock(v1beta1.LockPackage{Name: "other"}, func() v1beta1.LockPackage {
+				p := platform(">=v1.0.0")
+				p.Name = "platform-old"
+				return p
+			}())},
+			want: []Finding{
+				{
+					Severity:    SeverityWarning,
+					Check:       checkLock,
+					Resource:    "Configuration/platform",
+					Problem:     `current revision "platform-abc" is missing from the package Lock`,
+					Remediation: "Check the Crossplane logs for errors resolving package dependencies. Crossplane adds the revision back to the Lock when it next reconciles it.",
+				},
+				{
+					Severity:    SeverityCritical,
+					Check:       checkLock,
+					Resource:    "Configuration/platform-old",
+					Problem:     "dependency xpkg.upbound.io/upbound/provider-aws (>=v1.0.0) is not installed",
+					Remediation: `Install a version of xpkg.upbound.io/upbound/provider-aws that satisfies ">=v1.0.0", or allow Crossplane to resolve dependencies.`,
+				},
+			},
+		},
+		"UnsatisfiedConstraint": {
+			reason: "We should report dependencies whose installed version doesn't satisfy their constraints.",
+			client: &test.MockClient{MockList: list, MockGet: lock(platform(">=v2.0.0"), aws)},
+			want: []Finding{{
+				Severity:    SeverityCritical,
+				Check:       checkLock,
+				Resource:    "Configuration/platform-abc",
+				Problem:     `dependency xpkg.upbound.io/upbound/provider-aws requires ">=v2.0.0", but version v1.0.0 is installed`,
+				Remediation: `Update xpkg.upbound.io/upbound/provider-aws to a version that satisfies ">=v2.0.0".`,
+			}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := CheckLock().Run(context.Background(), tc.client)
+			if err != nil {
+				t.Fatalf("\n%s\nRun(...): unexpected error: %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nRun(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCheckWebhookCertificate(t *testing.T) {
+	now := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	cert := func(notAfter time.Time) []byte {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tmpl := &x509.Certificate{
+			SerialNumber: big.NewInt(1),
+			Subject:      pkix.Name{CommonName: "crossplane-webhooks"},
+			NotBefore:    now.Add(-24 * time.Hour),
+			NotAfter:     notAfter,
+		}
+		der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	}
+	secret := func(crt []byte) test.MockGetFn {
+		return func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+			obj.(*corev1.Secret).Data = map[string][]byte{corev1.TLSCertKey: crt}
+			return nil
+		}
+	}
+
+	cases := map[string]struct {
+		reason   string
+		client   client.Reader
+		severity []Severity
+	}{
+		"Valid": {
+			reason:   "A certificate that expires well in the future has no problems.",
+			client:   &test.MockClient{MockGet: secret(cert(now.Add(365 * 24 * time.Hour)))},
+			severity: []Severity{},
+		},
+		"ExpiresSoon": {
+			reason:   "A certificate that expires soon should be a warning.",
+			client:   &test.MockClient{MockGet: secret(cert(now.Add(24 * time.Hour)))},
+			severity: []Severity{SeverityWarning},
+		},
+		"Expired": {
+			reason:   "An expired certificate should be critical.",
+			client:   &test.MockClient{MockGet: secret(cert(now.Add(-time.Hour)))},
+			severity: []Severity{SeverityCritical},
+		},
+		"NotPEM": {
+			reason:   "A Secret without a PEM encoded certificate should be critical.",
+			client:   &test.MockClient{MockGet: secret([]byte("nope"))},
+			severity: []Severity{SeverityCritical},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := CheckWebhookCertificate("crossplane-system", "crossplane-tls-server", now).Run(context.Background(), tc.client)
+			if err != nil {
+				t.Fatalf("\n%s\nRun(...): unexpected error: %s", tc.reason, err)
+			}
+			sev := make([]Severity, 0, len(got))
+			for _, f := range got {
+				sev = append(sev, f.Severity)
+			}
+			if diff := cmp.Diff(tc.severity, sev); diff != "" {
+				t.Errorf("\n%s\nRun(...): -want severities, +got severities:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCheckCRDs(t *testing.T) {
+	crd := func(name string, status extv1.ConditionStatus) extv1.CustomResourceDefinition {
+		return extv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: extv1.CustomResourceDefinitionStatus{
+				Conditions: []extv1.CustomResourceDefinitionCondition{{Type: extv1.Established, Status: status, Message: "nope"}},
+			},
+		}
+	}
+	c := &test.MockClient{MockList: func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+		obj.(*extv1.CustomResourceDefinitionList).Items = []extv1.CustomResourceDefinition{
+			crd("good.example.org", extv1.ConditionTrue),
+			crd("bad.example.org", extv1.ConditionFalse),
+		}
+		return nil
+	}}
+
+	want := []Finding{{
+		Severity:    SeverityCritical,
+		Check:       checkCRDs,
+		Resource:    "CustomResourceDefinition/bad.example.org",
+		Problem:     "CustomResourceDefinition is not established: nope",
+		Remediation: "Run 'kubectl describe crd bad.example.org' and check its NamesAccepted condition for conflicts with other CRDs.",
+	}}
+	got, err := CheckCRDs().Run(context.Background(), c)
+	if err != nil {
+		t.Fatalf("Run(...): unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Run(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestDiagnose(t *testing.T) {
+	errBoom := errors.New("boom")
+	found := func(s Severity) Check {
+		return Check{Name: s.String(), Run: func(_ context.Context, _ client.Reader) ([]Finding, error) {
+			return []Finding{{Severity: s, Check: s.String()}}, nil
+		}}
+	}
+	broken := Check{Name: "broken", Run: func(_ context.Context, _ client.Reader) ([]Finding, error) {
+		return nil, errBoom
+	}}
+
+	got := Diagnose(context.Background(), nil, found(SeverityInfo), broken, found(SeverityCritical))
+	want := []Finding{
+		{Severity: SeverityCritical, Check: "CRITICAL"},
+		{Severity: SeverityWarning, Check: "broken", Problem: errors.Wrapf(errBoom, errFmtCheck, "broken").Error(), Remediation: "Make sure you have permission to read the resources this check needs, then run doctor again."},
+		{Severity: SeverityInfo, Check: "INFO"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Diagnose(...): -want, +got:\n%s", diff)
+	}
+
+	b := &bytes.Buffer{}
+	if err := PrintReport(b, got[:1]); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff("1. [CRITICAL] CRITICAL: \n   Remediation: \n", b.String()); diff != "" {
+		t.Errorf("PrintReport(...): -want, +got:\n%s", diff)
+	}
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package doctor contains the doctor command.
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/alecthomas/kong"
+	corev1 "k8s.io/api/core/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+	"github.com/crossplane/crossplane/cmd/crank/output"
+)
+
+const (
+	errInitKubeClient = "cannot init kubeclient"
+	errWriteReport    = "cannot write report"
+	errFmtCheck       = "cannot run %s check"
+	errCritical       = "found critical problems"
+)
+
+// Cmd arguments and flags for the doctor subcommand.
+type Cmd struct {
+	// Flags. Keep them in alphabetical order.
+	Namespace           string        `short:"n" default:"crossplane-system" help:"Namespace Crossplane is installed in."`
+	Timeout             time.Duration `default:"1m" help:"How long to run before timing out."`
+	TLSServerSecretName string        `default:"crossplane-tls-server" help:"Name of the Secret containing Crossplane's webhook TLS server certificate."`
+}
+
+// Help prints out the help for the doctor command.
+func (c *Cmd) Help() string {
+	return `
+This command checks a Crossplane control plane for common problems, and prints
+a report of the problems it finds, most severe first. Each problem includes a
+suggested remediation.
+
+It checks for:
+
+  - Packages that aren't installed or healthy.
+  - Packages that are missing from the package Lock.
+  - Package dependencies that aren't satisfied.
+  - Webhook TLS certificates that have expired, or will soon.
+  - CustomResourceDefinitions that aren't established.
+  - Crossplane resources that are paused using the crossplane.io/paused
+    annotation.
+
+The command exits with a non-zero code if it finds any critical problems.
+
+Examples:
+
+  # Check the control plane for problems.
+  crossplane beta doctor
+
+  # Check a control plane where Crossplane is installed in another namespace.
+  crossplane beta doctor -n crossplane
+`
+}
+
+// Run doctor.
+func (c *Cmd) Run(k *kong.Context, logger logging.Logger, kubeconfig *rest.Config, p *output.Printer) error {
+	logger = logger.WithValues("cmd", "doctor")
+
+	s := runtime.NewScheme()
+	_ = corev1.AddToScheme(s)
+	_ = extv1.AddToScheme(s)
+	_ = v1.AddToScheme(s)
+	_ = v1beta1.AddToScheme(s)
+	kube, err := client.New(kubeconfig, client.Options{Scheme: s})
+	if err != nil {
+		return errors.Wrap(err, errInitKubeClient)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+
+	checks := []Check{
+		CheckPackages(),
+		CheckLock(),
+		CheckWebhookCertificate(c.Namespace, c.TLSServerSecretName, time.Now()),
+		CheckCRDs(),
+		CheckPaused(),
+	}
+
+	findings := Diagnose(ctx, kube, checks...)
+	logger.Debug("Diagnosed control plane", "findings", len(findings))
+
+	if len(findings) == 0 {
+		p.Infof("No problems found.")
+		return nil
+	}
+	if err := PrintReport(k.Stdout, findings); err != nil {
+		return errors.Wrap(err, errWriteReport)
+	}
+	for _, f := range findings {
+		if f.Severity == SeverityCritical {
+			return errors.New(errCritical)
+		}
+	}
+	return nil
+}
+
+// A Severity indicates how urgently a problem should be addressed.
+type Severity int
+
+// Severities, most severe first.
+const (
+	SeverityCritical Severity = iota
+	SeverityWarning
+	SeverityInfo
+)
+
+// String returns the Severity's name.
+func (s Severity) String() string {
+	switch s {
+	case SeverityCritical:
+		return "CRITICAL"
+	case SeverityWarning:
+		return "WARNING"
+	default:
+		return "INFO"
+	}
+}
+
+// A Finding is a problem found by a Check.
+type Finding struct {
+	// Severity of the problem.
+	Severity Severity
+
+	// Check that found the problem.
+	Check string
+
+	// Resource the problem was found in, e.g. Provider/provider-aws.
+	Resource string
+
+	// Problem describes what's wrong.
+	Problem string
+
+	// Remediation suggests how to fix the problem.
+	Remediation string
+}
+
+// A Check diagnoses one kind of problem.
+type Check struct {
+	// Name of the check.
+	Name string
+
+	// Run the check.
+	Run func(ctx context.Context, c client.Reader) ([]Finding, error)
+}
+
+// Diagnose runs the supplied checks, and returns their findings sorted by
+// severity. A check that can't be run is itself reported as a finding.
+func Diagnose(ctx context.Context, c client.Reader, checks ...Check) []Finding {
+	all := make([]Finding, 0)
+	for _, chk := range checks {
+		f, err := chk.Run(ctx, c)
+		if err != nil {
+			all = append(all, Finding{
+				Severity:    SeverityWarning,
+				Check:       chk.Name,
+				Problem:     errors.Wrapf(err, errFmtCheck, chk.Name).Error(),
+				Remediation: "Make sure you have permission to read the resources this check needs, then run doctor again.",
+			})
+			continue
+		}
+		all = append(all, f...)
+	}
+	sort.SliceStable(all, func(i, j int) bool { return all[i].Severity < all[j].Severity })
+	return all
+}
+
+// PrintReport prints the supplied findings. They should already be sorted.
+func PrintReport(w io.Writer, findings []Finding) error {
+	for i, f := range findings {
+		res := ""
+		if f.Resource != "" {
+			res = " " + f.Resource
+		}
+		if _, err := fmt.Fprintf(w, "%d. [%s] %s%s: %s\n   Remediation: %s\n", i+1, f.Severity, f.Check, res, f.Problem, f.Remediation); err != nil {
+			return err
+		}
+	}
+	return nil
+}
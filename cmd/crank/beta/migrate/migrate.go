@@ -0,0 +1,334 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package migrate contains the migrate command.
+package migrate
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/afero"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	kyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1alpha1"
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+	"github.com/crossplane/crossplane/cmd/crank/beta/convert/deploymentruntime"
+	"github.com/crossplane/crossplane/cmd/crank/beta/convert/pipelinecomposition"
+	"github.com/crossplane/crossplane/cmd/crank/output"
+)
+
+const (
+	errFmtFindFiles     = "cannot find YAML files in %q"
+	errFmtReadFile      = "cannot read %q"
+	errFmtWriteFile     = "cannot write %q"
+	errFmtMigrateFile   = "cannot migrate %q"
+	errFmtPendingFiles  = "%d file(s) need to be migrated"
+	errFmtMigrateObject = "cannot migrate %s %q"
+)
+
+// Cmd arguments and flags for the migrate subcommand.
+type Cmd struct {
+	// Arguments.
+	Paths []string `arg:"" type:"path" help:"YAML files, or directories of YAML files, to migrate. Directories are walked recursively."`
+
+	// Flags. Keep them in alphabetical order.
+	Check        bool   `help:"Don't write anything. Instead exit with an error if any manifests need to be migrated."`
+	FunctionName string `default:"function-patch-and-transform" help:"Name of the patch-and-transform Function that migrated Compositions should use."`
+
+	fs afero.Fs
+}
+
+// Help prints out the help for the migrate command.
+func (c *Cmd) Help() string {
+	return `
+This command rewrites Crossplane manifests that use deprecated APIs. It
+currently migrates:
+
+  * Compositions that use native patch-and-transform (mode: Resources) to
+    mode: Pipeline, using function-patch-and-transform.
+  * ControllerConfigs to DeploymentRuntimeConfigs.
+  * Providers and Functions that reference a ControllerConfig to instead
+    reference the DeploymentRuntimeConfig it's migrated to.
+
+Files are rewritten in place. Only files that contain manifests that need to
+be migrated are rewritten. Other manifests in those files are preserved as-is.
+Use --check in CI to fail if any manifests still need to be migrated.
+
+Examples:
+
+  # Migrate all manifests in the apis directory.
+  crossplane beta migrate apis/
+
+  # Fail if any manifests in the apis directory need to be migrated.
+  crossplane beta migrate apis/ --check
+
+  # Migrate Compositions to use a differently named Function.
+  crossplane beta migrate composition.yaml --function-name=crossplane-contrib-function-patch-and-transform
+`
+}
+
+// AfterApply implements kong.AfterApply.
+func (c *Cmd) AfterApply() error {
+	c.fs = afero.NewOsFs()
+	return nil
+}
+
+// Run migrate.
+func (c *Cmd) Run(p *output.Printer) error {
+	files := make([]string, 0)
+	for _, path := range c.Paths {
+		f, err := findYAMLFiles(c.fs, path)
+		if err != nil {
+			return errors.Wrapf(err, errFmtFindFiles, path)
+		}
+		files = append(files, f...)
+	}
+
+	pending := 0
+	for _, f := range files {
+		data, err := afero.ReadFile(c.fs, f)
+		if err != nil {
+			return errors.Wrapf(err, errFmtReadFile, f)
+		}
+		r, err := Migrate(data, c.FunctionName)
+		if err != nil {
+			return errors.Wrapf(err, errFmtMigrateFile, f)
+		}
+		for _, w := range r.Warnings {
+			p.Warnf("%s: %s", f, w)
+		}
+		if len(r.Migrated) == 0 {
+			continue
+		}
+		if c.Check {
+			p.Infof("%s: needs migration: %s", f, strings.Join(r.Migrated, ", "))
+			pending++
+			continue
+		}
+		info, err := c.fs.Stat(f)
+		if err != nil {
+			return errors.Wrapf(err, errFmtWriteFile, f)
+		}
+		if err := afero.WriteFile(c.fs, f, r.Data, info.Mode()); err != nil {
+			return errors.Wrapf(err, errFmtWriteFile, f)
+		}
+		p.Infof("%s: migrated %s", f, strings.Join(r.Migrated, ", "))
+	}
+
+	if pending > 0 {
+		return errors.Errorf(errFmtPendingFiles, pending)
+	}
+	return nil
+}
+
+// A Result of migrating a YAML stream.
+type Result struct {
+	// Data is the migrated YAML stream.
+	Data []byte
+
+	// Migrated describes each manifest that was migrated, e.g. "Composition
+	// example". It's empty if nothing was migrated.
+	Migrated []string
+
+	// Warnings about things that couldn't be migrated faithfully.
+	Warnings []string
+}
+
+// Migrate the manifests in the supplied YAML stream. Compositions are
+// migrated to use the supplied patch-and-transform Function. Manifests that
+// don't need to be migrated are returned byte-for-byte.
+func Migrate(data []byte, functionName string) (Result, error) { //nolint:gocyclo // Only a touch over.
+	r := Result{}
+	docs := make([][]byte, 0)
+
+	yr := kyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(data)))
+	for {
+		doc, err := yr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return Result{}, errors.Wrap(err, "cannot read YAML document")
+		}
+		doc = bytes.TrimPrefix(doc, []byte("---\n"))
+
+		tm := &metav1.PartialObjectMetadata{}
+		if err := yaml.Unmarshal(doc, tm); err != nil {
+			return Result{}, errors.Wrap(err, "cannot parse YAML document")
+		}
+
+		switch tm.GroupVersionKind() {
+		case v1.CompositionGroupVersionKind:
+			comp := &v1.Composition{}
+			if err := yaml.Unmarshal(doc, comp); err != nil {
+				return Result{}, errors.Wrapf(err, errFmtMigrateObject, tm.Kind, tm.GetName())
+			}
+			if comp.Spec.Mode != nil && *comp.Spec.Mode == v1.CompositionModePipeline {
+				break
+			}
+			pc, err := pipelinecomposition.Convert(comp, functionName)
+			if err != nil {
+				return Result{}, errors.Wrapf(err, errFmtMigrateObject, tm.Kind, tm.GetName())
+			}
+			if doc, err = encode(pc); err != nil {
+				return Result{}, errors.Wrapf(err, errFmtMigrateObject, tm.Kind, tm.GetName())
+			}
+			r.Migrated = append(r.Migrated, fmt.Sprintf("%s %s", tm.Kind, tm.GetName()))
+
+		case v1alpha1.ControllerConfigGroupVersionKind:
+			drcs, warns, err := deploymentruntime.Convert(doc)
+			if err != nil {
+				return Result{}, errors.Wrapf(err, errFmtMigrateObject, tm.Kind, tm.GetName())
+			}
+			converted := make([][]byte, 0, len(drcs))
+			for _, drc := range drcs {
+				b, err := encode(drc)
+				if err != nil {
+					return Result{}, errors.Wrapf(err, errFmtMigrateObject, tm.Kind, tm.GetName())
+				}
+				converted = append(converted, b)
+			}
+			doc = bytes.Join(converted, []byte("---\n"))
+			r.Warnings = append(r.Warnings, warns...)
+			r.Migrated = append(r.Migrated, fmt.Sprintf("%s %s", tm.Kind, tm.GetName()))
+
+		case pkgv1.ProviderGroupVersionKind, v1beta1.FunctionGroupVersionKind:
+			u := &unstructured.Unstructured{}
+			if err := yaml.Unmarshal(doc, &u.Object); err != nil {
+				return Result{}, errors.Wrapf(err, errFmtMigrateObject, tm.Kind, tm.GetName())
+			}
+			if !migrateRuntimeConfigRef(u) {
+				break
+			}
+			if doc, err = encode(u); err != nil {
+				return Result{}, errors.Wrapf(err, errFmtMigrateObject, tm.Kind, tm.GetName())
+			}
+			r.Migrated = append(r.Migrated, fmt.Sprintf("%s %s", tm.Kind, tm.GetName()))
+		}
+
+		docs = append(docs, doc)
+	}
+
+	if len(r.Migrated) == 0 {
+		r.Data = data
+		return r, nil
+	}
+
+	out := &bytes.Buffer{}
+	for _, doc := range docs {
+		out.WriteString("---\n")
+		out.Write(doc)
+		if !bytes.HasSuffix(doc, []byte("\n")) {
+			out.WriteString("\n")
+		}
+	}
+	r.Data = out.Bytes()
+	return r, nil
+}
+
+// migrateRuntimeConfigRef replaces the supplied package's ControllerConfig
+// reference with a reference to the DeploymentRuntimeConfig of the same name,
+// which is what the ControllerConfig is migrated to. It returns false if the
+// package doesn't reference a ControllerConfig.
+func migrateRuntimeConfigRef(u *unstructured.Unstructured) bool {
+	name, found, _ := unstructured.NestedString(u.Object, "spec", "controllerConfigRef", "name")
+	if !found {
+		return false
+	}
+	unstructured.RemoveNestedField(u.Object, "spec", "controllerConfigRef")
+	_ = unstructured.SetNestedStringMap(u.Object, map[string]string{
+		"apiVersion": v1beta1.DeploymentRuntimeConfigGroupVersionKind.GroupVersion().String(),
+		"kind":       v1beta1.DeploymentRuntimeConfigKind,
+		"name":       name,
+	}, "spec", "runtimeConfigRef")
+	return true
+}
+
+// encode the supplied object as YAML, omitting fields that are meaningless
+// in a manifest.
+func encode(o runtime.Object) ([]byte, error) {
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(o)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot convert object to unstructured")
+	}
+	removeCreationTimestamps(u)
+	unstructured.RemoveNestedField(u, "status")
+	b, err := yaml.Marshal(u)
+	return b, errors.Wrap(err, "cannot marshal object to YAML")
+}
+
+// removeCreationTimestamps removes the creation timestamp from the supplied
+// object's metadata, and from any nested metadata (e.g. a pod template's).
+func removeCreationTimestamps(o map[string]any) {
+	for k, v := range o {
+		switch val := v.(type) {
+		case map[string]any:
+			if k == "metadata" {
+				delete(val, "creationTimestamp")
+			}
+			removeCreationTimestamps(val)
+		case []any:
+			for _, e := range val {
+				if m, ok := e.(map[string]any); ok {
+					removeCreationTimestamps(m)
+				}
+			}
+		}
+	}
+}
+
+// findYAMLFiles returns the supplied path if it's a file, or all YAML files
+// under it (sorted by path) if it's a directory.
+func findYAMLFiles(fs afero.Fs, path string) ([]string, error) {
+	info, err := fs.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	files := make([]string, 0)
+	err = afero.Walk(fs, path, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		if ext := filepath.Ext(p); ext == ".yaml" || ext == ".yml" {
+			files = append(files, p)
+		}
+		return nil
+	})
+	sort.Strings(files)
+	return files, err
+}
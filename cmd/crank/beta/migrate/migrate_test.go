@@ -0,0 +1,337 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrate
+
+import (
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/crossplane/cmd/crank/output"
+)
+
+const (
+	configMap = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cool-map
+data:
+  key: value
+`
+
+	resourcesComposition = `apiVersion: apiextensions.crossplane.io/v1
+kind: Composition
+metadata:
+  name: example
+spec:
+  compositeTypeRef:
+    apiVersion: example.org/v1
+    kind: XDatabase
+  resources:
+  - name: bucket
+    base:
+      apiVersion: s3.aws.upbound.io/v1beta1
+      kind: Bucket
+      spec:
+        forProvider:
+          region: us-east-2
+`
+
+	pipelineComposition = `apiVersion: apiextensions.crossplane.io/v1
+kind: Composition
+metadata:
+  name: example
+spec:
+  compositeTypeRef:
+    apiVersion: example.org/v1
+    kind: XDatabase
+  mode: Pipeline
+  pipeline:
+  - step: patch-and-transform
+    functionRef:
+      name: function-patch-and-transform
+`
+
+	controllerConfig = `apiVersion: pkg.crossplane.io/v1alpha1
+kind: ControllerConfig
+metadata:
+  name: debug
+  creationTimestamp: null
+spec:
+  args:
+  - --debug
+`
+
+	provider = `apiVersion: pkg.crossplane.io/v1
+kind: Provider
+metadata:
+  name: provider-aws
+spec:
+  package: xpkg.upbound.io/upbound/provider-aws:v1.0.0
+  controllerConfigRef:
+    name: debug
+`
+)
+
+func TestMigrate(t *testing.T) {
+	type args struct {
+		data         string
+		functionName string
+	}
+	type want struct {
+		data     string
+		migrated []string
+		err      error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NothingToMigrate": {
+			reason: "A YAML stream that doesn't need to be migrated should be returned byte-for-byte.",
+			args: args{
+				data: "# A comment.\n" + configMap + "---\n" + pipelineComposition,
+			},
+			want: want{
+				data: "# A comment.\n" + configMap + "---\n" + pipelineComposition,
+			},
+		},
+		"ResourcesComposition": {
+			reason: "A Composition that uses native patch-and-transform should be migrated to a function pipeline.",
+			args: args{
+				data:         resourcesComposition,
+				functionName: "function-patch-and-transform",
+			},
+			want: want{
+				data: `---
+apiVersion: apiextensions.crossplane.io/v1
+kind: Composition
+metadata:
+  name: example
+spec:
+  compositeTypeRef:
+    apiVersion: example.org/v1
+    kind: XDatabase
+  mode: Pipeline
+  pipeline:
+  - functionRef:
+      name: function-patch-and-transform
+    input:
+      apiVersion: pt.fn.crossplane.io/v1beta1
+      environment: null
+      kind: Resources
+      patchSets: []
+      resources:
+      - base:
+          apiVersion: s3.aws.upbound.io/v1beta1
+          kind: Bucket
+          spec:
+            forProvider:
+              region: us-east-2
+        name: bucket
+    step: patch-and-transform
+`,
+				migrated: []string{"Composition example"},
+			},
+		},
+		"ControllerConfig": {
+			reason: "A ControllerConfig should be migrated to a DeploymentRuntimeConfig, preserving other documents.",
+			args: args{
+				data: configMap + "---\n" + controllerConfig,
+			},
+			want: want{
+				data: "---\n" + configMap + `---
+apiVersion: pkg.crossplane.io/v1beta1
+kind: DeploymentRuntimeConfig
+metadata:
+  name: debug
+spec:
+  deploymentTemplate:
+    spec:
+      selector: {}
+      strategy: {}
+      template:
+        metadata: {}
+        spec:
+          containers:
+          - args:
+            - --debug
+            name: package-runtime
+            resources: {}
+`,
+				migrated: []string{"ControllerConfig debug"},
+			},
+		},
+		"Provider": {
+			reason: "A Provider that references a ControllerConfig should instead reference the DeploymentRuntimeConfig it's migrated to.",
+			args: args{
+				data: provider,
+			},
+			want: want{
+				data: `---
+apiVersion: pkg.crossplane.io/v1
+kind: Provider
+metadata:
+  name: provider-aws
+spec:
+  package: xpkg.upbound.io/upbound/provider-aws:v1.0.0
+  runtimeConfigRef:
+    apiVersion: pkg.crossplane.io/v1beta1
+    kind: DeploymentRuntimeConfig
+    name: debug
+`,
+				migrated: []string{"Provider provider-aws"},
+			},
+		},
+		"InvalidYAML": {
+			reason: "We should return an error if the YAML stream can't be parsed.",
+			args: args{
+				data: "apiVersion: [",
+			},
+			want: want{
+				err: errors.New("cannot parse YAML document"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, err := Migrate([]byte(tc.args.data), tc.args.functionName)
+			if diff := cmp.Diff(tc.want.err != nil, err != nil); diff != "" {
+				t.Fatalf("\n%s\nMigrate(...): -want error, +got error:\n%s\n%v", tc.reason, diff, err)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.data, string(r.Data)); diff != "" {
+				t.Errorf("\n%s\nMigrate(...): -want data, +got data:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.migrated, r.Migrated); diff != "" {
+				t.Errorf("\n%s\nMigrate(...): -want migrated, +got migrated:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	type args struct {
+		files map[string]string
+		check bool
+	}
+	type want struct {
+		files map[string]string
+		err   error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"CheckPending": {
+			reason: "In check mode we should return an error, and write nothing, if any files need to be migrated.",
+			args: args{
+				files: map[string]string{
+					"apis/provider.yaml": provider,
+					"apis/map.yaml":      configMap,
+				},
+				check: true,
+			},
+			want: want{
+				files: map[string]string{
+					"apis/provider.yaml": provider,
+					"apis/map.yaml":      configMap,
+				},
+				err: errors.Errorf(errFmtPendingFiles, 1),
+			},
+		},
+		"CheckNothingPending": {
+			reason: "In check mode we should succeed if no files need to be migrated.",
+			args: args{
+				files: map[string]string{
+					"apis/map.yaml": configMap,
+				},
+				check: true,
+			},
+			want: want{
+				files: map[string]string{
+					"apis/map.yaml": configMap,
+				},
+			},
+		},
+		"Migrate": {
+			reason: "We should rewrite files that need to be migrated, and leave others alone.",
+			args: args{
+				files: map[string]string{
+					"apis/sub/provider.yml": provider,
+					"apis/map.yaml":         configMap,
+					"apis/README.md":        "provider",
+				},
+			},
+			want: want{
+				files: map[string]string{
+					"apis/sub/provider.yml": `---
+apiVersion: pkg.crossplane.io/v1
+kind: Provider
+metadata:
+  name: provider-aws
+spec:
+  package: xpkg.upbound.io/upbound/provider-aws:v1.0.0
+  runtimeConfigRef:
+    apiVersion: pkg.crossplane.io/v1beta1
+    kind: DeploymentRuntimeConfig
+    name: debug
+`,
+					"apis/map.yaml":  configMap,
+					"apis/README.md": "provider",
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			for path, data := range tc.args.files {
+				if err := afero.WriteFile(fs, path, []byte(data), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			c := &Cmd{Paths: []string{"apis"}, Check: tc.args.check, FunctionName: "function-patch-and-transform", fs: fs}
+			err := c.Run(output.NewPrinter(output.FormatText, "crossplane", io.Discard, io.Discard))
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nRun(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			for path, want := range tc.want.files {
+				got, err := afero.ReadFile(fs, path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if diff := cmp.Diff(want, string(got)); diff != "" {
+					t.Errorf("\n%s\nRun(...): -want %s, +got %s:\n%s", tc.reason, path, path, diff)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package reconcile contains the reconcile command.
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/crossplane/crossplane/cmd/crank/output"
+	"github.com/crossplane/crossplane/internal/controller/apiextensions/composite"
+)
+
+const (
+	errInitKubeClient = "cannot init kubeclient"
+	errParseSelector  = "cannot parse label selector"
+	errFmtGetKind     = "cannot determine the kind of resource %q"
+	errFmtList        = "cannot list %s"
+	errFmtPatch       = "cannot annotate %s %q"
+)
+
+// Cmd arguments and flags for the reconcile subcommand.
+type Cmd struct {
+	// Arguments.
+	Resource string `arg:"" predictor:"xr-kind" help:"Kind of resources to reconcile, accepts the 'TYPE[.GROUP]' format."`
+	Name     string `arg:"" optional:"" help:"Name of the resource to reconcile. All resources of the kind are reconciled if omitted."`
+
+	// Flags. Keep them in alphabetical order.
+	Namespace string        `short:"n" help:"Only reconcile resources in this namespace. Resources in all namespaces are reconciled if omitted."`
+	Selector  string        `short:"l" help:"Only reconcile resources matching this label selector."`
+	Timeout   time.Duration `help:"How long to run before timing out." default:"1m"`
+}
+
+// Help prints out the help for the reconcile command.
+func (c *Cmd) Help() string {
+	return `
+This command triggers immediate reconciliation of Crossplane resources, rather
+than waiting for them to be polled. This is useful after fixing something a
+resource depends on, like provider credentials.
+
+It works by setting the crossplane.io/reconcile-now annotation to the current
+time. Any change to a resource's annotations triggers a reconcile, so this
+works for composite resources, claims, and managed resources.
+
+Examples:
+
+  # Reconcile all XMyKind composite resources.
+  crossplane beta reconcile xmykind.example.org
+
+  # Reconcile the XMyKind composite resource named my-xr.
+  crossplane beta reconcile xmykind.example.org my-xr
+
+  # Reconcile all MyKind claims in the my-ns namespace labelled team=a.
+  crossplane beta reconcile mykind.example.org -n my-ns -l team=a
+`
+}
+
+// Run reconcile.
+func (c *Cmd) Run(p *output.Printer, logger logging.Logger, kubeconfig *rest.Config) error {
+	logger = logger.WithValues("cmd", "reconcile")
+
+	sel, err := labels.Parse(c.Selector)
+	if err != nil {
+		return errors.Wrap(err, errParseSelector)
+	}
+
+	kube, err := client.New(kubeconfig, client.Options{Scheme: scheme.Scheme})
+	if err != nil {
+		return errors.Wrap(err, errInitKubeClient)
+	}
+	logger.Debug("Built client")
+
+	_, gr := schema.ParseResourceArg(c.Resource)
+	gvk, err := kube.RESTMapper().KindFor(gr.WithVersion(""))
+	if err != nil {
+		return errors.Wrapf(err, errFmtGetKind, c.Resource)
+	}
+	logger.Debug("Found kind", "gvk", gvk)
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+
+	names, err := RequestReconcile(ctx, kube, gvk, Filter{Name: c.Name, Namespace: c.Namespace, Selector: sel}, time.Now())
+	for _, n := range names {
+		p.Infof("Requested reconciliation of %s %s", gvk.Kind, n)
+	}
+	if err != nil {
+		return err
+	}
+	p.Infof("Requested reconciliation of %d %s resource(s)", len(names), gvk.Kind)
+	return nil
+}
+
+// A Filter selects the resources to reconcile.
+type Filter struct {
+	// Name of a single resource to reconcile. All resources matching the
+	// other filters are reconciled if empty.
+	Name string
+
+	// Namespace to reconcile resources in. All namespaces if empty.
+	Namespace string
+
+	// Selector resources must match to be reconciled.
+	Selector labels.Selector
+}
+
+// RequestReconcile requests immediate reconciliation of all resources of the
+// supplied kind that pass the supplied filter, by setting their reconcile-now
+// annotation to the supplied time. It returns the names of the resources it
+// annotated, in namespace/name form for namespaced resources.
+func RequestReconcile(ctx context.Context, c client.Client, gvk schema.GroupVersionKind, f Filter, now time.Time) ([]string, error) {
+	objs := make([]unstructured.Unstructured, 0)
+
+	if f.Name != "" {
+		u := unstructured.Unstructured{}
+		u.SetGroupVersionKind(gvk)
+		u.SetName(f.Name)
+		u.SetNamespace(f.Namespace)
+		objs = append(objs, u)
+	} else {
+		l := &unstructured.UnstructuredList{}
+		l.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		opts := []client.ListOption{client.InNamespace(f.Namespace)}
+		if f.Selector != nil {
+			opts = append(opts, client.MatchingLabelsSelector{Selector: f.Selector})
+		}
+		if err := c.List(ctx, l, opts...); err != nil {
+			return nil, errors.Wrapf(err, errFmtList, gvk.Kind)
+		}
+		objs = append(objs, l.Items...)
+	}
+
+	patch := client.RawPatch(types.MergePatchType, []byte(fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}}}`, composite.AnnotationKeyReconcileNow, now.UTC().Format(time.RFC3339Nano))))
+
+	names := make([]string, 0, len(objs))
+	for i := range objs {
+		o := &objs[i]
+		o.SetGroupVersionKind(gvk)
+		name := types.NamespacedName{Namespace: o.GetNamespace(), Name: o.GetName()}.String()
+		if o.GetNamespace() == "" {
+			name = o.GetName()
+		}
+		if err := c.Patch(ctx, o, patch); err != nil {
+			return names, errors.Wrapf(err, errFmtPatch, gvk.Kind, name)
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconcile
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestRequestReconcile(t *testing.T) {
+	errBoom := errors.New("boom")
+	gvk := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "XDatabase"}
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	list := func(names ...string) test.MockListFn {
+		return test.NewMockListFn(nil, func(obj client.ObjectList) error {
+			l := obj.(*unstructured.UnstructuredList)
+			if l.GetKind() != "XDatabaseList" {
+				return errors.Errorf("listed unexpected kind %q", l.GetKind())
+			}
+			for _, n := range names {
+				u := unstructured.Unstructured{}
+				u.SetName(n)
+				l.Items = append(l.Items, u)
+			}
+			return nil
+		})
+	}
+
+	patch := func(err error) test.MockPatchFn {
+		return func(_ context.Context, obj client.Object, p client.Patch, _ ...client.PatchOption) error {
+			if err != nil {
+				return err
+			}
+			if obj.GetObjectKind().GroupVersionKind() != gvk {
+				return errors.Errorf("patched unexpected kind %q", obj.GetObjectKind().GroupVersionKind())
+			}
+			data, _ := p.Data(obj)
+			want := `{"metadata":{"annotations":{"crossplane.io/reconcile-now":"2024-01-02T03:04:05Z"}}}`
+			if diff := cmp.Diff(want, string(data)); diff != "" {
+				return errors.Errorf("unexpected patch: -want, +got:\n%s", diff)
+			}
+			return nil
+		}
+	}
+
+	type args struct {
+		client client.Client
+		f      Filter
+	}
+	type want struct {
+		names []string
+		err   error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ListError": {
+			reason: "We should return any error encountered listing resources.",
+			args: args{
+				client: &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+				f:      Filter{Selector: labels.Everything()},
+			},
+			want: want{
+				err: errors.Wrapf(errBoom, errFmtList, "XDatabase"),
+			},
+		},
+		"PatchError": {
+			reason: "We should return any error encountered annotating a resource, along with the resources we did annotate.",
+			args: args{
+				client: &test.MockClient{
+					MockList:  list("cool-xr"),
+					MockPatch: patch(errBoom),
+				},
+			},
+			want: want{
+				names: []string{},
+				err:   errors.Wrapf(errBoom, errFmtPatch, "XDatabase", "cool-xr"),
+			},
+		},
+		"AllOfKind": {
+			reason: "We should annotate all listed resources of the kind.",
+			args: args{
+				client: &test.MockClient{
+					MockList:  list("cool-xr", "cooler-xr"),
+					MockPatch: patch(nil),
+				},
+				f: Filter{Selector: labels.SelectorFromSet(labels.Set{"team": "a"})},
+			},
+			want: want{
+				names: []string{"cool-xr", "cooler-xr"},
+			},
+		},
+		"Named": {
+			reason: "We should annotate only the named resource, without listing.",
+			args: args{
+				client: &test.MockClient{
+					MockList:  test.NewMockListFn(errBoom),
+					MockPatch: patch(nil),
+				},
+				f: Filter{Name: "cool-claim", Namespace: "default"},
+			},
+			want: want{
+				names: []string{"default/cool-claim"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			names, err := RequestReconcile(context.Background(), tc.args.client, gvk, tc.args.f, now)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nRequestReconcile(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.names, names); diff != "" {
+				t.Errorf("\n%s\nRequestReconcile(...): -want names, +got names:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -14,26 +14,36 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-// Package render implements composition rendering using composition functions.
+// Package render implements the render command. Rendering itself is
+// implemented by github.com/crossplane/crossplane/pkg/render.
 package render
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/alecthomas/kong"
 	"github.com/spf13/afero"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer/json"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+	ucomposite "github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/cmd/crank/output"
+	"github.com/crossplane/crossplane/internal/controller/apiextensions/composite"
+	"github.com/crossplane/crossplane/pkg/render"
 )
 
 // Cmd arguments and flags for render subcommand.
@@ -46,11 +56,13 @@ type Cmd struct {
 	// Flags. Keep them in alphabetical order.
 	ContextFiles           map[string]string `mapsep:"," help:"Comma-separated context key-value pairs to pass to the Function pipeline. Values must be files containing JSON."`
 	ContextValues          map[string]string `mapsep:"," help:"Comma-separated context key-value pairs to pass to the Function pipeline. Values must be JSON. Keys take precedence over --context-files."`
+	Environments           []string          `placeholder:"DIR" type:"path" help:"Directories of EnvironmentConfigs. Render the XR once in each environment, writing the output to --output-dir and printing a summary of the differences."`
 	IncludeFunctionResults bool              `short:"r" help:"Include informational and warning messages from Functions in the rendered output as resources of kind: Result."`
 	IncludeFullXR          bool              `short:"x" help:"Include a direct copy of the input XR's spec and metadata fields in the rendered output."`
 	ObservedResources      string            `short:"o" placeholder:"PATH" type:"path" help:"A YAML file or directory of YAML files specifying the observed state of composed resources."`
 	ExtraResources         string            `short:"e" placeholder:"PATH" type:"path" help:"A YAML file or directory of YAML files specifying extra resources to pass to the Function pipeline."`
 	IncludeContext         bool              `short:"c" help:"Include the context in the rendered output as a resource of kind: Context."`
+	OutputDir              string            `placeholder:"DIR" type:"path" help:"Directory to write the output of each environment to when rendering with --environments."`
 
 	Timeout time.Duration `help:"How long to run before timing out." default:"1m"`
 
@@ -66,6 +78,11 @@ status of the XR. It doesn't talk to Crossplane. Instead it runs the Composition
 Function pipeline specified by the Composition locally, and uses that to render
 the XR. It only supports Compositions in Pipeline mode.
 
+Pass the current state of composed resources with --observed-resources to
+simulate updating an XR that already exists. Each observed resource must have
+the crossplane.io/composition-resource-name annotation, which Crossplane sets
+on every composed resource.
+
 Composition Functions are pulled and run using Docker by default. You can add
 the following annotations to each Function to change how they're run:
 
@@ -89,6 +106,15 @@ the following annotations to each Function to change how they're run:
     Always pull the Function's package, even if it already exists locally.
 	Other supported values are Never, or IfNotPresent. 
 
+Pass --environments to render the XR once in each of several environments.
+Each environment is a directory of EnvironmentConfigs, named after the
+directory. The EnvironmentConfigs are passed to the Function pipeline as extra
+resources, and their merged data is passed as the
+apiextensions.crossplane.io/environment context key. The output of each
+environment is written to a directory under --output-dir, with one file per
+resource, so environments can be compared using a tool like diff -r. A summary
+of how each environment differs from the first is printed to stdout.
+
 Use the standard DOCKER_HOST, DOCKER_API_VERSION, DOCKER_CERT_PATH, and
 DOCKER_TLS_VERIFY environment variables to configure how this command connects
 to the Docker daemon.
@@ -102,6 +128,11 @@ Examples:
   crossplane beta render xr.yaml composition.yaml functions.yaml \
     --observed-resources=existing-observed-resources.yaml
 
+  # Simulate updating an XR using a directory of observed composed resources,
+  # for example one exported from a live control plane.
+  crossplane beta render xr.yaml composition.yaml functions.yaml \
+    --observed-resources=observed/
+
   # Pass context values to the Function pipeline.
   crossplane beta render xr.yaml composition.yaml functions.yaml \
     --context-values=apiextensions.crossplane.io/environment='{"key": "value"}'
@@ -109,6 +140,11 @@ Examples:
   # Pass extra resources Functions in the pipeline can request.
   crossplane beta render xr.yaml composition.yaml functions.yaml \
 	--extra-resources=extra-resources.yaml
+
+  # Render the XR in the dev, staging, and prod environments, and summarize
+  # how the environments differ.
+  crossplane beta render xr.yaml composition.yaml functions.yaml \
+    --environments=envs/dev,envs/staging,envs/prod --output-dir=rendered/
 `
 }
 
@@ -119,22 +155,22 @@ func (c *Cmd) AfterApply() error {
 }
 
 // Run render.
-func (c *Cmd) Run(k *kong.Context, _ logging.Logger) error { //nolint:gocyclo // Only a touch over.
-	xr, err := LoadCompositeResource(c.fs, c.CompositeResource)
+func (c *Cmd) Run(k *kong.Context, _ logging.Logger, p *output.Printer) error { //nolint:gocyclo // Only a touch over.
+	xr, err := render.LoadCompositeResource(c.fs, c.CompositeResource)
 	if err != nil {
 		return errors.Wrapf(err, "cannot load composite resource from %q", c.CompositeResource)
 	}
 
 	// TODO(negz): Should we do some simple validations, e.g. that the
 	// Composition's compositeTypeRef matches the XR's type?
-	comp, err := LoadComposition(c.fs, c.Composition)
+	comp, err := render.LoadComposition(c.fs, c.Composition)
 	if err != nil {
 		return errors.Wrapf(err, "cannot load Composition from %q", c.Composition)
 	}
 
 	warns, errs := comp.Validate()
 	for _, warn := range warns {
-		fmt.Fprintf(k.Stderr, "WARN(composition): %s\n", warn)
+		p.Warnf("Composition %q: %s", comp.GetName(), warn)
 	}
 	if len(errs) > 0 {
 		return errors.Wrapf(errs.ToAggregate(), "invalid Composition %q", comp.GetName())
@@ -144,14 +180,14 @@ func (c *Cmd) Run(k *kong.Context, _ logging.Logger) error { //nolint:gocyclo //
 		return errors.Errorf("render only supports Composition Function pipelines: Composition %q must use spec.mode: Pipeline", comp.GetName())
 	}
 
-	fns, err := LoadFunctions(c.fs, c.Functions)
+	fns, err := render.LoadFunctions(c.fs, c.Functions)
 	if err != nil {
 		return errors.Wrapf(err, "cannot load functions from %q", c.Functions)
 	}
 
 	ors := []composed.Unstructured{}
 	if c.ObservedResources != "" {
-		ors, err = LoadObservedResources(c.fs, c.ObservedResources)
+		ors, err = render.LoadObservedResources(c.fs, c.ObservedResources)
 		if err != nil {
 			return errors.Wrapf(err, "cannot load observed composed resources from %q", c.ObservedResources)
 		}
@@ -159,7 +195,7 @@ func (c *Cmd) Run(k *kong.Context, _ logging.Logger) error { //nolint:gocyclo //
 
 	ers := []unstructured.Unstructured{}
 	if c.ExtraResources != "" {
-		ers, err = LoadExtraResources(c.fs, c.ExtraResources)
+		ers, err = render.LoadExtraResources(c.fs, c.ExtraResources)
 		if err != nil {
 			return errors.Wrapf(err, "cannot load extra resources from %q", c.ExtraResources)
 		}
@@ -180,14 +216,20 @@ func (c *Cmd) Run(k *kong.Context, _ logging.Logger) error { //nolint:gocyclo //
 	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
 	defer cancel()
 
-	out, err := Render(ctx, Inputs{
+	in := render.Inputs{
 		CompositeResource: xr,
 		Composition:       comp,
 		Functions:         fns,
 		ObservedResources: ors,
 		ExtraResources:    ers,
 		Context:           fctx,
-	})
+	}
+
+	if len(c.Environments) > 0 {
+		return c.renderEnvironments(ctx, k.Stdout, p, in)
+	}
+
+	out, err := render.Render(ctx, in)
 	if err != nil {
 		return errors.Wrap(err, "cannot render composite resource")
 	}
@@ -202,22 +244,8 @@ func (c *Cmd) Run(k *kong.Context, _ logging.Logger) error { //nolint:gocyclo //
 	s := json.NewSerializerWithOptions(json.DefaultMetaFactory, nil, nil, json.SerializerOptions{Yaml: true})
 
 	if c.IncludeFullXR {
-		xrSpec, err := fieldpath.Pave(xr.Object).GetValue("spec")
-		if err != nil {
-			return errors.Wrapf(err, "cannot get composite resource spec")
-		}
-
-		if err := fieldpath.Pave(out.CompositeResource.Object).SetValue("spec", xrSpec); err != nil {
-			return errors.Wrapf(err, "cannot set composite resource spec")
-		}
-
-		xrMeta, err := fieldpath.Pave(xr.Object).GetValue("metadata")
-		if err != nil {
-			return errors.Wrapf(err, "cannot get composite resource metadata")
-		}
-
-		if err := fieldpath.Pave(out.CompositeResource.Object).SetValue("metadata", xrMeta); err != nil {
-			return errors.Wrapf(err, "cannot set composite resource metadata")
+		if err := includeFullXR(xr, out.CompositeResource); err != nil {
+			return err
 		}
 	}
 
@@ -229,7 +257,7 @@ func (c *Cmd) Run(k *kong.Context, _ logging.Logger) error { //nolint:gocyclo //
 	for i := range out.ComposedResources {
 		fmt.Fprintln(k.Stdout, "---")
 		if err := s.Encode(&out.ComposedResources[i], os.Stdout); err != nil {
-			return errors.Wrapf(err, "cannot marshal composed resource %q to YAML", out.ComposedResources[i].GetAnnotations()[AnnotationKeyCompositionResourceName])
+			return errors.Wrapf(err, "cannot marshal composed resource %q to YAML", out.ComposedResources[i].GetAnnotations()[render.AnnotationKeyCompositionResourceName])
 		}
 	}
 
@@ -251,3 +279,146 @@ func (c *Cmd) Run(k *kong.Context, _ logging.Logger) error { //nolint:gocyclo //
 
 	return nil
 }
+
+// renderEnvironments renders the supplied inputs once in each environment,
+// writes the output of each to a directory under the output directory, and
+// prints a summary of how the environments differ.
+func (c *Cmd) renderEnvironments(ctx context.Context, w io.Writer, p *output.Printer, in render.Inputs) error { //nolint:gocyclo // Only a touch over.
+	if c.OutputDir == "" {
+		return errors.New("--output-dir is required when rendering with --environments")
+	}
+
+	outs := make([]render.EnvironmentOutputs, 0, len(c.Environments))
+	for _, dir := range c.Environments {
+		env, err := render.LoadEnvironment(c.fs, dir)
+		if err != nil {
+			return errors.Wrapf(err, "cannot load environment from %q", dir)
+		}
+		envCtx, err := env.Context()
+		if err != nil {
+			return errors.Wrapf(err, "cannot build environment %q", env.Name)
+		}
+
+		ein := in
+		ein.ExtraResources = append(append([]unstructured.Unstructured{}, in.ExtraResources...), env.Resources...)
+		ein.Context = map[string][]byte{composite.FunctionContextKeyEnvironment: envCtx}
+		for k, v := range in.Context {
+			ein.Context[k] = v
+		}
+
+		out, err := render.Render(ctx, ein)
+		if err != nil {
+			return errors.Wrapf(err, "cannot render composite resource in environment %q", env.Name)
+		}
+		if c.IncludeFullXR {
+			if err := includeFullXR(in.CompositeResource, out.CompositeResource); err != nil {
+				return err
+			}
+		}
+
+		dir := filepath.Join(c.OutputDir, env.Name)
+		if err := c.writeOutputs(dir, out); err != nil {
+			return errors.Wrapf(err, "cannot write output of environment %q", env.Name)
+		}
+		p.Infof("Rendered environment %q to %s", env.Name, dir)
+
+		outs = append(outs, render.EnvironmentOutputs{Environment: env.Name, Outputs: out})
+	}
+
+	diffs := render.CompareEnvironments(outs)
+	if len(diffs) == 0 {
+		_, err := fmt.Fprintln(w, "All environments rendered identically.")
+		return errors.Wrap(err, "cannot write summary")
+	}
+	if _, err := fmt.Fprintf(w, "Compared to environment %q:\n", outs[0].Environment); err != nil {
+		return errors.Wrap(err, "cannot write summary")
+	}
+	for _, d := range diffs {
+		what := "composite resource"
+		if d.Resource != "" {
+			what = fmt.Sprintf("composed resource %q", d.Resource)
+		}
+		var line string
+		switch {
+		case d.Missing:
+			line = fmt.Sprintf("  %s: %s isn't rendered", d.Environment, what)
+		case d.Added:
+			line = fmt.Sprintf("  %s: %s is only rendered in this environment", d.Environment, what)
+		default:
+			line = fmt.Sprintf("  %s: %s differs: %s", d.Environment, what, strings.Join(d.Fields, ", "))
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return errors.Wrap(err, "cannot write summary")
+		}
+	}
+	return nil
+}
+
+// writeOutputs writes the supplied outputs to the supplied directory, with one
+// file per resource.
+func (c *Cmd) writeOutputs(dir string, out render.Outputs) error {
+	if err := c.fs.MkdirAll(filepath.Join(dir, "composed"), 0o755); err != nil {
+		return errors.Wrap(err, "cannot create output directory")
+	}
+
+	if err := writeYAML(c.fs, filepath.Join(dir, "xr.yaml"), out.CompositeResource); err != nil {
+		return err
+	}
+	for i := range out.ComposedResources {
+		name := out.ComposedResources[i].GetAnnotations()[render.AnnotationKeyCompositionResourceName]
+		if err := writeYAML(c.fs, filepath.Join(dir, "composed", name+".yaml"), &out.ComposedResources[i]); err != nil {
+			return err
+		}
+	}
+	if c.IncludeFunctionResults && len(out.Results) > 0 {
+		rs := make([]runtime.Object, len(out.Results))
+		for i := range out.Results {
+			rs[i] = &out.Results[i]
+		}
+		if err := writeYAML(c.fs, filepath.Join(dir, "results.yaml"), rs...); err != nil {
+			return err
+		}
+	}
+	if c.IncludeContext {
+		if err := writeYAML(c.fs, filepath.Join(dir, "context.yaml"), out.Context); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeYAML writes the supplied objects to the supplied file as a YAML stream.
+func writeYAML(fs afero.Fs, file string, objs ...runtime.Object) error {
+	s := json.NewSerializerWithOptions(json.DefaultMetaFactory, nil, nil, json.SerializerOptions{Yaml: true})
+	b := &bytes.Buffer{}
+	for _, o := range objs {
+		b.WriteString("---\n")
+		if err := s.Encode(o, b); err != nil {
+			return errors.Wrapf(err, "cannot marshal %q to YAML", file)
+		}
+	}
+	return errors.Wrapf(afero.WriteFile(fs, file, b.Bytes(), 0o644), "cannot write %q", file)
+}
+
+// includeFullXR copies the supplied input XR's spec and metadata to the
+// supplied rendered XR.
+func includeFullXR(xr, rendered *ucomposite.Unstructured) error {
+	xrSpec, err := fieldpath.Pave(xr.Object).GetValue("spec")
+	if err != nil {
+		return errors.Wrapf(err, "cannot get composite resource spec")
+	}
+
+	if err := fieldpath.Pave(rendered.Object).SetValue("spec", xrSpec); err != nil {
+		return errors.Wrapf(err, "cannot set composite resource spec")
+	}
+
+	xrMeta, err := fieldpath.Pave(xr.Object).GetValue("metadata")
+	if err != nil {
+		return errors.Wrapf(err, "cannot get composite resource metadata")
+	}
+
+	if err := fieldpath.Pave(rendered.Object).SetValue("metadata", xrMeta); err != nil {
+		return errors.Wrapf(err, "cannot set composite resource metadata")
+	}
+	return nil
+}
@@ -30,20 +30,22 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/cli-runtime/pkg/printers"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"k8s.io/metrics/pkg/client/clientset/versioned"
-	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/crossplane/crossplane/cmd/crank/output"
 )
 
 const (
-	errKubeConfig             = "failed to get kubeconfig"
 	errCreateK8sClientset     = "could not create the clientset for Kubernetes"
 	errCreateMetricsClientset = "could not create the clientset for Metrics"
 	errFetchAllPods           = "could not fetch pods"
 	errGetPodMetrics          = "error getting metrics for pod"
 	errPrintingPodsTable      = "error creating pods table"
+	errPrintingPackagesTable  = "error creating packages table"
 	errAddingPodMetrics       = "error adding metrics to pod, check if metrics-server is running or wait until metrics are available for the pod"
 	errWriteHeader            = "cannot write header"
 	errWriteRow               = "cannot write row"
@@ -53,6 +55,7 @@ const (
 type Cmd struct {
 	Summary   bool   `short:"s" name:"summary" help:"Adds summary header for all Crossplane pods."`
 	Namespace string `short:"n" name:"namespace" help:"Show pods from a specific namespace, defaults to crossplane-system." default:"crossplane-system"`
+	GroupBy   string `short:"g" name:"group-by" help:"Group resource usage by pod or by owning package. One of: pod, package." enum:"pod,package" default:"pod"`
 }
 
 // Help returns help instructions for the top command.
@@ -71,17 +74,30 @@ Examples:
 
   # Add summary of resources utilization for all Crossplane pods in the default 'crossplane-system' on top of the results.
   crossplane beta top -s
+
+  # Show resources utilization aggregated by the package (e.g. provider or function) that owns the pods.
+  crossplane beta top -g package
 `
 }
 
 type topMetrics struct {
 	PodType      string
+	PackageName  string
 	PodName      string
 	PodNamespace string
 	CPUUsage     resource.Quantity
 	MemoryUsage  resource.Quantity
 }
 
+// packageMetrics aggregates the resource usage of all pods owned by a package.
+type packageMetrics struct {
+	PackageType string
+	PackageName string
+	Pods        int
+	CPUUsage    resource.Quantity
+	MemoryUsage resource.Quantity
+}
+
 type defaultPrinterRow struct {
 	podType   string
 	namespace string
@@ -101,18 +117,11 @@ func (r *defaultPrinterRow) String() string {
 }
 
 // Run runs the top command.
-func (c *Cmd) Run(k *kong.Context, logger logging.Logger) error { //nolint:gocyclo // TODO:(piotr1215) refactor to use dedicated functions
+func (c *Cmd) Run(k *kong.Context, logger logging.Logger, config *rest.Config, p *output.Printer) error { //nolint:gocyclo // TODO:(piotr1215) refactor to use dedicated functions
 	logger = logger.WithValues("cmd", "top")
 
 	logger.Debug("Tabwriter header created")
 
-	// Build the config from the kubeconfig path
-	config, err := ctrl.GetConfig()
-	if err != nil {
-		return errors.Wrap(err, errKubeConfig)
-	}
-	logger.Debug("Found kubeconfig")
-
 	// Create the clientset for Kubernetes
 	k8sClientset, err := kubernetes.NewForConfig(config)
 	if err != nil {
@@ -139,7 +148,7 @@ func (c *Cmd) Run(k *kong.Context, logger logging.Logger) error { //nolint:gocyc
 	logger.Debug("Fetched all Crossplane pods", "pods", crossplanePods, "namespace", c.Namespace)
 
 	if len(crossplanePods) == 0 {
-		fmt.Println("No Crossplane pods found in the namespace", c.Namespace)
+		p.Infof("No Crossplane pods found in the namespace %s", c.Namespace)
 		return nil
 	}
 
@@ -173,7 +182,15 @@ func (c *Cmd) Run(k *kong.Context, logger logging.Logger) error { //nolint:gocyc
 	if c.Summary {
 		printPodsSummary(k.Stdout, crossplanePods)
 		logger.Debug("Printed pods summary")
-		fmt.Println()
+		fmt.Fprintln(k.Stdout)
+	}
+
+	if c.GroupBy == "package" {
+		if err := printPackagesTable(k.Stdout, aggregateByPackage(crossplanePods)); err != nil {
+			return errors.Wrap(err, errPrintingPackagesTable)
+		}
+		logger.Debug("Printed packages as table")
+		return nil
 	}
 
 	if err := printPodsTable(k.Stdout, crossplanePods); err != nil {
@@ -217,6 +234,56 @@ func printPodsTable(w io.Writer, crossplanePods []topMetrics) error {
 	return tw.Flush()
 }
 
+// aggregateByPackage sums the resource usage of the supplied pods by the
+// package that owns them, sorted by package type and name.
+func aggregateByPackage(pods []topMetrics) []packageMetrics {
+	idx := make(map[string]int)
+	pkgs := make([]packageMetrics, 0)
+	for _, pod := range pods {
+		key := pod.PodType + "/" + pod.PackageName
+		i, ok := idx[key]
+		if !ok {
+			i = len(pkgs)
+			idx[key] = i
+			pkgs = append(pkgs, packageMetrics{PackageType: pod.PodType, PackageName: pod.PackageName})
+		}
+		pkgs[i].Pods++
+		pkgs[i].CPUUsage.Add(pod.CPUUsage)
+		pkgs[i].MemoryUsage.Add(pod.MemoryUsage)
+	}
+
+	sort.Slice(pkgs, func(i, j int) bool {
+		if pkgs[i].PackageType == pkgs[j].PackageType {
+			return pkgs[i].PackageName < pkgs[j].PackageName
+		}
+		return pkgs[i].PackageType < pkgs[j].PackageType
+	})
+	return pkgs
+}
+
+func printPackagesTable(w io.Writer, pkgs []packageMetrics) error {
+	tw := printers.GetNewTabWriter(w)
+	_, err := fmt.Fprintln(tw, strings.Join([]string{"TYPE", "PACKAGE", "PODS", "CPU(cores)", "MEMORY"}, "\t"))
+	if err != nil {
+		return errors.Wrap(err, errWriteHeader)
+	}
+
+	for _, pkg := range pkgs {
+		_, err := fmt.Fprintln(tw, strings.Join([]string{
+			pkg.PackageType,
+			pkg.PackageName,
+			fmt.Sprintf("%d", pkg.Pods),
+			fmt.Sprintf("%vm", pkg.CPUUsage.MilliValue()),
+			fmt.Sprintf("%vMi", pkg.MemoryUsage.Value()/(1024*1024)),
+		}, "\t"))
+		if err != nil {
+			return errors.Wrap(err, errWriteRow)
+		}
+	}
+
+	return tw.Flush()
+}
+
 func printPodsSummary(w io.Writer, pods []topMetrics) {
 	categoryCounts := make(map[string]int)
 	var totalMemoryUsage, totalCPUUsage resource.Quantity
@@ -250,17 +317,19 @@ func getCrossplanePods(pods []v1.Pod) []topMetrics {
 	for _, pod := range pods {
 		labels := pod.GetLabels()
 
-		var podType string
+		var podType, packageName string
 		isCrossplanePod := false
 		for labelKey, labelValue := range labels {
 			switch {
 			case strings.HasPrefix(labelKey, "pkg.crossplane.io/"):
 				podType = strings.SplitN(labelKey, "/", 2)[1]
 				if podType != "revision" {
+					packageName = labelValue
 					isCrossplanePod = true
 				}
 			case labelKey == "app.kubernetes.io/part-of" && labelValue == "crossplane":
 				podType = "crossplane"
+				packageName = "crossplane"
 				isCrossplanePod = true
 			}
 			if isCrossplanePod {
@@ -271,6 +340,7 @@ func getCrossplanePods(pods []v1.Pod) []topMetrics {
 		if isCrossplanePod {
 			metricsList = append(metricsList, topMetrics{
 				PodType:      podType,
+				PackageName:  packageName,
 				PodName:      pod.Name,
 				PodNamespace: pod.Namespace,
 			})
@@ -49,6 +49,7 @@ func TestGetCrossplanePods(t *testing.T) {
 				topMetrics: []topMetrics{
 					{
 						PodType:      "function",
+						PackageName:  "function-go-templating",
 						PodName:      "function-12345abcd-xyzwv",
 						PodNamespace: "crossplane-system",
 					},
@@ -73,6 +74,7 @@ func TestGetCrossplanePods(t *testing.T) {
 				topMetrics: []topMetrics{
 					{
 						PodType:      "crossplane",
+						PackageName:  "crossplane",
 						PodName:      "crossplane-75575fcf5d-fzwgq",
 						PodNamespace: "crossplane-system",
 					},
@@ -107,11 +109,13 @@ func TestGetCrossplanePods(t *testing.T) {
 				topMetrics: []topMetrics{
 					{
 						PodType:      "function",
+						PackageName:  "function-go-templating",
 						PodName:      "function-go-templating-213wer",
 						PodNamespace: "crossplane-system",
 					},
 					{
 						PodType:      "provider",
+						PackageName:  "provider-azure-storage",
 						PodName:      "provider-azure-storage",
 						PodNamespace: "crossplane-system",
 					},
@@ -135,6 +139,7 @@ func TestGetCrossplanePods(t *testing.T) {
 				topMetrics: []topMetrics{
 					{
 						PodType:      "extension",
+						PackageName:  "new-crossplane-extension",
 						PodName:      "extension-some-feature-12345",
 						PodNamespace: "crossplane-system",
 					},
@@ -336,3 +341,111 @@ func TestCapitalizeFirst(t *testing.T) {
 		})
 	}
 }
+
+func TestAggregateByPackage(t *testing.T) {
+	tests := map[string]struct {
+		reason         string
+		crossplanePods []topMetrics
+		want           []packageMetrics
+	}{
+		"NoPods": {
+			reason:         "Should return no packages when there are no pods",
+			crossplanePods: []topMetrics{},
+			want:           []packageMetrics{},
+		},
+		"MultiplePodsPerPackage": {
+			reason: "Should sum the usage of all pods owned by the same package",
+			crossplanePods: []topMetrics{
+				{
+					PodType:     "provider",
+					PackageName: "provider-aws-s3",
+					PodName:     "provider-aws-s3-123",
+					CPUUsage:    resource.MustParse("100m"),
+					MemoryUsage: resource.MustParse("256Mi"),
+				},
+				{
+					PodType:     "crossplane",
+					PackageName: "crossplane",
+					PodName:     "crossplane-123",
+					CPUUsage:    resource.MustParse("50m"),
+					MemoryUsage: resource.MustParse("128Mi"),
+				},
+				{
+					PodType:     "provider",
+					PackageName: "provider-aws-s3",
+					PodName:     "provider-aws-s3-456",
+					CPUUsage:    resource.MustParse("200m"),
+					MemoryUsage: resource.MustParse("256Mi"),
+				},
+			},
+			want: []packageMetrics{
+				{
+					PackageType: "crossplane",
+					PackageName: "crossplane",
+					Pods:        1,
+					CPUUsage:    resource.MustParse("50m"),
+					MemoryUsage: resource.MustParse("128Mi"),
+				},
+				{
+					PackageType: "provider",
+					PackageName: "provider-aws-s3",
+					Pods:        2,
+					CPUUsage:    resource.MustParse("300m"),
+					MemoryUsage: resource.MustParse("512Mi"),
+				},
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := aggregateByPackage(tt.crossplanePods)
+			if diff := cmp.Diff(tt.want, got, cmp.Comparer(func(a, b resource.Quantity) bool { return a.Cmp(b) == 0 })); diff != "" {
+				t.Errorf("%s\naggregateByPackage(): -want, +got:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPrintPackagesTable(t *testing.T) {
+	tests := map[string]struct {
+		reason string
+		pkgs   []packageMetrics
+		want   string
+	}{
+		"MultiplePackages": {
+			reason: "Should return a row per package",
+			pkgs: []packageMetrics{
+				{
+					PackageType: "crossplane",
+					PackageName: "crossplane",
+					Pods:        1,
+					CPUUsage:    resource.MustParse("50m"),
+					MemoryUsage: resource.MustParse("128Mi"),
+				},
+				{
+					PackageType: "provider",
+					PackageName: "provider-aws-s3",
+					Pods:        2,
+					CPUUsage:    resource.MustParse("300m"),
+					MemoryUsage: resource.MustParse("512Mi"),
+				},
+			},
+			want: `
+TYPE         PACKAGE           PODS   CPU(cores)   MEMORY
+crossplane   crossplane        1      50m          128Mi
+provider     provider-aws-s3   2      300m         512Mi
+`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			b := &bytes.Buffer{}
+			if err := printPackagesTable(b, tt.pkgs); err != nil {
+				t.Errorf("%s\nprintPackagesTable(): unexpected error: %v", tt.reason, err)
+			}
+			if diff := cmp.Diff(strings.TrimSpace(tt.want), strings.TrimSpace(b.String())); diff != "" {
+				t.Errorf("%s\nprintPackagesTable(): -want, +got:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
@@ -253,6 +253,7 @@ func getPkgResourceStatus(r *resource.Resource, name string, wide bool) fmt.Stri
 
 	healthyCond := r.GetCondition(pkgv1.TypeHealthy)
 	installedCond := r.GetCondition(pkgv1.TypeInstalled)
+	runtimeCond := r.GetCondition(pkgv1.TypeRuntimeHealthy)
 
 	gk := r.Unstructured.GroupVersionKind().GroupKind()
 	switch {
@@ -263,7 +264,7 @@ func getPkgResourceStatus(r *resource.Resource, name string, wide bool) fmt.Stri
 		m = r.Error.Error()
 	case xpkg.IsPackageType(gk):
 		switch {
-		case healthyCond.Status == corev1.ConditionTrue && installedCond.Status == corev1.ConditionTrue:
+		case healthyCond.Status == corev1.ConditionTrue && installedCond.Status == corev1.ConditionTrue && runtimeCond.Status != corev1.ConditionFalse:
 			// If both are true we want to show the healthy reason only
 			status = string(healthyCond.Reason)
 
@@ -277,6 +278,10 @@ func getPkgResourceStatus(r *resource.Resource, name string, wide bool) fmt.Stri
 			(healthyCond.Reason != "" || healthyCond.Message != ""):
 			status = string(healthyCond.Reason)
 			m = healthyCond.Message
+		case runtimeCond.Status == corev1.ConditionFalse:
+			// The package installed, but its runtime is failing.
+			status = string(runtimeCond.Reason)
+			m = runtimeCond.Message
 		default:
 			// both are unknown or unset, let's try showing the installed reason
 			status = string(installedCond.Reason)
@@ -287,9 +292,15 @@ func getPkgResourceStatus(r *resource.Resource, name string, wide bool) fmt.Stri
 			state = err.Error()
 		}
 	case xpkg.IsPackageRevisionType(gk):
-		// package revisions only have the healthy condition, so use that
+		// package revisions only have the healthy condition, and the runtime
+		// healthy condition if they have a runtime. We show the latter if
+		// the revision is healthy but its runtime is failing.
 		status = string(healthyCond.Reason)
 		m = healthyCond.Message
+		if healthyCond.Status == corev1.ConditionTrue && runtimeCond.Status == corev1.ConditionFalse {
+			status = string(runtimeCond.Reason)
+			m = runtimeCond.Message
+		}
 
 		// Get the state (active vs. inactive) of this package revision.
 		var err error
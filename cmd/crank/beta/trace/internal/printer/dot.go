@@ -7,6 +7,7 @@ import (
 
 	"github.com/emicklei/dot"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
@@ -113,46 +114,9 @@ func (p *DotPrinter) Print(w io.Writer, root *resource.Resource) error {
 		if item.parent != nil {
 			g.Edge(*item.parent, node)
 		}
-		var label fmt.Stringer
-		gk := item.resource.Unstructured.GroupVersionKind().GroupKind()
-		switch {
-		case xpkg.IsPackageType(gk):
-			pkg, err := fieldpath.Pave(item.resource.Unstructured.Object).GetString("spec.package")
-			l := &dotPackageLabel{
-				apiVersion: item.resource.Unstructured.GroupVersionKind().GroupVersion().String(),
-				name:       item.resource.Unstructured.GetName(),
-				pkg:        pkg,
-				installed:  string(item.resource.GetCondition(v1.TypeInstalled).Status),
-				healthy:    string(item.resource.GetCondition(v1.TypeHealthy).Status),
-			}
-			if err != nil {
-				l.error = err.Error()
-			}
-			label = l
-		case xpkg.IsPackageRevisionType(gk):
-			pkg, err := fieldpath.Pave(item.resource.Unstructured.Object).GetString("spec.image")
-			l := &dotPackageLabel{
-				apiVersion: item.resource.Unstructured.GroupVersionKind().GroupVersion().String(),
-				name:       item.resource.Unstructured.GetName(),
-				pkg:        pkg,
-				healthy:    string(item.resource.GetCondition(v1.TypeHealthy).Status),
-				state:      string(item.resource.GetCondition(v1.TypeHealthy).Reason),
-			}
-			if err != nil {
-				l.error = err.Error()
-			}
-			label = l
-		default:
-			label = &dotLabel{
-				namespace:  item.resource.Unstructured.GetNamespace(),
-				apiVersion: item.resource.Unstructured.GetObjectKind().GroupVersionKind().GroupVersion().String(),
-				name:       fmt.Sprintf("%s/%s", item.resource.Unstructured.GetKind(), item.resource.Unstructured.GetName()),
-				ready:      string(item.resource.GetCondition(xpv1.TypeReady).Status),
-				synced:     string(item.resource.GetCondition(xpv1.TypeSynced).Status),
-			}
-		}
-		node.Label(label.String())
+		node.Label(nodeLabel(item.resource).String())
 		node.Attr("penwidth", "2")
+		node.Attr("color", dotColors[nodeStateOf(item.resource)])
 
 		// Push the children to the stack, increasing the depth
 		for _, child := range item.resource.Children {
@@ -168,3 +132,78 @@ func (p *DotPrinter) Print(w io.Writer, root *resource.Resource) error {
 
 	return nil
 }
+
+// nodeLabel returns the label of the graph node for the supplied resource.
+func nodeLabel(r *resource.Resource) fmt.Stringer {
+	gk := r.Unstructured.GroupVersionKind().GroupKind()
+	switch {
+	case xpkg.IsPackageType(gk):
+		pkg, err := fieldpath.Pave(r.Unstructured.Object).GetString("spec.package")
+		l := &dotPackageLabel{
+			apiVersion: r.Unstructured.GroupVersionKind().GroupVersion().String(),
+			name:       r.Unstructured.GetName(),
+			pkg:        pkg,
+			installed:  string(r.GetCondition(v1.TypeInstalled).Status),
+			healthy:    string(r.GetCondition(v1.TypeHealthy).Status),
+		}
+		if err != nil {
+			l.error = err.Error()
+		}
+		return l
+	case xpkg.IsPackageRevisionType(gk):
+		pkg, err := fieldpath.Pave(r.Unstructured.Object).GetString("spec.image")
+		l := &dotPackageLabel{
+			apiVersion: r.Unstructured.GroupVersionKind().GroupVersion().String(),
+			name:       r.Unstructured.GetName(),
+			pkg:        pkg,
+			healthy:    string(r.GetCondition(v1.TypeHealthy).Status),
+			state:      string(r.GetCondition(v1.TypeHealthy).Reason),
+		}
+		if err != nil {
+			l.error = err.Error()
+		}
+		return l
+	default:
+		return &dotLabel{
+			namespace:  r.Unstructured.GetNamespace(),
+			apiVersion: r.Unstructured.GetObjectKind().GroupVersionKind().GroupVersion().String(),
+			name:       fmt.Sprintf("%s/%s", r.Unstructured.GetKind(), r.Unstructured.GetName()),
+			ready:      string(r.GetCondition(xpv1.TypeReady).Status),
+			synced:     string(r.GetCondition(xpv1.TypeSynced).Status),
+		}
+	}
+}
+
+// A nodeState summarizes the state of a resource, for coloring graph nodes.
+type nodeState string
+
+const (
+	nodeStateReady    nodeState = "ready"
+	nodeStateNotReady nodeState = "notready"
+	nodeStateUnknown  nodeState = "unknown"
+)
+
+var dotColors = map[nodeState]string{
+	nodeStateReady:    "green",
+	nodeStateNotReady: "red",
+	nodeStateUnknown:  "orange",
+}
+
+// nodeStateOf returns the state of the supplied resource. Packages and package
+// revisions are ready when they're healthy, all other resources when they're
+// ready.
+func nodeStateOf(r *resource.Resource) nodeState {
+	ct := xpv1.TypeReady
+	gk := r.Unstructured.GroupVersionKind().GroupKind()
+	if xpkg.IsPackageType(gk) || xpkg.IsPackageRevisionType(gk) {
+		ct = v1.TypeHealthy
+	}
+	switch r.GetCondition(ct).Status {
+	case corev1.ConditionTrue:
+		return nodeStateReady
+	case corev1.ConditionFalse:
+		return nodeStateNotReady
+	default:
+		return nodeStateUnknown
+	}
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane/cmd/crank/beta/trace/internal/resource"
+)
+
+const errWriteMermaid = "cannot write mermaid graph"
+
+// MermaidPrinter prints a resource tree as a Mermaid flowchart.
+type MermaidPrinter struct{}
+
+var _ Printer = &MermaidPrinter{}
+
+// mermaidClasses styles nodes by their state. They're defined in this order.
+var mermaidClasses = []struct {
+	state nodeState
+	style string
+}{
+	{nodeStateReady, "stroke:#2e7d32,stroke-width:2px"},
+	{nodeStateNotReady, "stroke:#c62828,stroke-width:2px"},
+	{nodeStateUnknown, "stroke:#ef6c00,stroke-width:2px"},
+}
+
+// mermaidEscaper escapes node labels. Mermaid labels are quoted, and support
+// HTML line breaks.
+var mermaidEscaper = strings.NewReplacer(`"`, "#quot;", "\n", "<br/>")
+
+// Print writes the supplied resource tree to the Writer as a Mermaid
+// flowchart, coloring each node by its state.
+func (p *MermaidPrinter) Print(w io.Writer, root *resource.Resource) error {
+	type queueItem struct {
+		resource *resource.Resource
+		parent   string
+	}
+
+	nodes := &strings.Builder{}
+	edges := &strings.Builder{}
+	classes := map[nodeState][]string{}
+
+	queue := []*queueItem{{root, ""}}
+	var id int
+
+	for len(queue) > 0 {
+		item := queue[0]
+		queue = queue[1:]
+
+		node := fmt.Sprintf("n%d", id)
+		id++
+
+		label := strings.TrimSuffix(nodeLabel(item.resource).String(), "\n")
+		fmt.Fprintf(nodes, "    %s[\"%s\"]\n", node, mermaidEscaper.Replace(label))
+		if item.parent != "" {
+			fmt.Fprintf(edges, "    %s --> %s\n", item.parent, node)
+		}
+		s := nodeStateOf(item.resource)
+		classes[s] = append(classes[s], node)
+
+		for _, child := range item.resource.Children {
+			queue = append(queue, &queueItem{child, node})
+		}
+	}
+
+	out := &strings.Builder{}
+	out.WriteString("flowchart TD\n")
+	out.WriteString(nodes.String())
+	out.WriteString(edges.String())
+	for _, c := range mermaidClasses {
+		fmt.Fprintf(out, "    classDef %s %s\n", c.state, c.style)
+	}
+	for _, c := range mermaidClasses {
+		if n := classes[c.state]; len(n) > 0 {
+			fmt.Fprintf(out, "    class %s %s\n", strings.Join(n, ","), c.state)
+		}
+	}
+
+	_, err := io.WriteString(w, out.String())
+	return errors.Wrap(err, errWriteMermaid)
+}
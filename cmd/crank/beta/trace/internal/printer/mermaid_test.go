@@ -0,0 +1,90 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/crossplane/cmd/crank/beta/trace/internal/resource"
+)
+
+func TestMermaidPrinter(t *testing.T) {
+	type args struct {
+		resource *resource.Resource
+	}
+
+	type want struct {
+		output string
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ResourceWithChildren": {
+			reason: "Should print a complex Resource with children, with nodes classed by readiness.",
+			args: args{
+				resource: GetComplexResource(),
+			},
+			want: want{
+				output: `flowchart TD
+    n0["Name: ObjectStorage/test-resource<br/>ApiVersion: test.cloud/v1alpha1<br/>Namespace: default<br/>Ready: True<br/>Synced: True"]
+    n1["Name: XObjectStorage/test-resource-hash<br/>ApiVersion: test.cloud/v1alpha1<br/>Ready: True<br/>Synced: True"]
+    n2["Name: Bucket/test-resource-bucket-hash<br/>ApiVersion: test.cloud/v1alpha1<br/>Ready: True<br/>Synced: True"]
+    n3["Name: User/test-resource-user-hash<br/>ApiVersion: test.cloud/v1alpha1<br/>Ready: True<br/>Synced: Unknown"]
+    n4["Name: User/test-resource-child-1-bucket-hash<br/>ApiVersion: test.cloud/v1alpha1<br/>Ready: False<br/>Synced: True"]
+    n5["Name: User/test-resource-child-mid-bucket-hash<br/>ApiVersion: test.cloud/v1alpha1<br/>Ready: True<br/>Synced: False"]
+    n6["Name: User/test-resource-child-2-bucket-hash<br/>ApiVersion: test.cloud/v1alpha1<br/>Ready: False<br/>Synced: True"]
+    n7["Name: User/test-resource-child-2-1-bucket-hash<br/>ApiVersion: test.cloud/v1alpha1<br/>Ready: <br/>Synced: True"]
+    n0 --> n1
+    n1 --> n2
+    n1 --> n3
+    n2 --> n4
+    n2 --> n5
+    n2 --> n6
+    n6 --> n7
+    classDef ready stroke:#2e7d32,stroke-width:2px
+    classDef notready stroke:#c62828,stroke-width:2px
+    classDef unknown stroke:#ef6c00,stroke-width:2px
+    class n0,n1,n2,n3,n5 ready
+    class n4,n6 notready
+    class n7 unknown
+`,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := &MermaidPrinter{}
+			var buf bytes.Buffer
+			err := p.Print(&buf, tc.args.resource)
+
+			if diff := cmp.Diff(tc.want.err, err); diff != "" {
+				t.Errorf("%s\nMermaidPrinter.Print(): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.output, buf.String()); diff != "" {
+				t.Errorf("%s\nMermaidPrinter.Print(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
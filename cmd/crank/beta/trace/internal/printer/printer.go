@@ -39,6 +39,7 @@ const (
 	TypeWide    Type = "wide"
 	TypeJSON    Type = "json"
 	TypeDot     Type = "dot"
+	TypeMermaid Type = "mermaid"
 )
 
 // Printer implements the interface which is used by all printers in this package.
@@ -61,6 +62,8 @@ func New(typeStr string) (Printer, error) {
 		p = &JSONPrinter{}
 	case TypeDot:
 		p = &DotPrinter{}
+	case TypeMermaid:
+		p = &MermaidPrinter{}
 	default:
 		return nil, errors.Errorf(errFmtUnknownPrinterType, typeStr)
 	}
@@ -27,8 +27,8 @@ import (
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/restmapper"
-	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
@@ -44,7 +44,6 @@ import (
 const (
 	errGetResource            = "cannot get requested resource"
 	errCliOutput              = "cannot print output"
-	errKubeConfig             = "failed to get kubeconfig"
 	errInitKubeClient         = "cannot init kubeclient"
 	errGetDiscoveryClient     = "cannot get discovery client"
 	errGetMapping             = "cannot get mapping for resource"
@@ -57,13 +56,13 @@ const (
 
 // Cmd builds the trace tree for a Crossplane resource.
 type Cmd struct {
-	Resource string `arg:"" help:"Kind of the Crossplane resource, accepts the 'TYPE[.VERSION][.GROUP][/NAME]' format."`
+	Resource string `arg:"" predictor:"xr-kind" help:"Kind of the Crossplane resource, accepts the 'TYPE[.VERSION][.GROUP][/NAME]' format."`
 	Name     string `arg:"" optional:"" help:"Name of the Crossplane resource, can be passed as part of the resource too."`
 
 	// TODO(phisco): add support for all the usual kubectl flags; configFlags := genericclioptions.NewConfigFlags(true).AddFlags(...)
 	// TODO(phisco): move to namespace defaulting to "" and use the current context's namespace
 	Namespace                 string `short:"n" name:"namespace" help:"Namespace of the resource." default:"default"`
-	Output                    string `short:"o" name:"output" help:"Output format. One of: default, wide, json, dot." enum:"default,wide,json,dot" default:"default"`
+	Output                    string `short:"o" name:"output" help:"Output format. One of: default, wide, json, dot, mermaid." enum:"default,wide,json,dot,mermaid" default:"default"`
 	ShowConnectionSecrets     bool   `short:"s" name:"show-connection-secrets" help:"Show connection secrets in the output."`
 	ShowPackageDependencies   string `name:"show-package-dependencies" help:"Show package dependencies in the output. One of: unique, all, none." enum:"unique,all,none" default:"unique"`
 	ShowPackageRevisions      string `name:"show-package-revisions" help:"Show package revisions in the output. One of: active, all, none." enum:"active,all,none" default:"active"`
@@ -93,6 +92,9 @@ Examples:
   # Output a graph in dot format and pipe to dot to generate a png
   crossplane beta trace mykind my-res -n my-ns -o dot | dot -Tpng -o output.png
 
+  # Output a graph in Mermaid format, e.g. to embed in Markdown documentation
+  crossplane beta trace mykind my-res -n my-ns -o mermaid
+
   # Output all retrieved resources to json and pipe to jq to have it coloured
   crossplane beta trace mykind my-res -n my-ns -o json | jq
 
@@ -102,7 +104,7 @@ Examples:
 }
 
 // Run runs the trace command.
-func (c *Cmd) Run(k *kong.Context, logger logging.Logger) error { //nolint:gocyclo // TODO(phisco): refactor
+func (c *Cmd) Run(k *kong.Context, logger logging.Logger, kubeconfig *rest.Config) error { //nolint:gocyclo // TODO(phisco): refactor
 	ctx := context.Background()
 	logger = logger.WithValues("Resource", c.Resource, "Name", c.Name)
 
@@ -113,12 +115,6 @@ func (c *Cmd) Run(k *kong.Context, logger logging.Logger) error { //nolint:gocyc
 	}
 	logger.Debug("Built printer", "output", c.Output)
 
-	kubeconfig, err := ctrl.GetConfig()
-	if err != nil {
-		return errors.Wrap(err, errKubeConfig)
-	}
-	logger.Debug("Found kubeconfig")
-
 	client, err := client.New(kubeconfig, client.Options{
 		Scheme: scheme.Scheme,
 	})
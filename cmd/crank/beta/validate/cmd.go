@@ -14,7 +14,8 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-// Package validate implements offline schema validation of Crossplane resources.
+// Package validate implements the validate command. Validation itself is
+// implemented by github.com/crossplane/crossplane/pkg/validate.
 package validate
 
 import (
@@ -26,6 +27,10 @@ import (
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/crossplane/crossplane/cmd/crank/output"
+	"github.com/crossplane/crossplane/internal/controller/apiextensions/composite"
+	"github.com/crossplane/crossplane/pkg/validate"
 )
 
 // Cmd arguments and flags for render subcommand.
@@ -35,9 +40,10 @@ type Cmd struct {
 	Resources  string `arg:"" help:"Resources source which can be a file, directory, or '-' for standard input."`
 
 	// Flags. Keep them in alphabetical order.
-	CacheDir           string `help:"Absolute path to the cache directory where downloaded schemas are stored." default:".crossplane/cache"`
-	CleanCache         bool   `help:"Clean the cache directory before downloading package schemas."`
-	SkipSuccessResults bool   `help:"Skip printing success results."`
+	CacheDir           string   `help:"Absolute path to the cache directory where downloaded schemas are stored." default:".crossplane/cache"`
+	CleanCache         bool     `help:"Clean the cache directory before downloading package schemas."`
+	Providers          []string `name:"provider" placeholder:"PACKAGE" help:"A provider package to download CRD schemas from, for example xpkg.upbound.io/crossplane-contrib/provider-aws:v0.44.0. May be repeated."`
+	SkipSuccessResults bool     `help:"Skip printing success results."`
 
 	fs afero.Fs
 }
@@ -53,6 +59,9 @@ If providers or configurations are provided as extensions, they will be download
 validation. If the cache directory is not provided, it will default to ".crossplane/cache" in the current workspace. 
 Cache directory can be cleaned before downloading schemas by setting the "clean-cache" flag.
 
+Provider packages can also be passed using the "provider" flag, to validate managed resources without having to write
+a Provider manifest. Their CRDs are downloaded and cached the same way.
+
 All validation is performed offline locally using the Kubernetes API server's validation library, so it does not require 
 any Crossplane instance or control plane to be running or configured.
 
@@ -71,6 +80,9 @@ Examples:
   # Validate all resources in the resourceDir folder against the extensions in the extensionsDir folder using provided
   # cache directory and clean the cache directory before downloading schemas
   crossplane beta validate extensionsDir/ resourceDir/ --cache-dir .cache --clean-cache
+
+  # Validate managed resources in the resourceDir folder against the CRDs of a provider package
+  crossplane beta validate extensionsDir/ resourceDir/ --provider xpkg.upbound.io/crossplane-contrib/provider-aws:v0.44.0
 `
 }
 
@@ -81,13 +93,13 @@ func (c *Cmd) AfterApply() error {
 }
 
 // Run validate.
-func (c *Cmd) Run(_ *kong.Context, _ logging.Logger) error { //nolint:gocyclo // stdin check makes it over the top
+func (c *Cmd) Run(_ *kong.Context, _ logging.Logger, p *output.Printer) error { //nolint:gocyclo // stdin check makes it over the top
 	if c.Resources == "-" && c.Extensions == "-" {
 		return errors.New("cannot use stdin for both extensions and resources")
 	}
 
 	// Load all extensions
-	extensionLoader, err := NewLoader(c.Extensions)
+	extensionLoader, err := validate.NewLoader(c.Extensions)
 	if err != nil {
 		return errors.Wrapf(err, "cannot load extensions from %q", c.Extensions)
 	}
@@ -98,7 +110,7 @@ func (c *Cmd) Run(_ *kong.Context, _ logging.Logger) error { //nolint:gocyclo //
 	}
 
 	// Load all resources
-	resourceLoader, err := NewLoader(c.Resources)
+	resourceLoader, err := validate.NewLoader(c.Resources)
 	if err != nil {
 		return errors.Wrapf(err, "cannot load resources from %q", c.Resources)
 	}
@@ -109,7 +121,7 @@ func (c *Cmd) Run(_ *kong.Context, _ logging.Logger) error { //nolint:gocyclo //
 	}
 
 	// Update default cache directory to absolute path based on the current working directory
-	if c.CacheDir == defaultCacheDir {
+	if c.CacheDir == validate.DefaultCacheDir {
 		currentPath, err := os.Getwd()
 		if err != nil {
 			return errors.Wrapf(err, "cannot get current path")
@@ -117,22 +129,56 @@ func (c *Cmd) Run(_ *kong.Context, _ logging.Logger) error { //nolint:gocyclo //
 		c.CacheDir = filepath.Join(currentPath, c.CacheDir)
 	}
 
-	m := NewManager(c.CacheDir, c.fs)
+	m := validate.NewManager(c.CacheDir, c.fs, p.InfoWriter())
 
 	// Convert XRDs/CRDs to CRDs and add package dependencies
 	if err := m.PrepExtensions(extensions); err != nil {
 		return errors.Wrapf(err, "cannot prepare extensions")
 	}
 
+	// Add provider packages passed as flags
+	m.AddProviders(c.Providers...)
+
 	// Download package base layers to cache and load them as CRDs
 	if err := m.CacheAndLoad(c.CleanCache); err != nil {
 		return errors.Wrapf(err, "cannot download and load cache")
 	}
 
 	// Validate resources against schemas
-	if err := SchemaValidation(resources, m.crds, c.SkipSuccessResults); err != nil {
+	results, err := validate.Validate(resources, m.CRDs())
+	if err != nil {
 		return errors.Wrapf(err, "cannot validate resources")
 	}
+	return errors.Wrapf(printResults(p, results, c.SkipSuccessResults), "cannot validate resources")
+}
 
+// printResults prints the supplied validation results. It returns an error if
+// any resource is invalid.
+func printResults(p *output.Printer, results []validate.Result, skipSuccessResults bool) error {
+	failure, warning := 0, 0
+	for _, res := range results {
+		r := res.Resource
+		name := r.GetAnnotations()[composite.AnnotationKeyCompositionResourceName]
+		switch res.Status {
+		case validate.ResultMissingSchema:
+			warning++
+			p.Warnf("could not find CRD/XRD for: %s", r.GroupVersionKind().String())
+		case validate.ResultInvalid:
+			failure++
+			for _, e := range res.Errors {
+				p.Error(errors.Errorf("validation error %s, %s : %s", r.GroupVersionKind().String(), name, e.Error()))
+			}
+		case validate.ResultValid:
+			if !skipSuccessResults {
+				p.Infof("[✓] %s, %s validated successfully", r.GroupVersionKind().String(), name)
+			}
+		}
+	}
+
+	p.Infof("%d error, %d warning, %d success cases", failure, warning, len(results)-failure-warning)
+
+	if failure > 0 {
+		return errors.New("could not validate all resources")
+	}
 	return nil
 }
@@ -0,0 +1,277 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package whoowns contains the who-owns command.
+package whoowns
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+
+	"github.com/crossplane/crossplane/cmd/crank/output"
+)
+
+const (
+	errInitKubeClient = "cannot init kubeclient"
+	errListCRDs       = "cannot list CustomResourceDefinitions"
+	errFmtListMRs     = "cannot list %s"
+	errFmtGetOwner    = "cannot get owner %s %q"
+	errFmtNotFound    = "no managed resource has external name %q"
+)
+
+// categoryManaged is the CRD category all managed resources are in.
+const categoryManaged = "managed"
+
+// maxOwnerDepth limits how far we walk up a chain of owner references, in
+// case the chain contains a cycle.
+const maxOwnerDepth = 32
+
+// Cmd arguments and flags for the who-owns subcommand.
+type Cmd struct {
+	// Flags. Keep them in alphabetical order.
+	ExternalName string        `required:"" help:"External name of the cloud resource, i.e. its crossplane.io/external-name annotation."`
+	Kind         string        `help:"Kind of managed resource to search, e.g. VPC or vpcs.ec2.aws.upbound.io. Searches all managed resources if omitted."`
+	Timeout      time.Duration `help:"How long to run before timing out." default:"1m"`
+}
+
+// Help prints out the help for the who-owns command.
+func (c *Cmd) Help() string {
+	return `
+This command finds the managed resource that represents a cloud resource, and
+the composite resource (XR) and claim that own it. It answers the question
+"who created this cloud resource?".
+
+It searches managed resources for the supplied external name, then walks up
+their controller owner references to the claim, if any. Each match is printed
+as a chain from the managed resource to its claim.
+
+Examples:
+
+  # Find who owns the VPC with ID vpc-12345.
+  crossplane beta who-owns --external-name vpc-12345 --kind VPC
+
+  # Search all managed resources. This can be slow in large clusters.
+  crossplane beta who-owns --external-name my-bucket
+`
+}
+
+// Run who-owns.
+func (c *Cmd) Run(logger logging.Logger, kubeconfig *rest.Config, p *output.Printer) error {
+	logger = logger.WithValues("cmd", "who-owns")
+
+	s := runtime.NewScheme()
+	_ = extv1.AddToScheme(s)
+	kube, err := client.New(kubeconfig, client.Options{Scheme: s})
+	if err != nil {
+		return errors.Wrap(err, errInitKubeClient)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+
+	chains, err := NewFinder(kube).Find(ctx, c.Kind, c.ExternalName)
+	if err != nil {
+		return err
+	}
+	logger.Debug("Found owners", "count", len(chains))
+
+	if len(chains) == 0 {
+		return errors.Errorf(errFmtNotFound, c.ExternalName)
+	}
+	for _, ch := range chains {
+		p.Infof("%s", ch)
+	}
+	return nil
+}
+
+// A Ref refers to a resource in a chain of owners.
+type Ref struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+
+	// Claim is true if this resource is a claim.
+	Claim bool `json:"claim,omitempty"`
+}
+
+// String returns a human readable representation of the Ref.
+func (r Ref) String() string {
+	s := r.Kind + "/" + r.Name
+	if r.Namespace != "" {
+		s = r.Kind + "/" + r.Namespace + "/" + r.Name
+	}
+	if r.Claim {
+		s += " (claim)"
+	}
+	return s
+}
+
+// A Chain of owners, starting with a managed resource and ending with the
+// outermost resource that owns it - usually a claim.
+type Chain []Ref
+
+// String returns a human readable representation of the Chain.
+func (c Chain) String() string {
+	s := make([]string, len(c))
+	for i := range c {
+		s[i] = c[i].String()
+	}
+	return strings.Join(s, " -> ")
+}
+
+// A Finder finds the owners of managed resources.
+type Finder struct {
+	client client.Reader
+}
+
+// NewFinder returns a Finder that reads resources using the supplied client.
+func NewFinder(c client.Reader) *Finder {
+	return &Finder{client: c}
+}
+
+// Find the owners of all managed resources with the supplied external name.
+// Only managed resources of the supplied kind are searched, unless kind is
+// empty. The kind may be a kind, a plural, singular, or short name, or a
+// plural qualified by its API group.
+func (f *Finder) Find(ctx context.Context, kind, externalName string) ([]Chain, error) {
+	l := &extv1.CustomResourceDefinitionList{}
+	if err := f.client.List(ctx, l); err != nil {
+		return nil, errors.Wrap(err, errListCRDs)
+	}
+
+	chains := make([]Chain, 0)
+	for i := range l.Items {
+		crd := &l.Items[i]
+		if !isManaged(crd) || (kind != "" && !matchesKind(crd, kind)) {
+			continue
+		}
+		gvk := schema.GroupVersionKind{Group: crd.Spec.Group, Version: servedVersion(crd), Kind: crd.Spec.Names.Kind}
+
+		mrs := &unstructured.UnstructuredList{}
+		mrs.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := f.client.List(ctx, mrs); err != nil {
+			return nil, errors.Wrapf(err, errFmtListMRs, crd.GetName())
+		}
+
+		for j := range mrs.Items {
+			mr := &mrs.Items[j]
+			if meta.GetExternalName(mr) != externalName {
+				continue
+			}
+			ch, err := f.owners(ctx, mr)
+			if err != nil {
+				return nil, err
+			}
+			chains = append(chains, ch)
+		}
+	}
+	return chains, nil
+}
+
+// owners returns the chain of owners of the supplied managed resource. It
+// walks up controller owner references, then follows the claim reference of
+// the outermost composite resource.
+func (f *Finder) owners(ctx context.Context, mr *unstructured.Unstructured) (Chain, error) {
+	ch := Chain{refTo(mr)}
+	cur := mr
+	for i := 0; i < maxOwnerDepth; i++ {
+		or := metav1.GetControllerOf(cur)
+		if or == nil {
+			break
+		}
+		owner := &unstructured.Unstructured{}
+		owner.SetAPIVersion(or.APIVersion)
+		owner.SetKind(or.Kind)
+		// Owners must be cluster scoped, or in the same namespace as the
+		// resources they own.
+		if err := f.client.Get(ctx, client.ObjectKey{Namespace: cur.GetNamespace(), Name: or.Name}, owner); err != nil {
+			return nil, errors.Wrapf(err, errFmtGetOwner, or.Kind, or.Name)
+		}
+		ch = append(ch, refTo(owner))
+		cur = owner
+	}
+
+	xr := composite.Unstructured{Unstructured: *cur}
+	if ref := xr.GetClaimReference(); ref != nil && ref.Name != "" {
+		ch = append(ch, Ref{
+			APIVersion: ref.APIVersion,
+			Kind:       ref.Kind,
+			Namespace:  ref.Namespace,
+			Name:       ref.Name,
+			Claim:      true,
+		})
+	}
+	return ch, nil
+}
+
+func refTo(u *unstructured.Unstructured) Ref {
+	return Ref{APIVersion: u.GetAPIVersion(), Kind: u.GetKind(), Namespace: u.GetNamespace(), Name: u.GetName()}
+}
+
+func isManaged(crd *extv1.CustomResourceDefinition) bool {
+	for _, c := range crd.Spec.Names.Categories {
+		if c == categoryManaged {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesKind(crd *extv1.CustomResourceDefinition, kind string) bool {
+	n := crd.Spec.Names
+	if strings.EqualFold(kind, crd.GetName()) {
+		return true
+	}
+	for _, k := range append([]string{n.Kind, n.Plural, n.Singular}, n.ShortNames...) {
+		if strings.EqualFold(kind, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// servedVersion returns the storage version of the CRD if it's served, or
+// else the first served version.
+func servedVersion(crd *extv1.CustomResourceDefinition) string {
+	v := ""
+	for _, cv := range crd.Spec.Versions {
+		if !cv.Served {
+			continue
+		}
+		if cv.Storage {
+			return cv.Name
+		}
+		if v == "" {
+			v = cv.Name
+		}
+	}
+	return v
+}
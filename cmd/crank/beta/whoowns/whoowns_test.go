@@ -0,0 +1,184 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package whoowns
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestFind(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	crd := extv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "vpcs.ec2.aws.upbound.io"},
+		Spec: extv1.CustomResourceDefinitionSpec{
+			Group: "ec2.aws.upbound.io",
+			Names: extv1.CustomResourceDefinitionNames{
+				Kind:       "VPC",
+				Plural:     "vpcs",
+				Categories: []string{"crossplane", "managed", "aws"},
+			},
+			Versions: []extv1.CustomResourceDefinitionVersion{
+				{Name: "v1beta1", Served: true, Storage: true},
+			},
+		},
+	}
+
+	mr := func(name, extName string) unstructured.Unstructured {
+		u := unstructured.Unstructured{}
+		u.SetAPIVersion("ec2.aws.upbound.io/v1beta1")
+		u.SetKind("VPC")
+		u.SetName(name)
+		meta.SetExternalName(&u, extName)
+		meta.AddOwnerReference(&u, metav1.OwnerReference{
+			APIVersion: "example.org/v1",
+			Kind:       "XNetwork",
+			Name:       "cool-network-x7k2",
+			Controller: ptr.To(true),
+		})
+		return u
+	}
+
+	list := func(mrs ...unstructured.Unstructured) test.MockListFn {
+		return func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+			switch l := obj.(type) {
+			case *extv1.CustomResourceDefinitionList:
+				l.Items = []extv1.CustomResourceDefinition{crd}
+			case *unstructured.UnstructuredList:
+				l.Items = mrs
+			}
+			return nil
+		}
+	}
+
+	get := func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+		u := obj.(*unstructured.Unstructured)
+		u.SetName("cool-network-x7k2")
+		_ = unstructured.SetNestedMap(u.Object, map[string]any{
+			"apiVersion": "example.org/v1",
+			"kind":       "Network",
+			"namespace":  "default",
+			"name":       "cool-network",
+		}, "spec", "claimRef")
+		return nil
+	}
+
+	type args struct {
+		client       client.Reader
+		kind         string
+		externalName string
+	}
+	type want struct {
+		chains []Chain
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ListCRDsError": {
+			reason: "We should return any error encountered listing CRDs.",
+			args: args{
+				client: &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errListCRDs),
+			},
+		},
+		"KindDoesNotMatch": {
+			reason: "We shouldn't search managed resources of other kinds.",
+			args: args{
+				client:       &test.MockClient{MockList: list(mr("cool-vpc", "vpc-12345"))},
+				kind:         "Subnet",
+				externalName: "vpc-12345",
+			},
+			want: want{
+				chains: []Chain{},
+			},
+		},
+		"GetOwnerError": {
+			reason: "We should return any error encountered getting an owner.",
+			args: args{
+				client: &test.MockClient{
+					MockList: list(mr("cool-vpc", "vpc-12345")),
+					MockGet:  test.NewMockGetFn(errBoom),
+				},
+				kind:         "VPC",
+				externalName: "vpc-12345",
+			},
+			want: want{
+				err: errors.Wrapf(errBoom, errFmtGetOwner, "XNetwork", "cool-network-x7k2"),
+			},
+		},
+		"FoundClaim": {
+			reason: "We should walk up from a matching managed resource to its claim.",
+			args: args{
+				client: &test.MockClient{
+					MockList: list(mr("cool-vpc", "vpc-12345"), mr("other-vpc", "vpc-67890")),
+					MockGet:  get,
+				},
+				kind:         "vpcs.ec2.aws.upbound.io",
+				externalName: "vpc-12345",
+			},
+			want: want{
+				chains: []Chain{{
+					{APIVersion: "ec2.aws.upbound.io/v1beta1", Kind: "VPC", Name: "cool-vpc"},
+					{APIVersion: "example.org/v1", Kind: "XNetwork", Name: "cool-network-x7k2"},
+					{APIVersion: "example.org/v1", Kind: "Network", Namespace: "default", Name: "cool-network", Claim: true},
+				}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := NewFinder(tc.args.client).Find(context.Background(), tc.args.kind, tc.args.externalName)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nFind(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.chains, got); diff != "" {
+				t.Errorf("\n%s\nFind(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestChainString(t *testing.T) {
+	c := Chain{
+		{Kind: "VPC", Name: "cool-vpc"},
+		{Kind: "XNetwork", Name: "cool-network-x7k2"},
+		{Kind: "Network", Namespace: "default", Name: "cool-network", Claim: true},
+	}
+	want := "VPC/cool-vpc -> XNetwork/cool-network-x7k2 -> Network/default/cool-network (claim)"
+	if diff := cmp.Diff(want, c.String()); diff != "" {
+		t.Errorf("String(): -want, +got:\n%s", diff)
+	}
+}
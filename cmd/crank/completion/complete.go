@@ -0,0 +1,262 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package completion
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/alecthomas/kong"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiextensionsv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	pkgv1beta1 "github.com/crossplane/crossplane/apis/pkg/v1beta1"
+)
+
+// TagPredictor is the struct tag used to name the Predictor that should be
+// used to complete a positional argument or flag value, e.g.
+// `predictor:"package"`.
+const TagPredictor = "predictor"
+
+// Names of the predictors returned by ClusterPredictors.
+const (
+	PredictorPackage = "package"
+	PredictorXRKind  = "xr-kind"
+)
+
+// A Predictor predicts the values of a positional argument or flag. It's
+// passed the positional arguments that precede the value being predicted.
+type Predictor func(ctx context.Context, args []string) []string
+
+// Complete returns the completions for the last of the supplied words, which
+// are the words of a command line excluding the program name. Completions are
+// subcommands, flags, enum values, or the predictions of the Predictor named
+// by an argument or flag's predictor tag.
+func Complete(ctx context.Context, app *kong.Node, predictors map[string]Predictor, words []string) []string { //nolint:gocyclo // Only a touch over.
+	if len(words) == 0 {
+		words = []string{""}
+	}
+	prefix := words[len(words)-1]
+
+	node := app
+	args := make([]string, 0)
+	var pending *kong.Flag
+	for _, w := range words[:len(words)-1] {
+		if pending != nil {
+			pending = nil
+			continue
+		}
+		if strings.HasPrefix(w, "-") {
+			if f := findFlag(node, w); f != nil && !f.IsBool() && !strings.Contains(w, "=") {
+				pending = f
+			}
+			continue
+		}
+		if c := findChild(node, w); c != nil {
+			node = c
+			continue
+		}
+		args = append(args, w)
+	}
+
+	if pending != nil {
+		return filter(predict(ctx, pending.Value, predictors, args), prefix)
+	}
+
+	if strings.HasPrefix(prefix, "-") {
+		if name, _, ok := strings.Cut(prefix, "="); ok {
+			f := findFlag(node, name)
+			if f == nil {
+				return nil
+			}
+			values := predict(ctx, f.Value, predictors, args)
+			for i := range values {
+				values[i] = name + "=" + values[i]
+			}
+			return filter(values, prefix)
+		}
+		return filter(flagNames(node), prefix)
+	}
+
+	out := make([]string, 0)
+	for _, c := range node.Children {
+		if c.Type == kong.CommandNode && !c.Hidden {
+			out = append(out, c.Name)
+		}
+	}
+	if len(args) < len(node.Positional) {
+		out = append(out, predict(ctx, node.Positional[len(args)], predictors, args)...)
+	}
+	return filter(out, prefix)
+}
+
+func predict(ctx context.Context, v *kong.Value, predictors map[string]Predictor, args []string) []string {
+	if v.Enum != "" {
+		return v.EnumSlice()
+	}
+	if p, ok := predictors[v.Tag.Get(TagPredictor)]; ok {
+		return p(ctx, args)
+	}
+	return nil
+}
+
+func findChild(n *kong.Node, name string) *kong.Node {
+	for _, c := range n.Children {
+		if c.Type != kong.CommandNode {
+			continue
+		}
+		if c.Name == name {
+			return c
+		}
+		for _, a := range c.Aliases {
+			if a == name {
+				return c
+			}
+		}
+	}
+	return nil
+}
+
+// findFlag finds a flag of the supplied node or its ancestors, e.g. --flag,
+// --flag=value, or -f.
+func findFlag(n *kong.Node, word string) *kong.Flag {
+	name, _, _ := strings.Cut(word, "=")
+	for ; n != nil; n = n.Parent {
+		for _, f := range n.Flags {
+			if name == "--"+f.Name || (f.Short != 0 && name == "-"+string(f.Short)) {
+				return f
+			}
+		}
+	}
+	return nil
+}
+
+func flagNames(n *kong.Node) []string {
+	out := make([]string, 0)
+	for ; n != nil; n = n.Parent {
+		for _, f := range n.Flags {
+			if !f.Hidden {
+				out = append(out, "--"+f.Name)
+			}
+		}
+	}
+	return out
+}
+
+func filter(candidates []string, prefix string) []string {
+	out := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		if strings.HasPrefix(c, prefix) {
+			out = append(out, c)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// ClusterPredictors returns predictors that query the cluster configured by
+// the current kubeconfig. They predict nothing if the cluster can't be
+// reached; completions should never fail loudly.
+func ClusterPredictors() map[string]Predictor {
+	return map[string]Predictor{
+		PredictorPackage: func(ctx context.Context, args []string) []string {
+			c, err := newClient()
+			if err != nil {
+				return nil
+			}
+			// Commands that take a package name typically take its kind as
+			// their first argument.
+			kind := ""
+			if len(args) > 0 {
+				kind = args[0]
+			}
+			return PackageNames(ctx, c, kind)
+		},
+		PredictorXRKind: func(ctx context.Context, _ []string) []string {
+			c, err := newClient()
+			if err != nil {
+				return nil
+			}
+			return XRKinds(ctx, c)
+		},
+	}
+}
+
+// PackageNames returns the names of the installed packages of the supplied
+// kind - "provider", "configuration", or "function". It returns the names of
+// all installed packages if the kind is unknown.
+func PackageNames(ctx context.Context, c client.Reader, kind string) []string {
+	lists := map[string]client.ObjectList{
+		"provider":      &pkgv1.ProviderList{},
+		"configuration": &pkgv1.ConfigurationList{},
+		"function":      &pkgv1beta1.FunctionList{},
+	}
+	if l, ok := lists[kind]; ok {
+		lists = map[string]client.ObjectList{kind: l}
+	}
+
+	out := make([]string, 0)
+	for _, l := range lists {
+		if err := c.List(ctx, l); err != nil {
+			continue
+		}
+		items, err := meta.ExtractList(l)
+		if err != nil {
+			continue
+		}
+		for _, o := range items {
+			if p, ok := o.(pkgv1.Package); ok {
+				out = append(out, p.GetName())
+			}
+		}
+	}
+	return out
+}
+
+// XRKinds returns the lowercase kinds of the composite resources and claims
+// defined by the CompositeResourceDefinitions in the cluster.
+func XRKinds(ctx context.Context, c client.Reader) []string {
+	l := &apiextensionsv1.CompositeResourceDefinitionList{}
+	if err := c.List(ctx, l); err != nil {
+		return nil
+	}
+	out := make([]string, 0, len(l.Items))
+	for _, xrd := range l.Items {
+		out = append(out, strings.ToLower(xrd.Spec.Names.Kind))
+		if xrd.Spec.ClaimNames != nil {
+			out = append(out, strings.ToLower(xrd.Spec.ClaimNames.Kind))
+		}
+	}
+	return out
+}
+
+func newClient() (client.Client, error) {
+	cfg, err := ctrl.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	s := runtime.NewScheme()
+	_ = apiextensionsv1.AddToScheme(s)
+	_ = pkgv1.AddToScheme(s)
+	_ = pkgv1beta1.AddToScheme(s)
+	return client.New(cfg, client.Options{Scheme: s})
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package completion
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alecthomas/kong"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+type installCmd struct {
+	Kind string `arg:"" enum:"provider,configuration,function"`
+	Name string `arg:"" predictor:"package"`
+
+	Wait bool   `short:"w"`
+	Mode string `enum:"fast,slow" default:"fast"`
+}
+
+type testCLI struct {
+	Install  installCmd `cmd:""`
+	Internal struct{}   `cmd:"" hidden:""`
+
+	Verbose bool
+}
+
+func TestComplete(t *testing.T) {
+	predictors := map[string]Predictor{
+		"package": func(_ context.Context, args []string) []string {
+			return []string{"cool-" + args[0]}
+		},
+	}
+
+	cases := map[string]struct {
+		reason string
+		words  []string
+		want   []string
+	}{
+		"NoWords": {
+			reason: "With no words we should complete visible subcommands.",
+			words:  nil,
+			want:   []string{"install"},
+		},
+		"Subcommand": {
+			reason: "We should complete subcommands that match the prefix.",
+			words:  []string{"ins"},
+			want:   []string{"install"},
+		},
+		"Flags": {
+			reason: "We should complete the flags of the current command and its ancestors.",
+			words:  []string{"install", "--"},
+			want:   []string{"--help", "--mode", "--verbose", "--wait"},
+		},
+		"EnumArgument": {
+			reason: "We should complete the values of an enum argument.",
+			words:  []string{"install", "p"},
+			want:   []string{"provider"},
+		},
+		"PredictedArgument": {
+			reason: "We should complete an argument using its predictor, passing it the preceding arguments.",
+			words:  []string{"install", "-w", "function", ""},
+			want:   []string{"cool-function"},
+		},
+		"FlagValue": {
+			reason: "We should complete the value of a flag that follows it.",
+			words:  []string{"install", "--mode", ""},
+			want:   []string{"fast", "slow"},
+		},
+		"FlagValueWithEquals": {
+			reason: "We should complete the value of a flag joined to it by an equals sign.",
+			words:  []string{"install", "--mode=s"},
+			want:   []string{"--mode=slow"},
+		},
+		"FlagValueSkipped": {
+			reason: "A flag value shouldn't count as a positional argument.",
+			words:  []string{"install", "--mode", "slow", "c"},
+			want:   []string{"configuration"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			app, err := kong.New(&testCLI{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := Complete(context.Background(), app.Model.Node, predictors, tc.words)
+			if diff := cmp.Diff(tc.want, got, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nComplete(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestXRKinds(t *testing.T) {
+	c := &test.MockClient{
+		MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+			l := obj.(*v1.CompositeResourceDefinitionList)
+			l.Items = []v1.CompositeResourceDefinition{
+				{Spec: v1.CompositeResourceDefinitionSpec{
+					Names: extv1.CustomResourceDefinitionNames{Kind: "XBucket"},
+				}},
+				{Spec: v1.CompositeResourceDefinitionSpec{
+					Names:      extv1.CustomResourceDefinitionNames{Kind: "XDatabase"},
+					ClaimNames: &extv1.CustomResourceDefinitionNames{Kind: "Database"},
+				}},
+			}
+			return nil
+		}),
+	}
+	want := []string{"xbucket", "xdatabase", "database"}
+	if diff := cmp.Diff(want, XRKinds(context.Background(), c)); diff != "" {
+		t.Errorf("XRKinds(...): -want, +got:\n%s", diff)
+	}
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package completion contains the shell completion commands.
+package completion
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/alecthomas/kong"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+const (
+	errWriteScript = "cannot write completion script"
+
+	// completeTimeout bounds how long we'll spend querying the cluster for
+	// dynamic completions. Shells block while completing.
+	completeTimeout = 3 * time.Second
+)
+
+// Cmd arguments and flags for the completion subcommand.
+type Cmd struct {
+	Shell string `arg:"" enum:"bash,zsh,fish" help:"The shell to generate a completion script for. One of \"bash\", \"zsh\", or \"fish\"."`
+}
+
+// Help prints out the help for the completion command.
+func (c *Cmd) Help() string {
+	return `
+This command prints a shell completion script. Completions include commands,
+flags, and - when a kubeconfig is available - resources from the cluster, like
+installed packages and composite resource (XR) kinds.
+
+Examples:
+
+  # Load completions into the current bash session.
+  source <(crossplane completion bash)
+
+  # Load completions into every zsh session.
+  crossplane completion zsh > "${fpath[1]}/_crossplane"
+
+  # Load completions into every fish session.
+  crossplane completion fish > ~/.config/fish/completions/crossplane.fish
+`
+}
+
+// Run the completion command.
+func (c *Cmd) Run(k *kong.Context) error {
+	var script string
+	switch c.Shell {
+	case "bash":
+		script = bashScript
+	case "zsh":
+		script = zshScript
+	case "fish":
+		script = fishScript
+	}
+	_, err := fmt.Fprint(k.Stdout, strings.ReplaceAll(script, "{{name}}", k.Model.Name))
+	return errors.Wrap(err, errWriteScript)
+}
+
+// CompleteCmd prints completions for a partial command line. It's called by
+// the scripts printed by the completion command, and isn't intended to be
+// called directly.
+type CompleteCmd struct {
+	Words []string `arg:"" optional:"" help:"The words of the command line being completed, excluding the program name."`
+}
+
+// Run the __complete command.
+func (c *CompleteCmd) Run(k *kong.Context) error {
+	ctx, cancel := context.WithTimeout(context.Background(), completeTimeout)
+	defer cancel()
+	for _, s := range Complete(ctx, k.Model.Node, ClusterPredictors(), c.Words) {
+		fmt.Fprintln(k.Stdout, s)
+	}
+	return nil
+}
+
+// The completion scripts call the hidden __complete command with the words of
+// the command line being completed, including the (possibly empty) word under
+// the cursor. They fall back to file completion when there are no matches.
+
+const bashScript = `# bash completion for {{name}}
+_{{name}}_completions() {
+    local IFS=$'\n'
+    COMPREPLY=($({{name}} __complete -- "${COMP_WORDS[@]:1:$COMP_CWORD}" 2>/dev/null))
+}
+complete -o default -F _{{name}}_completions {{name}}
+`
+
+const zshScript = `#compdef {{name}}
+# zsh completion for {{name}}
+_{{name}}() {
+    local -a completions
+    completions=("${(@f)$({{name}} __complete -- "${(@)words[2,$CURRENT]}" 2>/dev/null)}")
+    if [[ -n "${completions[*]}" ]]; then
+        compadd -a completions
+    else
+        _files
+    fi
+}
+compdef _{{name}} {{name}}
+`
+
+const fishScript = `# fish completion for {{name}}
+function __{{name}}_complete
+    set -l words (commandline -opc)[2..-1] (commandline -ct)
+    {{name}} __complete -- $words 2>/dev/null
+end
+complete -c {{name}} -a '(__{{name}}_complete)'
+`
@@ -18,16 +18,31 @@ limitations under the License.
 package main
 
 import (
+	"flag"
 	"fmt"
+	"os"
 
 	"github.com/alecthomas/kong"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 
 	"github.com/crossplane/crossplane/cmd/crank/beta"
+	"github.com/crossplane/crossplane/cmd/crank/completion"
+	"github.com/crossplane/crossplane/cmd/crank/output"
 	"github.com/crossplane/crossplane/cmd/crank/xpkg"
 	"github.com/crossplane/crossplane/internal/version"
+	cliconfig "github.com/crossplane/crossplane/internal/xpkg/upbound/config"
+)
+
+const (
+	errKubeConfig  = "failed to get kubeconfig"
+	errLoadConfig  = "cannot load CLI config file"
+	errFmtContext  = "cannot use context %q"
+	contextFlagKey = "context"
 )
 
 var _ = kong.Must(&cli)
@@ -56,36 +71,132 @@ func (v verboseFlag) BeforeApply(ctx *kong.Context) error { //nolint:unparam //
 }
 
 // The top-level crossplane CLI.
-var cli struct {
+type crossplaneCLI struct {
 	// Subcommands and flags will appear in the CLI help output in the same
 	// order they're specified here. Keep them in alphabetical order.
 
 	// Subcommands.
-	XPKG xpkg.Cmd `cmd:"" help:"Manage Crossplane packages."`
+	Completion completion.Cmd `cmd:"" help:"Print a shell completion script."`
+	XPKG       xpkg.Cmd       `cmd:"" help:"Manage Crossplane packages."`
+
+	// Called by the completion scripts.
+	Complete completion.CompleteCmd `cmd:"" name:"__complete" hidden:""`
 
 	// The alpha and beta subcommands are intentionally in a separate block. We
 	// want them to appear after all other subcommands.
 	Beta beta.Cmd `cmd:"" help:"Beta commands."`
 
 	// Flags.
-	Verbose verboseFlag `name:"verbose" help:"Print verbose logging statements."`
-	Version versionFlag `short:"v" name:"version" help:"Print version and quit."`
+	Context      string      `placeholder:"NAME" env:"CROSSPLANE_CONTEXT" help:"Context from the CLI config file (~/.crossplane/config.json) to use. A context sets defaults for other flags. Defaults to the config file's current context."`
+	Kubeconfig   string      `placeholder:"PATH" help:"Path to the kubeconfig file used to connect to the control plane."`
+	KubeContext  string      `placeholder:"NAME" help:"Kubeconfig context used to connect to the control plane."`
+	OutputFormat string      `enum:"text,json" default:"text" help:"Format of messages, warnings, and errors. One of: text, json."`
+	Verbose      verboseFlag `name:"verbose" help:"Print verbose logging statements."`
+	Version      versionFlag `short:"v" name:"version" help:"Print version and quit."`
+}
+
+var cli crossplaneCLI
+
+// BeforeResolve loads the selected context from the CLI config file before
+// kong resolves any flags. An unknown context is reported once, as a --context
+// error, rather than on whichever flag kong happens to resolve first.
+func (c *crossplaneCLI) BeforeResolve(k *kong.Context, r *contextResolver) error {
+	name := ""
+	for _, f := range k.Flags() {
+		if f.Name == contextFlagKey {
+			name, _ = k.FlagValue(f).(string)
+		}
+	}
+	flags, err := contextFlags(name)
+	if err != nil {
+		return errors.Wrap(err, "--"+contextFlagKey)
+	}
+	r.flags = flags
+	return nil
+}
+
+// A contextResolver resolves the values of flags that weren't specified from
+// the selected context in the CLI config file.
+type contextResolver struct {
+	// Flags set by the selected context. Loaded by BeforeResolve.
+	flags map[string]string
+}
+
+// Validate does nothing. It's required to satisfy kong.Resolver.
+func (r *contextResolver) Validate(_ *kong.Application) error { return nil }
+
+// Resolve the value of the supplied flag from the selected context.
+func (r *contextResolver) Resolve(_ *kong.Context, _ *kong.Path, f *kong.Flag) (any, error) {
+	if f.Name == contextFlagKey {
+		return nil, nil
+	}
+	if v, ok := r.flags[f.Name]; ok {
+		return v, nil
+	}
+	return nil, nil
+}
+
+// contextFlags returns the flag values set by the named context, or by the
+// current context if name is empty.
+func contextFlags(name string) (map[string]string, error) {
+	p, err := cliconfig.GetDefaultPath()
+	if err != nil {
+		return nil, errors.Wrap(err, errLoadConfig)
+	}
+	cfg, err := cliconfig.Extract(cliconfig.NewFSSource(cliconfig.WithPath(p)))
+	if os.IsNotExist(err) {
+		cfg, err = &cliconfig.Config{}, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errLoadConfig)
+	}
+	c, err := cfg.GetContext(name)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtContext, name)
+	}
+	return c.Flags(), nil
+}
+
+// kubeConfig returns the config used to connect to the control plane. Commands
+// that connect to the control plane accept a *rest.Config argument.
+func kubeConfig() (*rest.Config, error) {
+	if cli.Kubeconfig != "" {
+		// controller-runtime reads the kubeconfig path from its flag.
+		if err := flag.CommandLine.Set(config.KubeconfigFlagName, cli.Kubeconfig); err != nil {
+			return nil, errors.Wrap(err, errKubeConfig)
+		}
+	}
+	cfg, err := config.GetConfigWithContext(cli.KubeContext)
+	return cfg, errors.Wrap(err, errKubeConfig)
+}
+
+// printer returns the printer used to print messages, warnings, and errors.
+// Commands that print them accept a *output.Printer argument.
+func printer() *output.Printer {
+	return output.NewPrinter(output.Format(cli.OutputFormat), "crossplane", os.Stdout, os.Stderr)
 }
 
 func main() {
 	logger := logging.NewNopLogger()
+	resolver := &contextResolver{}
 	ctx := kong.Parse(&cli,
 		kong.Name("crossplane"),
 		kong.Description("A command line tool for interacting with Crossplane."),
 		// Binding a variable to kong context makes it available to all commands
 		// at runtime.
 		kong.BindTo(logger, (*logging.Logger)(nil)),
+		kong.BindToProvider(kubeConfig),
+		kong.BindToProvider(func() (*output.Printer, error) { return printer(), nil }),
+		kong.Bind(resolver),
+		kong.Resolvers(resolver),
 		kong.ConfigureHelp(kong.HelpOptions{
 			FlagsLast:      true,
 			Compact:        true,
 			WrapUpperBound: 80,
 		}),
 		kong.UsageOnError())
-	err := ctx.Run()
-	ctx.FatalIfErrorf(err)
+	if err := ctx.Run(); err != nil {
+		printer().Error(err)
+		ctx.Exit(1)
+	}
 }
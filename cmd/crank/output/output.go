@@ -0,0 +1,161 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package output prints the crossplane CLI's messages, warnings, and errors in
+// a consistent format.
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// A Format of output.
+type Format string
+
+// Output formats.
+const (
+	// FormatText is human readable text.
+	FormatText Format = "text"
+
+	// FormatJSON is one JSON object per line.
+	FormatJSON Format = "json"
+)
+
+// A Level of message.
+type Level string
+
+// Message levels.
+const (
+	LevelInfo    Level = "info"
+	LevelWarning Level = "warning"
+	LevelError   Level = "error"
+)
+
+// A Message printed by the CLI.
+type Message struct {
+	Level   Level  `json:"level"`
+	Message string `json:"message"`
+
+	// Hint is an optional suggestion for how to address a warning or error.
+	Hint string `json:"hint,omitempty"`
+}
+
+// A Printer prints messages. Informational messages are printed to stdout,
+// while warnings and errors are printed to stderr. Commands that print other
+// output, like rendered resources, print it to stdout as usual.
+type Printer struct {
+	format Format
+	stdout io.Writer
+	stderr io.Writer
+	prefix string
+}
+
+// NewPrinter returns a Printer that prints messages in the supplied format.
+// Errors printed as text are prefixed with the supplied program name.
+func NewPrinter(f Format, program string, stdout, stderr io.Writer) *Printer {
+	return &Printer{format: f, stdout: stdout, stderr: stderr, prefix: program}
+}
+
+// Format returns the format the Printer prints messages in.
+func (p *Printer) Format() Format {
+	return p.format
+}
+
+// Infof prints an informational message, for example the result of a command.
+func (p *Printer) Infof(format string, args ...any) {
+	m := Message{Level: LevelInfo, Message: fmt.Sprintf(format, args...)}
+	if p.format == FormatJSON {
+		p.printJSON(p.stdout, m)
+		return
+	}
+	fmt.Fprintln(p.stdout, m.Message)
+}
+
+// Warnf prints a warning.
+func (p *Printer) Warnf(format string, args ...any) {
+	m := Message{Level: LevelWarning, Message: fmt.Sprintf(format, args...)}
+	if p.format == FormatJSON {
+		p.printJSON(p.stderr, m)
+		return
+	}
+	fmt.Fprintf(p.stderr, "WARN: %s\n", m.Message)
+}
+
+// Error prints an error, including its hint if it has one.
+func (p *Printer) Error(err error) {
+	m := Message{Level: LevelError, Message: err.Error()}
+	h := &hintedError{}
+	if errors.As(err, &h) {
+		m.Hint = h.hint
+	}
+	if p.format == FormatJSON {
+		p.printJSON(p.stderr, m)
+		return
+	}
+	fmt.Fprintf(p.stderr, "%s: error: %s\n", p.prefix, m.Message)
+}
+
+// InfoWriter returns a writer that prints each line written to it as an
+// informational message. It lets packages that write progress to an io.Writer
+// print it consistently with the rest of the CLI's messages.
+func (p *Printer) InfoWriter() io.Writer {
+	return infoWriter{p: p}
+}
+
+type infoWriter struct {
+	p *Printer
+}
+
+func (w infoWriter) Write(b []byte) (int, error) {
+	for _, line := range strings.Split(strings.TrimSuffix(string(b), "\n"), "\n") {
+		w.p.Infof("%s", line)
+	}
+	return len(b), nil
+}
+
+func (p *Printer) printJSON(w io.Writer, m Message) {
+	// Marshalling a Message can't fail.
+	b, _ := json.Marshal(m)
+	fmt.Fprintln(w, string(b))
+}
+
+// WithHint returns an error that includes the supplied hint. A nil error
+// remains nil. Printed as text the error is prefixed with the hint. Printed as
+// JSON the hint is a separate field.
+func WithHint(err error, format string, args ...any) error {
+	if err == nil {
+		return nil
+	}
+	return &hintedError{err: err, hint: fmt.Sprintf(format, args...)}
+}
+
+type hintedError struct {
+	err  error
+	hint string
+}
+
+func (e *hintedError) Error() string {
+	return e.hint + ": " + e.err.Error()
+}
+
+func (e *hintedError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+func TestPrinter(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type args struct {
+		format Format
+		print  func(p *Printer)
+	}
+	type want struct {
+		stdout string
+		stderr string
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"InfoText": {
+			reason: "Informational messages should be printed to stdout as is.",
+			args: args{
+				format: FormatText,
+				print:  func(p *Printer) { p.Infof("%s created", "provider/cool") },
+			},
+			want: want{
+				stdout: "provider/cool created\n",
+			},
+		},
+		"InfoJSON": {
+			reason: "Informational messages should be printed to stdout as a JSON object.",
+			args: args{
+				format: FormatJSON,
+				print:  func(p *Printer) { p.Infof("%s created", "provider/cool") },
+			},
+			want: want{
+				stdout: `{"level":"info","message":"provider/cool created"}` + "\n",
+			},
+		},
+		"InfoWriterJSON": {
+			reason: "Each line written to an info writer should be printed as an informational message.",
+			args: args{
+				format: FormatJSON,
+				print:  func(p *Printer) { _, _ = p.InfoWriter().Write([]byte("downloading\ndone\n")) },
+			},
+			want: want{
+				stdout: `{"level":"info","message":"downloading"}` + "\n" + `{"level":"info","message":"done"}` + "\n",
+			},
+		},
+		"WarnText": {
+			reason: "Warnings should be printed to stderr with a prefix.",
+			args: args{
+				format: FormatText,
+				print:  func(p *Printer) { p.Warnf("careful") },
+			},
+			want: want{
+				stderr: "WARN: careful\n",
+			},
+		},
+		"WarnJSON": {
+			reason: "Warnings should be printed to stderr as a JSON object.",
+			args: args{
+				format: FormatJSON,
+				print:  func(p *Printer) { p.Warnf("careful") },
+			},
+			want: want{
+				stderr: `{"level":"warning","message":"careful"}` + "\n",
+			},
+		},
+		"ErrorText": {
+			reason: "Errors should be printed to stderr prefixed with the program name.",
+			args: args{
+				format: FormatText,
+				print:  func(p *Printer) { p.Error(errors.Wrap(errBoom, "cannot frob")) },
+			},
+			want: want{
+				stderr: "crossplane: error: cannot frob: boom\n",
+			},
+		},
+		"ErrorWithHintText": {
+			reason: "Errors with a hint should include the hint in their message.",
+			args: args{
+				format: FormatText,
+				print:  func(p *Printer) { p.Error(errors.Wrap(WithHint(errBoom, "try %s", "again"), "cannot frob")) },
+			},
+			want: want{
+				stderr: "crossplane: error: cannot frob: try again: boom\n",
+			},
+		},
+		"ErrorWithHintJSON": {
+			reason: "Errors with a hint should be printed to stderr as a JSON object with a separate hint.",
+			args: args{
+				format: FormatJSON,
+				print:  func(p *Printer) { p.Error(errors.Wrap(WithHint(errBoom, "try %s", "again"), "cannot frob")) },
+			},
+			want: want{
+				stderr: `{"level":"error","message":"cannot frob: try again: boom","hint":"try again"}` + "\n",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+			tc.args.print(NewPrinter(tc.args.format, "crossplane", stdout, stderr))
+
+			if diff := cmp.Diff(tc.want.stdout, stdout.String()); diff != "" {
+				t.Errorf("\n%s\n-want stdout, +got stdout:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.stderr, stderr.String()); diff != "" {
+				t.Errorf("\n%s\n-want stderr, +got stderr:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestWithHint(t *testing.T) {
+	if err := WithHint(nil, "hint"); err != nil {
+		t.Errorf("WithHint(nil, ...): want nil, got %v", err)
+	}
+}
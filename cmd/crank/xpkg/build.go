@@ -32,6 +32,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/parser"
 
+	"github.com/crossplane/crossplane/cmd/crank/output"
 	"github.com/crossplane/crossplane/internal/xpkg"
 	"github.com/crossplane/crossplane/internal/xpkg/parser/examples"
 	"github.com/crossplane/crossplane/internal/xpkg/parser/yaml"
@@ -166,7 +167,7 @@ func (c *buildCmd) GetOutputFileName(meta runtime.Object, hash v1.Hash) (string,
 }
 
 // Run executes the build command.
-func (c *buildCmd) Run(logger logging.Logger) error {
+func (c *buildCmd) Run(logger logging.Logger, p *output.Printer) error {
 	var buildOpts []xpkg.BuildOpt
 	rtBuildOpts, err := c.GetRuntimeBaseImageOpts()
 	if err != nil {
@@ -179,6 +180,12 @@ func (c *buildCmd) Run(logger logging.Logger) error {
 		return errors.Wrap(err, errBuildPackage)
 	}
 
+	// Packages built before licenses were checked may declare a license
+	// that isn't a valid SPDX license expression, so we only warn.
+	if err := xpkg.PackageValidLicense(meta); err != nil {
+		p.Warnf("%s. Crossplane won't install this package if it enforces a license allowlist.", err)
+	}
+
 	hash, err := img.Digest()
 	if err != nil {
 		return errors.Wrap(err, errImageDigest)
@@ -18,18 +18,16 @@ package xpkg
 
 import (
 	"context"
-	"fmt"
 	"net/http"
 	"time"
 
-	"github.com/alecthomas/kong"
 	"github.com/google/go-containerregistry/pkg/name"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
-	ctrl "sigs.k8s.io/controller-runtime"
+	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
@@ -37,6 +35,7 @@ import (
 
 	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
 	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+	"github.com/crossplane/crossplane/cmd/crank/output"
 	"github.com/crossplane/crossplane/internal/version"
 	"github.com/crossplane/crossplane/internal/xpkg"
 
@@ -46,7 +45,6 @@ import (
 
 const (
 	errPkgIdentifier = "invalid package image identifier"
-	errKubeConfig    = "failed to get kubeconfig"
 	errKubeClient    = "failed to create kube client"
 )
 
@@ -58,6 +56,7 @@ type installCmd struct {
 	Name    string `arg:""  optional:"" help:"The name of the new package in the Crossplane API. Derived from the package repository and tag by default."`
 
 	// Flags. Keep sorted alphabetically.
+	DefaultRegistry      string        `placeholder:"REGISTRY" help:"Registry to install the package from if it doesn't specify one. Defaults to the control plane's default registry."`
 	RuntimeConfig        string        `placeholder:"NAME" help:"Install the package with a runtime configuration (for example a DeploymentRuntimeConfig)."`
 	ManualActivation     bool          `short:"m" help:"Require the new package's first revision to be manually activated."`
 	PackagePullSecrets   []string      `placeholder:"NAME" help:"A comma-separated list of secrets the package manager should use to pull the package from the registry."`
@@ -69,7 +68,7 @@ func (c *installCmd) Help() string {
 	return `
 This command installs a package in a Crossplane control plane. It uses
 ~/.kube/config to connect to the control plane. You can override this using the
-KUBECONFIG environment variable.
+KUBECONFIG environment variable, or the --kubeconfig and --kube-context flags.
 
 Examples:
 
@@ -83,11 +82,31 @@ Examples:
 `
 }
 
+// qualifiedSource returns the supplied package source, qualified with the
+// supplied registry if it doesn't specify one. It returns the source as is if
+// no registry is supplied, leaving the control plane to apply its default.
+func qualifiedSource(pkg, registry string) (string, error) {
+	if registry == "" {
+		return pkg, nil
+	}
+	ref, err := name.ParseReference(pkg, name.WithDefaultRegistry(registry))
+	if err != nil {
+		return "", err
+	}
+	return ref.Name(), nil
+}
+
 // Run the package install cmd.
-func (c *installCmd) Run(k *kong.Context, logger logging.Logger) error { //nolint:gocyclo // TODO(negz): Can anything be broken out here?
+func (c *installCmd) Run(logger logging.Logger, cfg *rest.Config, p *output.Printer) error { //nolint:gocyclo // TODO(negz): Can anything be broken out here?
+	source, err := qualifiedSource(c.Package, c.DefaultRegistry)
+	if err != nil {
+		logger.Debug(errPkgIdentifier, "error", err)
+		return errors.Wrap(err, errPkgIdentifier)
+	}
+
 	pkgName := c.Name
 	if pkgName == "" {
-		ref, err := name.ParseReference(c.Package, name.WithDefaultRegistry(xpkg.DefaultRegistry))
+		ref, err := name.ParseReference(source, name.WithDefaultRegistry(xpkg.DefaultRegistry))
 		if err != nil {
 			logger.Debug(errPkgIdentifier, "error", err)
 			return errors.Wrap(err, errPkgIdentifier)
@@ -97,7 +116,7 @@ func (c *installCmd) Run(k *kong.Context, logger logging.Logger) error { //nolin
 
 	logger = logger.WithValues(
 		"kind", c.Kind,
-		"ref", c.Package,
+		"ref", source,
 		"name", pkgName,
 	)
 
@@ -113,7 +132,7 @@ func (c *installCmd) Run(k *kong.Context, logger logging.Logger) error { //nolin
 	}
 
 	spec := v1.PackageSpec{
-		Package:                  c.Package,
+		Package:                  source,
 		RevisionActivationPolicy: &rap,
 		RevisionHistoryLimit:     &c.RevisionHistoryLimit,
 		PackagePullSecrets:       secrets,
@@ -149,12 +168,6 @@ func (c *installCmd) Run(k *kong.Context, logger logging.Logger) error { //nolin
 		rpkg.SetRuntimeConfigRef(&v1.RuntimeConfigReference{Name: c.RuntimeConfig})
 	}
 
-	cfg, err := ctrl.GetConfig()
-	if err != nil {
-		return errors.Wrap(err, errKubeConfig)
-	}
-	logger.Debug("Found kubeconfig")
-
 	s := runtime.NewScheme()
 	_ = v1.AddToScheme(s)
 	_ = v1beta1.AddToScheme(s)
@@ -203,8 +216,8 @@ func (c *installCmd) Run(k *kong.Context, logger logging.Logger) error { //nolin
 
 	}
 
-	_, err = fmt.Fprintf(k.Stdout, "%s/%s created\n", c.Kind, pkg.GetName())
-	return err
+	p.Infof("%s/%s created", c.Kind, pkg.GetName())
+	return nil
 }
 
 // TODO(negz): What is this trying to do? My guess is its trying to handle the
@@ -219,5 +232,5 @@ func warnIfNotFound(err error) error {
 	if serr.ErrStatus.Code != http.StatusNotFound {
 		return err
 	}
-	return errors.WithMessagef(err, "crossplane CLI (version %s) might be out of date", version.New().GetVersionString())
+	return output.WithHint(err, "crossplane CLI (version %s) might be out of date", version.New().GetVersionString())
 }
@@ -0,0 +1,72 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestQualifiedSource(t *testing.T) {
+	type args struct {
+		pkg      string
+		registry string
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   string
+	}{
+		"NoRegistry": {
+			reason: "The package should be returned as is if no registry is supplied.",
+			args: args{
+				pkg: "crossplane/provider-example:v1.0.0",
+			},
+			want: "crossplane/provider-example:v1.0.0",
+		},
+		"Unqualified": {
+			reason: "A package that doesn't specify a registry should be qualified with the supplied registry.",
+			args: args{
+				pkg:      "crossplane/provider-example:v1.0.0",
+				registry: "registry.example.org",
+			},
+			want: "registry.example.org/crossplane/provider-example:v1.0.0",
+		},
+		"Qualified": {
+			reason: "A package that specifies a registry should keep it.",
+			args: args{
+				pkg:      "xpkg.upbound.io/crossplane/provider-example:v1.0.0",
+				registry: "registry.example.org",
+			},
+			want: "xpkg.upbound.io/crossplane/provider-example:v1.0.0",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := qualifiedSource(tc.args.pkg, tc.args.registry)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nqualifiedSource(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
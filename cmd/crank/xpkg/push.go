@@ -21,11 +21,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
 	"github.com/google/go-containerregistry/pkg/v1/mutate"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/google/go-containerregistry/pkg/v1/tarball"
@@ -43,6 +45,8 @@ const (
 	errGetwd           = "failed to get working directory while searching for package"
 	errFindPackageinWd = "failed to find a package in current working directory"
 	errAnnotateLayers  = "failed to propagate xpkg annotations from OCI image config file to image layers"
+	errOpenOCILayout   = "failed to open OCI image layout"
+	errMultiArchive    = "docker-archive output supports only a single package file; use oci-layout output for multi-platform packages"
 
 	errFmtNewTag        = "failed to parse package tag %q"
 	errFmtReadPackage   = "failed to read package file %s"
@@ -52,6 +56,8 @@ const (
 	errFmtGetMediaType  = "failed to get media type of package file %s"
 	errFmtGetConfigFile = "failed to get OCI config file of package file %s"
 	errFmtWriteIndex    = "failed to push an OCI image index of %d packages"
+	errFmtInvalidOutput = "invalid output %q: must be oci-layout:DIR or docker-archive:FILE"
+	errFmtWriteOutput   = "failed to write package to %s"
 )
 
 // pushCmd pushes a package.
@@ -60,7 +66,9 @@ type pushCmd struct {
 	Package string `arg:"" help:"Where to push the package."`
 
 	// Flags. Keep sorted alphabetically.
-	PackageFiles []string `short:"f" type:"existingfile" placeholder:"PATH" help:"A comma-separated list of xpkg files to push."`
+	DefaultRegistry string   `default:"xpkg.upbound.io" placeholder:"REGISTRY" help:"Registry to push to if the package doesn't specify one."`
+	Output          string   `short:"o" placeholder:"TARGET" help:"Write the package to a local target instead of pushing it to a registry. One of oci-layout:DIR or docker-archive:FILE."`
+	PackageFiles    []string `short:"f" type:"existingfile" placeholder:"PATH" help:"A comma-separated list of xpkg files to push."`
 
 	// Internal state. These aren't part of the user-exposed CLI structure.
 	fs afero.Fs
@@ -73,6 +81,12 @@ xpkg.upbound.io registry by default. A package's OCI tag must be a semantic
 version. Credentials for the registry are automatically retrieved from xpkg login 
 and dockers configuration as fallback.
 
+Use --output to write the package to a local OCI image layout directory or
+Docker archive instead of pushing it. This is useful to transfer packages to
+air-gapped environments, or to cache them in CI. An OCI image layout may hold
+many packages, and supports multi-platform packages. A Docker archive holds a
+single platform package.
+
 Examples:
 
   # Push a multi-platform package.
@@ -80,6 +94,13 @@ Examples:
 
   # Push the xpkg file in the current directory to a different registry.
   crossplane xpkg push index.docker.io/crossplane/function-example:v1.0.0
+
+  # Write a multi-platform package to an OCI image layout directory.
+  crossplane xpkg push -f function-amd64.xpkg,function-arm64.xpkg crossplane/function-example:v1.0.0 \
+    --output=oci-layout:./layout
+
+  # Write a package to a Docker archive.
+  crossplane xpkg push crossplane/function-example:v1.0.0 --output=docker-archive:function.tar
 `
 }
 
@@ -91,11 +112,23 @@ func (c *pushCmd) AfterApply() error {
 
 // Run runs the push cmd.
 func (c *pushCmd) Run(logger logging.Logger) error { //nolint:gocyclo // This feels easier to read as-is.
-	tag, err := name.NewTag(c.Package, name.WithDefaultRegistry(xpkg.DefaultRegistry))
+	tag, err := name.NewTag(c.Package, name.WithDefaultRegistry(c.DefaultRegistry))
 	if err != nil {
 		return errors.Wrapf(err, errFmtNewTag, c.Package)
 	}
 
+	var out *outputTarget
+	if c.Output != "" {
+		o, err := parseOutput(c.Output)
+		if err != nil {
+			return err
+		}
+		if o.Type == outputDockerArchive && len(c.PackageFiles) > 1 {
+			return errors.New(errMultiArchive)
+		}
+		out = &o
+	}
+
 	// If package is not defined, attempt to find single package in current
 	// directory.
 	if len(c.PackageFiles) == 0 {
@@ -126,6 +159,13 @@ func (c *pushCmd) Run(logger logging.Logger) error { //nolint:gocyclo // This fe
 		if err != nil {
 			return errors.Wrapf(err, errAnnotateLayers)
 		}
+		if out != nil {
+			if err := out.WriteImage(tag, img); err != nil {
+				return errors.Wrapf(err, errFmtWriteOutput, c.Output)
+			}
+			logger.Debug("Wrote package", "path", c.PackageFiles[0], "ref", tag.String(), "output", c.Output)
+			return nil
+		}
 		if err := remote.Write(tag, img, remote.WithAuthFromKeychain(kc)); err != nil {
 			return errors.Wrapf(err, errFmtPushPackage, c.PackageFiles[0])
 		}
@@ -135,7 +175,8 @@ func (c *pushCmd) Run(logger logging.Logger) error { //nolint:gocyclo // This fe
 
 	// If there's more than one package file we'll write (push) them all by
 	// their digest, and create an index with the specified tag. This pattern is
-	// typically used to create a multi-platform image.
+	// typically used to create a multi-platform image. When writing to a local
+	// output the images are written along with the index instead.
 	adds := make([]mutate.IndexAddendum, len(c.PackageFiles))
 	g, ctx := errgroup.WithContext(context.Background())
 	for i, file := range c.PackageFiles {
@@ -156,7 +197,7 @@ func (c *pushCmd) Run(logger logging.Logger) error { //nolint:gocyclo // This fe
 				return errors.Wrapf(err, errFmtGetDigest, file)
 			}
 			n := fmt.Sprintf("%s@%s", tag.Repository.Name(), d.String())
-			ref, err := name.NewDigest(n, name.WithDefaultRegistry(xpkg.DefaultRegistry))
+			ref, err := name.NewDigest(n, name.WithDefaultRegistry(c.DefaultRegistry))
 			if err != nil {
 				return errors.Wrapf(err, errFmtNewDigest, n, file)
 			}
@@ -182,6 +223,9 @@ func (c *pushCmd) Run(logger logging.Logger) error { //nolint:gocyclo // This fe
 					},
 				},
 			}
+			if out != nil {
+				return nil
+			}
 			if err := remote.Write(ref, img, remote.WithAuthFromKeychain(kc), remote.WithContext(ctx)); err != nil {
 				return errors.Wrapf(err, errFmtPushPackage, file)
 			}
@@ -194,9 +238,87 @@ func (c *pushCmd) Run(logger logging.Logger) error { //nolint:gocyclo // This fe
 		return err
 	}
 
-	if err := remote.WriteIndex(tag, mutate.AppendManifests(empty.Index, adds...), remote.WithAuthFromKeychain(kc)); err != nil {
+	idx := mutate.AppendManifests(empty.Index, adds...)
+	if out != nil {
+		if err := out.WriteIndex(tag, idx); err != nil {
+			return errors.Wrapf(err, errFmtWriteOutput, c.Output)
+		}
+		logger.Debug("Wrote OCI index", "ref", tag.String(), "manifests", len(adds), "output", c.Output)
+		return nil
+	}
+	if err := remote.WriteIndex(tag, idx, remote.WithAuthFromKeychain(kc)); err != nil {
 		return errors.Wrapf(err, errFmtWriteIndex, len(adds))
 	}
 	logger.Debug("Wrote OCI index", "ref", tag.String(), "manifests", len(adds))
 	return nil
 }
+
+// Supported local output types.
+const (
+	outputOCILayout     = "oci-layout"
+	outputDockerArchive = "docker-archive"
+)
+
+// annotationRefName is the OCI image layout annotation that names a manifest.
+const annotationRefName = "org.opencontainers.image.ref.name"
+
+// An outputTarget is a local target a package can be written to instead of a
+// registry.
+type outputTarget struct {
+	// Type of output - either oci-layout or docker-archive.
+	Type string
+
+	// Path to the OCI image layout directory or Docker archive file.
+	Path string
+}
+
+// parseOutput parses an output target of the form TYPE:PATH.
+func parseOutput(s string) (outputTarget, error) {
+	t, path, ok := strings.Cut(s, ":")
+	if !ok || path == "" {
+		return outputTarget{}, errors.Errorf(errFmtInvalidOutput, s)
+	}
+	switch t {
+	case outputOCILayout, outputDockerArchive:
+		return outputTarget{Type: t, Path: path}, nil
+	default:
+		return outputTarget{}, errors.Errorf(errFmtInvalidOutput, s)
+	}
+}
+
+// WriteImage writes the supplied image to the output target. Images written
+// to an OCI image layout are appended to any it already contains. Docker
+// archives are overwritten.
+func (o outputTarget) WriteImage(tag name.Tag, img v1.Image) error {
+	if o.Type == outputDockerArchive {
+		return tarball.WriteToFile(o.Path, tag, img)
+	}
+	p, err := ociLayout(o.Path)
+	if err != nil {
+		return err
+	}
+	return p.AppendImage(img, layout.WithAnnotations(map[string]string{annotationRefName: tag.String()}))
+}
+
+// WriteIndex writes the supplied image index to the output target. Only OCI
+// image layouts support image indexes.
+func (o outputTarget) WriteIndex(tag name.Tag, idx v1.ImageIndex) error {
+	if o.Type != outputOCILayout {
+		return errors.New(errMultiArchive)
+	}
+	p, err := ociLayout(o.Path)
+	if err != nil {
+		return err
+	}
+	return p.AppendIndex(idx, layout.WithAnnotations(map[string]string{annotationRefName: tag.String()}))
+}
+
+// ociLayout opens the OCI image layout at the supplied path, creating it if it
+// doesn't exist.
+func ociLayout(path string) (layout.Path, error) {
+	if p, err := layout.FromPath(path); err == nil {
+		return p, nil
+	}
+	p, err := layout.Write(path, empty.Index)
+	return p, errors.Wrap(err, errOpenOCILayout)
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+)
+
+func TestParseOutput(t *testing.T) {
+	type want struct {
+		out outputTarget
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		s      string
+		want   want
+	}{
+		"OCILayout": {
+			reason: "We should parse an OCI image layout output.",
+			s:      "oci-layout:/tmp/layout",
+			want: want{
+				out: outputTarget{Type: outputOCILayout, Path: "/tmp/layout"},
+			},
+		},
+		"DockerArchive": {
+			reason: "We should parse a Docker archive output.",
+			s:      "docker-archive:function.tar",
+			want: want{
+				out: outputTarget{Type: outputDockerArchive, Path: "function.tar"},
+			},
+		},
+		"MissingPath": {
+			reason: "We should return an error if the output has no path.",
+			s:      "oci-layout:",
+			want: want{
+				err: cmpopts.AnyError,
+			},
+		},
+		"MissingType": {
+			reason: "We should return an error if the output has no type.",
+			s:      "function.tar",
+			want: want{
+				err: cmpopts.AnyError,
+			},
+		},
+		"UnknownType": {
+			reason: "We should return an error if the output type is unknown.",
+			s:      "registry:example.org",
+			want: want{
+				err: cmpopts.AnyError,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			out, err := parseOutput(tc.s)
+			if diff := cmp.Diff(tc.want.out, out); diff != "" {
+				t.Errorf("\n%s\nparseOutput(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nparseOutput(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestOutputTargetWriteImage(t *testing.T) {
+	tag, err := name.NewTag("xpkg.upbound.io/crossplane/function-example:v1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := random.Image(64, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("OCILayout", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "layout")
+		o := outputTarget{Type: outputOCILayout, Path: dir}
+
+		// Writing twice should append to the existing layout.
+		for i := 0; i < 2; i++ {
+			if err := o.WriteImage(tag, img); err != nil {
+				t.Fatalf("o.WriteImage(...): %v", err)
+			}
+		}
+
+		p, err := layout.FromPath(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		idx, err := p.ImageIndex()
+		if err != nil {
+			t.Fatal(err)
+		}
+		m, err := idx.IndexManifest()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := make([]v1.Hash, 0, len(m.Manifests))
+		for _, d := range m.Manifests {
+			got = append(got, d.Digest)
+			if d.Annotations[annotationRefName] != tag.String() {
+				t.Errorf("o.WriteImage(...): want ref name annotation %q, got %q", tag.String(), d.Annotations[annotationRefName])
+			}
+		}
+		if diff := cmp.Diff([]v1.Hash{want, want}, got); diff != "" {
+			t.Errorf("o.WriteImage(...): -want digests, +got digests:\n%s", diff)
+		}
+	})
+
+	t.Run("DockerArchive", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "function.tar")
+		o := outputTarget{Type: outputDockerArchive, Path: file}
+		if err := o.WriteImage(tag, img); err != nil {
+			t.Fatalf("o.WriteImage(...): %v", err)
+		}
+
+		got, err := tarball.ImageFromPath(file, &tag)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d, err := got.Digest()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, d); diff != "" {
+			t.Errorf("o.WriteImage(...): -want digest, +got digest:\n%s", diff)
+		}
+	})
+}
+
+func TestOutputTargetWriteIndex(t *testing.T) {
+	tag, err := name.NewTag("xpkg.upbound.io/crossplane/function-example:v1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := random.Image(64, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idx := mutate.AppendManifests(empty.Index, mutate.IndexAddendum{Add: img})
+	want, err := idx.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("OCILayout", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "layout")
+		o := outputTarget{Type: outputOCILayout, Path: dir}
+		if err := o.WriteIndex(tag, idx); err != nil {
+			t.Fatalf("o.WriteIndex(...): %v", err)
+		}
+
+		p, err := layout.FromPath(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := p.ImageIndex()
+		if err != nil {
+			t.Fatal(err)
+		}
+		m, err := got.IndexManifest()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(m.Manifests) != 1 {
+			t.Fatalf("o.WriteIndex(...): want 1 manifest, got %d", len(m.Manifests))
+		}
+		if diff := cmp.Diff(want, m.Manifests[0].Digest); diff != "" {
+			t.Errorf("o.WriteIndex(...): -want digest, +got digest:\n%s", diff)
+		}
+	})
+
+	t.Run("DockerArchive", func(t *testing.T) {
+		o := outputTarget{Type: outputDockerArchive, Path: filepath.Join(t.TempDir(), "function.tar")}
+		if err := o.WriteIndex(tag, idx); err == nil {
+			t.Errorf("o.WriteIndex(...): want error writing an index to a Docker archive, got nil")
+		}
+	})
+}
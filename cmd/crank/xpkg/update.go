@@ -18,15 +18,13 @@ package xpkg
 
 import (
 	"context"
-	"fmt"
 	"time"
 
-	"github.com/alecthomas/kong"
 	"github.com/google/go-containerregistry/pkg/name"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/util/retry"
-	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
@@ -34,6 +32,7 @@ import (
 
 	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
 	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+	"github.com/crossplane/crossplane/cmd/crank/output"
 	"github.com/crossplane/crossplane/internal/xpkg"
 
 	_ "k8s.io/client-go/plugin/pkg/client/auth" // Load all the auth plugins for the cloud providers.
@@ -44,14 +43,17 @@ type updateCmd struct {
 	// Arguments.
 	Kind    string `arg:"" help:"The kind of package to update. One of \"provider\", \"configuration\", or \"function\"." enum:"provider,configuration,function"`
 	Package string `arg:"" help:"The package to update to."`
-	Name    string `arg:""  optional:"" help:"The name of the package to update in the Crossplane API. Derived from the package repository and tag by default."`
+	Name    string `arg:""  optional:"" predictor:"package" help:"The name of the package to update in the Crossplane API. Derived from the package repository and tag by default."`
+
+	// Flags. Keep sorted alphabetically.
+	DefaultRegistry string `placeholder:"REGISTRY" help:"Registry to update the package from if it doesn't specify one. Defaults to the control plane's default registry."`
 }
 
 func (c *updateCmd) Help() string {
 	return `
 This command updates a package in a Crossplane control plane. It uses
 ~/.kube/config to connect to the control plane. You can override this using the
-KUBECONFIG environment variable.
+KUBECONFIG environment variable, or the --kubeconfig and --kube-context flags.
 
 Examples:
 
@@ -61,10 +63,16 @@ Examples:
 }
 
 // Run the package update cmd.
-func (c *updateCmd) Run(k *kong.Context, logger logging.Logger) error {
+func (c *updateCmd) Run(logger logging.Logger, cfg *rest.Config, p *output.Printer) error {
+	source, err := qualifiedSource(c.Package, c.DefaultRegistry)
+	if err != nil {
+		logger.Debug(errPkgIdentifier, "error", err)
+		return errors.Wrap(err, errPkgIdentifier)
+	}
+
 	pkgName := c.Name
 	if pkgName == "" {
-		ref, err := name.ParseReference(c.Package, name.WithDefaultRegistry(xpkg.DefaultRegistry))
+		ref, err := name.ParseReference(source, name.WithDefaultRegistry(xpkg.DefaultRegistry))
 		if err != nil {
 			logger.Debug(errPkgIdentifier, "error", err)
 			return errors.Wrap(err, errPkgIdentifier)
@@ -74,7 +82,7 @@ func (c *updateCmd) Run(k *kong.Context, logger logging.Logger) error {
 
 	logger = logger.WithValues(
 		"kind", c.Kind,
-		"ref", c.Package,
+		"ref", source,
 		"name", pkgName,
 	)
 
@@ -91,12 +99,6 @@ func (c *updateCmd) Run(k *kong.Context, logger logging.Logger) error {
 		return errors.Errorf("unsupported package kind %q", c.Kind)
 	}
 
-	cfg, err := ctrl.GetConfig()
-	if err != nil {
-		return errors.Wrap(err, errKubeConfig)
-	}
-	logger.Debug("Found kubeconfig")
-
 	s := runtime.NewScheme()
 	_ = v1.AddToScheme(s)
 	_ = v1beta1.AddToScheme(s)
@@ -116,13 +118,13 @@ func (c *updateCmd) Run(k *kong.Context, logger logging.Logger) error {
 		}
 		logger.Debug("Found existing package")
 
-		pkg.SetSource(c.Package)
+		pkg.SetSource(source)
 
 		return kube.Update(ctx, pkg)
 	}); err != nil {
 		return errors.Wrapf(err, "cannot update %s/%s", c.Kind, pkg.GetName())
 	}
 
-	_, err = fmt.Fprintf(k.Stdout, "%s/%s updated\n", c.Kind, pkg.GetName())
-	return err
+	p.Infof("%s/%s updated", c.Kind, pkg.GetName())
+	return nil
 }
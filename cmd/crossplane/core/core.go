@@ -28,6 +28,7 @@ import (
 	"github.com/alecthomas/kong"
 	"github.com/spf13/afero"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
@@ -36,6 +37,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	"github.com/crossplane/crossplane-runtime/pkg/certificates"
@@ -46,6 +48,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
 
 	"github.com/crossplane/crossplane/internal/controller/apiextensions"
+	"github.com/crossplane/crossplane/internal/controller/apiextensions/composite"
 	apiextensionscontroller "github.com/crossplane/crossplane/internal/controller/apiextensions/controller"
 	"github.com/crossplane/crossplane/internal/controller/pkg"
 	pkgcontroller "github.com/crossplane/crossplane/internal/controller/pkg/controller"
@@ -54,6 +57,7 @@ import (
 	"github.com/crossplane/crossplane/internal/metrics"
 	"github.com/crossplane/crossplane/internal/transport"
 	"github.com/crossplane/crossplane/internal/usage"
+	"github.com/crossplane/crossplane/internal/validation/apiextensions/v1/claim"
 	"github.com/crossplane/crossplane/internal/validation/apiextensions/v1/composition"
 	"github.com/crossplane/crossplane/internal/validation/apiextensions/v1/xrd"
 	"github.com/crossplane/crossplane/internal/xfn"
@@ -83,20 +87,41 @@ func (c *Command) Run() error {
 type startCommand struct {
 	Profile string `placeholder:"host:port" help:"Serve runtime profiling data via HTTP at /debug/pprof."`
 
-	Namespace      string `short:"n" help:"Namespace used to unpack and run packages." default:"crossplane-system" env:"POD_NAMESPACE"`
-	ServiceAccount string `help:"Name of the Crossplane Service Account." default:"crossplane" env:"POD_SERVICE_ACCOUNT"`
-	CacheDir       string `short:"c" help:"Directory used for caching package images." default:"/cache" env:"CACHE_DIR"`
-	LeaderElection bool   `short:"l" help:"Use leader election for the controller manager." default:"false" env:"LEADER_ELECTION"`
-	Registry       string `short:"r" help:"Default registry used to fetch packages when not specified in tag." default:"${default_registry}" env:"REGISTRY"`
-	CABundlePath   string `help:"Additional CA bundle to use when fetching packages from registry." env:"CA_BUNDLE_PATH"`
-	UserAgent      string `help:"The User-Agent header that will be set on all package requests." default:"${default_user_agent}" env:"USER_AGENT"`
+	Namespace               string        `short:"n" help:"Namespace used to unpack and run packages." default:"crossplane-system" env:"POD_NAMESPACE"`
+	ServiceAccount          string        `help:"Name of the Crossplane Service Account." default:"crossplane" env:"POD_SERVICE_ACCOUNT"`
+	CacheDir                string        `short:"c" help:"Directory used for caching package images. May be shared by Crossplane replicas." default:"/cache" env:"CACHE_DIR"`
+	LocalPackagesDir        string        `help:"Directory packages with a local source are read from, e.g. a mounted PersistentVolume. Only used if --enable-local-packages is set." default:"/packages" env:"LOCAL_PACKAGES_DIR"`
+	LeaderElection          bool          `short:"l" help:"Use leader election for the controller manager." default:"false" env:"LEADER_ELECTION"`
+	Registry                string        `short:"r" help:"Default registry used to fetch packages when not specified in tag." default:"${default_registry}" env:"REGISTRY"`
+	CABundlePath            string        `help:"Additional CA bundle to use when fetching packages from registry." env:"CA_BUNDLE_PATH"`
+	RegistryMirrors         []string      `name:"registry-mirror" placeholder:"REGISTRY=MIRROR" help:"A mirror to fetch packages from instead of a registry. May be repeated to configure several mirrors; the fastest healthy mirror is preferred, falling back to the registry." env:"REGISTRY_MIRRORS"`
+	PackageCacheGCInterval  time.Duration `help:"How often to garbage collect the package cache, if a maximum size or age is set." default:"1h"`
+	PackageCacheMaxAge      time.Duration `help:"Evict cached package content that hasn't been used for this long. Disabled if zero."`
+	PackageCacheMaxSize     string        `help:"Evict the least recently used cached package content to keep the package cache under this size, e.g. 10Gi. Disabled if unset."`
+	WebhookCertValidity     time.Duration `help:"How long provider webhook server certificates are valid for. They're renewed once a third of that remains. Only used if --enable-webhook-cert-rotation is set." default:"2160h" env:"WEBHOOK_CERT_VALIDITY"`
+	PackageLicenseAllowlist []string      `placeholder:"SPDX-ID" help:"Only install packages whose meta.crossplane.io/license is satisfied by these SPDX license identifiers, e.g. Apache-2.0. Packages without a license aren't installed. Any license is allowed if unset." env:"PACKAGE_LICENSE_ALLOWLIST"`
+	UserAgent               string        `help:"The User-Agent header that will be set on all package requests." default:"${default_user_agent}" env:"USER_AGENT"`
 
 	PackageRuntime string `helm:"The package runtime to use for packages with a runtime (e.g. Providers and Functions)" default:"Deployment" env:"PACKAGE_RUNTIME"`
 
+	MaxConcurrentPackageInstalls int `help:"The maximum number of missing package dependencies to install concurrently." default:"5"`
+
 	SyncInterval     time.Duration `short:"s" help:"How often all resources will be double-checked for drift from the desired state." default:"1h"`
 	PollInterval     time.Duration `help:"How often individual resources will be checked for drift from the desired state." default:"1m"`
 	MaxReconcileRate int           `help:"The global maximum rate per second at which resources may checked for drift from the desired state." default:"10"`
 
+	MaxConcurrentFunctionRuns    int            `help:"The maximum number of Composition Functions that may run concurrently. Unlimited if zero." default:"0" env:"MAX_CONCURRENT_FUNCTION_RUNS"`
+	MaxQueuedFunctionRuns        int            `help:"The maximum number of Composition Function runs that may wait for --max-concurrent-function-runs. Runs beyond this fail immediately. Unbounded if zero." default:"0" env:"MAX_QUEUED_FUNCTION_RUNS"`
+	MaxConcurrentRunsPerFunction map[string]int `placeholder:"FUNCTION=N" help:"The maximum number of concurrent runs of particular Composition Functions, e.g. function-heavy=2. Runs beyond this wait, and don't count toward --max-concurrent-function-runs." env:"MAX_CONCURRENT_RUNS_PER_FUNCTION"`
+	MaxFunctionResponseSize      int            `help:"The maximum size in bytes of a Composition Function's response." default:"4194304" env:"MAX_FUNCTION_RESPONSE_SIZE"`
+	FunctionResponseCacheMaxTTL  time.Duration  `help:"Cache Composition Function responses that specify a TTL for up to this long. Responses aren't cached if zero." default:"0" env:"FUNCTION_RESPONSE_CACHE_MAX_TTL"`
+	FunctionConnectionMaxAge     time.Duration  `help:"Redial gRPC connections to Composition Functions once they're this old, so that requests are balanced across Function pods that were added since. Connections are never redialed if zero." default:"5m" env:"FUNCTION_CONNECTION_MAX_AGE"`
+
+	MetricsBindAddress string `help:"The address the Prometheus metrics endpoint binds to. Set to 0 to disable serving metrics." default:":8080" env:"METRICS_BIND_ADDRESS"`
+
+	TracingEndpoint string `placeholder:"host:port" help:"Export OpenTelemetry traces of Composition Function runs to this OTLP gRPC endpoint. Traces aren't exported if unset." env:"TRACING_ENDPOINT"`
+	TracingInsecure bool   `help:"Don't use TLS when exporting traces to --tracing-endpoint." env:"TRACING_INSECURE"`
+
 	WebhookEnabled bool `help:"Enable webhook configuration." default:"true" env:"WEBHOOK_ENABLED"`
 
 	TLSServerSecretName string `help:"The name of the TLS Secret that will store Crossplane's server certificate." env:"TLS_SERVER_SECRET_NAME"`
@@ -104,10 +129,21 @@ type startCommand struct {
 	TLSClientSecretName string `help:"The name of the TLS Secret that will be store Crossplane's client certificate." env:"TLS_CLIENT_SECRET_NAME"`
 	TLSClientCertsDir   string `help:"The path of the folder which will store TLS client certificate of Crossplane." env:"TLS_CLIENT_CERTS_DIR"`
 
-	EnableEnvironmentConfigs   bool `group:"Alpha Features:" help:"Enable support for EnvironmentConfigs."`
-	EnableExternalSecretStores bool `group:"Alpha Features:" help:"Enable support for External Secret Stores."`
-	EnableUsages               bool `group:"Alpha Features:" help:"Enable support for deletion ordering and resource protection with Usages."`
-	EnableRealtimeCompositions bool `group:"Alpha Features:" help:"Enable support for realtime compositions, i.e. watching composed resources and reconciling compositions immediately when any of the composed resources is updated."`
+	EnableEnvironmentConfigs     bool `group:"Alpha Features:" help:"Enable support for EnvironmentConfigs."`
+	EnableExternalSecretStores   bool `group:"Alpha Features:" help:"Enable support for External Secret Stores."`
+	EnableUsages                 bool `group:"Alpha Features:" help:"Enable support for deletion ordering and resource protection with Usages."`
+	EnableRealtimeCompositions   bool `group:"Alpha Features:" help:"Enable support for realtime compositions, i.e. watching composed resources and reconciling compositions immediately when any of the composed resources is updated."`
+	EnableConfigMapPackages      bool `group:"Alpha Features:" help:"Enable support for Configurations sourced from a ConfigMap in Crossplane's namespace, e.g. configmap://my-configuration."`
+	EnableClaimAdmissionRules    bool `group:"Alpha Features:" help:"Enable support for claim admission rules, i.e. CEL expressions defined by an XRD that are evaluated when a claim is created or updated. Requires webhooks to be enabled."`
+	EnableProviderFamilyVersions bool `group:"Alpha Features:" help:"Enable keeping providers in the same family at the same version. A provider revision isn't activated until every provider in its family wants the same version."`
+	EnableDependencyUpgrades     bool `group:"Alpha Features:" help:"Enable upgrading an installed dependency to the greatest version that satisfies the constraints of every package that depends on it, when its current version doesn't."`
+	EnableSignatureVerification  bool `group:"Alpha Features:" help:"Enable verifying the cosign signatures of package images that match an ImageConfig before installing them."`
+	EnablePackageUpdates         bool `group:"Alpha Features:" help:"Enable periodically updating packages to the newest version that satisfies their updatePolicy."`
+	EnableImageRewrites          bool `group:"Alpha Features:" help:"Enable rewriting package images that match an ImageConfig before pulling them, for example to pull them from a mirror."`
+	EnableLocalPackages          bool `group:"Alpha Features:" help:"Enable support for packages sourced from an xpkg file or OCI image layout in the local packages directory, e.g. file://my-configuration.xpkg."`
+	EnableWebhookCertRotation    bool `group:"Alpha Features:" help:"Enable renewing the certificates of provider webhook servers before they expire. Webhook CA bundles are always set to the CA that signs them."`
+	EnableCRDUpgradeChecks       bool `group:"Alpha Features:" help:"Enable checking that a package revision's CRDs can safely replace the installed CRDs before activating it. Set the pkg.crossplane.io/allow-unsafe-upgrade annotation on a package to override the checks."`
+	EnableFunctionAutoscaling    bool `group:"Alpha Features:" help:"Enable scaling Function runtime Deployments using the HorizontalPodAutoscaler template in their DeploymentRuntimeConfig."`
 
 	EnableCompositionFunctions               bool `group:"Beta Features:" default:"true" help:"Enable support for Composition Functions."`
 	EnableCompositionFunctionsExtraResources bool `group:"Beta Features:" default:"true" help:"Enable support for Composition Functions Extra Resources. Only respected if --enable-composition-functions is set to true."`
@@ -133,6 +169,15 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		Deduplicate: true,
 	})
 
+	if c.TracingEndpoint != "" {
+		shutdown, err := setupTracing(context.Background(), c.TracingEndpoint, c.TracingInsecure)
+		if err != nil {
+			return errors.Wrap(err, "cannot set up tracing")
+		}
+		defer shutdown(context.Background()) //nolint:errcheck // Nothing useful we can do with this error.
+		log.Info("Exporting traces", "endpoint", c.TracingEndpoint)
+	}
+
 	eb := record.NewBroadcaster()
 	mgr, err := ctrl.NewManager(ratelimiter.LimitRESTConfig(cfg, c.MaxReconcileRate), ctrl.Options{
 		Scheme: s,
@@ -169,6 +214,10 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		LeaseDuration:                 func() *time.Duration { d := 60 * time.Second; return &d }(),
 		RenewDeadline:                 func() *time.Duration { d := 50 * time.Second; return &d }(),
 
+		Metrics: metricsserver.Options{
+			BindAddress: c.MetricsBindAddress,
+		},
+
 		PprofBindAddress:       c.Profile,
 		HealthProbeBindAddress: ":8081",
 	})
@@ -203,11 +252,12 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 			log.Info("Beta feature enabled", "flag", features.EnableBetaCompositionFunctionsExtraResources)
 		}
 
-		clienttls, err := certificates.LoadMTLSConfig(
+		// Reload client certificates when they change, so that they can be
+		// rotated without restarting Crossplane.
+		clientcreds, err := xfn.NewReloadingCredentials(
 			filepath.Join(c.TLSClientCertsDir, initializer.SecretKeyCACert),
 			filepath.Join(c.TLSClientCertsDir, corev1.TLSCertKey),
-			filepath.Join(c.TLSClientCertsDir, corev1.TLSPrivateKeyKey),
-			false)
+			filepath.Join(c.TLSClientCertsDir, corev1.TLSPrivateKeyKey))
 		if err != nil {
 			return errors.Wrap(err, "cannot load client TLS certificates")
 		}
@@ -215,17 +265,32 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		m := xfn.NewMetrics()
 		metrics.Registry.MustRegister(m)
 
+		var cache *xfn.ResponseCache
+		if c.FunctionResponseCacheMaxTTL > 0 {
+			cache = xfn.NewResponseCache(c.FunctionResponseCacheMaxTTL)
+			metrics.Registry.MustRegister(cache)
+		}
+
 		// We want all XR controllers to share the same gRPC clients.
 		functionRunner = xfn.NewPackagedFunctionRunner(mgr.GetClient(),
 			xfn.WithLogger(log),
-			xfn.WithTLSConfig(clienttls),
-			xfn.WithInterceptorCreators(m),
+			xfn.WithTransportCredentials(clientcreds),
+			xfn.WithInterceptorCreators(m, xfn.NewTracing()),
+			xfn.WithMaxConcurrentRuns(c.MaxConcurrentFunctionRuns),
+			xfn.WithMaxConcurrentRunsPerFunction(c.MaxConcurrentRunsPerFunction),
+			xfn.WithMaxQueuedRuns(c.MaxQueuedFunctionRuns),
+			xfn.WithMaxResponseSize(c.MaxFunctionResponseSize),
+			xfn.WithResponseCache(cache),
+			xfn.WithMaxConnectionAge(c.FunctionConnectionMaxAge),
 		)
 
 		// Periodically remove clients for Functions that no longer exist.
 		ctx, cancel := context.WithCancel(context.Background())
 		defer cancel()
 		go functionRunner.GarbageCollectConnections(ctx, 10*time.Minute)
+		if cache != nil {
+			go cache.GarbageCollect(ctx, c.FunctionResponseCacheMaxTTL)
+		}
 	}
 	if c.EnableEnvironmentConfigs {
 		o.Features.Enable(features.EnableAlphaEnvironmentConfigs)
@@ -260,14 +325,65 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		o.Features.Enable(features.EnableAlphaRealtimeCompositions)
 		log.Info("Alpha feature enabled", "flag", features.EnableAlphaRealtimeCompositions)
 	}
+	if c.EnableConfigMapPackages {
+		o.Features.Enable(features.EnableAlphaConfigMapPackages)
+		log.Info("Alpha feature enabled", "flag", features.EnableAlphaConfigMapPackages)
+	}
+	if c.EnableProviderFamilyVersions {
+		o.Features.Enable(features.EnableAlphaProviderFamilyVersions)
+		log.Info("Alpha feature enabled", "flag", features.EnableAlphaProviderFamilyVersions)
+	}
+	if c.EnableDependencyUpgrades {
+		o.Features.Enable(features.EnableAlphaDependencyUpgrades)
+		log.Info("Alpha feature enabled", "flag", features.EnableAlphaDependencyUpgrades)
+	}
+	if c.EnableSignatureVerification {
+		o.Features.Enable(features.EnableAlphaSignatureVerification)
+		log.Info("Alpha feature enabled", "flag", features.EnableAlphaSignatureVerification)
+	}
+	if c.EnablePackageUpdates {
+		o.Features.Enable(features.EnableAlphaPackageUpdates)
+		log.Info("Alpha feature enabled", "flag", features.EnableAlphaPackageUpdates)
+	}
+	if c.EnableImageRewrites {
+		o.Features.Enable(features.EnableAlphaImageRewrites)
+		log.Info("Alpha feature enabled", "flag", features.EnableAlphaImageRewrites)
+	}
+	if c.EnableLocalPackages {
+		o.Features.Enable(features.EnableAlphaLocalPackages)
+		log.Info("Alpha feature enabled", "flag", features.EnableAlphaLocalPackages)
+	}
+	if c.EnableWebhookCertRotation {
+		o.Features.Enable(features.EnableAlphaWebhookCertificateRotation)
+		log.Info("Alpha feature enabled", "flag", features.EnableAlphaWebhookCertificateRotation)
+	}
+	if c.EnableCRDUpgradeChecks {
+		o.Features.Enable(features.EnableAlphaCRDUpgradeChecks)
+		log.Info("Alpha feature enabled", "flag", features.EnableAlphaCRDUpgradeChecks)
+	}
+	if c.EnableFunctionAutoscaling {
+		o.Features.Enable(features.EnableAlphaFunctionAutoscaling)
+		log.Info("Alpha feature enabled", "flag", features.EnableAlphaFunctionAutoscaling)
+	}
+	if c.EnableClaimAdmissionRules {
+		if !c.WebhookEnabled {
+			return errors.New("claim admission rules require webhooks to be enabled")
+		}
+		o.Features.Enable(features.EnableAlphaClaimAdmissionRules)
+		log.Info("Alpha feature enabled", "flag", features.EnableAlphaClaimAdmissionRules)
+	}
 	if c.EnableDeploymentRuntimeConfigs {
 		o.Features.Enable(features.EnableBetaDeploymentRuntimeConfigs)
 		log.Info("Beta feature enabled", "flag", features.EnableBetaDeploymentRuntimeConfigs)
 	}
 
+	cm := composite.NewMetrics()
+	metrics.Registry.MustRegister(cm)
+
 	ao := apiextensionscontroller.Options{
 		Options:        o,
 		FunctionRunner: functionRunner,
+		ApplyRecorder:  cm,
 	}
 
 	if err := apiextensions.Setup(mgr, ao); err != nil {
@@ -285,14 +401,40 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 			c.PackageRuntime, pkgcontroller.PackageRuntimeDeployment, pkgcontroller.PackageRuntimeExternal)
 	}
 
+	pkgCache := xpkg.NewFsPackageCache(c.CacheDir, afero.NewOsFs())
+	if c.PackageCacheMaxSize != "" || c.PackageCacheMaxAge > 0 {
+		if c.PackageCacheGCInterval <= 0 {
+			return errors.New("--package-cache-gc-interval must be greater than zero when a package cache maximum size or age is set")
+		}
+		gcOpts := []xpkg.CacheGarbageCollectorOption{
+			xpkg.WithMaxAge(c.PackageCacheMaxAge),
+			xpkg.WithGarbageCollectorLogger(log.WithValues("component", "package-cache-gc")),
+		}
+		if c.PackageCacheMaxSize != "" {
+			q, err := resource.ParseQuantity(c.PackageCacheMaxSize)
+			if err != nil {
+				return errors.Wrap(err, "cannot parse package cache maximum size")
+			}
+			gcOpts = append(gcOpts, xpkg.WithMaxSize(q.Value()))
+		}
+		if err := mgr.Add(xpkg.NewCacheGarbageCollector(pkgCache, c.PackageCacheGCInterval, gcOpts...)); err != nil {
+			return errors.Wrap(err, "cannot add package cache garbage collector to manager")
+		}
+	}
+
 	po := pkgcontroller.Options{
 		Options:         o,
-		Cache:           xpkg.NewFsPackageCache(c.CacheDir, afero.NewOsFs()),
+		Cache:           pkgCache,
 		Namespace:       c.Namespace,
 		ServiceAccount:  c.ServiceAccount,
 		DefaultRegistry: c.Registry,
-		FetcherOptions:  []xpkg.FetcherOpt{xpkg.WithUserAgent(c.UserAgent)},
+		FetcherOptions:  []xpkg.FetcherOpt{xpkg.WithUserAgent(c.UserAgent), xpkg.WithRegistryBackoff(xpkg.NewRegistryBackoff())},
 		PackageRuntime:  pr,
+
+		LocalPackagesDir:             c.LocalPackagesDir,
+		WebhookCertificateValidity:   c.WebhookCertValidity,
+		LicenseAllowlist:             c.PackageLicenseAllowlist,
+		MaxConcurrentPackageInstalls: c.MaxConcurrentPackageInstalls,
 	}
 
 	if c.CABundlePath != "" {
@@ -303,6 +445,21 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		po.FetcherOptions = append(po.FetcherOptions, xpkg.WithCustomCA(rootCAs))
 	}
 
+	if len(c.RegistryMirrors) > 0 {
+		mirrors, err := xpkg.ParseMirrors(c.RegistryMirrors)
+		if err != nil {
+			return errors.Wrap(err, "cannot parse registry mirrors")
+		}
+		m := xpkg.NewMirrors(mirrors)
+		metrics.Registry.MustRegister(m)
+		po.FetcherOptions = append(po.FetcherOptions, xpkg.WithMirrors(m))
+		log.Info("Fetching packages from registry mirrors", "mirrors", mirrors)
+	}
+
+	if o.Features.Enabled(features.EnableAlphaImageRewrites) {
+		po.FetcherOptions = append(po.FetcherOptions, xpkg.WithImageRewriter(xpkg.NewImageConfigRewriter(mgr.GetClient())))
+	}
+
 	if err := pkg.Setup(mgr, po); err != nil {
 		return errors.Wrap(err, "cannot add packages controllers to manager")
 	}
@@ -324,6 +481,11 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 				return errors.Wrap(err, "cannot setup webhook for usages")
 			}
 		}
+		if o.Features.Enabled(features.EnableAlphaClaimAdmissionRules) {
+			if err := claim.SetupWebhookWithManager(mgr, o); err != nil {
+				return errors.Wrap(err, "cannot setup webhook for composite resource claims")
+			}
+		}
 	}
 
 	if err := c.SetupProbes(mgr); err != nil {
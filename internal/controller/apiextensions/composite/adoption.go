@@ -0,0 +1,212 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"encoding/json"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+// Error strings.
+const (
+	errListAdoptable         = "cannot list composed resources that may be adopted"
+	errFmtAdoptControlled    = "cannot adopt composed resource %q: it is controlled by another resource"
+	errFmtAdoptNotAllowed    = "cannot adopt composed resource %q: it must be labelled " + LabelKeyAdoptableByComposite + " or " + LabelKeyAdoptableByClaimName + " and " + LabelKeyAdoptableByClaimNamespace
+	errFmtAdoptAmbiguous     = "cannot adopt composed resource: %d existing resources have external name %q"
+	errFmtUnknownAdoptMatch  = "unknown adoption match type %q"
+	errFmtGetAdoptableByName = "cannot get composed resource %q that may be adopted"
+)
+
+// A ComposedResourceAdopter adopts existing composed resources.
+type ComposedResourceAdopter interface {
+	// Adopt updates the supplied rendered composed resource to identify an
+	// existing composed resource that it should adopt, if any. It returns an
+	// error if a matching resource exists but can't be adopted.
+	Adopt(ctx context.Context, xr resource.Composite, cd resource.Composed, a *v1.ComposedResourceAdoption) error
+}
+
+// A ComposedResourceAdopterFn adopts existing composed resources.
+type ComposedResourceAdopterFn func(ctx context.Context, xr resource.Composite, cd resource.Composed, a *v1.ComposedResourceAdoption) error
+
+// Adopt an existing composed resource.
+func (fn ComposedResourceAdopterFn) Adopt(ctx context.Context, xr resource.Composite, cd resource.Composed, a *v1.ComposedResourceAdoption) error {
+	return fn(ctx, xr, cd, a)
+}
+
+// An ExistingResourceAdopter adopts existing composed resources that aren't
+// controlled by another resource. A composed resource may only be adopted by a
+// composite resource if it's labelled to opt in to adoption by that composite
+// resource or its claim.
+type ExistingResourceAdopter struct {
+	client client.Reader
+}
+
+// NewExistingResourceAdopter returns a ComposedResourceAdopter that adopts
+// existing composed resources read from the API server.
+func NewExistingResourceAdopter(c client.Reader) *ExistingResourceAdopter {
+	return &ExistingResourceAdopter{client: c}
+}
+
+// Adopt an existing composed resource that matches the supplied rendered
+// composed resource. When matching by external name the rendered composed
+// resource is renamed to match the existing resource, so that applying it
+// updates the existing resource rather than creating a new one. Nothing is
+// adopted if the rendered composed resource has no external name or name.
+func (a *ExistingResourceAdopter) Adopt(ctx context.Context, xr resource.Composite, cd resource.Composed, ad *v1.ComposedResourceAdoption) error {
+	switch ad.GetMatchBy() {
+	case v1.AdoptionMatchTypeExternalName:
+		return a.adoptByExternalName(ctx, xr, cd)
+	case v1.AdoptionMatchTypeName:
+		return a.adoptByName(ctx, xr, cd)
+	}
+	return errors.Errorf(errFmtUnknownAdoptMatch, ad.GetMatchBy())
+}
+
+func (a *ExistingResourceAdopter) adoptByExternalName(ctx context.Context, xr resource.Composite, cd resource.Composed) error {
+	en := meta.GetExternalName(cd)
+	if en == "" {
+		return nil
+	}
+
+	// We only list resources that opted in to adoption by this XR or its
+	// claim, so that we don't need to read every resource of this kind.
+	selectors := []client.MatchingLabels{{LabelKeyAdoptableByComposite: xr.GetName()}}
+	if ref := xr.GetClaimReference(); ref != nil {
+		selectors = append(selectors, client.MatchingLabels{LabelKeyAdoptableByClaimName: ref.Name, LabelKeyAdoptableByClaimNamespace: ref.Namespace})
+	}
+
+	gvk := cd.GetObjectKind().GroupVersionKind()
+	matches := map[string]*composed.Unstructured{}
+	for _, sel := range selectors {
+		l := &kunstructured.UnstructuredList{}
+		l.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := a.client.List(ctx, l, client.InNamespace(cd.GetNamespace()), sel); err != nil {
+			return errors.Wrap(err, errListAdoptable)
+		}
+		for i := range l.Items {
+			existing := &composed.Unstructured{Unstructured: l.Items[i]}
+			if meta.GetExternalName(existing) == en && adoptableBy(existing, xr) {
+				matches[existing.GetName()] = existing
+			}
+		}
+	}
+
+	var existing *composed.Unstructured
+	switch len(matches) {
+	case 0:
+		return nil
+	case 1:
+		for _, m := range matches {
+			existing = m
+		}
+	default:
+		return errors.Errorf(errFmtAdoptAmbiguous, len(matches), en)
+	}
+
+	if err := checkAdoptable(existing, xr); err != nil {
+		return err
+	}
+	cd.SetName(existing.GetName())
+	return nil
+}
+
+func (a *ExistingResourceAdopter) adoptByName(ctx context.Context, xr resource.Composite, cd resource.Composed) error {
+	if cd.GetName() == "" {
+		return nil
+	}
+
+	existing := composed.New(composed.FromReference(*meta.ReferenceTo(cd, cd.GetObjectKind().GroupVersionKind())))
+	err := a.client.Get(ctx, types.NamespacedName{Namespace: cd.GetNamespace(), Name: cd.GetName()}, existing)
+	if kerrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, errFmtGetAdoptableByName, cd.GetName())
+	}
+	return checkAdoptable(existing, xr)
+}
+
+// checkAdoptable returns an error if the supplied existing composed resource
+// can't be adopted by the supplied composite resource. A resource that's
+// already controlled by the composite resource doesn't need to be adopted.
+func checkAdoptable(existing metav1.Object, xr resource.Composite) error {
+	c := metav1.GetControllerOf(existing)
+	switch {
+	case c != nil && c.UID == xr.GetUID():
+		return nil
+	case c != nil:
+		return errors.Errorf(errFmtAdoptControlled, existing.GetName())
+	case !adoptableBy(existing, xr):
+		return errors.Errorf(errFmtAdoptNotAllowed, existing.GetName())
+	}
+	return nil
+}
+
+// adoptableBy returns true if the supplied existing composed resource is
+// labelled to opt in to adoption by the supplied composite resource or its
+// claim.
+func adoptableBy(existing metav1.Object, xr resource.Composite) bool {
+	l := existing.GetLabels()
+	if n, ok := l[LabelKeyAdoptableByComposite]; ok && n == xr.GetName() {
+		return true
+	}
+	ref := xr.GetClaimReference()
+	if ref == nil {
+		return false
+	}
+	n, nok := l[LabelKeyAdoptableByClaimName]
+	ns, nsok := l[LabelKeyAdoptableByClaimNamespace]
+	return nok && nsok && n == ref.Name && ns == ref.Namespace
+}
+
+// adoptingTemplate returns the index of the only template that may adopt the
+// supplied existing composed resource. A template may adopt a resource if it
+// configures adoption, renders a resource of the same kind, and isn't yet
+// associated with a composed resource.
+func adoptingTemplate(tas []TemplateAssociation, cd resource.Composed) (int, bool) {
+	found := -1
+	for i, ta := range tas {
+		if ta.Template.Adoption == nil || ta.Reference.Name != "" {
+			continue
+		}
+		base := metav1.TypeMeta{}
+		if err := json.Unmarshal(ta.Template.Base.Raw, &base); err != nil {
+			continue
+		}
+		if base.GroupVersionKind() != cd.GetObjectKind().GroupVersionKind() {
+			continue
+		}
+		if found >= 0 {
+			return 0, false
+		}
+		found = i
+	}
+	return found, found >= 0
+}
@@ -0,0 +1,261 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/claim"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+func TestExistingResourceAdopter(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	xr := composite.New()
+	xr.SetName("cool-xr")
+	xr.SetUID("xr-uid")
+	xr.SetClaimReference(&claim.Reference{Namespace: "default", Name: "cool-claim"})
+
+	adoptable := map[string]string{LabelKeyAdoptableByComposite: "cool-xr"}
+
+	// rendered returns a rendered composed resource with the supplied name and
+	// external name.
+	rendered := func(name, en string) *composed.Unstructured {
+		cd := composed.New()
+		cd.SetAPIVersion("example.org/v1")
+		cd.SetKind("Bucket")
+		cd.SetName(name)
+		if en != "" {
+			meta.SetExternalName(cd, en)
+		}
+		return cd
+	}
+
+	// existing returns an existing composed resource with the supplied name,
+	// external name, and labels, controlled by the supplied UID (if any).
+	existing := func(name, en string, labels map[string]string, controller types.UID) kunstructured.Unstructured {
+		cd := rendered(name, en)
+		cd.SetLabels(labels)
+		if controller != "" {
+			cd.SetOwnerReferences([]metav1.OwnerReference{{UID: controller, Controller: ptr.To(true)}})
+		}
+		return cd.Unstructured
+	}
+
+	list := func(items ...kunstructured.Unstructured) test.MockListFn {
+		return func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+			obj.(*kunstructured.UnstructuredList).Items = items
+			return nil
+		}
+	}
+
+	type args struct {
+		client client.Reader
+		cd     resource.Composed
+		a      *v1.ComposedResourceAdoption
+	}
+	type want struct {
+		name string
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoExternalName": {
+			reason: "We should not try to adopt anything if the rendered resource has no external name.",
+			args: args{
+				cd: rendered("", ""),
+				a:  &v1.ComposedResourceAdoption{},
+			},
+			want: want{},
+		},
+		"ListError": {
+			reason: "We should return any error encountered listing resources that may be adopted.",
+			args: args{
+				client: &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+				cd:     rendered("", "bucket"),
+				a:      &v1.ComposedResourceAdoption{},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errListAdoptable),
+			},
+		},
+		"NoMatchingExternalName": {
+			reason: "We should not adopt anything if no existing resource has the rendered resource's external name.",
+			args: args{
+				client: &test.MockClient{MockList: list(existing("other", "other-bucket", adoptable, ""))},
+				cd:     rendered("", "bucket"),
+				a:      &v1.ComposedResourceAdoption{},
+			},
+			want: want{},
+		},
+		"AmbiguousExternalName": {
+			reason: "We should return an error if more than one existing resource has the rendered resource's external name.",
+			args: args{
+				client: &test.MockClient{MockList: list(existing("a", "bucket", adoptable, ""), existing("b", "bucket", adoptable, ""))},
+				cd:     rendered("", "bucket"),
+				a:      &v1.ComposedResourceAdoption{},
+			},
+			want: want{
+				err: errors.Errorf(errFmtAdoptAmbiguous, 2, "bucket"),
+			},
+		},
+		"ExternalNameControlledBySomeoneElse": {
+			reason: "We should return an error if the matching existing resource is controlled by another resource.",
+			args: args{
+				client: &test.MockClient{MockList: list(existing("existing", "bucket", adoptable, "other-uid"))},
+				cd:     rendered("", "bucket"),
+				a:      &v1.ComposedResourceAdoption{MatchBy: v1.AdoptionMatchTypeExternalName},
+			},
+			want: want{
+				err: errors.Errorf(errFmtAdoptControlled, "existing"),
+			},
+		},
+		"AdoptByExternalName": {
+			reason: "We should name the rendered resource after the existing resource with the same external name.",
+			args: args{
+				client: &test.MockClient{MockList: list(existing("other", "other-bucket", adoptable, ""), existing("existing", "bucket", adoptable, ""))},
+				cd:     rendered("", "bucket"),
+				a:      &v1.ComposedResourceAdoption{MatchBy: v1.AdoptionMatchTypeExternalName},
+			},
+			want: want{
+				name: "existing",
+			},
+		},
+		"ExternalNameNotAdoptable": {
+			reason: "We should not adopt an existing resource with the rendered resource's external name that isn't labelled as adoptable by the XR or its claim.",
+			args: args{
+				client: &test.MockClient{MockList: list(existing("existing", "bucket", map[string]string{LabelKeyAdoptableByComposite: "other-xr"}, ""))},
+				cd:     rendered("", "bucket"),
+				a:      &v1.ComposedResourceAdoption{MatchBy: v1.AdoptionMatchTypeExternalName},
+			},
+			want: want{},
+		},
+		"AdoptByExternalNameForClaim": {
+			reason: "We should adopt an existing resource with the rendered resource's external name that is labelled as adoptable by the XR's claim.",
+			args: args{
+				client: &test.MockClient{MockList: list(existing("existing", "bucket", map[string]string{
+					LabelKeyAdoptableByClaimName:      "cool-claim",
+					LabelKeyAdoptableByClaimNamespace: "default",
+				}, ""))},
+				cd: rendered("", "bucket"),
+				a:  &v1.ComposedResourceAdoption{MatchBy: v1.AdoptionMatchTypeExternalName},
+			},
+			want: want{
+				name: "existing",
+			},
+		},
+		"NameNotFound": {
+			reason: "We should not adopt anything if no existing resource has the rendered resource's name.",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "existing"))},
+				cd:     rendered("existing", ""),
+				a:      &v1.ComposedResourceAdoption{MatchBy: v1.AdoptionMatchTypeName},
+			},
+			want: want{
+				name: "existing",
+			},
+		},
+		"GetError": {
+			reason: "We should return any error encountered getting a resource that may be adopted.",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				cd:     rendered("existing", ""),
+				a:      &v1.ComposedResourceAdoption{MatchBy: v1.AdoptionMatchTypeName},
+			},
+			want: want{
+				name: "existing",
+				err:  errors.Wrapf(errBoom, errFmtGetAdoptableByName, "existing"),
+			},
+		},
+		"NameControlledBySomeoneElse": {
+			reason: "We should return an error if the existing resource with the rendered resource's name is controlled by another resource.",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+					obj.SetOwnerReferences([]metav1.OwnerReference{{UID: "other-uid", Controller: ptr.To(true)}})
+					return nil
+				})},
+				cd: rendered("existing", ""),
+				a:  &v1.ComposedResourceAdoption{MatchBy: v1.AdoptionMatchTypeName},
+			},
+			want: want{
+				name: "existing",
+				err:  errors.Errorf(errFmtAdoptControlled, "existing"),
+			},
+		},
+		"NameNotAdoptable": {
+			reason: "We should return an error if the existing resource with the rendered resource's name isn't labelled as adoptable by the XR or its claim.",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(nil)},
+				cd:     rendered("existing", ""),
+				a:      &v1.ComposedResourceAdoption{MatchBy: v1.AdoptionMatchTypeName},
+			},
+			want: want{
+				name: "existing",
+				err:  errors.Errorf(errFmtAdoptNotAllowed, "existing"),
+			},
+		},
+		"AdoptByName": {
+			reason: "We should adopt an existing resource with the rendered resource's name that is controlled by nothing and labelled as adoptable by the XR.",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+					obj.SetLabels(adoptable)
+					return nil
+				})},
+				cd: rendered("existing", ""),
+				a:  &v1.ComposedResourceAdoption{MatchBy: v1.AdoptionMatchTypeName},
+			},
+			want: want{
+				name: "existing",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			a := NewExistingResourceAdopter(tc.args.client)
+			err := a.Adopt(context.Background(), xr, tc.args.cd, tc.args.a)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nAdopt(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.name, tc.args.cd.GetName()); diff != "" {
+				t.Errorf("\n%s\nAdopt(...): -want name, +got name:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
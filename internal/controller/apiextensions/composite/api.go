@@ -18,12 +18,16 @@ package composite
 
 import (
 	"context"
+	"hash/fnv"
 	"math/rand"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -33,6 +37,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	"github.com/crossplane/crossplane/internal/xcrd"
@@ -52,6 +57,9 @@ const (
 	errCompositionNotCompatible        = "referenced composition is not compatible with this composite resource"
 	errGetXRD                          = "cannot get composite resource definition"
 	errFetchCompositionRevision        = "cannot fetch composition revision"
+	errRollout                         = "cannot determine whether to roll out the latest composition revision"
+	errListComposites                  = "cannot list composite resources"
+	errParseRolloutSelector            = "cannot parse rollout selector"
 )
 
 // Event reasons.
@@ -125,13 +133,32 @@ func (a *APIFilteredSecretPublisher) UnpublishConnection(_ context.Context, _ re
 // for compatibility with existing Composition logic while CompositionRevisions
 // are in alpha.
 type APIRevisionFetcher struct {
-	ca resource.ClientApplicator
+	ca       resource.ClientApplicator
+	unsynced client.Reader
+}
+
+// An APIRevisionFetcherOption configures an APIRevisionFetcher.
+type APIRevisionFetcherOption func(f *APIRevisionFetcher)
+
+// WithUnsyncedCompositeReader configures the reader an APIRevisionFetcher uses
+// to find composite resources that fail to sync, in order to pause rollouts.
+// The reader must index composite resources using
+// IndexUnsyncedCompositionRevision - typically it's a cache. By default an
+// APIRevisionFetcher lists and filters every composite resource of a kind.
+func WithUnsyncedCompositeReader(r client.Reader) APIRevisionFetcherOption {
+	return func(f *APIRevisionFetcher) {
+		f.unsynced = r
+	}
 }
 
 // NewAPIRevisionFetcher returns a RevisionFetcher that fetches the
 // Revision referenced by a composite resource.
-func NewAPIRevisionFetcher(ca resource.ClientApplicator) *APIRevisionFetcher {
-	return &APIRevisionFetcher{ca: ca}
+func NewAPIRevisionFetcher(ca resource.ClientApplicator, o ...APIRevisionFetcherOption) *APIRevisionFetcher {
+	f := &APIRevisionFetcher{ca: ca}
+	for _, fn := range o {
+		fn(f)
+	}
+	return f
 }
 
 // Fetch the appropriate CompositionRevision for the supplied XR. Panics if the
@@ -167,6 +194,20 @@ func (f *APIRevisionFetcher) Fetch(ctx context.Context, cr resource.Composite) (
 		return nil, errors.New(errNoCompatibleCompositionRevision)
 	}
 
+	// Composite resources that already use a revision only move to the
+	// latest revision once the Composition's rollout allows it.
+	if current != nil && current.Name != latest.GetName() {
+		if rev := revisionNamed(rl.Items, current.Name); rev != nil {
+			ok, err := f.rollout(ctx, cr, comp.Spec.Rollout, latest)
+			if err != nil {
+				return nil, errors.Wrap(err, errRollout)
+			}
+			if !ok {
+				return rev, nil
+			}
+		}
+	}
+
 	if current == nil || current.Name != latest.GetName() {
 		cr.SetCompositionRevisionReference(meta.ReferenceTo(latest, v1.CompositionRevisionGroupVersionKind))
 		if err := f.ca.Apply(ctx, cr); err != nil {
@@ -193,6 +234,105 @@ func (f *APIRevisionFetcher) getCompositionRevisionList(ctx context.Context, cr
 	return rl, nil
 }
 
+// rollout returns true if the supplied composite resource should be updated to
+// the latest revision according to the supplied rollout.
+func (f *APIRevisionFetcher) rollout(ctx context.Context, cr resource.Composite, r *v1.CompositionRollout, latest *v1.CompositionRevision) (bool, error) {
+	if r == nil {
+		return true, nil
+	}
+
+	selected, err := rolloutSelects(r, cr)
+	if err != nil || !selected {
+		return false, err
+	}
+
+	if !r.ShouldPauseOnError() {
+		return true, nil
+	}
+
+	// Pause the rollout while any composite resource that was already updated
+	// to the latest revision fails to sync.
+	gvk := cr.GetObjectKind().GroupVersionKind()
+	l := &kunstructured.UnstructuredList{}
+	l.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+
+	// We're called for every composite resource that's waiting to be updated,
+	// so we read only the composite resources that fail to sync from an
+	// index if we can.
+	if f.unsynced != nil {
+		if err := f.unsynced.List(ctx, l, client.MatchingFields{IndexKeyUnsyncedCompositionRevision: latest.GetName()}, client.Limit(1)); err != nil {
+			return false, errors.Wrap(err, errListComposites)
+		}
+		return len(l.Items) == 0, nil
+	}
+
+	if err := f.ca.List(ctx, l); err != nil {
+		return false, errors.Wrap(err, errListComposites)
+	}
+	for i := range l.Items {
+		if rev := IndexUnsyncedCompositionRevision(&l.Items[i]); len(rev) > 0 && rev[0] == latest.GetName() {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// IndexKeyUnsyncedCompositionRevision is the key of an index of composite
+// resources that fail to sync, by the name of the CompositionRevision they
+// use.
+const IndexKeyUnsyncedCompositionRevision = "unsyncedCompositionRevision"
+
+// IndexUnsyncedCompositionRevision assumes the supplied object is a composite
+// resource. It returns the name of the CompositionRevision the composite
+// resource uses if it fails to sync.
+func IndexUnsyncedCompositionRevision(o client.Object) []string {
+	u, ok := o.(*kunstructured.Unstructured)
+	if !ok {
+		return nil // should never happen
+	}
+	xr := &composite.Unstructured{Unstructured: *u}
+	ref := xr.GetCompositionRevisionReference()
+	if ref == nil || xr.GetCondition(xpv1.TypeSynced).Status != corev1.ConditionFalse {
+		return nil
+	}
+	return []string{ref.Name}
+}
+
+// rolloutSelects returns true if the supplied rollout selects the supplied
+// composite resource, either by percentage or by label selector.
+func rolloutSelects(r *v1.CompositionRollout, cr resource.Composite) (bool, error) {
+	if r.Percentage == nil && r.Selector == nil {
+		return true, nil
+	}
+	if r.Percentage != nil {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(cr.GetUID()))
+		if int32(h.Sum32()%100) < *r.Percentage {
+			return true, nil
+		}
+	}
+	if r.Selector != nil {
+		sel, err := metav1.LabelSelectorAsSelector(r.Selector)
+		if err != nil {
+			return false, errors.Wrap(err, errParseRolloutSelector)
+		}
+		if sel.Matches(labels.Set(cr.GetLabels())) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// revisionNamed returns the revision with the supplied name, if any.
+func revisionNamed(revs []v1.CompositionRevision, name string) *v1.CompositionRevision {
+	for i := range revs {
+		if revs[i].GetName() == name {
+			return &revs[i]
+		}
+	}
+	return nil
+}
+
 // NewCompositionSelectorChain returns a new CompositionSelectorChain.
 func NewCompositionSelectorChain(list ...CompositionSelector) *CompositionSelectorChain {
 	return &CompositionSelectorChain{list: list}
@@ -22,9 +22,11 @@ import (
 	"github.com/google/go-cmp/cmp"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
@@ -33,6 +35,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
@@ -212,10 +215,11 @@ func TestFetchRevision(t *testing.T) {
 	}
 
 	cases := map[string]struct {
-		reason string
-		client resource.ClientApplicator
-		args   args
-		want   want
+		reason   string
+		client   resource.ClientApplicator
+		unsynced client.Reader
+		args     args
+		want     want
 	}{
 		"GetCompositionRevisionError": {
 			reason: "We should wrap and return errors encountered getting the CompositionRevision.",
@@ -455,6 +459,167 @@ func TestFetchRevision(t *testing.T) {
 				rev: rev2,
 			},
 		},
+		"RolloutNotSelected": {
+			reason: "We should keep using our current revision if the Composition's rollout doesn't select us.",
+			client: resource.ClientApplicator{
+				Client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						c := comp.DeepCopy()
+						c.Spec.Rollout = &v1.CompositionRollout{Percentage: ptr.To[int32](0)}
+						*obj.(*v1.Composition) = *c
+						return nil
+					}),
+					MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+						*obj.(*v1.CompositionRevisionList) = v1.CompositionRevisionList{
+							Items: []v1.CompositionRevision{*rev2, *rev1},
+						}
+						return nil
+					}),
+				},
+				// This should not be called.
+				Applicator: resource.ApplyFn(func(c context.Context, o client.Object, ao ...resource.ApplyOption) error { return errBoom }),
+			},
+			args: args{
+				cr: &fake.Composite{
+					CompositionReferencer: fake.CompositionReferencer{
+						Ref: &corev1.ObjectReference{Name: comp.GetName()},
+					},
+					CompositionRevisionReferencer: fake.CompositionRevisionReferencer{
+						Ref: &corev1.ObjectReference{Name: rev1.GetName()},
+					},
+				},
+			},
+			want: want{
+				rev: rev1,
+			},
+		},
+		"RolloutPaused": {
+			reason: "We should keep using our current revision if a composite resource that uses the latest revision fails to sync.",
+			client: resource.ClientApplicator{
+				Client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						c := comp.DeepCopy()
+						c.Spec.Rollout = &v1.CompositionRollout{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"canary": "true"}}}
+						*obj.(*v1.Composition) = *c
+						return nil
+					}),
+					MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+						switch l := obj.(type) {
+						case *v1.CompositionRevisionList:
+							l.Items = []v1.CompositionRevision{*rev2, *rev1}
+						case *kunstructured.UnstructuredList:
+							xr := composite.New()
+							xr.SetCompositionRevisionReference(&corev1.ObjectReference{Name: rev2.GetName()})
+							xr.SetConditions(xpv1.ReconcileError(errBoom))
+							l.Items = []kunstructured.Unstructured{xr.Unstructured}
+						}
+						return nil
+					}),
+				},
+				// This should not be called.
+				Applicator: resource.ApplyFn(func(c context.Context, o client.Object, ao ...resource.ApplyOption) error { return errBoom }),
+			},
+			args: args{
+				cr: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"canary": "true"}},
+					CompositionReferencer: fake.CompositionReferencer{
+						Ref: &corev1.ObjectReference{Name: comp.GetName()},
+					},
+					CompositionRevisionReferencer: fake.CompositionRevisionReferencer{
+						Ref: &corev1.ObjectReference{Name: rev1.GetName()},
+					},
+				},
+			},
+			want: want{
+				rev: rev1,
+			},
+		},
+		"RolloutPausedByIndex": {
+			reason: "We should keep using our current revision if the index of composite resources that fail to sync includes one that uses the latest revision.",
+			client: resource.ClientApplicator{
+				Client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						c := comp.DeepCopy()
+						c.Spec.Rollout = &v1.CompositionRollout{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"canary": "true"}}}
+						*obj.(*v1.Composition) = *c
+						return nil
+					}),
+					MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+						// We should read composite resources from the index.
+						if l, ok := obj.(*v1.CompositionRevisionList); ok {
+							l.Items = []v1.CompositionRevision{*rev2, *rev1}
+							return nil
+						}
+						return errBoom
+					}),
+				},
+				// This should not be called.
+				Applicator: resource.ApplyFn(func(c context.Context, o client.Object, ao ...resource.ApplyOption) error { return errBoom }),
+			},
+			unsynced: &test.MockClient{
+				MockList: func(_ context.Context, obj client.ObjectList, opts ...client.ListOption) error {
+					want := []client.ListOption{client.MatchingFields{IndexKeyUnsyncedCompositionRevision: rev2.GetName()}, client.Limit(1)}
+					if diff := cmp.Diff(want, opts); diff != "" {
+						t.Errorf("List(): -want options, +got options: %s", diff)
+					}
+					obj.(*kunstructured.UnstructuredList).Items = []kunstructured.Unstructured{composite.New().Unstructured}
+					return nil
+				},
+			},
+			args: args{
+				cr: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"canary": "true"}},
+					CompositionReferencer: fake.CompositionReferencer{
+						Ref: &corev1.ObjectReference{Name: comp.GetName()},
+					},
+					CompositionRevisionReferencer: fake.CompositionRevisionReferencer{
+						Ref: &corev1.ObjectReference{Name: rev1.GetName()},
+					},
+				},
+			},
+			want: want{
+				rev: rev1,
+			},
+		},
+		"RolloutSelected": {
+			reason: "We should update to the latest revision if the Composition's rollout selects us and no composite resource that uses it fails to sync.",
+			client: resource.ClientApplicator{
+				Client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						c := comp.DeepCopy()
+						c.Spec.Rollout = &v1.CompositionRollout{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"canary": "true"}}}
+						*obj.(*v1.Composition) = *c
+						return nil
+					}),
+					MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+						if l, ok := obj.(*v1.CompositionRevisionList); ok {
+							l.Items = []v1.CompositionRevision{*rev2, *rev1}
+						}
+						return nil
+					}),
+				},
+				Applicator: resource.ApplyFn(func(c context.Context, o client.Object, ao ...resource.ApplyOption) error {
+					if diff := cmp.Diff(rev2.GetName(), o.(*fake.Composite).GetCompositionRevisionReference().Name); diff != "" {
+						t.Errorf("Apply(): -want, +got: %s", diff)
+					}
+					return nil
+				}),
+			},
+			args: args{
+				cr: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"canary": "true"}},
+					CompositionReferencer: fake.CompositionReferencer{
+						Ref: &corev1.ObjectReference{Name: comp.GetName()},
+					},
+					CompositionRevisionReferencer: fake.CompositionRevisionReferencer{
+						Ref: &corev1.ObjectReference{Name: rev1.GetName()},
+					},
+				},
+			},
+			want: want{
+				rev: rev2,
+			},
+		},
 		"SetRevisionError": {
 			reason: "We should return the latest revision and update our reference if none is set.",
 			client: resource.ClientApplicator{
@@ -494,6 +659,9 @@ func TestFetchRevision(t *testing.T) {
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
 			f := NewAPIRevisionFetcher(tc.client)
+			if tc.unsynced != nil {
+				f = NewAPIRevisionFetcher(tc.client, WithUnsyncedCompositeReader(tc.unsynced))
+			}
 			got, err := f.Fetch(tc.args.ctx, tc.args.cr)
 
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
@@ -998,3 +1166,51 @@ func TestAPINamingConfigurator(t *testing.T) {
 		})
 	}
 }
+
+func TestIndexUnsyncedCompositionRevision(t *testing.T) {
+	xr := func(rev string, c ...xpv1.Condition) client.Object {
+		xr := composite.New()
+		if rev != "" {
+			xr.SetCompositionRevisionReference(&corev1.ObjectReference{Name: rev})
+		}
+		xr.SetConditions(c...)
+		return &xr.Unstructured
+	}
+
+	cases := map[string]struct {
+		reason string
+		o      client.Object
+		want   []string
+	}{
+		"NotUnstructured": {
+			reason: "We should not index objects that aren't unstructured.",
+			o:      &v1.Composition{},
+		},
+		"NoRevision": {
+			reason: "We should not index composite resources that don't use a revision.",
+			o:      xr("", xpv1.ReconcileError(errors.New("boom"))),
+		},
+		"Synced": {
+			reason: "We should not index composite resources that are synced.",
+			o:      xr("cool-rev", xpv1.ReconcileSuccess()),
+		},
+		"SyncUnknown": {
+			reason: "We should not index composite resources that haven't synced yet.",
+			o:      xr("cool-rev"),
+		},
+		"Unsynced": {
+			reason: "We should index composite resources that fail to sync by the revision they use.",
+			o:      xr("cool-rev", xpv1.ReconcileError(errors.New("boom"))),
+			want:   []string{"cool-rev"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := IndexUnsyncedCompositionRevision(tc.o)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nIndexUnsyncedCompositionRevision(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
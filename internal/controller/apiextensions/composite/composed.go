@@ -16,6 +16,11 @@ specific language governing permissions and limitations under the License.
 package composite
 
 import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
@@ -37,6 +42,19 @@ type ComposedResource struct {
 	// Ready indicates whether this composed resource is ready - i.e. whether
 	// all of its readiness checks passed.
 	Ready bool
+
+	// ReadinessTimeout is how long this composed resource may be unready
+	// before its composite resource is considered degraded. Zero means it
+	// may be unready indefinitely.
+	ReadinessTimeout time.Duration
+
+	// UnreadySince is when this composed resource was created, or last
+	// stopped being ready. It's only meaningful if ReadinessTimeout is set.
+	UnreadySince time.Time
+
+	// ApplyError is the error encountered applying this composed resource,
+	// if any. A composed resource that couldn't be applied isn't ready.
+	ApplyError error
 }
 
 // ComposedResourceState represents a composed resource (either desired or
@@ -52,3 +70,13 @@ type ComposedResourceStates map[ResourceName]ComposedResourceState
 
 // ComposedResourceTemplates are the P&T templates for composed resources.
 type ComposedResourceTemplates map[ResourceName]v1.ComposedTemplate
+
+// unreadySince returns when the supplied composed resource was created, or
+// when it last stopped being ready, whichever is later.
+func unreadySince(cd resource.Composed) time.Time {
+	t := cd.GetCreationTimestamp().Time
+	if c := cd.GetCondition(xpv1.TypeReady); c.Status != corev1.ConditionTrue && c.LastTransitionTime.After(t) {
+		t = c.LastTransitionTime.Time
+	}
+	return t
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+)
+
+func TestUnreadySince(t *testing.T) {
+	created := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	transitioned := created.Add(time.Hour)
+
+	cases := map[string]struct {
+		reason string
+		cond   *xpv1.Condition
+		want   time.Time
+	}{
+		"NoReadyCondition": {
+			reason: "A composed resource without a Ready condition has been unready since it was created.",
+			want:   created,
+		},
+		"NotReady": {
+			reason: "A composed resource that stopped being ready after it was created has been unready since then.",
+			cond:   &xpv1.Condition{Type: xpv1.TypeReady, Status: "False", LastTransitionTime: metav1.NewTime(transitioned)},
+			want:   transitioned,
+		},
+		"Ready": {
+			reason: "A composed resource with a Ready condition that is True was last unready when it was created.",
+			cond:   &xpv1.Condition{Type: xpv1.TypeReady, Status: "True", LastTransitionTime: metav1.NewTime(transitioned)},
+			want:   created,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cd := composed.New()
+			cd.SetCreationTimestamp(metav1.NewTime(created))
+			if tc.cond != nil {
+				cd.SetConditions(*tc.cond)
+			}
+			got := unreadySince(cd)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nunreadySince(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
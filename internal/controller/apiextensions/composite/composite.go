@@ -27,6 +27,26 @@ import (
 // Annotation keys.
 const (
 	AnnotationKeyCompositionResourceName = "crossplane.io/composition-resource-name"
+
+	// AnnotationKeyReconcileNow can be set to any new value (e.g. a
+	// timestamp) to trigger immediate reconciliation of a composite resource,
+	// rather than waiting for its next poll. Crossplane doesn't otherwise
+	// interpret it; any change to an XR's annotations triggers a reconcile.
+	AnnotationKeyReconcileNow = "crossplane.io/reconcile-now"
+)
+
+// Label keys.
+const (
+	// LabelKeyAdoptableByComposite must be set to the name of a composite
+	// resource on an existing composed resource before that composite
+	// resource may adopt it.
+	LabelKeyAdoptableByComposite = "crossplane.io/adoptable-by-composite"
+
+	// LabelKeyAdoptableByClaimName and LabelKeyAdoptableByClaimNamespace may
+	// be set to the name and namespace of a claim on an existing composed
+	// resource instead. The claim's composite resource may then adopt it.
+	LabelKeyAdoptableByClaimName      = "crossplane.io/adoptable-by-claim-name"
+	LabelKeyAdoptableByClaimNamespace = "crossplane.io/adoptable-by-claim-namespace"
 )
 
 // SetCompositionResourceName sets the name of the composition template used to
@@ -22,7 +22,12 @@ import (
 	"reflect"
 	"sort"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/structpb"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
@@ -43,7 +48,9 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
 
 	"github.com/crossplane/crossplane/apis/apiextensions/fn/proto/v1beta1"
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	"github.com/crossplane/crossplane/internal/names"
+	"github.com/crossplane/crossplane/internal/xfn"
 )
 
 // Error strings.
@@ -69,6 +76,7 @@ const (
 	errFmtFetchCDConnectionDetails   = "cannot fetch connection details for composed resource %q (a %s named %s)"
 	errFmtUnmarshalPipelineStepInput = "cannot unmarshal input for Composition pipeline step %q"
 	errFmtRunPipelineStep            = "cannot run Composition pipeline step %q"
+	errFmtEvaluateStepCondition      = "cannot evaluate condition of Composition pipeline step %q"
 	errFmtDeleteCD                   = "cannot delete composed resource %q (a %s named %s)"
 	errFmtUnmarshalDesiredCD         = "cannot unmarshal desired composed resource %q from RunFunctionResponse"
 	errFmtCDAsStruct                 = "cannot encode composed resource %q to protocol buffer Struct well-known type"
@@ -106,12 +114,17 @@ const (
 	MaxRequirementsIterations = 5
 )
 
+// tracerName is the name of the OpenTelemetry tracer used to trace
+// composition.
+const tracerName = "github.com/crossplane/crossplane/internal/controller/apiextensions/composite"
+
 // A FunctionComposer supports composing resources using a pipeline of
 // Composition Functions. It ignores the P&T resources array.
 type FunctionComposer struct {
 	client    client.Client
 	composite xr
 	pipeline  FunctionRunner
+	applies   ApplyRecorder
 }
 
 type xr struct {
@@ -205,6 +218,14 @@ func WithExtraResourcesFetcher(f ExtraResourcesFetcher) FunctionComposerOption {
 	}
 }
 
+// WithFunctionComposerApplyRecorder configures how the FunctionComposer should
+// record applies of composed resources.
+func WithFunctionComposerApplyRecorder(r ApplyRecorder) FunctionComposerOption {
+	return func(p *FunctionComposer) {
+		p.applies = r
+	}
+}
+
 // WithComposedResourceGarbageCollector configures how the FunctionComposer should
 // garbage collect undesired composed resources.
 func WithComposedResourceGarbageCollector(d ComposedResourceGarbageCollector) FunctionComposerOption {
@@ -233,6 +254,7 @@ func NewFunctionComposer(kube client.Client, r FunctionRunner, o ...FunctionComp
 		},
 
 		pipeline: r,
+		applies:  NopApplyRecorder{},
 	}
 
 	for _, fn := range o {
@@ -243,7 +265,25 @@ func NewFunctionComposer(kube client.Client, r FunctionRunner, o ...FunctionComp
 }
 
 // Compose resources using the Functions pipeline.
-func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructured, req CompositionRequest) (CompositionResult, error) { //nolint:gocyclo // We probably don't want any further abstraction for the sake of reduced complexity.
+func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructured, req CompositionRequest) (CompositionResult, error) {
+	// Each Function run is traced as a child of this span.
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "Compose", trace.WithAttributes(
+		attribute.String("composite.apiVersion", xr.GetAPIVersion()),
+		attribute.String("composite.kind", xr.GetKind()),
+		attribute.String("composite.name", xr.GetName()),
+		attribute.String("composition.revision", req.Revision.GetName()),
+	))
+	defer span.End()
+
+	res, err := c.compose(ctx, xr, req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return res, err
+}
+
+func (c *FunctionComposer) compose(ctx context.Context, xr *composite.Unstructured, req CompositionRequest) (CompositionResult, error) { //nolint:gocyclo // We probably don't want any further abstraction for the sake of reduced complexity.
 	// Observe our existing composed resources. We need to do this before we
 	// render any P&T templates, so that we can make sure we use the same
 	// composed resource names (as in, metadata.name) every time. We know what
@@ -287,10 +327,24 @@ func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructur
 		fctx.Fields[FunctionContextKeyEnvironment] = structpb.NewStructValue(e)
 	}
 
+	// The number of pipeline steps that changed each desired composed
+	// resource. Their changes are batched into a single apply.
+	changes := map[ResourceName]int{}
+
 	// Run any Composition Functions in the pipeline. Each Function may mutate
 	// the desired state returned by the last, and each Function may produce
 	// results that will be emitted as events.
 	for _, fn := range req.Revision.Spec.Pipeline {
+		// Skip any step whose condition isn't met by the observed XR and
+		// the environment.
+		run, err := stepConditionMet(fn, xr, req.Environment)
+		if err != nil {
+			return CompositionResult{}, errors.Wrapf(err, errFmtEvaluateStepCondition, fn.Step)
+		}
+		if !run {
+			continue
+		}
+
 		req := &v1beta1.RunFunctionRequest{Observed: o, Desired: d, Context: fctx}
 
 		if fn.Input != nil {
@@ -352,6 +406,12 @@ func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructur
 			req.Context = rsp.GetContext()
 		}
 
+		for name, r := range rsp.GetDesired().GetResources() {
+			if !proto.Equal(r.GetResource(), d.GetResources()[name].GetResource()) {
+				changes[ResourceName(name)]++
+			}
+		}
+
 		// Pass the desired state returned by this Function to the next one.
 		d = rsp.GetDesired()
 
@@ -484,7 +544,9 @@ func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructur
 
 	// We apply all of our desired resources before we observe them in the loop
 	// below. This ensures that issues observing and processing one composed
-	// resource won't block the application of another.
+	// resource won't block the application of another. Likewise failing to
+	// apply one composed resource isn't terminal. We record the error and move
+	// on to the next.
 	for name, cd := range desired {
 		// We don't need any crossplane-runtime resource.Applicator style apply
 		// options here because server-side apply takes care of everything.
@@ -495,8 +557,12 @@ func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructur
 		// this prevents multiple XRs composing the same resource to be
 		// continuously alternated as controllers.
 		if err := c.client.Patch(ctx, cd.Resource, client.Apply, client.ForceOwnership, client.FieldOwner(ComposedFieldOwnerName(xr))); err != nil {
-			return CompositionResult{}, errors.Wrapf(err, errFmtApplyCD, name)
+			err = errors.Wrapf(err, errFmtApplyCD, name)
+			events = append(events, event.Warning(reasonCompose, err))
+			resources = append(resources, ComposedResource{ResourceName: name, Ready: false, ApplyError: err})
+			continue
 		}
+		c.applies.RecordApply(v1.CompositionModePipeline, cd.Resource.GetObjectKind().GroupVersionKind(), changes[name])
 
 		resources = append(resources, ComposedResource{ResourceName: name, Ready: cd.Ready})
 	}
@@ -783,3 +849,21 @@ func UpdateResourceRefs(xr resource.ComposedResourcesReferencer, desired Compose
 
 	xr.SetResourceReferences(refs)
 }
+
+// stepConditionMet returns true if the supplied pipeline step has no
+// condition, or if its condition evaluates to true for the supplied XR and
+// environment.
+func stepConditionMet(fn v1.PipelineStep, xr *composite.Unstructured, env *Environment) (bool, error) {
+	if fn.Condition == nil {
+		return true, nil
+	}
+	prg, err := xfn.CompileCondition(*fn.Condition)
+	if err != nil {
+		return false, err
+	}
+	var e map[string]any
+	if env != nil {
+		e = env.UnstructuredContent()
+	}
+	return xfn.EvaluateCondition(prg, xr.UnstructuredContent(), e)
+}
@@ -37,6 +37,7 @@ import (
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
@@ -61,7 +62,6 @@ func TestFunctionCompose(t *testing.T) {
 		o    []FunctionComposerOption
 	}
 	type args struct {
-		ctx context.Context
 		xr  *composite.Unstructured
 		req CompositionRequest
 	}
@@ -182,6 +182,84 @@ func TestFunctionCompose(t *testing.T) {
 				err: errors.Wrapf(errBoom, errFmtRunPipelineStep, "run-cool-function"),
 			},
 		},
+		"EvaluateStepConditionError": {
+			reason: "We should return any error encountered while evaluating a pipeline step's condition",
+			params: params{
+				o: []FunctionComposerOption{
+					WithCompositeConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(ctx context.Context, o resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+						return nil, nil
+					})),
+					WithComposedResourceObserver(ComposedResourceObserverFn(func(ctx context.Context, xr resource.Composite) (ComposedResourceStates, error) {
+						return nil, nil
+					})),
+				},
+			},
+			args: args{
+				xr: composite.New(),
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{
+						Spec: v1.CompositionRevisionSpec{
+							Pipeline: []v1.PipelineStep{
+								{
+									Step:        "run-cool-function",
+									FunctionRef: v1.FunctionReference{Name: "cool-function"},
+									Condition:   ptr.To(`"not a boolean"`),
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrapf(errors.New("expression must evaluate to a boolean, not string"), errFmtEvaluateStepCondition, "run-cool-function"),
+			},
+		},
+		"SkipStepConditionNotMet": {
+			reason: "We should skip a pipeline step whose condition evaluates to false",
+			params: params{
+				r: FunctionRunnerFn(func(ctx context.Context, name string, req *v1beta1.RunFunctionRequest) (rsp *v1beta1.RunFunctionResponse, err error) {
+					if name == "skipped-function" {
+						t.Errorf("RunFunction(...): unexpectedly ran a function whose condition was not met")
+					}
+					return nil, errBoom
+				}),
+				o: []FunctionComposerOption{
+					WithCompositeConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(ctx context.Context, o resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+						return nil, nil
+					})),
+					WithComposedResourceObserver(ComposedResourceObserverFn(func(ctx context.Context, xr resource.Composite) (ComposedResourceStates, error) {
+						return nil, nil
+					})),
+				},
+			},
+			args: args{
+				xr: func() *composite.Unstructured {
+					xr := composite.New()
+					_ = fieldpath.Pave(xr.Object).SetValue("spec.backup.enabled", false)
+					return xr
+				}(),
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{
+						Spec: v1.CompositionRevisionSpec{
+							Pipeline: []v1.PipelineStep{
+								{
+									Step:        "run-skipped-function",
+									FunctionRef: v1.FunctionReference{Name: "skipped-function"},
+									Condition:   ptr.To("has(xr.spec.backup) && xr.spec.backup.enabled"),
+								},
+								{
+									Step:        "run-cool-function",
+									FunctionRef: v1.FunctionReference{Name: "cool-function"},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrapf(errBoom, errFmtRunPipelineStep, "run-cool-function"),
+			},
+		},
 		"FatalFunctionResultError": {
 			reason: "We should return any fatal function results as an error",
 			params: params{
@@ -451,7 +529,7 @@ func TestFunctionCompose(t *testing.T) {
 			},
 		},
 		"ApplyComposedResourceError": {
-			reason: "We should return any error we encounter when applying a composed resource",
+			reason: "We should record, but not return, any error we encounter when applying a composed resource",
 			params: params{
 				kube: &test.MockClient{
 					MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{Resource: "UncoolComposed"}, "")), // all names are available
@@ -507,7 +585,16 @@ func TestFunctionCompose(t *testing.T) {
 				},
 			},
 			want: want{
-				err: errors.Wrapf(errBoom, errFmtApplyCD, "uncool-resource"),
+				res: CompositionResult{
+					Composed: []ComposedResource{{
+						ResourceName: "uncool-resource",
+						Ready:        false,
+						ApplyError:   errors.Wrapf(errBoom, errFmtApplyCD, "uncool-resource"),
+					}},
+					Events: []event.Event{
+						event.Warning(reasonCompose, errors.Wrapf(errBoom, errFmtApplyCD, "uncool-resource")),
+					},
+				},
 			},
 		},
 		"Successful": {
@@ -848,7 +935,7 @@ func TestFunctionCompose(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 
 			c := NewFunctionComposer(tc.params.kube, tc.params.r, tc.params.o...)
-			res, err := c.Compose(tc.args.ctx, tc.args.xr, tc.args.req)
+			res, err := c.Compose(context.Background(), tc.args.xr, tc.args.req)
 
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\nCompose(...): -want, +got:\n%s", tc.reason, diff)
@@ -856,7 +943,7 @@ func TestFunctionCompose(t *testing.T) {
 
 			// We iterate over a map to produce ComposedResources, so they're
 			// returned in random order.
-			if diff := cmp.Diff(tc.want.res, res, cmpopts.EquateEmpty(), cmpopts.SortSlices(func(i, j ComposedResource) bool { return i.ResourceName < j.ResourceName })); diff != "" {
+			if diff := cmp.Diff(tc.want.res, res, cmpopts.EquateEmpty(), test.EquateErrors(), cmpopts.SortSlices(func(i, j ComposedResource) bool { return i.ResourceName < j.ResourceName })); diff != "" {
 				t.Errorf("\n%s\nCompose(...): -want, +got:\n%s", tc.reason, diff)
 			}
 		})
@@ -23,6 +23,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -43,20 +45,22 @@ import (
 
 // Error strings
 const (
-	errGetComposed   = "cannot get composed resource"
-	errGCComposed    = "cannot garbage collect composed resource"
-	errApplyComposed = "cannot apply composed resource"
-	errFetchDetails  = "cannot fetch connection details"
-	errInline        = "cannot inline Composition patch sets"
+	errGetComposed  = "cannot get composed resource"
+	errGCComposed   = "cannot garbage collect composed resource"
+	errFetchDetails = "cannot fetch connection details"
+	errInline       = "cannot inline Composition patch sets"
 
 	errFmtPatchEnvironment           = "cannot apply environment patch at index %d"
 	errFmtParseBase                  = "cannot parse base template of composed resource %q"
+	errFmtApplyComposed              = "cannot apply composed resource %q"
 	errFmtRenderFromCompositePatches = "cannot render FromComposite or environment patches for composed resource %q"
 	errFmtRenderToCompositePatches   = "cannot render ToComposite patches for composed resource %q"
 	errFmtRenderMetadata             = "cannot render metadata for composed resource %q"
 	errFmtGenerateName               = "cannot generate a name for composed resource %q"
+	errFmtAdoptComposed              = "cannot adopt an existing resource for composed resource %q"
 	errFmtExtractDetails             = "cannot extract composite resource connection details from composed resource %q"
 	errFmtCheckReadiness             = "cannot check whether composed resource %q is ready"
+	errFmtRenderConnDetailTemplate   = "cannot render connection detail template %q"
 )
 
 // TODO(negz): Move P&T Composition logic into its own package?
@@ -80,6 +84,14 @@ func WithComposedNameGenerator(r names.NameGenerator) PTComposerOption {
 	}
 }
 
+// WithComposedResourceAdopter configures how a PatchAndTransformComposer
+// adopts existing composed resources.
+func WithComposedResourceAdopter(a ComposedResourceAdopter) PTComposerOption {
+	return func(c *PTComposer) {
+		c.composed.ComposedResourceAdopter = a
+	}
+}
+
 // WithComposedReadinessChecker configures how a PatchAndTransformComposer
 // checks composed resource readiness.
 func WithComposedReadinessChecker(r ReadinessChecker) PTComposerOption {
@@ -105,8 +117,17 @@ func WithComposedConnectionDetailsExtractor(e ConnectionDetailsExtractor) PTComp
 	}
 }
 
+// WithPTComposerApplyRecorder configures how a PatchAndTransformComposer
+// records applies of composed resources.
+func WithPTComposerApplyRecorder(r ApplyRecorder) PTComposerOption {
+	return func(c *PTComposer) {
+		c.applies = r
+	}
+}
+
 type composedResource struct {
 	names.NameGenerator
+	ComposedResourceAdopter
 	managed.ConnectionDetailsFetcher
 	ConnectionDetailsExtractor
 	ReadinessChecker
@@ -121,6 +142,7 @@ type PTComposer struct {
 
 	composition CompositionTemplateAssociator
 	composed    composedResource
+	applies     ApplyRecorder
 }
 
 // NewPTComposer returns a Composer that composes resources using Patch and
@@ -136,10 +158,12 @@ func NewPTComposer(kube client.Client, o ...PTComposerOption) *PTComposer {
 		composition: NewGarbageCollectingAssociator(kube),
 		composed: composedResource{
 			NameGenerator:              names.NewNameGenerator(kube),
+			ComposedResourceAdopter:    NewExistingResourceAdopter(kube),
 			ReadinessChecker:           ReadinessCheckerFn(IsReady),
 			ConnectionDetailsFetcher:   NewSecretConnectionDetailsFetcher(kube),
 			ConnectionDetailsExtractor: ConnectionDetailsExtractorFn(ExtractConnectionDetails),
 		},
+		applies: NopApplyRecorder{},
 	}
 
 	for _, fn := range o {
@@ -227,6 +251,16 @@ func (c *PTComposer) Compose(ctx context.Context, xr *composite.Unstructured, re
 			rendered = false
 		}
 
+		// Templates that adopt existing composed resources may only do so if
+		// this XR doesn't already reference a composed resource for them.
+		// Adopting an existing resource may name the rendered resource.
+		if ta.Template.Adoption != nil && ta.Reference.Name == "" {
+			if err := c.composed.Adopt(ctx, xr, r, ta.Template.Adoption); err != nil {
+				events = append(events, event.Warning(reasonCompose, errors.Wrapf(err, errFmtAdoptComposed, name)))
+				rendered = false
+			}
+		}
+
 		if err := c.composed.GenerateName(ctx, r); err != nil {
 			events = append(events, event.Warning(reasonCompose, errors.Wrapf(err, errFmtGenerateName, name)))
 			rendered = false
@@ -257,27 +291,27 @@ func (c *PTComposer) Compose(ctx context.Context, xr *composite.Unstructured, re
 
 	// We apply all of our composed resources before we observe them in the
 	// loop below. This ensures that issues observing and processing one
-	// composed resource won't block the application of another.
-	for i := range tas {
-		t := tas[i].Template
-		cd := cds[i]
-
-		// If we were unable to render the composed resource we should not try
-		// and apply it. The risk of doing so is that we successfully apply a
-		// partially-rendered composed resource that we can't later fix (e.g.
-		// due to an immutable field).
-		if cd == nil {
+	// composed resource won't block the application of another. All of a
+	// template's patches (including those from patch sets) have already been
+	// rendered, so we apply each composed resource once. If more than one
+	// template renders the same composed resource we batch them into one
+	// apply, rather than having them fight over its fields.
+	//
+	// Failing to apply one composed resource isn't terminal. We record the
+	// error and move on to the next, so that (for example) one flaky provider
+	// API doesn't block the rest of the XR's composed resources.
+	failed := map[int]error{}
+	for _, b := range batchComposedResources(tas, cds) {
+		if err := c.apply(ctx, xr, b); err != nil {
+			for _, i := range b.templates {
+				name := ptr.Deref(tas[i].Template.Name, fmt.Sprintf("resource %d", i+1))
+				err := errors.Wrapf(err, errFmtApplyComposed, name)
+				events = append(events, event.Warning(reasonCompose, err))
+				failed[i] = err
+			}
 			continue
 		}
-
-		o := []resource.ApplyOption{resource.MustBeControllableBy(xr.GetUID()), usage.RespectOwnerRefs()}
-		o = append(o, mergeOptions(filterPatches(t.Patches, patchTypesFromXR()...))...)
-		if err := c.client.Apply(ctx, cd, o...); err != nil {
-			// TODO(negz): Include the template name (if any) in this error.
-			// Including the rendered resource's kind may help too (e.g. if the
-			// template is anonymous).
-			return CompositionResult{}, errors.Wrap(err, errApplyComposed)
-		}
+		c.applies.RecordApply(v1.CompositionModeResources, b.cd.GetObjectKind().GroupVersionKind(), len(b.patches))
 	}
 
 	// Produce our array of resources to return to the Reconciler. The
@@ -286,6 +320,7 @@ func (c *PTComposer) Compose(ctx context.Context, xr *composite.Unstructured, re
 	// in tas - i.e. a resources resource for every resource template.
 	resources := make([]ComposedResource, len(tas))
 	xrConnDetails := managed.ConnectionDetails{}
+	cdsConnDetails := make(map[string]managed.ConnectionDetails, len(tas))
 	for i := range tas {
 		t := tas[i].Template
 		cd := cds[i]
@@ -302,6 +337,12 @@ func (c *PTComposer) Compose(ctx context.Context, xr *composite.Unstructured, re
 			continue
 		}
 
+		// Likewise if we were unable to apply it.
+		if err, ok := failed[i]; ok {
+			resources[i] = ComposedResource{ResourceName: name, Ready: false, ApplyError: err}
+			continue
+		}
+
 		if err := RenderToCompositePatches(xr, cd, t.Patches); err != nil {
 			// Failures to render ToComposite patches are terminal because this
 			// indicates a Required ToCompositeFieldPath patch failed; i.e. the
@@ -322,6 +363,7 @@ func (c *PTComposer) Compose(ctx context.Context, xr *composite.Unstructured, re
 		for key, val := range extracted {
 			xrConnDetails[key] = val
 		}
+		cdsConnDetails[string(name)] = cdConnDetails
 
 		ready, err := c.composed.IsReady(ctx, cd, ReadinessChecksFromComposedTemplate(&t)...)
 		if err != nil {
@@ -329,6 +371,22 @@ func (c *PTComposer) Compose(ctx context.Context, xr *composite.Unstructured, re
 		}
 
 		resources[i] = ComposedResource{ResourceName: name, Ready: ready}
+		if t.ReadinessTimeout != nil {
+			resources[i].ReadinessTimeout = t.ReadinessTimeout.Duration
+			resources[i].UnreadySince = unreadySince(cd)
+		}
+	}
+
+	// Render connection details that combine the connection details of
+	// several composed resources. A template that references connection
+	// details that don't exist yet is skipped until they do.
+	for _, t := range req.Revision.Spec.ConnectionDetailTemplates {
+		val, err := RenderConnectionDetailTemplate(t, cdsConnDetails)
+		if err != nil {
+			events = append(events, event.Warning(reasonCompose, errors.Wrapf(err, errFmtRenderConnDetailTemplate, t.Name)))
+			continue
+		}
+		xrConnDetails[t.Name] = val
 	}
 
 	// Call Apply so that we do not just replace fields on existing XR but
@@ -352,6 +410,65 @@ func (c *PTComposer) Compose(ctx context.Context, xr *composite.Unstructured, re
 	return CompositionResult{ConnectionDetails: xrConnDetails, Composed: resources, Events: events}, nil
 }
 
+// A composedBatch is a composed resource rendered by one or more templates.
+type composedBatch struct {
+	cd resource.Composed
+
+	// The indices of the templates that rendered the composed resource.
+	templates []int
+
+	// The patches the templates rendered into the composed resource.
+	patches []v1.Patch
+}
+
+// batchComposedResources batches the supplied rendered composed resources by
+// the resource they render, in template order. The composed resources of
+// templates that render the same resource are merged into the first, which
+// replaces them in the supplied array. Templates that didn't render a
+// composed resource aren't batched.
+func batchComposedResources(tas []TemplateAssociation, cds []resource.Composed) []*composedBatch {
+	type key struct {
+		gvk  schema.GroupVersionKind
+		name types.NamespacedName
+	}
+
+	batches := make([]*composedBatch, 0, len(cds))
+	named := map[key]*composedBatch{}
+	for i, cd := range cds {
+		if cd == nil {
+			continue
+		}
+		patches := filterPatches(tas[i].Template.Patches, patchTypesFromXR()...)
+
+		k := key{gvk: cd.GetObjectKind().GroupVersionKind(), name: types.NamespacedName{Namespace: cd.GetNamespace(), Name: cd.GetName()}}
+		if b, ok := named[k]; ok {
+			mergeRendered(b.cd.(runtime.Unstructured).UnstructuredContent(), cd.(runtime.Unstructured).UnstructuredContent())
+			b.templates = append(b.templates, i)
+			b.patches = append(b.patches, patches...)
+			cds[i] = b.cd
+			continue
+		}
+
+		b := &composedBatch{cd: cd, templates: []int{i}, patches: patches}
+		batches = append(batches, b)
+
+		// A composed resource that isn't named yet can't be the same as
+		// any other.
+		if k.name.Name != "" {
+			named[k] = b
+		}
+	}
+	return batches
+}
+
+// apply the supplied batch's composed resource. The patches of every template
+// in the batch may ask for their value to be merged with the existing value.
+func (c *PTComposer) apply(ctx context.Context, xr *composite.Unstructured, b *composedBatch) error {
+	o := []resource.ApplyOption{resource.MustBeControllableBy(xr.GetUID()), usage.RespectOwnerRefs()}
+	o = append(o, mergeOptions(b.patches)...)
+	return c.client.Apply(ctx, b.cd, o...)
+}
+
 // toXRPatchesFromTAs selects patches defined in composed templates,
 // whose type is one of the XR-targeting patches
 // (e.g. v1.PatchTypeToCompositeFieldPath or v1.PatchTypeCombineToComposite)
@@ -456,6 +573,12 @@ func (a *GarbageCollectingAssociator) AssociateTemplates(ctx context.Context, cr
 		tas[i] = TemplateAssociation{Template: ct[i]}
 	}
 
+	// Existing resources that aren't annotated with a template name, aren't
+	// controlled by anything, are labelled as adoptable by this XR, and are of
+	// a kind a template adopts may have been referenced so that they're
+	// adopted.
+	adoptable := make([]*composed.Unstructured, 0)
+
 	for _, ref := range cr.GetResourceReferences() {
 		// If reference does not have a name then we haven't rendered it yet.
 		if ref.Name == "" {
@@ -475,6 +598,10 @@ func (a *GarbageCollectingAssociator) AssociateTemplates(ctx context.Context, cr
 		}
 
 		name := GetCompositionResourceName(cd)
+		if _, ok := adoptingTemplate(tas, cd); ok && name == "" && metav1.GetControllerOf(cd) == nil && adoptableBy(cd, cr) {
+			adoptable = append(adoptable, cd)
+			continue
+		}
 		if name == "" {
 			// All of our templates are named, but this existing composed
 			// resource is not associated with a named template. It's likely
@@ -506,6 +633,18 @@ func (a *GarbageCollectingAssociator) AssociateTemplates(ctx context.Context, cr
 		}
 	}
 
+	// Associate each referenced resource we may adopt with the only template
+	// that adopts resources of its kind. If we can't, we fall back to
+	// assuming that the existing resource reference array already matches
+	// the order of our resource template array.
+	for _, cd := range adoptable {
+		i, ok := adoptingTemplate(tas, cd)
+		if !ok {
+			return AssociateByOrder(ct, cr.GetResourceReferences()), nil
+		}
+		tas[i].Reference = *meta.ReferenceTo(cd, cd.GetObjectKind().GroupVersionKind())
+	}
+
 	return tas, nil
 }
 
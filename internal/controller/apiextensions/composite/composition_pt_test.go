@@ -33,9 +33,11 @@ import (
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
@@ -175,13 +177,17 @@ func TestPTCompose(t *testing.T) {
 			},
 		},
 		"ApplyComposedError": {
-			reason: "We should return any error encountered while applying a composed resource.",
+			reason: "We should record, but not return, any error encountered while applying a composed resource.",
 			params: params{
 				kube: &test.MockClient{
 					MockUpdate: test.NewMockUpdateFn(nil),
 
 					// Apply calls Create because GenerateName is set.
 					MockCreate: test.NewMockCreateFn(errBoom),
+
+					// Applying the XR uses Get and Patch.
+					MockGet:   test.NewMockGetFn(nil),
+					MockPatch: test.NewMockPatchFn(nil),
 				},
 				o: []PTComposerOption{
 					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(ctx context.Context, c resource.Composite, ct []v1.ComposedTemplate) ([]TemplateAssociation, error) {
@@ -203,7 +209,16 @@ func TestPTCompose(t *testing.T) {
 				},
 			},
 			want: want{
-				err: errors.Wrap(errors.Wrap(errBoom, "cannot create object"), errApplyComposed),
+				res: CompositionResult{
+					Composed: []ComposedResource{{
+						ResourceName: "cool-resource",
+						Ready:        false,
+						ApplyError:   errors.Wrapf(errors.Wrap(errBoom, "cannot create object"), errFmtApplyComposed, "cool-resource"),
+					}},
+					Events: []event.Event{
+						event.Warning(reasonCompose, errors.Wrapf(errors.Wrap(errBoom, "cannot create object"), errFmtApplyComposed, "cool-resource")),
+					},
+				},
 			},
 		},
 		"FetchConnectionDetailsError": {
@@ -478,7 +493,7 @@ func TestPTCompose(t *testing.T) {
 			c := NewPTComposer(tc.params.kube, tc.params.o...)
 			res, err := c.Compose(tc.args.ctx, tc.args.xr, tc.args.req)
 
-			if diff := cmp.Diff(tc.want.res, res, cmpopts.EquateEmpty()); diff != "" {
+			if diff := cmp.Diff(tc.want.res, res, cmpopts.EquateEmpty(), test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\nCompose(...): -want, +got:\n%s", tc.reason, diff)
 			}
 
@@ -626,6 +641,82 @@ func TestGarbageCollectingAssociator(t *testing.T) {
 				tas: []TemplateAssociation{{Template: t0, Reference: r0}},
 			},
 		},
+		"AdoptedResource": {
+			reason: "We should associate a referenced resource that isn't annotated or controlled, but is adoptable, with the only template that adopts resources of its kind.",
+			c: &test.MockClient{
+				// Return an unannotated and uncontrolled composed resource
+				// that's labelled as adoptable by the XR.
+				MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+					obj.SetLabels(map[string]string{LabelKeyAdoptableByComposite: "cool-xr"})
+					return nil
+				}),
+			},
+			args: args{
+				cr: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{Name: "cool-xr"},
+					ComposedResourcesReferencer: fake.ComposedResourcesReferencer{Refs: []corev1.ObjectReference{
+						{APIVersion: "example.org/v1", Kind: "Bucket", Name: "existing"},
+					}},
+				},
+				ct: []v1.ComposedTemplate{
+					t0,
+					{
+						Name:     ptr.To("bucket"),
+						Base:     runtime.RawExtension{Raw: []byte(`{"apiVersion":"example.org/v1","kind":"Bucket"}`)},
+						Adoption: &v1.ComposedResourceAdoption{},
+					},
+				},
+			},
+			want: want{
+				tas: []TemplateAssociation{
+					{Template: t0},
+					{
+						Template: v1.ComposedTemplate{
+							Name:     ptr.To("bucket"),
+							Base:     runtime.RawExtension{Raw: []byte(`{"apiVersion":"example.org/v1","kind":"Bucket"}`)},
+							Adoption: &v1.ComposedResourceAdoption{},
+						},
+						Reference: corev1.ObjectReference{APIVersion: "example.org/v1", Kind: "Bucket", Name: "existing"},
+					},
+				},
+			},
+		},
+		"NotAdoptableResource": {
+			reason: "We should fall back to associating templates with references by order if a referenced resource isn't labelled as adoptable by the XR.",
+			c: &test.MockClient{
+				// Return an empty (and thus unannotated, uncontrolled, and
+				// unlabelled) composed resource.
+				MockGet: test.NewMockGetFn(nil),
+			},
+			args: args{
+				cr: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{Name: "cool-xr"},
+					ComposedResourcesReferencer: fake.ComposedResourcesReferencer{Refs: []corev1.ObjectReference{
+						{APIVersion: "example.org/v1", Kind: "Bucket", Name: "existing"},
+					}},
+				},
+				ct: []v1.ComposedTemplate{
+					t0,
+					{
+						Name:     ptr.To("bucket"),
+						Base:     runtime.RawExtension{Raw: []byte(`{"apiVersion":"example.org/v1","kind":"Bucket"}`)},
+						Adoption: &v1.ComposedResourceAdoption{},
+					},
+				},
+			},
+			want: want{
+				tas: []TemplateAssociation{
+					{Template: t0, Reference: corev1.ObjectReference{APIVersion: "example.org/v1", Kind: "Bucket", Name: "existing"}},
+					{
+						Template: v1.ComposedTemplate{
+							Name:     ptr.To("bucket"),
+							Base:     runtime.RawExtension{Raw: []byte(`{"apiVersion":"example.org/v1","kind":"Bucket"}`)},
+							Adoption: &v1.ComposedResourceAdoption{},
+						},
+					},
+				},
+			},
+		},
 		"AssociatedResource": {
 			reason: "We should associate referenced resources by their template name annotation.",
 			c: &test.MockClient{
@@ -769,3 +860,85 @@ func TestGarbageCollectingAssociator(t *testing.T) {
 		})
 	}
 }
+
+func TestBatchComposedResources(t *testing.T) {
+	// rendered returns a rendered composed resource with the supplied name and
+	// spec.
+	rendered := func(name string, spec map[string]any) resource.Composed {
+		cd := composed.New()
+		cd.SetAPIVersion("example.org/v1")
+		cd.SetKind("Bucket")
+		cd.SetName(name)
+		_ = fieldpath.Pave(cd.Object).SetValue("spec", spec)
+		return cd
+	}
+
+	patch := v1.Patch{Type: v1.PatchTypeFromCompositeFieldPath, FromFieldPath: ptr.To("spec.region")}
+	tas := []TemplateAssociation{
+		{Template: v1.ComposedTemplate{Patches: []v1.Patch{patch}}},
+		{Template: v1.ComposedTemplate{Patches: []v1.Patch{patch, patch}}},
+		{Template: v1.ComposedTemplate{}},
+		{Template: v1.ComposedTemplate{Patches: []v1.Patch{patch, {Type: v1.PatchTypeToCompositeFieldPath}}}},
+		{Template: v1.ComposedTemplate{}},
+	}
+
+	type want struct {
+		batches []*composedBatch
+		cds     []resource.Composed
+	}
+
+	cases := map[string]struct {
+		reason string
+		cds    []resource.Composed
+		want   want
+	}{
+		"BatchSameResource": {
+			reason: "Templates that render the same composed resource should be merged into a single batch, in template order.",
+			cds: []resource.Composed{
+				rendered("cool-bucket", map[string]any{"region": "us-west-2", "tags": map[string]any{"a": "a"}}),
+				rendered("other-bucket", map[string]any{"region": "eu-west-1"}),
+				nil,
+				rendered("cool-bucket", map[string]any{"size": "big", "tags": map[string]any{"b": "b"}}),
+				rendered("", map[string]any{"region": "us-east-1"}),
+			},
+			want: want{
+				batches: []*composedBatch{
+					{
+						cd:        rendered("cool-bucket", map[string]any{"region": "us-west-2", "size": "big", "tags": map[string]any{"a": "a", "b": "b"}}),
+						templates: []int{0, 3},
+						patches:   []v1.Patch{patch, patch},
+					},
+					{
+						cd:        rendered("other-bucket", map[string]any{"region": "eu-west-1"}),
+						templates: []int{1},
+						patches:   []v1.Patch{patch, patch},
+					},
+					{
+						cd:        rendered("", map[string]any{"region": "us-east-1"}),
+						templates: []int{4},
+						patches:   []v1.Patch{},
+					},
+				},
+				cds: []resource.Composed{
+					rendered("cool-bucket", map[string]any{"region": "us-west-2", "size": "big", "tags": map[string]any{"a": "a", "b": "b"}}),
+					rendered("other-bucket", map[string]any{"region": "eu-west-1"}),
+					nil,
+					rendered("cool-bucket", map[string]any{"region": "us-west-2", "size": "big", "tags": map[string]any{"a": "a", "b": "b"}}),
+					rendered("", map[string]any{"region": "us-east-1"}),
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := batchComposedResources(tas, tc.cds)
+			if diff := cmp.Diff(tc.want.batches, got, cmp.AllowUnexported(composedBatch{})); diff != "" {
+				t.Errorf("\n%s\nbatchComposedResources(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cds, tc.cds); diff != "" {
+				t.Errorf("\n%s\nbatchComposedResources(...): -want composed resources, +got composed resources:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -28,6 +28,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"text/template"
 
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -67,9 +68,12 @@ const (
 	errStringTransformTypeTrim          = "string transform of type %s trim is not set"
 	errStringTransformTypeRegexp        = "string transform of type %s regexp is not set"
 	errStringTransformTypeJoin          = "string transform of type %s join is not set"
+	errStringTransformTypeTemplate      = "string transform of type %s template is not set"
 	errStringTransformTypeJoinFailed    = "cannot join non-array values"
 	errStringTransformTypeRegexpFailed  = "could not compile regexp"
 	errStringTransformTypeRegexpNoMatch = "regexp %q had no matches for group %d"
+	errStringTransformTypeTemplateParse = "cannot parse template"
+	errStringTransformTypeTemplateExec  = "cannot execute template"
 	errStringConvertTypeFailed          = "type %s is not supported for string convert"
 
 	errDecodeString = "string is not valid base64"
@@ -310,6 +314,11 @@ func ResolveString(t v1.StringTransform, input any) (string, error) { //nolint:g
 			return "", errors.Errorf(errStringTransformTypeJoin, string(t.Type))
 		}
 		return stringJoinTransform(input, *t.Join)
+	case v1.StringTransformTypeTemplate:
+		if t.Template == nil {
+			return "", errors.Errorf(errStringTransformTypeTemplate, string(t.Type))
+		}
+		return stringTemplateTransform(input, *t.Template)
 	default:
 		return "", errors.Errorf(errStringTransformTypeFailed, string(t.Type))
 	}
@@ -404,6 +413,18 @@ func stringJoinTransform(input any, r v1.StringTransformJoin) (string, error) {
 	return strings.Join(stringList, r.Separator), nil
 }
 
+func stringTemplateTransform(input any, tmpl string) (string, error) {
+	t, err := template.New("").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", errors.Wrap(err, errStringTransformTypeTemplateParse)
+	}
+	b := &strings.Builder{}
+	if err := t.Execute(b, input); err != nil {
+		return "", errors.Wrap(err, errStringTransformTypeTemplateExec)
+	}
+	return b.String(), nil
+}
+
 // ResolveConvert resolves a Convert transform by looking up the appropriate
 // conversion function for the given input type and invoking it.
 func ResolveConvert(t v1.ConvertTransform, input any) (any, error) {
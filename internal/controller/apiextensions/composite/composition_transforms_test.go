@@ -654,6 +654,7 @@ func TestStringResolve(t *testing.T) {
 		trim    *string
 		regexp  *v1.StringTransformRegexp
 		join    *v1.StringTransformJoin
+		tmpl    *string
 		i       any
 	}
 	type want struct {
@@ -1029,6 +1030,55 @@ func TestStringResolve(t *testing.T) {
 				err: errors.New(errStringTransformTypeJoinFailed),
 			},
 		},
+		"TemplateNotSet": {
+			args: args{
+				stype: v1.StringTransformTypeTemplate,
+				i:     "value",
+			},
+			want: want{
+				err: errors.Errorf(errStringTransformTypeTemplate, string(v1.StringTransformTypeTemplate)),
+			},
+		},
+		"TemplateObject": {
+			args: args{
+				stype: v1.StringTransformTypeTemplate,
+				tmpl:  ptr.To("postgres://{{ .host }}:{{ .port }}/{{ .db }}"),
+				i: map[string]any{
+					"host": "db.example.org",
+					"port": 5432.0,
+					"db":   "cool",
+				},
+			},
+			want: want{
+				o: "postgres://db.example.org:5432/cool",
+			},
+		},
+		"TemplateMissingKey": {
+			args: args{
+				stype: v1.StringTransformTypeTemplate,
+				tmpl:  ptr.To("postgres://{{ .host }}:{{ .port }}/{{ .db }}"),
+				i: map[string]any{
+					"host": "db.example.org",
+					"port": 5432.0,
+				},
+			},
+			want: want{
+				err: errors.Wrap(errors.New(`template: :1:38: executing "" at <.db>: map has no entry for key "db"`), errStringTransformTypeTemplateExec),
+			},
+		},
+		"TemplateConditional": {
+			args: args{
+				stype: v1.StringTransformTypeTemplate,
+				tmpl:  ptr.To("{{ .name }}{{ if .ha }}-ha{{ end }}"),
+				i: map[string]any{
+					"name": "cool",
+					"ha":   true,
+				},
+			},
+			want: want{
+				o: "cool-ha",
+			},
+		},
 		"ConvertToJSONSuccess": {
 			args: args{
 				stype:   v1.StringTransformTypeConvert,
@@ -1059,11 +1109,12 @@ func TestStringResolve(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 
 			tr := v1.StringTransform{Type: tc.stype,
-				Format:  tc.fmts,
-				Convert: tc.convert,
-				Trim:    tc.trim,
-				Regexp:  tc.regexp,
-				Join:    tc.join,
+				Format:   tc.fmts,
+				Convert:  tc.convert,
+				Trim:     tc.trim,
+				Regexp:   tc.regexp,
+				Join:     tc.join,
+				Template: tc.tmpl,
 			}
 
 			got, err := ResolveString(tr, tc.i)
@@ -17,7 +17,9 @@ limitations under the License.
 package composite
 
 import (
+	"bytes"
 	"context"
+	"text/template"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -42,6 +44,9 @@ const (
 	errFmtConnDetailKey  = "connection detail of type %q key is not set"
 	errFmtConnDetailVal  = "connection detail of type %q value is not set"
 	errFmtConnDetailPath = "connection detail of type %q fromFieldPath is not set"
+
+	errParseConnDetailTemplate   = "cannot parse connection detail template"
+	errExecuteConnDetailTemplate = "cannot execute connection detail template"
 )
 
 // A ConnectionDetailsFetcherFn fetches the connection details of the supplied
@@ -340,3 +345,28 @@ func fromFieldPath(from runtime.Object, path string) ([]byte, error) {
 
 	return json.Marshal(in)
 }
+
+// RenderConnectionDetailTemplate renders the supplied connection detail
+// template using the supplied connection details of composed resources, keyed
+// by composed resource name. It returns an error if the template references a
+// connection detail that doesn't exist.
+func RenderConnectionDetailTemplate(t v1.ConnectionDetailTemplate, cds map[string]managed.ConnectionDetails) ([]byte, error) {
+	tmpl, err := template.New(t.Name).Option("missingkey=error").Parse(t.Template)
+	if err != nil {
+		return nil, errors.Wrap(err, errParseConnDetailTemplate)
+	}
+
+	data := make(map[string]map[string]string, len(cds))
+	for name, cd := range cds {
+		data[name] = make(map[string]string, len(cd))
+		for k, v := range cd {
+			data[name][k] = string(v)
+		}
+	}
+
+	b := &bytes.Buffer{}
+	if err := tmpl.Execute(b, data); err != nil {
+		return nil, errors.Wrap(err, errExecuteConnDetailTemplate)
+	}
+	return b.Bytes(), nil
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+// Error strings.
+const (
+	errFmtDeleteComposed = "cannot delete composed resource %q"
+)
+
+// A ComposedResourceDeleter deletes the composed resources of a composite
+// resource that is being deleted.
+type ComposedResourceDeleter interface {
+	// DeleteComposedResources deletes the supplied composite resource's
+	// composed resources. It returns true once they are all gone, or if the
+	// composite resource doesn't require them to be deleted before it is.
+	DeleteComposedResources(ctx context.Context, xr resource.Composite) (bool, error)
+}
+
+// A ComposedResourceDeleterFn deletes the composed resources of a composite
+// resource that is being deleted.
+type ComposedResourceDeleterFn func(ctx context.Context, xr resource.Composite) (bool, error)
+
+// DeleteComposedResources deletes the supplied composite resource's composed
+// resources.
+func (fn ComposedResourceDeleterFn) DeleteComposedResources(ctx context.Context, xr resource.Composite) (bool, error) {
+	return fn(ctx, xr)
+}
+
+// An OrderedComposedResourceDeleter deletes composed resources in the order
+// declared by the deletion order of a composite resource's composition
+// revision. A composed resource is deleted only once all of the composed
+// resources it must be deleted after are gone.
+type OrderedComposedResourceDeleter struct {
+	client client.Client
+}
+
+// NewOrderedComposedResourceDeleter returns a new ComposedResourceDeleter
+// that deletes composed resources in the order declared by their composite
+// resource's composition revision.
+func NewOrderedComposedResourceDeleter(c client.Client) *OrderedComposedResourceDeleter {
+	return &OrderedComposedResourceDeleter{client: c}
+}
+
+// DeleteComposedResources deletes the supplied composite resource's composed
+// resources in order. Composite resources whose composition revision doesn't
+// declare a deletion order are left to the garbage collector.
+func (d *OrderedComposedResourceDeleter) DeleteComposedResources(ctx context.Context, xr resource.Composite) (bool, error) { //nolint:gocyclo // Only slightly over.
+	ref := xr.GetCompositionRevisionReference()
+	if ref == nil {
+		return true, nil
+	}
+
+	// Without its composition revision we can't know the deletion order, so
+	// we leave the composed resources to the garbage collector rather than
+	// block deletion of the composite resource.
+	rev := &v1.CompositionRevision{}
+	err := d.client.Get(ctx, types.NamespacedName{Name: ref.Name}, rev)
+	if kerrors.IsNotFound(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, errors.Wrap(err, errGetCompositionRevision)
+	}
+	if len(rev.Spec.DeletionOrder) == 0 {
+		return true, nil
+	}
+
+	after := make(map[ResourceName][]ResourceName, len(rev.Spec.DeletionOrder))
+	for _, dep := range rev.Spec.DeletionOrder {
+		for _, a := range dep.After {
+			after[ResourceName(dep.Resource)] = append(after[ResourceName(dep.Resource)], ResourceName(a))
+		}
+	}
+
+	existing := make(map[ResourceName]bool)
+	crs := make([]*composed.Unstructured, 0, len(xr.GetResourceReferences()))
+	for _, ref := range xr.GetResourceReferences() {
+		// References to resources that failed to render have no name.
+		if ref.Name == "" {
+			continue
+		}
+
+		cd := composed.New(composed.FromReference(ref))
+		err := d.client.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, cd)
+		if kerrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return false, errors.Wrap(err, errGetComposed)
+		}
+
+		// We don't delete resources we don't control.
+		if c := metav1.GetControllerOf(cd); c == nil || c.UID != xr.GetUID() {
+			continue
+		}
+
+		existing[GetCompositionResourceName(cd)] = true
+		crs = append(crs, cd)
+	}
+
+	for _, cd := range crs {
+		if meta.WasDeleted(cd) || blocked(after[GetCompositionResourceName(cd)], existing) {
+			continue
+		}
+		if err := d.client.Delete(ctx, cd); resource.IgnoreNotFound(err) != nil {
+			return false, errors.Wrapf(err, errFmtDeleteComposed, GetCompositionResourceName(cd))
+		}
+	}
+
+	return len(crs) == 0, nil
+}
+
+// blocked returns true if any of the supplied composed resources still exist.
+func blocked(after []ResourceName, existing map[ResourceName]bool) bool {
+	for _, a := range after {
+		if existing[a] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+func TestOrderedComposedResourceDeleter(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	xr := func() *composite.Unstructured {
+		xr := composite.New()
+		xr.SetUID("xr-uid")
+		xr.SetCompositionRevisionReference(&corev1.ObjectReference{Name: "rev"})
+		xr.SetResourceReferences([]corev1.ObjectReference{
+			{APIVersion: "example.org/v1", Kind: "Zone", Name: "zone"},
+			{APIVersion: "example.org/v1", Kind: "Record", Name: "record"},
+		})
+		return xr
+	}
+
+	ordered := &v1.CompositionRevision{
+		Spec: v1.CompositionRevisionSpec{
+			DeletionOrder: []v1.DeletionDependency{
+				{Resource: "zone", After: []string{"record"}},
+			},
+		},
+	}
+
+	// getComposed returns a MockGetFn that returns the supplied revision, and
+	// composed resources controlled by the XR with the supplied names.
+	getComposed := func(rev *v1.CompositionRevision, exist ...string) test.MockGetFn {
+		return func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+			switch o := obj.(type) {
+			case *v1.CompositionRevision:
+				rev.DeepCopyInto(o)
+				return nil
+			case *composed.Unstructured:
+				for _, n := range exist {
+					if key.Name != n {
+						continue
+					}
+					SetCompositionResourceName(o, ResourceName(n))
+					o.SetOwnerReferences([]metav1.OwnerReference{{UID: "xr-uid", Controller: ptr.To(true)}})
+					return nil
+				}
+			}
+			return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+		}
+	}
+
+	type args struct {
+		client client.Client
+		xr     resource.Composite
+	}
+	type want struct {
+		deleted bool
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoCompositionRevision": {
+			reason: "We should leave composed resources to the garbage collector if the XR has no composition revision.",
+			args: args{
+				xr: composite.New(),
+			},
+			want: want{
+				deleted: true,
+			},
+		},
+		"GetCompositionRevisionError": {
+			reason: "We should return any error encountered getting the composition revision.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(errBoom),
+				},
+				xr: xr(),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetCompositionRevision),
+			},
+		},
+		"CompositionRevisionNotFound": {
+			reason: "We should leave composed resources to the garbage collector if the composition revision no longer exists.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "rev")),
+				},
+				xr: xr(),
+			},
+			want: want{
+				deleted: true,
+			},
+		},
+		"NoDeletionOrder": {
+			reason: "We should leave composed resources to the garbage collector if the composition revision doesn't declare a deletion order.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: getComposed(&v1.CompositionRevision{}, "zone", "record"),
+				},
+				xr: xr(),
+			},
+			want: want{
+				deleted: true,
+			},
+		},
+		"DeleteUnblockedResources": {
+			reason: "We should only delete composed resources that aren't waiting for other composed resources to be deleted.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: getComposed(ordered, "zone", "record"),
+					MockDelete: func(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+						if obj.GetName() != "record" {
+							t.Errorf("Delete(...): unexpectedly deleted %q before its dependents", obj.GetName())
+						}
+						return nil
+					},
+				},
+				xr: xr(),
+			},
+			want: want{
+				deleted: false,
+			},
+		},
+		"DeleteError": {
+			reason: "We should return any error encountered deleting a composed resource.",
+			args: args{
+				client: &test.MockClient{
+					MockGet:    getComposed(ordered, "zone"),
+					MockDelete: test.NewMockDeleteFn(errBoom),
+				},
+				xr: xr(),
+			},
+			want: want{
+				err: errors.Wrapf(errBoom, errFmtDeleteComposed, "zone"),
+			},
+		},
+		"AllDeleted": {
+			reason: "We should return true once all composed resources are gone.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: getComposed(ordered),
+				},
+				xr: xr(),
+			},
+			want: want{
+				deleted: true,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			d := NewOrderedComposedResourceDeleter(tc.args.client)
+			deleted, err := d.DeleteComposedResources(context.Background(), tc.args.xr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nDeleteComposedResources(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.deleted, deleted); diff != "" {
+				t.Errorf("\n%s\nDeleteComposedResources(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -27,6 +27,9 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	v1alpha1 "github.com/crossplane/crossplane/apis/apiextensions/v1alpha1"
 )
 
@@ -35,6 +38,8 @@ const (
 	errFetchEnvironmentConfigs = "cannot fetch environment configs"
 	errMergeData               = "failed to merge data"
 
+	errFmtParseMergeStrategyKey = "cannot parse merge strategy key %q"
+
 	environmentGroup   = "internal.crossplane.io"
 	environmentVersion = "v1alpha1"
 	environmentKind    = "Environment"
@@ -77,48 +82,64 @@ type APIEnvironmentFetcher struct {
 // Note: The `.Data` path is trimmed from the result so its necessary to include
 // it in patches.
 func (f *APIEnvironmentFetcher) Fetch(ctx context.Context, req EnvironmentFetcherRequest) (*Environment, error) {
-	env, err := f.fetchEnvironment(ctx, req)
+	loadedConfigs, err := f.fetchEnvironmentConfigs(ctx, req)
 	if err != nil {
-		return nil, err
+		return nil, errors.Wrap(err, errFetchEnvironmentConfigs)
+	}
+	var defaults map[string]extv1.JSON
+	var strategies []v1.EnvironmentKeyMergeStrategy
+	if req.Revision != nil && req.Revision.Spec.Environment != nil {
+		defaults = req.Revision.Spec.Environment.DefaultData
+		strategies = req.Revision.Spec.Environment.MergeStrategies
 	}
+	return newEnvironment(defaults, loadedConfigs, strategies)
+}
 
-	// GVK is necessary for patching because it uses unstructured conversion
-	env.SetGroupVersionKind(schema.GroupVersionKind{
-		Group:   environmentGroup,
-		Version: environmentVersion,
-		Kind:    environmentKind,
-	})
-	return env, nil
+// NewEnvironment merges the `.Data` of the supplied EnvironmentConfigs, in
+// order, into a single Environment. Later EnvironmentConfigs take precedence,
+// unless a merge strategy for a key specifies otherwise.
+func NewEnvironment(configs []*v1alpha1.EnvironmentConfig, strategies ...v1.EnvironmentKeyMergeStrategy) (*Environment, error) {
+	return newEnvironment(nil, configs, strategies)
 }
 
-func (f *APIEnvironmentFetcher) fetchEnvironment(ctx context.Context, req EnvironmentFetcherRequest) (*Environment, error) {
-	loadedConfigs, err := f.fetchEnvironmentConfigs(ctx, req)
+func newEnvironment(defaults map[string]extv1.JSON, configs []*v1alpha1.EnvironmentConfig, strategies []v1.EnvironmentKeyMergeStrategy) (*Environment, error) {
+	ms, err := newMergeStrategies(strategies)
 	if err != nil {
-		return nil, errors.Wrap(err, errFetchEnvironmentConfigs)
+		return nil, errors.Wrap(err, errMergeData)
 	}
-
-	mergedData, err := mergeEnvironmentData(loadedConfigs)
+	mergedData, err := mergeEnvironmentData(configs, ms)
 	if err != nil {
 		return nil, errors.Wrap(err, errMergeData)
 	}
-	return &Environment{
+
+	// Default data is overwritten by all selected environment configs,
+	// regardless of their merge strategies.
+	if defaults != nil {
+		defaultData, err := unmarshalData(defaults)
+		if err != nil {
+			return nil, errors.Wrap(err, errMergeData)
+		}
+		mergedData = mergeStrategies{}.merge(defaultData, mergedData, nil)
+	}
+
+	env := &Environment{
 		unstructured.Unstructured{
 			Object: mergedData,
 		},
-	}, nil
+	}
+
+	// GVK is necessary for patching because it uses unstructured conversion
+	env.SetGroupVersionKind(schema.GroupVersionKind{
+		Group:   environmentGroup,
+		Version: environmentVersion,
+		Kind:    environmentKind,
+	})
+	return env, nil
 }
 
 func (f *APIEnvironmentFetcher) fetchEnvironmentConfigs(ctx context.Context, req EnvironmentFetcherRequest) ([]*v1alpha1.EnvironmentConfig, error) {
 	loadedConfigs := []*v1alpha1.EnvironmentConfig{}
 
-	// If the user provides a default environment with the composition, add it
-	// as a dummy environment config that is overwritten by all others.
-	if req.Revision != nil && req.Revision.Spec.Environment != nil && req.Revision.Spec.Environment.DefaultData != nil {
-		loadedConfigs = append(loadedConfigs, &v1alpha1.EnvironmentConfig{
-			Data: req.Revision.Spec.Environment.DefaultData,
-		})
-	}
-
 	refs := req.Composite.GetEnvironmentConfigReferences()
 	for _, ref := range refs {
 		config := &v1alpha1.EnvironmentConfig{}
@@ -138,7 +159,7 @@ func (f *APIEnvironmentFetcher) fetchEnvironmentConfigs(ctx context.Context, req
 	return loadedConfigs, nil
 }
 
-func mergeEnvironmentData(configs []*v1alpha1.EnvironmentConfig) (map[string]interface{}, error) {
+func mergeEnvironmentData(configs []*v1alpha1.EnvironmentConfig, ms mergeStrategies) (map[string]interface{}, error) {
 	merged := map[string]interface{}{}
 	for _, e := range configs {
 		if e == nil || e.Data == nil {
@@ -148,7 +169,7 @@ func mergeEnvironmentData(configs []*v1alpha1.EnvironmentConfig) (map[string]int
 		if err != nil {
 			return nil, err
 		}
-		merged = mergeMaps(merged, data)
+		merged = ms.merge(merged, data, nil)
 	}
 	return merged, nil
 }
@@ -165,23 +186,58 @@ func unmarshalData(data map[string]extv1.JSON) (map[string]interface{}, error) {
 	return res, nil
 }
 
-// mergeMaps merges b into a.
-// Extracted from https://stackoverflow.com/a/70291996
-func mergeMaps(a, b map[string]interface{}) map[string]interface{} {
+// mergeStrategies maps normalized field paths to the strategy used to merge
+// the values at them.
+type mergeStrategies map[string]v1.EnvironmentMergeStrategy
+
+func newMergeStrategies(strategies []v1.EnvironmentKeyMergeStrategy) (mergeStrategies, error) {
+	ms := make(mergeStrategies, len(strategies))
+	for _, s := range strategies {
+		p, err := fieldpath.Parse(s.Key)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtParseMergeStrategyKey, s.Key)
+		}
+		ms[p.String()] = s.Strategy
+	}
+	return ms, nil
+}
+
+// merge merges b, found at the supplied path, into a. Values are deep merged
+// unless a merge strategy specifies otherwise for their path.
+func (ms mergeStrategies) merge(a, b map[string]interface{}, path fieldpath.Segments) map[string]interface{} {
 	out := make(map[string]interface{}, len(a))
 	for k, v := range a {
 		out[k] = v
 	}
-	for k, v := range b {
-		if v, ok := v.(map[string]interface{}); ok {
-			if bv, ok := out[k]; ok {
-				if bv, ok := bv.(map[string]interface{}); ok {
-					out[k] = mergeMaps(bv, v)
-					continue
-				}
+	for k, bv := range b {
+		av, exists := out[k]
+		if !exists {
+			out[k] = bv
+			continue
+		}
+		p := append(append(fieldpath.Segments{}, path...), fieldpath.Field(k))
+		switch ms[p.String()] {
+		case v1.EnvironmentMergeStrategyFirstWins:
+			// Keep the existing value.
+		case v1.EnvironmentMergeStrategyLastWins:
+			out[k] = bv
+		case v1.EnvironmentMergeStrategyConcat:
+			al, aok := av.([]interface{})
+			bl, bok := bv.([]interface{})
+			if !aok || !bok {
+				out[k] = bv
+				continue
 			}
+			out[k] = append(append(make([]interface{}, 0, len(al)+len(bl)), al...), bl...)
+		default:
+			am, aok := av.(map[string]interface{})
+			bm, bok := bv.(map[string]interface{})
+			if !aok || !bok {
+				out[k] = bv
+				continue
+			}
+			out[k] = ms.merge(am, bm, p)
 		}
-		out[k] = v
 	}
 	return out
 }
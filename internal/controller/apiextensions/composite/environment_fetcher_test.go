@@ -243,6 +243,61 @@ func TestFetch(t *testing.T) {
 				env: makeEnvironment(testDataMerged),
 			},
 		},
+		"MergeMultipleSourcesWithStrategies": {
+			reason: "It should merge the data of multiple EnvironmentConfigs using the merge strategies of their keys, and merge the result over the default data.",
+			args: args{
+				kube: &test.MockClient{
+					MockGet: func(ctx context.Context, key client.ObjectKey, o client.Object) error {
+						cs := o.(*v1alpha1.EnvironmentConfig)
+						switch key.Name {
+						case "a":
+							cs.Data = makeJSON(testData1)
+						case "b":
+							cs.Data = makeJSON(testData2)
+						}
+						return nil
+					},
+				},
+				cr: composite(
+					withEnvironmentRefs(
+						corev1.ObjectReference{Name: "a"},
+						corev1.ObjectReference{Name: "b"},
+					),
+				),
+				revision: &v1.CompositionRevision{
+					Spec: v1.CompositionRevisionSpec{
+						Environment: &v1.EnvironmentConfiguration{
+							DefaultData: makeJSON(map[string]interface{}{
+								"test": map[string]interface{}{
+									"default": true,
+								},
+							}),
+							MergeStrategies: []v1.EnvironmentKeyMergeStrategy{
+								{Key: "array", Strategy: v1.EnvironmentMergeStrategyConcat},
+								{Key: "test", Strategy: v1.EnvironmentMergeStrategyFirstWins},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				env: makeEnvironment(map[string]interface{}{
+					"int":  int(2),
+					"bool": true,
+					"str":  "some str",
+					"array": []int{
+						1, 2, 3, 4, 1, 2, 3, 4, 5,
+					},
+					"test": map[string]interface{}{
+						"foo":     "bar",
+						"default": true,
+						"complex": map[string]interface{}{
+							"data": "val",
+						},
+					},
+				}),
+			},
+		},
 		"ErrorOnKubeGetError": {
 			reason: "It should return an error if getting a EnvironmentConfig from a reference fails",
 			args: args{
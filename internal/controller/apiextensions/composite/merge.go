@@ -99,3 +99,18 @@ func patchFieldValueToObject(fieldPath string, value any, to runtime.Object, mo
 
 	return runtime.DefaultUnstructuredConverter.FromUnstructured(paved.UnstructuredContent(), to)
 }
+
+// mergeRendered merges the supplied src object into the supplied dst object.
+// Objects are merged recursively. Any other value in src replaces the value
+// at the same path in dst.
+func mergeRendered(dst, src map[string]any) {
+	for k, sv := range src {
+		sm, sok := sv.(map[string]any)
+		dm, dok := dst[k].(map[string]any)
+		if sok && dok {
+			mergeRendered(dm, sm)
+			continue
+		}
+		dst[k] = sv
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+// An ApplyRecorder records applies of composed resources.
+type ApplyRecorder interface {
+	// RecordApply records that a composed resource of the supplied kind was
+	// applied once, batching the supplied number of changes. A change is a
+	// patch in Resources mode, or a pipeline step in Pipeline mode.
+	RecordApply(mode v1.CompositionMode, gvk schema.GroupVersionKind, batched int)
+}
+
+// A NopApplyRecorder does nothing.
+type NopApplyRecorder struct{}
+
+// RecordApply does nothing.
+func (NopApplyRecorder) RecordApply(_ v1.CompositionMode, _ schema.GroupVersionKind, _ int) {}
+
+// Metrics are metrics for composing resources. They can be used to verify
+// that each composed resource is applied once per reconcile, regardless of how
+// many patches or functions contribute to it.
+type Metrics struct {
+	applies *prometheus.CounterVec
+	batched *prometheus.HistogramVec
+}
+
+// NewMetrics creates metrics for composing resources.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		applies: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Subsystem: "composition",
+			Name:      "composed_resource_apply_total",
+			Help:      "Total number of applies of composed resources.",
+		}, []string{"mode", "group", "kind"}),
+
+		batched: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Subsystem: "composition",
+			Name:      "composed_resource_apply_batch_size",
+			Help:      "Histogram of the number of changes batched into each apply of a composed resource - patches in Resources mode, or pipeline steps in Pipeline mode.",
+			Buckets:   []float64{0, 1, 2, 5, 10, 20, 50},
+		}, []string{"mode", "group", "kind"}),
+	}
+}
+
+// Describe sends the super-set of all possible descriptors of metrics
+// collected by this Collector to the provided channel and returns once
+// the last descriptor has been sent.
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.applies.Describe(ch)
+	m.batched.Describe(ch)
+}
+
+// Collect is called by the Prometheus registry when collecting
+// metrics. The implementation sends each collected metric via the
+// provided channel and returns once the last metric has been sent.
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.applies.Collect(ch)
+	m.batched.Collect(ch)
+}
+
+// RecordApply records that a composed resource of the supplied kind was
+// applied once, batching the supplied number of changes.
+func (m *Metrics) RecordApply(mode v1.CompositionMode, gvk schema.GroupVersionKind, batched int) {
+	l := prometheus.Labels{"mode": string(mode), "group": gvk.Group, "kind": gvk.Kind}
+	m.applies.With(l).Inc()
+	m.batched.With(l).Observe(float64(batched))
+}
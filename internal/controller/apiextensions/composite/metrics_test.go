@@ -0,0 +1,52 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+func TestMetricsRecordApply(t *testing.T) {
+	gvk := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Bucket"}
+
+	m := NewMetrics()
+	m.RecordApply(v1.CompositionModePipeline, gvk, 3)
+	m.RecordApply(v1.CompositionModePipeline, gvk, 1)
+	m.RecordApply(v1.CompositionModeResources, gvk, 4)
+
+	want := map[v1.CompositionMode]float64{
+		v1.CompositionModePipeline:  2,
+		v1.CompositionModeResources: 1,
+	}
+	for mode, applies := range want {
+		got := testutil.ToFloat64(m.applies.WithLabelValues(string(mode), gvk.Group, gvk.Kind))
+		if diff := cmp.Diff(applies, got); diff != "" {
+			t.Errorf("RecordApply(%s): -want applies, +got applies:\n%s", mode, diff)
+		}
+	}
+
+	// We should observe one batch size per mode, group, and kind.
+	if diff := cmp.Diff(2, testutil.CollectAndCount(m.batched)); diff != "" {
+		t.Errorf("RecordApply(...): -want batch size series, +got batch size series:\n%s", diff)
+	}
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/xcel"
 )
 
 // Error strings
@@ -39,6 +40,8 @@ const (
 	errFmtRequiresMatchString     = "type %q requires a match string"
 	errFmtRequiresMatchConditions = "type %q requires a valid match condition"
 	errFmtRequiresMatchInteger    = "type %q requires a match integer"
+	errFmtRequiresMatchExpression = "type %q requires a match expression"
+	errCompileMatchExpression     = "cannot compile match expression"
 	errFmtUnknownCheck            = "unknown type %q"
 	errFmtRunCheck                = "cannot run readiness check at index %d"
 )
@@ -53,12 +56,17 @@ const (
 	ReadinessCheckTypeMatchInteger ReadinessCheckType = "MatchInteger"
 	// discussion regarding MatchBool vs MatchTrue/MatchFalse:
 	// https://github.com/crossplane/crossplane/pull/4399#discussion_r1277225375
-	ReadinessCheckTypeMatchTrue      ReadinessCheckType = "MatchTrue"
-	ReadinessCheckTypeMatchFalse     ReadinessCheckType = "MatchFalse"
-	ReadinessCheckTypeMatchCondition ReadinessCheckType = "MatchCondition"
-	ReadinessCheckTypeNone           ReadinessCheckType = "None"
+	ReadinessCheckTypeMatchTrue       ReadinessCheckType = "MatchTrue"
+	ReadinessCheckTypeMatchFalse      ReadinessCheckType = "MatchFalse"
+	ReadinessCheckTypeMatchCondition  ReadinessCheckType = "MatchCondition"
+	ReadinessCheckTypeMatchExpression ReadinessCheckType = "MatchExpression"
+	ReadinessCheckTypeNone            ReadinessCheckType = "None"
 )
 
+// readinessExpressions caches compiled MatchExpression readiness checks. Every
+// readiness check is run each time a composite resource is reconciled.
+var readinessExpressions = xcel.NewPrograms([]string{v1.ReadinessCheckVarObject})
+
 // ReadinessCheck is used to indicate how to tell whether a resource is ready
 // for consumption
 type ReadinessCheck struct {
@@ -76,6 +84,9 @@ type ReadinessCheck struct {
 
 	// MatchCondition is the condition you'd like to match if you're using "MatchCondition" type.
 	MatchCondition *MatchConditionReadinessCheck
+
+	// MatchExpression is the CEL expression you'd like to match if you're using "MatchExpression" type.
+	MatchExpression *string
 }
 
 // MatchConditionReadinessCheck is used to indicate how to tell whether a resource is ready
@@ -115,6 +126,9 @@ func ReadinessCheckFromV1(in *v1.ReadinessCheck) ReadinessCheck {
 			Status: in.MatchCondition.Status,
 		}
 	}
+	if in.MatchExpression != "" {
+		out.MatchExpression = ptr.To(in.MatchExpression)
+	}
 	return out
 }
 
@@ -156,6 +170,11 @@ func (c ReadinessCheck) Validate() error {
 			return errors.Errorf(errFmtRequiresMatchConditions, c.Type)
 		}
 		return nil
+	case ReadinessCheckTypeMatchExpression:
+		if c.MatchExpression == nil {
+			return errors.Errorf(errFmtRequiresMatchExpression, c.Type)
+		}
+		return nil
 	default:
 		return errors.Errorf(errFmtUnknownCheck, c.Type)
 	}
@@ -197,6 +216,18 @@ func (c ReadinessCheck) IsReady(p *fieldpath.Paved, o ConditionedObject) (bool,
 	case ReadinessCheckTypeMatchCondition:
 		val := o.GetCondition(c.MatchCondition.Type)
 		return val.Status == c.MatchCondition.Status, nil
+	case ReadinessCheckTypeMatchExpression:
+		prg, err := readinessExpressions.Compile(*c.MatchExpression)
+		if err != nil {
+			return false, errors.Wrap(err, errCompileMatchExpression)
+		}
+		ready, err := xcel.Evaluate(prg, map[string]any{v1.ReadinessCheckVarObject: p.UnstructuredContent()})
+		if err != nil {
+			// Expressions typically fail to evaluate because the fields they
+			// reference aren't populated yet, i.e. the resource isn't ready.
+			return false, nil //nolint:nilerr // See above.
+		}
+		return ready, nil
 	case ReadinessCheckTypeMatchFalse:
 		val, err := p.GetBool(*c.FieldPath)
 		if err != nil {
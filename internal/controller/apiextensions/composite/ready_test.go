@@ -98,6 +98,61 @@ func TestIsReady(t *testing.T) {
 				ready: false,
 			},
 		},
+		"MatchExpressionReady": {
+			reason: "If a match expression evaluates to true the resource should be ready",
+			args: args{
+				o: composed.New(func(r *composed.Unstructured) {
+					r.Object["status"] = map[string]any{"phase": "Running"}
+				}),
+				rc: []ReadinessCheck{{
+					Type:            ReadinessCheckTypeMatchExpression,
+					MatchExpression: ptr.To("object.status.phase == 'Running'"),
+				}},
+			},
+			want: want{
+				ready: true,
+			},
+		},
+		"MatchExpressionNotReady": {
+			reason: "If a match expression evaluates to false the resource should not be ready",
+			args: args{
+				o: composed.New(func(r *composed.Unstructured) {
+					r.Object["status"] = map[string]any{"phase": "Pending"}
+				}),
+				rc: []ReadinessCheck{{
+					Type:            ReadinessCheckTypeMatchExpression,
+					MatchExpression: ptr.To("object.status.phase == 'Running'"),
+				}},
+			},
+			want: want{
+				ready: false,
+			},
+		},
+		"MatchExpressionMissingField": {
+			reason: "If a match expression references a field that isn't populated yet the resource should not be ready",
+			args: args{
+				o: composed.New(),
+				rc: []ReadinessCheck{{
+					Type:            ReadinessCheckTypeMatchExpression,
+					MatchExpression: ptr.To("object.status.phase == 'Running'"),
+				}},
+			},
+			want: want{
+				ready: false,
+			},
+		},
+		"MatchExpressionMissing": {
+			reason: "If the match expression is missing, we should return an error",
+			args: args{
+				o: composed.New(),
+				rc: []ReadinessCheck{{
+					Type: ReadinessCheckTypeMatchExpression,
+				}},
+			},
+			want: want{
+				err: errors.Wrapf(errors.Wrap(errors.Errorf(errFmtRequiresMatchExpression, ReadinessCheckTypeMatchExpression), errInvalidCheck), errFmtRunCheck, 0),
+			},
+		},
 		"ExplictNone": {
 			reason: "If the only readiness check is explicitly 'None' the resource is always ready.",
 			args: args{
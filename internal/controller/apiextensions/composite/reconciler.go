@@ -20,7 +20,9 @@ package composite
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
@@ -55,24 +57,26 @@ const (
 
 // Error strings
 const (
-	errGet                    = "cannot get composite resource"
-	errUpdate                 = "cannot update composite resource"
-	errUpdateStatus           = "cannot update composite resource status"
-	errAddFinalizer           = "cannot add composite resource finalizer"
-	errRemoveFinalizer        = "cannot remove composite resource finalizer"
-	errSelectComp             = "cannot select Composition"
-	errSelectCompUpdatePolicy = "cannot select CompositionUpdatePolicy"
-	errFetchComp              = "cannot fetch Composition"
-	errConfigure              = "cannot configure composite resource"
-	errPublish                = "cannot publish connection details"
-	errUnpublish              = "cannot unpublish connection details"
-	errValidate               = "refusing to use invalid Composition"
-	errAssociate              = "cannot associate composed resources with Composition resource templates"
-	errFetchEnvironment       = "cannot fetch environment"
-	errSelectEnvironment      = "cannot select environment"
-	errCompose                = "cannot compose resources"
-	errInvalidResources       = "some resources were invalid, check events"
-	errRenderCD               = "cannot render composed resource"
+	errGet                     = "cannot get composite resource"
+	errUpdate                  = "cannot update composite resource"
+	errUpdateStatus            = "cannot update composite resource status"
+	errAddFinalizer            = "cannot add composite resource finalizer"
+	errRemoveFinalizer         = "cannot remove composite resource finalizer"
+	errDeleteComposedResources = "cannot delete composed resources"
+	errSelectComp              = "cannot select Composition"
+	errSelectCompUpdatePolicy  = "cannot select CompositionUpdatePolicy"
+	errFetchComp               = "cannot fetch Composition"
+	errConfigure               = "cannot configure composite resource"
+	errPublish                 = "cannot publish connection details"
+	errUnpublish               = "cannot unpublish connection details"
+	errValidate                = "refusing to use invalid Composition"
+	errAssociate               = "cannot associate composed resources with Composition resource templates"
+	errFetchEnvironment        = "cannot fetch environment"
+	errSelectEnvironment       = "cannot select environment"
+	errCompose                 = "cannot compose resources"
+	errInvalidResources        = "some resources were invalid, check events"
+	errFmtUnapplied            = "cannot apply composed resources: %s"
+	errRenderCD                = "cannot render composed resource"
 
 	reconcilePausedMsg = "Reconciliation (including deletion) is paused via the pause annotation"
 )
@@ -273,6 +277,14 @@ func WithCompositeFinalizer(f resource.Finalizer) ReconcilerOption {
 	}
 }
 
+// WithComposedResourceDeleter specifies how the Reconciler should delete
+// composed resources when their composite resource is deleted.
+func WithComposedResourceDeleter(d ComposedResourceDeleter) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.composite.ComposedResourceDeleter = d
+	}
+}
+
 // WithCompositionSelector specifies how the composition to be used should be
 // selected.
 func WithCompositionSelector(s CompositionSelector) ReconcilerOption {
@@ -357,6 +369,7 @@ type compositeResource struct {
 	EnvironmentSelector
 	Configurator
 	managed.ConnectionPublisher
+	ComposedResourceDeleter
 }
 
 // KindObserver tracks kinds of referenced composed resources in composite
@@ -410,6 +423,8 @@ func NewReconciler(mgr manager.Manager, of resource.CompositeKind, opts ...Recon
 			EnvironmentSelector: NewNoopEnvironmentSelector(),
 			Configurator:        NewConfiguratorChain(NewAPINamingConfigurator(kube), NewAPIConfigurator(kube)),
 
+			ComposedResourceDeleter: NewOrderedComposedResourceDeleter(kube),
+
 			// TODO(negz): In practice this is a filtered publisher that will
 			// never filter any keys. Is there an unfiltered variant we could
 			// use by default instead?
@@ -485,6 +500,22 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		log = log.WithValues("deletion-timestamp", xr.GetDeletionTimestamp())
 
 		xr.SetConditions(xpv1.Deleting())
+
+		// If the composition declares a deletion order we delete composed
+		// resources ourselves, and keep our finalizer until they're gone.
+		deleted, err := r.composite.DeleteComposedResources(ctx, xr)
+		if err != nil {
+			err = errors.Wrap(err, errDeleteComposedResources)
+			r.record.Event(xr, event.Warning(reasonDelete, err))
+			xr.SetConditions(xpv1.ReconcileError(err))
+			return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, xr), errUpdateStatus)
+		}
+		if !deleted {
+			log.Debug("Waiting for composed resources to be deleted")
+			xr.SetConditions(xpv1.ReconcileSuccess())
+			return reconcile.Result{RequeueAfter: r.pollInterval}, errors.Wrap(r.client.Status().Update(ctx, xr), errUpdateStatus)
+		}
+
 		if err := r.composite.UnpublishConnection(ctx, xr, nil); err != nil {
 			err = errors.Wrap(err, errUnpublish)
 			r.record.Event(xr, event.Warning(reasonDelete, err))
@@ -652,7 +683,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		r.record.Event(xr, event.Normal(reasonCompose, "Successfully composed resources"))
 	}
 
-	var unready []ComposedResource
+	var unready, degraded, unapplied []string
 	for i, cd := range res.Composed {
 		// Specifying a name for P&T templates is optional but encouraged.
 		// If there was no name, fall back to using the index.
@@ -661,28 +692,53 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 			id = strconv.Itoa(i)
 		}
 
+		if cd.ApplyError != nil {
+			// See the comment about invalid errors above. The event
+			// recorded by the Composer has the details.
+			msg := cd.ApplyError.Error()
+			if kerrors.IsInvalid(cd.ApplyError) {
+				msg = errInvalidResources
+			}
+			unapplied = append(unapplied, fmt.Sprintf("%s: %s", id, msg))
+		}
+
 		if !cd.Ready {
 			log.Debug("Composed resource is not yet ready", "id", id)
-			unready = append(unready, cd)
+			unready = append(unready, id)
+			if cd.ReadinessTimeout > 0 && !cd.UnreadySince.IsZero() && time.Since(cd.UnreadySince) > cd.ReadinessTimeout {
+				degraded = append(degraded, id)
+				r.record.Event(xr, event.Warning(reasonCompose, errors.Errorf("Composed resource %q has not been ready for longer than its readiness timeout of %s", id, cd.ReadinessTimeout)))
+				continue
+			}
 			r.record.Event(xr, event.Normal(reasonCompose, fmt.Sprintf("Composed resource %q is not yet ready", id)))
 			continue
 		}
 	}
 
 	xr.SetConditions(xpv1.ReconcileSuccess())
+	if len(unapplied) > 0 {
+		// We applied the composed resources we could, but the XR isn't
+		// synced until we can apply them all. Composed resources that
+		// couldn't be applied aren't ready, so we'll requeue below.
+		sort.Strings(unapplied)
+		xr.SetConditions(xpv1.ReconcileError(errors.Errorf(errFmtUnapplied, strings.Join(unapplied, "; "))))
+	}
 
 	// TODO(muvaf): If a resource becomes Unavailable at some point, should we
 	// still report it as Creating?
+	if len(degraded) > 0 {
+		// We keep requeueing, since degraded resources may still become
+		// ready.
+		xr.SetConditions(v1.Degraded().WithMessage(fmt.Sprintf("Resources unready for longer than their readiness timeout: %s", resource.StableNAndSomeMore(resource.DefaultFirstN, degraded))))
+		return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, xr), errUpdateStatus)
+	}
+
 	if len(unready) > 0 {
 		// We want to requeue to wait for our composed resources to
-		// become ready, since we can't watch them.
-		names := make([]string, len(unready))
-		for i, cd := range unready {
-			names[i] = string(cd.ResourceName)
-		}
-		// sort for stable condition messages. With functions, we don't have a
-		// stable order otherwise.
-		xr.SetConditions(xpv1.Creating().WithMessage(fmt.Sprintf("Unready resources: %s", resource.StableNAndSomeMore(resource.DefaultFirstN, names))))
+		// become ready, since we can't watch them. StableNAndSomeMore
+		// sorts for stable condition messages. With functions, we don't
+		// have a stable order otherwise.
+		xr.SetConditions(xpv1.Creating().WithMessage(fmt.Sprintf("Unready resources: %s", resource.StableNAndSomeMore(resource.DefaultFirstN, unready))))
 		return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, xr), errUpdateStatus)
 	}
 
@@ -101,6 +101,58 @@ func TestReconcile(t *testing.T) {
 				err: errors.Wrap(errBoom, errGet),
 			},
 		},
+		"DeleteComposedResourcesError": {
+			reason: "We should return any error encountered while deleting composed resources.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClient(&test.MockClient{
+						MockGet: WithComposite(t, NewComposite(func(cr resource.Composite) {
+							cr.SetDeletionTimestamp(&now)
+						})),
+						MockStatusUpdate: WantComposite(t, NewComposite(func(want resource.Composite) {
+							want.SetDeletionTimestamp(&now)
+							want.SetConditions(xpv1.Deleting(), xpv1.ReconcileError(errors.Wrap(errBoom, errDeleteComposedResources)))
+						})),
+					}),
+					WithComposedResourceDeleter(ComposedResourceDeleterFn(func(_ context.Context, _ resource.Composite) (bool, error) {
+						return false, errBoom
+					})),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: true},
+			},
+		},
+		"WaitForComposedResourcesDeletion": {
+			reason: "We should keep our finalizer and requeue while composed resources are being deleted in order.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClient(&test.MockClient{
+						MockGet: WithComposite(t, NewComposite(func(cr resource.Composite) {
+							cr.SetDeletionTimestamp(&now)
+						})),
+						MockStatusUpdate: WantComposite(t, NewComposite(func(want resource.Composite) {
+							want.SetDeletionTimestamp(&now)
+							want.SetConditions(xpv1.Deleting(), xpv1.ReconcileSuccess())
+						})),
+					}),
+					WithComposedResourceDeleter(ComposedResourceDeleterFn(func(_ context.Context, _ resource.Composite) (bool, error) {
+						return false, nil
+					})),
+					WithCompositeFinalizer(resource.FinalizerFns{
+						RemoveFinalizerFn: func(ctx context.Context, obj resource.Object) error {
+							t.Errorf("RemoveFinalizer should not be called while composed resources exist")
+							return nil
+						},
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: defaultPollInterval},
+			},
+		},
 		"UnpublishConnectionError": {
 			reason: "We should return any error encountered while unpublishing connection details.",
 			args: args{
@@ -546,6 +598,133 @@ func TestReconcile(t *testing.T) {
 				r: reconcile.Result{Requeue: true},
 			},
 		},
+		"ComposedResourcesNotApplied": {
+			reason: "We should report any composed resources we couldn't apply in our Synced condition, while still processing the others.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClient(&test.MockClient{
+						MockGet: test.NewMockGetFn(nil),
+						MockStatusUpdate: WantComposite(t, NewComposite(func(cr resource.Composite) {
+							cr.SetCompositionReference(&corev1.ObjectReference{})
+							cr.SetConditions(
+								xpv1.ReconcileError(errors.Errorf(errFmtUnapplied, "cow: boom; elephant: "+errInvalidResources)),
+								xpv1.Creating().WithMessage("Unready resources: cow, elephant"),
+							)
+						})),
+					}),
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						return nil
+					})),
+					WithCompositionRevisionFetcher(CompositionRevisionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.CompositionRevision, error) {
+						c := &v1.CompositionRevision{Spec: v1.CompositionRevisionSpec{
+							Resources: []v1.ComposedTemplate{{}},
+						}}
+						return c, nil
+					})),
+					WithCompositionRevisionValidator(CompositionRevisionValidatorFn(func(_ *v1.CompositionRevision) error { return nil })),
+					WithConfigurator(ConfiguratorFn(func(_ context.Context, _ resource.Composite, _ *v1.CompositionRevision) error {
+						return nil
+					})),
+					WithComposer(ComposerFn(func(ctx context.Context, xr *composite.Unstructured, req CompositionRequest) (CompositionResult, error) {
+						return CompositionResult{
+							Composed: []ComposedResource{{
+								ResourceName: "elephant",
+								Ready:        false,
+								ApplyError:   kerrors.NewInvalid(schema.GroupKind{Kind: "Elephant"}, "elephant", nil),
+							}, {
+								ResourceName: "cow",
+								Ready:        false,
+								ApplyError:   errors.New("boom"),
+							}, {
+								ResourceName: "pig",
+								Ready:        true,
+							}},
+						}, nil
+					})),
+					WithConnectionPublishers(managed.ConnectionPublisherFns{
+						PublishConnectionFn: func(ctx context.Context, o resource.ConnectionSecretOwner, c managed.ConnectionDetails) (published bool, err error) {
+							return false, nil
+						},
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: true},
+			},
+		},
+		"ComposedResourcesDegraded": {
+			reason: "We should mark the XR degraded if any of our composed resources have been unready for longer than their readiness timeout.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClient(&test.MockClient{
+						MockGet: test.NewMockGetFn(nil),
+						MockStatusUpdate: WantComposite(t, NewComposite(func(cr resource.Composite) {
+							cr.SetCompositionReference(&corev1.ObjectReference{})
+							cr.SetConditions(xpv1.ReconcileSuccess(), v1.Degraded().WithMessage("Resources unready for longer than their readiness timeout: 6, cow"))
+						})),
+					}),
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						return nil
+					})),
+					WithCompositionRevisionFetcher(CompositionRevisionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.CompositionRevision, error) {
+						c := &v1.CompositionRevision{Spec: v1.CompositionRevisionSpec{
+							Resources: []v1.ComposedTemplate{{}},
+						}}
+						return c, nil
+					})),
+					WithCompositionRevisionValidator(CompositionRevisionValidatorFn(func(_ *v1.CompositionRevision) error { return nil })),
+					WithConfigurator(ConfiguratorFn(func(_ context.Context, _ resource.Composite, _ *v1.CompositionRevision) error {
+						return nil
+					})),
+					WithComposer(ComposerFn(func(ctx context.Context, xr *composite.Unstructured, req CompositionRequest) (CompositionResult, error) {
+						return CompositionResult{
+							Composed: []ComposedResource{{
+								ResourceName:     "elephant",
+								Ready:            false,
+								ReadinessTimeout: time.Hour,
+								UnreadySince:     time.Now(),
+							}, {
+								ResourceName:     "cow",
+								Ready:            false,
+								ReadinessTimeout: time.Minute,
+								UnreadySince:     time.Now().Add(-time.Hour),
+							}, {
+								ResourceName: "pig",
+								Ready:        true,
+							}, {
+								ResourceName: "cat",
+								Ready:        false,
+							}, {
+								ResourceName: "dog",
+								Ready:        true,
+							}, {
+								ResourceName: "snake",
+								Ready:        false,
+							}, {
+								// Unnamed resources are identified by index.
+								Ready:            false,
+								ReadinessTimeout: time.Minute,
+								UnreadySince:     time.Now().Add(-time.Hour),
+							}},
+						}, nil
+					})),
+					WithConnectionPublishers(managed.ConnectionPublisherFns{
+						PublishConnectionFn: func(ctx context.Context, o resource.ConnectionSecretOwner, c managed.ConnectionDetails) (published bool, err error) {
+							return false, nil
+						},
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: true},
+			},
+		},
 		"ComposedResourcesReady": {
 			reason: "We should requeue after our poll interval if all of our composed resources are ready.",
 			args: args{
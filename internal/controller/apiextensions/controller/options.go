@@ -20,6 +20,9 @@ package controller
 import (
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
 
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	"github.com/crossplane/crossplane/internal/xfn"
 )
 
@@ -29,4 +32,13 @@ type Options struct {
 
 	// FunctionRunner used to run Composition Functions.
 	FunctionRunner *xfn.PackagedFunctionRunner
+
+	// ApplyRecorder used to record applies of composed resources.
+	ApplyRecorder ApplyRecorder
+}
+
+// An ApplyRecorder records applies of composed resources. It's satisfied by the
+// composite package's Metrics.
+type ApplyRecorder interface {
+	RecordApply(mode v1.CompositionMode, gvk schema.GroupVersionKind, batched int)
 }
@@ -103,6 +103,16 @@ func (i *composedResourceInformers) Start(ctx context.Context, h handler.EventHa
 	return nil
 }
 
+// send delivers the supplied event to all sinks.
+func (i *composedResourceInformers) send(ev runtimeevent.UpdateEvent) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	for _, handleFn := range i.sinks {
+		handleFn(ev)
+	}
+}
+
 // RegisterComposite registers a composite resource cache with its GVK.
 // Instances of this GVK will be considered to keep composed resource informers
 // alive.
@@ -171,16 +181,27 @@ func (i *composedResourceInformers) WatchComposedResources(gvks ...schema.GroupV
 					return
 				}
 
-				i.lock.RLock()
-				defer i.lock.RUnlock()
-
-				ev := runtimeevent.UpdateEvent{
+				i.send(runtimeevent.UpdateEvent{
 					ObjectOld: old,
 					ObjectNew: obj,
+				})
+			},
+			// We deliver deletes as updates too. This lets a composite
+			// reconcile immediately to recreate a composed resource that
+			// was deleted out from under it, or to stop waiting for one it
+			// deleted itself.
+			DeleteFunc: func(oldObj interface{}) {
+				if tombstone, ok := oldObj.(kcache.DeletedFinalStateUnknown); ok {
+					oldObj = tombstone.Obj
 				}
-				for _, handleFn := range i.sinks {
-					handleFn(ev)
+				obj, ok := oldObj.(client.Object)
+				if !ok {
+					return
 				}
+				i.send(runtimeevent.UpdateEvent{
+					ObjectOld: obj,
+					ObjectNew: obj,
+				})
 			},
 		}); err != nil {
 			cancelFn()
@@ -105,3 +105,10 @@ func enqueueXRsForMR(ca cache.Cache, xrGVK schema.GroupVersionKind, log logging.
 		}
 	}
 }
+
+// A deferredReader reads from a reader that's set after the deferredReader is
+// created - e.g. the cache of a controller that doesn't exist yet when its
+// reconciler is built. It must be set before the controller is started.
+type deferredReader struct {
+	client.Reader
+}
@@ -72,6 +72,7 @@ const (
 	errUpdateStatus                   = "cannot update status of CompositeResourceDefinition"
 	errStartController                = "cannot start composite resource controller"
 	errAddIndex                       = "cannot add composite GVK index"
+	errAddUnsyncedIndex               = "cannot add index of composite resources that fail to sync"
 	errAddFinalizer                   = "cannot add composite resource finalizer"
 	errRemoveFinalizer                = "cannot remove composite resource finalizer"
 	errDeleteCRD                      = "cannot delete composite resource CustomResourceDefinition"
@@ -458,6 +459,15 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 
 	ro := CompositeReconcilerOptions(r.options, d, r.client, r.log, r.record)
 	ck := resource.CompositeKind(d.GetCompositeGroupVersionKind())
+
+	// Composition rollouts read the composite resources that fail to sync
+	// from an index of the composite resource controller's cache, which we
+	// create below.
+	unsynced := &deferredReader{}
+	ro = append(ro, composite.WithCompositionRevisionFetcher(composite.NewAPIRevisionFetcher(
+		resource.ClientApplicator{Client: r.client.Client, Applicator: resource.NewAPIPatchingApplicator(r.client.Client)},
+		composite.WithUnsyncedCompositeReader(unsynced),
+	)))
 	if r.options.Features.Enabled(features.EnableAlphaRealtimeCompositions) {
 		ro = append(ro, composite.WithKindObserver(composite.KindObserverFunc(r.xrInformers.WatchComposedResources)))
 	}
@@ -496,6 +506,14 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return reconcile.Result{}, err
 	}
 
+	unsynced.Reader = c.GetCache()
+	if err := c.GetCache().IndexField(ctx, u, composite.IndexKeyUnsyncedCompositionRevision, composite.IndexUnsyncedCompositionRevision); err != nil {
+		log.Debug(errAddUnsyncedIndex, "error", err)
+		err = errors.Wrap(err, errAddUnsyncedIndex)
+		r.record.Event(d, event.Warning(reasonEstablishXR, err))
+		return reconcile.Result{}, err
+	}
+
 	if r.options.Features.Enabled(features.EnableAlphaRealtimeCompositions) {
 		ca = c.GetCache()
 		if err := ca.IndexField(ctx, u, compositeResourceRefGVKsIndex, IndexCompositeResourceRefGVKs); err != nil {
@@ -583,29 +601,36 @@ func CompositeReconcilerOptions(co apiextensionscontroller.Options, d *v1.Compos
 
 		o = append(o,
 			composite.WithConnectionPublishers(pc...),
-			composite.WithConfigurator(cc),
-			composite.WithComposer(composite.NewPTComposer(c, composite.WithComposedConnectionDetailsFetcher(fetcher))))
+			composite.WithConfigurator(cc))
 	}
 
+	ptopts := []composite.PTComposerOption{composite.WithComposedConnectionDetailsFetcher(fetcher)}
+	if co.ApplyRecorder != nil {
+		ptopts = append(ptopts, composite.WithPTComposerApplyRecorder(co.ApplyRecorder))
+	}
+	ptc := composite.NewPTComposer(c, ptopts...)
+	o = append(o, composite.WithComposer(ptc))
+
 	// If Composition Functions are enabled we use two different Composer
 	// implementations. One supports P&T (aka 'Resources mode') and the other
 	// Functions (aka 'Pipeline mode').
 	if co.Features.Enabled(features.EnableBetaCompositionFunctions) {
-		ptc := composite.NewPTComposer(c, composite.WithComposedConnectionDetailsFetcher(fetcher))
-
 		fcopts := []composite.FunctionComposerOption{
 			composite.WithComposedResourceObserver(composite.NewExistingComposedResourceObserver(c, fetcher)),
 			composite.WithCompositeConnectionDetailsFetcher(fetcher),
 		}
 
+		if co.ApplyRecorder != nil {
+			fcopts = append(fcopts, composite.WithFunctionComposerApplyRecorder(co.ApplyRecorder))
+		}
+
 		if co.Features.Enabled(features.EnableBetaCompositionFunctionsExtraResources) {
 			fcopts = append(fcopts, composite.WithExtraResourcesFetcher(composite.NewExistingExtraResourcesFetcher(c)))
 		}
 
 		fc := composite.NewFunctionComposer(c, co.FunctionRunner, fcopts...)
 
-		// Note that if external secret stores are enabled this will supersede
-		// the WithComposer option specified in that block.
+		// Note that this will supersede the WithComposer option above.
 		o = append(o, composite.WithComposer(composite.ComposerSelectorFn(func(cm *v1.CompositionMode) composite.Composer {
 			// Resources mode is the implicit default.
 			m := v1.CompositionModeResources
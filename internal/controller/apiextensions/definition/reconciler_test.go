@@ -579,6 +579,65 @@ func TestReconcile(t *testing.T) {
 				err: errors.Wrap(errBoom, errStartController),
 			},
 		},
+		"AddUnsyncedIndexError": {
+			reason: "We should return any error we encounter while indexing the composite resources that fail to sync.",
+			args: args{
+				mgr: &mockManager{
+					GetCacheFn: func() cache.Cache {
+						return &mockCache{
+							ListFn: func(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error { return nil },
+						}
+					},
+					GetClientFn: func() client.Client {
+						return &test.MockClient{MockList: test.NewMockListFn(nil)}
+					},
+					GetSchemeFn: runtime.NewScheme,
+					GetRESTMapperFn: func() meta.RESTMapper {
+						return meta.NewDefaultRESTMapper([]schema.GroupVersion{v1.SchemeGroupVersion})
+					},
+				},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					}),
+					WithCRDRenderer(CRDRenderFn(func(_ *v1.CompositeResourceDefinition) (*extv1.CustomResourceDefinition, error) {
+						return &extv1.CustomResourceDefinition{
+							Status: extv1.CustomResourceDefinitionStatus{
+								Conditions: []extv1.CustomResourceDefinitionCondition{
+									{Type: extv1.Established, Status: extv1.ConditionTrue},
+								},
+							},
+						}, nil
+					})),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+						return nil
+					}}),
+					WithControllerEngine(&MockEngine{
+						MockErr: func(_ string) error { return nil },
+						MockCreate: func(_ string, _ kcontroller.Options, _ ...controller.Watch) (controller.NamedController, error) {
+							return mockNamedController{
+								MockGetCache: func() cache.Cache {
+									return &mockCache{
+										IndexFieldFn: func(_ context.Context, _ client.Object, _ string, _ client.IndexerFunc) error {
+											return errBoom
+										},
+									}
+								},
+							}, nil
+						},
+					}),
+				},
+			},
+			want: want{
+				r:   reconcile.Result{},
+				err: errors.Wrap(errBoom, errAddUnsyncedIndex),
+			},
+		},
 		"SuccessfulStart": {
 			reason: "We should return without requeueing if we successfully ensured our CRD exists and controller is started.",
 			args: args{
@@ -71,6 +71,7 @@ const (
 	errDeleteCRD       = "cannot delete composite resource claim CustomResourceDefinition"
 	errListCRs         = "cannot list defined composite resource claims"
 	errDeleteCR        = "cannot delete defined composite resource claim"
+	errConfigureHook   = "cannot configure composite resource claim admission webhook"
 )
 
 // Wait strings.
@@ -116,10 +117,21 @@ func (fn CRDRenderFn) Render(d *v1.CompositeResourceDefinition) (*extv1.CustomRe
 func Setup(mgr ctrl.Manager, o apiextensionscontroller.Options) error {
 	name := "offered/" + strings.ToLower(v1.CompositeResourceDefinitionGroupKind)
 
-	r := NewReconciler(mgr,
+	opts := []ReconcilerOption{
 		WithLogger(o.Logger.WithValues("controller", name)),
 		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
-		WithOptions(o))
+		WithOptions(o),
+	}
+
+	if o.Features.Enabled(features.EnableAlphaClaimAdmissionRules) {
+		kube := unstructured.NewClient(mgr.GetClient())
+		opts = append(opts, WithWebhookConfigurator(NewAPIWebhookConfigurator(resource.ClientApplicator{
+			Client:     kube,
+			Applicator: resource.NewAPIUpdatingApplicator(kube),
+		})))
+	}
+
+	r := NewReconciler(mgr, opts...)
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
@@ -179,6 +191,14 @@ func WithCRDRenderer(c CRDRenderer) ReconcilerOption {
 	}
 }
 
+// WithWebhookConfigurator specifies how the Reconciler should configure the
+// admission webhook for the composite resource claims it defines.
+func WithWebhookConfigurator(c WebhookConfigurator) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.claim.WebhookConfigurator = c
+	}
+}
+
 // WithClientApplicator specifies how the Reconciler should interact with the
 // Kubernetes API.
 func WithClientApplicator(ca resource.ClientApplicator) ReconcilerOption {
@@ -203,6 +223,8 @@ func NewReconciler(mgr manager.Manager, opts ...ReconcilerOption) *Reconciler {
 			CRDRenderer:      CRDRenderFn(xcrd.ForCompositeResourceClaim),
 			ControllerEngine: controller.NewEngine(mgr),
 			Finalizer:        resource.NewAPIFinalizer(kube, finalizer),
+
+			WebhookConfigurator: NopWebhookConfigurator{},
 		},
 
 		log:    logging.NewNopLogger(),
@@ -223,6 +245,7 @@ type definition struct {
 	CRDRenderer
 	ControllerEngine
 	resource.Finalizer
+	WebhookConfigurator
 }
 
 // A Reconciler reconciles CompositeResourceDefinitions.
@@ -394,6 +417,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return reconcile.Result{Requeue: true}, nil
 	}
 
+	if err := r.claim.Configure(ctx, d); err != nil {
+		err = errors.Wrap(err, errConfigureHook)
+		r.record.Event(d, event.Warning(reasonOfferXRC, err))
+		return reconcile.Result{}, err
+	}
+
 	o := []claim.ReconcilerOption{
 		claim.WithLogger(log.WithValues("controller", claim.ControllerName(d.GetName()))),
 		claim.WithRecorder(r.record.WithAnnotations("controller", claim.ControllerName(d.GetName()))),
@@ -525,6 +525,40 @@ func TestReconcile(t *testing.T) {
 				r: reconcile.Result{Requeue: true},
 			},
 		},
+		"ConfigureWebhookError": {
+			reason: "We should return any error we encounter while configuring our claim admission webhook.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					}),
+					WithCRDRenderer(CRDRenderFn(func(_ *v1.CompositeResourceDefinition) (*extv1.CustomResourceDefinition, error) {
+						return &extv1.CustomResourceDefinition{
+							Status: extv1.CustomResourceDefinitionStatus{
+								Conditions: []extv1.CustomResourceDefinitionCondition{
+									{Type: extv1.Established, Status: extv1.ConditionTrue},
+								},
+							},
+						}, nil
+					})),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+						return nil
+					}}),
+					WithWebhookConfigurator(WebhookConfiguratorFn(func(_ context.Context, _ *v1.CompositeResourceDefinition) error {
+						return errBoom
+					})),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errConfigureHook),
+			},
+		},
 		"StartControllerError": {
 			reason: "We should return any error we encounter while starting our controller.",
 			args: args{
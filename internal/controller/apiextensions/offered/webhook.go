@@ -0,0 +1,139 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package offered
+
+import (
+	"context"
+
+	admv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/validation/apiextensions/v1/claim"
+)
+
+const (
+	// The ValidatingWebhookConfiguration, and the webhook within it, that
+	// serves Crossplane's own types. Claim webhooks are served by the same
+	// service, so we copy its client config.
+	coreWebhookConfigurationName = "crossplane"
+	coreWebhookName              = "compositeresourcedefinitions.apiextensions.crossplane.io"
+
+	claimWebhookConfigurationPrefix = "crossplane-claims-"
+)
+
+// Error strings.
+const (
+	errGetCoreWebhookConfiguration     = "cannot get Crossplane's ValidatingWebhookConfiguration"
+	errFmtNoCoreWebhook                = "cannot find webhook %q in Crossplane's ValidatingWebhookConfiguration"
+	errApplyClaimWebhookConfiguration  = "cannot apply composite resource claim ValidatingWebhookConfiguration"
+	errDeleteClaimWebhookConfiguration = "cannot delete composite resource claim ValidatingWebhookConfiguration"
+)
+
+// A WebhookConfigurator configures the admission webhook for the kind of
+// composite resource claim a CompositeResourceDefinition defines.
+type WebhookConfigurator interface {
+	Configure(ctx context.Context, d *v1.CompositeResourceDefinition) error
+}
+
+// A WebhookConfiguratorFn configures the admission webhook for the kind of
+// composite resource claim a CompositeResourceDefinition defines.
+type WebhookConfiguratorFn func(ctx context.Context, d *v1.CompositeResourceDefinition) error
+
+// Configure the admission webhook for the supplied XRD's claim.
+func (fn WebhookConfiguratorFn) Configure(ctx context.Context, d *v1.CompositeResourceDefinition) error {
+	return fn(ctx, d)
+}
+
+// A NopWebhookConfigurator does nothing.
+type NopWebhookConfigurator struct{}
+
+// Configure does nothing.
+func (NopWebhookConfigurator) Configure(_ context.Context, _ *v1.CompositeResourceDefinition) error {
+	return nil
+}
+
+// An APIWebhookConfigurator configures claim admission webhooks by managing a
+// ValidatingWebhookConfiguration for each XRD with claim admission rules.
+type APIWebhookConfigurator struct {
+	client resource.ClientApplicator
+}
+
+// NewAPIWebhookConfigurator returns a WebhookConfigurator that manages
+// ValidatingWebhookConfigurations using the Kubernetes API.
+func NewAPIWebhookConfigurator(c resource.ClientApplicator) *APIWebhookConfigurator {
+	return &APIWebhookConfigurator{client: c}
+}
+
+// Configure a ValidatingWebhookConfiguration that sends the supplied XRD's
+// claims to Crossplane's claim webhook, if the XRD has claim admission rules.
+// Otherwise make sure no such configuration exists.
+func (c *APIWebhookConfigurator) Configure(ctx context.Context, d *v1.CompositeResourceDefinition) error {
+	name := claimWebhookConfigurationPrefix + d.GetName()
+
+	if d.Spec.ClaimNames == nil || len(d.Spec.ClaimAdmissionRules) == 0 {
+		wc := &admv1.ValidatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		return errors.Wrap(resource.IgnoreNotFound(c.client.Delete(ctx, wc)), errDeleteClaimWebhookConfiguration)
+	}
+
+	core := &admv1.ValidatingWebhookConfiguration{}
+	if err := c.client.Get(ctx, types.NamespacedName{Name: coreWebhookConfigurationName}, core); err != nil {
+		return errors.Wrap(err, errGetCoreWebhookConfiguration)
+	}
+	var cc *admv1.WebhookClientConfig
+	for i := range core.Webhooks {
+		if core.Webhooks[i].Name == coreWebhookName {
+			cc = core.Webhooks[i].ClientConfig.DeepCopy()
+			break
+		}
+	}
+	if cc == nil || cc.Service == nil {
+		return errors.Errorf(errFmtNoCoreWebhook, coreWebhookName)
+	}
+	cc.Service.Path = ptr.To(claim.WebhookPath)
+
+	wc := &admv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Webhooks: []admv1.ValidatingWebhook{{
+			// The XRD's name is <plural>.<group>, which makes it a fully
+			// qualified webhook name.
+			Name:                    "claims." + d.GetName(),
+			ClientConfig:            *cc,
+			AdmissionReviewVersions: []string{"v1"},
+			SideEffects:             ptr.To(admv1.SideEffectClassNone),
+			FailurePolicy:           ptr.To(admv1.Fail),
+			Rules: []admv1.RuleWithOperations{{
+				Operations: []admv1.OperationType{admv1.Create, admv1.Update},
+				Rule: admv1.Rule{
+					APIGroups:   []string{d.Spec.Group},
+					APIVersions: []string{"*"},
+					Resources:   []string{d.Spec.ClaimNames.Plural},
+					Scope:       ptr.To(admv1.NamespacedScope),
+				},
+			}},
+		}},
+	}
+	meta.AddOwnerReference(wc, meta.AsController(meta.TypedReferenceTo(d, v1.CompositeResourceDefinitionGroupVersionKind)))
+
+	return errors.Wrap(c.client.Apply(ctx, wc, resource.MustBeControllableBy(d.GetUID())), errApplyClaimWebhookConfiguration)
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package offered
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	admv1 "k8s.io/api/admissionregistration/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/validation/apiextensions/v1/claim"
+)
+
+var _ WebhookConfigurator = &APIWebhookConfigurator{}
+
+func TestAPIWebhookConfiguratorConfigure(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	xrd := &v1.CompositeResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "xdatabases.example.org", UID: types.UID("xrd-uid")},
+		Spec: v1.CompositeResourceDefinitionSpec{
+			Group:      "example.org",
+			ClaimNames: &extv1.CustomResourceDefinitionNames{Kind: "Database", Plural: "databases"},
+			ClaimAdmissionRules: []v1.ClaimAdmissionRule{
+				{Rule: "object.spec.size != 'xlarge'"},
+			},
+		},
+	}
+
+	core := func(obj client.Object) error {
+		wc, ok := obj.(*admv1.ValidatingWebhookConfiguration)
+		if !ok {
+			return errors.New("unexpected object")
+		}
+		wc.Webhooks = []admv1.ValidatingWebhook{{
+			Name: coreWebhookName,
+			ClientConfig: admv1.WebhookClientConfig{
+				CABundle: []byte("ca"),
+				Service: &admv1.ServiceReference{
+					Name:      "crossplane-webhooks",
+					Namespace: "crossplane-system",
+					Path:      ptr.To("/validate-apiextensions-crossplane-io-v1-compositeresourcedefinition"),
+					Port:      ptr.To[int32](9443),
+				},
+			},
+		}}
+		return nil
+	}
+
+	type args struct {
+		client resource.ClientApplicator
+		d      *v1.CompositeResourceDefinition
+	}
+	type want struct {
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoRulesDeleteError": {
+			reason: "We should return any error encountered deleting the webhook configuration of an XRD without rules.",
+			args: args{
+				client: resource.ClientApplicator{
+					Client: &test.MockClient{MockDelete: test.NewMockDeleteFn(errBoom)},
+				},
+				d: &v1.CompositeResourceDefinition{},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errDeleteClaimWebhookConfiguration),
+			},
+		},
+		"NoRules": {
+			reason: "We should delete the webhook configuration of an XRD without rules, if it exists.",
+			args: args{
+				client: resource.ClientApplicator{
+					Client: &test.MockClient{MockDelete: test.NewMockDeleteFn(nil, func(obj client.Object) error {
+						if obj.GetName() != claimWebhookConfigurationPrefix+"xdatabases.example.org" {
+							return errors.Errorf("deleted unexpected webhook configuration %q", obj.GetName())
+						}
+						return nil
+					})},
+				},
+				d: &v1.CompositeResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: "xdatabases.example.org"}},
+			},
+		},
+		"GetCoreWebhookConfigurationError": {
+			reason: "We should return any error encountered getting Crossplane's webhook configuration.",
+			args: args{
+				client: resource.ClientApplicator{
+					Client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				},
+				d: xrd,
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetCoreWebhookConfiguration),
+			},
+		},
+		"NoCoreWebhook": {
+			reason: "We should return an error if Crossplane's webhook configuration doesn't contain the webhook we copy.",
+			args: args{
+				client: resource.ClientApplicator{
+					Client: &test.MockClient{MockGet: test.NewMockGetFn(nil)},
+				},
+				d: xrd,
+			},
+			want: want{
+				err: errors.Errorf(errFmtNoCoreWebhook, coreWebhookName),
+			},
+		},
+		"ApplyError": {
+			reason: "We should return any error encountered applying the webhook configuration.",
+			args: args{
+				client: resource.ClientApplicator{
+					Client: &test.MockClient{MockGet: test.NewMockGetFn(nil, core)},
+					Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+						return errBoom
+					}),
+				},
+				d: xrd,
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errApplyClaimWebhookConfiguration),
+			},
+		},
+		"Success": {
+			reason: "We should apply a webhook configuration that sends the XRD's claims to the claim webhook.",
+			args: args{
+				client: resource.ClientApplicator{
+					Client: &test.MockClient{MockGet: test.NewMockGetFn(nil, core)},
+					Applicator: resource.ApplyFn(func(_ context.Context, obj client.Object, _ ...resource.ApplyOption) error {
+						want := &admv1.ValidatingWebhookConfiguration{
+							ObjectMeta: metav1.ObjectMeta{
+								Name: claimWebhookConfigurationPrefix + "xdatabases.example.org",
+								OwnerReferences: []metav1.OwnerReference{{
+									APIVersion:         v1.SchemeGroupVersion.String(),
+									Kind:               v1.CompositeResourceDefinitionKind,
+									Name:               "xdatabases.example.org",
+									UID:                types.UID("xrd-uid"),
+									Controller:         ptr.To(true),
+									BlockOwnerDeletion: ptr.To(true),
+								}},
+							},
+							Webhooks: []admv1.ValidatingWebhook{{
+								Name: "claims.xdatabases.example.org",
+								ClientConfig: admv1.WebhookClientConfig{
+									CABundle: []byte("ca"),
+									Service: &admv1.ServiceReference{
+										Name:      "crossplane-webhooks",
+										Namespace: "crossplane-system",
+										Path:      ptr.To(claim.WebhookPath),
+										Port:      ptr.To[int32](9443),
+									},
+								},
+								AdmissionReviewVersions: []string{"v1"},
+								SideEffects:             ptr.To(admv1.SideEffectClassNone),
+								FailurePolicy:           ptr.To(admv1.Fail),
+								Rules: []admv1.RuleWithOperations{{
+									Operations: []admv1.OperationType{admv1.Create, admv1.Update},
+									Rule: admv1.Rule{
+										APIGroups:   []string{"example.org"},
+										APIVersions: []string{"*"},
+										Resources:   []string{"databases"},
+										Scope:       ptr.To(admv1.NamespacedScope),
+									},
+								}},
+							}},
+						}
+						if diff := cmp.Diff(want, obj); diff != "" {
+							t.Errorf("Apply(...): -want, +got:\n%s", diff)
+						}
+						return nil
+					}),
+				},
+				d: xrd,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := NewAPIWebhookConfigurator(tc.args.client)
+			err := c.Configure(context.Background(), tc.args.d)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nConfigure(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
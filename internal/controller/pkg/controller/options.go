@@ -18,6 +18,8 @@ limitations under the License.
 package controller
 
 import (
+	"time"
+
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
 
 	"github.com/crossplane/crossplane/internal/xpkg"
@@ -43,6 +45,23 @@ type Options struct {
 	// NewK8sFetcher.
 	FetcherOptions []xpkg.FetcherOpt
 
+	// LocalPackagesDir is the directory packages with a local source are
+	// read from.
+	LocalPackagesDir string
+
+	// WebhookCertificateValidity is how long the certificates of provider
+	// webhook servers are valid for when they're rotated.
+	WebhookCertificateValidity time.Duration
+
 	// PackageRuntime specifies the runtime to use for package runtime.
 	PackageRuntime PackageRuntime
+
+	// LicenseAllowlist is the SPDX license identifiers a package's license
+	// must be satisfied by for it to be installed. Any license is allowed if
+	// it's empty.
+	LicenseAllowlist []string
+
+	// MaxConcurrentPackageInstalls is the maximum number of missing package
+	// dependencies to install concurrently.
+	MaxConcurrentPackageInstalls int
 }
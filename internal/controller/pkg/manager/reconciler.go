@@ -19,13 +19,16 @@ package manager
 
 import (
 	"context"
-	"math"
+	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/google/go-containerregistry/pkg/name"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -43,6 +46,7 @@ import (
 	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
 	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
 	"github.com/crossplane/crossplane/internal/controller/pkg/controller"
+	"github.com/crossplane/crossplane/internal/features"
 	"github.com/crossplane/crossplane/internal/xpkg"
 )
 
@@ -54,6 +58,10 @@ const (
 	// enabled when the packagePullPolicy is Always.
 	pullWait = 1 * time.Minute
 
+	// familyWait is how long the package manager waits before checking
+	// whether the providers in a family want the same version again.
+	familyWait = 30 * time.Second
+
 	reconcilePausedMsg = "Reconciliation (including deletion) is paused via the pause annotation"
 )
 
@@ -77,6 +85,13 @@ const (
 	errUnhealthyPackageRevision     = "current package revision is unhealthy"
 	errUnknownPackageRevisionHealth = "current package revision health is unknown"
 
+	errListFamilyRevisions = "cannot list package revisions in provider family"
+	errSoakPeriod          = "cannot parse package soak period"
+	errRevisionMaxAge      = "cannot parse package revision maximum age"
+	errVerify              = "cannot verify package signature"
+	errCheckUpdate         = "cannot check for a newer package version"
+	errUpdatePackage       = "cannot update package"
+
 	errCreateK8sClient = "failed to initialize clientset"
 	errBuildFetcher    = "cannot build fetcher"
 )
@@ -89,6 +104,12 @@ const (
 	reasonGarbageCollect     event.Reason = "GarbageCollect"
 	reasonInstall            event.Reason = "InstallPackageRevision"
 	reasonPaused             event.Reason = "ReconciliationPaused"
+	reasonPendingApproval    event.Reason = "PendingApproval"
+	reasonFamilyVersions     event.Reason = "ProviderFamilyVersions"
+	reasonSoak               event.Reason = "SoakPackageRevision"
+	reasonUpgradeUnsafe      event.Reason = "UnsafePackageUpgrade"
+	reasonVerify             event.Reason = "VerifyPackage"
+	reasonUpdate             event.Reason = "UpdatePackage"
 )
 
 // ReconcilerOption is used to configure the Reconciler.
@@ -137,18 +158,66 @@ func WithRecorder(er event.Recorder) ReconcilerOption {
 	}
 }
 
+// WithFamilyVersionEnforcement specifies that the Reconciler shouldn't
+// activate a package revision until every package in its provider family wants
+// the same version.
+func WithFamilyVersionEnforcement() ReconcilerOption {
+	return func(r *Reconciler) {
+		r.familyVersions = true
+	}
+}
+
+// WithUpgradeCheckEnforcement specifies that the Reconciler shouldn't activate
+// a package revision until its CRDs are known to be safe to upgrade.
+func WithUpgradeCheckEnforcement() ReconcilerOption {
+	return func(r *Reconciler) {
+		r.upgradeChecks = true
+	}
+}
+
+// WithVerifier specifies how the Reconciler should verify a package's image
+// before it creates a new revision for it.
+func WithVerifier(v Verifier) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.verify = v
+	}
+}
+
+// WithUpdater specifies how the Reconciler should find newer versions of
+// packages that have an update policy.
+func WithUpdater(u Updater) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.update = u
+	}
+}
+
 // Reconciler reconciles packages.
 type Reconciler struct {
 	client resource.ClientApplicator
 	pkg    Revisioner
+	verify Verifier
+	update Updater
 	log    logging.Logger
 	record event.Recorder
 
+	familyVersions bool
+	upgradeChecks  bool
+
 	newPackage             func() v1.Package
 	newPackageRevision     func() v1.PackageRevision
 	newPackageRevisionList func() v1.PackageRevisionList
 }
 
+// revisionerOptions returns the package revisioner options shared by all
+// package kinds.
+func revisionerOptions(o controller.Options) []PackageRevisionerOption {
+	ro := []PackageRevisionerOption{WithDefaultRegistry(o.DefaultRegistry)}
+	if o.Features.Enabled(features.EnableAlphaLocalPackages) {
+		ro = append(ro, WithLocalSources(xpkg.NewLocalPackages(o.LocalPackagesDir)))
+	}
+	return ro
+}
+
 // SetupProvider adds a controller that reconciles Providers.
 func SetupProvider(mgr ctrl.Manager, o controller.Options) error {
 	name := "packages/" + strings.ToLower(v1.ProviderGroupKind)
@@ -169,10 +238,22 @@ func SetupProvider(mgr ctrl.Manager, o controller.Options) error {
 		WithNewPackageFn(np),
 		WithNewPackageRevisionFn(nr),
 		WithNewPackageRevisionListFn(nrl),
-		WithRevisioner(NewPackageRevisioner(f, WithDefaultRegistry(o.DefaultRegistry))),
+		WithRevisioner(NewPackageRevisioner(f, revisionerOptions(o)...)),
 		WithLogger(o.Logger.WithValues("controller", name)),
 		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
 	}
+	if o.Features.Enabled(features.EnableAlphaProviderFamilyVersions) {
+		opts = append(opts, WithFamilyVersionEnforcement())
+	}
+	if o.Features.Enabled(features.EnableAlphaCRDUpgradeChecks) {
+		opts = append(opts, WithUpgradeCheckEnforcement())
+	}
+	if o.Features.Enabled(features.EnableAlphaSignatureVerification) {
+		opts = append(opts, WithVerifier(NewImageConfigVerifier(mgr.GetClient(), f, o.DefaultRegistry, o.Namespace)))
+	}
+	if o.Features.Enabled(features.EnableAlphaPackageUpdates) {
+		opts = append(opts, WithUpdater(NewRegistryUpdater(f, o.DefaultRegistry)))
+	}
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
@@ -198,14 +279,26 @@ func SetupConfiguration(mgr ctrl.Manager, o controller.Options) error {
 		return errors.Wrap(err, "cannot build fetcher")
 	}
 
-	r := NewReconciler(mgr,
+	ro := revisionerOptions(o)
+	if o.Features.Enabled(features.EnableAlphaConfigMapPackages) {
+		ro = append(ro, WithConfigMapSources(mgr.GetAPIReader(), o.Namespace))
+	}
+
+	opts := []ReconcilerOption{
 		WithNewPackageFn(np),
 		WithNewPackageRevisionFn(nr),
 		WithNewPackageRevisionListFn(nrl),
-		WithRevisioner(NewPackageRevisioner(fetcher, WithDefaultRegistry(o.DefaultRegistry))),
+		WithRevisioner(NewPackageRevisioner(fetcher, ro...)),
 		WithLogger(o.Logger.WithValues("controller", name)),
 		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
-	)
+	}
+	if o.Features.Enabled(features.EnableAlphaSignatureVerification) {
+		opts = append(opts, WithVerifier(NewImageConfigVerifier(mgr.GetClient(), fetcher, o.DefaultRegistry, o.Namespace)))
+	}
+	if o.Features.Enabled(features.EnableAlphaPackageUpdates) {
+		opts = append(opts, WithUpdater(NewRegistryUpdater(fetcher, o.DefaultRegistry)))
+	}
+	r := NewReconciler(mgr, opts...)
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
@@ -235,10 +328,19 @@ func SetupFunction(mgr ctrl.Manager, o controller.Options) error {
 		WithNewPackageFn(np),
 		WithNewPackageRevisionFn(nr),
 		WithNewPackageRevisionListFn(nrl),
-		WithRevisioner(NewPackageRevisioner(f, WithDefaultRegistry(o.DefaultRegistry))),
+		WithRevisioner(NewPackageRevisioner(f, revisionerOptions(o)...)),
 		WithLogger(o.Logger.WithValues("controller", name)),
 		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
 	}
+	if o.Features.Enabled(features.EnableAlphaCRDUpgradeChecks) {
+		opts = append(opts, WithUpgradeCheckEnforcement())
+	}
+	if o.Features.Enabled(features.EnableAlphaSignatureVerification) {
+		opts = append(opts, WithVerifier(NewImageConfigVerifier(mgr.GetClient(), f, o.DefaultRegistry, o.Namespace)))
+	}
+	if o.Features.Enabled(features.EnableAlphaPackageUpdates) {
+		opts = append(opts, WithUpdater(NewRegistryUpdater(f, o.DefaultRegistry)))
+	}
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
@@ -296,6 +398,46 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		p.CleanConditions()
 	}
 
+	// Packages with an update policy are periodically updated to the newest
+	// version that satisfies it. We're requeued to check again once the
+	// interval has passed. We don't let a failed check block installing the
+	// current version. Updating the status requeues us to install it.
+	if r.update != nil && p.GetUpdatePolicy() != nil && updateDue(p, time.Now()) == 0 {
+		src, check, err := r.update.Update(ctx, p)
+		if err != nil {
+			err = errors.Wrap(err, errCheckUpdate)
+			log.Debug(errCheckUpdate, "error", err)
+			r.record.Event(p, event.Warning(reasonUpdate, err))
+
+			// We record when a failed check happened so that we don't
+			// check again until the interval has passed. We keep the
+			// versions reported by the last successful check.
+			failed := v1.PackageUpdateCheck{}
+			if uc := p.GetUpdateCheck(); uc != nil {
+				failed = *uc
+			}
+			failed.LastCheckTime = metav1.Now()
+			p.SetUpdateCheck(&failed)
+			return reconcile.Result{RequeueAfter: updateInterval(p)}, errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
+		}
+		if src != p.GetSource() {
+			from := p.GetSource()
+			p.SetSource(src)
+			if err := r.client.Update(ctx, p); err != nil {
+				if kerrors.IsConflict(err) {
+					return reconcile.Result{Requeue: true}, nil
+				}
+				err = errors.Wrap(err, errUpdatePackage)
+				r.record.Event(p, event.Warning(reasonUpdate, err))
+				return reconcile.Result{}, err
+			}
+			r.record.Event(p, event.Normal(reasonUpdate, fmt.Sprintf("Updated package from %s to %s", from, src)))
+		}
+		check.LastCheckTime = metav1.Now()
+		p.SetUpdateCheck(&check)
+		return reconcile.Result{RequeueAfter: updateInterval(p)}, errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
+	}
+
 	// Get existing package revisions.
 	prs := r.newPackageRevisionList()
 	if err := r.client.List(ctx, prs, client.MatchingLabels(map[string]string{v1.LabelParentPackage: p.GetName()})); resource.IgnoreNotFound(err) != nil {
@@ -304,7 +446,15 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return reconcile.Result{}, err
 	}
 
-	revisionName, err := r.pkg.Revision(ctx, p)
+	revisionName, digest, err := r.pkg.Revision(ctx, p)
+	if wait, ok := xpkg.RateLimited(err); ok {
+		// We don't return an error, which would requeue us with a short
+		// backoff. We wait until the registry is likely to serve us again.
+		err = errors.Wrap(err, errUnpack)
+		p.SetConditions(v1.RegistryRateLimited().WithMessage(err.Error()))
+		r.record.Event(p, event.Warning(reasonUnpack, err))
+		return reconcile.Result{RequeueAfter: wait}, errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
+	}
 	if err != nil {
 		err = errors.Wrap(err, errUnpack)
 		p.SetConditions(v1.Unpacking().WithMessage(err.Error()))
@@ -323,31 +473,75 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
 	}
 
+	// We don't create a new revision until its package image is verified.
+	if r.verify != nil && !revisionExists(revisionName, prs.GetRevisions()) {
+		if err := r.verify.Verify(ctx, p, digest); err != nil {
+			err = errors.Wrap(err, errVerify)
+			p.SetConditions(v1.VerificationFailed().WithMessage(err.Error()))
+			r.record.Event(p, event.Warning(reasonVerify, err))
+
+			if updateErr := r.client.Status().Update(ctx, p); updateErr != nil {
+				return reconcile.Result{}, errors.Wrap(updateErr, errUpdateStatus)
+			}
+
+			return reconcile.Result{}, err
+		}
+	}
+
 	// Set the current revision and identifier.
 	p.SetCurrentRevision(revisionName)
 	p.SetCurrentIdentifier(p.GetSource())
 
+	// A package that requires approval keeps its previously active revision
+	// active until the current revision is approved.
+	pending := approvalPending(p, revisionName, prs.GetRevisions())
+
+	// A provider in a family keeps its previously active revision active
+	// until every provider in the family wants the same version.
+	diverged := ""
+	if r.familyVersions && !pending {
+		if diverged, err = r.familyDiverged(ctx, p, revisionName, prs.GetRevisions()); err != nil {
+			err = errors.Wrap(err, errListFamilyRevisions)
+			r.record.Event(p, event.Warning(reasonFamilyVersions, err))
+			return reconcile.Result{}, err
+		}
+		pending = diverged != ""
+	}
+
+	// A new revision keeps the previously active revision active until it
+	// has been healthy for the package's soak period.
+	period, err := v1.SoakPeriod(p)
+	if err != nil {
+		err = errors.Wrap(err, errSoakPeriod)
+		r.record.Event(p, event.Warning(reasonSoak, err))
+		return reconcile.Result{}, err
+	}
+	soaking, soakedAt := false, time.Time{}
+	if !pending {
+		soaking, soakedAt = soakUntil(p, period, revisionName, prs.GetRevisions(), time.Now())
+		pending = soaking
+	}
+
+	// A new revision keeps the previously active revision active until it
+	// has found that its CRDs are safe to upgrade.
+	unsafe := false
+	if r.upgradeChecks && !pending {
+		unsafe = upgradeUnchecked(p, revisionName, prs.GetRevisions())
+		pending = unsafe
+	}
+
 	pr := r.newPackageRevision()
 	maxRevision := int64(0)
-	oldestRevision := int64(math.MaxInt64)
-	oldestRevisionIndex := -1
 	revisions := prs.GetRevisions()
 
 	// Check to see if revision already exists.
-	for index, rev := range revisions {
+	for _, rev := range revisions {
 		revisionNum := rev.GetRevision()
 
 		// Set max revision to the highest numbered existing revision.
 		if revisionNum > maxRevision {
 			maxRevision = revisionNum
 		}
-
-		// Set oldest revision to the lowest numbered revision and
-		// record its index.
-		if revisionNum < oldestRevision {
-			oldestRevision = revisionNum
-			oldestRevisionIndex = index
-		}
 		// If revision name is same as current revision, then revision
 		// already exists.
 		if rev.GetName() == p.GetCurrentRevision() {
@@ -356,10 +550,11 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 			// all non-current revisions are inactive.
 			continue
 		}
-		if rev.GetDesiredState() == v1.PackageRevisionActive {
+		if rev.GetDesiredState() == v1.PackageRevisionActive && !pending {
 			// If revision is not the current revision, set to
 			// inactive. This should always be done, regardless of
-			// the package's revision activation policy.
+			// the package's revision activation policy, unless the
+			// current revision is pending approval.
 			rev.SetDesiredState(v1.PackageRevisionInactive)
 			if err := r.client.Apply(ctx, rev, resource.MustBeControllableBy(p.GetUID())); err != nil {
 				if kerrors.IsConflict(err) {
@@ -377,17 +572,26 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		pr.SetRevision(maxRevision + 1)
 	}
 
-	// Check to see if there are revisions eligible for garbage collection.
-	if p.GetRevisionHistoryLimit() != nil &&
-		*p.GetRevisionHistoryLimit() != 0 &&
-		len(revisions) > (int(*p.GetRevisionHistoryLimit())+1) {
-		gcRev := revisions[oldestRevisionIndex]
-		// Find the oldest revision and delete it.
-		if err := r.client.Delete(ctx, gcRev); err != nil {
-			err = errors.Wrap(err, errGCPackageRevision)
-			r.record.Event(p, event.Warning(reasonGarbageCollect, err))
-			return reconcile.Result{}, err
+	// Garbage collect inactive revisions that fall outside the revision
+	// history limit, or that are older than the maximum revision age.
+	maxAge, err := v1.RevisionMaxAge(p)
+	if err != nil {
+		err = errors.Wrap(err, errRevisionMaxAge)
+		r.record.Event(p, event.Warning(reasonGarbageCollect, err))
+		return reconcile.Result{}, err
+	}
+	if gc := revisionsToGC(p.GetCurrentRevision(), revisions, p.GetRevisionHistoryLimit(), maxAge, time.Now()); len(gc) > 0 {
+		names := make([]string, 0, len(gc))
+		for _, rev := range gc {
+			if err := r.client.Delete(ctx, rev); resource.IgnoreNotFound(err) != nil {
+				err = errors.Wrap(err, errGCPackageRevision)
+				r.record.Event(p, event.Warning(reasonGarbageCollect, err))
+				return reconcile.Result{}, err
+			}
+			names = append(names, rev.GetName())
 		}
+		p.SetLastGarbageCollection(&v1.RevisionGarbageCollection{Time: metav1.Now(), Revisions: names})
+		r.record.Event(p, event.Normal(reasonGarbageCollect, fmt.Sprintf("Garbage collected package revisions %s", strings.Join(names, ", "))))
 	}
 
 	// TODO(phisco): refactor these conditions to make it clearer
@@ -408,15 +612,43 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		r.record.Event(p, event.Warning(reasonInstall, errors.New(errUnknownPackageRevisionHealth)))
 	}
 
+	// Packages with a runtime also report the health of their current
+	// revision's runtime. A revision that doesn't have a runtime, or hasn't
+	// checked its health yet, has no reason.
+	if prRuntime := pr.GetCondition(v1.TypeRuntimeHealthy); prRuntime.Reason != "" {
+		p.SetConditions(prRuntime)
+	}
+
+	// Likewise, packages report whether their current revision's CRDs are
+	// safe to upgrade if it has checked.
+	if prUpgrade := pr.GetCondition(v1.TypeUpgradeSafe); prUpgrade.Reason != "" {
+		p.SetConditions(prUpgrade)
+	}
+
 	// Create the non-existent package revision.
 	pr.SetName(revisionName)
 	pr.SetLabels(map[string]string{v1.LabelParentPackage: p.GetName()})
-	pr.SetSource(p.GetSource())
+	// When we verify package images, a revision named after its image's
+	// digest is pinned to that digest, so that it pulls the image that was
+	// verified even if the package's tag later moves. We don't unpin an
+	// existing revision if we didn't resolve its digest this time, e.g.
+	// because its package's pull policy is IfNotPresent.
+	switch {
+	case r.verify == nil:
+		pr.SetSource(p.GetSource())
+	case digest != "":
+		pr.SetSource(xpkg.PinSource(p.GetSource(), digest))
+	case pr.GetSource() == "":
+		pr.SetSource(p.GetSource())
+	}
 	pr.SetPackagePullPolicy(p.GetPackagePullPolicy())
 	pr.SetPackagePullSecrets(p.GetPackagePullSecrets())
 	pr.SetIgnoreCrossplaneConstraints(p.GetIgnoreCrossplaneConstraints())
 	pr.SetSkipDependencyResolution(p.GetSkipDependencyResolution())
 	pr.SetCommonLabels(p.GetCommonLabels())
+	pr.SetExternallyManagedObjects(p.GetExternallyManagedObjects())
+
+	propagateAnnotations(p, pr)
 
 	if pwr, ok := p.(v1.PackageWithRuntime); ok {
 		pwrr := pr.(v1.PackageRevisionWithRuntime)
@@ -427,8 +659,8 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	}
 
 	// If current revision is not active, and we have an automatic or
-	// undefined activation policy, always activate.
-	if pr.GetDesiredState() != v1.PackageRevisionActive && (p.GetActivationPolicy() == nil || *p.GetActivationPolicy() == v1.AutomaticActivation) {
+	// undefined activation policy, activate unless it's pending approval.
+	if pr.GetDesiredState() != v1.PackageRevisionActive && !pending && (p.GetActivationPolicy() == nil || *p.GetActivationPolicy() == v1.AutomaticActivation) {
 		pr.SetDesiredState(v1.PackageRevisionActive)
 	}
 
@@ -444,10 +676,16 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return reconcile.Result{}, err
 	}
 
-	// Handle changes in labels
-	same := reflect.DeepEqual(pr.GetCommonLabels(), p.GetCommonLabels())
+	// Handle changes in labels, externally managed objects, and propagated
+	// annotations. Apply won't remove labels, annotations, or omitted fields.
+	same := reflect.DeepEqual(pr.GetCommonLabels(), p.GetCommonLabels()) &&
+		reflect.DeepEqual(pr.GetExternallyManagedObjects(), p.GetExternallyManagedObjects()) &&
+		v1.WebhooksDisabled(pr) == v1.WebhooksDisabled(p) &&
+		v1.UnsafeUpgradeAllowed(pr) == v1.UnsafeUpgradeAllowed(p)
 	if !same {
 		pr.SetCommonLabels(p.GetCommonLabels())
+		pr.SetExternallyManagedObjects(p.GetExternallyManagedObjects())
+		propagateAnnotations(p, pr)
 		if err := r.client.Update(ctx, pr); err != nil {
 			if kerrors.IsConflict(err) {
 				return reconcile.Result{Requeue: true}, nil
@@ -458,6 +696,11 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		}
 	}
 
+	// The gates below only emit an event when they change the package's
+	// conditions, so we must remember them before we reset them.
+	installed := p.GetCondition(v1.TypeInstalled)
+	approval := p.GetCondition(v1.TypeRevisionApproved)
+
 	p.SetConditions(v1.Active())
 
 	// If current revision is still not active, the package is inactive.
@@ -465,9 +708,289 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		p.SetConditions(v1.Inactive().WithMessage("Package is inactive"))
 	}
 
+	if diverged != "" {
+		msg := fmt.Sprintf("Package revision %s won't be activated until every provider in its family wants the same version. %s", revisionName, diverged)
+		if installed.Reason != v1.ReasonInactive || installed.Message != msg {
+			r.record.Event(p, event.Normal(reasonFamilyVersions, msg))
+		}
+		p.SetConditions(v1.Inactive().WithMessage(msg))
+		return reconcile.Result{RequeueAfter: familyWait}, errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
+	}
+
+	if soaking {
+		msg := fmt.Sprintf("Package revision %s will be activated once it has been healthy for %s.", revisionName, period)
+		wait := period
+		if !soakedAt.IsZero() {
+			// The message must be stable across reconciles, so we report when
+			// soaking will finish rather than how long remains.
+			msg = fmt.Sprintf("Package revision %s is soaking. It will be activated at %s, once it has been healthy for %s.", revisionName, soakedAt.UTC().Format(time.RFC3339), period)
+			wait = time.Until(soakedAt)
+		}
+		if installed.Reason != v1.ReasonSoaking || installed.Message != msg {
+			r.record.Event(p, event.Normal(reasonSoak, msg))
+		}
+		p.SetConditions(v1.Soaking().WithMessage(msg))
+		return reconcile.Result{RequeueAfter: wait}, errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
+	}
+
+	if unsafe {
+		msg := fmt.Sprintf("Package revision %s won't be activated until it has checked that its CRDs are safe to upgrade.", revisionName)
+		if c := pr.GetCondition(v1.TypeUpgradeSafe); c.Status == corev1.ConditionFalse {
+			msg = fmt.Sprintf("Package revision %s won't be activated. %s", revisionName, c.Message)
+		}
+		if installed.Reason != v1.ReasonInactive || installed.Message != msg {
+			r.record.Event(p, event.Warning(reasonUpgradeUnsafe, errors.New(msg)))
+		}
+		p.SetConditions(v1.Inactive().WithMessage(msg))
+		return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
+	}
+
+	if v1.ApprovalRequired(p) {
+		p.SetConditions(v1.Approved())
+	}
+	if pending {
+		msg := fmt.Sprintf("Package revision %s is pending approval. Set the %s annotation to %q to approve it.", revisionName, v1.AnnotationApprovedRevision, revisionName)
+		if approval.Reason != v1.ReasonPendingApproval || approval.Message != msg {
+			r.record.Event(p, event.Normal(reasonPendingApproval, msg))
+		}
+		p.SetConditions(v1.PendingApproval().WithMessage(msg))
+		p.SetConditions(v1.Inactive().WithMessage(msg))
+	}
+
 	// NOTE(hasheddan): when the first package revision is created for a
 	// package, the health of the package is not set until the revision reports
 	// its health. If updating from an existing revision, the package health
 	// will match the health of the old revision until the next reconcile.
 	return pullBasedRequeue(p.GetPackagePullPolicy()), errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
 }
+
+// revisionExists returns true if the named revision is one of the supplied
+// revisions.
+func revisionExists(revision string, revs []v1.PackageRevision) bool {
+	for _, rev := range revs {
+		if rev.GetName() == revision {
+			return true
+		}
+	}
+	return false
+}
+
+// approvalPending returns true if the supplied package requires approval, and
+// the named revision has been neither approved nor activated. Revisions are
+// only gated when the package's activation policy is automatic; a manual
+// activation policy already gates every revision.
+func approvalPending(p v1.Package, revision string, revs []v1.PackageRevision) bool {
+	if !v1.ApprovalRequired(p) || v1.RevisionApproved(p, revision) {
+		return false
+	}
+	if p.GetActivationPolicy() != nil && *p.GetActivationPolicy() != v1.AutomaticActivation {
+		return false
+	}
+	for _, rev := range revs {
+		if rev.GetName() == revision && rev.GetDesiredState() == v1.PackageRevisionActive {
+			return false
+		}
+	}
+	return true
+}
+
+// soakUntil returns true if the named revision of the supplied package is
+// soaking, i.e. it must be healthy for the supplied soak period before it's
+// activated. If the revision is healthy it also returns when it will have
+// soaked. Like approvalPending, it doesn't gate a revision that's already
+// active or a package whose activation policy is manual. It also doesn't gate
+// a package's first revision, because there's no previously active revision
+// to keep active while it soaks.
+func soakUntil(p v1.Package, period time.Duration, revision string, revs []v1.PackageRevision, now time.Time) (bool, time.Time) {
+	if period <= 0 {
+		return false, time.Time{}
+	}
+	if p.GetActivationPolicy() != nil && *p.GetActivationPolicy() != v1.AutomaticActivation {
+		return false, time.Time{}
+	}
+
+	var current v1.PackageRevision
+	previous := false
+	for _, rev := range revs {
+		if rev.GetName() == revision {
+			current = rev
+			continue
+		}
+		if rev.GetDesiredState() == v1.PackageRevisionActive {
+			previous = true
+		}
+	}
+	if !previous {
+		return false, time.Time{}
+	}
+	if current == nil {
+		return true, time.Time{}
+	}
+	if current.GetDesiredState() == v1.PackageRevisionActive {
+		return false, time.Time{}
+	}
+
+	healthy := current.GetCondition(v1.TypeHealthy)
+	if healthy.Status != corev1.ConditionTrue {
+		return true, time.Time{}
+	}
+	until := healthy.LastTransitionTime.Add(period)
+	return now.Before(until), until
+}
+
+// upgradeUnchecked returns true if the named revision of the supplied package
+// hasn't found that its CRDs are safe to upgrade, unless the package allows
+// unsafe upgrades. Like soakUntil, it doesn't gate a revision that's already
+// active, a package whose activation policy is manual, or a package's first
+// revision.
+func upgradeUnchecked(p v1.Package, revision string, revs []v1.PackageRevision) bool {
+	if v1.UnsafeUpgradeAllowed(p) {
+		return false
+	}
+	if p.GetActivationPolicy() != nil && *p.GetActivationPolicy() != v1.AutomaticActivation {
+		return false
+	}
+
+	var current v1.PackageRevision
+	previous := false
+	for _, rev := range revs {
+		if rev.GetName() == revision {
+			current = rev
+			continue
+		}
+		if rev.GetDesiredState() == v1.PackageRevisionActive {
+			previous = true
+		}
+	}
+	if !previous {
+		return false
+	}
+	if current == nil {
+		return true
+	}
+	if current.GetDesiredState() == v1.PackageRevisionActive {
+		return false
+	}
+	return current.GetCondition(v1.TypeUpgradeSafe).Status != corev1.ConditionTrue
+}
+
+// familyDiverged returns a description of how the supplied package's version
+// diverges from the other packages in its provider family, or an empty string
+// if it doesn't. A package's version is the tag of its newest revision.
+// Packages referenced by digest aren't compared. Like approvalPending, it
+// doesn't gate the named revision if it's already active or the package's
+// activation policy is manual.
+func (r *Reconciler) familyDiverged(ctx context.Context, p v1.Package, revision string, revs []v1.PackageRevision) (string, error) {
+	if p.GetActivationPolicy() != nil && *p.GetActivationPolicy() != v1.AutomaticActivation {
+		return "", nil
+	}
+
+	// The family label is propagated from package metadata to revisions when
+	// they're unpacked, so a new revision may not have it yet. Fall back to
+	// the family of the newest revision that does.
+	family := ""
+	newest := int64(-1)
+	for _, rev := range revs {
+		if rev.GetName() == revision && rev.GetDesiredState() == v1.PackageRevisionActive {
+			return "", nil
+		}
+		f := rev.GetLabels()[v1.LabelProviderFamily]
+		if f == "" {
+			continue
+		}
+		if rev.GetName() == revision {
+			family = f
+			break
+		}
+		if rev.GetRevision() > newest {
+			family, newest = f, rev.GetRevision()
+		}
+	}
+	if family == "" {
+		return "", nil
+	}
+
+	version := tag(p.GetSource())
+	if version == "" {
+		return "", nil
+	}
+
+	l := r.newPackageRevisionList()
+	if err := r.client.List(ctx, l, client.MatchingLabels{v1.LabelProviderFamily: family}); err != nil {
+		return "", err
+	}
+
+	// Find the newest revision of each other package in the family.
+	latest := map[string]v1.PackageRevision{}
+	for _, rev := range l.GetRevisions() {
+		parent := rev.GetLabels()[v1.LabelParentPackage]
+		if parent == "" || parent == p.GetName() {
+			continue
+		}
+		if cur, ok := latest[parent]; !ok || rev.GetRevision() > cur.GetRevision() {
+			latest[parent] = rev
+		}
+	}
+
+	diverged := make([]string, 0)
+	for parent, rev := range latest {
+		if v := tag(rev.GetSource()); v != "" && v != version {
+			diverged = append(diverged, fmt.Sprintf("%s wants %s", parent, v))
+		}
+	}
+	if len(diverged) == 0 {
+		return "", nil
+	}
+	sort.Strings(diverged)
+	return fmt.Sprintf("This package wants %s, but %s.", version, strings.Join(diverged, ", ")), nil
+}
+
+// tag returns the tag of the supplied package source, or an empty string if
+// it's referenced by digest or can't be parsed. A source that's pinned to a
+// digest returns the tag it was pinned from.
+func tag(source string) string {
+	t, err := name.NewTag(xpkg.UnpinSource(source))
+	if err != nil {
+		return ""
+	}
+	return t.TagStr()
+}
+
+// revisionsToGC returns the supplied package revisions that should be garbage
+// collected, newest first. The current, active, and pinned revisions are never
+// garbage collected. Any other revision is garbage collected if there are at
+// least limit newer such revisions, or if it's older than maxAge. A nil or
+// zero limit, or a zero maxAge, is no limit.
+func revisionsToGC(current string, revs []v1.PackageRevision, limit *int64, maxAge time.Duration, now time.Time) []v1.PackageRevision {
+	candidates := make([]v1.PackageRevision, 0, len(revs))
+	for _, rev := range revs {
+		if rev.GetName() == current || rev.GetDesiredState() == v1.PackageRevisionActive || v1.Pinned(rev) {
+			continue
+		}
+		candidates = append(candidates, rev)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].GetRevision() > candidates[j].GetRevision() })
+
+	gc := make([]v1.PackageRevision, 0)
+	for i, rev := range candidates {
+		if limit != nil && *limit != 0 && int64(i) >= *limit {
+			gc = append(gc, rev)
+			continue
+		}
+		if maxAge > 0 && now.Sub(rev.GetCreationTimestamp().Time) > maxAge {
+			gc = append(gc, rev)
+		}
+	}
+	return gc
+}
+
+// propagateAnnotations propagates the annotations that disable webhooks and
+// allow unsafe upgrades from a package to a package revision.
+func propagateAnnotations(from, to metav1.Object) {
+	for _, k := range []string{v1.AnnotationDisableWebhooks, v1.AnnotationAllowUnsafeUpgrade} {
+		meta.RemoveAnnotations(to, k)
+		if v, ok := from.GetAnnotations()[k]; ok {
+			meta.AddAnnotations(to, map[string]string{k: v})
+		}
+	}
+}
@@ -20,13 +20,18 @@ import (
 	"context"
 	"io"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -40,25 +45,62 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/internal/xpkg"
 )
 
 var _ Revisioner = &MockRevisioner{}
 
 type MockRevisioner struct {
-	MockRevision func() (string, error)
+	MockRevision func() (string, string, error)
 }
 
-func NewMockRevisionFn(hash string, err error) func() (string, error) {
-	return func() (string, error) {
-		return hash, err
+func NewMockRevisionFn(hash string, err error) func() (string, string, error) {
+	return func() (string, string, error) {
+		return hash, "", err
 	}
 }
-func (m *MockRevisioner) Revision(context.Context, v1.Package) (string, error) {
+func (m *MockRevisioner) Revision(context.Context, v1.Package) (string, string, error) {
 	return m.MockRevision()
 }
 
+// familyList returns a MockListFn for the provider-aws-s3 Provider, which
+// belongs to the family-aws family. Its previously active revision wants
+// v1.0.0. The provider-aws-ec2 Provider in the same family wants the supplied
+// version.
+func familyList(ec2 string) test.MockListFn {
+	rev := func(name, parent, version string, revision int64, state v1.PackageRevisionDesiredState) v1.ProviderRevision {
+		pr := v1.ProviderRevision{ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{v1.LabelParentPackage: parent, v1.LabelProviderFamily: "family-aws"},
+		}}
+		pr.SetSource("xpkg.upbound.io/upbound/" + parent + ":" + version)
+		pr.SetRevision(revision)
+		pr.SetDesiredState(state)
+		pr.SetConditions(v1.Healthy())
+		return pr
+	}
+	s3 := rev("provider-aws-s3-7654321", "provider-aws-s3", "v1.0.0", 1, v1.PackageRevisionActive)
+
+	return func(_ context.Context, o client.ObjectList, opts ...client.ListOption) error {
+		lo := &client.ListOptions{}
+		lo.ApplyOptions(opts)
+		l := o.(*v1.ProviderRevisionList)
+		if lo.LabelSelector.Matches(labels.Set{v1.LabelProviderFamily: "family-aws"}) {
+			*l = v1.ProviderRevisionList{Items: []v1.ProviderRevision{
+				s3,
+				rev("provider-aws-ec2-1111111", "provider-aws-ec2", "v0.9.0", 1, v1.PackageRevisionInactive),
+				rev("provider-aws-ec2-2222222", "provider-aws-ec2", ec2, 2, v1.PackageRevisionActive),
+			}}
+			return nil
+		}
+		*l = v1.ProviderRevisionList{Items: []v1.ProviderRevision{s3}}
+		return nil
+	}
+}
+
 func TestReconcile(t *testing.T) {
 	errBoom := errors.New("boom")
+	errRateLimited := &xpkg.RateLimitedError{Registry: "index.docker.io", RetryAfter: 2 * time.Minute}
 	testLog := logging.NewLogrLogger(zap.New(zap.UseDevMode(true), zap.WriteTo(io.Discard)).WithName("testlog"))
 	pullAlways := corev1.PullAlways
 	trueVal := true
@@ -166,6 +208,162 @@ func TestReconcile(t *testing.T) {
 				err: errors.Wrap(errBoom, errUnpack),
 			},
 		},
+		"RegistryRateLimited": {
+			reason: "We should wait until the registry is likely to serve us again if it rate limits fetching the revision for a package.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet:  test.NewMockGetFn(nil),
+							MockList: test.NewMockListFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.Configuration{}
+								want.SetConditions(v1.RegistryRateLimited().WithMessage(errors.Wrap(errRateLimited, errUnpack).Error()))
+								if diff := cmp.Diff(want, o); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+					},
+					log:    testLog,
+					record: event.NewNopRecorder(),
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("", errRateLimited),
+					},
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: 2 * time.Minute},
+			},
+		},
+		"ErrVerify": {
+			reason: "We should return an error and not create a revision if the package's image can't be verified.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet:  test.NewMockGetFn(nil),
+							MockList: test.NewMockListFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.Configuration{}
+								want.SetConditions(v1.VerificationFailed().WithMessage(errors.Wrap(errBoom, errVerify).Error()))
+								if diff := cmp.Diff(want, o); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							t.Errorf("We shouldn't create a revision for an unverified package.")
+							return nil
+						}),
+					},
+					log:    testLog,
+					record: event.NewNopRecorder(),
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					verify: VerifierFn(func(_ context.Context, _ v1.Package, _ string) error {
+						return errBoom
+					}),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errVerify),
+			},
+		},
+		"SuccessfulUpdate": {
+			reason: "We should update the package's source and report the update check if a newer version satisfies its update policy.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetSource("xpkg.upbound.io/cool/config:v1.0.0")
+								p.Spec.UpdatePolicy = &v1.UpdatePolicy{Version: ptr.To(">=1.0.0")}
+								return nil
+							}),
+							MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+								if diff := cmp.Diff("xpkg.upbound.io/cool/config:v1.1.0", o.(*v1.Configuration).GetSource()); diff != "" {
+									t.Errorf("-want source, +got source:\n%s", diff)
+								}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.PackageUpdateCheck{LatestVersion: "v1.1.0", AvailableVersions: []string{"v1.1.0", "v1.0.0"}}
+								if diff := cmp.Diff(want, o.(*v1.Configuration).GetUpdateCheck(), cmpopts.IgnoreFields(v1.PackageUpdateCheck{}, "LastCheckTime")); diff != "" {
+									t.Errorf("-want update check, +got update check:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							t.Errorf("We shouldn't create a revision until we've reconciled the updated package.")
+							return nil
+						}),
+					},
+					log:    testLog,
+					record: event.NewNopRecorder(),
+					update: UpdaterFn(func(_ context.Context, _ v1.Package) (string, v1.PackageUpdateCheck, error) {
+						return "xpkg.upbound.io/cool/config:v1.1.0", v1.PackageUpdateCheck{LatestVersion: "v1.1.0", AvailableVersions: []string{"v1.1.0", "v1.0.0"}}, nil
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: defaultUpdateInterval},
+			},
+		},
+		"FailedUpdateCheck": {
+			reason: "We should record when a failed update check happened, keeping the last known versions, so that we don't check again until the update interval has passed.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetSource("xpkg.upbound.io/cool/config:v1.0.0")
+								p.Spec.UpdatePolicy = &v1.UpdatePolicy{Version: ptr.To(">=1.0.0")}
+								p.SetUpdateCheck(&v1.PackageUpdateCheck{LatestVersion: "v1.0.0", AvailableVersions: []string{"v1.0.0"}})
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								got := o.(*v1.Configuration).GetUpdateCheck()
+								want := &v1.PackageUpdateCheck{LatestVersion: "v1.0.0", AvailableVersions: []string{"v1.0.0"}}
+								if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(v1.PackageUpdateCheck{}, "LastCheckTime")); diff != "" {
+									t.Errorf("-want update check, +got update check:\n%s", diff)
+								}
+								if got.LastCheckTime.IsZero() {
+									t.Errorf("We should record when the failed update check happened.")
+								}
+								return nil
+							}),
+						},
+					},
+					log:    testLog,
+					record: event.NewNopRecorder(),
+					update: UpdaterFn(func(_ context.Context, _ v1.Package) (string, v1.PackageUpdateCheck, error) {
+						return "", v1.PackageUpdateCheck{}, errBoom
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: defaultUpdateInterval},
+			},
+		},
 		"SuccessfulNoExistingRevisionsAutoActivate": {
 			reason: "We should be active and not requeue on successful creation of the first revision with auto activation.",
 			args: args{
@@ -213,6 +411,87 @@ func TestReconcile(t *testing.T) {
 				r: reconcile.Result{Requeue: false},
 			},
 		},
+		"SuccessfulVerifiedRevisionPinned": {
+			reason: "We should pin a new revision's source to the digest it's named after if we verify package images.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetSource("xpkg.upbound.io/cool/config:v1.0.0")
+								return nil
+							}),
+							MockList:         test.NewMockListFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							if diff := cmp.Diff("xpkg.upbound.io/cool/config:v1.0.0@sha256:1234567", o.(*v1.ConfigurationRevision).GetSource()); diff != "" {
+								t.Errorf("-want source, +got source:\n%s", diff)
+							}
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: func() (string, string, error) {
+							return "test-1234567", "sha256:1234567", nil
+						},
+					},
+					verify: VerifierFn(func(_ context.Context, _ v1.Package, _ string) error {
+						return nil
+					}),
+					log:    testLog,
+					record: event.NewNopRecorder(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"SuccessfulUnverifiedRevisionNotPinned": {
+			reason: "We should not pin a new revision's source to a digest if we don't verify package images.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetSource("xpkg.upbound.io/cool/config:v1.0.0")
+								return nil
+							}),
+							MockList:         test.NewMockListFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							if diff := cmp.Diff("xpkg.upbound.io/cool/config:v1.0.0", o.(*v1.ConfigurationRevision).GetSource()); diff != "" {
+								t.Errorf("-want source, +got source:\n%s", diff)
+							}
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: func() (string, string, error) {
+							return "test-1234567", "sha256:1234567", nil
+						},
+					},
+					log:    testLog,
+					record: event.NewNopRecorder(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
 		"SuccessfulNoExistingRevisionsAutoActivatePullAlways": {
 			reason: "We should be active and requeue after wait on successful creation of the first revision with auto activation and package pull policy Always.",
 			args: args{
@@ -445,8 +724,8 @@ func TestReconcile(t *testing.T) {
 				r: reconcile.Result{Requeue: false},
 			},
 		},
-		"ErrUpdatePackageRevision": {
-			reason: "Failing to update a package revision should cause us to return an error.",
+		"SuccessfulRevisionPendingApproval": {
+			reason: "We should leave the previously active revision active, and report that the package is pending approval, when a new revision requires approval.",
 			args: args{
 				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
 				rec: &Reconciler{
@@ -459,30 +738,32 @@ func TestReconcile(t *testing.T) {
 								p := o.(*v1.Configuration)
 								p.SetName("test")
 								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetAnnotations(map[string]string{v1.AnnotationRequireApproval: "true"})
 								return nil
 							}),
 							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
 								l := o.(*v1.ConfigurationRevisionList)
 								cr := v1.ConfigurationRevision{
 									ObjectMeta: metav1.ObjectMeta{
-										Name: "test-1234567",
+										Name: "test-7654321",
 									},
 								}
-								cr.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
 								cr.SetConditions(v1.Healthy())
-								cr.SetDesiredState(v1.PackageRevisionInactive)
-								c := v1.ConfigurationRevisionList{
-									Items: []v1.ConfigurationRevision{cr},
-								}
-								*l = c
+								cr.SetDesiredState(v1.PackageRevisionActive)
+								cr.SetRevision(1)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{cr}}
 								return nil
 							}),
 							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								msg := "Package revision test-1234567 is pending approval. Set the pkg.crossplane.io/approved-revision annotation to \"test-1234567\" to approve it."
 								want := &v1.Configuration{}
 								want.SetName("test")
 								want.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								want.SetAnnotations(map[string]string{v1.AnnotationRequireApproval: "true"})
 								want.SetCurrentRevision("test-1234567")
-								want.SetConditions(v1.Healthy())
+								want.SetConditions(v1.UnknownHealth())
+								want.SetConditions(v1.Inactive().WithMessage(msg))
+								want.SetConditions(v1.PendingApproval().WithMessage(msg))
 								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
@@ -490,7 +771,14 @@ func TestReconcile(t *testing.T) {
 							}),
 						},
 						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
-							return errBoom
+							pr := o.(*v1.ConfigurationRevision)
+							if pr.GetName() != "test-1234567" {
+								t.Errorf("unexpected apply of revision %q", pr.GetName())
+							}
+							if pr.GetDesiredState() == v1.PackageRevisionActive {
+								t.Errorf("revision %q should not be activated before it's approved", pr.GetName())
+							}
+							return nil
 						}),
 					},
 					pkg: &MockRevisioner{
@@ -501,11 +789,11 @@ func TestReconcile(t *testing.T) {
 				},
 			},
 			want: want{
-				err: errors.Wrap(errBoom, errApplyPackageRevision),
+				r: reconcile.Result{Requeue: false},
 			},
 		},
-		"SuccessfulTransitionUnhealthy": {
-			reason: "If the current revision is unhealthy the package should be also.",
+		"SuccessfulRevisionStillPendingApproval": {
+			reason: "We should not emit another event when a revision that was already pending approval is still pending approval.",
 			args: args{
 				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
 				rec: &Reconciler{
@@ -518,54 +806,69 @@ func TestReconcile(t *testing.T) {
 								p := o.(*v1.Configuration)
 								p.SetName("test")
 								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetAnnotations(map[string]string{v1.AnnotationRequireApproval: "true"})
+								msg := "Package revision test-1234567 is pending approval. Set the pkg.crossplane.io/approved-revision annotation to \"test-1234567\" to approve it."
+								p.SetConditions(v1.Inactive().WithMessage(msg))
+								p.SetConditions(v1.PendingApproval().WithMessage(msg))
 								return nil
 							}),
 							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
 								l := o.(*v1.ConfigurationRevisionList)
 								cr := v1.ConfigurationRevision{
 									ObjectMeta: metav1.ObjectMeta{
-										Name: "test-1234567",
+										Name: "test-7654321",
 									},
 								}
-								cr.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
-								cr.SetConditions(v1.Unhealthy().WithMessage("some message"))
+								cr.SetConditions(v1.Healthy())
 								cr.SetDesiredState(v1.PackageRevisionActive)
-								c := v1.ConfigurationRevisionList{
-									Items: []v1.ConfigurationRevision{cr},
-								}
-								*l = c
+								cr.SetRevision(1)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{cr}}
 								return nil
 							}),
 							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								msg := "Package revision test-1234567 is pending approval. Set the pkg.crossplane.io/approved-revision annotation to \"test-1234567\" to approve it."
 								want := &v1.Configuration{}
 								want.SetName("test")
 								want.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								want.SetAnnotations(map[string]string{v1.AnnotationRequireApproval: "true"})
 								want.SetCurrentRevision("test-1234567")
-								want.SetConditions(v1.Unhealthy().WithMessage("some message"))
-								want.SetConditions(v1.Active())
+								want.SetConditions(v1.UnknownHealth())
+								want.SetConditions(v1.Inactive().WithMessage(msg))
+								want.SetConditions(v1.PendingApproval().WithMessage(msg))
 								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
 							}),
 						},
-						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							pr := o.(*v1.ConfigurationRevision)
+							if pr.GetName() != "test-1234567" {
+								t.Errorf("unexpected apply of revision %q", pr.GetName())
+							}
+							if pr.GetDesiredState() == v1.PackageRevisionActive {
+								t.Errorf("revision %q should not be activated before it's approved", pr.GetName())
+							}
 							return nil
 						}),
 					},
 					pkg: &MockRevisioner{
 						MockRevision: NewMockRevisionFn("test-1234567", nil),
 					},
-					log:    testLog,
-					record: event.NewNopRecorder(),
+					log: testLog,
+					record: eventRecorderFn(func(_ runtime.Object, e event.Event) {
+						if e.Reason == reasonPendingApproval {
+							t.Errorf("unexpected %s event: %s", e.Reason, e.Message)
+						}
+					}),
 				},
 			},
 			want: want{
 				r: reconcile.Result{Requeue: false},
 			},
 		},
-		"SuccessfulRevisionExistsNeedGC": {
-			reason: "We should successfully garbage collect when an old revision falls outside range.",
+		"SuccessfulRevisionApproved": {
+			reason: "We should activate a new revision, and deactivate the previously active revision, once the new revision is approved.",
 			args: args{
 				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
 				rec: &Reconciler{
@@ -578,59 +881,668 @@ func TestReconcile(t *testing.T) {
 								p := o.(*v1.Configuration)
 								p.SetName("test")
 								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetAnnotations(map[string]string{
+									v1.AnnotationRequireApproval:  "true",
+									v1.AnnotationApprovedRevision: "test-1234567",
+								})
 								return nil
 							}),
 							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
 								l := o.(*v1.ConfigurationRevisionList)
 								cr := v1.ConfigurationRevision{
 									ObjectMeta: metav1.ObjectMeta{
-										Name: "test-1234567",
+										Name: "test-7654321",
 									},
 								}
-								cr.SetRevision(3)
-								cr.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
 								cr.SetConditions(v1.Healthy())
-								cr.SetDesiredState(v1.PackageRevisionInactive)
-								c := v1.ConfigurationRevisionList{
-									Items: []v1.ConfigurationRevision{
-										cr,
-										{
-											ObjectMeta: metav1.ObjectMeta{
-												Name: "made-the-cut",
-											},
-											Spec: v1.PackageRevisionSpec{
-												Revision: 2,
-											},
-										},
-										{
-											ObjectMeta: metav1.ObjectMeta{
-												Name: "missed-the-cut",
-											},
-											Spec: v1.PackageRevisionSpec{
-												Revision: 1,
-											},
-										},
-									},
-								}
-								*l = c
+								cr.SetDesiredState(v1.PackageRevisionActive)
+								cr.SetRevision(1)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{cr}}
 								return nil
 							}),
 							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
 								want := &v1.Configuration{}
 								want.SetName("test")
 								want.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								want.SetAnnotations(map[string]string{
+									v1.AnnotationRequireApproval:  "true",
+									v1.AnnotationApprovedRevision: "test-1234567",
+								})
 								want.SetCurrentRevision("test-1234567")
-								want.SetConditions(v1.Healthy())
+								want.SetConditions(v1.UnknownHealth())
 								want.SetConditions(v1.Active())
+								want.SetConditions(v1.Approved())
 								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
 							}),
-							MockDelete: test.NewMockDeleteFn(nil),
 						},
 						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
-							want := &v1.ConfigurationRevision{}
+							pr := o.(*v1.ConfigurationRevision)
+							want := map[string]v1.PackageRevisionDesiredState{
+								"test-7654321": v1.PackageRevisionInactive,
+								"test-1234567": v1.PackageRevisionActive,
+							}
+							if diff := cmp.Diff(want[pr.GetName()], pr.GetDesiredState()); diff != "" {
+								t.Errorf("%s: -want desired state, +got desired state:\n%s", pr.GetName(), diff)
+							}
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					log:    testLog,
+					record: event.NewNopRecorder(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"SuccessfulRevisionSoaking": {
+			reason: "We should leave the previously active revision active, and report that the new revision is soaking, until the new revision has been healthy for the package's soak period.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetAnnotations(map[string]string{v1.AnnotationSoakPeriod: "1h"})
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cr := v1.ConfigurationRevision{
+									ObjectMeta: metav1.ObjectMeta{
+										Name: "test-7654321",
+									},
+								}
+								cr.SetConditions(v1.Healthy())
+								cr.SetDesiredState(v1.PackageRevisionActive)
+								cr.SetRevision(1)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{cr}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								msg := "Package revision test-1234567 will be activated once it has been healthy for 1h0m0s."
+								want := &v1.Configuration{}
+								want.SetName("test")
+								want.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								want.SetAnnotations(map[string]string{v1.AnnotationSoakPeriod: "1h"})
+								want.SetCurrentRevision("test-1234567")
+								want.SetConditions(v1.UnknownHealth())
+								want.SetConditions(v1.Soaking().WithMessage(msg))
+								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							pr := o.(*v1.ConfigurationRevision)
+							if pr.GetName() != "test-1234567" {
+								t.Errorf("unexpected apply of revision %q", pr.GetName())
+							}
+							if pr.GetDesiredState() == v1.PackageRevisionActive {
+								t.Errorf("revision %q should not be activated while it's soaking", pr.GetName())
+							}
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					log:    testLog,
+					record: event.NewNopRecorder(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: time.Hour},
+			},
+		},
+		"SuccessfulRevisionStillSoaking": {
+			reason: "We should not emit another event when a revision that was already soaking is still soaking.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetAnnotations(map[string]string{v1.AnnotationSoakPeriod: "1h"})
+								p.SetConditions(v1.Soaking().WithMessage("Package revision test-1234567 will be activated once it has been healthy for 1h0m0s."))
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cr := v1.ConfigurationRevision{
+									ObjectMeta: metav1.ObjectMeta{
+										Name: "test-7654321",
+									},
+								}
+								cr.SetConditions(v1.Healthy())
+								cr.SetDesiredState(v1.PackageRevisionActive)
+								cr.SetRevision(1)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{cr}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								msg := "Package revision test-1234567 will be activated once it has been healthy for 1h0m0s."
+								want := &v1.Configuration{}
+								want.SetName("test")
+								want.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								want.SetAnnotations(map[string]string{v1.AnnotationSoakPeriod: "1h"})
+								want.SetCurrentRevision("test-1234567")
+								want.SetConditions(v1.UnknownHealth())
+								want.SetConditions(v1.Soaking().WithMessage(msg))
+								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							pr := o.(*v1.ConfigurationRevision)
+							if pr.GetName() != "test-1234567" {
+								t.Errorf("unexpected apply of revision %q", pr.GetName())
+							}
+							if pr.GetDesiredState() == v1.PackageRevisionActive {
+								t.Errorf("revision %q should not be activated while it's soaking", pr.GetName())
+							}
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					log: testLog,
+					record: eventRecorderFn(func(_ runtime.Object, e event.Event) {
+						if e.Reason == reasonSoak {
+							t.Errorf("unexpected %s event: %s", e.Reason, e.Message)
+						}
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: time.Hour},
+			},
+		},
+		"SuccessfulRevisionStillUnsafe": {
+			reason: "We should not emit another event when a revision that was already waiting to be checked for a safe upgrade still is.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					upgradeChecks:          true,
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetConditions(v1.Inactive().WithMessage("Package revision test-1234567 won't be activated until it has checked that its CRDs are safe to upgrade."))
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cr := v1.ConfigurationRevision{
+									ObjectMeta: metav1.ObjectMeta{
+										Name: "test-7654321",
+									},
+								}
+								cr.SetConditions(v1.Healthy())
+								cr.SetDesiredState(v1.PackageRevisionActive)
+								cr.SetRevision(1)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{cr}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								msg := "Package revision test-1234567 won't be activated until it has checked that its CRDs are safe to upgrade."
+								want := &v1.Configuration{}
+								want.SetName("test")
+								want.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								want.SetCurrentRevision("test-1234567")
+								want.SetConditions(v1.UnknownHealth())
+								want.SetConditions(v1.Inactive().WithMessage(msg))
+								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							pr := o.(*v1.ConfigurationRevision)
+							if pr.GetName() != "test-1234567" {
+								t.Errorf("unexpected apply of revision %q", pr.GetName())
+							}
+							if pr.GetDesiredState() == v1.PackageRevisionActive {
+								t.Errorf("revision %q should not be activated until its upgrade is checked", pr.GetName())
+							}
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					log: testLog,
+					record: eventRecorderFn(func(_ runtime.Object, e event.Event) {
+						if e.Reason == reasonUpgradeUnsafe {
+							t.Errorf("unexpected %s event: %s", e.Reason, e.Message)
+						}
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"ErrSoakPeriod": {
+			reason: "We should return an error if the package's soak period isn't a valid duration.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetAnnotations(map[string]string{v1.AnnotationSoakPeriod: "a while"})
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+						},
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					log:    testLog,
+					record: event.NewNopRecorder(),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errors.New(`time: invalid duration "a while"`), errSoakPeriod),
+			},
+		},
+		"SuccessfulFamilyVersionsDiverged": {
+			reason: "We should leave the previously active revision active, and report why, when a new revision's version diverges from the rest of its provider family.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "provider-aws-s3"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Provider{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ProviderRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ProviderRevisionList{} },
+					familyVersions:         true,
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Provider)
+								p.SetName("provider-aws-s3")
+								p.SetGroupVersionKind(v1.ProviderGroupVersionKind)
+								p.SetSource("xpkg.upbound.io/upbound/provider-aws-s3:v1.1.0")
+								return nil
+							}),
+							MockList: familyList("v1.0.0"),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								msg := "Package revision provider-aws-s3-1234567 won't be activated until every provider in its family wants the same version. This package wants v1.1.0, but provider-aws-ec2 wants v1.0.0."
+								want := &v1.Provider{}
+								want.SetName("provider-aws-s3")
+								want.SetGroupVersionKind(v1.ProviderGroupVersionKind)
+								want.SetSource("xpkg.upbound.io/upbound/provider-aws-s3:v1.1.0")
+								want.SetCurrentRevision("provider-aws-s3-1234567")
+								want.SetCurrentIdentifier("xpkg.upbound.io/upbound/provider-aws-s3:v1.1.0")
+								want.SetConditions(v1.UnknownHealth())
+								want.SetConditions(v1.Inactive().WithMessage(msg))
+								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							pr := o.(*v1.ProviderRevision)
+							if pr.GetName() != "provider-aws-s3-1234567" {
+								t.Errorf("unexpected apply of revision %q", pr.GetName())
+							}
+							if pr.GetDesiredState() == v1.PackageRevisionActive {
+								t.Errorf("revision %q should not be activated while its family's versions diverge", pr.GetName())
+							}
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("provider-aws-s3-1234567", nil),
+					},
+					log:    testLog,
+					record: event.NewNopRecorder(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: familyWait},
+			},
+		},
+		"SuccessfulFamilyVersionsStillDiverged": {
+			reason: "We should not emit another event when a revision's version still diverges from the rest of its provider family.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "provider-aws-s3"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Provider{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ProviderRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ProviderRevisionList{} },
+					familyVersions:         true,
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Provider)
+								p.SetName("provider-aws-s3")
+								p.SetGroupVersionKind(v1.ProviderGroupVersionKind)
+								p.SetSource("xpkg.upbound.io/upbound/provider-aws-s3:v1.1.0")
+								p.SetConditions(v1.Inactive().WithMessage("Package revision provider-aws-s3-1234567 won't be activated until every provider in its family wants the same version. This package wants v1.1.0, but provider-aws-ec2 wants v1.0.0."))
+								return nil
+							}),
+							MockList: familyList("v1.0.0"),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								msg := "Package revision provider-aws-s3-1234567 won't be activated until every provider in its family wants the same version. This package wants v1.1.0, but provider-aws-ec2 wants v1.0.0."
+								want := &v1.Provider{}
+								want.SetName("provider-aws-s3")
+								want.SetGroupVersionKind(v1.ProviderGroupVersionKind)
+								want.SetSource("xpkg.upbound.io/upbound/provider-aws-s3:v1.1.0")
+								want.SetCurrentRevision("provider-aws-s3-1234567")
+								want.SetCurrentIdentifier("xpkg.upbound.io/upbound/provider-aws-s3:v1.1.0")
+								want.SetConditions(v1.UnknownHealth())
+								want.SetConditions(v1.Inactive().WithMessage(msg))
+								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							pr := o.(*v1.ProviderRevision)
+							if pr.GetName() != "provider-aws-s3-1234567" {
+								t.Errorf("unexpected apply of revision %q", pr.GetName())
+							}
+							if pr.GetDesiredState() == v1.PackageRevisionActive {
+								t.Errorf("revision %q should not be activated while its family's versions diverge", pr.GetName())
+							}
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("provider-aws-s3-1234567", nil),
+					},
+					log: testLog,
+					record: eventRecorderFn(func(_ runtime.Object, e event.Event) {
+						if e.Reason == reasonFamilyVersions {
+							t.Errorf("unexpected %s event: %s", e.Reason, e.Message)
+						}
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: familyWait},
+			},
+		},
+		"SuccessfulFamilyVersionsConsistent": {
+			reason: "We should activate a new revision, and deactivate the previously active revision, once every provider in its family wants the same version.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "provider-aws-s3"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Provider{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ProviderRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ProviderRevisionList{} },
+					familyVersions:         true,
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Provider)
+								p.SetName("provider-aws-s3")
+								p.SetGroupVersionKind(v1.ProviderGroupVersionKind)
+								p.SetSource("xpkg.upbound.io/upbound/provider-aws-s3:v1.1.0")
+								return nil
+							}),
+							MockList: familyList("v1.1.0"),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.Provider{}
+								want.SetName("provider-aws-s3")
+								want.SetGroupVersionKind(v1.ProviderGroupVersionKind)
+								want.SetSource("xpkg.upbound.io/upbound/provider-aws-s3:v1.1.0")
+								want.SetCurrentRevision("provider-aws-s3-1234567")
+								want.SetCurrentIdentifier("xpkg.upbound.io/upbound/provider-aws-s3:v1.1.0")
+								want.SetConditions(v1.UnknownHealth())
+								want.SetConditions(v1.Active())
+								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							pr := o.(*v1.ProviderRevision)
+							want := map[string]v1.PackageRevisionDesiredState{
+								"provider-aws-s3-7654321": v1.PackageRevisionInactive,
+								"provider-aws-s3-1234567": v1.PackageRevisionActive,
+							}
+							if diff := cmp.Diff(want[pr.GetName()], pr.GetDesiredState()); diff != "" {
+								t.Errorf("%s: -want desired state, +got desired state:\n%s", pr.GetName(), diff)
+							}
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("provider-aws-s3-1234567", nil),
+					},
+					log:    testLog,
+					record: event.NewNopRecorder(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"ErrUpdatePackageRevision": {
+			reason: "Failing to update a package revision should cause us to return an error.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cr := v1.ConfigurationRevision{
+									ObjectMeta: metav1.ObjectMeta{
+										Name: "test-1234567",
+									},
+								}
+								cr.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+								cr.SetConditions(v1.Healthy())
+								cr.SetDesiredState(v1.PackageRevisionInactive)
+								c := v1.ConfigurationRevisionList{
+									Items: []v1.ConfigurationRevision{cr},
+								}
+								*l = c
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.Configuration{}
+								want.SetName("test")
+								want.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								want.SetCurrentRevision("test-1234567")
+								want.SetConditions(v1.Healthy())
+								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							return errBoom
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					log:    testLog,
+					record: event.NewNopRecorder(),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errApplyPackageRevision),
+			},
+		},
+		"SuccessfulTransitionUnhealthy": {
+			reason: "If the current revision is unhealthy the package should be also.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cr := v1.ConfigurationRevision{
+									ObjectMeta: metav1.ObjectMeta{
+										Name: "test-1234567",
+									},
+								}
+								cr.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+								cr.SetConditions(v1.Unhealthy().WithMessage("some message"))
+								cr.SetDesiredState(v1.PackageRevisionActive)
+								c := v1.ConfigurationRevisionList{
+									Items: []v1.ConfigurationRevision{cr},
+								}
+								*l = c
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.Configuration{}
+								want.SetName("test")
+								want.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								want.SetCurrentRevision("test-1234567")
+								want.SetConditions(v1.Unhealthy().WithMessage("some message"))
+								want.SetConditions(v1.Active())
+								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					log:    testLog,
+					record: event.NewNopRecorder(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"SuccessfulRevisionExistsNeedGC": {
+			reason: "We should successfully garbage collect when an old revision falls outside range.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cr := v1.ConfigurationRevision{
+									ObjectMeta: metav1.ObjectMeta{
+										Name: "test-1234567",
+									},
+								}
+								cr.SetRevision(3)
+								cr.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+								cr.SetConditions(v1.Healthy())
+								cr.SetDesiredState(v1.PackageRevisionInactive)
+								c := v1.ConfigurationRevisionList{
+									Items: []v1.ConfigurationRevision{
+										cr,
+										{
+											ObjectMeta: metav1.ObjectMeta{
+												Name: "made-the-cut",
+											},
+											Spec: v1.PackageRevisionSpec{
+												Revision: 2,
+											},
+										},
+										{
+											ObjectMeta: metav1.ObjectMeta{
+												Name: "missed-the-cut",
+											},
+											Spec: v1.PackageRevisionSpec{
+												Revision: 1,
+											},
+										},
+									},
+								}
+								*l = c
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.Configuration{}
+								want.SetName("test")
+								want.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								want.SetCurrentRevision("test-1234567")
+								want.SetConditions(v1.Healthy())
+								want.SetConditions(v1.Active())
+								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+							MockDelete: test.NewMockDeleteFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							want := &v1.ConfigurationRevision{}
 							want.SetLabels(map[string]string{"pkg.crossplane.io/package": "test"})
 							want.SetName("test-1234567")
 							want.SetOwnerReferences([]metav1.OwnerReference{{
@@ -850,3 +1762,346 @@ func TestReconcile(t *testing.T) {
 		})
 	}
 }
+
+func TestSoakUntil(t *testing.T) {
+	now := time.Now()
+	healthy := now.Add(-30 * time.Minute)
+	manual := v1.ManualActivation
+
+	rev := func(name string, state v1.PackageRevisionDesiredState, h *time.Time) v1.PackageRevision {
+		cr := &v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		cr.SetDesiredState(state)
+		if h != nil {
+			c := v1.Healthy()
+			c.LastTransitionTime = metav1.NewTime(*h)
+			cr.SetConditions(c)
+		}
+		return cr
+	}
+
+	type args struct {
+		p      v1.Package
+		period time.Duration
+		revs   []v1.PackageRevision
+	}
+	type want struct {
+		soaking bool
+		until   time.Time
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoSoakPeriod": {
+			reason: "A revision shouldn't soak if the package has no soak period.",
+			args: args{
+				p:    &v1.Configuration{},
+				revs: []v1.PackageRevision{rev("old", v1.PackageRevisionActive, &healthy)},
+			},
+		},
+		"ManualActivation": {
+			reason: "A revision shouldn't soak if the package's activation policy is manual.",
+			args: args{
+				p:      &v1.Configuration{Spec: v1.ConfigurationSpec{PackageSpec: v1.PackageSpec{RevisionActivationPolicy: &manual}}},
+				period: time.Hour,
+				revs:   []v1.PackageRevision{rev("old", v1.PackageRevisionActive, &healthy)},
+			},
+		},
+		"FirstRevision": {
+			reason: "A package's first revision shouldn't soak.",
+			args: args{
+				p:      &v1.Configuration{},
+				period: time.Hour,
+				revs:   []v1.PackageRevision{rev("new", v1.PackageRevisionInactive, &healthy)},
+			},
+		},
+		"AlreadyActive": {
+			reason: "A revision that's already active shouldn't soak.",
+			args: args{
+				p:      &v1.Configuration{},
+				period: time.Hour,
+				revs: []v1.PackageRevision{
+					rev("old", v1.PackageRevisionActive, &healthy),
+					rev("new", v1.PackageRevisionActive, &healthy),
+				},
+			},
+		},
+		"NotYetCreated": {
+			reason: "A revision that doesn't exist yet should soak.",
+			args: args{
+				p:      &v1.Configuration{},
+				period: time.Hour,
+				revs:   []v1.PackageRevision{rev("old", v1.PackageRevisionActive, &healthy)},
+			},
+			want: want{soaking: true},
+		},
+		"NotYetHealthy": {
+			reason: "A revision that isn't healthy yet should soak, but we can't know until when.",
+			args: args{
+				p:      &v1.Configuration{},
+				period: time.Hour,
+				revs: []v1.PackageRevision{
+					rev("old", v1.PackageRevisionActive, &healthy),
+					rev("new", v1.PackageRevisionInactive, nil),
+				},
+			},
+			want: want{soaking: true},
+		},
+		"Soaking": {
+			reason: "A revision that hasn't been healthy for the soak period should soak until it has.",
+			args: args{
+				p:      &v1.Configuration{},
+				period: time.Hour,
+				revs: []v1.PackageRevision{
+					rev("old", v1.PackageRevisionActive, &healthy),
+					rev("new", v1.PackageRevisionInactive, &healthy),
+				},
+			},
+			want: want{soaking: true, until: healthy.Add(time.Hour)},
+		},
+		"Soaked": {
+			reason: "A revision that has been healthy for the soak period shouldn't soak.",
+			args: args{
+				p:      &v1.Configuration{},
+				period: 10 * time.Minute,
+				revs: []v1.PackageRevision{
+					rev("old", v1.PackageRevisionActive, &healthy),
+					rev("new", v1.PackageRevisionInactive, &healthy),
+				},
+			},
+			want: want{soaking: false, until: healthy.Add(10 * time.Minute)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			soaking, until := soakUntil(tc.args.p, tc.args.period, "new", tc.args.revs, now)
+			if diff := cmp.Diff(tc.want.soaking, soaking); diff != "" {
+				t.Errorf("\n%s\nsoakUntil(...): -want soaking, +got soaking:\n%s", tc.reason, diff)
+			}
+			// Conditions only store second precision.
+			if diff := cmp.Diff(tc.want.until.Truncate(time.Second), until.Truncate(time.Second)); diff != "" {
+				t.Errorf("\n%s\nsoakUntil(...): -want until, +got until:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestUpgradeUnchecked(t *testing.T) {
+	manual := v1.ManualActivation
+
+	rev := func(name string, state v1.PackageRevisionDesiredState, c ...commonv1.Condition) v1.PackageRevision {
+		cr := &v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		cr.SetDesiredState(state)
+		cr.SetConditions(c...)
+		return cr
+	}
+
+	type args struct {
+		p    v1.Package
+		revs []v1.PackageRevision
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   bool
+	}{
+		"UnsafeUpgradeAllowed": {
+			reason: "A revision shouldn't be gated if the package allows unsafe upgrades.",
+			args: args{
+				p: &v1.Configuration{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{v1.AnnotationAllowUnsafeUpgrade: "true"}}},
+				revs: []v1.PackageRevision{
+					rev("old", v1.PackageRevisionActive),
+					rev("new", v1.PackageRevisionInactive, v1.UpgradeUnsafe()),
+				},
+			},
+			want: false,
+		},
+		"ManualActivation": {
+			reason: "A revision shouldn't be gated if the package's activation policy is manual.",
+			args: args{
+				p:    &v1.Configuration{Spec: v1.ConfigurationSpec{PackageSpec: v1.PackageSpec{RevisionActivationPolicy: &manual}}},
+				revs: []v1.PackageRevision{rev("old", v1.PackageRevisionActive)},
+			},
+			want: false,
+		},
+		"FirstRevision": {
+			reason: "A package's first revision shouldn't be gated.",
+			args: args{
+				p:    &v1.Configuration{},
+				revs: []v1.PackageRevision{rev("new", v1.PackageRevisionInactive)},
+			},
+			want: false,
+		},
+		"AlreadyActive": {
+			reason: "A revision that's already active shouldn't be gated.",
+			args: args{
+				p: &v1.Configuration{},
+				revs: []v1.PackageRevision{
+					rev("old", v1.PackageRevisionActive),
+					rev("new", v1.PackageRevisionActive, v1.UpgradeUnsafe()),
+				},
+			},
+			want: false,
+		},
+		"NotYetCreated": {
+			reason: "A revision that doesn't exist yet should be gated.",
+			args: args{
+				p:    &v1.Configuration{},
+				revs: []v1.PackageRevision{rev("old", v1.PackageRevisionActive)},
+			},
+			want: true,
+		},
+		"NotYetChecked": {
+			reason: "A revision that hasn't checked its CRDs yet should be gated.",
+			args: args{
+				p: &v1.Configuration{},
+				revs: []v1.PackageRevision{
+					rev("old", v1.PackageRevisionActive),
+					rev("new", v1.PackageRevisionInactive),
+				},
+			},
+			want: true,
+		},
+		"Unsafe": {
+			reason: "A revision whose CRDs are unsafe to upgrade should be gated.",
+			args: args{
+				p: &v1.Configuration{},
+				revs: []v1.PackageRevision{
+					rev("old", v1.PackageRevisionActive),
+					rev("new", v1.PackageRevisionInactive, v1.UpgradeUnsafe()),
+				},
+			},
+			want: true,
+		},
+		"Safe": {
+			reason: "A revision whose CRDs are safe to upgrade shouldn't be gated.",
+			args: args{
+				p: &v1.Configuration{},
+				revs: []v1.PackageRevision{
+					rev("old", v1.PackageRevisionActive),
+					rev("new", v1.PackageRevisionInactive, v1.UpgradeSafe()),
+				},
+			},
+			want: false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := upgradeUnchecked(tc.args.p, "new", tc.args.revs)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nupgradeUnchecked(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRevisionsToGC(t *testing.T) {
+	now := time.Now()
+	limit := int64(1)
+
+	rev := func(name string, revision int64, state v1.PackageRevisionDesiredState, age time.Duration, pinned bool) v1.PackageRevision {
+		cr := &v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(now.Add(-age))}}
+		cr.SetRevision(revision)
+		cr.SetDesiredState(state)
+		if pinned {
+			cr.SetAnnotations(map[string]string{v1.AnnotationPinned: "true"})
+		}
+		return cr
+	}
+
+	type args struct {
+		revs   []v1.PackageRevision
+		limit  *int64
+		maxAge time.Duration
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   []string
+	}{
+		"NoPolicy": {
+			reason: "No revisions should be garbage collected if there's no limit or maximum age.",
+			args: args{
+				revs: []v1.PackageRevision{
+					rev("current", 3, v1.PackageRevisionActive, time.Minute, false),
+					rev("old", 2, v1.PackageRevisionInactive, time.Hour, false),
+					rev("older", 1, v1.PackageRevisionInactive, 2*time.Hour, false),
+				},
+			},
+			want: []string{},
+		},
+		"Limit": {
+			reason: "Inactive revisions beyond the revision history limit should be garbage collected, oldest last.",
+			args: args{
+				revs: []v1.PackageRevision{
+					rev("oldest", 1, v1.PackageRevisionInactive, 3*time.Hour, false),
+					rev("current", 4, v1.PackageRevisionActive, time.Minute, false),
+					rev("old", 3, v1.PackageRevisionInactive, time.Hour, false),
+					rev("older", 2, v1.PackageRevisionInactive, 2*time.Hour, false),
+				},
+				limit: &limit,
+			},
+			want: []string{"older", "oldest"},
+		},
+		"MaxAge": {
+			reason: "Inactive revisions older than the maximum age should be garbage collected, even within the limit.",
+			args: args{
+				revs: []v1.PackageRevision{
+					rev("current", 3, v1.PackageRevisionActive, 3*time.Hour, false),
+					rev("old", 2, v1.PackageRevisionInactive, 2*time.Hour, false),
+				},
+				limit:  &limit,
+				maxAge: time.Hour,
+			},
+			want: []string{"old"},
+		},
+		"Pinned": {
+			reason: "Pinned revisions should never be garbage collected, and shouldn't count toward the limit.",
+			args: args{
+				revs: []v1.PackageRevision{
+					rev("current", 4, v1.PackageRevisionActive, time.Minute, false),
+					rev("pinned", 3, v1.PackageRevisionInactive, 3*time.Hour, true),
+					rev("old", 2, v1.PackageRevisionInactive, time.Minute, false),
+					rev("older", 1, v1.PackageRevisionInactive, time.Minute, false),
+				},
+				limit:  &limit,
+				maxAge: time.Hour,
+			},
+			want: []string{"older"},
+		},
+		"Active": {
+			reason: "Active revisions, for example one kept active while a new revision is pending approval, should never be garbage collected.",
+			args: args{
+				revs: []v1.PackageRevision{
+					rev("current", 3, v1.PackageRevisionInactive, time.Minute, false),
+					rev("active", 2, v1.PackageRevisionActive, 3*time.Hour, false),
+					rev("old", 1, v1.PackageRevisionInactive, 3*time.Hour, false),
+				},
+				maxAge: time.Hour,
+			},
+			want: []string{"old"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			gc := revisionsToGC("current", tc.args.revs, tc.args.limit, tc.args.maxAge, now)
+			got := make([]string, 0, len(gc))
+			for _, rev := range gc {
+				got = append(got, rev.GetName())
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nrevisionsToGC(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+type eventRecorderFn func(obj runtime.Object, e event.Event)
+
+func (fn eventRecorderFn) Event(obj runtime.Object, e event.Event) { fn(obj, e) }
+
+func (fn eventRecorderFn) WithAnnotations(_ ...string) event.Recorder { return fn }
@@ -18,9 +18,13 @@ package manager
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 
 	"github.com/google/go-containerregistry/pkg/name"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 
@@ -31,17 +35,34 @@ import (
 const (
 	errBadReference = "package tag is not a valid reference"
 	errFetchPackage = "failed to fetch package digest from remote"
+
+	errConfigMapSourceDisabled = "ConfigMap package sources are not enabled"
+	errConfigMapSourceKind     = "only Configurations may be sourced from a ConfigMap"
+	errGetConfigMap            = "cannot get package ConfigMap"
+	errFmtNoStreamFile         = "package ConfigMap has no %q key"
+
+	errLocalSourceDisabled = "local package sources are not enabled"
+	errReadLocalPackage    = "cannot read local package"
+	errLocalPackageDigest  = "cannot compute local package digest"
 )
 
 // Revisioner extracts a revision name for a package source.
 type Revisioner interface {
-	Revision(context.Context, v1.Package) (string, error)
+	// Revision returns the revision name for the supplied package, and the
+	// digest of the package image the name was derived from. The digest is
+	// empty if the name wasn't derived from a registry image's digest.
+	Revision(context.Context, v1.Package) (string, string, error)
 }
 
 // PackageRevisioner extracts a revision name for a package source.
 type PackageRevisioner struct {
 	fetcher  xpkg.Fetcher
 	registry string
+
+	configMaps client.Reader
+	namespace  string
+
+	local *xpkg.LocalPackages
 }
 
 // A PackageRevisionerOption sets configuration for a package revisioner.
@@ -54,6 +75,23 @@ func WithDefaultRegistry(registry string) PackageRevisionerOption {
 	}
 }
 
+// WithConfigMapSources allows a package revisioner to extract revision names
+// for Configurations sourced from a ConfigMap in the supplied namespace.
+func WithConfigMapSources(c client.Reader, namespace string) PackageRevisionerOption {
+	return func(r *PackageRevisioner) {
+		r.configMaps = c
+		r.namespace = namespace
+	}
+}
+
+// WithLocalSources allows a package revisioner to extract revision names for
+// packages sourced from the supplied local packages.
+func WithLocalSources(l *xpkg.LocalPackages) PackageRevisionerOption {
+	return func(r *PackageRevisioner) {
+		r.local = l
+	}
+}
+
 // NewPackageRevisioner returns a new PackageRevisioner.
 func NewPackageRevisioner(fetcher xpkg.Fetcher, opts ...PackageRevisionerOption) *PackageRevisioner {
 	r := &PackageRevisioner{
@@ -66,25 +104,73 @@ func NewPackageRevisioner(fetcher xpkg.Fetcher, opts ...PackageRevisionerOption)
 }
 
 // Revision extracts a revision name for a package source.
-func (r *PackageRevisioner) Revision(ctx context.Context, p v1.Package) (string, error) {
+func (r *PackageRevisioner) Revision(ctx context.Context, p v1.Package) (string, string, error) {
+	if cm, ok := xpkg.ParseConfigMapSource(p.GetSource()); ok {
+		n, err := r.configMapRevision(ctx, p, cm)
+		return n, "", err
+	}
+	if path, ok := xpkg.ParseLocalSource(p.GetSource()); ok {
+		n, err := r.localRevision(p, path)
+		return n, "", err
+	}
 	pullPolicy := p.GetPackagePullPolicy()
 	if pullPolicy != nil && *pullPolicy == corev1.PullNever {
-		return xpkg.FriendlyID(p.GetName(), p.GetSource()), nil
+		return xpkg.FriendlyID(p.GetName(), p.GetSource()), "", nil
 	}
 	if pullPolicy != nil && *pullPolicy == corev1.PullIfNotPresent {
 		if p.GetCurrentIdentifier() == p.GetSource() {
-			return p.GetCurrentRevision(), nil
+			return p.GetCurrentRevision(), "", nil
 		}
 	}
 	ref, err := name.ParseReference(p.GetSource(), name.WithDefaultRegistry(r.registry))
 	if err != nil {
-		return "", errors.Wrap(err, errBadReference)
+		return "", "", errors.Wrap(err, errBadReference)
 	}
 	d, err := r.fetcher.Head(ctx, ref, v1.RefNames(p.GetPackagePullSecrets())...)
 	if err != nil || d == nil {
-		return "", errors.Wrap(err, errFetchPackage)
+		return "", "", errors.Wrap(err, errFetchPackage)
+	}
+	return xpkg.FriendlyID(p.GetName(), d.Digest.Hex), d.Digest.String(), nil
+}
+
+// configMapRevision extracts a revision name from the content of the ConfigMap
+// a package is sourced from. Pull policy doesn't apply to ConfigMaps; a new
+// revision is created whenever their content changes.
+func (r *PackageRevisioner) configMapRevision(ctx context.Context, p v1.Package, cmName string) (string, error) {
+	if r.configMaps == nil {
+		return "", errors.New(errConfigMapSourceDisabled)
+	}
+	if _, ok := p.(*v1.Configuration); !ok {
+		return "", errors.New(errConfigMapSourceKind)
+	}
+	cm := &corev1.ConfigMap{}
+	if err := r.configMaps.Get(ctx, types.NamespacedName{Namespace: r.namespace, Name: cmName}, cm); err != nil {
+		return "", errors.Wrap(err, errGetConfigMap)
+	}
+	stream, ok := cm.Data[xpkg.StreamFile]
+	if !ok {
+		return "", errors.Errorf(errFmtNoStreamFile, xpkg.StreamFile)
+	}
+	h := sha256.Sum256([]byte(stream))
+	return xpkg.FriendlyID(p.GetName(), hex.EncodeToString(h[:])), nil
+}
+
+// localRevision extracts a revision name from the digest of the local package
+// image a package is sourced from. Pull policy doesn't apply to local
+// packages; a new revision is created whenever their image changes.
+func (r *PackageRevisioner) localRevision(p v1.Package, path string) (string, error) {
+	if r.local == nil {
+		return "", errors.New(errLocalSourceDisabled)
+	}
+	img, err := r.local.Image(path)
+	if err != nil {
+		return "", errors.Wrap(err, errReadLocalPackage)
+	}
+	d, err := img.Digest()
+	if err != nil {
+		return "", errors.Wrap(err, errLocalPackageDigest)
 	}
-	return xpkg.FriendlyID(p.GetName(), d.Digest.Hex), nil
+	return xpkg.FriendlyID(p.GetName(), d.Hex), nil
 }
 
 // NopRevisioner returns an empty revision name.
@@ -96,6 +182,6 @@ func NewNopRevisioner() *NopRevisioner {
 }
 
 // Revision returns an empty revision name and no error.
-func (d *NopRevisioner) Revision(context.Context, v1.Package) (string, error) {
-	return "", nil
+func (d *NopRevisioner) Revision(context.Context, v1.Package) (string, string, error) {
+	return "", "", nil
 }
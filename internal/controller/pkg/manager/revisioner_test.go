@@ -21,8 +21,10 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	ociv1 "github.com/google/go-containerregistry/pkg/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
@@ -38,13 +40,15 @@ func TestPackageRevisioner(t *testing.T) {
 	pullIfNotPresent := corev1.PullIfNotPresent
 
 	type args struct {
-		f   xpkg.Fetcher
-		pkg v1.Package
+		f    xpkg.Fetcher
+		opts []PackageRevisionerOption
+		pkg  v1.Package
 	}
 
 	type want struct {
 		err    error
 		digest string
+		image  string
 	}
 
 	cases := map[string]struct {
@@ -129,12 +133,140 @@ func TestPackageRevisioner(t *testing.T) {
 				err: errors.Wrap(errBoom, errFetchPackage),
 			},
 		},
+		"SuccessfulRegistry": {
+			reason: "Should return a friendly identifier derived from the package image's digest, and the digest.",
+			args: args{
+				f: &fake.MockFetcher{
+					MockHead: fake.NewMockHeadFn(&ociv1.Descriptor{Digest: ociv1.Hash{Algorithm: "sha256", Hex: "c88b938d6e7b2ed43d40b71e5a55df9c60fa653bea0c0961f3294fac46d5b56e"}}, nil),
+				},
+				pkg: &v1.Provider{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "provider-aws",
+					},
+					Spec: v1.ProviderSpec{
+						PackageSpec: v1.PackageSpec{
+							Package: "crossplane/provider-aws:v1.0.0",
+						},
+					},
+				},
+			},
+			want: want{
+				digest: "provider-aws-c88b938d6e7b",
+				image:  "sha256:c88b938d6e7b2ed43d40b71e5a55df9c60fa653bea0c0961f3294fac46d5b56e",
+			},
+		},
+		"SuccessfulConfigMap": {
+			reason: "Should return a friendly identifier derived from the ConfigMap content if the package is sourced from a ConfigMap.",
+			args: args{
+				opts: []PackageRevisionerOption{WithConfigMapSources(&test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						obj.(*corev1.ConfigMap).Data = map[string]string{xpkg.StreamFile: "cool: stream"}
+						return nil
+					}),
+				}, "crossplane-system")},
+				pkg: &v1.Configuration{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cool-config",
+					},
+					Spec: v1.ConfigurationSpec{
+						PackageSpec: v1.PackageSpec{
+							Package: "configmap://cool-config",
+						},
+					},
+				},
+			},
+			want: want{
+				digest: "cool-config-41e74f6a16cf",
+			},
+		},
+		"ErrConfigMapSourceDisabled": {
+			reason: "Should return an error if a package is sourced from a ConfigMap but ConfigMap sources aren't enabled.",
+			args: args{
+				pkg: &v1.Configuration{
+					Spec: v1.ConfigurationSpec{
+						PackageSpec: v1.PackageSpec{
+							Package: "configmap://cool-config",
+						},
+					},
+				},
+			},
+			want: want{
+				err: errors.New(errConfigMapSourceDisabled),
+			},
+		},
+		"ErrLocalSourceDisabled": {
+			reason: "Should return an error if a package has a local source but local sources aren't enabled.",
+			args: args{
+				pkg: &v1.Provider{
+					Spec: v1.ProviderSpec{
+						PackageSpec: v1.PackageSpec{
+							Package: "file://cool-provider.xpkg",
+						},
+					},
+				},
+			},
+			want: want{
+				err: errors.New(errLocalSourceDisabled),
+			},
+		},
+		"ErrConfigMapSourceKind": {
+			reason: "Should return an error if a package other than a Configuration is sourced from a ConfigMap.",
+			args: args{
+				opts: []PackageRevisionerOption{WithConfigMapSources(&test.MockClient{}, "crossplane-system")},
+				pkg: &v1.Provider{
+					Spec: v1.ProviderSpec{
+						PackageSpec: v1.PackageSpec{
+							Package: "configmap://cool-provider",
+						},
+					},
+				},
+			},
+			want: want{
+				err: errors.New(errConfigMapSourceKind),
+			},
+		},
+		"ErrGetConfigMap": {
+			reason: "Should return an error if we cannot get the ConfigMap a package is sourced from.",
+			args: args{
+				opts: []PackageRevisionerOption{WithConfigMapSources(&test.MockClient{
+					MockGet: test.NewMockGetFn(errBoom),
+				}, "crossplane-system")},
+				pkg: &v1.Configuration{
+					Spec: v1.ConfigurationSpec{
+						PackageSpec: v1.PackageSpec{
+							Package: "configmap://cool-config",
+						},
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetConfigMap),
+			},
+		},
+		"ErrNoStreamFile": {
+			reason: "Should return an error if the ConfigMap a package is sourced from has no package YAML stream.",
+			args: args{
+				opts: []PackageRevisionerOption{WithConfigMapSources(&test.MockClient{
+					MockGet: test.NewMockGetFn(nil),
+				}, "crossplane-system")},
+				pkg: &v1.Configuration{
+					Spec: v1.ConfigurationSpec{
+						PackageSpec: v1.PackageSpec{
+							Package: "configmap://cool-config",
+						},
+					},
+				},
+			},
+			want: want{
+				err: errors.Errorf(errFmtNoStreamFile, xpkg.StreamFile),
+			},
+		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			r := NewPackageRevisioner(tc.args.f)
-			h, err := r.Revision(context.TODO(), tc.args.pkg)
+			r := NewPackageRevisioner(tc.args.f, tc.args.opts...)
+			h, d, err := r.Revision(context.TODO(), tc.args.pkg)
 
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\nr.Name(...): -want error, +got error:\n%s", tc.reason, diff)
@@ -142,6 +274,9 @@ func TestPackageRevisioner(t *testing.T) {
 			if diff := cmp.Diff(tc.want.digest, h, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\nr.Name(...): -want, +got:\n%s", tc.reason, diff)
 			}
+			if diff := cmp.Diff(tc.want.image, d); diff != "" {
+				t.Errorf("\n%s\nr.Name(...): -want digest, +got digest:\n%s", tc.reason, diff)
+			}
 		})
 	}
 }
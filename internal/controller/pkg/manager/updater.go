@@ -0,0 +1,206 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Masterminds/semver"
+	"github.com/google/go-containerregistry/pkg/name"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/internal/xpkg"
+)
+
+const (
+	errNoUpdatePolicy      = "package has no update policy"
+	errInvalidUpdateTarget = "update policy must set exactly one of channel or version"
+	errParseConstraint     = "cannot parse update policy version constraint"
+	errHeadChannel         = "cannot resolve update policy channel"
+	errFetchTags           = "cannot fetch package tags"
+	errFmtNoChannel        = "channel %q doesn't exist"
+
+	// maxAvailableVersions is the maximum number of available versions
+	// reported in a package's status.
+	maxAvailableVersions = 10
+
+	// defaultUpdateInterval is how often to check for a newer version if an
+	// update policy doesn't specify an interval.
+	defaultUpdateInterval = 1 * time.Hour
+)
+
+// An Updater finds the newest version of a package that satisfies its update
+// policy.
+type Updater interface {
+	// Update returns the source the supplied package should be updated to,
+	// which may be its current source, and the result of the check.
+	Update(ctx context.Context, p v1.Package) (string, v1.PackageUpdateCheck, error)
+}
+
+// An UpdaterFn finds the newest version of a package that satisfies its update
+// policy.
+type UpdaterFn func(ctx context.Context, p v1.Package) (string, v1.PackageUpdateCheck, error)
+
+// Update returns the source the supplied package should be updated to.
+func (fn UpdaterFn) Update(ctx context.Context, p v1.Package) (string, v1.PackageUpdateCheck, error) {
+	return fn(ctx, p)
+}
+
+// A RegistryUpdater finds the newest version of a package that satisfies its
+// update policy by querying the package's OCI registry.
+type RegistryUpdater struct {
+	fetcher  xpkg.Fetcher
+	registry string
+}
+
+// NewRegistryUpdater returns an Updater that queries the registry of each
+// package using the supplied Fetcher.
+func NewRegistryUpdater(f xpkg.Fetcher, registry string) *RegistryUpdater {
+	return &RegistryUpdater{fetcher: f, registry: registry}
+}
+
+// Update returns the source the supplied package should be updated to. A
+// package that tracks a channel is pinned to the digest its channel tag refers
+// to, e.g. registry/org/pkg:stable@sha256:... A package that tracks a version constraint is updated to the
+// greatest tag that satisfies it, unless its current version is greater.
+func (u *RegistryUpdater) Update(ctx context.Context, p v1.Package) (string, v1.PackageUpdateCheck, error) {
+	pol := p.GetUpdatePolicy()
+	if pol == nil {
+		return "", v1.PackageUpdateCheck{}, errors.New(errNoUpdatePolicy)
+	}
+
+	// Packages sourced from a ConfigMap or a local package have no registry
+	// to check.
+	if !xpkg.IsRegistrySource(p.GetSource()) {
+		return p.GetSource(), v1.PackageUpdateCheck{}, nil
+	}
+
+	ref, err := name.ParseReference(p.GetSource(), name.WithDefaultRegistry(u.registry))
+	if err != nil {
+		return "", v1.PackageUpdateCheck{}, errors.Wrap(err, errBadReference)
+	}
+
+	secrets := v1.RefNames(p.GetPackagePullSecrets())
+
+	switch {
+	case pol.Channel != nil && pol.Version == nil:
+		return u.updateChannel(ctx, p.GetSource(), ref, *pol.Channel, secrets)
+	case pol.Version != nil && pol.Channel == nil:
+		return u.updateVersion(ctx, p.GetSource(), ref, *pol.Version, secrets)
+	default:
+		return "", v1.PackageUpdateCheck{}, errors.New(errInvalidUpdateTarget)
+	}
+}
+
+func (u *RegistryUpdater) updateChannel(ctx context.Context, current string, ref name.Reference, channel string, secrets []string) (string, v1.PackageUpdateCheck, error) {
+	tag := ref.Context().Tag(channel)
+	d, err := u.fetcher.Head(ctx, tag, secrets...)
+	if err != nil {
+		return "", v1.PackageUpdateCheck{}, errors.Wrap(err, errHeadChannel)
+	}
+	if d == nil {
+		return "", v1.PackageUpdateCheck{}, errors.Errorf(errFmtNoChannel, channel)
+	}
+
+	check := v1.PackageUpdateCheck{LatestVersion: d.Digest.String(), AvailableVersions: []string{d.Digest.String()}}
+	if ref.Identifier() == d.Digest.String() {
+		return current, check, nil
+	}
+	// We keep the channel tag, so that the package's source still shows
+	// which channel it tracks.
+	return xpkg.PinSource(tag.String(), d.Digest.String()), check, nil
+}
+
+func (u *RegistryUpdater) updateVersion(ctx context.Context, current string, ref name.Reference, constraint string, secrets []string) (string, v1.PackageUpdateCheck, error) {
+	c, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return "", v1.PackageUpdateCheck{}, errors.Wrap(err, errParseConstraint)
+	}
+	tags, err := u.fetcher.Tags(ctx, ref, secrets...)
+	if err != nil {
+		return "", v1.PackageUpdateCheck{}, errors.Wrap(err, errFetchTags)
+	}
+
+	available := satisfyingVersions(c, tags)
+	check := v1.PackageUpdateCheck{AvailableVersions: available}
+	if len(available) > maxAvailableVersions {
+		check.AvailableVersions = available[:maxAvailableVersions]
+	}
+	if len(available) == 0 {
+		return current, check, nil
+	}
+	check.LatestVersion = available[0]
+
+	// We never downgrade a package. A package that's pinned to a digest, or
+	// to a tag that isn't a semantic version, is always updated.
+	latest := semver.MustParse(available[0])
+	if cur, err := semver.NewVersion(ref.Identifier()); err == nil && !latest.GreaterThan(cur) {
+		return current, check, nil
+	}
+	return fmt.Sprintf("%s:%s", ref.Context().String(), available[0]), check, nil
+}
+
+// satisfyingVersions returns the supplied tags that are semantic versions that
+// satisfy the supplied constraint, greatest first.
+func satisfyingVersions(c *semver.Constraints, tags []string) []string {
+	vs := []*semver.Version{}
+	for _, t := range tags {
+		v, err := semver.NewVersion(t)
+		if err != nil {
+			// We skip any tags that are not valid semantic versions.
+			continue
+		}
+		if c.Check(v) {
+			vs = append(vs, v)
+		}
+	}
+	sort.Sort(sort.Reverse(semver.Collection(vs)))
+
+	out := make([]string, len(vs))
+	for i, v := range vs {
+		out[i] = v.Original()
+	}
+	return out
+}
+
+// updateInterval returns how often the supplied package should be checked for
+// a newer version.
+func updateInterval(p v1.Package) time.Duration {
+	if pol := p.GetUpdatePolicy(); pol != nil && pol.Interval != nil && pol.Interval.Duration > 0 {
+		return pol.Interval.Duration
+	}
+	return defaultUpdateInterval
+}
+
+// updateDue returns how long until the supplied package should next be
+// checked for a newer version. It returns zero if a check is due now.
+func updateDue(p v1.Package, now time.Time) time.Duration {
+	uc := p.GetUpdateCheck()
+	if uc == nil {
+		return 0
+	}
+	wait := uc.LastCheckTime.Add(updateInterval(p)).Sub(now)
+	if wait < 0 {
+		return 0
+	}
+	return wait
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	conregv1 "github.com/google/go-containerregistry/pkg/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/internal/xpkg"
+	fakexpkg "github.com/crossplane/crossplane/internal/xpkg/fake"
+)
+
+func TestRegistryUpdaterUpdate(t *testing.T) {
+	errBoom := errors.New("boom")
+	digest := conregv1.Hash{Algorithm: "sha256", Hex: "ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d87fd5f5b11b843"}
+
+	pkg := func(source string, pol v1.UpdatePolicy) v1.Package {
+		return &v1.Provider{Spec: v1.ProviderSpec{PackageSpec: v1.PackageSpec{Package: source, UpdatePolicy: &pol}}}
+	}
+
+	type args struct {
+		f xpkg.Fetcher
+		p v1.Package
+	}
+	type want struct {
+		source string
+		check  v1.PackageUpdateCheck
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"InvalidPolicy": {
+			reason: "We should return an error if the update policy sets both a channel and a version.",
+			args: args{
+				p: pkg("xpkg.upbound.io/cool/provider:v1.0.0", v1.UpdatePolicy{Channel: ptr.To("stable"), Version: ptr.To(">=1.0.0")}),
+			},
+			want: want{
+				err: errors.New(errInvalidUpdateTarget),
+			},
+		},
+		"ErrHeadChannel": {
+			reason: "We should return an error if we can't resolve the channel tag.",
+			args: args{
+				f: &fakexpkg.MockFetcher{MockHead: fakexpkg.NewMockHeadFn(nil, errBoom)},
+				p: pkg("xpkg.upbound.io/cool/provider:v1.0.0", v1.UpdatePolicy{Channel: ptr.To("stable")}),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errHeadChannel),
+			},
+		},
+		"ChannelMoved": {
+			reason: "A package that tracks a channel should be updated to the digest the channel refers to.",
+			args: args{
+				f: &fakexpkg.MockFetcher{MockHead: fakexpkg.NewMockHeadFn(&conregv1.Descriptor{Digest: digest}, nil)},
+				p: pkg("xpkg.upbound.io/cool/provider:v1.0.0", v1.UpdatePolicy{Channel: ptr.To("stable")}),
+			},
+			want: want{
+				source: "xpkg.upbound.io/cool/provider:stable@" + digest.String(),
+				check:  v1.PackageUpdateCheck{LatestVersion: digest.String(), AvailableVersions: []string{digest.String()}},
+			},
+		},
+		"ChannelUnchangedPinned": {
+			reason: "A package that is already pinned to the digest its channel refers to shouldn't be updated.",
+			args: args{
+				f: &fakexpkg.MockFetcher{MockHead: fakexpkg.NewMockHeadFn(&conregv1.Descriptor{Digest: digest}, nil)},
+				p: pkg("xpkg.upbound.io/cool/provider:stable@"+digest.String(), v1.UpdatePolicy{Channel: ptr.To("stable")}),
+			},
+			want: want{
+				source: "xpkg.upbound.io/cool/provider:stable@" + digest.String(),
+				check:  v1.PackageUpdateCheck{LatestVersion: digest.String(), AvailableVersions: []string{digest.String()}},
+			},
+		},
+		"ChannelUnchanged": {
+			reason: "A package that is already at the digest its channel refers to shouldn't be updated.",
+			args: args{
+				f: &fakexpkg.MockFetcher{MockHead: fakexpkg.NewMockHeadFn(&conregv1.Descriptor{Digest: digest}, nil)},
+				p: pkg("xpkg.upbound.io/cool/provider@"+digest.String(), v1.UpdatePolicy{Channel: ptr.To("stable")}),
+			},
+			want: want{
+				source: "xpkg.upbound.io/cool/provider@" + digest.String(),
+				check:  v1.PackageUpdateCheck{LatestVersion: digest.String(), AvailableVersions: []string{digest.String()}},
+			},
+		},
+		"ErrFetchTags": {
+			reason: "We should return an error if we can't fetch the package's tags.",
+			args: args{
+				f: &fakexpkg.MockFetcher{MockTags: fakexpkg.NewMockTagsFn(nil, errBoom)},
+				p: pkg("xpkg.upbound.io/cool/provider:v1.0.0", v1.UpdatePolicy{Version: ptr.To(">=1.0.0, <2.0.0")}),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errFetchTags),
+			},
+		},
+		"VersionUpdated": {
+			reason: "A package that tracks a version constraint should be updated to the greatest tag that satisfies it.",
+			args: args{
+				f: &fakexpkg.MockFetcher{MockTags: fakexpkg.NewMockTagsFn([]string{"latest", "v0.9.0", "v1.0.0", "v1.2.0", "v1.1.0", "v2.0.0"}, nil)},
+				p: pkg("xpkg.upbound.io/cool/provider:v1.0.0", v1.UpdatePolicy{Version: ptr.To(">=1.0.0, <2.0.0")}),
+			},
+			want: want{
+				source: "xpkg.upbound.io/cool/provider:v1.2.0",
+				check:  v1.PackageUpdateCheck{LatestVersion: "v1.2.0", AvailableVersions: []string{"v1.2.0", "v1.1.0", "v1.0.0"}},
+			},
+		},
+		"NoDowngrade": {
+			reason: "A package shouldn't be downgraded to a version that satisfies its constraint.",
+			args: args{
+				f: &fakexpkg.MockFetcher{MockTags: fakexpkg.NewMockTagsFn([]string{"v1.0.0", "v1.1.0", "v2.0.0"}, nil)},
+				p: pkg("xpkg.upbound.io/cool/provider:v2.0.0", v1.UpdatePolicy{Version: ptr.To(">=1.0.0, <2.0.0")}),
+			},
+			want: want{
+				source: "xpkg.upbound.io/cool/provider:v2.0.0",
+				check:  v1.PackageUpdateCheck{LatestVersion: "v1.1.0", AvailableVersions: []string{"v1.1.0", "v1.0.0"}},
+			},
+		},
+		"NoSatisfyingVersion": {
+			reason: "A package shouldn't be updated if no tag satisfies its constraint.",
+			args: args{
+				f: &fakexpkg.MockFetcher{MockTags: fakexpkg.NewMockTagsFn([]string{"v1.0.0"}, nil)},
+				p: pkg("xpkg.upbound.io/cool/provider:v1.0.0", v1.UpdatePolicy{Version: ptr.To(">=2.0.0")}),
+			},
+			want: want{
+				source: "xpkg.upbound.io/cool/provider:v1.0.0",
+				check:  v1.PackageUpdateCheck{AvailableVersions: []string{}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			u := NewRegistryUpdater(tc.args.f, "index.docker.io")
+			source, check, err := u.Update(context.Background(), tc.args.p)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nUpdate(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.source, source); diff != "" {
+				t.Errorf("\n%s\nUpdate(...): -want source, +got source:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.check, check, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nUpdate(...): -want check, +got check:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestUpdateDue(t *testing.T) {
+	now := time.Now()
+
+	pkg := func(interval *metav1.Duration, lastCheck *time.Time) v1.Package {
+		p := &v1.Configuration{Spec: v1.ConfigurationSpec{PackageSpec: v1.PackageSpec{UpdatePolicy: &v1.UpdatePolicy{Interval: interval}}}}
+		if lastCheck != nil {
+			p.SetUpdateCheck(&v1.PackageUpdateCheck{LastCheckTime: metav1.NewTime(*lastCheck)})
+		}
+		return p
+	}
+	recent := now.Add(-10 * time.Minute)
+
+	cases := map[string]struct {
+		reason string
+		p      v1.Package
+		want   time.Duration
+	}{
+		"NeverChecked": {
+			reason: "A check should be due if the package has never been checked.",
+			p:      pkg(nil, nil),
+			want:   0,
+		},
+		"DefaultInterval": {
+			reason: "A check should be due an hour after the last one by default.",
+			p:      pkg(nil, &recent),
+			want:   50 * time.Minute,
+		},
+		"Overdue": {
+			reason: "A check should be due if the interval has passed.",
+			p:      pkg(&metav1.Duration{Duration: 5 * time.Minute}, &recent),
+			want:   0,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := updateDue(tc.p, now)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nupdateDue(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"crypto"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	ociv1 "github.com/google/go-containerregistry/pkg/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+	"github.com/crossplane/crossplane/internal/xpkg"
+)
+
+const (
+	errListImageConfigs     = "cannot list ImageConfigs"
+	errGetPublicKey         = "cannot get public key Secret"
+	errFetchSignatures      = "cannot fetch cosign signatures"
+	errFmtNoPublicKey       = "public key Secret has no %q key"
+	errFmtNoCosignConfig    = "ImageConfig %q doesn't configure cosign verification"
+	errFmtVerifyImageConfig = "cannot verify package image using ImageConfig %q"
+	errFmtParsePublicKey    = "cannot parse public key of authority %q"
+	errParseDigest          = "cannot parse package image digest"
+)
+
+// A Verifier verifies the signature of a package's image.
+type Verifier interface {
+	// Verify the signature of the supplied package's image. If a digest is
+	// supplied the image with that digest is verified. Otherwise the image
+	// the package's source currently resolves to is verified.
+	Verify(ctx context.Context, p v1.Package, digest string) error
+}
+
+// A VerifierFn verifies the signature of a package's image.
+type VerifierFn func(ctx context.Context, p v1.Package, digest string) error
+
+// Verify the signature of the supplied package's image.
+func (fn VerifierFn) Verify(ctx context.Context, p v1.Package, digest string) error {
+	return fn(ctx, p, digest)
+}
+
+// An ImageConfigVerifier verifies the signature of a package's image using
+// the ImageConfig that matches it.
+type ImageConfigVerifier struct {
+	client    client.Reader
+	fetcher   xpkg.Fetcher
+	registry  string
+	namespace string
+}
+
+// NewImageConfigVerifier returns a Verifier that verifies package images using
+// the ImageConfig that matches them. Public keys are read from Secrets in the
+// supplied namespace.
+func NewImageConfigVerifier(c client.Reader, f xpkg.Fetcher, registry, namespace string) *ImageConfigVerifier {
+	return &ImageConfigVerifier{client: c, fetcher: f, registry: registry, namespace: namespace}
+}
+
+// Verify that the supplied package's image has a valid signature from one of
+// the authorities of the ImageConfig that matches it. Images that don't match
+// an ImageConfig that configures verification aren't verified.
+func (v *ImageConfigVerifier) Verify(ctx context.Context, p v1.Package, digest string) error {
+	// Packages sourced from a ConfigMap or a local package have no registry
+	// to fetch signatures from. Their content is controlled by whoever can
+	// write to Crossplane's namespace or local packages directory.
+	if !xpkg.IsRegistrySource(p.GetSource()) {
+		return nil
+	}
+
+	ref, err := name.ParseReference(p.GetSource(), name.WithDefaultRegistry(v.registry))
+	if err != nil {
+		return errors.Wrap(err, errBadReference)
+	}
+
+	l := &v1beta1.ImageConfigList{}
+	if err := v.client.List(ctx, l); err != nil {
+		return errors.Wrap(err, errListImageConfigs)
+	}
+	ic := matchImageConfig(l.Items, ref.Name())
+	if ic == nil || ic.Spec.Verification == nil {
+		return nil
+	}
+
+	if err := v.verify(ctx, p, ref, digest, ic); err != nil {
+		return errors.Wrapf(err, errFmtVerifyImageConfig, ic.GetName())
+	}
+	return nil
+}
+
+func (v *ImageConfigVerifier) verify(ctx context.Context, p v1.Package, ref name.Reference, digest string, ic *v1beta1.ImageConfig) error {
+	if ic.Spec.Verification.Cosign == nil {
+		return errors.Errorf(errFmtNoCosignConfig, ic.GetName())
+	}
+
+	keys := make([]crypto.PublicKey, 0, len(ic.Spec.Verification.Cosign.Authorities))
+	for _, a := range ic.Spec.Verification.Cosign.Authorities {
+		s := &corev1.Secret{}
+		if err := v.client.Get(ctx, types.NamespacedName{Namespace: v.namespace, Name: a.Key.SecretRef.Name}, s); err != nil {
+			return errors.Wrap(err, errGetPublicKey)
+		}
+		data, ok := s.Data[a.Key.SecretRef.Key]
+		if !ok {
+			return errors.Errorf(errFmtNoPublicKey, a.Key.SecretRef.Key)
+		}
+		k, err := xpkg.ParsePublicKey(data)
+		if err != nil {
+			return errors.Wrapf(err, errFmtParsePublicKey, a.Name)
+		}
+		keys = append(keys, k)
+	}
+
+	secrets := v1.RefNames(p.GetPackagePullSecrets())
+	h, err := v.digest(ctx, ref, digest, secrets)
+	if err != nil {
+		return err
+	}
+	sigs, err := v.fetcher.Fetch(ctx, xpkg.CosignSignatureTag(ref, h), secrets...)
+	if err != nil {
+		return errors.Wrap(err, errFetchSignatures)
+	}
+	return xpkg.VerifyCosignSignatures(sigs, h, keys)
+}
+
+// digest returns the digest of the image to verify. We verify the supplied
+// digest if there is one, so that we verify the same image the package
+// revision is created from even if the package's tag has since moved.
+func (v *ImageConfigVerifier) digest(ctx context.Context, ref name.Reference, digest string, secrets []string) (ociv1.Hash, error) {
+	if digest != "" {
+		h, err := ociv1.NewHash(digest)
+		return h, errors.Wrap(err, errParseDigest)
+	}
+	d, err := v.fetcher.Head(ctx, ref, secrets...)
+	if err != nil {
+		return ociv1.Hash{}, errors.Wrap(err, errFetchPackage)
+	}
+	if d == nil {
+		return ociv1.Hash{}, errors.New(errFetchPackage)
+	}
+	return d.Digest, nil
+}
+
+// matchImageConfig returns the ImageConfig with the longest prefix that
+// matches the supplied image, or nil if none match.
+func matchImageConfig(ics []v1beta1.ImageConfig, image string) *v1beta1.ImageConfig {
+	var match *v1beta1.ImageConfig
+	longest := 0
+	for i := range ics {
+		for _, m := range ics[i].Spec.MatchImages {
+			if m.Type != "" && m.Type != v1beta1.Prefix {
+				continue
+			}
+			if strings.HasPrefix(image, m.Prefix) && len(m.Prefix) > longest {
+				match, longest = &ics[i], len(m.Prefix)
+			}
+		}
+	}
+	return match
+}
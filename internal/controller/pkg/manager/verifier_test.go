@@ -0,0 +1,155 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-containerregistry/pkg/name"
+	ociv1 "github.com/google/go-containerregistry/pkg/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+	"github.com/crossplane/crossplane/internal/xpkg"
+	"github.com/crossplane/crossplane/internal/xpkg/fake"
+)
+
+func TestMatchImageConfig(t *testing.T) {
+	ic := func(name string, prefixes ...string) v1beta1.ImageConfig {
+		c := v1beta1.ImageConfig{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		for _, p := range prefixes {
+			c.Spec.MatchImages = append(c.Spec.MatchImages, v1beta1.ImageMatch{Type: v1beta1.Prefix, Prefix: p})
+		}
+		return c
+	}
+	ics := []v1beta1.ImageConfig{
+		ic("registry", "xpkg.upbound.io/"),
+		ic("org", "ghcr.io/cool/", "xpkg.upbound.io/crossplane-contrib/"),
+	}
+
+	cases := map[string]struct {
+		reason string
+		image  string
+		want   string
+	}{
+		"LongestPrefix": {
+			reason: "We should return the ImageConfig with the longest matching prefix.",
+			image:  "xpkg.upbound.io/crossplane-contrib/provider-nop:v0.2.1",
+			want:   "org",
+		},
+		"ShorterPrefix": {
+			reason: "We should return a less specific ImageConfig if it's the only match.",
+			image:  "xpkg.upbound.io/upbound/provider-aws:v1.0.0",
+			want:   "registry",
+		},
+		"NoMatch": {
+			reason: "We should return nothing if no ImageConfig matches.",
+			image:  "index.docker.io/cool/provider:v1.0.0",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ""
+			if m := matchImageConfig(ics, tc.image); m != nil {
+				got = m.GetName()
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nmatchImageConfig(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestImageConfigVerifierDigest(t *testing.T) {
+	errBoom := errors.New("boom")
+	verified := ociv1.Hash{Algorithm: "sha256", Hex: "c88b938d6e7b2ed43d40b71e5a55df9c60fa653bea0c0961f3294fac46d5b56e"}
+	moved := ociv1.Hash{Algorithm: "sha256", Hex: "0000000000000000000000000000000000000000000000000000000000000000"}
+
+	type args struct {
+		f      xpkg.Fetcher
+		digest string
+	}
+	type want struct {
+		h   ociv1.Hash
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"SuppliedDigest": {
+			reason: "We should verify the supplied digest, even if the package's tag now resolves to another digest.",
+			args: args{
+				f:      &fake.MockFetcher{MockHead: fake.NewMockHeadFn(&ociv1.Descriptor{Digest: moved}, nil)},
+				digest: verified.String(),
+			},
+			want: want{
+				h: verified,
+			},
+		},
+		"InvalidDigest": {
+			reason: "We should return an error if the supplied digest is invalid.",
+			args: args{
+				digest: "sha256:nope",
+			},
+			want: want{
+				err: errors.Wrap(errors.New("found non-hex character in hash: n"), errParseDigest),
+			},
+		},
+		"NoDigest": {
+			reason: "We should verify the digest the package's tag resolves to if no digest is supplied.",
+			args: args{
+				f: &fake.MockFetcher{MockHead: fake.NewMockHeadFn(&ociv1.Descriptor{Digest: moved}, nil)},
+			},
+			want: want{
+				h: moved,
+			},
+		},
+		"HeadError": {
+			reason: "We should return an error if we can't resolve the package's tag.",
+			args: args{
+				f: &fake.MockFetcher{MockHead: fake.NewMockHeadFn(nil, errBoom)},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errFetchPackage),
+			},
+		},
+	}
+
+	ref := name.MustParseReference("xpkg.upbound.io/cool/provider:v1.0.0")
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := NewImageConfigVerifier(nil, tc.args.f, "", "")
+			h, err := v.digest(context.Background(), ref, tc.args.digest, nil)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ndigest(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.h, h); diff != "" {
+				t.Errorf("\n%s\ndigest(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
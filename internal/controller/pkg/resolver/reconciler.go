@@ -22,10 +22,13 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/Masterminds/semver"
 	"github.com/google/go-containerregistry/pkg/name"
+	"golang.org/x/sync/errgroup"
+	"k8s.io/apimachinery/pkg/api/equality"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/client-go/kubernetes"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -34,6 +37,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
@@ -42,6 +46,7 @@ import (
 	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
 	"github.com/crossplane/crossplane/internal/controller/pkg/controller"
 	"github.com/crossplane/crossplane/internal/dag"
+	"github.com/crossplane/crossplane/internal/features"
 	"github.com/crossplane/crossplane/internal/xpkg"
 )
 
@@ -67,6 +72,9 @@ const (
 	errFmtNoValidVersion    = "dependency (%s) does not have version in constraints (%s)"
 	errInvalidPackageType   = "cannot create invalid package dependency type"
 	errCreateDependency     = "cannot create dependency package"
+	errUpdateLockStatus     = "cannot update package lock status"
+
+	reasonDependencyConflict event.Reason = "DependencyConflict"
 )
 
 // ReconcilerOption is used to configure the Reconciler.
@@ -107,14 +115,42 @@ func WithDefaultRegistry(registry string) ReconcilerOption {
 	}
 }
 
+// WithRecorder specifies how the Reconciler should record events.
+func WithRecorder(er event.Recorder) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.record = er
+	}
+}
+
+// WithDependencyUpgrades specifies that the Reconciler should upgrade an
+// installed dependency to the greatest version that satisfies the constraints
+// of all the packages that depend on it, if its current version doesn't.
+func WithDependencyUpgrades() ReconcilerOption {
+	return func(r *Reconciler) {
+		r.upgrade = true
+	}
+}
+
+// WithMaxConcurrentInstalls specifies the maximum number of missing
+// dependencies the Reconciler should install concurrently.
+func WithMaxConcurrentInstalls(n int) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.maxInstalls = n
+	}
+}
+
 // Reconciler reconciles packages.
 type Reconciler struct {
 	client   client.Client
 	log      logging.Logger
+	record   event.Recorder
 	lock     resource.Finalizer
 	newDag   dag.NewDAGFn
 	fetcher  xpkg.Fetcher
 	registry string
+	upgrade  bool
+
+	maxInstalls int
 }
 
 // Setup adds a controller that reconciles the Lock.
@@ -130,11 +166,19 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		return errors.Wrap(err, "cannot build fetcher")
 	}
 
-	r := NewReconciler(mgr,
+	opts := []ReconcilerOption{
 		WithLogger(o.Logger.WithValues("controller", name)),
+		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
 		WithFetcher(f),
 		WithDefaultRegistry(o.DefaultRegistry),
-	)
+	}
+	if o.MaxConcurrentPackageInstalls > 0 {
+		opts = append(opts, WithMaxConcurrentInstalls(o.MaxConcurrentPackageInstalls))
+	}
+	if o.Features.Enabled(features.EnableAlphaDependencyUpgrades) {
+		opts = append(opts, WithDependencyUpgrades())
+	}
+	r := NewReconciler(mgr, opts...)
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
@@ -151,8 +195,11 @@ func NewReconciler(mgr manager.Manager, opts ...ReconcilerOption) *Reconciler {
 		client:  mgr.GetClient(),
 		lock:    resource.NewAPIFinalizer(mgr.GetClient(), finalizer),
 		log:     logging.NewNopLogger(),
+		record:  event.NewNopRecorder(),
 		newDag:  dag.NewMapDag,
 		fetcher: xpkg.NewNopFetcher(),
+
+		maxInstalls: 1,
 	}
 
 	for _, f := range opts {
@@ -222,28 +269,109 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return reconcile.Result{}, errors.Wrap(err, errSortDAG)
 	}
 
+	conflicts := installedConflicts(lock)
+
 	if len(implied) == 0 {
+		if err := r.updateStatus(ctx, lock, conflicts); err != nil {
+			log.Debug(errUpdateLockStatus, "error", err)
+			return reconcile.Result{}, err
+		}
+
+		// All dependencies are installed. Upgrade any whose versions
+		// don't satisfy the packages that depend on them, if we can.
+		if r.upgrade {
+			return reconcile.Result{Requeue: false}, r.upgradeDependency(ctx, log, lock)
+		}
 		return reconcile.Result{Requeue: false}, nil
 	}
 
-	// If we are missing a node, we want to create it. The resolver never
-	// modifies the Lock. We only create the first implied node as we will
-	// be requeued when it adds itself to the Lock, at which point we will
-	// check for missing nodes again.
-	dep, ok := implied[0].(*v1beta1.Dependency)
+	// If we are missing nodes, we want to create them. The resolver never
+	// modifies the Lock. Missing nodes are independent of each other, so we
+	// create them concurrently. We will be requeued when they add themselves
+	// to the Lock, at which point we will check for missing nodes again.
+	mu := &sync.Mutex{}
+	g := &errgroup.Group{}
+	g.SetLimit(r.maxInstalls)
+	for _, n := range implied {
+		n := n // Pin the loop variable.
+		g.Go(func() error {
+			c, err := r.installDependency(ctx, log, lock, n)
+			if c != nil {
+				mu.Lock()
+				conflicts = append(conflicts, *c)
+				mu.Unlock()
+			}
+			return err
+		})
+	}
+	err = g.Wait()
+
+	sort.Slice(conflicts, func(i, j int) bool { return conflicts[i].Package < conflicts[j].Package })
+	if err := r.updateStatus(ctx, lock, conflicts); err != nil {
+		log.Debug(errUpdateLockStatus, "error", err)
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{Requeue: false}, err
+}
+
+// updateStatus records the resolved dependency graph and the supplied
+// dependency conflicts in the status of the supplied Lock, and emits an event
+// for each new conflict.
+func (r *Reconciler) updateStatus(ctx context.Context, lock *v1beta1.Lock, conflicts []v1.DependencyConflict) error {
+	graph := lock.Graph()
+	if equality.Semantic.DeepEqual(lock.Status.DependencyConflicts, conflicts) && equality.Semantic.DeepEqual(lock.Status.Graph, graph) {
+		return nil
+	}
+
+	existing := map[string]bool{}
+	for _, c := range lock.Status.DependencyConflicts {
+		existing[c.String()] = true
+	}
+	for _, c := range conflicts {
+		if !existing[c.String()] {
+			r.record.Event(lock, event.Warning(reasonDependencyConflict, errors.New(c.String())))
+		}
+	}
+
+	lock.Status.DependencyConflicts = conflicts
+	lock.Status.Graph = graph
+	return errors.Wrap(r.client.Status().Update(ctx, lock), errUpdateLockStatus)
+}
+
+// installedConflicts returns the installed packages in the supplied Lock whose
+// versions don't satisfy the constraints of every package that depends on
+// them.
+func installedConflicts(lock *v1beta1.Lock) []v1.DependencyConflict {
+	out := []v1.DependencyConflict{}
+	for _, u := range unsatisfiedDependencies(lock) {
+		out = append(out, v1.DependencyConflict{
+			Package:     u.pkg.Identifier(),
+			Version:     u.pkg.Version,
+			Constraints: lock.Constraints(u.pkg.Identifier()),
+		})
+	}
+	return out
+}
+
+// installDependency creates a package for the supplied missing dependency, at
+// the greatest version that satisfies its constraints. Dependencies that are
+// invalid are skipped. Dependencies that have no satisfying version are
+// skipped, and returned as a conflict.
+func (r *Reconciler) installDependency(ctx context.Context, log logging.Logger, lock *v1beta1.Lock, n dag.Node) (*v1.DependencyConflict, error) {
+	dep, ok := n.(*v1beta1.Dependency)
 	if !ok {
-		log.Debug(errInvalidDependency, "error", errors.Errorf(errFmtMissingDependency, dep.Identifier()))
-		return reconcile.Result{Requeue: false}, nil
+		log.Debug(errInvalidDependency, "error", errors.Errorf(errFmtMissingDependency, n.Identifier()))
+		return nil, nil
 	}
 	c, err := semver.NewConstraint(dep.Constraints)
 	if err != nil {
 		log.Debug(errInvalidConstraint, "error", err)
-		return reconcile.Result{Requeue: false}, nil
+		return nil, nil
 	}
 	ref, err := name.ParseReference(dep.Package, name.WithDefaultRegistry(r.registry))
 	if err != nil {
 		log.Debug(errInvalidDependency, "error", err)
-		return reconcile.Result{Requeue: false}, nil
+		return nil, nil
 	}
 
 	// NOTE(hasheddan): we will be unable to fetch tags for private
@@ -252,7 +380,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	tags, err := r.fetcher.Tags(ctx, ref)
 	if err != nil {
 		log.Debug(errFetchTags, "error", err)
-		return reconcile.Result{}, errors.Wrap(err, errFetchTags)
+		return nil, errors.Wrap(err, errFetchTags)
 	}
 
 	vs := []*semver.Version{}
@@ -273,11 +401,9 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		}
 	}
 
-	// NOTE(hasheddan): consider creating event on package revision
-	// dictating constraints.
 	if addVer == "" {
 		log.Debug(errNoValidVersion, "error", errors.Errorf(errFmtNoValidVersion, dep.Identifier(), dep.Constraints))
-		return reconcile.Result{Requeue: false}, nil
+		return &v1.DependencyConflict{Package: dep.Identifier(), Constraints: lock.Constraints(dep.Identifier())}, nil
 	}
 
 	var pack v1.Package
@@ -290,7 +416,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		pack = &v1beta1.Function{}
 	default:
 		log.Debug(errInvalidPackageType)
-		return reconcile.Result{Requeue: false}, nil
+		return nil, nil
 	}
 
 	// NOTE(hasheddan): packages are currently created with default
@@ -301,11 +427,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	pack.SetSource(fmt.Sprintf(packageTagFmt, ref.String(), addVer))
 
 	// NOTE(hasheddan): consider making the lock the controller of packages
-	// it creates.
-	if err := r.client.Create(ctx, pack); err != nil {
+	// it creates. The package may already exist if we created it but it
+	// hasn't added itself to the Lock yet.
+	if err := r.client.Create(ctx, pack); resource.Ignore(kerrors.IsAlreadyExists, err) != nil {
 		log.Debug(errCreateDependency, "error", err)
-		return reconcile.Result{}, errors.Wrap(err, errCreateDependency)
+		return nil, errors.Wrap(err, errCreateDependency)
 	}
 
-	return reconcile.Result{Requeue: false}, nil
+	return nil, nil
 }
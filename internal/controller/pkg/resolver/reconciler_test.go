@@ -32,10 +32,12 @@ import (
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
 	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
 	"github.com/crossplane/crossplane/internal/dag"
 	fakedag "github.com/crossplane/crossplane/internal/dag/fake"
@@ -233,7 +235,95 @@ func TestReconcile(t *testing.T) {
 							})
 							return nil
 						}),
+						MockUpdate:       test.NewMockUpdateFn(nil),
+						MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+					},
+				},
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: []ReconcilerOption{
+					WithNewDagFn(func() dag.DAG {
+						return &fakedag.MockDag{
+							MockInit: func(nodes []dag.Node) ([]dag.Node, error) {
+								return nil, nil
+							},
+							MockSort: func() ([]string, error) {
+								return nil, nil
+							},
+						}
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"SuccessfulUpdateGraph": {
+			reason: "We should record the resolved dependency graph in the Lock's status.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+							l := o.(*v1beta1.Lock)
+							l.Packages = []v1beta1.LockPackage{
+								{
+									Name:    "cool-config",
+									Type:    v1beta1.ConfigurationPackageType,
+									Source:  "cool-repo/cool-config",
+									Version: "v1.0.0",
+									Dependencies: []v1beta1.Dependency{
+										{
+											Package:     "cool-repo/cool-provider",
+											Type:        v1beta1.ProviderPackageType,
+											Constraints: ">=v0.1.0",
+										},
+										{
+											Package:     "cool-repo/cool-function",
+											Type:        v1beta1.FunctionPackageType,
+											Constraints: ">=v0.2.0",
+										},
+									},
+								},
+								{
+									Name:    "cool-provider",
+									Type:    v1beta1.ProviderPackageType,
+									Source:  "cool-repo/cool-provider",
+									Version: "v0.1.0",
+								},
+							}
+							return nil
+						}),
 						MockUpdate: test.NewMockUpdateFn(nil),
+						MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+							want := []v1beta1.ResolvedPackage{
+								{
+									Source:  "cool-repo/cool-config",
+									Type:    v1beta1.ConfigurationPackageType,
+									Version: "v1.0.0",
+									Dependencies: []v1beta1.ResolvedDependency{
+										{
+											Package:     "cool-repo/cool-provider",
+											Type:        v1beta1.ProviderPackageType,
+											Constraints: ">=v0.1.0",
+											Version:     "v0.1.0",
+										},
+										{
+											Package:     "cool-repo/cool-function",
+											Type:        v1beta1.FunctionPackageType,
+											Constraints: ">=v0.2.0",
+										},
+									},
+								},
+								{
+									Source:  "cool-repo/cool-provider",
+									Type:    v1beta1.ProviderPackageType,
+									Version: "v0.1.0",
+								},
+							}
+							if diff := cmp.Diff(want, o.(*v1beta1.Lock).Status.Graph); diff != "" {
+								t.Errorf("StatusUpdate(...): -want graph, +got graph:\n%s", diff)
+							}
+							return nil
+						}),
 					},
 				},
 				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
@@ -272,7 +362,8 @@ func TestReconcile(t *testing.T) {
 							})
 							return nil
 						}),
-						MockUpdate: test.NewMockUpdateFn(nil),
+						MockUpdate:       test.NewMockUpdateFn(nil),
+						MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
 					},
 				},
 				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
@@ -315,7 +406,8 @@ func TestReconcile(t *testing.T) {
 							})
 							return nil
 						}),
-						MockUpdate: test.NewMockUpdateFn(nil),
+						MockUpdate:       test.NewMockUpdateFn(nil),
+						MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
 					},
 				},
 				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
@@ -345,7 +437,7 @@ func TestReconcile(t *testing.T) {
 			},
 		},
 		"ErrorNoValidVersion": {
-			reason: "We should not requeue if valid version does not exist for dependency.",
+			reason: "We should not requeue, but should record a conflict, if valid version does not exist for dependency.",
 			args: args{
 				mgr: &fake.Manager{
 					Client: &test.MockClient{
@@ -359,10 +451,25 @@ func TestReconcile(t *testing.T) {
 								Type:    v1beta1.ProviderPackageType,
 								Source:  "cool-repo/cool-image",
 								Version: "v0.0.1",
+								Dependencies: []v1beta1.Dependency{{
+									Package:     "hasheddan/config-nop-b",
+									Type:        v1beta1.ConfigurationPackageType,
+									Constraints: ">v1.0.0",
+								}},
 							})
 							return nil
 						}),
 						MockUpdate: test.NewMockUpdateFn(nil),
+						MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+							want := []v1.DependencyConflict{{
+								Package:     "hasheddan/config-nop-b",
+								Constraints: []v1.DependencyConstraint{{Package: "cool-repo/cool-image", Constraints: ">v1.0.0"}},
+							}}
+							if diff := cmp.Diff(want, o.(*v1beta1.Lock).Status.DependencyConflicts); diff != "" {
+								t.Errorf("StatusUpdate(...): -want conflicts, +got conflicts:\n%s", diff)
+							}
+							return nil
+						}),
 					},
 				},
 				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
@@ -409,8 +516,9 @@ func TestReconcile(t *testing.T) {
 							})
 							return nil
 						}),
-						MockCreate: test.NewMockCreateFn(errBoom),
-						MockUpdate: test.NewMockUpdateFn(nil),
+						MockCreate:       test.NewMockCreateFn(errBoom),
+						MockUpdate:       test.NewMockUpdateFn(nil),
+						MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
 					},
 				},
 				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
@@ -458,8 +566,67 @@ func TestReconcile(t *testing.T) {
 							})
 							return nil
 						}),
-						MockCreate: test.NewMockCreateFn(nil),
-						MockUpdate: test.NewMockUpdateFn(nil),
+						MockCreate:       test.NewMockCreateFn(nil),
+						MockUpdate:       test.NewMockUpdateFn(nil),
+						MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+					},
+				},
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: []ReconcilerOption{
+					WithNewDagFn(func() dag.DAG {
+						return &fakedag.MockDag{
+							MockInit: func(nodes []dag.Node) ([]dag.Node, error) {
+								return []dag.Node{
+									&v1beta1.Dependency{
+										Package:     "hasheddan/config-nop-c",
+										Constraints: ">v1.0.0",
+										Type:        v1beta1.ConfigurationPackageType,
+									},
+								}, nil
+							},
+							MockSort: func() ([]string, error) {
+								return nil, nil
+							},
+						}
+					}),
+					WithFetcher(&fakexpkg.MockFetcher{
+						MockTags: fakexpkg.NewMockTagsFn([]string{"v0.2.0", "v0.3.0", "v1.0.0", "v1.2.0"}, nil),
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"ErrorCreateOneOfMissingDependencies": {
+			reason: "We should install every missing dependency, and return an error if we can't create any of them.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+							// Populate package list so we attempt
+							// reconciliation. This is overridden by the mock
+							// DAG.
+							l := o.(*v1beta1.Lock)
+							l.Packages = append(l.Packages, v1beta1.LockPackage{
+								Name:    "cool-package",
+								Type:    v1beta1.ProviderPackageType,
+								Source:  "cool-repo/cool-image",
+								Version: "v0.0.1",
+							})
+							return nil
+						}),
+						MockCreate: func(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+							// Only the last missing dependency fails, so
+							// we'd return no error if we only installed
+							// the first.
+							if obj.GetName() == "hasheddan-config-nop-c" {
+								return errBoom
+							}
+							return nil
+						},
+						MockUpdate:       test.NewMockUpdateFn(nil),
+						MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
 					},
 				},
 				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
@@ -468,6 +635,16 @@ func TestReconcile(t *testing.T) {
 						return &fakedag.MockDag{
 							MockInit: func(nodes []dag.Node) ([]dag.Node, error) {
 								return []dag.Node{
+									&v1beta1.Dependency{
+										Package:     "hasheddan/config-nop-a",
+										Constraints: ">v1.0.0",
+										Type:        v1beta1.ConfigurationPackageType,
+									},
+									&v1beta1.Dependency{
+										Package:     "hasheddan/provider-nop-b",
+										Constraints: ">v1.0.0",
+										Type:        v1beta1.ProviderPackageType,
+									},
 									&v1beta1.Dependency{
 										Package:     "hasheddan/config-nop-c",
 										Constraints: ">v1.0.0",
@@ -483,12 +660,225 @@ func TestReconcile(t *testing.T) {
 					WithFetcher(&fakexpkg.MockFetcher{
 						MockTags: fakexpkg.NewMockTagsFn([]string{"v0.2.0", "v0.3.0", "v1.0.0", "v1.2.0"}, nil),
 					}),
+					WithMaxConcurrentInstalls(2),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errCreateDependency),
+			},
+		},
+		"SuccessfulUpgradeDependency": {
+			reason: "We should upgrade an installed dependency that doesn't satisfy its dependents' constraints if upgrades are enabled.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+							switch o := o.(type) {
+							case *v1beta1.Lock:
+								o.Packages = []v1beta1.LockPackage{
+									{
+										Name:    "cool-config-1234",
+										Type:    v1beta1.ConfigurationPackageType,
+										Source:  "cool-repo/cool-config",
+										Version: "v1.0.0",
+										Dependencies: []v1beta1.Dependency{{
+											Package:     "cool-repo/cool-provider",
+											Type:        v1beta1.ProviderPackageType,
+											Constraints: ">=v1.1.0",
+										}},
+									},
+									{
+										Name:    "cool-provider-5678",
+										Type:    v1beta1.ProviderPackageType,
+										Source:  "cool-repo/cool-provider",
+										Version: "v1.0.0",
+									},
+								}
+							case *v1.ProviderRevision:
+								o.SetLabels(map[string]string{v1.LabelParentPackage: "cool-provider"})
+							case *v1.Provider:
+								o.SetName("cool-provider")
+								o.SetSource("cool-repo/cool-provider:v1.0.0")
+							}
+							return nil
+						}),
+						MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+						MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+							want := "cool-repo/cool-provider:v1.2.0"
+							if got := o.(*v1.Provider).GetSource(); got != want {
+								t.Errorf("Update(...): want source %q, got %q", want, got)
+							}
+							return nil
+						}),
+					},
+				},
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: []ReconcilerOption{
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+						return nil
+					}}),
+					WithDependencyUpgrades(),
+					WithFetcher(&fakexpkg.MockFetcher{
+						MockTags: fakexpkg.NewMockTagsFn([]string{"v0.9.0", "v1.0.0", "v1.2.0", "v2.0.0-rc.1"}, nil),
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"SkipUpgradePausedDependency": {
+			reason: "We shouldn't upgrade an installed dependency whose package is paused.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+							switch o := o.(type) {
+							case *v1beta1.Lock:
+								o.Packages = []v1beta1.LockPackage{
+									{
+										Name:    "cool-config-1234",
+										Type:    v1beta1.ConfigurationPackageType,
+										Source:  "cool-repo/cool-config",
+										Version: "v1.0.0",
+										Dependencies: []v1beta1.Dependency{{
+											Package:     "cool-repo/cool-provider",
+											Type:        v1beta1.ProviderPackageType,
+											Constraints: ">=v1.1.0",
+										}},
+									},
+									{
+										Name:    "cool-provider-5678",
+										Type:    v1beta1.ProviderPackageType,
+										Source:  "cool-repo/cool-provider",
+										Version: "v1.0.0",
+									},
+								}
+							case *v1.ProviderRevision:
+								o.SetLabels(map[string]string{v1.LabelParentPackage: "cool-provider"})
+							case *v1.Provider:
+								o.SetName("cool-provider")
+								o.SetSource("cool-repo/cool-provider:v1.0.0")
+								o.SetAnnotations(map[string]string{meta.AnnotationKeyReconciliationPaused: "true"})
+							}
+							return nil
+						}),
+						MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+						MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+							if _, ok := o.(*v1.Provider); ok {
+								t.Errorf("Update(...): unexpected update of paused dependency package")
+							}
+							return nil
+						}),
+					},
+				},
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: []ReconcilerOption{
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+						return nil
+					}}),
+					WithDependencyUpgrades(),
+					WithFetcher(&fakexpkg.MockFetcher{
+						MockTags: fakexpkg.NewMockTagsFn([]string{"v0.9.0", "v1.0.0", "v1.2.0", "v2.0.0-rc.1"}, nil),
+					}),
 				},
 			},
 			want: want{
 				r: reconcile.Result{Requeue: false},
 			},
 		},
+		"ErrUpdateLockStatus": {
+			reason: "We should return an error if we can't record dependency conflicts in the Lock's status.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+							o.(*v1beta1.Lock).Packages = []v1beta1.LockPackage{
+								{
+									Name:    "cool-config-1234",
+									Type:    v1beta1.ConfigurationPackageType,
+									Source:  "cool-repo/cool-config",
+									Version: "v1.0.0",
+									Dependencies: []v1beta1.Dependency{{
+										Package:     "cool-repo/cool-provider",
+										Type:        v1beta1.ProviderPackageType,
+										Constraints: ">=v1.1.0",
+									}},
+								},
+								{
+									Name:    "cool-provider-5678",
+									Type:    v1beta1.ProviderPackageType,
+									Source:  "cool-repo/cool-provider",
+									Version: "v1.0.0",
+								},
+							}
+							return nil
+						}),
+						MockStatusUpdate: test.NewMockSubResourceUpdateFn(errBoom),
+					},
+				},
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: []ReconcilerOption{
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+						return nil
+					}}),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errUpdateLockStatus),
+			},
+		},
+		"ErrUpgradeDependency": {
+			reason: "We should return an error if we can't update a dependency we're upgrading.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+							switch o := o.(type) {
+							case *v1beta1.Lock:
+								o.Packages = []v1beta1.LockPackage{
+									{
+										Name:    "cool-config-1234",
+										Type:    v1beta1.ConfigurationPackageType,
+										Source:  "cool-repo/cool-config",
+										Version: "v1.0.0",
+										Dependencies: []v1beta1.Dependency{{
+											Package:     "cool-repo/cool-provider",
+											Type:        v1beta1.ProviderPackageType,
+											Constraints: ">=v1.1.0",
+										}},
+									},
+									{
+										Name:    "cool-provider-5678",
+										Type:    v1beta1.ProviderPackageType,
+										Source:  "cool-repo/cool-provider",
+										Version: "v1.0.0",
+									},
+								}
+							case *v1.ProviderRevision:
+								o.SetLabels(map[string]string{v1.LabelParentPackage: "cool-provider"})
+							}
+							return nil
+						}),
+						MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+						MockUpdate:       test.NewMockUpdateFn(errBoom),
+					},
+				},
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: []ReconcilerOption{
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+						return nil
+					}}),
+					WithDependencyUpgrades(),
+					WithFetcher(&fakexpkg.MockFetcher{
+						MockTags: fakexpkg.NewMockTagsFn([]string{"v1.0.0", "v1.2.0"}, nil),
+					}),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errUpgradeDependency),
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -0,0 +1,205 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	"github.com/google/go-containerregistry/pkg/name"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+)
+
+const (
+	errGetRevision         = "cannot get dependency package revision"
+	errGetPackage          = "cannot get dependency package"
+	errUpgradeDependency   = "cannot upgrade dependency package"
+	errFmtNoParentPackage  = "dependency package revision %q has no %s label"
+	errFmtNoUpgradeVersion = "no version of dependency (%s) satisfies all constraints (%s)"
+
+	reasonUpgradeDependency event.Reason = "UpgradeDependency"
+)
+
+// An unsatisfied dependency is an installed package whose version doesn't
+// satisfy the constraints of the packages that depend on it.
+type unsatisfied struct {
+	pkg         v1beta1.LockPackage
+	constraints []string
+}
+
+// unsatisfiedDependencies returns the installed packages in the supplied Lock
+// whose versions don't satisfy the constraints of all the packages that depend
+// on them, sorted by source. Packages whose versions aren't semantic versions
+// (for example digests) are never unsatisfied.
+func unsatisfiedDependencies(lock *v1beta1.Lock) []unsatisfied {
+	constraints := map[string][]string{}
+	for _, lp := range lock.Packages {
+		for _, dep := range lp.Dependencies {
+			constraints[dep.Identifier()] = append(constraints[dep.Identifier()], dep.Constraints)
+		}
+	}
+
+	out := []unsatisfied{}
+	for _, lp := range lock.Packages {
+		cs, ok := constraints[lp.Identifier()]
+		if !ok {
+			continue
+		}
+		v, err := semver.NewVersion(lp.Version)
+		if err != nil {
+			continue
+		}
+		if !satisfies(v, cs) {
+			out = append(out, unsatisfied{pkg: lp, constraints: cs})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].pkg.Source < out[j].pkg.Source })
+	return out
+}
+
+// upgradeVersion returns the greatest of the supplied tags that is a newer
+// version than current and that satisfies all of the supplied constraints. It
+// returns an empty string if there is no such tag.
+func upgradeVersion(current string, constraints []string, tags []string) string {
+	cur, err := semver.NewVersion(current)
+	if err != nil {
+		return ""
+	}
+
+	vs := []*semver.Version{}
+	for _, t := range tags {
+		v, err := semver.NewVersion(t)
+		if err != nil {
+			// We skip any tags that are not valid semantic versions.
+			continue
+		}
+		vs = append(vs, v)
+	}
+	sort.Sort(sort.Reverse(semver.Collection(vs)))
+
+	for _, v := range vs {
+		if !v.GreaterThan(cur) {
+			return ""
+		}
+		if satisfies(v, constraints) {
+			return v.Original()
+		}
+	}
+	return ""
+}
+
+// satisfies returns true if the supplied version satisfies all of the supplied
+// constraints. Invalid constraints are never satisfied.
+func satisfies(v *semver.Version, constraints []string) bool {
+	for _, s := range constraints {
+		c, err := semver.NewConstraint(s)
+		if err != nil || !c.Check(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// upgradeDependency upgrades the first installed dependency in the supplied
+// Lock that doesn't satisfy the constraints of the packages that depend on it
+// to the greatest version that does. Dependencies are upgraded by updating
+// the source of the package that owns their revision. Dependencies that have
+// no newer satisfying version, or whose package is paused, are left as they
+// are.
+func (r *Reconciler) upgradeDependency(ctx context.Context, log logging.Logger, lock *v1beta1.Lock) error {
+	for _, u := range unsatisfiedDependencies(lock) {
+		log := log.WithValues("dependency", u.pkg.Identifier(), "version", u.pkg.Version)
+
+		ref, err := name.ParseReference(u.pkg.Source, name.WithDefaultRegistry(r.registry))
+		if err != nil {
+			log.Debug(errInvalidDependency, "error", err)
+			continue
+		}
+
+		var rev v1.PackageRevision
+		var pkg v1.Package
+		switch u.pkg.Type {
+		case v1beta1.ConfigurationPackageType:
+			rev, pkg = &v1.ConfigurationRevision{}, &v1.Configuration{}
+		case v1beta1.ProviderPackageType:
+			rev, pkg = &v1.ProviderRevision{}, &v1.Provider{}
+		case v1beta1.FunctionPackageType:
+			rev, pkg = &v1beta1.FunctionRevision{}, &v1beta1.Function{}
+		default:
+			log.Debug(errInvalidPackageType)
+			continue
+		}
+
+		if err := r.client.Get(ctx, types.NamespacedName{Name: u.pkg.Name}, rev); err != nil {
+			return errors.Wrap(err, errGetRevision)
+		}
+		parent := rev.GetLabels()[v1.LabelParentPackage]
+		if parent == "" {
+			log.Debug(errUpgradeDependency, "error", errors.Errorf(errFmtNoParentPackage, rev.GetName(), v1.LabelParentPackage))
+			continue
+		}
+		if err := r.client.Get(ctx, types.NamespacedName{Name: parent}, pkg); err != nil {
+			return errors.Wrap(err, errGetPackage)
+		}
+
+		// Upgrading a paused package would roll it to a new revision as
+		// soon as it's unpaused.
+		if meta.IsPaused(pkg) {
+			log.Debug("Not upgrading paused dependency")
+			continue
+		}
+
+		secrets := make([]string, 0, len(pkg.GetPackagePullSecrets()))
+		for _, s := range pkg.GetPackagePullSecrets() {
+			secrets = append(secrets, s.Name)
+		}
+		tags, err := r.fetcher.Tags(ctx, ref, secrets...)
+		if err != nil {
+			return errors.Wrap(err, errFetchTags)
+		}
+
+		to := upgradeVersion(u.pkg.Version, u.constraints, tags)
+		if to == "" {
+			log.Debug(errNoValidVersion, "error", errors.Errorf(errFmtNoUpgradeVersion, u.pkg.Identifier(), strings.Join(u.constraints, ", ")))
+			continue
+		}
+
+		pkg.SetSource(fmt.Sprintf(packageTagFmt, ref.Context().String(), to))
+		if err := r.client.Update(ctx, pkg); err != nil {
+			return errors.Wrap(err, errUpgradeDependency)
+		}
+
+		log.Debug("Upgraded dependency", "to", to)
+		r.record.Event(pkg, event.Normal(reasonUpgradeDependency, fmt.Sprintf("Upgraded from %s to %s to satisfy the constraints of the packages that depend on it (%s)", u.pkg.Version, to, strings.Join(u.constraints, ", "))))
+
+		// We upgrade one dependency at a time. We'll be requeued when the
+		// upgraded package updates the Lock.
+		return nil
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resolver
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestUpgradeVersion(t *testing.T) {
+	type args struct {
+		current     string
+		constraints []string
+		tags        []string
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   string
+	}{
+		"GreatestSatisfying": {
+			reason: "We should return the greatest version that satisfies all constraints.",
+			args: args{
+				current:     "v1.0.0",
+				constraints: []string{">=v1.1.0", "<v2.0.0"},
+				tags:        []string{"v1.0.0", "v1.1.0", "v1.3.0", "v2.0.0", "latest"},
+			},
+			want: "v1.3.0",
+		},
+		"NoneSatisfying": {
+			reason: "We should return nothing if no version satisfies all constraints.",
+			args: args{
+				current:     "v1.0.0",
+				constraints: []string{">=v1.1.0", "<v1.0.0"},
+				tags:        []string{"v0.9.0", "v1.1.0"},
+			},
+			want: "",
+		},
+		"NoDowngrade": {
+			reason: "We should never return a version older than the current version.",
+			args: args{
+				current:     "v1.5.0",
+				constraints: []string{"<v1.5.0"},
+				tags:        []string{"v1.0.0", "v1.4.0", "v1.5.0"},
+			},
+			want: "",
+		},
+		"InvalidConstraint": {
+			reason: "We should return nothing if a constraint is invalid.",
+			args: args{
+				current:     "v1.0.0",
+				constraints: []string{"cool"},
+				tags:        []string{"v1.1.0"},
+			},
+			want: "",
+		},
+		"CurrentNotSemver": {
+			reason: "We should return nothing if the current version isn't a semantic version.",
+			args: args{
+				current:     "sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d2ddd5bd4e0ed1d",
+				constraints: []string{">=v1.0.0"},
+				tags:        []string{"v1.1.0"},
+			},
+			want: "",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := upgradeVersion(tc.args.current, tc.args.constraints, tc.args.tags)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nupgradeVersion(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
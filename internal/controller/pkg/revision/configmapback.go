@@ -0,0 +1,74 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/parser"
+
+	"github.com/crossplane/crossplane/internal/xpkg"
+)
+
+const (
+	errGetConfigMap    = "cannot get package ConfigMap"
+	errFmtNoStreamFile = "package ConfigMap has no %q key"
+)
+
+// ConfigMapBackend is a backend for parser that reads a package's YAML stream
+// from a ConfigMap, for packages with a ConfigMap source. It passes packages
+// with any other source to the wrapped backend.
+type ConfigMapBackend struct {
+	client    client.Reader
+	namespace string
+	wrapped   parser.Backend
+}
+
+// NewConfigMapBackend returns a backend that reads the YAML stream of packages
+// sourced from a ConfigMap in the supplied namespace, and otherwise calls the
+// wrapped backend.
+func NewConfigMapBackend(c client.Reader, namespace string, wrapped parser.Backend) *ConfigMapBackend {
+	return &ConfigMapBackend{client: c, namespace: namespace, wrapped: wrapped}
+}
+
+// Init initializes a ConfigMapBackend.
+func (b *ConfigMapBackend) Init(ctx context.Context, bo ...parser.BackendOption) (io.ReadCloser, error) {
+	n := &nestedBackend{}
+	for _, o := range bo {
+		o(n)
+	}
+	name, ok := xpkg.ParseConfigMapSource(n.pr.GetSource())
+	if !ok {
+		return b.wrapped.Init(ctx, bo...)
+	}
+	cm := &corev1.ConfigMap{}
+	if err := b.client.Get(ctx, types.NamespacedName{Namespace: b.namespace, Name: name}, cm); err != nil {
+		return nil, errors.Wrap(err, errGetConfigMap)
+	}
+	stream, ok := cm.Data[xpkg.StreamFile]
+	if !ok {
+		return nil, errors.Errorf(errFmtNoStreamFile, xpkg.StreamFile)
+	}
+	return io.NopCloser(strings.NewReader(stream)), nil
+}
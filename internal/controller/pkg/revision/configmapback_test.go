@@ -0,0 +1,114 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/parser"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/internal/xpkg"
+)
+
+func TestConfigMapBackend(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type args struct {
+		client client.Reader
+		source string
+	}
+	type want struct {
+		stream string
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NotConfigMapSource": {
+			reason: "Packages that aren't sourced from a ConfigMap should be passed to the wrapped backend.",
+			args: args{
+				source: "xpkg.upbound.io/crossplane/cool-config:v1.0.0",
+			},
+			want: want{
+				stream: "wrapped",
+			},
+		},
+		"ErrGetConfigMap": {
+			reason: "We should return any error encountered getting the ConfigMap.",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				source: "configmap://cool-config",
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetConfigMap),
+			},
+		},
+		"ErrNoStreamFile": {
+			reason: "We should return an error if the ConfigMap has no package YAML stream.",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(nil)},
+				source: "configmap://cool-config",
+			},
+			want: want{
+				err: errors.Errorf(errFmtNoStreamFile, xpkg.StreamFile),
+			},
+		},
+		"Success": {
+			reason: "We should return the package YAML stream from the ConfigMap.",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+					obj.(*corev1.ConfigMap).Data = map[string]string{xpkg.StreamFile: "cool: stream"}
+					return nil
+				})},
+				source: "configmap://cool-config",
+			},
+			want: want{
+				stream: "cool: stream",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			b := NewConfigMapBackend(tc.args.client, "crossplane-system", parser.NewEchoBackend("wrapped"))
+			pr := &v1.ConfigurationRevision{Spec: v1.PackageRevisionSpec{Package: tc.args.source}}
+			rc, err := b.Init(context.TODO(), PackageRevision(pr))
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nb.Init(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			got, _ := io.ReadAll(rc)
+			if diff := cmp.Diff(tc.want.stream, string(got)); diff != "" {
+				t.Errorf("\n%s\nb.Init(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
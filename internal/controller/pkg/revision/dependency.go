@@ -18,6 +18,7 @@ package revision
 
 import (
 	"context"
+	"strings"
 
 	"github.com/Masterminds/semver"
 	"github.com/google/go-containerregistry/pkg/name"
@@ -41,7 +42,7 @@ const (
 	errNotMeta                   = "meta type is not a valid package"
 	errGetOrCreateLock           = "cannot get or create lock"
 	errInitDAG                   = "cannot initialize dependency graph from the packages in the lock"
-	errFmtIncompatibleDependency = "incompatible dependencies: %+v"
+	errFmtIncompatibleDependency = "incompatible dependencies: %s"
 	errFmtMissingDependencies    = "missing dependencies: %+v"
 	errDependencyNotInGraph      = "dependency is not present in graph"
 	errDependencyNotLockPackage  = "dependency in graph is not a lock package"
@@ -71,6 +72,9 @@ func NewPackageDependencyManager(c client.Client, nd dag.NewDAGFn, t v1beta1.Pac
 
 // Resolve resolves package dependencies.
 func (m *PackageDependencyManager) Resolve(ctx context.Context, pkg runtime.Object, pr v1.PackageRevision) (found, installed, invalid int, err error) { //nolint:gocyclo // TODO(negz): Can this be refactored for less complexity?
+	// We only report conflicts found by this resolution.
+	pr.SetDependencyConflicts(nil)
+
 	// If we are inactive, we don't need to resolve dependencies.
 	if pr.GetDesiredState() == v1.PackageRevisionInactive {
 		return 0, 0, 0, nil
@@ -113,9 +117,17 @@ func (m *PackageDependencyManager) Resolve(ctx context.Context, pkg runtime.Obje
 		return found, installed, invalid, errors.Wrap(err, errGetOrCreateLock)
 	}
 
-	prRef, err := name.ParseReference(pr.GetSource(), name.WithDefaultRegistry(""))
-	if err != nil {
-		return found, installed, invalid, err
+	// Packages sourced from a ConfigMap or a local package have no OCI
+	// reference, so we record their source as is, and no version. A revision
+	// pinned to a digest is recorded with the tag it was pinned from, so
+	// that its version can satisfy dependency constraints.
+	lockRef, lockVersion := pr.GetSource(), ""
+	if xpkg.IsRegistrySource(pr.GetSource()) {
+		prRef, err := name.ParseReference(xpkg.UnpinSource(pr.GetSource()), name.WithDefaultRegistry(""))
+		if err != nil {
+			return found, installed, invalid, err
+		}
+		lockRef, lockVersion = xpkg.ParsePackageSourceFromReference(prRef), prRef.Identifier()
 	}
 
 	d := m.newDag()
@@ -124,14 +136,13 @@ func (m *PackageDependencyManager) Resolve(ctx context.Context, pkg runtime.Obje
 		return found, installed, invalid, errors.Wrap(err, errInitDAG)
 	}
 
-	lockRef := xpkg.ParsePackageSourceFromReference(prRef)
 	// NOTE(hasheddan): consider adding health of package to lock so that it can
 	// be rolled up to any dependent packages.
 	self := v1beta1.LockPackage{
 		Name:         pr.GetName(),
 		Type:         m.packageType,
 		Source:       lockRef,
-		Version:      prRef.Identifier(),
+		Version:      lockVersion,
 		Dependencies: sources,
 	}
 
@@ -188,7 +199,7 @@ func (m *PackageDependencyManager) Resolve(ctx context.Context, pkg runtime.Obje
 
 	// All of our dependencies and transitive dependencies must exist. Check
 	// that neighbors have valid versions.
-	var invalidDeps []string
+	var conflicts []v1.DependencyConflict
 	for _, dep := range self.Dependencies {
 		n, err := d.GetNode(dep.Package)
 		if err != nil {
@@ -207,12 +218,21 @@ func (m *PackageDependencyManager) Resolve(ctx context.Context, pkg runtime.Obje
 			return found, installed, invalid, err
 		}
 		if !c.Check(v) {
-			invalidDeps = append(invalidDeps, lp.Identifier())
+			conflicts = append(conflicts, v1.DependencyConflict{
+				Package:     lp.Identifier(),
+				Version:     lp.Version,
+				Constraints: lock.Constraints(lp.Identifier()),
+			})
 		}
 	}
-	invalid = len(invalidDeps)
+	invalid = len(conflicts)
 	if invalid > 0 {
-		return found, installed, invalid, errors.Errorf(errFmtIncompatibleDependency, invalidDeps)
+		pr.SetDependencyConflicts(conflicts)
+		msgs := make([]string, len(conflicts))
+		for i, c := range conflicts {
+			msgs[i] = c.String()
+		}
+		return found, installed, invalid, errors.Errorf(errFmtIncompatibleDependency, strings.Join(msgs, "; "))
 	}
 	return found, installed, invalid, nil
 }
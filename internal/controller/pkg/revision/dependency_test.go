@@ -54,6 +54,7 @@ func TestResolve(t *testing.T) {
 		total     int
 		installed int
 		invalid   int
+		conflicts []v1.DependencyConflict
 	}
 
 	cases := map[string]struct {
@@ -350,12 +351,14 @@ func TestResolve(t *testing.T) {
 									Source: "hasheddan/config-nop-a",
 									Dependencies: []v1beta1.Dependency{
 										{
-											Package: "not-here-1",
-											Type:    v1beta1.ProviderPackageType,
+											Package:     "not-here-1",
+											Type:        v1beta1.ProviderPackageType,
+											Constraints: ">=v0.1.0",
 										},
 										{
-											Package: "not-here-2",
-											Type:    v1beta1.ConfigurationPackageType,
+											Package:     "not-here-2",
+											Type:        v1beta1.ConfigurationPackageType,
+											Constraints: ">=v0.1.0",
 										},
 									},
 								},
@@ -368,6 +371,16 @@ func TestResolve(t *testing.T) {
 										},
 									},
 								},
+								{
+									Source: "hasheddan/config-nop-b",
+									Dependencies: []v1beta1.Dependency{
+										{
+											Package:     "not-here-1",
+											Type:        v1beta1.ProviderPackageType,
+											Constraints: "<v0.1.0",
+										},
+									},
+								},
 							}
 							return nil
 						}),
@@ -433,7 +446,24 @@ func TestResolve(t *testing.T) {
 				total:     3,
 				installed: 3,
 				invalid:   2,
-				err:       errors.Errorf(errFmtIncompatibleDependency, []string{"not-here-1", "not-here-2"}),
+				err:       errors.Errorf(errFmtIncompatibleDependency, "not-here-1@v0.0.1: hasheddan/config-nop-a requires >=v0.1.0, hasheddan/config-nop-b requires <v0.1.0; not-here-2@v0.0.1: hasheddan/config-nop-a requires >=v0.1.0"),
+				conflicts: []v1.DependencyConflict{
+					{
+						Package: "not-here-1",
+						Version: "v0.0.1",
+						Constraints: []v1.DependencyConstraint{
+							{Package: "hasheddan/config-nop-a", Constraints: ">=v0.1.0"},
+							{Package: "hasheddan/config-nop-b", Constraints: "<v0.1.0"},
+						},
+					},
+					{
+						Package: "not-here-2",
+						Version: "v0.0.1",
+						Constraints: []v1.DependencyConstraint{
+							{Package: "hasheddan/config-nop-a", Constraints: ">=v0.1.0"},
+						},
+					},
+				},
 			},
 		},
 		"SuccessfulSelfExistValidDependencies": {
@@ -571,6 +601,9 @@ func TestResolve(t *testing.T) {
 			if diff := cmp.Diff(tc.want.invalid, invalid); diff != "" {
 				t.Errorf("\n%s\nInvalid(...): -want, +got:\n%s", tc.reason, diff)
 			}
+			if diff := cmp.Diff(tc.want.conflicts, tc.args.pr.GetDependencyConflicts()); diff != "" {
+				t.Errorf("\n%s\nGetDependencyConflicts(...): -want, +got:\n%s", tc.reason, diff)
+			}
 		})
 	}
 }
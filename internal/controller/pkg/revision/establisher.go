@@ -38,7 +38,9 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
+	apiextensionsv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/internal/initializer"
 )
 
 const (
@@ -55,13 +57,17 @@ const (
 	errWebhookSecretWithoutCABundle = "the value for the key tls.crt cannot be empty"
 	errFmtGetOwnedObject            = "cannot get owned object: %s/%s"
 	errFmtUpdateOwnedObject         = "cannot update owned object: %s/%s"
+	errFmtGetExternalObject         = "cannot get externally managed object: %s/%s"
+	errFmtExternalObjectMissing     = "externally managed object %s/%s doesn't exist"
+	errFmtExternalVersionNotServed  = "externally managed object %s/%s doesn't serve version %s"
 )
 
 // An Establisher establishes control or ownership of a set of resources in the
 // API server by checking that control or ownership can be established for all
-// resources and then establishing it.
+// resources and then establishing it. It returns references to all of the
+// resources, and to those whose spec changed when it established control.
 type Establisher interface {
-	Establish(ctx context.Context, objects []runtime.Object, parent v1.PackageRevision, control bool) ([]xpv1.TypedReference, error)
+	Establish(ctx context.Context, objects []runtime.Object, parent v1.PackageRevision, control bool) (refs, changed []xpv1.TypedReference, err error)
 	ReleaseObjects(ctx context.Context, parent v1.PackageRevision) error
 }
 
@@ -74,8 +80,8 @@ func NewNopEstablisher() *NopEstablisher {
 type NopEstablisher struct{}
 
 // Establish does nothing.
-func (*NopEstablisher) Establish(_ context.Context, _ []runtime.Object, _ v1.PackageRevision, _ bool) ([]xpv1.TypedReference, error) {
-	return nil, nil
+func (*NopEstablisher) Establish(_ context.Context, _ []runtime.Object, _ v1.PackageRevision, _ bool) ([]xpv1.TypedReference, []xpv1.TypedReference, error) {
+	return nil, nil, nil
 }
 
 // ReleaseObjects does nothing.
@@ -109,21 +115,41 @@ type currentDesired struct {
 
 // Establish checks that control or ownership of resources can be established by
 // parent, then establishes it.
-func (e *APIEstablisher) Establish(ctx context.Context, objs []runtime.Object, parent v1.PackageRevision, control bool) ([]xpv1.TypedReference, error) {
+func (e *APIEstablisher) Establish(ctx context.Context, objs []runtime.Object, parent v1.PackageRevision, control bool) ([]xpv1.TypedReference, []xpv1.TypedReference, error) {
 	err := e.addLabels(objs, parent)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	// We never create, update, or delete externally managed objects. An
+	// active revision only checks that they're ready for it to use. We still
+	// reference them in the revision's status, because the RBAC manager
+	// grants a provider access to the types defined by the CRDs it
+	// references.
+	objs, external := partitionExternallyManaged(objs, parent)
+	if control {
+		if err := e.checkExternallyManaged(ctx, external); err != nil {
+			return nil, nil, err
+		}
 	}
+
 	allObjs, err := e.validate(ctx, objs, parent, control)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	resourceRefs, err := e.establish(ctx, allObjs, parent, control)
+	refs, changed, err := e.establish(ctx, allObjs, parent, control)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	for _, o := range external {
+		d, ok := o.(metav1.Object)
+		if !ok {
+			return nil, nil, errors.New(errAssertResourceObj)
+		}
+		refs = append(refs, *meta.TypedReferenceTo(d, o.GetObjectKind().GroupVersionKind()))
 	}
-	return resourceRefs, nil
+	return refs, changed, nil
 }
 
 // ReleaseObjects removes control of owned resources in the API server for a
@@ -146,6 +172,12 @@ func (e *APIEstablisher) ReleaseObjects(ctx context.Context, parent v1.PackageRe
 	g.SetLimit(maxConcurrentEstablishers)
 	for _, ref := range allObjs {
 		ref := ref // Pin the loop variable.
+
+		// We never update externally managed objects, so we never
+		// established control of them.
+		if v1.ExternallyManaged(parent.GetExternallyManagedObjects(), ref.Kind, ref.Name) {
+			continue
+		}
 		g.Go(func() error {
 			select {
 			case <-ctx.Done():
@@ -202,6 +234,72 @@ func (e *APIEstablisher) ReleaseObjects(ctx context.Context, parent v1.PackageRe
 	return g.Wait()
 }
 
+// checkExternallyManaged checks that the supplied externally managed objects
+// exist, and that they serve every version the package serves.
+func (e *APIEstablisher) checkExternallyManaged(ctx context.Context, objs []runtime.Object) error {
+	for _, o := range objs {
+		d, ok := o.(client.Object)
+		if !ok {
+			return errors.New(errAssertClientObj)
+		}
+		kind := d.GetObjectKind().GroupVersionKind().Kind
+		current, _ := o.DeepCopyObject().(client.Object)
+		if err := e.client.Get(ctx, types.NamespacedName{Name: d.GetName(), Namespace: d.GetNamespace()}, current); err != nil {
+			if kerrors.IsNotFound(err) {
+				return errors.Errorf(errFmtExternalObjectMissing, kind, d.GetName())
+			}
+			return errors.Wrapf(err, errFmtGetExternalObject, kind, d.GetName())
+		}
+		served := map[string]bool{}
+		for _, v := range servedVersions(current) {
+			served[v] = true
+		}
+		for _, v := range servedVersions(d) {
+			if !served[v] {
+				return errors.Errorf(errFmtExternalVersionNotServed, kind, d.GetName(), v)
+			}
+		}
+	}
+	return nil
+}
+
+// partitionExternallyManaged partitions the supplied objects into those the
+// package manager manages and those the parent says are externally managed.
+func partitionExternallyManaged(objs []runtime.Object, parent v1.PackageRevision) (managed, external []runtime.Object) {
+	sel := parent.GetExternallyManagedObjects()
+	if len(sel) == 0 {
+		return objs, nil
+	}
+	for _, o := range objs {
+		if d, ok := o.(metav1.Object); ok && v1.ExternallyManaged(sel, o.GetObjectKind().GroupVersionKind().Kind, d.GetName()) {
+			external = append(external, o)
+			continue
+		}
+		managed = append(managed, o)
+	}
+	return managed, external
+}
+
+// servedVersions returns the versions served by the supplied CRD or XRD.
+func servedVersions(o runtime.Object) []string {
+	var served []string
+	switch d := o.(type) {
+	case *extv1.CustomResourceDefinition:
+		for _, v := range d.Spec.Versions {
+			if v.Served {
+				served = append(served, v.Name)
+			}
+		}
+	case *apiextensionsv1.CompositeResourceDefinition:
+		for _, v := range d.Spec.Versions {
+			if v.Served {
+				served = append(served, v.Name)
+			}
+		}
+	}
+	return served
+}
+
 func (e *APIEstablisher) addLabels(objs []runtime.Object, parent v1.PackageRevision) error {
 	commonLabels := parent.GetCommonLabels()
 	for _, obj := range objs {
@@ -328,6 +426,11 @@ func (e *APIEstablisher) enrichControlledResource(res runtime.Object, webhookTLS
 			conf.Webhooks[i].ClientConfig.Service.Name = parent.GetLabels()[v1.LabelParentPackage]
 			conf.Webhooks[i].ClientConfig.Service.Namespace = e.namespace
 			conf.Webhooks[i].ClientConfig.Service.Port = ptr.To[int32](servicePort)
+			// A package's webhooks can be disabled in case they're broken
+			// and blocking API requests.
+			if v1.WebhooksDisabled(parent) {
+				conf.Webhooks[i].FailurePolicy = ptr.To(admv1.Ignore)
+			}
 		}
 	case *admv1.MutatingWebhookConfiguration:
 		if len(webhookTLSCert) == 0 {
@@ -344,6 +447,11 @@ func (e *APIEstablisher) enrichControlledResource(res runtime.Object, webhookTLS
 			conf.Webhooks[i].ClientConfig.Service.Name = parent.GetLabels()[v1.LabelParentPackage]
 			conf.Webhooks[i].ClientConfig.Service.Namespace = e.namespace
 			conf.Webhooks[i].ClientConfig.Service.Port = ptr.To[int32](servicePort)
+			// A package's webhooks can be disabled in case they're broken
+			// and blocking API requests.
+			if v1.WebhooksDisabled(parent) {
+				conf.Webhooks[i].FailurePolicy = ptr.To(admv1.Ignore)
+			}
 		}
 	case *extv1.CustomResourceDefinition:
 		if conf.Spec.Conversion != nil && conf.Spec.Conversion.Strategy == extv1.WebhookConverter {
@@ -368,8 +476,10 @@ func (e *APIEstablisher) enrichControlledResource(res runtime.Object, webhookTLS
 	return nil
 }
 
-// getWebhookTLSCert returns the TLS certificate of the webhook server if the
-// revision has a TLS server secret name.
+// getWebhookTLSCert returns the CA bundle that verifies the TLS certificate of
+// the webhook server if the revision has a TLS server secret name. We prefer
+// the certificate of the CA that signed the server's certificate, so that the
+// CA bundle doesn't change when the server's certificate is renewed.
 func (e *APIEstablisher) getWebhookTLSCert(ctx context.Context, parentWithRuntime v1.PackageRevisionWithRuntime) (webhookTLSCert []byte, err error) {
 	tlsServerSecretName := parentWithRuntime.GetTLSServerSecretName()
 	if tlsServerSecretName == nil {
@@ -382,6 +492,9 @@ func (e *APIEstablisher) getWebhookTLSCert(ctx context.Context, parentWithRuntim
 		return nil, errors.Wrap(err, errGetWebhookTLSSecret)
 	}
 
+	if ca := s.Data[initializer.SecretKeyCACert]; len(ca) != 0 {
+		return ca, nil
+	}
 	if len(s.Data["tls.crt"]) == 0 {
 		return nil, errors.New(errWebhookSecretWithoutCABundle)
 	}
@@ -389,10 +502,18 @@ func (e *APIEstablisher) getWebhookTLSCert(ctx context.Context, parentWithRuntim
 	return webhookTLSCert, nil
 }
 
-func (e *APIEstablisher) establish(ctx context.Context, allObjs []currentDesired, parent client.Object, control bool) ([]xpv1.TypedReference, error) { //nolint:gocyclo // Only slightly over (12).
+// establishedRef is a reference to an established resource.
+type establishedRef struct {
+	ref xpv1.TypedReference
+
+	// changed is true if establishing control changed the resource's spec.
+	changed bool
+}
+
+func (e *APIEstablisher) establish(ctx context.Context, allObjs []currentDesired, parent client.Object, control bool) ([]xpv1.TypedReference, []xpv1.TypedReference, error) { //nolint:gocyclo // Only slightly over (12).
 	g, ctx := errgroup.WithContext(ctx)
 	g.SetLimit(maxConcurrentEstablishers)
-	out := make(chan xpv1.TypedReference, len(allObjs))
+	out := make(chan establishedRef, len(allObjs))
 	for _, cd := range allObjs {
 		cd := cd // Pin the loop variable.
 		g.Go(func() error {
@@ -406,18 +527,25 @@ func (e *APIEstablisher) establish(ctx context.Context, allObjs []currentDesired
 					}
 				}
 				select {
-				case out <- *meta.TypedReferenceTo(cd.Desired, cd.Desired.GetObjectKind().GroupVersionKind()):
+				case out <- establishedRef{ref: *meta.TypedReferenceTo(cd.Desired, cd.Desired.GetObjectKind().GroupVersionKind())}:
 					return nil
 				case <-ctx.Done():
 					return ctx.Err()
 				}
 			}
 
+			// The API server increments an object's generation when its
+			// spec changes. Updating the desired object populates it with
+			// the new generation.
+			generation := cd.Current.GetGeneration()
 			if err := e.update(ctx, cd.Current, cd.Desired, parent, control); err != nil {
 				return err
 			}
 			select {
-			case out <- *meta.TypedReferenceTo(cd.Desired, cd.Desired.GetObjectKind().GroupVersionKind()):
+			case out <- establishedRef{
+				ref:     *meta.TypedReferenceTo(cd.Desired, cd.Desired.GetObjectKind().GroupVersionKind()),
+				changed: control && cd.Desired.GetGeneration() > generation,
+			}:
 				return nil
 			case <-ctx.Done():
 				return ctx.Err()
@@ -425,14 +553,18 @@ func (e *APIEstablisher) establish(ctx context.Context, allObjs []currentDesired
 		})
 	}
 	if err := g.Wait(); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	close(out)
 	resourceRefs := []xpv1.TypedReference{}
-	for ref := range out {
-		resourceRefs = append(resourceRefs, ref)
+	changedRefs := []xpv1.TypedReference{}
+	for er := range out {
+		resourceRefs = append(resourceRefs, er.ref)
+		if er.changed {
+			changedRefs = append(changedRefs, er.ref)
+		}
 	}
-	return resourceRefs, nil
+	return resourceRefs, changedRefs, nil
 }
 
 func (e *APIEstablisher) create(ctx context.Context, obj resource.Object, parent resource.Object, opts ...client.CreateOption) error {
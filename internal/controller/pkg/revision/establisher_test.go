@@ -40,6 +40,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/internal/controller/rbac/provider/roles"
 )
 
 var _ Establisher = &APIEstablisher{}
@@ -57,8 +58,9 @@ func TestAPIEstablisherEstablish(t *testing.T) {
 	}
 
 	type want struct {
-		err  error
-		refs []xpv1.TypedReference
+		err     error
+		refs    []xpv1.TypedReference
+		changed []xpv1.TypedReference
 	}
 
 	cases := map[string]struct {
@@ -101,6 +103,42 @@ func TestAPIEstablisherEstablish(t *testing.T) {
 				refs: []xpv1.TypedReference{{Name: "ref-me"}},
 			},
 		},
+		"SuccessfulExistsEstablishControlChanged": {
+			reason: "Establishment should report existing objects whose spec changed when we established control.",
+			args: args{
+				est: &APIEstablisher{
+					client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+							obj.SetGeneration(1)
+							return nil
+						}),
+						MockUpdate: test.NewMockUpdateFn(nil, func(obj client.Object) error {
+							obj.SetGeneration(2)
+							return nil
+						}),
+					},
+				},
+				objs: []runtime.Object{
+					&extv1.CustomResourceDefinition{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "ref-me",
+						},
+					},
+				},
+				parent: &v1.ProviderRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{
+							v1.LabelParentPackage: "provider-name",
+						},
+					},
+				},
+				control: true,
+			},
+			want: want{
+				refs:    []xpv1.TypedReference{{Name: "ref-me"}},
+				changed: []xpv1.TypedReference{{Name: "ref-me"}},
+			},
+		},
 		"SuccessfulNotExistsEstablishControl": {
 			reason: "Establishment should be successful if we can establish control for a parent of new objects.",
 			args: args{
@@ -220,6 +258,64 @@ func TestAPIEstablisherEstablish(t *testing.T) {
 				},
 			},
 		},
+		"SuccessfulNotExistsEstablishControlWebhooksDisabled": {
+			reason: "Disabled webhooks should be created with a failure policy that ignores failures.",
+			args: args{
+				est: &APIEstablisher{
+					client: &test.MockClient{
+						MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+							if s, ok := obj.(*corev1.Secret); ok {
+								s.Data = map[string][]byte{"tls.crt": caBundle}
+								return nil
+							}
+							return kerrors.NewNotFound(schema.GroupResource{}, "")
+						},
+						MockCreate: func(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+							for _, w := range obj.(*admv1.ValidatingWebhookConfiguration).Webhooks {
+								if w.FailurePolicy == nil || *w.FailurePolicy != admv1.Ignore {
+									return errBoom
+								}
+							}
+							return nil
+						},
+					},
+				},
+				objs: []runtime.Object{
+					&admv1.ValidatingWebhookConfiguration{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "crossplane-providerrevision-provider-name",
+						},
+						Webhooks: []admv1.ValidatingWebhook{
+							{
+								Name: "some-webhook",
+							},
+						},
+					},
+				},
+				parent: &v1.ProviderRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "provider-name-1234",
+						Labels: map[string]string{
+							v1.LabelParentPackage: "provider-name",
+						},
+						Annotations: map[string]string{
+							v1.AnnotationDisableWebhooks: "true",
+						},
+					},
+					Spec: v1.ProviderRevisionSpec{
+						PackageRevisionRuntimeSpec: v1.PackageRevisionRuntimeSpec{
+							TLSServerSecretName: &tlsServerSecretName,
+						},
+					},
+				},
+				control: true,
+			},
+			want: want{
+				refs: []xpv1.TypedReference{
+					{Name: "crossplane-providerrevision-provider-name"},
+				},
+			},
+		},
 		"SuccessfulExistsEstablishOwnership": {
 			reason: "Establishment should be successful if we can establish ownership for a parent of existing objects.",
 			args: args{
@@ -452,11 +548,160 @@ func TestAPIEstablisherEstablish(t *testing.T) {
 				err: errBoom,
 			},
 		},
+		"SuccessfulExternallyManaged": {
+			reason: "We shouldn't update an externally managed object that serves the package's versions, but we should still reference it.",
+			args: args{
+				est: &APIEstablisher{
+					client: &test.MockClient{
+						MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+							if key.Name == "external" {
+								obj.(*extv1.CustomResourceDefinition).Spec.Versions = []extv1.CustomResourceDefinitionVersion{
+									{Name: "v1beta1", Served: true},
+									{Name: "v1", Served: true},
+								}
+							}
+							return nil
+						},
+						MockUpdate: func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+							if obj.GetName() == "external" {
+								return errors.New("externally managed object should not be updated")
+							}
+							return nil
+						},
+					},
+				},
+				objs: []runtime.Object{
+					&extv1.CustomResourceDefinition{
+						TypeMeta:   metav1.TypeMeta{Kind: "CustomResourceDefinition"},
+						ObjectMeta: metav1.ObjectMeta{Name: "external"},
+						Spec: extv1.CustomResourceDefinitionSpec{
+							Versions: []extv1.CustomResourceDefinitionVersion{{Name: "v1", Served: true}},
+						},
+					},
+					&extv1.CustomResourceDefinition{
+						ObjectMeta: metav1.ObjectMeta{Name: "ref-me"},
+					},
+				},
+				parent: &v1.ProviderRevision{
+					ObjectMeta: metav1.ObjectMeta{Name: "test"},
+					Spec: v1.ProviderRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							ExternallyManagedObjects: []v1.ExternallyManagedObject{
+								{Kind: "CustomResourceDefinition", Name: ptr.String("external")},
+							},
+						},
+					},
+				},
+				control: true,
+			},
+			want: want{
+				refs: []xpv1.TypedReference{{Name: "ref-me"}, {Kind: "CustomResourceDefinition", Name: "external"}},
+			},
+		},
+		"FailedExternallyManagedMissing": {
+			reason: "An active revision should return an error if an externally managed object doesn't exist.",
+			args: args{
+				est: &APIEstablisher{
+					client: &test.MockClient{
+						MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+					},
+				},
+				objs: []runtime.Object{
+					&extv1.CustomResourceDefinition{
+						TypeMeta:   metav1.TypeMeta{Kind: "CustomResourceDefinition"},
+						ObjectMeta: metav1.ObjectMeta{Name: "external"},
+						Spec: extv1.CustomResourceDefinitionSpec{
+							Versions: []extv1.CustomResourceDefinitionVersion{{Name: "v1", Served: true}},
+						},
+					},
+				},
+				parent: &v1.ProviderRevision{
+					ObjectMeta: metav1.ObjectMeta{Name: "test"},
+					Spec: v1.ProviderRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							ExternallyManagedObjects: []v1.ExternallyManagedObject{
+								{Kind: "CustomResourceDefinition", Name: ptr.String("external")},
+							},
+						},
+					},
+				},
+				control: true,
+			},
+			want: want{
+				err: errors.Errorf(errFmtExternalObjectMissing, "CustomResourceDefinition", "external"),
+			},
+		},
+		"FailedExternallyManagedVersionNotServed": {
+			reason: "An active revision should return an error if an externally managed object doesn't serve a version the package serves.",
+			args: args{
+				est: &APIEstablisher{
+					client: &test.MockClient{
+						MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+							obj.(*extv1.CustomResourceDefinition).Spec.Versions = []extv1.CustomResourceDefinitionVersion{{Name: "v1beta1", Served: true}}
+							return nil
+						},
+					},
+				},
+				objs: []runtime.Object{
+					&extv1.CustomResourceDefinition{
+						TypeMeta:   metav1.TypeMeta{Kind: "CustomResourceDefinition"},
+						ObjectMeta: metav1.ObjectMeta{Name: "external"},
+						Spec: extv1.CustomResourceDefinitionSpec{
+							Versions: []extv1.CustomResourceDefinitionVersion{{Name: "v1", Served: true}},
+						},
+					},
+				},
+				parent: &v1.ProviderRevision{
+					ObjectMeta: metav1.ObjectMeta{Name: "test"},
+					Spec: v1.ProviderRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							ExternallyManagedObjects: []v1.ExternallyManagedObject{
+								{Kind: "CustomResourceDefinition", Name: ptr.String("external")},
+							},
+						},
+					},
+				},
+				control: true,
+			},
+			want: want{
+				err: errors.Errorf(errFmtExternalVersionNotServed, "CustomResourceDefinition", "external", "v1"),
+			},
+		},
+		"SuccessfulExternallyManagedNoControl": {
+			reason: "An inactive revision shouldn't check externally managed objects.",
+			args: args{
+				est: &APIEstablisher{
+					client: &test.MockClient{},
+				},
+				objs: []runtime.Object{
+					&extv1.CustomResourceDefinition{
+						TypeMeta:   metav1.TypeMeta{Kind: "CustomResourceDefinition"},
+						ObjectMeta: metav1.ObjectMeta{Name: "external"},
+						Spec: extv1.CustomResourceDefinitionSpec{
+							Versions: []extv1.CustomResourceDefinitionVersion{{Name: "v1", Served: true}},
+						},
+					},
+				},
+				parent: &v1.ProviderRevision{
+					ObjectMeta: metav1.ObjectMeta{Name: "test"},
+					Spec: v1.ProviderRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							ExternallyManagedObjects: []v1.ExternallyManagedObject{
+								{Kind: "CustomResourceDefinition", Name: ptr.String("external")},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				refs: []xpv1.TypedReference{{Kind: "CustomResourceDefinition", Name: "external"}},
+			},
+		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			refs, err := tc.args.est.Establish(context.TODO(), tc.args.objs, tc.args.parent, tc.args.control)
+			refs, changed, err := tc.args.est.Establish(context.TODO(), tc.args.objs, tc.args.parent, tc.args.control)
 
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors(), cmpopts.EquateEmpty()); diff != "" {
 				t.Errorf("\n%s\ne.Check(...): -want error, +got error:\n%s", tc.reason, diff)
@@ -467,10 +712,54 @@ func TestAPIEstablisherEstablish(t *testing.T) {
 			if diff := cmp.Diff(tc.want.refs, refs, test.EquateErrors(), sort, cmpopts.EquateEmpty()); diff != "" {
 				t.Errorf("\n%s\ne.Check(...): -want, +got:\n%s", tc.reason, diff)
 			}
+			if diff := cmp.Diff(tc.want.changed, changed, sort, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\ne.Check(...): -want changed, +got changed:\n%s", tc.reason, diff)
+			}
 		})
 	}
 }
 
+func TestAPIEstablisherEstablishExternallyManagedProviderRoles(t *testing.T) {
+	// The RBAC manager grants a provider access to the types defined by the
+	// CRDs its revision references. A provider must be granted access to the
+	// types defined by its externally managed CRDs too.
+	e := &APIEstablisher{
+		client: &test.MockClient{
+			MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+				obj.(*extv1.CustomResourceDefinition).Spec.Versions = []extv1.CustomResourceDefinitionVersion{{Name: "v1", Served: true}}
+				return nil
+			},
+		},
+	}
+	objs := []runtime.Object{
+		&extv1.CustomResourceDefinition{
+			TypeMeta:   metav1.TypeMeta{APIVersion: extv1.SchemeGroupVersion.String(), Kind: "CustomResourceDefinition"},
+			ObjectMeta: metav1.ObjectMeta{Name: "pinballs.example.org"},
+			Spec: extv1.CustomResourceDefinitionSpec{
+				Versions: []extv1.CustomResourceDefinitionVersion{{Name: "v1", Served: true}},
+			},
+		},
+	}
+	pr := &v1.ProviderRevision{
+		ObjectMeta: metav1.ObjectMeta{Name: "test"},
+		Spec: v1.ProviderRevisionSpec{
+			PackageRevisionSpec: v1.PackageRevisionSpec{
+				ExternallyManagedObjects: []v1.ExternallyManagedObject{{Kind: "CustomResourceDefinition"}},
+			},
+		},
+	}
+
+	refs, _, err := e.Establish(context.TODO(), objs, pr, true)
+	if err != nil {
+		t.Fatalf("Establish(...): %v", err)
+	}
+
+	want := []roles.Resource{{Group: "example.org", Plural: "pinballs"}}
+	if diff := cmp.Diff(want, roles.DefinedResources(refs)); diff != "" {
+		t.Errorf("roles.DefinedResources(...): -want, +got:\n%s", diff)
+	}
+}
+
 func TestAPIEstablisherReleaseObjects(t *testing.T) {
 	errBoom := errors.New("boom")
 	controls := true
@@ -519,6 +808,39 @@ func TestAPIEstablisherReleaseObjects(t *testing.T) {
 				err: errors.Wrapf(errBoom, errFmtGetOwnedObject, "CustomResourceDefinition", "releases.helm.crossplane.io"),
 			},
 		},
+		"IgnoreExternallyManagedObject": {
+			reason: "Should not get or update an externally managed object, since we never established control of it.",
+			args: args{
+				est: &APIEstablisher{
+					// Any call to the client would panic.
+					client: &test.MockClient{},
+				},
+				parent: &v1.ProviderRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						UID: "some-unique-uid-2312",
+					},
+					Spec: v1.ProviderRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							ExternallyManagedObjects: []v1.ExternallyManagedObject{
+								{Kind: "CustomResourceDefinition"},
+							},
+						},
+					},
+					Status: v1.PackageRevisionStatus{
+						ObjectRefs: []xpv1.TypedReference{
+							{
+								APIVersion: "apiextensions.k8s.io/v1",
+								Kind:       "CustomResourceDefinition",
+								Name:       "releases.helm.crossplane.io",
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				err: nil,
+			},
+		},
 		"IgnoreOwnedObjectNotFound": {
 			reason: "Should ignore if we the owned object does not exist.",
 			args: args{
@@ -22,6 +22,7 @@ import (
 	"io"
 
 	"github.com/google/go-containerregistry/pkg/name"
+	conregv1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/mutate"
 	"github.com/google/go-containerregistry/pkg/v1/validate"
 
@@ -80,7 +81,7 @@ func NewImageBackend(fetcher xpkg.Fetcher, opts ...ImageBackendOption) *ImageBac
 }
 
 // Init initializes an ImageBackend.
-func (i *ImageBackend) Init(ctx context.Context, bo ...parser.BackendOption) (io.ReadCloser, error) { //nolint:gocyclo // TODO(negz): Can this be made less complex?
+func (i *ImageBackend) Init(ctx context.Context, bo ...parser.BackendOption) (io.ReadCloser, error) {
 	// NOTE(hasheddan): we use nestedBackend here because simultaneous
 	// reconciles of providers or configurations can lead to the package
 	// revision being overwritten mid-execution in the shared image backend when
@@ -102,6 +103,11 @@ func (i *ImageBackend) Init(ctx context.Context, bo ...parser.BackendOption) (io
 	if err != nil {
 		return nil, errors.Wrap(err, errFetchPackage)
 	}
+	return packageStream(img)
+}
+
+// packageStream returns the package YAML stream of the supplied image.
+func packageStream(img conregv1.Image) (io.ReadCloser, error) { //nolint:gocyclo // TODO(negz): Can this be made less complex?
 	// Get image manifest.
 	manifest, err := img.Manifest()
 	if err != nil {
@@ -0,0 +1,63 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"context"
+	"io"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/parser"
+
+	"github.com/crossplane/crossplane/internal/xpkg"
+)
+
+const (
+	errReadLocalPackage = "cannot read local package"
+)
+
+// LocalBackend is a backend for parser that reads a package's YAML stream
+// from a package image in a local directory, for packages with a local
+// source. It passes packages with any other source to the wrapped backend.
+type LocalBackend struct {
+	local   *xpkg.LocalPackages
+	wrapped parser.Backend
+}
+
+// NewLocalBackend returns a backend that reads the YAML stream of packages
+// sourced from the supplied local packages, and otherwise calls the wrapped
+// backend.
+func NewLocalBackend(l *xpkg.LocalPackages, wrapped parser.Backend) *LocalBackend {
+	return &LocalBackend{local: l, wrapped: wrapped}
+}
+
+// Init initializes a LocalBackend.
+func (b *LocalBackend) Init(ctx context.Context, bo ...parser.BackendOption) (io.ReadCloser, error) {
+	n := &nestedBackend{}
+	for _, o := range bo {
+		o(n)
+	}
+	path, ok := xpkg.ParseLocalSource(n.pr.GetSource())
+	if !ok {
+		return b.wrapped.Init(ctx, bo...)
+	}
+	img, err := b.local.Image(path)
+	if err != nil {
+		return nil, errors.Wrap(err, errReadLocalPackage)
+	}
+	return packageStream(img)
+}
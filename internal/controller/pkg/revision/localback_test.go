@@ -0,0 +1,83 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/parser"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/internal/xpkg"
+)
+
+func TestLocalBackend(t *testing.T) {
+	dir := t.TempDir()
+	_, errNotExist := os.Stat(filepath.Join(dir, "cool-config.xpkg"))
+
+	type want struct {
+		stream string
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		source string
+		want   want
+	}{
+		"NotLocalSource": {
+			reason: "Packages that don't have a local source should be passed to the wrapped backend.",
+			source: "xpkg.upbound.io/crossplane/cool-config:v1.0.0",
+			want: want{
+				stream: "wrapped",
+			},
+		},
+		"ErrReadLocalPackage": {
+			reason: "We should return any error encountered reading the local package.",
+			source: "file://cool-config.xpkg",
+			want: want{
+				err: errors.Wrap(errors.Wrap(errNotExist, "cannot stat local package"), errReadLocalPackage),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			b := NewLocalBackend(xpkg.NewLocalPackages(dir), parser.NewEchoBackend("wrapped"))
+			pr := &v1.ConfigurationRevision{Spec: v1.PackageRevisionSpec{Package: tc.source}}
+			rc, err := b.Init(context.TODO(), PackageRevision(pr))
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nb.Init(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			got, _ := io.ReadAll(rc)
+			if diff := cmp.Diff(tc.want.stream, string(got)); diff != "" {
+				t.Errorf("\n%s\nb.Init(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
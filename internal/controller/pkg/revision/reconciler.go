@@ -19,6 +19,7 @@ package revision
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"sort"
 	"strings"
@@ -59,6 +60,8 @@ import (
 
 const (
 	reconcileTimeout = 3 * time.Minute
+	// how often to check again whether a revision's CRDs are safe to upgrade
+	upgradeCheckWait = 1 * time.Minute
 	// the max size of a package parsed by the parser
 	maxPackageSize = 200 << 20 // 100 MB
 )
@@ -84,6 +87,7 @@ const (
 	errLintPackage       = "linting package contents failed"
 	errNotOneMeta        = "cannot install package with multiple meta types"
 	errIncompatible      = "incompatible Crossplane version"
+	errLicenseNotAllowed = "package license is not allowed"
 
 	errManifestBuilderOptions = "cannot prepare runtime manifest builder options"
 	errPreHook                = "pre establish runtime hook failed for package"
@@ -98,6 +102,11 @@ const (
 	errRemoveLock  = "cannot remove package revision from Lock"
 	errResolveDeps = "cannot resolve package dependencies"
 
+	errCheckUpgrade = "cannot check whether package CRDs are safe to upgrade"
+
+	errSummarizeChanges = "cannot summarize package revision changes"
+	errListRevisions    = "cannot list package revisions"
+
 	errConfResourceObject = "cannot convert to resource.Object"
 
 	errCannotInitializeHostClientSet = "failed to initialize host clientset with in cluster config"
@@ -118,9 +127,11 @@ const (
 	reasonParse        event.Reason = "ParsePackage"
 	reasonLint         event.Reason = "LintPackage"
 	reasonDependencies event.Reason = "ResolveDependencies"
+	reasonUpgrade      event.Reason = "CheckUpgrade"
 	reasonSync         event.Reason = "SyncPackage"
 	reasonDeactivate   event.Reason = "DeactivateRevision"
 	reasonPaused       event.Reason = "ReconciliationPaused"
+	reasonChanges      event.Reason = "SummarizeChanges"
 )
 
 // ReconcilerOption is used to configure the Reconciler.
@@ -148,6 +159,15 @@ func WithNewPackageRevisionFn(f func() v1.PackageRevision) ReconcilerOption {
 	}
 }
 
+// WithNewPackageRevisionListFn determines the type of package revision list
+// used to find the revision a package revision replaced, in order to summarize
+// what it changed. Changes aren't summarized if it's not set.
+func WithNewPackageRevisionListFn(f func() v1.PackageRevisionList) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.newPackageRevisionList = f
+	}
+}
+
 // WithLogger specifies how the Reconciler should log messages.
 func WithLogger(log logging.Logger) ReconcilerOption {
 	return func(r *Reconciler) {
@@ -186,6 +206,23 @@ func WithRuntimeHooks(h RuntimeHooks) ReconcilerOption {
 	}
 }
 
+// WithRuntimeHealthChecker specifies how the Reconciler should determine the
+// health of an active package revision's runtime.
+func WithRuntimeHealthChecker(c RuntimeHealthChecker) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.runtimeHealth = c
+	}
+}
+
+// WithUpgradeChecker specifies how the Reconciler should check whether a
+// package revision's objects can safely replace the installed objects before
+// it establishes control of them.
+func WithUpgradeChecker(c UpgradeChecker) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.upgrades = c
+	}
+}
+
 // WithEstablisher specifies how the Reconciler should establish package resources.
 func WithEstablisher(e Establisher) ReconcilerOption {
 	return func(r *Reconciler) {
@@ -222,6 +259,15 @@ func WithVersioner(v version.Operations) ReconcilerOption {
 	}
 }
 
+// WithLicenseAllowlist specifies the SPDX license identifiers a package's
+// license must be satisfied by for the Reconciler to install it. Any license
+// is allowed if the allowlist is empty.
+func WithLicenseAllowlist(ids []string) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.licenses = ids
+	}
+}
+
 // WithNamespace specifies the namespace in which the Reconciler should create
 // runtime resources.
 func WithNamespace(n string) ReconcilerOption {
@@ -257,10 +303,13 @@ type Reconciler struct {
 	revision       resource.Finalizer
 	lock           DependencyManager
 	runtimeHook    RuntimeHooks
+	runtimeHealth  RuntimeHealthChecker
+	upgrades       UpgradeChecker
 	objects        Establisher
 	parser         parser.Parser
 	linter         parser.Linter
 	versioner      version.Operations
+	licenses       []string
 	backend        parser.Backend
 	log            logging.Logger
 	record         event.Recorder
@@ -268,13 +317,15 @@ type Reconciler struct {
 	namespace      string
 	serviceAccount string
 
-	newPackageRevision func() v1.PackageRevision
+	newPackageRevision     func() v1.PackageRevision
+	newPackageRevisionList func() v1.PackageRevisionList
 }
 
 // SetupProviderRevision adds a controller that reconciles ProviderRevisions.
 func SetupProviderRevision(mgr ctrl.Manager, o controller.Options) error {
 	name := "packages/" + strings.ToLower(v1.ProviderRevisionGroupKind)
 	nr := func() v1.PackageRevision { return &v1.ProviderRevision{} }
+	nrl := func() v1.PackageRevisionList { return &v1.ProviderRevisionList{} }
 
 	clientset, err := kubernetes.NewForConfig(mgr.GetConfig())
 	if err != nil {
@@ -310,18 +361,27 @@ func SetupProviderRevision(mgr ctrl.Manager, o controller.Options) error {
 		WithDependencyManager(NewPackageDependencyManager(mgr.GetClient(), dag.NewMapDag, v1beta1.ProviderPackageType)),
 		WithEstablisher(NewAPIEstablisher(mgr.GetClient(), o.Namespace)),
 		WithNewPackageRevisionFn(nr),
+		WithNewPackageRevisionListFn(nrl),
 		WithParser(parser.New(metaScheme, objScheme)),
-		WithParserBackend(NewImageBackend(fetcher, WithDefaultRegistry(o.DefaultRegistry))),
+		WithParserBackend(parserBackend(fetcher, o)),
 		WithLinter(xpkg.NewProviderLinter()),
 		WithLogger(o.Logger.WithValues("controller", name)),
 		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
 		WithNamespace(o.Namespace),
 		WithServiceAccount(o.ServiceAccount),
 		WithFeatureFlags(o.Features),
+		WithLicenseAllowlist(o.LicenseAllowlist),
+	}
+
+	if o.Features.Enabled(features.EnableAlphaCRDUpgradeChecks) {
+		ro = append(ro, WithUpgradeChecker(NewCRDUpgradeChecker(mgr.GetClient())))
 	}
 
 	if o.PackageRuntime == controller.PackageRuntimeDeployment {
-		ro = append(ro, WithRuntimeHooks(NewProviderHooks(mgr.GetClient(), o.DefaultRegistry)))
+		ro = append(ro,
+			WithRuntimeHooks(NewProviderHooks(mgr.GetClient(), o.DefaultRegistry, providerHooksOptions(o)...)),
+			WithRuntimeHealthChecker(NewDeploymentHealthChecker(mgr.GetAPIReader())),
+		)
 
 		if o.Features.Enabled(features.EnableBetaDeploymentRuntimeConfigs) {
 			cb = cb.Watches(&v1beta1.DeploymentRuntimeConfig{}, &EnqueueRequestForReferencingProviderRevisions{
@@ -334,10 +394,36 @@ func SetupProviderRevision(mgr ctrl.Manager, o controller.Options) error {
 		Complete(ratelimiter.NewReconciler(name, errors.WithSilentRequeueOnConflict(NewReconciler(mgr, ro...)), o.GlobalRateLimiter))
 }
 
+// providerHooksOptions returns the options for provider runtime hooks.
+func providerHooksOptions(o controller.Options) []ProviderHooksOption {
+	if !o.Features.Enabled(features.EnableAlphaWebhookCertificateRotation) {
+		return nil
+	}
+	return []ProviderHooksOption{WithServerCertificateValidity(o.WebhookCertificateValidity)}
+}
+
+// functionHooksOptions returns the options for function runtime hooks.
+func functionHooksOptions(o controller.Options) []FunctionHooksOption {
+	if !o.Features.Enabled(features.EnableAlphaFunctionAutoscaling) {
+		return nil
+	}
+	return []FunctionHooksOption{WithHorizontalPodAutoscaling()}
+}
+
+// parserBackend returns the parser backend shared by all package kinds.
+func parserBackend(f xpkg.Fetcher, o controller.Options) parser.Backend {
+	var b parser.Backend = NewImageBackend(f, WithDefaultRegistry(o.DefaultRegistry))
+	if o.Features.Enabled(features.EnableAlphaLocalPackages) {
+		b = NewLocalBackend(xpkg.NewLocalPackages(o.LocalPackagesDir), b)
+	}
+	return b
+}
+
 // SetupConfigurationRevision adds a controller that reconciles ConfigurationRevisions.
 func SetupConfigurationRevision(mgr ctrl.Manager, o controller.Options) error {
 	name := "packages/" + strings.ToLower(v1.ConfigurationRevisionGroupKind)
 	nr := func() v1.PackageRevision { return &v1.ConfigurationRevision{} }
+	nrl := func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} }
 
 	cs, err := kubernetes.NewForConfig(mgr.GetConfig())
 	if err != nil {
@@ -357,19 +443,26 @@ func SetupConfigurationRevision(mgr ctrl.Manager, o controller.Options) error {
 		return errors.Wrap(err, errCannotBuildFetcher)
 	}
 
+	b := parserBackend(f, o)
+	if o.Features.Enabled(features.EnableAlphaConfigMapPackages) {
+		b = NewConfigMapBackend(mgr.GetAPIReader(), o.Namespace, b)
+	}
+
 	r := NewReconciler(mgr,
 		WithCache(o.Cache),
 		WithDependencyManager(NewPackageDependencyManager(mgr.GetClient(), dag.NewMapDag, v1beta1.ConfigurationPackageType)),
 		WithNewPackageRevisionFn(nr),
+		WithNewPackageRevisionListFn(nrl),
 		WithEstablisher(NewAPIEstablisher(mgr.GetClient(), o.Namespace)),
 		WithParser(parser.New(metaScheme, objScheme)),
-		WithParserBackend(NewImageBackend(f, WithDefaultRegistry(o.DefaultRegistry))),
+		WithParserBackend(b),
 		WithLinter(xpkg.NewConfigurationLinter()),
 		WithLogger(o.Logger.WithValues("controller", name)),
 		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
 		WithNamespace(o.Namespace),
 		WithServiceAccount(o.ServiceAccount),
 		WithFeatureFlags(o.Features),
+		WithLicenseAllowlist(o.LicenseAllowlist),
 	)
 
 	return ctrl.NewControllerManagedBy(mgr).
@@ -383,6 +476,7 @@ func SetupConfigurationRevision(mgr ctrl.Manager, o controller.Options) error {
 func SetupFunctionRevision(mgr ctrl.Manager, o controller.Options) error {
 	name := "packages/" + strings.ToLower(v1beta1.FunctionRevisionGroupKind)
 	nr := func() v1.PackageRevision { return &v1beta1.FunctionRevision{} }
+	nrl := func() v1.PackageRevisionList { return &v1beta1.FunctionRevisionList{} }
 
 	clientset, err := kubernetes.NewForConfig(mgr.GetConfig())
 	if err != nil {
@@ -418,18 +512,27 @@ func SetupFunctionRevision(mgr ctrl.Manager, o controller.Options) error {
 		WithDependencyManager(NewPackageDependencyManager(mgr.GetClient(), dag.NewMapDag, v1beta1.FunctionPackageType)),
 		WithEstablisher(NewAPIEstablisher(mgr.GetClient(), o.Namespace)),
 		WithNewPackageRevisionFn(nr),
+		WithNewPackageRevisionListFn(nrl),
 		WithParser(parser.New(metaScheme, objScheme)),
-		WithParserBackend(NewImageBackend(fetcher, WithDefaultRegistry(o.DefaultRegistry))),
+		WithParserBackend(parserBackend(fetcher, o)),
 		WithLinter(xpkg.NewFunctionLinter()),
 		WithLogger(o.Logger.WithValues("controller", name)),
 		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
 		WithNamespace(o.Namespace),
 		WithServiceAccount(o.ServiceAccount),
 		WithFeatureFlags(o.Features),
+		WithLicenseAllowlist(o.LicenseAllowlist),
+	}
+
+	if o.Features.Enabled(features.EnableAlphaCRDUpgradeChecks) {
+		ro = append(ro, WithUpgradeChecker(NewCRDUpgradeChecker(mgr.GetClient())))
 	}
 
 	if o.PackageRuntime == controller.PackageRuntimeDeployment {
-		ro = append(ro, WithRuntimeHooks(NewFunctionHooks(mgr.GetClient(), o.DefaultRegistry)))
+		ro = append(ro,
+			WithRuntimeHooks(NewFunctionHooks(mgr.GetClient(), o.DefaultRegistry, functionHooksOptions(o)...)),
+			WithRuntimeHealthChecker(NewDeploymentHealthChecker(mgr.GetAPIReader())),
+		)
 
 		if o.Features.Enabled(features.EnableBetaDeploymentRuntimeConfigs) {
 			cb = cb.Watches(&v1beta1.DeploymentRuntimeConfig{}, &EnqueueRequestForReferencingFunctionRevisions{
@@ -579,7 +682,9 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 			return reconcile.Result{}, err
 		}
 
-		if len(pr.GetObjects()) > 0 {
+		// We don't take this shortcut while the revision's CRDs are unsafe to
+		// upgrade, so that we notice when they become safe.
+		if len(pr.GetObjects()) > 0 && pr.GetCondition(v1.TypeUpgradeSafe).Status != corev1.ConditionFalse {
 			// Note(turkenh): If the revision is inactive we don't need to
 			// fetch/parse the package again, so we can report success and return
 			// here. The only exception is that revision NOT having references
@@ -662,6 +767,14 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	if rc == nil {
 		// Initialize parser backend to obtain package contents.
 		imgrc, err := r.backend.Init(ctx, PackageRevision(pr))
+		if wait, ok := xpkg.RateLimited(err); ok {
+			// We don't return an error, which would requeue us with a short
+			// backoff. We wait until the registry is likely to serve us again.
+			err = errors.Wrap(err, errInitParserBackend)
+			pr.SetConditions(v1.RegistryRateLimited().WithMessage(err.Error()))
+			r.record.Event(pr, event.Warning(reasonParse, err))
+			return reconcile.Result{RequeueAfter: wait}, errors.Wrap(r.client.Status().Update(ctx, pr), errUpdateStatus)
+		}
 		if err != nil {
 			err = errors.Wrap(err, errInitParserBackend)
 			pr.SetConditions(v1.Unhealthy().WithMessage(err.Error()))
@@ -777,6 +890,21 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		}
 	}
 
+	// Check the package's license if only some licenses are allowed.
+	if len(r.licenses) > 0 {
+		if err := xpkg.PackageLicenseAllowed(r.licenses)(pkgMeta); err != nil {
+			err = errors.Wrap(err, errLicenseNotAllowed)
+			pr.SetConditions(v1.Unhealthy().WithMessage(err.Error()))
+
+			r.record.Event(pr, event.Warning(reasonLint, err))
+
+			// No need to requeue if the license isn't allowed. The
+			// package will need to be updated, or the allowlist
+			// changed (which restarts Crossplane).
+			return reconcile.Result{Requeue: false}, errors.Wrap(r.client.Status().Update(ctx, pr), errUpdateStatus)
+		}
+	}
+
 	// Check status of package dependencies unless package specifies to skip
 	// resolution.
 	if pr.GetSkipDependencyResolution() != nil && !*pr.GetSkipDependencyResolution() {
@@ -797,6 +925,45 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		}
 	}
 
+	// Check whether the revision's CRDs can safely replace the installed CRDs
+	// before it establishes control of them. Inactive revisions only record
+	// the result, which the package manager uses to decide whether to
+	// activate them.
+	recheck := time.Duration(0)
+	if r.upgrades != nil {
+		managed, _ := partitionExternallyManaged(pkg.GetObjects(), pr)
+		unsafe, err := r.upgrades.CheckUpgrade(ctx, managed)
+		if err != nil {
+			err = errors.Wrap(err, errCheckUpgrade)
+			pr.SetConditions(v1.UnknownHealth().WithMessage(err.Error()))
+			_ = r.client.Status().Update(ctx, pr)
+
+			r.record.Event(pr, event.Warning(reasonUpgrade, err))
+
+			return reconcile.Result{}, err
+		}
+
+		switch {
+		case len(unsafe) == 0:
+			pr.SetConditions(v1.UpgradeSafe())
+		case v1.UnsafeUpgradeAllowed(pr):
+			pr.SetConditions(v1.UpgradeOverridden().WithMessage(fmt.Sprintf("Unsafe CRD upgrade allowed by the %s annotation: %s", v1.AnnotationAllowUnsafeUpgrade, strings.Join(unsafe, "; "))))
+		default:
+			msg := fmt.Sprintf("Package CRDs are unsafe to upgrade: %s. Set the %s annotation to \"true\" on the package to upgrade them anyway.", strings.Join(unsafe, "; "), v1.AnnotationAllowUnsafeUpgrade)
+			pr.SetConditions(v1.UpgradeUnsafe().WithMessage(msg))
+			recheck = upgradeCheckWait
+
+			if pr.GetDesiredState() == v1.PackageRevisionActive {
+				pr.SetConditions(v1.Unhealthy().WithMessage(msg))
+				r.record.Event(pr, event.Warning(reasonUpgrade, errors.New(msg)))
+
+				// Existing objects may change so that the upgrade becomes
+				// safe, so we check again periodically.
+				return reconcile.Result{RequeueAfter: recheck}, errors.Wrap(r.client.Status().Update(ctx, pr), errUpdateStatus)
+			}
+		}
+	}
+
 	if r.runtimeHook != nil {
 		pwr := pr.(v1.PackageRevisionWithRuntime)
 		if err := r.runtimeHook.Pre(ctx, pkgMeta, pwr, runtimeManifestBuilder); err != nil {
@@ -815,7 +982,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	}
 
 	// Establish control or ownership of objects.
-	refs, err := r.objects.Establish(ctx, pkg.GetObjects(), pr, pr.GetDesiredState() == v1.PackageRevisionActive)
+	refs, changed, err := r.objects.Establish(ctx, pkg.GetObjects(), pr, pr.GetDesiredState() == v1.PackageRevisionActive)
 	if err != nil {
 		if kerrors.IsConflict(err) {
 			return reconcile.Result{Requeue: true}, nil
@@ -847,8 +1014,32 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	})
 	pr.SetObjects(refs)
 
+	// Summarize what this revision changed the first time it becomes active,
+	// which is when it replaces the previously active revision.
+	if r.newPackageRevisionList != nil && pr.GetDesiredState() == v1.PackageRevisionActive && pr.GetChanges() == nil {
+		c, err := r.summarizeChanges(ctx, pr, changed)
+		if err != nil {
+			// Failing to summarize changes shouldn't stop us from
+			// finishing installing the package.
+			log.Debug(errSummarizeChanges, "error", err)
+			r.record.Event(pr, event.Warning(reasonChanges, errors.Wrap(err, errSummarizeChanges)))
+		}
+		if c != nil {
+			pr.SetChanges(c)
+			r.record.Event(pr, event.Normal(reasonChanges, c.String()))
+		}
+	}
+
 	if r.runtimeHook != nil {
-		if err := r.runtimeHook.Post(ctx, pkgMeta, pr.(v1.PackageRevisionWithRuntime), runtimeManifestBuilder); err != nil {
+		err := r.runtimeHook.Post(ctx, pkgMeta, pr.(v1.PackageRevisionWithRuntime), runtimeManifestBuilder)
+
+		// We check the runtime's health even if the post hook failed, since
+		// an unavailable runtime is a common reason for it to fail.
+		if r.runtimeHealth != nil && pr.GetDesiredState() == v1.PackageRevisionActive {
+			pr.SetConditions(r.runtimeHealth.RuntimeHealth(ctx, pr.(v1.PackageRevisionWithRuntime), runtimeManifestBuilder))
+		}
+
+		if err != nil {
 			if kerrors.IsConflict(err) {
 				return reconcile.Result{Requeue: true}, nil
 			}
@@ -869,7 +1060,74 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		r.record.Event(pr, event.Normal(reasonSync, "Successfully configured package revision"))
 	}
 	pr.SetConditions(v1.Healthy())
-	return reconcile.Result{Requeue: false}, errors.Wrap(r.client.Status().Update(ctx, pr), errUpdateStatus)
+	return reconcile.Result{RequeueAfter: recheck}, errors.Wrap(r.client.Status().Update(ctx, pr), errUpdateStatus)
+}
+
+// summarizeChanges summarizes how the supplied package revision changed the
+// objects installed by its package, compared to the revision it replaced. The
+// supplied changed objects are those whose spec establishing the revision
+// changed. It returns nil if the revision didn't replace another.
+func (r *Reconciler) summarizeChanges(ctx context.Context, pr v1.PackageRevision, changed []xpv1.TypedReference) (*v1.RevisionChanges, error) {
+	parent := pr.GetLabels()[v1.LabelParentPackage]
+	if parent == "" {
+		return nil, nil
+	}
+
+	l := r.newPackageRevisionList()
+	if err := r.client.List(ctx, l, client.MatchingLabels{v1.LabelParentPackage: parent}); err != nil {
+		return nil, errors.Wrap(err, errListRevisions)
+	}
+
+	// The revision this revision replaced is the newest revision older than
+	// it.
+	var prev v1.PackageRevision
+	for _, rev := range l.GetRevisions() {
+		if rev.GetRevision() >= pr.GetRevision() {
+			continue
+		}
+		if prev == nil || rev.GetRevision() > prev.GetRevision() {
+			prev = rev
+		}
+	}
+	if prev == nil {
+		return nil, nil
+	}
+
+	c := &v1.RevisionChanges{PreviousRevision: prev.GetName()}
+
+	before := map[string]bool{}
+	for _, ref := range prev.GetObjects() {
+		before[objectIdentifier(ref)] = true
+	}
+	after := map[string]bool{}
+	for _, ref := range pr.GetObjects() {
+		after[objectIdentifier(ref)] = true
+		if !before[objectIdentifier(ref)] {
+			c.Added = append(c.Added, ref)
+		}
+	}
+	for _, ref := range changed {
+		if before[objectIdentifier(ref)] {
+			c.Changed = append(c.Changed, ref)
+		}
+	}
+	for _, ref := range prev.GetObjects() {
+		if !after[objectIdentifier(ref)] {
+			c.Removed = append(c.Removed, ref)
+		}
+	}
+
+	for _, refs := range [][]xpv1.TypedReference{c.Added, c.Changed, c.Removed} {
+		sort.Slice(refs, func(i, j int) bool { return objectIdentifier(refs[i]) < objectIdentifier(refs[j]) })
+	}
+	return c, nil
+}
+
+// objectIdentifier identifies an object regardless of its version or UID, so
+// that an object a new revision installs at a different version isn't treated
+// as a different object.
+func objectIdentifier(ref xpv1.TypedReference) string {
+	return strings.Join([]string{ref.GroupVersionKind().GroupKind().String(), ref.Name}, "/")
 }
 
 func (r *Reconciler) deactivateRevision(ctx context.Context, pr v1.PackageRevision, runtimeManifestBuilder ManifestBuilder) error {
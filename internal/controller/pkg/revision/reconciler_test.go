@@ -21,6 +21,7 @@ import (
 	"context"
 	"io"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	corev1 "k8s.io/api/core/v1"
@@ -61,7 +62,7 @@ func (e *ErrBackend) Init(_ context.Context, _ ...parser.BackendOption) (io.Read
 var _ Establisher = &MockEstablisher{}
 
 type MockEstablisher struct {
-	MockEstablish  func() ([]xpv1.TypedReference, error)
+	MockEstablish  func() ([]xpv1.TypedReference, []xpv1.TypedReference, error)
 	MockRelinquish func() error
 }
 
@@ -72,15 +73,15 @@ func NewMockEstablisher() *MockEstablisher {
 	}
 }
 
-func NewMockEstablishFn(refs []xpv1.TypedReference, err error) func() ([]xpv1.TypedReference, error) {
-	return func() ([]xpv1.TypedReference, error) { return refs, err }
+func NewMockEstablishFn(refs []xpv1.TypedReference, err error) func() ([]xpv1.TypedReference, []xpv1.TypedReference, error) {
+	return func() ([]xpv1.TypedReference, []xpv1.TypedReference, error) { return refs, nil, err }
 }
 
 func NewMockRelinquishFn(err error) func() error {
 	return func() error { return err }
 }
 
-func (e *MockEstablisher) Establish(context.Context, []runtime.Object, v1.PackageRevision, bool) ([]xpv1.TypedReference, error) {
+func (e *MockEstablisher) Establish(context.Context, []runtime.Object, v1.PackageRevision, bool) ([]xpv1.TypedReference, []xpv1.TypedReference, error) {
 	return e.MockEstablish()
 }
 
@@ -175,6 +176,7 @@ spec:
 
 func TestReconcile(t *testing.T) {
 	errBoom := errors.New("boom")
+	errRateLimited := &xpkg.RateLimitedError{Registry: "index.docker.io", RetryAfter: 2 * time.Minute}
 	testLog := logging.NewLogrLogger(zap.New(zap.UseDevMode(true), zap.WriteTo(io.Discard)).WithName("testlog"))
 	now := metav1.Now()
 	pullPolicy := corev1.PullNever
@@ -523,6 +525,47 @@ func TestReconcile(t *testing.T) {
 				err: errors.Wrap(errBoom, errInitParserBackend),
 			},
 		},
+		"RegistryRateLimited": {
+			reason: "We should wait until the registry is likely to serve us again if it rate limits initializing the parser backend.",
+			args: args{
+				mgr: &fake.Manager{},
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: []ReconcilerOption{
+					WithNewPackageRevisionFn(func() v1.PackageRevision { return &v1.ConfigurationRevision{} }),
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								pr := o.(*v1.ConfigurationRevision)
+								pr.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+								pr.SetDesiredState(v1.PackageRevisionActive)
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.ConfigurationRevision{}
+								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+								want.SetDesiredState(v1.PackageRevisionActive)
+								want.SetConditions(v1.RegistryRateLimited().WithMessage(errors.Wrap(errRateLimited, errInitParserBackend).Error()))
+
+								if diff := cmp.Diff(want, o); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+					}),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+						return nil
+					}}),
+					WithCache(&xpkgfake.MockCache{
+						MockHas: xpkgfake.NewMockCacheHasFn(false),
+					}),
+					WithParserBackend(&ErrBackend{err: errRateLimited}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: 2 * time.Minute},
+			},
+		},
 		"ErrParseFromCache": {
 			reason: "We should return an error if fail to parse the package from the cache.",
 			args: args{
@@ -1159,6 +1202,95 @@ func TestReconcile(t *testing.T) {
 				r: reconcile.Result{Requeue: false},
 			},
 		},
+		"SuccessfulActiveRevisionSummarizeChanges": {
+			reason: "An active revision should summarize how it changed the objects installed by the revision it replaced.",
+			args: args{
+				mgr: &fake.Manager{},
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: []ReconcilerOption{
+					WithNewPackageRevisionFn(func() v1.PackageRevision { return &v1.ConfigurationRevision{} }),
+					WithNewPackageRevisionListFn(func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} }),
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								pr := o.(*v1.ConfigurationRevision)
+								pr.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+								pr.SetName("cool-2")
+								pr.SetLabels(map[string]string{v1.LabelParentPackage: "cool"})
+								pr.SetRevision(2)
+								pr.SetDesiredState(v1.PackageRevisionActive)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								older := v1.ConfigurationRevision{}
+								older.SetName("cool-0")
+								older.SetRevision(0)
+								prev := v1.ConfigurationRevision{}
+								prev.SetName("cool-1")
+								prev.SetRevision(1)
+								prev.SetObjects([]xpv1.TypedReference{
+									{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "changed"},
+									{APIVersion: "apiextensions.crossplane.io/v1", Kind: "Composition", Name: "removed"},
+									{APIVersion: "apiextensions.crossplane.io/v1", Kind: "Composition", Name: "unchanged"},
+								})
+								l.Items = []v1.ConfigurationRevision{older, prev}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.RevisionChanges{
+									PreviousRevision: "cool-1",
+									Added: []xpv1.TypedReference{
+										{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "added"},
+									},
+									Changed: []xpv1.TypedReference{
+										{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "changed"},
+									},
+									Removed: []xpv1.TypedReference{
+										{APIVersion: "apiextensions.crossplane.io/v1", Kind: "Composition", Name: "removed"},
+									},
+								}
+								if diff := cmp.Diff(want, o.(*v1.ConfigurationRevision).GetChanges()); diff != "" {
+									t.Errorf("-want changes, +got changes:\n%s", diff)
+								}
+								return nil
+							}),
+							MockUpdate: test.NewMockUpdateFn(nil),
+						},
+					}),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+						return nil
+					}}),
+					WithEstablisher(&MockEstablisher{
+						MockEstablish: func() ([]xpv1.TypedReference, []xpv1.TypedReference, error) {
+							refs := []xpv1.TypedReference{
+								{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "added"},
+								{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "changed"},
+								{APIVersion: "apiextensions.crossplane.io/v1", Kind: "Composition", Name: "unchanged"},
+							}
+							changed := []xpv1.TypedReference{
+								{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "changed"},
+							}
+							return refs, changed, nil
+						},
+					}),
+					WithParser(parser.New(metaScheme, objScheme)),
+					WithParserBackend(parser.NewEchoBackend(string(providerBytes))),
+					WithCache(&xpkgfake.MockCache{
+						MockHas: xpkgfake.NewMockCacheHasFn(false),
+						MockStore: func(s string, rc io.ReadCloser) error {
+							_, err := io.ReadAll(rc)
+							return err
+						},
+					}),
+					WithLinter(&MockLinter{MockLint: NewMockLintFn(nil)}),
+					WithVersioner(&verfake.MockVersioner{MockInConstraints: verfake.NewMockInConstraintsFn(true, nil)}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
 		"SuccessfulActiveRevisionIgnoreConstraints": {
 			reason: "An active revision with incompatible Crossplane version should install successfully when constraints ignored.",
 			args: args{
@@ -1288,6 +1420,64 @@ func TestReconcile(t *testing.T) {
 				err: errors.Wrap(errBoom, errEstablishControl),
 			},
 		},
+		"UnsafeUpgradeActiveRevision": {
+			reason: "An active revision whose CRDs are unsafe to upgrade shouldn't establish control of its resources.",
+			args: args{
+				mgr: &fake.Manager{},
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: []ReconcilerOption{
+					WithNewPackageRevisionFn(func() v1.PackageRevision { return &v1.ProviderRevision{} }),
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								pr := o.(*v1.ProviderRevision)
+								pr.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
+								pr.SetDesiredState(v1.PackageRevisionActive)
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.ProviderRevision{}
+								want.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
+								want.SetDesiredState(v1.PackageRevisionActive)
+								want.SetAnnotations(map[string]string{"author": "crossplane"})
+								want.SetConditions(v1.UpgradeUnsafe().WithMessage("Package CRDs are unsafe to upgrade: CRD widgets.example.org changes scope from Namespaced to Cluster. Set the pkg.crossplane.io/allow-unsafe-upgrade annotation to \"true\" on the package to upgrade them anyway."))
+								want.SetConditions(v1.Unhealthy().WithMessage("Package CRDs are unsafe to upgrade: CRD widgets.example.org changes scope from Namespaced to Cluster. Set the pkg.crossplane.io/allow-unsafe-upgrade annotation to \"true\" on the package to upgrade them anyway."))
+
+								if diff := cmp.Diff(want, o); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+							MockDelete: test.NewMockDeleteFn(nil),
+							MockUpdate: test.NewMockUpdateFn(nil),
+						},
+					}),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+						return nil
+					}}),
+					WithUpgradeChecker(UpgradeCheckerFn(func(_ context.Context, _ []runtime.Object) ([]string, error) {
+						return []string{"CRD widgets.example.org changes scope from Namespaced to Cluster"}, nil
+					})),
+					WithEstablisher(&MockEstablisher{
+						MockEstablish: NewMockEstablishFn(nil, errBoom),
+					}),
+					WithParser(parser.New(metaScheme, objScheme)),
+					WithParserBackend(parser.NewEchoBackend(string(providerBytes))),
+					WithCache(&xpkgfake.MockCache{
+						MockHas: xpkgfake.NewMockCacheHasFn(false),
+						MockStore: func(s string, rc io.ReadCloser) error {
+							_, err := io.ReadAll(rc)
+							return err
+						},
+					}),
+					WithLinter(&MockLinter{MockLint: NewMockLintFn(nil)}),
+					WithVersioner(&verfake.MockVersioner{MockInConstraints: verfake.NewMockInConstraintsFn(true, nil)}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: upgradeCheckWait},
+			},
+		},
 		"ErrEstablishInactiveRevision": {
 			reason: "An inactive revision that fails to establish ownership should return an error.",
 			args: args{
@@ -19,6 +19,7 @@ package revision
 import (
 	"golang.org/x/net/context"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -78,6 +79,9 @@ type ManifestBuilder interface {
 	TLSClientSecret() *corev1.Secret
 	// TLSServerSecret builds and returns the TLS server secret manifest.
 	TLSServerSecret() *corev1.Secret
+	// HorizontalPodAutoscaler builds and returns the horizontal pod autoscaler
+	// manifest for the supplied deployment.
+	HorizontalPodAutoscaler(d *appsv1.Deployment) *autoscalingv2.HorizontalPodAutoscaler
 }
 
 // A RuntimeHooks performs runtime operations before and after a revision
@@ -319,6 +323,43 @@ func (b *RuntimeManifestBuilder) TLSServerSecret() *corev1.Secret {
 	}
 }
 
+// HorizontalPodAutoscaler builds and returns the HorizontalPodAutoscaler
+// manifest that scales the supplied Deployment. It returns nil if the runtime
+// config doesn't have a HorizontalPodAutoscaler template.
+func (b *RuntimeManifestBuilder) HorizontalPodAutoscaler(d *appsv1.Deployment) *autoscalingv2.HorizontalPodAutoscaler {
+	if b.runtimeConfig == nil || b.runtimeConfig.Spec.HorizontalPodAutoscalerTemplate == nil {
+		return nil
+	}
+	tmpl := b.runtimeConfig.Spec.HorizontalPodAutoscalerTemplate
+
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			// The HorizontalPodAutoscaler is always named after the Deployment
+			// so that we can find and delete it without the runtime config.
+			Name:            d.GetName(),
+			Namespace:       b.namespace,
+			OwnerReferences: []metav1.OwnerReference{meta.AsController(meta.TypedReferenceTo(b.revision, b.revision.GetObjectKind().GroupVersionKind()))},
+		},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+				APIVersion: appsv1.SchemeGroupVersion.String(),
+				Kind:       "Deployment",
+				Name:       d.GetName(),
+			},
+			MinReplicas: tmpl.Spec.MinReplicas,
+			MaxReplicas: tmpl.Spec.MaxReplicas,
+			Metrics:     tmpl.Spec.Metrics,
+			Behavior:    tmpl.Spec.Behavior,
+		},
+	}
+	if m := tmpl.Metadata; m != nil {
+		hpa.SetAnnotations(m.Annotations)
+		hpa.SetLabels(m.Labels)
+	}
+
+	return hpa
+}
+
 func (b *RuntimeManifestBuilder) podSelectors() map[string]string {
 	return map[string]string{
 		"pkg.crossplane.io/revision":           b.revision.GetName(),
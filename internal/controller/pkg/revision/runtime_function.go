@@ -22,6 +22,7 @@ import (
 
 	"github.com/google/go-containerregistry/pkg/name"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -46,6 +47,8 @@ const (
 	errApplyFunctionSecret                    = "cannot apply function package secret"
 	errApplyFunctionSA                        = "cannot apply function package service account"
 	errApplyFunctionService                   = "cannot apply function package service"
+	errApplyFunctionHPA                       = "cannot apply function package horizontal pod autoscaler"
+	errDeleteFunctionHPA                      = "cannot delete function package horizontal pod autoscaler"
 	errFmtUnavailableFunctionDeployment       = "function package deployment is unavailable with message: %s"
 	errNoAvailableConditionFunctionDeployment = "function package deployment has no condition of type \"Available\" yet"
 	errParseFunctionImage                     = "cannot parse function package image"
@@ -55,17 +58,39 @@ const (
 type FunctionHooks struct {
 	client          resource.ClientApplicator
 	defaultRegistry string
+
+	// autoscaling is true if function Deployments may be scaled by a
+	// HorizontalPodAutoscaler configured by their runtime config.
+	autoscaling bool
+}
+
+// A FunctionHooksOption configures FunctionHooks.
+type FunctionHooksOption func(*FunctionHooks)
+
+// WithHorizontalPodAutoscaling configures FunctionHooks to scale function
+// Deployments using a HorizontalPodAutoscaler when their runtime config has a
+// HorizontalPodAutoscaler template.
+func WithHorizontalPodAutoscaling() FunctionHooksOption {
+	return func(h *FunctionHooks) {
+		h.autoscaling = true
+	}
 }
 
 // NewFunctionHooks returns a new FunctionHooks.
-func NewFunctionHooks(client client.Client, defaultRegistry string) *FunctionHooks {
-	return &FunctionHooks{
+func NewFunctionHooks(client client.Client, defaultRegistry string, opts ...FunctionHooksOption) *FunctionHooks {
+	h := &FunctionHooks{
 		client: resource.ClientApplicator{
 			Client:     client,
 			Applicator: resource.NewAPIPatchingApplicator(client),
 		},
 		defaultRegistry: defaultRegistry,
 	}
+
+	for _, fn := range opts {
+		fn(h)
+	}
+
+	return h
 }
 
 // Pre performs operations meant to happen before establishing objects.
@@ -128,6 +153,17 @@ func (h *FunctionHooks) Post(ctx context.Context, pkg runtime.Object, pr v1.Pack
 	}
 
 	d := build.Deployment(sa.Name, functionDeploymentOverrides(image)...)
+
+	var hpa *autoscalingv2.HorizontalPodAutoscaler
+	if h.autoscaling {
+		hpa = build.HorizontalPodAutoscaler(d)
+	}
+	if hpa != nil {
+		// The HorizontalPodAutoscaler manages the Deployment's replicas. We
+		// omit them so that applying the Deployment doesn't reset them.
+		d.Spec.Replicas = nil
+	}
+
 	// Create/Apply the SA only if the deployment references it.
 	// This is to avoid creating a SA that is NOT used by the deployment when
 	// the SA is managed externally by the user and configured by setting
@@ -141,6 +177,11 @@ func (h *FunctionHooks) Post(ctx context.Context, pkg runtime.Object, pr v1.Pack
 	if err := h.client.Apply(ctx, d); err != nil {
 		return errors.Wrap(err, errApplyFunctionDeployment)
 	}
+	if h.autoscaling {
+		if err := h.applyHorizontalPodAutoscaler(ctx, d, hpa); err != nil {
+			return err
+		}
+	}
 
 	for _, c := range d.Status.Conditions {
 		if c.Type == appsv1.DeploymentAvailable {
@@ -160,9 +201,15 @@ func (h *FunctionHooks) Deactivate(ctx context.Context, _ v1.PackageRevisionWith
 	// Different from the Post runtimeHook, we don't need to pass the
 	// "functionDeploymentOverrides()" here, because we're only interested
 	// in the name and namespace of the deployment to delete it.
-	if err := h.client.Delete(ctx, build.Deployment(sa.Name)); resource.IgnoreNotFound(err) != nil {
+	d := build.Deployment(sa.Name)
+	if err := h.client.Delete(ctx, d); resource.IgnoreNotFound(err) != nil {
 		return errors.Wrap(err, errDeleteFunctionDeployment)
 	}
+	if h.autoscaling {
+		if err := h.client.Delete(ctx, horizontalPodAutoscalerFor(d)); resource.IgnoreNotFound(err) != nil {
+			return errors.Wrap(err, errDeleteFunctionHPA)
+		}
+	}
 
 	// NOTE(turkenh): We don't delete the service account here because it might
 	// be used by other package revisions, e.g. user might have specified a
@@ -176,6 +223,27 @@ func (h *FunctionHooks) Deactivate(ctx context.Context, _ v1.PackageRevisionWith
 	return nil
 }
 
+// applyHorizontalPodAutoscaler applies the supplied HorizontalPodAutoscaler. If
+// it's nil the runtime config doesn't want the Deployment to be autoscaled, so
+// we delete any HorizontalPodAutoscaler we previously created for it.
+func (h *FunctionHooks) applyHorizontalPodAutoscaler(ctx context.Context, d *appsv1.Deployment, hpa *autoscalingv2.HorizontalPodAutoscaler) error {
+	if hpa == nil {
+		return errors.Wrap(resource.IgnoreNotFound(h.client.Delete(ctx, horizontalPodAutoscalerFor(d))), errDeleteFunctionHPA)
+	}
+	return errors.Wrap(h.client.Apply(ctx, hpa), errApplyFunctionHPA)
+}
+
+// horizontalPodAutoscalerFor returns a HorizontalPodAutoscaler with the name
+// and namespace of the one that scales the supplied Deployment.
+func horizontalPodAutoscalerFor(d *appsv1.Deployment) *autoscalingv2.HorizontalPodAutoscaler {
+	return &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      d.GetName(),
+			Namespace: d.GetNamespace(),
+		},
+	}
+}
+
 func functionDeploymentOverrides(image string) []DeploymentOverride {
 	do := []DeploymentOverride{
 		DeploymentRuntimeWithAdditionalPorts([]corev1.ContainerPort{
@@ -188,6 +256,15 @@ func functionDeploymentOverrides(image string) []DeploymentOverride {
 
 	do = append(do, DeploymentRuntimeWithOptionalImage(image))
 
+	// Functions may run third-party code, so we constrain what they can do at
+	// the syscall level unless the runtime config says otherwise. A runtime
+	// config can opt out by explicitly setting the runtime container's
+	// seccompProfile or capabilities, e.g. to a Localhost profile.
+	do = append(do,
+		DeploymentRuntimeWithOptionalSeccompProfile(&corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}),
+		DeploymentRuntimeWithOptionalCapabilities(&corev1.Capabilities{Drop: []corev1.Capability{"ALL"}}),
+	)
+
 	return do
 }
 
@@ -208,6 +285,12 @@ func getFunctionImage(fm *pkgmetav1beta1.Function, pr v1.PackageRevisionWithRunt
 	if fm.Spec.Image != nil {
 		image = *fm.Spec.Image
 	}
+	// A package sourced from a ConfigMap or a local package has no image to
+	// run. Its runtime image must instead be set by its metadata or its
+	// DeploymentRuntimeConfig.
+	if !xpkg.IsRegistrySource(image) {
+		return "", nil
+	}
 	ref, err := name.ParseReference(image, name.WithDefaultRegistry(defaultRegistry))
 	if err != nil {
 		return "", errors.Wrap(err, errParseFunctionImage)
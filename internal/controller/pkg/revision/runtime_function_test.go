@@ -23,6 +23,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -133,6 +134,7 @@ func TestFunctionPreHook(t *testing.T) {
 func TestFunctionPostHook(t *testing.T) {
 	type args struct {
 		client    client.Client
+		opts      []FunctionHooksOption
 		pkg       runtime.Object
 		rev       v1.PackageRevisionWithRuntime
 		manifests ManifestBuilder
@@ -463,11 +465,172 @@ func TestFunctionPostHook(t *testing.T) {
 				},
 			},
 		},
+		"ErrApplyHorizontalPodAutoscaler": {
+			reason: "Should return error if we fail to apply the horizontal pod autoscaler for active function revision.",
+			args: args{
+				opts: []FunctionHooksOption{WithHorizontalPodAutoscaling()},
+				pkg:  &pkgmetav1beta1.Function{},
+				rev: &v1beta1.FunctionRevision{
+					Spec: v1beta1.FunctionRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							Package:      functionImage,
+							DesiredState: v1.PackageRevisionActive,
+						},
+					},
+				},
+				manifests: &MockManifestBuilder{
+					ServiceAccountFn: func(overrides ...ServiceAccountOverride) *corev1.ServiceAccount {
+						return &corev1.ServiceAccount{}
+					},
+					DeploymentFn: func(serviceAccount string, overrides ...DeploymentOverride) *appsv1.Deployment {
+						return &appsv1.Deployment{}
+					},
+					HorizontalPodAutoscalerFn: func(d *appsv1.Deployment) *autoscalingv2.HorizontalPodAutoscaler {
+						return &autoscalingv2.HorizontalPodAutoscaler{}
+					},
+				},
+				client: &test.MockClient{
+					MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+						return nil
+					},
+					MockPatch: func(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+						if _, ok := obj.(*autoscalingv2.HorizontalPodAutoscaler); ok {
+							return errBoom
+						}
+						return nil
+					},
+				},
+			},
+			want: want{
+				rev: &v1beta1.FunctionRevision{
+					Spec: v1beta1.FunctionRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							Package:      functionImage,
+							DesiredState: v1.PackageRevisionActive,
+						},
+					},
+				},
+				err: errors.Wrap(errors.Wrap(errBoom, "cannot patch object"), errApplyFunctionHPA),
+			},
+		},
+		"SuccessfulWithHorizontalPodAutoscaler": {
+			reason: "Should apply a horizontal pod autoscaler, and a deployment without replicas, when the runtime config has a horizontal pod autoscaler template.",
+			args: args{
+				opts: []FunctionHooksOption{WithHorizontalPodAutoscaling()},
+				pkg:  &pkgmetav1beta1.Function{},
+				rev: &v1beta1.FunctionRevision{
+					Spec: v1beta1.FunctionRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							Package:      functionImage,
+							DesiredState: v1.PackageRevisionActive,
+						},
+					},
+				},
+				manifests: &MockManifestBuilder{
+					ServiceAccountFn: func(overrides ...ServiceAccountOverride) *corev1.ServiceAccount {
+						return &corev1.ServiceAccount{}
+					},
+					DeploymentFn: func(serviceAccount string, overrides ...DeploymentOverride) *appsv1.Deployment {
+						return &appsv1.Deployment{Spec: appsv1.DeploymentSpec{Replicas: ptr.To[int32](1)}}
+					},
+					HorizontalPodAutoscalerFn: func(d *appsv1.Deployment) *autoscalingv2.HorizontalPodAutoscaler {
+						return &autoscalingv2.HorizontalPodAutoscaler{}
+					},
+				},
+				client: &test.MockClient{
+					MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+						return nil
+					},
+					MockPatch: func(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+						if d, ok := obj.(*appsv1.Deployment); ok {
+							if d.Spec.Replicas != nil {
+								t.Error("unexpected replicas in deployment scaled by a horizontal pod autoscaler")
+							}
+							d.Status.Conditions = []appsv1.DeploymentCondition{{
+								Type:   appsv1.DeploymentAvailable,
+								Status: corev1.ConditionTrue,
+							}}
+						}
+						return nil
+					},
+					MockDelete: func(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+						t.Errorf("unexpected call to delete %T", obj)
+						return nil
+					},
+				},
+			},
+			want: want{
+				rev: &v1beta1.FunctionRevision{
+					Spec: v1beta1.FunctionRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							Package:      functionImage,
+							DesiredState: v1.PackageRevisionActive,
+						},
+					},
+				},
+			},
+		},
+		"SuccessfulDeleteStaleHorizontalPodAutoscaler": {
+			reason: "Should delete the deployment's horizontal pod autoscaler when the runtime config has no horizontal pod autoscaler template.",
+			args: args{
+				opts: []FunctionHooksOption{WithHorizontalPodAutoscaling()},
+				pkg:  &pkgmetav1beta1.Function{},
+				rev: &v1beta1.FunctionRevision{
+					Spec: v1beta1.FunctionRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							Package:      functionImage,
+							DesiredState: v1.PackageRevisionActive,
+						},
+					},
+				},
+				manifests: &MockManifestBuilder{
+					ServiceAccountFn: func(overrides ...ServiceAccountOverride) *corev1.ServiceAccount {
+						return &corev1.ServiceAccount{}
+					},
+					DeploymentFn: func(serviceAccount string, overrides ...DeploymentOverride) *appsv1.Deployment {
+						return &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "some-deployment"}}
+					},
+					HorizontalPodAutoscalerFn: func(d *appsv1.Deployment) *autoscalingv2.HorizontalPodAutoscaler {
+						return nil
+					},
+				},
+				client: &test.MockClient{
+					MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+						return nil
+					},
+					MockPatch: func(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+						if d, ok := obj.(*appsv1.Deployment); ok {
+							d.Status.Conditions = []appsv1.DeploymentCondition{{
+								Type:   appsv1.DeploymentAvailable,
+								Status: corev1.ConditionTrue,
+							}}
+						}
+						return nil
+					},
+					MockDelete: func(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+						if _, ok := obj.(*autoscalingv2.HorizontalPodAutoscaler); !ok || obj.GetName() != "some-deployment" {
+							t.Errorf("unexpected call to delete %T %q", obj, obj.GetName())
+						}
+						return kerrors.NewNotFound(autoscalingv2.Resource("horizontalpodautoscaler"), obj.GetName())
+					},
+				},
+			},
+			want: want{
+				rev: &v1beta1.FunctionRevision{
+					Spec: v1beta1.FunctionRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							Package:      functionImage,
+							DesiredState: v1.PackageRevisionActive,
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			h := NewFunctionHooks(tc.args.client, xpkg.DefaultRegistry)
+			h := NewFunctionHooks(tc.args.client, xpkg.DefaultRegistry, tc.args.opts...)
 			err := h.Post(context.TODO(), tc.args.pkg, tc.args.rev, tc.args.manifests)
 
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
@@ -483,6 +646,7 @@ func TestFunctionPostHook(t *testing.T) {
 func TestFunctionDeactivateHook(t *testing.T) {
 	type args struct {
 		client    client.Client
+		opts      []FunctionHooksOption
 		rev       v1.PackageRevisionWithRuntime
 		manifests ManifestBuilder
 	}
@@ -567,11 +731,36 @@ func TestFunctionDeactivateHook(t *testing.T) {
 				},
 			},
 		},
+		"ErrDeleteHorizontalPodAutoscaler": {
+			reason: "Should return error if we fail to delete the horizontal pod autoscaler.",
+			args: args{
+				opts: []FunctionHooksOption{WithHorizontalPodAutoscaling()},
+				manifests: &MockManifestBuilder{
+					ServiceAccountFn: func(overrides ...ServiceAccountOverride) *corev1.ServiceAccount {
+						return &corev1.ServiceAccount{}
+					},
+					DeploymentFn: func(serviceAccount string, overrides ...DeploymentOverride) *appsv1.Deployment {
+						return &appsv1.Deployment{}
+					},
+				},
+				client: &test.MockClient{
+					MockDelete: func(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+						if _, ok := obj.(*autoscalingv2.HorizontalPodAutoscaler); ok {
+							return errBoom
+						}
+						return nil
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errDeleteFunctionHPA),
+			},
+		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			h := NewFunctionHooks(tc.args.client, xpkg.DefaultRegistry)
+			h := NewFunctionHooks(tc.args.client, xpkg.DefaultRegistry, tc.args.opts...)
 			err := h.Deactivate(context.TODO(), tc.args.rev, tc.args.manifests)
 
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
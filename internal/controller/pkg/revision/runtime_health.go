@@ -0,0 +1,165 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+const (
+	errGetRuntimeDeployment = "cannot get package runtime deployment"
+	errListRuntimePods      = "cannot list package runtime pods"
+	errParseRuntimeSelector = "cannot parse package runtime deployment selector"
+
+	msgNoRuntimeDeployment = "Package runtime deployment doesn't exist yet"
+)
+
+// Container waiting reasons that indicate a pod is failing, rather than just
+// starting.
+var failingContainerReasons = map[string]bool{
+	"CrashLoopBackOff":           true,
+	"ImagePullBackOff":           true,
+	"ErrImagePull":               true,
+	"InvalidImageName":           true,
+	"CreateContainerConfigError": true,
+	"CreateContainerError":       true,
+	"RunContainerError":          true,
+}
+
+// A RuntimeHealthChecker reports the health of a package revision's runtime.
+type RuntimeHealthChecker interface {
+	// RuntimeHealth returns a condition of type RuntimeHealthy that reflects
+	// the health of the supplied package revision's runtime.
+	RuntimeHealth(ctx context.Context, pr v1.PackageRevisionWithRuntime, build ManifestBuilder) xpv1.Condition
+}
+
+// A RuntimeHealthCheckerFn reports the health of a package revision's runtime.
+type RuntimeHealthCheckerFn func(ctx context.Context, pr v1.PackageRevisionWithRuntime, build ManifestBuilder) xpv1.Condition
+
+// RuntimeHealth returns a condition that reflects the health of the supplied
+// package revision's runtime.
+func (fn RuntimeHealthCheckerFn) RuntimeHealth(ctx context.Context, pr v1.PackageRevisionWithRuntime, build ManifestBuilder) xpv1.Condition {
+	return fn(ctx, pr, build)
+}
+
+// A DeploymentHealthChecker reports the health of a package revision's
+// runtime Deployment, taking into account both its replica availability and
+// whether its pods are failing.
+type DeploymentHealthChecker struct {
+	client client.Reader
+}
+
+// NewDeploymentHealthChecker returns a RuntimeHealthChecker that reads
+// Deployments and Pods using the supplied client. The client should not be
+// backed by a cache, to avoid caching every Pod in the cluster.
+func NewDeploymentHealthChecker(c client.Reader) *DeploymentHealthChecker {
+	return &DeploymentHealthChecker{client: c}
+}
+
+// RuntimeHealth returns a condition that reflects the health of the supplied
+// package revision's runtime Deployment.
+func (h *DeploymentHealthChecker) RuntimeHealth(ctx context.Context, _ v1.PackageRevisionWithRuntime, build ManifestBuilder) xpv1.Condition {
+	// Like the runtime hooks' Deactivate, we only need the deployment's name
+	// and namespace, so we don't need to pass any overrides.
+	want := build.Deployment(build.ServiceAccount().Name)
+
+	d := &appsv1.Deployment{}
+	if err := h.client.Get(ctx, types.NamespacedName{Namespace: want.GetNamespace(), Name: want.GetName()}, d); err != nil {
+		if kerrors.IsNotFound(err) {
+			return v1.RuntimeUnknownHealth().WithMessage(msgNoRuntimeDeployment)
+		}
+		return v1.RuntimeUnknownHealth().WithMessage(errors.Wrap(err, errGetRuntimeDeployment).Error())
+	}
+
+	s, err := metav1.LabelSelectorAsSelector(d.Spec.Selector)
+	if err != nil {
+		return v1.RuntimeUnknownHealth().WithMessage(errors.Wrap(err, errParseRuntimeSelector).Error())
+	}
+	pods := &corev1.PodList{}
+	if err := h.client.List(ctx, pods, client.InNamespace(d.GetNamespace()), client.MatchingLabelsSelector{Selector: s}); err != nil {
+		return v1.RuntimeUnknownHealth().WithMessage(errors.Wrap(err, errListRuntimePods).Error())
+	}
+
+	return deploymentHealth(d, pods.Items)
+}
+
+// deploymentHealth returns a condition that reflects the health of the
+// supplied Deployment and its pods.
+func deploymentHealth(d *appsv1.Deployment, pods []corev1.Pod) xpv1.Condition {
+	for _, p := range pods {
+		if msg := podFailure(p); msg != "" {
+			return v1.RuntimeUnhealthy().WithMessage(msg)
+		}
+	}
+
+	want := int32(1)
+	if d.Spec.Replicas != nil {
+		want = *d.Spec.Replicas
+	}
+	available := fmt.Sprintf("%d/%d replicas available", d.Status.AvailableReplicas, want)
+
+	if d.Status.AvailableReplicas >= want {
+		return v1.RuntimeHealthy().WithMessage(available)
+	}
+
+	for _, c := range d.Status.Conditions {
+		switch {
+		case c.Type == appsv1.DeploymentReplicaFailure && c.Status == corev1.ConditionTrue:
+			return v1.RuntimeUnhealthy().WithMessage(fmt.Sprintf("%s: %s", available, c.Message))
+		case c.Type == appsv1.DeploymentProgressing && c.Status == corev1.ConditionFalse:
+			return v1.RuntimeUnhealthy().WithMessage(fmt.Sprintf("%s: %s", available, c.Message))
+		}
+	}
+	return v1.RuntimeUnhealthy().WithMessage(available)
+}
+
+// podFailure returns a message describing why the supplied pod is failing, or
+// an empty string if it isn't.
+func podFailure(p corev1.Pod) string {
+	statuses := append(append([]corev1.ContainerStatus{}, p.Status.InitContainerStatuses...), p.Status.ContainerStatuses...)
+	for _, cs := range statuses {
+		w := cs.State.Waiting
+		if w == nil || !failingContainerReasons[w.Reason] {
+			continue
+		}
+		// We avoid details that change every time the container restarts,
+		// like its restart count or back-off duration, so that a crash
+		// looping runtime doesn't cause a status update for every restart.
+		msg := fmt.Sprintf("Container %q of pod %q is %s", cs.Name, p.GetName(), w.Reason)
+		switch t := cs.LastTerminationState.Terminated; {
+		case w.Reason == "CrashLoopBackOff" && t != nil:
+			msg = fmt.Sprintf("%s: last terminated with exit code %d (%s)", msg, t.ExitCode, t.Reason)
+		case w.Reason != "CrashLoopBackOff" && w.Message != "":
+			msg = fmt.Sprintf("%s: %s", msg, w.Message)
+		}
+		return msg
+	}
+	return ""
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+func TestRuntimeHealth(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	build := &MockManifestBuilder{
+		ServiceAccountFn: func(_ ...ServiceAccountOverride) *corev1.ServiceAccount {
+			return &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "some-sa"}}
+		},
+		DeploymentFn: func(_ string, _ ...DeploymentOverride) *appsv1.Deployment {
+			return &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "crossplane-system", Name: "some-deployment"}}
+		},
+	}
+
+	deployment := func(available int32, conditions ...appsv1.DeploymentCondition) func(client.Object) error {
+		return func(o client.Object) error {
+			d := o.(*appsv1.Deployment)
+			d.SetNamespace("crossplane-system")
+			d.SetName("some-deployment")
+			d.Spec.Replicas = ptr.To[int32](2)
+			d.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"pkg.crossplane.io/revision": "some-revision"}}
+			d.Status.AvailableReplicas = available
+			d.Status.Conditions = conditions
+			return nil
+		}
+	}
+
+	crashing := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "some-pod"},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:         runtimeContainerName,
+				RestartCount: 5,
+				State: corev1.ContainerState{
+					Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff", Message: "back-off 2m40s restarting failed container"},
+				},
+				LastTerminationState: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Reason: "Error"},
+				},
+			}},
+		},
+	}
+	pulling := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "some-pod"},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name: runtimeContainerName,
+				State: corev1.ContainerState{
+					Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: `Back-off pulling image "xpkg.upbound.io/cool/provider:v1"`},
+				},
+			}},
+		},
+	}
+	starting := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "some-pod"},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name: runtimeContainerName,
+				State: corev1.ContainerState{
+					Waiting: &corev1.ContainerStateWaiting{Reason: "ContainerCreating"},
+				},
+			}},
+		},
+	}
+
+	pods := func(p ...corev1.Pod) func(client.ObjectList) error {
+		return func(o client.ObjectList) error {
+			o.(*corev1.PodList).Items = p
+			return nil
+		}
+	}
+
+	type args struct {
+		client client.Reader
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   xpv1.Condition
+	}{
+		"DeploymentNotFound": {
+			reason: "Runtime health should be unknown if the deployment doesn't exist yet.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "some-deployment")),
+				},
+			},
+			want: v1.RuntimeUnknownHealth().WithMessage(msgNoRuntimeDeployment),
+		},
+		"ErrGetDeployment": {
+			reason: "Runtime health should be unknown if we can't get the deployment.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(errBoom),
+				},
+			},
+			want: v1.RuntimeUnknownHealth().WithMessage(errors.Wrap(errBoom, errGetRuntimeDeployment).Error()),
+		},
+		"ErrListPods": {
+			reason: "Runtime health should be unknown if we can't list the deployment's pods.",
+			args: args{
+				client: &test.MockClient{
+					MockGet:  test.NewMockGetFn(nil, deployment(2)),
+					MockList: test.NewMockListFn(errBoom),
+				},
+			},
+			want: v1.RuntimeUnknownHealth().WithMessage(errors.Wrap(errBoom, errListRuntimePods).Error()),
+		},
+		"CrashLooping": {
+			reason: "The runtime should be unhealthy if any of its pods are crash looping, even if all replicas are available.",
+			args: args{
+				client: &test.MockClient{
+					MockGet:  test.NewMockGetFn(nil, deployment(2)),
+					MockList: test.NewMockListFn(nil, pods(crashing)),
+				},
+			},
+			want: v1.RuntimeUnhealthy().WithMessage(`Container "package-runtime" of pod "some-pod" is CrashLoopBackOff: last terminated with exit code 1 (Error)`),
+		},
+		"ImagePullBackOff": {
+			reason: "The runtime should be unhealthy if any of its pods can't pull their image.",
+			args: args{
+				client: &test.MockClient{
+					MockGet:  test.NewMockGetFn(nil, deployment(0)),
+					MockList: test.NewMockListFn(nil, pods(pulling)),
+				},
+			},
+			want: v1.RuntimeUnhealthy().WithMessage(`Container "package-runtime" of pod "some-pod" is ImagePullBackOff: Back-off pulling image "xpkg.upbound.io/cool/provider:v1"`),
+		},
+		"ProgressDeadlineExceeded": {
+			reason: "The runtime should be unhealthy if not all replicas are available, and the deployment isn't progressing.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, deployment(1, appsv1.DeploymentCondition{
+						Type:    appsv1.DeploymentProgressing,
+						Status:  corev1.ConditionFalse,
+						Message: `ReplicaSet "some-deployment-abc" has timed out progressing.`,
+					})),
+					MockList: test.NewMockListFn(nil, pods(starting)),
+				},
+			},
+			want: v1.RuntimeUnhealthy().WithMessage(`1/2 replicas available: ReplicaSet "some-deployment-abc" has timed out progressing.`),
+		},
+		"ReplicasUnavailable": {
+			reason: "The runtime should be unhealthy if not all replicas are available.",
+			args: args{
+				client: &test.MockClient{
+					MockGet:  test.NewMockGetFn(nil, deployment(0)),
+					MockList: test.NewMockListFn(nil, pods(starting)),
+				},
+			},
+			want: v1.RuntimeUnhealthy().WithMessage("0/2 replicas available"),
+		},
+		"Healthy": {
+			reason: "The runtime should be healthy if all replicas are available and no pods are failing.",
+			args: args{
+				client: &test.MockClient{
+					MockGet:  test.NewMockGetFn(nil, deployment(2)),
+					MockList: test.NewMockListFn(nil, pods()),
+				},
+			},
+			want: v1.RuntimeHealthy().WithMessage("2/2 replicas available"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			h := NewDeploymentHealthChecker(tc.args.client)
+			got := h.RuntimeHealth(context.Background(), &v1.ProviderRevision{}, build)
+			if diff := cmp.Diff(tc.want, got, test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\nRuntimeHealth(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -227,6 +227,34 @@ func DeploymentRuntimeWithOptionalSecurityContext(securityContext *corev1.Securi
 	}
 }
 
+// DeploymentRuntimeWithOptionalSeccompProfile sets the seccomp profile of the
+// runtime container if it is unset.
+func DeploymentRuntimeWithOptionalSeccompProfile(profile *corev1.SeccompProfile) DeploymentOverride {
+	return func(d *appsv1.Deployment) {
+		c := &d.Spec.Template.Spec.Containers[0]
+		if c.SecurityContext == nil {
+			c.SecurityContext = &corev1.SecurityContext{}
+		}
+		if c.SecurityContext.SeccompProfile == nil {
+			c.SecurityContext.SeccompProfile = profile
+		}
+	}
+}
+
+// DeploymentRuntimeWithOptionalCapabilities sets the capabilities of the
+// runtime container if they are unset.
+func DeploymentRuntimeWithOptionalCapabilities(capabilities *corev1.Capabilities) DeploymentOverride {
+	return func(d *appsv1.Deployment) {
+		c := &d.Spec.Template.Spec.Containers[0]
+		if c.SecurityContext == nil {
+			c.SecurityContext = &corev1.SecurityContext{}
+		}
+		if c.SecurityContext.Capabilities == nil {
+			c.SecurityContext.Capabilities = capabilities
+		}
+	}
+}
+
 // DeploymentWithRuntimeContainer ensures that the runtime container exists and
 // is the first container.
 func DeploymentWithRuntimeContainer() DeploymentOverride {
@@ -19,6 +19,7 @@ package revision
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/google/go-containerregistry/pkg/name"
 	appsv1 "k8s.io/api/apps/v1"
@@ -54,17 +55,34 @@ const (
 type ProviderHooks struct {
 	client          resource.ClientApplicator
 	defaultRegistry string
+	certValidity    time.Duration
+}
+
+// A ProviderHooksOption configures ProviderHooks.
+type ProviderHooksOption func(*ProviderHooks)
+
+// WithServerCertificateValidity configures ProviderHooks to issue provider
+// webhook server certificates that are valid for the supplied duration, and to
+// renew them once a third of it remains.
+func WithServerCertificateValidity(d time.Duration) ProviderHooksOption {
+	return func(h *ProviderHooks) {
+		h.certValidity = d
+	}
 }
 
 // NewProviderHooks returns a new ProviderHooks.
-func NewProviderHooks(client client.Client, defaultRegistry string) *ProviderHooks {
-	return &ProviderHooks{
+func NewProviderHooks(client client.Client, defaultRegistry string, opts ...ProviderHooksOption) *ProviderHooks {
+	h := &ProviderHooks{
 		client: resource.ClientApplicator{
 			Client:     client,
 			Applicator: resource.NewAPIPatchingApplicator(client),
 		},
 		defaultRegistry: defaultRegistry,
 	}
+	for _, fn := range opts {
+		fn(h)
+	}
+	return h
 }
 
 // Pre performs operations meant to happen before establishing objects.
@@ -121,7 +139,8 @@ func (h *ProviderHooks) Pre(ctx context.Context, pkg runtime.Object, pr v1.Packa
 	if err := initializer.NewTLSCertificateGenerator(secClient.Namespace, initializer.RootCACertSecretName,
 		initializer.TLSCertificateGeneratorWithOwner(pr.GetOwnerReferences()),
 		initializer.TLSCertificateGeneratorWithServerSecretName(secServer.GetName(), initializer.DNSNamesForService(svc.Name, svc.Namespace)),
-		initializer.TLSCertificateGeneratorWithClientSecretName(secClient.GetName(), []string{pr.GetName()})).Run(ctx, h.client); err != nil {
+		initializer.TLSCertificateGeneratorWithClientSecretName(secClient.GetName(), []string{pr.GetName()}),
+		initializer.TLSCertificateGeneratorWithServerCertificateValidity(h.certValidity)).Run(ctx, h.client); err != nil {
 		return errors.Wrapf(err, "cannot generate TLS certificates for %q", pr.GetLabels()[v1.LabelParentPackage])
 	}
 
@@ -162,6 +181,16 @@ func (h *ProviderHooks) Post(ctx context.Context, pkg runtime.Object, pr v1.Pack
 		return errors.Wrap(err, errApplyProviderDeployment)
 	}
 
+	if err := deploymentAvailable(d); err != nil {
+		return err
+	}
+
+	// A provider's webhooks are part of its health, since a broken webhook
+	// can block API requests.
+	return errors.Wrapf(checkWebhooks(ctx, h.client, pr, build, time.Now()), errFmtUnhealthyWebhooks, v1.AnnotationDisableWebhooks)
+}
+
+func deploymentAvailable(d *appsv1.Deployment) error {
 	for _, c := range d.Status.Conditions {
 		if c.Type == appsv1.DeploymentAvailable {
 			if c.Status == corev1.ConditionTrue {
@@ -280,6 +309,12 @@ func getProviderImage(pm *pkgmetav1.Provider, pr v1.PackageRevisionWithRuntime,
 	if pm.Spec.Controller.Image != nil {
 		image = *pm.Spec.Controller.Image
 	}
+	// A package sourced from a ConfigMap or a local package has no image to
+	// run. Its runtime image must instead be set by its metadata or its
+	// DeploymentRuntimeConfig.
+	if !xpkg.IsRegistrySource(image) {
+		return "", nil
+	}
 	ref, err := name.ParseReference(image, name.WithDefaultRegistry(defaultRegistry))
 	if err != nil {
 		return "", errors.Wrap(err, errParseProviderImage)
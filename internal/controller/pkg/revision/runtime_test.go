@@ -21,6 +21,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -320,6 +321,66 @@ func TestRuntimeManifestBuilderDeployment(t *testing.T) {
 				}),
 			},
 		},
+		"ProviderDeploymentWithSchedulingRuntimeConfig": {
+			reason: "Scheduling and priority controls provided by the runtime config should be applied to the deployment",
+			args: args{
+				builder: &RuntimeManifestBuilder{
+					revision:  providerRevision,
+					namespace: namespace,
+					runtimeConfig: &v1beta1.DeploymentRuntimeConfig{
+						Spec: v1beta1.DeploymentRuntimeConfigSpec{
+							DeploymentTemplate: &v1beta1.DeploymentTemplate{
+								Spec: &appsv1.DeploymentSpec{
+									Template: corev1.PodTemplateSpec{
+										Spec: corev1.PodSpec{
+											TopologySpreadConstraints: []corev1.TopologySpreadConstraint{
+												{
+													MaxSkew:           1,
+													TopologyKey:       "topology.kubernetes.io/zone",
+													WhenUnsatisfiable: corev1.ScheduleAnyway,
+													LabelSelector: &metav1.LabelSelector{
+														MatchLabels: map[string]string{"pkg.crossplane.io/provider": providerMetaName},
+													},
+												},
+											},
+											PriorityClassName: "system-cluster-critical",
+											RuntimeClassName:  ptr.To("gvisor"),
+											HostAliases: []corev1.HostAlias{
+												{IP: "10.0.0.1", Hostnames: []string{"registry.example.org"}},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				serviceAccountName: providerRevisionName,
+				overrides:          providerDeploymentOverrides(&pkgmetav1.Provider{ObjectMeta: metav1.ObjectMeta{Name: providerMetaName}}, providerRevision, providerImage),
+			},
+			want: want{
+				want: deploymentProvider(providerName, providerRevisionName, providerImage, DeploymentWithSelectors(map[string]string{
+					"pkg.crossplane.io/provider": providerMetaName,
+					"pkg.crossplane.io/revision": providerRevisionName,
+				}), func(deployment *appsv1.Deployment) {
+					deployment.Spec.Template.Spec.TopologySpreadConstraints = []corev1.TopologySpreadConstraint{
+						{
+							MaxSkew:           1,
+							TopologyKey:       "topology.kubernetes.io/zone",
+							WhenUnsatisfiable: corev1.ScheduleAnyway,
+							LabelSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{"pkg.crossplane.io/provider": providerMetaName},
+							},
+						},
+					}
+					deployment.Spec.Template.Spec.PriorityClassName = "system-cluster-critical"
+					deployment.Spec.Template.Spec.RuntimeClassName = ptr.To("gvisor")
+					deployment.Spec.Template.Spec.HostAliases = []corev1.HostAlias{
+						{IP: "10.0.0.1", Hostnames: []string{"registry.example.org"}},
+					}
+				}),
+			},
+		},
 		"FunctionDeploymentNoControllerConfig": {
 			reason: "No overrides should result in a deployment with default values",
 			args: args{
@@ -355,6 +416,49 @@ func TestRuntimeManifestBuilderDeployment(t *testing.T) {
 				}),
 			},
 		},
+		"FunctionDeploymentWithRuntimeConfigSeccompProfile": {
+			reason: "A seccomp profile from the runtime config should be preserved, and capabilities should still be dropped",
+			args: args{
+				builder: &RuntimeManifestBuilder{
+					revision:  functionRevision,
+					namespace: namespace,
+					runtimeConfig: &v1beta1.DeploymentRuntimeConfig{
+						Spec: v1beta1.DeploymentRuntimeConfigSpec{
+							DeploymentTemplate: &v1beta1.DeploymentTemplate{
+								Spec: &appsv1.DeploymentSpec{
+									Template: corev1.PodTemplateSpec{
+										Spec: corev1.PodSpec{
+											Containers: []corev1.Container{{
+												Name: runtimeContainerName,
+												SecurityContext: &corev1.SecurityContext{
+													SeccompProfile: &corev1.SeccompProfile{
+														Type:             corev1.SeccompProfileTypeLocalhost,
+														LocalhostProfile: ptr.To("profiles/function.json"),
+													},
+												},
+											}},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				serviceAccountName: functionRevisionName,
+				overrides:          functionDeploymentOverrides(functionImage),
+			},
+			want: want{
+				want: deploymentFunction(functionName, functionRevisionName, functionImage, func(deployment *appsv1.Deployment) {
+					deployment.Spec.Template.Spec.Containers[0].SecurityContext = &corev1.SecurityContext{
+						SeccompProfile: &corev1.SeccompProfile{
+							Type:             corev1.SeccompProfileTypeLocalhost,
+							LocalhostProfile: ptr.To("profiles/function.json"),
+						},
+						Capabilities: &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+					}
+				}),
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
@@ -366,6 +470,104 @@ func TestRuntimeManifestBuilderDeployment(t *testing.T) {
 	}
 }
 
+func TestRuntimeManifestBuilderHorizontalPodAutoscaler(t *testing.T) {
+	d := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: functionRevisionName, Namespace: namespace}}
+
+	type args struct {
+		builder ManifestBuilder
+		d       *appsv1.Deployment
+	}
+	type want struct {
+		hpa *autoscalingv2.HorizontalPodAutoscaler
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoRuntimeConfig": {
+			reason: "No horizontal pod autoscaler should be built without a runtime config",
+			args: args{
+				builder: &RuntimeManifestBuilder{
+					revision:  functionRevision,
+					namespace: namespace,
+				},
+				d: d,
+			},
+		},
+		"NoTemplate": {
+			reason: "No horizontal pod autoscaler should be built if the runtime config has no template",
+			args: args{
+				builder: &RuntimeManifestBuilder{
+					revision:      functionRevision,
+					namespace:     namespace,
+					runtimeConfig: &v1beta1.DeploymentRuntimeConfig{},
+				},
+				d: d,
+			},
+		},
+		"Template": {
+			reason: "A horizontal pod autoscaler named after and scaling the deployment should be built from the runtime config's template",
+			args: args{
+				builder: &RuntimeManifestBuilder{
+					revision:  functionRevision,
+					namespace: namespace,
+					runtimeConfig: &v1beta1.DeploymentRuntimeConfig{
+						Spec: v1beta1.DeploymentRuntimeConfigSpec{
+							HorizontalPodAutoscalerTemplate: &v1beta1.HorizontalPodAutoscalerTemplate{
+								Metadata: &v1beta1.ObjectMeta{
+									Name:   ptr.To("ignored"),
+									Labels: map[string]string{"k": "v"},
+								},
+								Spec: v1beta1.HorizontalPodAutoscalerSpec{
+									MinReplicas: ptr.To[int32](2),
+									MaxReplicas: 10,
+								},
+							},
+						},
+					},
+				},
+				d: d,
+			},
+			want: want{
+				hpa: &autoscalingv2.HorizontalPodAutoscaler{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      functionRevisionName,
+						Namespace: namespace,
+						Labels:    map[string]string{"k": "v"},
+						OwnerReferences: []metav1.OwnerReference{
+							{
+								APIVersion:         "pkg.crossplane.io/v1beta1",
+								Kind:               "FunctionRevision",
+								Name:               functionRevisionName,
+								Controller:         ptr.To(true),
+								BlockOwnerDeletion: ptr.To(true),
+							},
+						},
+					},
+					Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+						ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+							APIVersion: "apps/v1",
+							Kind:       "Deployment",
+							Name:       functionRevisionName,
+						},
+						MinReplicas: ptr.To[int32](2),
+						MaxReplicas: 10,
+					},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.args.builder.HorizontalPodAutoscaler(tc.args.d)
+			if diff := cmp.Diff(tc.want.hpa, got); diff != "" {
+				t.Errorf("\n%s\nHorizontalPodAutoscaler(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func deploymentProvider(provider string, revision string, image string, overrides ...DeploymentOverride) *appsv1.Deployment {
 	d := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
@@ -594,6 +796,8 @@ func deploymentFunction(function string, revision string, image string, override
 								AllowPrivilegeEscalation: &allowPrivilegeEscalation,
 								Privileged:               &privileged,
 								RunAsNonRoot:             &runAsNonRoot,
+								SeccompProfile:           &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+								Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
 							},
 						},
 					},
@@ -640,6 +844,8 @@ type MockManifestBuilder struct {
 	ServiceFn         func(overrides ...ServiceOverride) *corev1.Service
 	TLSClientSecretFn func() *corev1.Secret
 	TLSServerSecretFn func() *corev1.Secret
+
+	HorizontalPodAutoscalerFn func(d *appsv1.Deployment) *autoscalingv2.HorizontalPodAutoscaler
 }
 
 // ServiceAccount returns the result of calling ServiceAccountFn.
@@ -666,3 +872,9 @@ func (b *MockManifestBuilder) TLSClientSecret() *corev1.Secret {
 func (b *MockManifestBuilder) TLSServerSecret() *corev1.Secret {
 	return b.TLSServerSecretFn()
 }
+
+// HorizontalPodAutoscaler returns the result of calling
+// HorizontalPodAutoscalerFn.
+func (b *MockManifestBuilder) HorizontalPodAutoscaler(d *appsv1.Deployment) *autoscalingv2.HorizontalPodAutoscaler {
+	return b.HorizontalPodAutoscalerFn(d)
+}
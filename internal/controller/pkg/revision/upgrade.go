@@ -0,0 +1,226 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+const (
+	errFmtGetInstalledCRD = "cannot get installed CRD %s"
+	errFmtListCRDObjects  = "cannot list objects of CRD %s at version %s"
+)
+
+// An UpgradeChecker checks whether a package revision's objects can safely
+// replace the objects that are already installed.
+type UpgradeChecker interface {
+	// CheckUpgrade returns a description of each reason the supplied objects
+	// can't safely replace the objects that are already installed.
+	CheckUpgrade(ctx context.Context, objs []runtime.Object) ([]string, error)
+}
+
+// An UpgradeCheckerFn checks whether a package revision's objects can safely
+// replace the objects that are already installed.
+type UpgradeCheckerFn func(ctx context.Context, objs []runtime.Object) ([]string, error)
+
+// CheckUpgrade returns a description of each reason the supplied objects can't
+// safely replace the objects that are already installed.
+func (fn UpgradeCheckerFn) CheckUpgrade(ctx context.Context, objs []runtime.Object) ([]string, error) {
+	return fn(ctx, objs)
+}
+
+// A CRDUpgradeChecker checks whether a package revision's CRDs can safely
+// replace the CRDs that are already installed. It's unsafe for a CRD to change
+// scope, to remove or stop serving a version that existing objects are stored
+// at, or to remove a field that existing objects set.
+type CRDUpgradeChecker struct {
+	client client.Reader
+}
+
+// NewCRDUpgradeChecker returns an UpgradeChecker that reads installed CRDs,
+// and the objects they define, using the supplied client. The client should
+// not cache unstructured objects.
+func NewCRDUpgradeChecker(c client.Reader) *CRDUpgradeChecker {
+	return &CRDUpgradeChecker{client: c}
+}
+
+// CheckUpgrade returns a description of each reason the supplied CRDs can't
+// safely replace the CRDs that are already installed. Objects that aren't CRDs,
+// and CRDs that aren't installed yet, are always safe.
+func (c *CRDUpgradeChecker) CheckUpgrade(ctx context.Context, objs []runtime.Object) ([]string, error) {
+	var unsafe []string
+	for _, o := range objs {
+		crd, ok := o.(*extv1.CustomResourceDefinition)
+		if !ok {
+			continue
+		}
+		installed := &extv1.CustomResourceDefinition{}
+		if err := c.client.Get(ctx, types.NamespacedName{Name: crd.GetName()}, installed); err != nil {
+			if kerrors.IsNotFound(err) {
+				continue
+			}
+			return nil, errors.Wrapf(err, errFmtGetInstalledCRD, crd.GetName())
+		}
+		u, err := c.checkCRD(ctx, installed, crd)
+		if err != nil {
+			return nil, err
+		}
+		unsafe = append(unsafe, u...)
+	}
+	return unsafe, nil
+}
+
+func (c *CRDUpgradeChecker) checkCRD(ctx context.Context, installed, crd *extv1.CustomResourceDefinition) ([]string, error) {
+	if installed.Spec.Scope != crd.Spec.Scope {
+		return []string{fmt.Sprintf("CRD %s changes scope from %s to %s", crd.GetName(), installed.Spec.Scope, crd.Spec.Scope)}, nil
+	}
+
+	var unsafe []string
+	for _, stored := range installed.Status.StoredVersions {
+		want := crdVersion(crd, stored)
+		if want == nil {
+			unsafe = append(unsafe, fmt.Sprintf("CRD %s removes version %s, which existing objects may be stored at", crd.GetName(), stored))
+			continue
+		}
+		if !want.Served {
+			unsafe = append(unsafe, fmt.Sprintf("CRD %s stops serving version %s, which existing objects may be stored at", crd.GetName(), stored))
+			continue
+		}
+
+		// We can only read existing objects at a version that's served.
+		have := crdVersion(installed, stored)
+		if have == nil || !have.Served {
+			continue
+		}
+		removed := removedFields(openAPISchema(have), openAPISchema(want), nil)
+		if len(removed) == 0 {
+			continue
+		}
+
+		l := &unstructured.UnstructuredList{}
+		l.SetGroupVersionKind(schema.GroupVersionKind{Group: installed.Spec.Group, Version: stored, Kind: installed.Spec.Names.Kind + "List"})
+		if err := c.client.List(ctx, l); err != nil {
+			return nil, errors.Wrapf(err, errFmtListCRDObjects, crd.GetName(), stored)
+		}
+		for _, path := range removed {
+			for i := range l.Items {
+				if hasField(l.Items[i].Object, path) {
+					unsafe = append(unsafe, fmt.Sprintf("CRD %s removes field %s from version %s, which existing objects set", crd.GetName(), fieldPath(path), stored))
+					break
+				}
+			}
+		}
+	}
+	return unsafe, nil
+}
+
+// crdVersion returns the named version of the supplied CRD, or nil if it
+// doesn't have that version.
+func crdVersion(crd *extv1.CustomResourceDefinition, name string) *extv1.CustomResourceDefinitionVersion {
+	for i := range crd.Spec.Versions {
+		if crd.Spec.Versions[i].Name == name {
+			return &crd.Spec.Versions[i]
+		}
+	}
+	return nil
+}
+
+func openAPISchema(v *extv1.CustomResourceDefinitionVersion) *extv1.JSONSchemaProps {
+	if v.Schema == nil {
+		return nil
+	}
+	return v.Schema.OpenAPIV3Schema
+}
+
+// removedFields returns the path of each field that's in the have schema but
+// not the want schema, sorted. Fields that the want schema preserves as
+// unknown fields aren't removed. Array items are denoted by [*].
+func removedFields(have, want *extv1.JSONSchemaProps, path []string) [][]string {
+	if have == nil || want == nil {
+		return nil
+	}
+	if ptr.Deref(want.XPreserveUnknownFields, false) || want.AdditionalProperties != nil {
+		return nil
+	}
+
+	var removed [][]string
+	for name := range have.Properties {
+		p := append(append([]string{}, path...), name)
+		w, ok := want.Properties[name]
+		if !ok {
+			removed = append(removed, p)
+			continue
+		}
+		h := have.Properties[name]
+		removed = append(removed, removedFields(&h, &w, p)...)
+	}
+	if have.Items != nil && want.Items != nil {
+		removed = append(removed, removedFields(have.Items.Schema, want.Items.Schema, append(append([]string{}, path...), "[*]"))...)
+	}
+
+	sort.Slice(removed, func(i, j int) bool { return fieldPath(removed[i]) < fieldPath(removed[j]) })
+	return removed
+}
+
+// hasField returns true if the supplied object sets the field at the supplied
+// path. Array items are denoted by [*].
+func hasField(v any, path []string) bool {
+	if len(path) == 0 {
+		return true
+	}
+	switch t := v.(type) {
+	case map[string]any:
+		f, ok := t[path[0]]
+		return ok && hasField(f, path[1:])
+	case []any:
+		if path[0] != "[*]" {
+			return false
+		}
+		for _, e := range t {
+			if hasField(e, path[1:]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// fieldPath returns the supplied path as a string, e.g. spec.items[*].name.
+func fieldPath(path []string) string {
+	b := &strings.Builder{}
+	for i, p := range path {
+		if i > 0 && p != "[*]" {
+			b.WriteString(".")
+		}
+		b.WriteString(p)
+	}
+	return b.String()
+}
@@ -0,0 +1,279 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+func TestCRDUpgradeCheckerCheckUpgrade(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type crdVersion struct {
+		name   string
+		served bool
+		schema *extv1.JSONSchemaProps
+	}
+	crd := func(scope extv1.ResourceScope, stored []string, versions ...crdVersion) *extv1.CustomResourceDefinition {
+		c := &extv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "widgets.example.org"},
+			Spec: extv1.CustomResourceDefinitionSpec{
+				Group: "example.org",
+				Names: extv1.CustomResourceDefinitionNames{Kind: "Widget"},
+				Scope: scope,
+			},
+			Status: extv1.CustomResourceDefinitionStatus{StoredVersions: stored},
+		}
+		for _, v := range versions {
+			c.Spec.Versions = append(c.Spec.Versions, extv1.CustomResourceDefinitionVersion{
+				Name:   v.name,
+				Served: v.served,
+				Schema: &extv1.CustomResourceValidation{OpenAPIV3Schema: v.schema},
+			})
+		}
+		return c
+	}
+	spec := func(fields ...string) *extv1.JSONSchemaProps {
+		s := extv1.JSONSchemaProps{Type: "object", Properties: map[string]extv1.JSONSchemaProps{}}
+		for _, f := range fields {
+			s.Properties[f] = extv1.JSONSchemaProps{Type: "string"}
+		}
+		return &extv1.JSONSchemaProps{Type: "object", Properties: map[string]extv1.JSONSchemaProps{"spec": s}}
+	}
+	installed := func(c *extv1.CustomResourceDefinition) test.MockGetFn {
+		return func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+			c.DeepCopyInto(obj.(*extv1.CustomResourceDefinition))
+			return nil
+		}
+	}
+	widgets := func(specs ...map[string]any) test.MockListFn {
+		return func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+			l := obj.(*unstructured.UnstructuredList)
+			if l.GroupVersionKind() != (schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "WidgetList"}) {
+				return errors.Errorf("unexpected list %s", l.GroupVersionKind())
+			}
+			for _, s := range specs {
+				l.Items = append(l.Items, unstructured.Unstructured{Object: map[string]any{"spec": s}})
+			}
+			return nil
+		}
+	}
+
+	type args struct {
+		client client.Reader
+		objs   []runtime.Object
+	}
+	type want struct {
+		unsafe []string
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NotACRD": {
+			reason: "Objects that aren't CRDs should always be safe to upgrade.",
+			args: args{
+				objs: []runtime.Object{&v1.Provider{}},
+			},
+		},
+		"NotInstalled": {
+			reason: "A CRD that isn't installed yet should be safe to upgrade.",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, ""))},
+				objs:   []runtime.Object{crd(extv1.NamespaceScoped, nil, crdVersion{"v1", true, spec()})},
+			},
+		},
+		"ErrGetInstalledCRD": {
+			reason: "We should return an error if we can't get an installed CRD.",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				objs:   []runtime.Object{crd(extv1.NamespaceScoped, nil, crdVersion{"v1", true, spec()})},
+			},
+			want: want{
+				err: errors.Wrapf(errBoom, errFmtGetInstalledCRD, "widgets.example.org"),
+			},
+		},
+		"ScopeChanged": {
+			reason: "A CRD that changes scope shouldn't be safe to upgrade.",
+			args: args{
+				client: &test.MockClient{MockGet: installed(crd(extv1.NamespaceScoped, []string{"v1"}, crdVersion{"v1", true, spec()}))},
+				objs:   []runtime.Object{crd(extv1.ClusterScoped, nil, crdVersion{"v1", true, spec()})},
+			},
+			want: want{
+				unsafe: []string{"CRD widgets.example.org changes scope from Namespaced to Cluster"},
+			},
+		},
+		"StoredVersionRemoved": {
+			reason: "A CRD that removes a stored version shouldn't be safe to upgrade.",
+			args: args{
+				client: &test.MockClient{MockGet: installed(crd(extv1.NamespaceScoped, []string{"v1alpha1", "v1"}, crdVersion{"v1alpha1", true, spec()}, crdVersion{"v1", true, spec()}))},
+				objs:   []runtime.Object{crd(extv1.NamespaceScoped, nil, crdVersion{"v1", true, spec()})},
+			},
+			want: want{
+				unsafe: []string{"CRD widgets.example.org removes version v1alpha1, which existing objects may be stored at"},
+			},
+		},
+		"StoredVersionNotServed": {
+			reason: "A CRD that stops serving a stored version shouldn't be safe to upgrade.",
+			args: args{
+				client: &test.MockClient{MockGet: installed(crd(extv1.NamespaceScoped, []string{"v1alpha1", "v1"}, crdVersion{"v1alpha1", true, spec()}, crdVersion{"v1", true, spec()}))},
+				objs:   []runtime.Object{crd(extv1.NamespaceScoped, nil, crdVersion{"v1alpha1", false, spec()}, crdVersion{"v1", true, spec()})},
+			},
+			want: want{
+				unsafe: []string{"CRD widgets.example.org stops serving version v1alpha1, which existing objects may be stored at"},
+			},
+		},
+		"ErrListObjects": {
+			reason: "We should return an error if we can't list the objects of a CRD that removes a field.",
+			args: args{
+				client: &test.MockClient{
+					MockGet:  installed(crd(extv1.NamespaceScoped, []string{"v1"}, crdVersion{"v1", true, spec("colour", "size")})),
+					MockList: test.NewMockListFn(errBoom),
+				},
+				objs: []runtime.Object{crd(extv1.NamespaceScoped, nil, crdVersion{"v1", true, spec("size")})},
+			},
+			want: want{
+				err: errors.Wrapf(errBoom, errFmtListCRDObjects, "widgets.example.org", "v1"),
+			},
+		},
+		"RemovedFieldSet": {
+			reason: "A CRD that removes a field existing objects set shouldn't be safe to upgrade.",
+			args: args{
+				client: &test.MockClient{
+					MockGet:  installed(crd(extv1.NamespaceScoped, []string{"v1"}, crdVersion{"v1", true, spec("colour", "size")})),
+					MockList: widgets(map[string]any{"size": "large"}, map[string]any{"colour": "red"}),
+				},
+				objs: []runtime.Object{crd(extv1.NamespaceScoped, nil, crdVersion{"v1", true, spec("size")})},
+			},
+			want: want{
+				unsafe: []string{"CRD widgets.example.org removes field spec.colour from version v1, which existing objects set"},
+			},
+		},
+		"RemovedFieldUnset": {
+			reason: "A CRD that removes a field no existing objects set should be safe to upgrade.",
+			args: args{
+				client: &test.MockClient{
+					MockGet:  installed(crd(extv1.NamespaceScoped, []string{"v1"}, crdVersion{"v1", true, spec("colour", "size")})),
+					MockList: widgets(map[string]any{"size": "large"}),
+				},
+				objs: []runtime.Object{crd(extv1.NamespaceScoped, nil, crdVersion{"v1", true, spec("size")})},
+			},
+		},
+		"RemovedFieldPreserved": {
+			reason: "A CRD that preserves the unknown fields of an object that removes a field should be safe to upgrade.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: installed(crd(extv1.NamespaceScoped, []string{"v1"}, crdVersion{"v1", true, spec("colour", "size")})),
+				},
+				objs: []runtime.Object{crd(extv1.NamespaceScoped, nil, crdVersion{"v1", true, &extv1.JSONSchemaProps{
+					Type: "object",
+					Properties: map[string]extv1.JSONSchemaProps{
+						"spec": {Type: "object", XPreserveUnknownFields: ptr.To(true)},
+					},
+				}})},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := NewCRDUpgradeChecker(tc.args.client)
+			unsafe, err := c.CheckUpgrade(context.Background(), tc.args.objs)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nCheckUpgrade(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.unsafe, unsafe); diff != "" {
+				t.Errorf("\n%s\nCheckUpgrade(...): -want unsafe, +got unsafe:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRemovedFields(t *testing.T) {
+	props := func(p map[string]extv1.JSONSchemaProps) *extv1.JSONSchemaProps {
+		return &extv1.JSONSchemaProps{Type: "object", Properties: p}
+	}
+
+	cases := map[string]struct {
+		reason string
+		have   *extv1.JSONSchemaProps
+		want   *extv1.JSONSchemaProps
+		result []string
+	}{
+		"Nested": {
+			reason: "We should return the path of removed nested fields, but not the fields beneath them.",
+			have: props(map[string]extv1.JSONSchemaProps{
+				"spec": *props(map[string]extv1.JSONSchemaProps{
+					"a": *props(map[string]extv1.JSONSchemaProps{"b": {Type: "string"}}),
+					"c": {Type: "string"},
+				}),
+			}),
+			want: props(map[string]extv1.JSONSchemaProps{
+				"spec": *props(map[string]extv1.JSONSchemaProps{
+					"c": {Type: "string"},
+				}),
+			}),
+			result: []string{"spec.a"},
+		},
+		"ArrayItems": {
+			reason: "We should return the path of fields removed from array items.",
+			have: props(map[string]extv1.JSONSchemaProps{
+				"spec": {Type: "array", Items: &extv1.JSONSchemaPropsOrArray{Schema: props(map[string]extv1.JSONSchemaProps{
+					"name":  {Type: "string"},
+					"value": {Type: "string"},
+				})}},
+			}),
+			want: props(map[string]extv1.JSONSchemaProps{
+				"spec": {Type: "array", Items: &extv1.JSONSchemaPropsOrArray{Schema: props(map[string]extv1.JSONSchemaProps{
+					"name": {Type: "string"},
+				})}},
+			}),
+			result: []string{"spec[*].value"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got []string
+			for _, p := range removedFields(tc.have, tc.want, nil) {
+				got = append(got, fieldPath(p))
+			}
+			if diff := cmp.Diff(tc.result, got); diff != "" {
+				t.Errorf("\n%s\nremovedFields(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"time"
+
+	admv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+const (
+	errGetWebhookService     = "cannot get webhook service"
+	errParseWebhookTLSCert   = "cannot parse webhook TLS certificate"
+	errFmtWebhookCertExpired = "webhook TLS certificate expired at %s"
+	errFmtUnhealthyWebhooks  = "package webhooks are unhealthy (annotate the package with %s: \"true\" to disable them)"
+)
+
+// hasAdmissionWebhooks returns true if the supplied package revision
+// established a validating or mutating webhook configuration.
+func hasAdmissionWebhooks(pr v1.PackageRevision) bool {
+	for _, ref := range pr.GetObjects() {
+		if ref.GroupVersionKind().Group != admv1.GroupName {
+			continue
+		}
+		if ref.Kind == "ValidatingWebhookConfiguration" || ref.Kind == "MutatingWebhookConfiguration" {
+			return true
+		}
+	}
+	return false
+}
+
+// checkWebhooks returns an error if the supplied package revision has admission
+// webhooks that can't be healthy, because their service doesn't exist or their
+// TLS certificate has expired. Disabled webhooks are never unhealthy.
+func checkWebhooks(ctx context.Context, c client.Reader, pr v1.PackageRevisionWithRuntime, build ManifestBuilder, now time.Time) error {
+	if v1.WebhooksDisabled(pr) || !hasAdmissionWebhooks(pr) {
+		return nil
+	}
+
+	svc := build.Service()
+	if err := c.Get(ctx, client.ObjectKeyFromObject(svc), &corev1.Service{}); err != nil {
+		return errors.Wrap(err, errGetWebhookService)
+	}
+
+	sec := build.TLSServerSecret()
+	if sec == nil {
+		return nil
+	}
+	s := &corev1.Secret{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(sec), s); err != nil {
+		return errors.Wrap(err, errGetWebhookTLSSecret)
+	}
+	block, _ := pem.Decode(s.Data[corev1.TLSCertKey])
+	if block == nil {
+		return errors.New(errParseWebhookTLSCert)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return errors.Wrap(err, errParseWebhookTLSCert)
+	}
+	if now.After(cert.NotAfter) {
+		return errors.Errorf(errFmtWebhookCertExpired, cert.NotAfter.Format(time.RFC3339))
+	}
+	return nil
+}
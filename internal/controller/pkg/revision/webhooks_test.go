@@ -0,0 +1,150 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+func TestCheckWebhooks(t *testing.T) {
+	errBoom := errors.New("boom")
+	now := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	expiry := now.Add(-time.Hour)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert := func(notAfter time.Time) []byte {
+		tmpl := &x509.Certificate{
+			SerialNumber: big.NewInt(1),
+			Subject:      pkix.Name{CommonName: "provider-nop"},
+			NotBefore:    now.Add(-24 * time.Hour),
+			NotAfter:     notAfter,
+		}
+		der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	}
+
+	rev := func(annotations map[string]string, kinds ...string) *v1.ProviderRevision {
+		pr := &v1.ProviderRevision{ObjectMeta: metav1.ObjectMeta{Annotations: annotations}}
+		refs := make([]xpv1.TypedReference, 0, len(kinds))
+		for _, k := range kinds {
+			refs = append(refs, xpv1.TypedReference{APIVersion: "admissionregistration.k8s.io/v1", Kind: k, Name: "provider-nop"})
+		}
+		pr.SetObjects(refs)
+		return pr
+	}
+
+	build := &MockManifestBuilder{
+		ServiceFn: func(_ ...ServiceOverride) *corev1.Service {
+			return &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "crossplane-system", Name: "provider-nop"}}
+		},
+		TLSServerSecretFn: func() *corev1.Secret {
+			return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "crossplane-system", Name: "provider-nop-tls-server"}}
+		},
+	}
+
+	get := func(crt []byte) test.MockGetFn {
+		return func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+			if s, ok := obj.(*corev1.Secret); ok {
+				s.Data = map[string][]byte{corev1.TLSCertKey: crt}
+			}
+			return nil
+		}
+	}
+
+	type args struct {
+		client client.Reader
+		rev    v1.PackageRevisionWithRuntime
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   error
+	}{
+		"NoWebhooks": {
+			reason: "A revision without webhook configurations has no webhooks to check.",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				rev:    rev(nil),
+			},
+		},
+		"Disabled": {
+			reason: "Disabled webhooks should not be checked.",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				rev:    rev(map[string]string{v1.AnnotationDisableWebhooks: "true"}, "ValidatingWebhookConfiguration"),
+			},
+		},
+		"GetServiceError": {
+			reason: "We should return an error if we can't get the webhook service.",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				rev:    rev(nil, "MutatingWebhookConfiguration"),
+			},
+			want: errors.Wrap(errBoom, errGetWebhookService),
+		},
+		"CertificateExpired": {
+			reason: "We should return an error if the webhook TLS certificate has expired.",
+			args: args{
+				client: &test.MockClient{MockGet: get(cert(expiry))},
+				rev:    rev(nil, "ValidatingWebhookConfiguration"),
+			},
+			want: errors.Errorf(errFmtWebhookCertExpired, expiry.Format(time.RFC3339)),
+		},
+		"Healthy": {
+			reason: "Webhooks with a service and a valid certificate should be healthy.",
+			args: args{
+				client: &test.MockClient{MockGet: get(cert(now.Add(time.Hour)))},
+				rev:    rev(nil, "ValidatingWebhookConfiguration"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := checkWebhooks(context.Background(), tc.args.client, tc.args.rev, build, now)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ncheckWebhooks(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
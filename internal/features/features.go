@@ -40,6 +40,63 @@ const (
 	// compositions, i.e. watching MRs and reconciling compositions immediately
 	// when any MR is updated.
 	EnableAlphaRealtimeCompositions feature.Flag = "EnableAlphaRealtimeCompositions"
+
+	// EnableAlphaConfigMapPackages enables alpha support for Configurations
+	// sourced from a ConfigMap, rather than an OCI image. This is useful for
+	// small Configurations that don't warrant a registry.
+	EnableAlphaConfigMapPackages feature.Flag = "EnableAlphaConfigMapPackages"
+
+	// EnableAlphaClaimAdmissionRules enables alpha support for claim admission
+	// rules, i.e. CEL expressions defined by an XRD that Crossplane's webhook
+	// evaluates when a claim is created or updated.
+	EnableAlphaClaimAdmissionRules feature.Flag = "EnableAlphaClaimAdmissionRules"
+
+	// EnableAlphaProviderFamilyVersions enables alpha support for keeping all
+	// providers in a family at the same version, by not activating a
+	// provider revision until every provider in its family wants the same
+	// version.
+	EnableAlphaProviderFamilyVersions feature.Flag = "EnableAlphaProviderFamilyVersions"
+
+	// EnableAlphaDependencyUpgrades enables alpha support for upgrading an
+	// installed dependency to the greatest version that satisfies the
+	// constraints of every package that depends on it, rather than leaving
+	// the dependent packages unhealthy.
+	EnableAlphaDependencyUpgrades feature.Flag = "EnableAlphaDependencyUpgrades"
+
+	// EnableAlphaSignatureVerification enables alpha support for verifying
+	// the cosign signatures of package images before installing them, using
+	// the ImageConfig that matches each image.
+	EnableAlphaSignatureVerification feature.Flag = "EnableAlphaSignatureVerification"
+
+	// EnableAlphaPackageUpdates enables alpha support for periodically
+	// updating packages to the newest version that satisfies their update
+	// policy.
+	EnableAlphaPackageUpdates feature.Flag = "EnableAlphaPackageUpdates"
+
+	// EnableAlphaImageRewrites enables alpha support for rewriting package
+	// images before they're pulled, using the ImageConfig that matches each
+	// image.
+	EnableAlphaImageRewrites feature.Flag = "EnableAlphaImageRewrites"
+
+	// EnableAlphaLocalPackages enables alpha support for packages sourced
+	// from a local directory, e.g. file://my-configuration.xpkg, rather than
+	// from a registry.
+	EnableAlphaLocalPackages feature.Flag = "EnableAlphaLocalPackages"
+
+	// EnableAlphaCRDUpgradeChecks enables alpha support for checking that a
+	// package revision's CRDs can safely replace the CRDs that are already
+	// installed before the revision is activated.
+	EnableAlphaCRDUpgradeChecks feature.Flag = "EnableAlphaCRDUpgradeChecks"
+
+	// EnableAlphaWebhookCertificateRotation enables alpha support for
+	// renewing the certificates of provider webhook servers before they
+	// expire.
+	EnableAlphaWebhookCertificateRotation feature.Flag = "EnableAlphaWebhookCertificateRotation"
+
+	// EnableAlphaFunctionAutoscaling enables alpha support for scaling
+	// Function package runtimes using a HorizontalPodAutoscaler configured by
+	// their DeploymentRuntimeConfig.
+	EnableAlphaFunctionAutoscaling feature.Flag = "EnableAlphaFunctionAutoscaling"
 )
 
 // Beta Feature Flags
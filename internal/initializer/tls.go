@@ -65,6 +65,7 @@ type TLSCertificateGenerator struct {
 	caSecretName        string
 	tlsServerSecretName *string
 	tlsServerDNSNames   []string
+	tlsServerValidity   time.Duration
 	tlsClientSecretName *string
 	tlsClientDNSNames   []string
 	owner               []metav1.OwnerReference
@@ -97,6 +98,16 @@ func TLSCertificateGeneratorWithServerSecretName(s string, dnsNames []string) TL
 	}
 }
 
+// TLSCertificateGeneratorWithServerCertificateValidity returns an
+// TLSCertificateGeneratorOption that issues server certificates that are valid
+// for the supplied duration, and renews them once a third of it remains.
+// Server certificates are valid for ten years and never renewed by default.
+func TLSCertificateGeneratorWithServerCertificateValidity(d time.Duration) TLSCertificateGeneratorOption {
+	return func(g *TLSCertificateGenerator) {
+		g.tlsServerValidity = d
+	}
+}
+
 // TLSCertificateGeneratorWithClientSecretName returns an TLSCertificateGeneratorOption that sets client secret name.
 func TLSCertificateGeneratorWithClientSecretName(s string, subjects []string) TLSCertificateGeneratorOption {
 	return func(g *TLSCertificateGenerator) {
@@ -234,24 +245,31 @@ func (e *TLSCertificateGenerator) ensureServerCertificate(ctx context.Context, k
 		return errors.Wrapf(err, errFmtGetTLSSecret, nn.Name)
 	}
 
-	if err == nil {
-		if len(sec.Data[corev1.TLSCertKey]) != 0 || len(sec.Data[corev1.TLSPrivateKeyKey]) != 0 || len(sec.Data[SecretKeyCACert]) != 0 {
-			e.log.Info("TLS secret contains server certificate.", "secret", nn.Name)
-			return nil
-		}
+	switch {
+	case err != nil || (len(sec.Data[corev1.TLSCertKey]) == 0 && len(sec.Data[corev1.TLSPrivateKeyKey]) == 0 && len(sec.Data[SecretKeyCACert]) == 0):
+		e.log.Info("Server certificates are empty or not complete, generating a new pair...", "secret", nn.Name)
+	case e.tlsServerValidity > 0 && renewalDue(sec.Data[corev1.TLSCertKey], signer, e.tlsServerValidity, time.Now()):
+		e.log.Info("Server certificate is due for renewal, generating a new pair...", "secret", nn.Name)
+	default:
+		e.log.Info("TLS secret contains server certificate.", "secret", nn.Name)
+		return nil
 	}
-	e.log.Info("Server certificates are empty or not complete, generating a new pair...", "secret", nn.Name)
 	dnsNames := e.tlsServerDNSNames
 	if len(dnsNames) == 0 {
 		return errors.New("server DNS names are empty, you must provide at least one DNS name")
 	}
 
+	notAfter := time.Now().AddDate(10, 0, 0)
+	if e.tlsServerValidity > 0 {
+		notAfter = time.Now().Add(e.tlsServerValidity)
+	}
+
 	cert := &x509.Certificate{
 		SerialNumber:          big.NewInt(2022),
 		Subject:               pkixName,
 		DNSNames:              dnsNames,
 		NotBefore:             time.Now(),
-		NotAfter:              time.Now().AddDate(10, 0, 0),
+		NotAfter:              notAfter,
 		IsCA:                  false,
 		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment | x509.KeyUsageDataEncipherment,
 		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
@@ -317,6 +335,30 @@ func (e *TLSCertificateGenerator) Run(ctx context.Context, kube client.Client) e
 	return nil
 }
 
+// renewalDue returns true if the supplied PEM encoded certificate should be
+// renewed because it can't be parsed, it wasn't signed by the supplied signer,
+// or a third or less of the supplied validity remains. A certificate that was
+// issued for longer than the supplied validity, for example before rotation was
+// enabled, is treated as though it expires once the validity has elapsed.
+func renewalDue(certPEM []byte, signer *CertificateSigner, validity time.Duration, now time.Time) bool {
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return true
+	}
+	c, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return true
+	}
+	if c.CheckSignatureFrom(signer.certificate) != nil {
+		return true
+	}
+	expiry := c.NotAfter
+	if e := c.NotBefore.Add(validity); e.Before(expiry) {
+		expiry = e
+	}
+	return !now.Before(expiry.Add(-validity / 3))
+}
+
 func parseCertificateSigner(key, cert []byte) (*CertificateSigner, error) {
 	block, _ := pem.Decode(key)
 	if block == nil {
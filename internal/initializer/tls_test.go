@@ -19,7 +19,9 @@ package initializer
 import (
 	"context"
 	"crypto/x509"
+	"math/big"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	corev1 "k8s.io/api/core/v1"
@@ -776,3 +778,86 @@ func TestTLSCertificateGeneratorRun(t *testing.T) {
 		})
 	}
 }
+
+func TestRenewalDue(t *testing.T) {
+	now := time.Now()
+	validity := 90 * 24 * time.Hour
+
+	signer, err := parseCertificateSigner([]byte(caKey), []byte(caCert))
+	if err != nil {
+		t.Fatalf("parseCertificateSigner(...): %v", err)
+	}
+	otherKey, otherCert, err := NewCertGenerator().Generate(&x509.Certificate{
+		SerialNumber:          big.NewInt(2022),
+		Subject:               pkixName,
+		NotBefore:             now,
+		NotAfter:              now.AddDate(10, 0, 0),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}, nil)
+	if err != nil {
+		t.Fatalf("Generate(...): %v", err)
+	}
+	other, err := parseCertificateSigner(otherKey, otherCert)
+	if err != nil {
+		t.Fatalf("parseCertificateSigner(...): %v", err)
+	}
+
+	leaf := func(s *CertificateSigner, notBefore, notAfter time.Time) []byte {
+		_, crt, err := NewCertGenerator().Generate(&x509.Certificate{
+			SerialNumber: big.NewInt(2022),
+			Subject:      pkixName,
+			DNSNames:     []string{"provider"},
+			NotBefore:    notBefore,
+			NotAfter:     notAfter,
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		}, s)
+		if err != nil {
+			t.Fatalf("Generate(...): %v", err)
+		}
+		return crt
+	}
+
+	cases := map[string]struct {
+		reason string
+		cert   []byte
+		want   bool
+	}{
+		"Unparseable": {
+			reason: "A certificate that can't be parsed should be renewed.",
+			cert:   []byte("not a certificate"),
+			want:   true,
+		},
+		"OtherSigner": {
+			reason: "A certificate that wasn't signed by the current CA should be renewed.",
+			cert:   leaf(other, now, now.Add(validity)),
+			want:   true,
+		},
+		"Fresh": {
+			reason: "A certificate with more than a third of its validity remaining shouldn't be renewed.",
+			cert:   leaf(signer, now.Add(-24*time.Hour), now.Add(validity-24*time.Hour)),
+			want:   false,
+		},
+		"Expiring": {
+			reason: "A certificate with less than a third of its validity remaining should be renewed.",
+			cert:   leaf(signer, now.Add(-70*24*time.Hour), now.Add(20*24*time.Hour)),
+			want:   true,
+		},
+		"LongLivedAndOld": {
+			reason: "A certificate issued for longer than the validity should be renewed once it's as old as an expiring certificate.",
+			cert:   leaf(signer, now.Add(-365*24*time.Hour), now.AddDate(9, 0, 0)),
+			want:   true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := renewalDue(tc.cert, signer, validity, now)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nrenewalDue(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
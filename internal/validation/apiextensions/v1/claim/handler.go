@@ -0,0 +1,198 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package claim contains the admission Handler for composite resource claims.
+package claim
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/xcel"
+)
+
+// WebhookPath is the path at which the claim admission webhook is served.
+const WebhookPath = "/validate-claims"
+
+// Error strings.
+const (
+	errFmtUnexpectedOp = "unexpected operation %q, expected \"CREATE\" or \"UPDATE\""
+	errListXRDs        = "cannot list CompositeResourceDefinitions"
+	errGetNamespace    = "cannot get claim namespace"
+	errConvert         = "cannot convert admission request to CEL variables"
+	errFmtCompileRule  = "cannot compile claim admission rule %q"
+	errFmtEvalRule     = "cannot evaluate claim admission rule %q"
+)
+
+// SetupWebhookWithManager sets up the webhook with the manager.
+func SetupWebhookWithManager(mgr ctrl.Manager, options controller.Options) error {
+	h := NewHandler(mgr.GetClient(), WithLogger(options.Logger.WithValues("webhook", "claims")))
+	mgr.GetWebhookServer().Register(WebhookPath, &webhook.Admission{Handler: h})
+	return nil
+}
+
+// Handler implements the admission Handler for composite resource claims. It
+// evaluates the claim admission rules of the XRD that defines the claim.
+type Handler struct {
+	reader client.Reader
+	log    logging.Logger
+}
+
+// HandlerOption is used to configure the Handler.
+type HandlerOption func(*Handler)
+
+// WithLogger configures the logger for the Handler.
+func WithLogger(l logging.Logger) HandlerOption {
+	return func(h *Handler) {
+		h.log = l
+	}
+}
+
+// NewHandler returns a new Handler.
+func NewHandler(reader client.Reader, opts ...HandlerOption) *Handler {
+	h := &Handler{
+		reader: reader,
+		log:    logging.NewNopLogger(),
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+// Handle handles the admission request, validating the claim against the
+// claim admission rules of the XRD that defines it.
+func (h *Handler) Handle(ctx context.Context, request admission.Request) admission.Response {
+	switch request.Operation {
+	case admissionv1.Create, admissionv1.Update:
+	default:
+		return admission.Errored(http.StatusBadRequest, errors.Errorf(errFmtUnexpectedOp, request.Operation))
+	}
+
+	xrd, err := h.definitionFor(ctx, request.Kind.Group, request.Kind.Kind)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if xrd == nil || len(xrd.Spec.ClaimAdmissionRules) == 0 {
+		return admission.Allowed("")
+	}
+
+	log := h.log.WithValues("kind", request.Kind.Kind, "namespace", request.Namespace, "name", request.Name, "xrd", xrd.GetName())
+
+	vars, err := h.variables(ctx, request)
+	if err != nil {
+		log.Debug(errConvert, "error", err)
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	for _, r := range xrd.Spec.ClaimAdmissionRules {
+		prg, err := Compile(r.Rule)
+		if err != nil {
+			log.Debug("Cannot compile claim admission rule", "rule", r.Rule, "error", err)
+			return admission.Errored(http.StatusInternalServerError, errors.Wrapf(err, errFmtCompileRule, r.Rule))
+		}
+		ok, err := xcel.Evaluate(prg, vars)
+		if err != nil {
+			log.Debug("Cannot evaluate claim admission rule", "rule", r.Rule, "error", err)
+			return admission.Denied(errors.Wrapf(err, errFmtEvalRule, r.Rule).Error())
+		}
+		if !ok {
+			log.Debug("Claim denied by admission rule", "rule", r.Rule)
+			return admission.Denied(message(r))
+		}
+	}
+
+	return admission.Allowed("")
+}
+
+// definitionFor returns the XRD that defines the supplied kind of claim, or
+// nil if no XRD defines it.
+func (h *Handler) definitionFor(ctx context.Context, group, kind string) (*v1.CompositeResourceDefinition, error) {
+	l := &v1.CompositeResourceDefinitionList{}
+	if err := h.reader.List(ctx, l); err != nil {
+		return nil, errors.Wrap(err, errListXRDs)
+	}
+	for i := range l.Items {
+		xrd := &l.Items[i]
+		if xrd.Spec.Group == group && xrd.Spec.ClaimNames != nil && xrd.Spec.ClaimNames.Kind == kind {
+			return xrd, nil
+		}
+	}
+	return nil, nil
+}
+
+// variables returns the variables claim admission rules may reference.
+func (h *Handler) variables(ctx context.Context, request admission.Request) (map[string]any, error) {
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(request.Object.Raw); err != nil {
+		return nil, errors.Wrap(err, "cannot unmarshal claim")
+	}
+
+	var old any
+	if len(request.OldObject.Raw) > 0 {
+		o := &unstructured.Unstructured{}
+		if err := o.UnmarshalJSON(request.OldObject.Raw); err != nil {
+			return nil, errors.Wrap(err, "cannot unmarshal old claim")
+		}
+		old = o.Object
+	}
+
+	ns := &corev1.Namespace{}
+	if err := h.reader.Get(ctx, types.NamespacedName{Name: request.Namespace}, ns); err != nil {
+		return nil, errors.Wrap(err, errGetNamespace)
+	}
+	nsObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(ns)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot convert namespace")
+	}
+
+	ui, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&request.UserInfo)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot convert user info")
+	}
+
+	return map[string]any{
+		VarObject:          obj.Object,
+		VarOldObject:       old,
+		VarRequest:         map[string]any{"userInfo": ui},
+		VarNamespaceObject: nsObj,
+	}, nil
+}
+
+func message(r v1.ClaimAdmissionRule) string {
+	if r.Message != "" {
+		return r.Message
+	}
+	return fmt.Sprintf("failed claim admission rule: %s", r.Rule)
+}
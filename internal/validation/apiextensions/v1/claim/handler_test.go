@@ -0,0 +1,258 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package claim
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+var _ admission.Handler = &Handler{}
+
+func TestHandle(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	xrd := func(rules ...v1.ClaimAdmissionRule) *v1.CompositeResourceDefinition {
+		return &v1.CompositeResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "xdatabases.example.org"},
+			Spec: v1.CompositeResourceDefinitionSpec{
+				Group:               "example.org",
+				Names:               extv1.CustomResourceDefinitionNames{Kind: "XDatabase"},
+				ClaimNames:          &extv1.CustomResourceDefinitionNames{Kind: "Database"},
+				ClaimAdmissionRules: rules,
+			},
+		}
+	}
+
+	reader := func(d *v1.CompositeResourceDefinition, tier string) client.Reader {
+		return &test.MockClient{
+			MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+				if d != nil {
+					obj.(*v1.CompositeResourceDefinitionList).Items = []v1.CompositeResourceDefinition{*d}
+				}
+				return nil
+			}),
+			MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+				if tier != "" {
+					obj.(*corev1.Namespace).SetLabels(map[string]string{"tier": tier})
+				}
+				return nil
+			}),
+		}
+	}
+
+	request := func(op admissionv1.Operation, size string) admission.Request {
+		return admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: op,
+				Kind:      metav1.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Database"},
+				Namespace: "default",
+				Name:      "cool-db",
+				UserInfo:  authenticationv1.UserInfo{Username: "alice", Groups: []string{"dba"}},
+				Object: runtime.RawExtension{
+					Raw: []byte(`{"apiVersion":"example.org/v1","kind":"Database","metadata":{"name":"cool-db"},"spec":{"size":"` + size + `","replicas":3}}`),
+				},
+			},
+		}
+	}
+
+	goldOnly := v1.ClaimAdmissionRule{
+		Rule:    "object.spec.size != 'xlarge' || (has(namespaceObject.metadata.labels) && namespaceObject.metadata.labels['tier'] == 'gold')",
+		Message: "only gold tier namespaces may create xlarge databases",
+	}
+
+	type args struct {
+		reader  client.Reader
+		request admission.Request
+	}
+	type want struct {
+		allowed bool
+		code    int32
+		message string
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"UnexpectedDelete": {
+			reason: "We should return an error if the request is a delete.",
+			args: args{
+				reader:  reader(nil, ""),
+				request: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{Operation: admissionv1.Delete}},
+			},
+			want: want{
+				code:    http.StatusBadRequest,
+				message: errors.Errorf(errFmtUnexpectedOp, admissionv1.Delete).Error(),
+			},
+		},
+		"ListXRDsError": {
+			reason: "We should return an error if we can't list XRDs.",
+			args: args{
+				reader:  &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+				request: request(admissionv1.Create, "small"),
+			},
+			want: want{
+				code:    http.StatusInternalServerError,
+				message: errors.Wrap(errBoom, errListXRDs).Error(),
+			},
+		},
+		"NoXRD": {
+			reason: "We should allow a claim if no XRD defines it.",
+			args: args{
+				reader:  reader(nil, ""),
+				request: request(admissionv1.Create, "xlarge"),
+			},
+			want: want{
+				allowed: true,
+				code:    http.StatusOK,
+			},
+		},
+		"NoRules": {
+			reason: "We should allow a claim if its XRD has no admission rules.",
+			args: args{
+				reader:  reader(xrd(), ""),
+				request: request(admissionv1.Create, "xlarge"),
+			},
+			want: want{
+				allowed: true,
+				code:    http.StatusOK,
+			},
+		},
+		"RulesPass": {
+			reason: "We should allow a claim that passes all of its XRD's admission rules.",
+			args: args{
+				reader:  reader(xrd(goldOnly, v1.ClaimAdmissionRule{Rule: "object.spec.replicas <= 5"}), "gold"),
+				request: request(admissionv1.Create, "xlarge"),
+			},
+			want: want{
+				allowed: true,
+				code:    http.StatusOK,
+			},
+		},
+		"NamespaceRuleFails": {
+			reason: "We should deny a claim that fails a rule referencing its namespace, using the rule's message.",
+			args: args{
+				reader:  reader(xrd(goldOnly), "silver"),
+				request: request(admissionv1.Update, "xlarge"),
+			},
+			want: want{
+				code:    http.StatusForbidden,
+				message: goldOnly.Message,
+			},
+		},
+		"UserInfoRuleFails": {
+			reason: "We should deny a claim that fails a rule referencing the requesting user.",
+			args: args{
+				reader:  reader(xrd(v1.ClaimAdmissionRule{Rule: "'admins' in request.userInfo.groups"}), ""),
+				request: request(admissionv1.Create, "small"),
+			},
+			want: want{
+				code:    http.StatusForbidden,
+				message: "failed claim admission rule: 'admins' in request.userInfo.groups",
+			},
+		},
+		"RuleNotBool": {
+			reason: "We should deny a claim if a rule doesn't evaluate to a boolean.",
+			args: args{
+				reader:  reader(xrd(v1.ClaimAdmissionRule{Rule: "object.spec.size"}), ""),
+				request: request(admissionv1.Create, "small"),
+			},
+			want: want{
+				code:    http.StatusForbidden,
+				message: errors.Wrapf(errors.New("expression evaluated to string, not a boolean"), errFmtEvalRule, "object.spec.size").Error(),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			h := NewHandler(tc.args.reader)
+			resp := h.Handle(context.Background(), tc.args.request)
+
+			if diff := cmp.Diff(tc.want.allowed, resp.Allowed); diff != "" {
+				t.Errorf("\n%s\nHandle(...): -want allowed, +got allowed:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.code, resp.Result.Code); diff != "" {
+				t.Errorf("\n%s\nHandle(...): -want code, +got code:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.message, resp.Result.Message); diff != "" {
+				t.Errorf("\n%s\nHandle(...): -want message, +got message:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestValidateRules(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		rules  []v1.ClaimAdmissionRule
+		want   error
+	}{
+		"NoRules": {
+			reason: "No rules are valid.",
+		},
+		"ValidRules": {
+			reason: "Rules that compile should be valid.",
+			rules: []v1.ClaimAdmissionRule{
+				{Rule: "object.spec.size != 'xlarge'"},
+				{Rule: "request.userInfo.username.startsWith('system:')"},
+			},
+		},
+		"SyntaxError": {
+			reason: "Rules with syntax errors should be invalid.",
+			rules:  []v1.ClaimAdmissionRule{{Rule: "object.spec.size =="}},
+			want:   cmpopts.AnyError,
+		},
+		"UndeclaredVariable": {
+			reason: "Rules that reference undeclared variables should be invalid.",
+			rules:  []v1.ClaimAdmissionRule{{Rule: "claim.spec.size == 'xlarge'"}},
+			want:   cmpopts.AnyError,
+		},
+		"NotBool": {
+			reason: "Rules that can't evaluate to a boolean should be invalid.",
+			rules:  []v1.ClaimAdmissionRule{{Rule: "'xlarge'"}},
+			want:   cmpopts.AnyError,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateRules(tc.rules)
+			if diff := cmp.Diff(tc.want, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidateRules(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package claim
+
+import (
+	"github.com/google/cel-go/cel"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/xcel"
+)
+
+// Variables that may be referenced by a claim admission rule.
+const (
+	VarObject          = "object"
+	VarOldObject       = "oldObject"
+	VarRequest         = "request"
+	VarNamespaceObject = "namespaceObject"
+)
+
+// rules caches compiled claim admission rules. The same rules are evaluated
+// for every claim of a kind.
+var rules = xcel.NewPrograms([]string{VarObject, VarOldObject, VarRequest, VarNamespaceObject})
+
+// Compile the supplied claim admission rule, or return the cached program if
+// it was compiled before. Rules must evaluate to a boolean.
+func Compile(rule string) (cel.Program, error) {
+	return rules.Compile(rule)
+}
+
+// ValidateRules returns an error if any of the supplied claim admission rules
+// can't be compiled.
+func ValidateRules(rs []v1.ClaimAdmissionRule) error {
+	errs := make([]error, 0)
+	for i, r := range rs {
+		if _, err := Compile(r.Rule); err != nil {
+			errs = append(errs, errors.Wrapf(err, "spec.claimAdmissionRules[%d]", i))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -35,6 +35,7 @@ import (
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	"github.com/crossplane/crossplane/internal/features"
+	"github.com/crossplane/crossplane/internal/xfn"
 	"github.com/crossplane/crossplane/pkg/validation/apiextensions/v1/composition"
 )
 
@@ -48,6 +49,8 @@ const (
 const (
 	errNotComposition = "supplied object was not a Composition"
 	errValidationMode = "cannot get validation mode"
+	errConditions     = "invalid pipeline step conditions"
+	errReadiness      = "invalid readiness check expressions"
 
 	errFmtTooManyCRDs = "more than one CRD found for %s.%s: %v"
 	errFmtGetCRDs     = "cannot get the needed CRDs: %v"
@@ -91,6 +94,14 @@ func (v *validator) ValidateCreate(ctx context.Context, obj runtime.Object) (adm
 		return warns, kerrors.NewInvalid(comp.GroupVersionKind().GroupKind(), comp.GetName(), validationErrs)
 	}
 
+	if err := xfn.ValidateConditions(comp.Spec.Pipeline); err != nil {
+		return warns, errors.Wrap(err, errConditions)
+	}
+
+	if err := ValidateReadinessExpressions(comp.Spec.Resources); err != nil {
+		return warns, errors.Wrap(err, errReadiness)
+	}
+
 	if !v.options.Features.Enabled(features.EnableBetaCompositionWebhookSchemaValidation) {
 		return warns, nil
 	}
@@ -0,0 +1,44 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composition
+
+import (
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/xcel"
+)
+
+// readinessExpressions caches compiled MatchExpression readiness checks.
+var readinessExpressions = xcel.NewPrograms([]string{v1.ReadinessCheckVarObject})
+
+// ValidateReadinessExpressions returns an error if any of the MatchExpression
+// readiness checks of the supplied composed templates can't be compiled.
+func ValidateReadinessExpressions(resources []v1.ComposedTemplate) error {
+	errs := make([]error, 0)
+	for i := range resources {
+		for j, rc := range resources[i].ReadinessChecks {
+			if rc.Type != v1.ReadinessCheckTypeMatchExpression {
+				continue
+			}
+			if _, err := readinessExpressions.Compile(rc.MatchExpression); err != nil {
+				errs = append(errs, errors.Wrapf(err, "spec.resources[%d].readinessChecks[%d].matchExpression", i, j))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composition
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+func TestValidateReadinessExpressions(t *testing.T) {
+	cases := map[string]struct {
+		reason    string
+		resources []v1.ComposedTemplate
+		wantErr   bool
+	}{
+		"NoExpressions": {
+			reason:    "Readiness checks that aren't match expressions should be valid.",
+			resources: []v1.ComposedTemplate{{ReadinessChecks: []v1.ReadinessCheck{{Type: v1.ReadinessCheckTypeNone}}}},
+		},
+		"ValidExpression": {
+			reason:    "A match expression that compiles should be valid.",
+			resources: []v1.ComposedTemplate{{ReadinessChecks: []v1.ReadinessCheck{{Type: v1.ReadinessCheckTypeMatchExpression, MatchExpression: "object.status.ready"}}}},
+		},
+		"SyntaxError": {
+			reason:    "A match expression with a syntax error should be invalid.",
+			resources: []v1.ComposedTemplate{{ReadinessChecks: []v1.ReadinessCheck{{Type: v1.ReadinessCheckTypeMatchExpression, MatchExpression: "object.status.("}}}},
+			wantErr:   true,
+		},
+		"NotBool": {
+			reason:    "A match expression that is known not to evaluate to a boolean should be invalid.",
+			resources: []v1.ComposedTemplate{{ReadinessChecks: []v1.ReadinessCheck{{Type: v1.ReadinessCheckTypeMatchExpression, MatchExpression: "'cool'"}}}},
+			wantErr:   true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateReadinessExpressions(tc.resources)
+			if diff := cmp.Diff(tc.wantErr, err != nil); diff != "" {
+				t.Errorf("\n%s\nValidateReadinessExpressions(...): -want error, +got error:\n%s\n%v", tc.reason, diff, err)
+			}
+		})
+	}
+}
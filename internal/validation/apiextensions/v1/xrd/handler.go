@@ -33,6 +33,7 @@ import (
 	xperrors "github.com/crossplane/crossplane-runtime/pkg/errors"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/validation/apiextensions/v1/claim"
 	"github.com/crossplane/crossplane/internal/xcrd"
 )
 
@@ -41,6 +42,8 @@ const (
 	errNotCompositeResourceDefinition = "supplied object was not a CompositeResourceDefinition"
 
 	errUnexpectedType = "unexpected type"
+
+	errInvalidClaimAdmissionRules = "invalid claim admission rules"
 )
 
 // SetupWebhookWithManager sets up the webhook with the manager.
@@ -85,6 +88,9 @@ func (v *validator) ValidateCreate(ctx context.Context, obj runtime.Object) (war
 	if validationErr != nil {
 		return validationWarns, validationErr.ToAggregate()
 	}
+	if err := claim.ValidateRules(in.Spec.ClaimAdmissionRules); err != nil {
+		return warns, xperrors.Wrap(err, errInvalidClaimAdmissionRules)
+	}
 	crds, err := getAllCRDsForXRD(in)
 	if err != nil {
 		return warns, xperrors.Wrap(err, "cannot get CRDs for CompositeResourceDefinition")
@@ -120,6 +126,9 @@ func (v *validator) ValidateUpdate(ctx context.Context, old, new runtime.Object)
 	if validationErr != nil {
 		return validationWarns, validationErr.ToAggregate()
 	}
+	if err := claim.ValidateRules(newObj.Spec.ClaimAdmissionRules); err != nil {
+		return warns, xperrors.Wrap(err, errInvalidClaimAdmissionRules)
+	}
 	crds, err := getAllCRDsForXRD(newObj)
 	if err != nil {
 		return warns, xperrors.Wrap(err, "cannot get CRDs for CompositeResourceDefinition")
@@ -515,6 +515,30 @@ func TestValidateCreate(t *testing.T) {
 				},
 			},
 		},
+		"FailOnInvalidClaimAdmissionRule": {
+			args: args{
+				obj: &v1.CompositeResourceDefinition{
+					Spec: v1.CompositeResourceDefinitionSpec{
+						Names: extv1.CustomResourceDefinitionNames{
+							Kind:     "A",
+							Plural:   "as",
+							Singular: "a",
+							ListKind: "AList",
+						},
+						ClaimNames: &extv1.CustomResourceDefinitionNames{
+							Kind:     "B",
+							Plural:   "bs",
+							Singular: "b",
+							ListKind: "BList",
+						},
+						ClaimAdmissionRules: []v1.ClaimAdmissionRule{
+							{Rule: "object.spec.size =="},
+						},
+					},
+				},
+			},
+			err: cmpopts.AnyError,
+		},
 		"FailOnClaim": {
 			args: args{
 				obj: &v1.CompositeResourceDefinition{
@@ -0,0 +1,151 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package xcel compiles and evaluates the Common Expression Language (CEL)
+// expressions Crossplane supports, i.e. MatchExpression readiness checks,
+// pipeline step conditions, and claim admission rules.
+package xcel
+
+import (
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// Error strings.
+const (
+	errCreateEnv        = "cannot create CEL environment"
+	errFmtNotBool       = "expression must evaluate to a boolean, not %s"
+	errFmtResultNotBool = "expression evaluated to %T, not a boolean"
+)
+
+// DefaultMaxPrograms is the default maximum number of compiled programs
+// Programs caches.
+const DefaultMaxPrograms = 1024
+
+// Programs compiles boolean expressions in a CEL environment, and caches the
+// compiled programs. Expressions are typically evaluated many times - e.g. a
+// readiness check is evaluated every time a composite resource is reconciled
+// - so compiling them only once saves a lot of work. Programs is safe for
+// concurrent use.
+type Programs struct {
+	vars []string
+	max  int
+
+	once sync.Once
+	env  *cel.Env
+	err  error
+
+	mu       sync.RWMutex
+	programs map[string]cel.Program
+}
+
+// A ProgramsOption configures Programs.
+type ProgramsOption func(p *Programs)
+
+// WithMaxPrograms configures the maximum number of compiled programs to
+// cache. The cache is emptied when it grows beyond this size. Expressions are
+// read from Crossplane's API types, so the cache would otherwise grow without
+// bound as those types are updated.
+func WithMaxPrograms(n int) ProgramsOption {
+	return func(p *Programs) {
+		p.max = n
+	}
+}
+
+// NewPrograms returns Programs that compile expressions in a CEL environment
+// with the supplied variables. All variables are dynamically typed. The
+// environment also includes the CEL strings extension.
+func NewPrograms(vars []string, o ...ProgramsOption) *Programs {
+	p := &Programs{
+		vars:     vars,
+		max:      DefaultMaxPrograms,
+		programs: make(map[string]cel.Program),
+	}
+	for _, fn := range o {
+		fn(p)
+	}
+	return p
+}
+
+// Compile the supplied expression, or return the cached program if it was
+// compiled before. The expression must evaluate to a boolean. Expressions
+// that reference dynamically typed variables may only be known to evaluate
+// to a boolean at runtime. Expressions that fail to compile aren't cached.
+func (p *Programs) Compile(expr string) (cel.Program, error) {
+	p.mu.RLock()
+	prg, ok := p.programs[expr]
+	p.mu.RUnlock()
+	if ok {
+		return prg, nil
+	}
+
+	env, err := p.environment()
+	if err != nil {
+		return nil, err
+	}
+	ast, iss := env.Compile(expr)
+	if iss.Err() != nil {
+		return nil, iss.Err()
+	}
+	if t := ast.OutputType(); !t.IsExactType(cel.BoolType) && !t.IsExactType(cel.DynType) {
+		return nil, errors.Errorf(errFmtNotBool, t)
+	}
+	prg, err = env.Program(ast)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.programs) >= p.max {
+		p.programs = make(map[string]cel.Program)
+	}
+	p.programs[expr] = prg
+	return prg, nil
+}
+
+// environment lazily creates the CEL environment, so that Programs can be
+// declared as package variables.
+func (p *Programs) environment() (*cel.Env, error) {
+	p.once.Do(func() {
+		opts := make([]cel.EnvOption, 0, len(p.vars)+1)
+		for _, v := range p.vars {
+			opts = append(opts, cel.Variable(v, cel.DynType))
+		}
+		opts = append(opts, ext.Strings())
+		env, err := cel.NewEnv(opts...)
+		p.env, p.err = env, errors.Wrap(err, errCreateEnv)
+	})
+	return p.env, p.err
+}
+
+// Evaluate the supplied compiled program against the supplied variables. It
+// returns an error if the program doesn't evaluate to a boolean.
+func Evaluate(prg cel.Program, vars map[string]any) (bool, error) {
+	out, _, err := prg.Eval(vars)
+	if err != nil {
+		return false, err
+	}
+	ok, isBool := out.Value().(bool)
+	if !isBool {
+		return false, errors.Errorf(errFmtResultNotBool, out.Value())
+	}
+	return ok, nil
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xcel
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestProgramsCompile(t *testing.T) {
+	type want struct {
+		cached int
+		err    bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		exprs  []string
+		max    int
+		want   want
+	}{
+		"Bool": {
+			reason: "An expression that evaluates to a boolean should be compiled and cached.",
+			exprs:  []string{"object.ready"},
+			max:    DefaultMaxPrograms,
+			want:   want{cached: 1},
+		},
+		"SameExpression": {
+			reason: "An expression should only be cached once.",
+			exprs:  []string{"object.ready", "object.ready"},
+			max:    DefaultMaxPrograms,
+			want:   want{cached: 1},
+		},
+		"NotBool": {
+			reason: "An expression that is known not to evaluate to a boolean should not compile, or be cached.",
+			exprs:  []string{"'cool'"},
+			max:    DefaultMaxPrograms,
+			want:   want{err: true},
+		},
+		"UndeclaredVariable": {
+			reason: "An expression that references an undeclared variable should not compile.",
+			exprs:  []string{"claim.ready"},
+			max:    DefaultMaxPrograms,
+			want:   want{err: true},
+		},
+		"StringsExtension": {
+			reason: "An expression should be able to use the CEL strings extension.",
+			exprs:  []string{"object.name.lowerAscii() == 'cool'"},
+			max:    DefaultMaxPrograms,
+			want:   want{cached: 1},
+		},
+		"MaxPrograms": {
+			reason: "The cache should be emptied when it grows beyond its maximum size.",
+			exprs:  []string{"object.a", "object.b", "object.c"},
+			max:    2,
+			want:   want{cached: 1},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := NewPrograms([]string{"object"}, WithMaxPrograms(tc.max))
+			var err error
+			for _, e := range tc.exprs {
+				_, err = p.Compile(e)
+			}
+			if diff := cmp.Diff(tc.want.err, err != nil); diff != "" {
+				t.Errorf("\n%s\nCompile(...): -want error, +got error:\n%s\n%v", tc.reason, diff, err)
+			}
+			if diff := cmp.Diff(tc.want.cached, len(p.programs)); diff != "" {
+				t.Errorf("\n%s\nCompile(...): -want cached, +got cached:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	type want struct {
+		ok  bool
+		err bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		expr   string
+		vars   map[string]any
+		want   want
+	}{
+		"True": {
+			reason: "An expression that evaluates to true should return true.",
+			expr:   "object.ready",
+			vars:   map[string]any{"object": map[string]any{"ready": true}},
+			want:   want{ok: true},
+		},
+		"False": {
+			reason: "An expression that evaluates to false should return false.",
+			expr:   "object.ready",
+			vars:   map[string]any{"object": map[string]any{"ready": false}},
+			want:   want{ok: false},
+		},
+		"MissingField": {
+			reason: "We should return an error if an expression references a field that doesn't exist.",
+			expr:   "object.ready",
+			vars:   map[string]any{"object": map[string]any{}},
+			want:   want{err: true},
+		},
+		"NotBool": {
+			reason: "We should return an error if an expression doesn't evaluate to a boolean at runtime.",
+			expr:   "object.ready",
+			vars:   map[string]any{"object": map[string]any{"ready": "yes"}},
+			want:   want{err: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			prg, err := NewPrograms([]string{"object"}).Compile(tc.expr)
+			if err != nil {
+				t.Fatalf("Compile(...): %v", err)
+			}
+			ok, err := Evaluate(prg, tc.vars)
+			if diff := cmp.Diff(tc.want.err, err != nil); diff != "" {
+				t.Errorf("\n%s\nEvaluate(...): -want error, +got error:\n%s\n%v", tc.reason, diff, err)
+			}
+			if diff := cmp.Diff(tc.want.ok, ok); diff != "" {
+				t.Errorf("\n%s\nEvaluate(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
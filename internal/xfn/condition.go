@@ -0,0 +1,71 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xfn
+
+import (
+	"github.com/google/cel-go/cel"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/xcel"
+)
+
+// Variables that may be referenced by a pipeline step condition.
+const (
+	VarCompositeResource = "xr"
+	VarEnvironment       = "environment"
+)
+
+// conditions caches compiled pipeline step conditions. Every condition is
+// evaluated each time a composite resource is reconciled.
+var conditions = xcel.NewPrograms([]string{VarCompositeResource, VarEnvironment})
+
+// CompileCondition compiles the supplied pipeline step condition, or returns
+// the cached program if it was compiled before. Conditions must evaluate to a
+// boolean.
+func CompileCondition(condition string) (cel.Program, error) {
+	return conditions.Compile(condition)
+}
+
+// EvaluateCondition evaluates the supplied compiled condition against the
+// supplied composite resource and environment, both of which are expected to
+// be unstructured content, i.e. map[string]any.
+func EvaluateCondition(prg cel.Program, xr, environment map[string]any) (bool, error) {
+	if environment == nil {
+		environment = map[string]any{}
+	}
+	return xcel.Evaluate(prg, map[string]any{
+		VarCompositeResource: xr,
+		VarEnvironment:       environment,
+	})
+}
+
+// ValidateConditions returns an error if any of the conditions of the supplied
+// pipeline steps can't be compiled.
+func ValidateConditions(steps []v1.PipelineStep) error {
+	errs := make([]error, 0)
+	for i, s := range steps {
+		if s.Condition == nil {
+			continue
+		}
+		if _, err := CompileCondition(*s.Condition); err != nil {
+			errs = append(errs, errors.Wrapf(err, "spec.pipeline[%d].condition", i))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xfn
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/utils/ptr"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+func TestEvaluateCondition(t *testing.T) {
+	xr := map[string]any{
+		"spec": map[string]any{
+			"backup": map[string]any{
+				"enabled": true,
+			},
+		},
+	}
+
+	type args struct {
+		condition   string
+		xr          map[string]any
+		environment map[string]any
+	}
+	type want struct {
+		met bool
+		err bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ConditionMet": {
+			reason: "A condition that evaluates to true should be met.",
+			args: args{
+				condition: "xr.spec.backup.enabled",
+				xr:        xr,
+			},
+			want: want{
+				met: true,
+			},
+		},
+		"ConditionNotMet": {
+			reason: "A condition that evaluates to false should not be met.",
+			args: args{
+				condition: "has(xr.spec.replicas) && xr.spec.replicas > 1",
+				xr:        xr,
+			},
+			want: want{
+				met: false,
+			},
+		},
+		"EnvironmentCondition": {
+			reason: "A condition should be able to reference the environment.",
+			args: args{
+				condition: "environment.region == 'us-east-1'",
+				xr:        xr,
+				environment: map[string]any{
+					"region": "us-east-1",
+				},
+			},
+			want: want{
+				met: true,
+			},
+		},
+		"MissingField": {
+			reason: "We should return an error if a condition references a field that doesn't exist.",
+			args: args{
+				condition: "xr.spec.missing",
+				xr:        xr,
+			},
+			want: want{
+				err: true,
+			},
+		},
+		"NotBool": {
+			reason: "We should return an error if a condition doesn't evaluate to a boolean.",
+			args: args{
+				condition: "xr.spec.backup",
+				xr:        xr,
+			},
+			want: want{
+				err: true,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			prg, err := CompileCondition(tc.args.condition)
+			if err != nil {
+				t.Fatalf("CompileCondition(...): %v", err)
+			}
+			met, err := EvaluateCondition(prg, tc.args.xr, tc.args.environment)
+			if diff := cmp.Diff(tc.want.err, err != nil); diff != "" {
+				t.Errorf("\n%s\nEvaluateCondition(...): -want error, +got error:\n%s\n%v", tc.reason, diff, err)
+			}
+			if diff := cmp.Diff(tc.want.met, met); diff != "" {
+				t.Errorf("\n%s\nEvaluateCondition(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestValidateConditions(t *testing.T) {
+	cases := map[string]struct {
+		reason  string
+		steps   []v1.PipelineStep
+		wantErr bool
+	}{
+		"NoConditions": {
+			reason: "Steps without conditions should be valid.",
+			steps:  []v1.PipelineStep{{Step: "a"}},
+		},
+		"ValidCondition": {
+			reason: "A condition that compiles should be valid.",
+			steps:  []v1.PipelineStep{{Step: "a", Condition: ptr.To("xr.spec.backup.enabled")}},
+		},
+		"SyntaxError": {
+			reason:  "A condition with a syntax error should be invalid.",
+			steps:   []v1.PipelineStep{{Step: "a", Condition: ptr.To("xr.spec.(")}},
+			wantErr: true,
+		},
+		"NotBool": {
+			reason:  "A condition that is known not to evaluate to a boolean should be invalid.",
+			steps:   []v1.PipelineStep{{Step: "a", Condition: ptr.To("'cool'")}},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateConditions(tc.steps)
+			if diff := cmp.Diff(tc.wantErr, err != nil); diff != "" {
+				t.Errorf("\n%s\nValidateConditions(...): -want error, +got error:\n%s\n%v", tc.reason, diff, err)
+			}
+		})
+	}
+}
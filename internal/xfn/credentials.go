@@ -0,0 +1,146 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xfn
+
+import (
+	"context"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/crossplane/crossplane-runtime/pkg/certificates"
+)
+
+const (
+	errLoadTLSFiles = "cannot load TLS certificates"
+	errStatTLSFiles = "cannot stat TLS certificates"
+
+	errOverrideServerName = "overriding the server name is not supported"
+)
+
+// ReloadingCredentials are mutual TLS gRPC transport credentials that are
+// loaded from a CA certificate, certificate, and key file. The files are
+// loaded again when they change, so that certificates can be rotated without
+// restarting Crossplane. New credentials apply to new connections; existing
+// connections keep the credentials they were established with.
+type ReloadingCredentials struct {
+	caPath   string
+	certPath string
+	keyPath  string
+
+	mx      sync.Mutex
+	creds   credentials.TransportCredentials
+	modTime time.Time
+}
+
+// NewReloadingCredentials loads mutual TLS gRPC client credentials from the
+// supplied files.
+func NewReloadingCredentials(caPath, certPath, keyPath string) (*ReloadingCredentials, error) {
+	c := &ReloadingCredentials{caPath: caPath, certPath: certPath, keyPath: keyPath}
+	if _, err := c.current(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// current returns the current credentials, loading them again if any of their
+// files have changed since they were last loaded. If the files can't be loaded
+// (for example because they're only partially written) the previous
+// credentials are returned, and loading is retried next time.
+func (c *ReloadingCredentials) current() (credentials.TransportCredentials, error) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	latest := time.Time{}
+	for _, p := range []string{c.caPath, c.certPath, c.keyPath} {
+		fi, err := os.Stat(p)
+		if err != nil {
+			if c.creds != nil {
+				return c.creds, nil
+			}
+			return nil, errors.Wrap(err, errStatTLSFiles)
+		}
+		if fi.ModTime().After(latest) {
+			latest = fi.ModTime()
+		}
+	}
+
+	if c.creds != nil && !latest.After(c.modTime) {
+		return c.creds, nil
+	}
+
+	cfg, err := certificates.LoadMTLSConfig(c.caPath, c.certPath, c.keyPath, false)
+	if err != nil {
+		if c.creds != nil {
+			return c.creds, nil
+		}
+		return nil, errors.Wrap(err, errLoadTLSFiles)
+	}
+
+	c.creds = credentials.NewTLS(cfg)
+	c.modTime = latest
+	return c.creds, nil
+}
+
+// ClientHandshake does the authentication handshake for a client connection
+// using the current credentials.
+func (c *ReloadingCredentials) ClientHandshake(ctx context.Context, authority string, conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	cur, err := c.current()
+	if err != nil {
+		return nil, nil, err
+	}
+	return cur.ClientHandshake(ctx, authority, conn)
+}
+
+// ServerHandshake does the authentication handshake for a server connection
+// using the current credentials.
+func (c *ReloadingCredentials) ServerHandshake(conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	cur, err := c.current()
+	if err != nil {
+		return nil, nil, err
+	}
+	return cur.ServerHandshake(conn)
+}
+
+// Info provides the ProtocolInfo of the current credentials.
+func (c *ReloadingCredentials) Info() credentials.ProtocolInfo {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	return c.creds.Info()
+}
+
+// Clone returns credentials that load the same files.
+func (c *ReloadingCredentials) Clone() credentials.TransportCredentials {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	return &ReloadingCredentials{
+		caPath:   c.caPath,
+		certPath: c.certPath,
+		keyPath:  c.keyPath,
+		creds:    c.creds.Clone(),
+		modTime:  c.modTime,
+	}
+}
+
+// OverrideServerName is deprecated by gRPC, and not supported.
+func (c *ReloadingCredentials) OverrideServerName(_ string) error {
+	return errors.New(errOverrideServerName)
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xfn
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane/internal/initializer"
+)
+
+func TestReloadingCredentials(t *testing.T) {
+	dir := t.TempDir()
+	ca := filepath.Join(dir, "ca.crt")
+	crt := filepath.Join(dir, "tls.crt")
+	key := filepath.Join(dir, "tls.key")
+
+	write := func(serial int64, mod time.Time) {
+		tmpl := &x509.Certificate{
+			SerialNumber:          big.NewInt(serial),
+			Subject:               pkix.Name{CommonName: "crossplane"},
+			NotBefore:             time.Now(),
+			NotAfter:              time.Now().Add(time.Hour),
+			IsCA:                  true,
+			BasicConstraintsValid: true,
+			KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		}
+		k, c, err := initializer.NewCertGenerator().Generate(tmpl, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for p, b := range map[string][]byte{ca: c, crt: c, key: k} {
+			if err := os.WriteFile(p, b, 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Chtimes(p, mod, mod); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	now := time.Now()
+	write(1, now.Add(-time.Hour))
+
+	c, err := NewReloadingCredentials(ca, crt, key)
+	if err != nil {
+		t.Fatalf("NewReloadingCredentials(...): %s", err)
+	}
+	first, _ := c.current()
+
+	again, _ := c.current()
+	if again != first {
+		t.Errorf("current(): credentials were reloaded even though their files didn't change")
+	}
+
+	// A partially written key shouldn't break existing credentials.
+	if err := os.WriteFile(key, []byte("broken"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	broken, err := c.current()
+	if err != nil {
+		t.Errorf("current(): unexpected error loading broken files: %s", err)
+	}
+	if broken != first {
+		t.Errorf("current(): credentials changed even though the new files were broken")
+	}
+
+	write(2, now)
+	rotated, err := c.current()
+	if err != nil {
+		t.Errorf("current(): unexpected error loading rotated files: %s", err)
+	}
+	if rotated == first {
+		t.Errorf("current(): credentials weren't reloaded after their files changed")
+	}
+}
+
+func TestNewReloadingCredentialsError(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := NewReloadingCredentials(filepath.Join(dir, "ca.crt"), filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")); err == nil {
+		t.Errorf("NewReloadingCredentials(...): expected an error loading files that don't exist")
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"crypto/tls"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/pkg/errors"
@@ -45,6 +46,8 @@ const (
 	errFmtRunFunction   = "cannot run Function %q"
 	errFmtEmptyEndpoint = "cannot determine gRPC target: active FunctionRevision %q has an empty status.endpoint"
 	errFmtDialFunction  = "cannot gRPC dial target %q from status.endpoint of active FunctionRevision %q"
+	errFmtQueueFull     = "cannot run Function %q: too many Function runs are already queued"
+	errFmtWaitToRun     = "cannot run Function %q: gave up waiting for another Function run to finish"
 )
 
 // TODO(negz): Should any of these be configurable?
@@ -71,6 +74,26 @@ type PackagedFunctionRunner struct {
 
 	connsMx sync.RWMutex
 	conns   map[string]*grpc.ClientConn
+	dialed  map[string]time.Time
+
+	// maxConnAge is how long a connection may be used before it's redialed.
+	// Connections are never redialed if it's zero.
+	maxConnAge time.Duration
+
+	// runs limits how many Functions may run concurrently. It's nil if
+	// there's no limit. fnRuns limits how many runs of particular Functions
+	// may happen concurrently. It's not modified after construction.
+	runs      *limiter
+	fnRuns    map[string]*limiter
+	maxQueued int64
+
+	// maxResponseSize is the largest response, in bytes, we'll accept from
+	// a Function. gRPC's default applies if it's zero.
+	maxResponseSize int
+
+	// cache caches responses that have a TTL. It's nil if responses aren't
+	// cached.
+	cache *ResponseCache
 
 	log logging.Logger
 }
@@ -100,6 +123,14 @@ func WithTLSConfig(cfg *tls.Config) PackagedFunctionRunnerOption {
 	}
 }
 
+// WithTransportCredentials configures the gRPC transport credentials the
+// PackagedFunctionRunner should use, e.g. ReloadingCredentials.
+func WithTransportCredentials(c credentials.TransportCredentials) PackagedFunctionRunnerOption {
+	return func(r *PackagedFunctionRunner) {
+		r.creds = c
+	}
+}
+
 // WithInterceptorCreators configures the interceptors the
 // PackagedFunctionRunner should create for each function.
 func WithInterceptorCreators(ics ...InterceptorCreator) PackagedFunctionRunnerOption {
@@ -108,6 +139,72 @@ func WithInterceptorCreators(ics ...InterceptorCreator) PackagedFunctionRunnerOp
 	}
 }
 
+// WithMaxConcurrentRuns configures how many Functions the
+// PackagedFunctionRunner may run concurrently. Runs beyond this limit wait in a
+// queue until an earlier run finishes, or until their context is done. There's
+// no limit if n isn't positive.
+func WithMaxConcurrentRuns(n int) PackagedFunctionRunnerOption {
+	return func(r *PackagedFunctionRunner) {
+		r.runs = newLimiter(n)
+	}
+}
+
+// WithMaxConcurrentRunsPerFunction configures how many runs of particular
+// Functions, keyed by name, may happen concurrently. This limits Functions
+// that are known to be expensive to run so that they can't starve others.
+// Runs beyond a Function's limit wait in a queue, and don't count toward the
+// limit configured by WithMaxConcurrentRuns while they wait. A Function isn't
+// limited if its limit isn't positive.
+func WithMaxConcurrentRunsPerFunction(limits map[string]int) PackagedFunctionRunnerOption {
+	return func(r *PackagedFunctionRunner) {
+		r.fnRuns = make(map[string]*limiter, len(limits))
+		for name, n := range limits {
+			if l := newLimiter(n); l != nil {
+				r.fnRuns[name] = l
+			}
+		}
+	}
+}
+
+// WithMaxResponseSize configures the largest RunFunctionResponse, in bytes,
+// the PackagedFunctionRunner will accept from a Function. Functions that
+// return many or large desired composed resources may need more than gRPC's
+// default of 4MiB, which applies if n isn't positive.
+func WithMaxResponseSize(n int) PackagedFunctionRunnerOption {
+	return func(r *PackagedFunctionRunner) {
+		r.maxResponseSize = n
+	}
+}
+
+// WithResponseCache configures the PackagedFunctionRunner to cache responses
+// that have a TTL in the supplied cache.
+func WithResponseCache(c *ResponseCache) PackagedFunctionRunnerOption {
+	return func(r *PackagedFunctionRunner) {
+		r.cache = c
+	}
+}
+
+// WithMaxQueuedRuns configures how many Function runs may wait in the queue
+// when the maximum number of concurrent runs is reached. Each Function with its
+// own concurrency limit has its own queue of the same size. Runs beyond this
+// limit fail immediately. The queue is unbounded if n isn't positive.
+func WithMaxQueuedRuns(n int) PackagedFunctionRunnerOption {
+	return func(r *PackagedFunctionRunner) {
+		r.maxQueued = int64(n)
+	}
+}
+
+// WithMaxConnectionAge configures the PackagedFunctionRunner to redial a
+// Function's gRPC client connection once it's older than d. Redialing resolves
+// the Function's endpoint again, so that requests are balanced across any
+// Function Pods that were added since, e.g. by a HorizontalPodAutoscaler.
+// Connections are never redialed if d isn't positive.
+func WithMaxConnectionAge(d time.Duration) PackagedFunctionRunnerOption {
+	return func(r *PackagedFunctionRunner) {
+		r.maxConnAge = d
+	}
+}
+
 // NewPackagedFunctionRunner returns a FunctionRunner that runs a Function by
 // making a gRPC call to a Function package's runtime.
 func NewPackagedFunctionRunner(c client.Reader, o ...PackagedFunctionRunnerOption) *PackagedFunctionRunner {
@@ -115,6 +212,7 @@ func NewPackagedFunctionRunner(c client.Reader, o ...PackagedFunctionRunnerOptio
 		client: c,
 		creds:  insecure.NewCredentials(),
 		conns:  make(map[string]*grpc.ClientConn),
+		dialed: make(map[string]time.Time),
 		log:    logging.NewNopLogger(),
 	}
 
@@ -127,7 +225,24 @@ func NewPackagedFunctionRunner(c client.Reader, o ...PackagedFunctionRunnerOptio
 
 // RunFunction sends the supplied RunFunctionRequest to the named Function. The
 // function is expected to be an installed Function.pkg.crossplane.io package.
+// If a response cache is configured, an unexpired cached response to an
+// identical request is returned without running the Function.
 func (r *PackagedFunctionRunner) RunFunction(ctx context.Context, name string, req *v1beta1.RunFunctionRequest) (*v1beta1.RunFunctionResponse, error) {
+	if r.cache != nil {
+		if rsp, ok := r.cache.Get(name, req); ok {
+			return rsp, nil
+		}
+	}
+
+	// We wait to run before we get a client connection. A connection that
+	// exceeds its max age is closed runFunctionTimeout after it's replaced,
+	// so a run must not wait in the queue while holding one.
+	release, err := r.acquire(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	conn, err := r.getClientConn(ctx, name)
 	if err != nil {
 		return nil, errors.Wrapf(err, errFmtGetClientConn, name)
@@ -138,7 +253,79 @@ func (r *PackagedFunctionRunner) RunFunction(ctx context.Context, name string, r
 	defer cancel()
 
 	rsp, err := v1beta1.NewFunctionRunnerServiceClient(conn).RunFunction(ctx, req)
-	return rsp, errors.Wrapf(err, errFmtRunFunction, name)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtRunFunction, name)
+	}
+	if r.cache != nil {
+		r.cache.Set(name, req, rsp)
+	}
+	return rsp, nil
+}
+
+// acquire waits until the named Function may run. It returns a function that
+// must be called when the run is finished. Waiting is abandoned when the
+// supplied context is done, so a run never waits past its caller's deadline.
+func (r *PackagedFunctionRunner) acquire(ctx context.Context, name string) (func(), error) {
+	// We wait for the Function's own limit first, so that runs of a busy
+	// Function don't hold on to the shared limit while they wait.
+	releaseFn, err := r.fnRuns[name].acquire(ctx, name, r.maxQueued)
+	if err != nil {
+		return nil, err
+	}
+	release, err := r.runs.acquire(ctx, name, r.maxQueued)
+	if err != nil {
+		releaseFn()
+		return nil, err
+	}
+	return func() {
+		release()
+		releaseFn()
+	}, nil
+}
+
+// A limiter limits how many Function runs may happen concurrently. A nil
+// limiter doesn't limit runs.
+type limiter struct {
+	runs   chan struct{}
+	queued atomic.Int64
+}
+
+// newLimiter returns a limiter that allows n concurrent runs. It returns nil if
+// n isn't positive.
+func newLimiter(n int) *limiter {
+	if n <= 0 {
+		return nil
+	}
+	return &limiter{runs: make(chan struct{}, n)}
+}
+
+// acquire waits until the named Function may run, or fails immediately if more
+// than maxQueued runs are already waiting. The queue is unbounded if maxQueued
+// isn't positive.
+func (l *limiter) acquire(ctx context.Context, name string, maxQueued int64) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	// Fast path - we're under the concurrency limit.
+	select {
+	case l.runs <- struct{}{}:
+		return func() { <-l.runs }, nil
+	default:
+	}
+
+	if q := l.queued.Add(1); maxQueued > 0 && q > maxQueued {
+		l.queued.Add(-1)
+		return nil, errors.Errorf(errFmtQueueFull, name)
+	}
+	defer l.queued.Add(-1)
+
+	select {
+	case l.runs <- struct{}{}:
+		return func() { <-l.runs }, nil
+	case <-ctx.Done():
+		return nil, errors.Wrapf(ctx.Err(), errFmtWaitToRun, name)
+	}
 }
 
 // In most cases our gRPC target will be a Kubernetes Service. The package
@@ -188,20 +375,29 @@ func (r *PackagedFunctionRunner) getClientConn(ctx context.Context, name string)
 	}
 
 	r.connsMx.RLock()
-	conn, ok := r.conns[name]
+	old, ok := r.conns[name]
+	dialed := r.dialed[name]
 	r.connsMx.RUnlock()
 
+	// expired is true if we're replacing a connection that's too old.
+	expired := false
+
 	if ok {
-		// We have a connection for the up-to-date endpoint. Return it.
-		if conn.Target() == active.Status.Endpoint {
-			return conn, nil
+		switch {
+		case old.Target() != active.Status.Endpoint:
+			// This connection is to an old endpoint. We need to close it and
+			// create a new connection. Close only returns an error is if the
+			// connection is already closed or in the process of closing.
+			log.Debug("Closing gRPC client connection with stale target", "old-target", old.Target(), "new-target", active.Status.Endpoint)
+			_ = old.Close()
+		case r.maxConnAge > 0 && time.Since(dialed) > r.maxConnAge:
+			// This connection is too old. We create a new connection so that
+			// the Function's endpoint is resolved again.
+			expired = true
+		default:
+			// We have a connection for the up-to-date endpoint. Return it.
+			return old, nil
 		}
-
-		// This connection is to an old endpoint. We need to close it and create
-		// a new connection. Close only returns an error is if the connection is
-		// already closed or in the process of closing.
-		log.Debug("Closing gRPC client connection with stale target", "old-target", conn.Target(), "new-target", active.Status.Endpoint)
-		_ = conn.Close()
 	}
 
 	// This context is only used for setting up the connection.
@@ -213,18 +409,39 @@ func (r *PackagedFunctionRunner) getClientConn(ctx context.Context, name string)
 		is[i] = r.interceptors[i].CreateInterceptor(name, active.Spec.Package)
 	}
 
-	conn, err := grpc.DialContext(ctx, active.Status.Endpoint,
+	opts := []grpc.DialOption{
 		grpc.WithTransportCredentials(r.creds),
 		grpc.WithDefaultServiceConfig(lbRoundRobin),
-		grpc.WithChainUnaryInterceptor(is...))
+		grpc.WithChainUnaryInterceptor(is...),
+	}
+	if r.maxResponseSize > 0 {
+		opts = append(opts, grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(r.maxResponseSize)))
+	}
+
+	conn, err := grpc.DialContext(ctx, active.Status.Endpoint, opts...)
 	if err != nil {
 		return nil, errors.Wrapf(err, errFmtDialFunction, active.Status.Endpoint, active.GetName())
 	}
 
 	r.connsMx.Lock()
+	if cur, ok := r.conns[name]; ok && cur != old {
+		// Another run created a new connection while we were creating ours.
+		// Use theirs, so that ours isn't leaked.
+		r.connsMx.Unlock()
+		_ = conn.Close()
+		return cur, nil
+	}
 	r.conns[name] = conn
+	r.dialed[name] = time.Now()
 	r.connsMx.Unlock()
 
+	if expired {
+		// Other runs may still be using the expired connection, so we give
+		// them time to finish before we close it.
+		log.Debug("Replaced gRPC client connection that exceeded its max age", "target", active.Status.Endpoint, "max-age", r.maxConnAge)
+		time.AfterFunc(runFunctionTimeout, func() { _ = old.Close() })
+	}
+
 	log.Debug("Created new gRPC client connection", "target", active.Status.Endpoint)
 	return conn, nil
 }
@@ -300,6 +517,7 @@ func (r *PackagedFunctionRunner) GarbageCollectConnectionsNow(ctx context.Contex
 		// closed or in the process of closing.
 		_ = r.conns[name].Close()
 		delete(r.conns, name)
+		delete(r.dialed, name)
 	}
 	r.connsMx.Unlock()
 
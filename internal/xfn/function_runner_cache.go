@@ -0,0 +1,178 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xfn
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/crossplane/crossplane/apis/apiextensions/fn/proto/v1beta1"
+)
+
+// A ResponseCache caches RunFunctionResponses. Deterministic Functions with no
+// side effects may return a response with a TTL. Until it expires, the cached
+// response is returned instead of running the Function again with the same
+// request.
+type ResponseCache struct {
+	maxTTL time.Duration
+
+	mx      sync.Mutex
+	entries map[string]cachedResponse
+
+	hits   *prometheus.CounterVec
+	misses *prometheus.CounterVec
+
+	// now is used to determine whether a response has expired.
+	now func() time.Time
+}
+
+type cachedResponse struct {
+	rsp     *v1beta1.RunFunctionResponse
+	expires time.Time
+}
+
+// NewResponseCache returns a cache of RunFunctionResponses. Responses are
+// cached for their TTL, or for maxTTL if their TTL is longer.
+func NewResponseCache(maxTTL time.Duration) *ResponseCache {
+	return &ResponseCache{
+		maxTTL:  maxTTL,
+		entries: make(map[string]cachedResponse),
+		hits: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Subsystem: "composition",
+			Name:      "run_function_response_cache_hits_total",
+			Help:      "Total number of RunFunctionRequests answered from the response cache.",
+		}, []string{"function_name"}),
+		misses: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Subsystem: "composition",
+			Name:      "run_function_response_cache_misses_total",
+			Help:      "Total number of RunFunctionRequests that couldn't be answered from the response cache.",
+		}, []string{"function_name"}),
+		now: time.Now,
+	}
+}
+
+// Describe sends the super-set of all possible descriptors of metrics
+// collected by this Collector to the provided channel and returns once
+// the last descriptor has been sent.
+func (c *ResponseCache) Describe(ch chan<- *prometheus.Desc) {
+	c.hits.Describe(ch)
+	c.misses.Describe(ch)
+}
+
+// Collect is called by the Prometheus registry when collecting
+// metrics. The implementation sends each collected metric via the
+// provided channel and returns once the last metric has been sent.
+func (c *ResponseCache) Collect(ch chan<- prometheus.Metric) {
+	c.hits.Collect(ch)
+	c.misses.Collect(ch)
+}
+
+// Get returns the cached response to the supplied request to the named
+// Function, if there is one and it hasn't expired.
+func (c *ResponseCache) Get(name string, req *v1beta1.RunFunctionRequest) (*v1beta1.RunFunctionResponse, bool) {
+	k, err := key(name, req)
+	if err != nil {
+		c.misses.WithLabelValues(name).Inc()
+		return nil, false
+	}
+
+	c.mx.Lock()
+	e, ok := c.entries[k]
+	if ok && !c.now().Before(e.expires) {
+		delete(c.entries, k)
+		ok = false
+	}
+	c.mx.Unlock()
+
+	if !ok {
+		c.misses.WithLabelValues(name).Inc()
+		return nil, false
+	}
+	c.hits.WithLabelValues(name).Inc()
+
+	// Callers may modify the response, so we return a copy.
+	return proto.Clone(e.rsp).(*v1beta1.RunFunctionResponse), true
+}
+
+// Set caches the supplied response to the supplied request to the named
+// Function. It's a no-op if the response doesn't have a TTL.
+func (c *ResponseCache) Set(name string, req *v1beta1.RunFunctionRequest, rsp *v1beta1.RunFunctionResponse) {
+	ttl := rsp.GetMeta().GetTtl().AsDuration()
+	if ttl > c.maxTTL {
+		ttl = c.maxTTL
+	}
+	if ttl <= 0 {
+		return
+	}
+
+	k, err := key(name, req)
+	if err != nil {
+		return
+	}
+
+	c.mx.Lock()
+	c.entries[k] = cachedResponse{rsp: proto.Clone(rsp).(*v1beta1.RunFunctionResponse), expires: c.now().Add(ttl)}
+	c.mx.Unlock()
+}
+
+// GarbageCollect runs every interval until the supplied context is cancelled.
+// It removes expired responses from the cache.
+func (c *ResponseCache) GarbageCollect(ctx context.Context, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			c.GarbageCollectNow()
+		}
+	}
+}
+
+// GarbageCollectNow removes expired responses from the cache. It returns the
+// number of responses removed.
+func (c *ResponseCache) GarbageCollectNow() int {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	now := c.now()
+	removed := 0
+	for k, e := range c.entries {
+		if !now.Before(e.expires) {
+			delete(c.entries, k)
+			removed++
+		}
+	}
+	return removed
+}
+
+// key returns the cache key for the supplied request to the named Function.
+func key(name string, req *v1beta1.RunFunctionRequest) (string, error) {
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s/%x", name, sha256.Sum256(b)), nil
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xfn
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/crossplane/crossplane/apis/apiextensions/fn/proto/v1beta1"
+)
+
+func TestResponseCache(t *testing.T) {
+	now := time.Now()
+	req := &v1beta1.RunFunctionRequest{Meta: &v1beta1.RequestMeta{Tag: "cool"}}
+	rsp := func(ttl time.Duration) *v1beta1.RunFunctionResponse {
+		return &v1beta1.RunFunctionResponse{Meta: &v1beta1.ResponseMeta{Tag: "cool", Ttl: durationpb.New(ttl)}}
+	}
+
+	type set struct {
+		name string
+		req  *v1beta1.RunFunctionRequest
+		rsp  *v1beta1.RunFunctionResponse
+	}
+	type get struct {
+		name  string
+		req   *v1beta1.RunFunctionRequest
+		after time.Duration
+	}
+	type want struct {
+		rsp *v1beta1.RunFunctionResponse
+		ok  bool
+	}
+	cases := map[string]struct {
+		reason string
+		set    set
+		get    get
+		want   want
+	}{
+		"Hit": {
+			reason: "We should return a cached response to an identical request until its TTL expires.",
+			set:    set{name: "cool-fn", req: req, rsp: rsp(time.Minute)},
+			get:    get{name: "cool-fn", req: req, after: 30 * time.Second},
+			want:   want{rsp: rsp(time.Minute), ok: true},
+		},
+		"NoTTL": {
+			reason: "We shouldn't cache a response without a TTL.",
+			set:    set{name: "cool-fn", req: req, rsp: &v1beta1.RunFunctionResponse{}},
+			get:    get{name: "cool-fn", req: req},
+		},
+		"Expired": {
+			reason: "We shouldn't return a cached response once its TTL has expired.",
+			set:    set{name: "cool-fn", req: req, rsp: rsp(time.Minute)},
+			get:    get{name: "cool-fn", req: req, after: time.Minute},
+		},
+		"MaxTTL": {
+			reason: "We shouldn't cache a response for longer than our maximum TTL.",
+			set:    set{name: "cool-fn", req: req, rsp: rsp(time.Hour)},
+			get:    get{name: "cool-fn", req: req, after: 10 * time.Minute},
+		},
+		"DifferentRequest": {
+			reason: "We shouldn't return a cached response to a different request.",
+			set:    set{name: "cool-fn", req: req, rsp: rsp(time.Minute)},
+			get:    get{name: "cool-fn", req: &v1beta1.RunFunctionRequest{Meta: &v1beta1.RequestMeta{Tag: "uncool"}}},
+		},
+		"DifferentFunction": {
+			reason: "We shouldn't return a cached response from a different Function.",
+			set:    set{name: "cool-fn", req: req, rsp: rsp(time.Minute)},
+			get:    get{name: "other-fn", req: req},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := NewResponseCache(5 * time.Minute)
+			c.now = func() time.Time { return now }
+			c.Set(tc.set.name, tc.set.req, tc.set.rsp)
+
+			c.now = func() time.Time { return now.Add(tc.get.after) }
+			got, ok := c.Get(tc.get.name, tc.get.req)
+			if diff := cmp.Diff(tc.want.ok, ok); diff != "" {
+				t.Errorf("\n%s\nGet(...): -want ok, +got ok:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.rsp, got, protocmp.Transform()); diff != "" {
+				t.Errorf("\n%s\nGet(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestResponseCacheGarbageCollectNow(t *testing.T) {
+	now := time.Now()
+	c := NewResponseCache(time.Hour)
+	c.now = func() time.Time { return now }
+
+	for tag, ttl := range map[string]time.Duration{"a": time.Minute, "b": time.Minute, "c": time.Hour} {
+		c.Set("cool-fn", &v1beta1.RunFunctionRequest{Meta: &v1beta1.RequestMeta{Tag: tag}}, &v1beta1.RunFunctionResponse{Meta: &v1beta1.ResponseMeta{Ttl: durationpb.New(ttl)}})
+	}
+
+	c.now = func() time.Time { return now.Add(10 * time.Minute) }
+	if diff := cmp.Diff(2, c.GarbageCollectNow()); diff != "" {
+		t.Errorf("GarbageCollectNow(): -want removed, +got removed:\n%s", diff)
+	}
+	if diff := cmp.Diff(1, len(c.entries)); diff != "" {
+		t.Errorf("GarbageCollectNow(): -want remaining, +got remaining:\n%s", diff)
+	}
+}
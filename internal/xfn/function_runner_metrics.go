@@ -32,6 +32,7 @@ type Metrics struct {
 	requests  *prometheus.CounterVec
 	responses *prometheus.CounterVec
 	duration  *prometheus.HistogramVec
+	inFlight  *prometheus.GaugeVec
 }
 
 // NewMetrics creates metrics for composition function runs.
@@ -55,6 +56,12 @@ func NewMetrics() *Metrics {
 			Help:      "Histogram of RunFunctionResponse latency (seconds).",
 			Buckets:   prometheus.DefBuckets,
 		}, []string{"function_name", "function_package", "grpc_target", "grpc_code", "result_severity"}),
+
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Subsystem: "composition",
+			Name:      "run_function_requests_in_flight",
+			Help:      "Number of RunFunctionRequests sent that haven't yet received a response.",
+		}, []string{"function_name", "function_package", "grpc_target"}),
 	}
 }
 
@@ -65,6 +72,7 @@ func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
 	m.requests.Describe(ch)
 	m.responses.Describe(ch)
 	m.duration.Describe(ch)
+	m.inFlight.Describe(ch)
 }
 
 // Collect is called by the Prometheus registry when collecting
@@ -74,6 +82,7 @@ func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
 	m.requests.Collect(ch)
 	m.responses.Collect(ch)
 	m.duration.Collect(ch)
+	m.inFlight.Collect(ch)
 }
 
 // CreateInterceptor returns a gRPC UnaryClientInterceptor for the named
@@ -84,9 +93,12 @@ func (m *Metrics) CreateInterceptor(name, pkg string) grpc.UnaryClientIntercepto
 
 		m.requests.With(l).Inc()
 
+		inFlight := m.inFlight.With(l)
+		inFlight.Inc()
 		start := time.Now()
 		err := invoker(ctx, method, req, reply, cc, opts...)
 		duration := time.Since(start)
+		inFlight.Dec()
 
 		s, _ := status.FromError(err)
 		l["grpc_code"] = s.Code().String()
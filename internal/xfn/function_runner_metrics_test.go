@@ -0,0 +1,119 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+
+package xfn
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/crossplane/crossplane/apis/apiextensions/fn/proto/v1beta1"
+)
+
+func TestMetricsCreateInterceptor(t *testing.T) {
+	target := "dns:///function-cool.crossplane-system:9443"
+	conn, err := grpc.Dial(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	type want struct {
+		code     string
+		severity string
+		err      error
+	}
+	cases := map[string]struct {
+		reason string
+		rsp    *v1beta1.RunFunctionResponse
+		err    error
+		want   want
+	}{
+		"Success": {
+			reason: "A response with only normal results should be recorded as a normal response.",
+			rsp: &v1beta1.RunFunctionResponse{
+				Results: []*v1beta1.Result{{Severity: v1beta1.Severity_SEVERITY_NORMAL}},
+			},
+			want: want{
+				code:     codes.OK.String(),
+				severity: "Normal",
+			},
+		},
+		"Fatal": {
+			reason: "A response with a fatal result should be recorded as a fatal response, even if it also has warnings.",
+			rsp: &v1beta1.RunFunctionResponse{
+				Results: []*v1beta1.Result{
+					{Severity: v1beta1.Severity_SEVERITY_WARNING},
+					{Severity: v1beta1.Severity_SEVERITY_FATAL},
+				},
+			},
+			want: want{
+				code:     codes.OK.String(),
+				severity: "Fatal",
+			},
+		},
+		"Error": {
+			reason: "A gRPC error should be recorded with its status code.",
+			rsp:    &v1beta1.RunFunctionResponse{},
+			err:    status.Error(codes.Unavailable, "boom"),
+			want: want{
+				code:     codes.Unavailable.String(),
+				severity: "Normal",
+				err:      status.Error(codes.Unavailable, "boom"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := NewMetrics()
+			i := m.CreateInterceptor("function-cool", "xpkg.upbound.io/cool/function-cool:v1.0.0")
+
+			var inFlight float64
+			invoker := func(_ context.Context, _ string, _, reply any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+				inFlight = testutil.ToFloat64(m.inFlight)
+				proto.Merge(reply.(*v1beta1.RunFunctionResponse), tc.rsp)
+				return tc.err
+			}
+
+			err := i(context.Background(), "RunFunction", &v1beta1.RunFunctionRequest{}, &v1beta1.RunFunctionResponse{}, conn, invoker)
+			if diff := cmp.Diff(tc.want.err, err, cmp.Comparer(func(a, b error) bool { return status.Convert(a).String() == status.Convert(b).String() })); diff != "" {
+				t.Errorf("\n%s\nCreateInterceptor(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(1.0, inFlight); diff != "" {
+				t.Errorf("\n%s\nCreateInterceptor(...): -want in flight during request, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(0.0, testutil.ToFloat64(m.inFlight)); diff != "" {
+				t.Errorf("\n%s\nCreateInterceptor(...): -want in flight after request, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(1.0, testutil.ToFloat64(m.requests)); diff != "" {
+				t.Errorf("\n%s\nCreateInterceptor(...): -want requests, +got requests:\n%s", tc.reason, diff)
+			}
+			got := testutil.ToFloat64(m.responses.WithLabelValues("function-cool", "xpkg.upbound.io/cool/function-cool:v1.0.0", target, tc.want.code, tc.want.severity))
+			if diff := cmp.Diff(1.0, got); diff != "" {
+				t.Errorf("\n%s\nCreateInterceptor(...): -want responses, +got responses:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"net"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
@@ -39,6 +40,9 @@ import (
 func TestRunFunction(t *testing.T) {
 	errBoom := errors.New("boom")
 
+	// Larger than gRPC's default maximum message size of 4MiB.
+	large := strings.Repeat("a", 5<<20)
+
 	// Make sure to add servers listeners here, for us to later close.
 	listeners := make([]net.Listener, 0)
 
@@ -181,6 +185,54 @@ func TestRunFunction(t *testing.T) {
 				},
 			},
 		},
+		"LargeResponse": {
+			reason: "We should accept a response larger than gRPC's default limit if configured to.",
+			params: params{
+				c: &test.MockClient{
+					MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+						// Start a gRPC server.
+						lis := NewGRPCServer(t, &MockFunctionServer{rsp: &v1beta1.RunFunctionResponse{
+							Meta: &v1beta1.ResponseMeta{Tag: large},
+						}})
+						listeners = append(listeners, lis)
+
+						l, ok := obj.(*pkgv1beta1.FunctionRevisionList)
+						if !ok {
+							// If we're called to list Functions we want to
+							// return none, to make sure we GC everything.
+							return nil
+						}
+						l.Items = []pkgv1beta1.FunctionRevision{
+							{
+								ObjectMeta: metav1.ObjectMeta{
+									Name: "cool-fn-revision-a",
+								},
+								Spec: pkgv1beta1.FunctionRevisionSpec{
+									PackageRevisionSpec: pkgv1.PackageRevisionSpec{
+										DesiredState: pkgv1.PackageRevisionActive,
+									},
+								},
+								Status: pkgv1beta1.FunctionRevisionStatus{
+									Endpoint: strings.Replace(lis.Addr().String(), "127.0.0.1", "dns:///localhost", 1),
+								},
+							},
+						}
+						return nil
+					}),
+				},
+				o: []PackagedFunctionRunnerOption{WithMaxResponseSize(8 << 20)},
+			},
+			args: args{
+				ctx:  context.Background(),
+				name: "cool-fn",
+				req:  &v1beta1.RunFunctionRequest{},
+			},
+			want: want{
+				rsp: &v1beta1.RunFunctionResponse{
+					Meta: &v1beta1.ResponseMeta{Tag: large},
+				},
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -210,6 +262,125 @@ func TestRunFunction(t *testing.T) {
 	}
 }
 
+func TestAcquire(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	type params struct {
+		o []PackagedFunctionRunnerOption
+	}
+	type args struct {
+		ctx context.Context
+	}
+	type want struct {
+		err error
+	}
+	cases := map[string]struct {
+		reason  string
+		params  params
+		running map[string]int
+		queued  int64
+		args    args
+		want    want
+	}{
+		"Unlimited": {
+			reason: "We should always be able to run a Function if there's no concurrency limit.",
+			args: args{
+				ctx: cancelled,
+			},
+		},
+		"UnderLimit": {
+			reason: "We should be able to run a Function if we're under the concurrency limit.",
+			params: params{
+				o: []PackagedFunctionRunnerOption{WithMaxConcurrentRuns(2)},
+			},
+			running: map[string]int{"other-fn": 1},
+			args: args{
+				ctx: cancelled,
+			},
+		},
+		"QueueFull": {
+			reason: "We should fail immediately if too many Function runs are already queued.",
+			params: params{
+				o: []PackagedFunctionRunnerOption{WithMaxConcurrentRuns(1), WithMaxQueuedRuns(1)},
+			},
+			running: map[string]int{"other-fn": 1},
+			queued:  1,
+			args: args{
+				ctx: context.Background(),
+			},
+			want: want{
+				err: errors.Errorf(errFmtQueueFull, "cool-fn"),
+			},
+		},
+		"FunctionUnderLimit": {
+			reason: "We should be able to run a Function if other Functions are at their own concurrency limit.",
+			params: params{
+				o: []PackagedFunctionRunnerOption{WithMaxConcurrentRunsPerFunction(map[string]int{"other-fn": 1, "cool-fn": 1})},
+			},
+			running: map[string]int{"other-fn": 1},
+			args: args{
+				ctx: cancelled,
+			},
+		},
+		"FunctionAtLimit": {
+			reason: "We should wait to run a Function that's at its own concurrency limit, even if we're under the overall limit.",
+			params: params{
+				o: []PackagedFunctionRunnerOption{WithMaxConcurrentRuns(10), WithMaxConcurrentRunsPerFunction(map[string]int{"cool-fn": 1})},
+			},
+			running: map[string]int{"cool-fn": 1},
+			args: args{
+				ctx: cancelled,
+			},
+			want: want{
+				err: errors.Wrapf(context.Canceled, errFmtWaitToRun, "cool-fn"),
+			},
+		},
+		"ContextDone": {
+			reason: "We should stop waiting to run a Function when our context is done.",
+			params: params{
+				o: []PackagedFunctionRunnerOption{WithMaxConcurrentRuns(1)},
+			},
+			running: map[string]int{"other-fn": 1},
+			args: args{
+				ctx: cancelled,
+			},
+			want: want{
+				err: errors.Wrapf(context.Canceled, errFmtWaitToRun, "cool-fn"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewPackagedFunctionRunner(nil, tc.params.o...)
+			for fn, n := range tc.running {
+				for i := 0; i < n; i++ {
+					if _, err := r.acquire(context.Background(), fn); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+			if r.runs != nil {
+				r.runs.queued.Store(tc.queued)
+			}
+
+			release, err := r.acquire(tc.args.ctx, "cool-fn")
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.acquire(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if err == nil {
+				release()
+			}
+			if r.runs != nil {
+				if diff := cmp.Diff(tc.queued, r.runs.queued.Load()); diff != "" {
+					t.Errorf("\n%s\nr.acquire(...): -want queued, +got queued:\n%s", tc.reason, diff)
+				}
+			}
+		})
+	}
+}
+
 func TestGetClientConn(t *testing.T) {
 	// TestRunFunction exercises most of the getClientConn code. Here we just
 	// test some cases that don't fit well in our usual table-driven format.
@@ -281,6 +452,68 @@ func TestGetClientConn(t *testing.T) {
 	}
 }
 
+func TestGetClientConnMaxAge(t *testing.T) {
+	// Start a gRPC server.
+	lis := NewGRPCServer(t, &MockFunctionServer{rsp: &v1beta1.RunFunctionResponse{
+		Meta: &v1beta1.ResponseMeta{Tag: "hi!"},
+	}})
+	defer lis.Close()
+
+	target := strings.Replace(lis.Addr().String(), "127.0.0.1", "dns:///localhost", 1)
+
+	c := &test.MockClient{
+		MockList: NewListFn(target),
+	}
+
+	// If our connection is younger than the max age we should reuse it.
+	t.Run("ReuseYoungConnection", func(t *testing.T) {
+		r := NewPackagedFunctionRunner(c, WithMaxConnectionAge(1*time.Hour))
+
+		first, err := r.getClientConn(context.Background(), "cool-fn")
+		if err != nil {
+			t.Fatalf("r.getClientConn(...): %s", err)
+		}
+		second, err := r.getClientConn(context.Background(), "cool-fn")
+		if err != nil {
+			t.Fatalf("r.getClientConn(...): %s", err)
+		}
+
+		if first != second {
+			t.Errorf("\nr.getClientConn(...): want the cached connection, got a new connection")
+		}
+
+		if _, err := r.GarbageCollectConnectionsNow(context.Background()); err != nil {
+			t.Logf("Error closing client connections: %s", err)
+		}
+	})
+
+	// If our connection is older than the max age we should replace it.
+	t.Run("ReplaceExpiredConnection", func(t *testing.T) {
+		r := NewPackagedFunctionRunner(c, WithMaxConnectionAge(1*time.Nanosecond))
+
+		first, err := r.getClientConn(context.Background(), "cool-fn")
+		if err != nil {
+			t.Fatalf("r.getClientConn(...): %s", err)
+		}
+		second, err := r.getClientConn(context.Background(), "cool-fn")
+		if err != nil {
+			t.Fatalf("r.getClientConn(...): %s", err)
+		}
+
+		if first == second {
+			t.Errorf("\nr.getClientConn(...): want a new connection, got the expired connection")
+		}
+		if diff := cmp.Diff(target, second.Target()); diff != "" {
+			t.Errorf("\nr.getClientConn(...): -want, +got:\n%s", diff)
+		}
+
+		_ = first.Close()
+		if _, err := r.GarbageCollectConnectionsNow(context.Background()); err != nil {
+			t.Logf("Error closing client connections: %s", err)
+		}
+	})
+}
+
 func TestGarbageCollectConnectionsNow(t *testing.T) {
 	// TestRunFunction exercises most of the GarbageCollectConnectionsNow code.
 	// Here we just test some cases that don't fit well in our usual
@@ -0,0 +1,125 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xfn
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/crossplane/crossplane/apis/apiextensions/fn/proto/v1beta1"
+)
+
+// TracerName is the name of the OpenTelemetry tracer used to trace
+// composition function runs.
+const TracerName = "github.com/crossplane/crossplane/internal/xfn"
+
+// Tracing traces composition function runs using OpenTelemetry. It uses the
+// global tracer provider and text map propagator.
+type Tracing struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+// NewTracing creates tracing for composition function runs.
+func NewTracing() *Tracing {
+	return &Tracing{
+		tracer:     otel.Tracer(TracerName),
+		propagator: otel.GetTextMapPropagator(),
+	}
+}
+
+// CreateInterceptor returns a gRPC UnaryClientInterceptor for the named
+// function. The supplied package (pkg) should be the package's OCI reference.
+// The interceptor records a span for each run, and propagates its trace
+// context to the function in the request's gRPC metadata.
+func (t *Tracing) CreateInterceptor(name, pkg string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx, span := t.tracer.Start(ctx, "RunFunction",
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				attribute.String("function.name", name),
+				attribute.String("function.package", pkg),
+				attribute.String("rpc.system", "grpc"),
+				attribute.String("rpc.method", method),
+				attribute.String("server.address", cc.Target()),
+			))
+		defer span.End()
+
+		md, ok := metadata.FromOutgoingContext(ctx)
+		if ok {
+			md = md.Copy()
+		} else {
+			md = metadata.MD{}
+		}
+		t.propagator.Inject(ctx, metadataCarrier(md))
+		ctx = metadata.NewOutgoingContext(ctx, md)
+
+		err := invoker(ctx, method, req, reply, cc, opts...)
+
+		s, _ := status.FromError(err)
+		span.SetAttributes(attribute.String("rpc.grpc.status_code", s.Code().String()))
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, s.Message())
+			return err
+		}
+
+		if rsp, ok := reply.(*v1beta1.RunFunctionResponse); ok {
+			for _, r := range rsp.GetResults() {
+				if r.GetSeverity() == v1beta1.Severity_SEVERITY_FATAL {
+					span.SetStatus(codes.Error, r.GetMessage())
+					break
+				}
+			}
+		}
+
+		return nil
+	}
+}
+
+// metadataCarrier adapts gRPC metadata to an OpenTelemetry TextMapCarrier.
+type metadataCarrier metadata.MD
+
+// Get returns the first value associated with the supplied key.
+func (c metadataCarrier) Get(key string) string {
+	if v := metadata.MD(c).Get(key); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// Set sets the value associated with the supplied key.
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+// Keys lists the keys stored in this carrier.
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
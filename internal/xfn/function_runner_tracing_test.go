@@ -0,0 +1,121 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+
+package xfn
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/crossplane/crossplane/apis/apiextensions/fn/proto/v1beta1"
+)
+
+func TestTracingCreateInterceptor(t *testing.T) {
+	target := "dns:///function-cool.crossplane-system:9443"
+	conn, err := grpc.Dial(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	type want struct {
+		status otelcodes.Code
+		err    error
+	}
+	cases := map[string]struct {
+		reason string
+		rsp    *v1beta1.RunFunctionResponse
+		err    error
+		want   want
+	}{
+		"Success": {
+			reason: "A response with only normal results should be recorded as an unset span status.",
+			rsp: &v1beta1.RunFunctionResponse{
+				Results: []*v1beta1.Result{{Severity: v1beta1.Severity_SEVERITY_NORMAL}},
+			},
+			want: want{
+				status: otelcodes.Unset,
+			},
+		},
+		"Fatal": {
+			reason: "A response with a fatal result should be recorded as an error span status.",
+			rsp: &v1beta1.RunFunctionResponse{
+				Results: []*v1beta1.Result{{Severity: v1beta1.Severity_SEVERITY_FATAL, Message: "boom"}},
+			},
+			want: want{
+				status: otelcodes.Error,
+			},
+		},
+		"Error": {
+			reason: "A gRPC error should be recorded as an error span status.",
+			rsp:    &v1beta1.RunFunctionResponse{},
+			err:    status.Error(codes.Unavailable, "boom"),
+			want: want{
+				status: otelcodes.Error,
+				err:    status.Error(codes.Unavailable, "boom"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			sr := tracetest.NewSpanRecorder()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+			tr := &Tracing{tracer: tp.Tracer(TracerName), propagator: propagation.TraceContext{}}
+			i := tr.CreateInterceptor("function-cool", "xpkg.upbound.io/cool/function-cool:v1.0.0")
+
+			var traceparent []string
+			invoker := func(ctx context.Context, _ string, _, reply any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+				md, _ := metadata.FromOutgoingContext(ctx)
+				traceparent = md.Get("traceparent")
+				proto.Merge(reply.(*v1beta1.RunFunctionResponse), tc.rsp)
+				return tc.err
+			}
+
+			err := i(context.Background(), "RunFunction", &v1beta1.RunFunctionRequest{}, &v1beta1.RunFunctionResponse{}, conn, invoker)
+			if diff := cmp.Diff(tc.want.err, err, cmp.Comparer(func(a, b error) bool { return status.Convert(a).String() == status.Convert(b).String() })); diff != "" {
+				t.Errorf("\n%s\nCreateInterceptor(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			spans := sr.Ended()
+			if len(spans) != 1 {
+				t.Fatalf("\n%s\nCreateInterceptor(...): want 1 ended span, got %d", tc.reason, len(spans))
+			}
+			if diff := cmp.Diff(tc.want.status, spans[0].Status().Code); diff != "" {
+				t.Errorf("\n%s\nCreateInterceptor(...): -want span status, +got span status:\n%s", tc.reason, diff)
+			}
+
+			// The function should receive the context of the span that
+			// traced its run.
+			sc := spans[0].SpanContext()
+			want := []string{"00-" + sc.TraceID().String() + "-" + sc.SpanID().String() + "-01"}
+			if diff := cmp.Diff(want, traceparent); diff != "" {
+				t.Errorf("\n%s\nCreateInterceptor(...): -want traceparent, +got traceparent:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
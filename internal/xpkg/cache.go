@@ -18,20 +18,35 @@ package xpkg
 
 import (
 	"compress/gzip"
+	"context"
 	"io"
 	"os"
+	"path/filepath"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/spf13/afero"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
 )
 
 const (
-	errGetNopCache = "cannot get content from a NopCache"
+	errGetNopCache   = "cannot get content from a NopCache"
+	errReadCacheDir  = "cannot read package cache directory"
+	errFmtEvictCache = "cannot evict %q from package cache"
+	errGCInterval    = "package cache garbage collection interval must be greater than zero"
 )
 
-const cacheContentExt = ".gz"
+const (
+	cacheContentExt = ".gz"
+	cacheTempExt    = ".tmp"
+
+	// Temporary files older than this were left behind by a Crossplane
+	// replica that stopped while storing content.
+	cacheTempMaxAge = time.Hour
+)
 
 // A PackageCache caches package content.
 type PackageCache interface {
@@ -69,22 +84,32 @@ func (c *FsPackageCache) Has(id string) bool {
 func (c *FsPackageCache) Get(id string) (io.ReadCloser, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
-	f, err := c.fs.Open(BuildPath(c.dir, id, cacheContentExt))
+	path := BuildPath(c.dir, id, cacheContentExt)
+	f, err := c.fs.Open(path)
 	if err != nil {
 		return nil, err
 	}
+	// Record when the content was last used, so that garbage collection can
+	// evict the least recently used content first.
+	now := time.Now()
+	_ = c.fs.Chtimes(path, now, now)
 	return GzipReadCloser(f)
 }
 
-// Store saves the package contents to the cache.
+// Store saves the package contents to the cache. Content is written to a
+// temporary file that is renamed into place once it's complete, so the cache
+// may be shared by several Crossplane replicas (e.g. using a ReadWriteMany
+// volume) without any of them reading partially written content.
 func (c *FsPackageCache) Store(id string, content io.ReadCloser) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	cf, err := c.fs.Create(BuildPath(c.dir, id, cacheContentExt))
+	path := BuildPath(c.dir, id, cacheContentExt)
+	cf, err := afero.TempFile(c.fs, filepath.Dir(path), filepath.Base(path)+".*"+cacheTempExt)
 	if err != nil {
 		return err
 	}
-	defer cf.Close() //nolint:errcheck // Error is checked in the happy path.
+	defer c.fs.Remove(cf.Name()) //nolint:errcheck // The file won't exist once it's renamed.
+	defer cf.Close()             //nolint:errcheck // Error is checked in the happy path.
 	w, err := gzip.NewWriterLevel(cf, gzip.BestSpeed)
 	if err != nil {
 		return err
@@ -98,7 +123,10 @@ func (c *FsPackageCache) Store(id string, content io.ReadCloser) error {
 	if err := w.Close(); err != nil {
 		return err
 	}
-	return cf.Close()
+	if err := cf.Close(); err != nil {
+		return err
+	}
+	return c.fs.Rename(cf.Name(), path)
 }
 
 // Delete removes package contents from the cache.
@@ -112,6 +140,127 @@ func (c *FsPackageCache) Delete(id string) error {
 	return err
 }
 
+// GarbageCollect evicts package contents that haven't been used for longer
+// than maxAge, then evicts the least recently used package contents until the
+// cache is no larger than maxSize bytes. A zero maxAge or maxSize disables the
+// respective limit. Only content stored by the package manager is evicted;
+// content pre-loaded for packages with a Never pull policy lives in
+// subdirectories of the cache and is never evicted. Evicted content is fetched
+// again the next time it's needed. Temporary files left behind by a replica
+// that stopped while storing content are also removed. It returns the number
+// of evicted items.
+func (c *FsPackageCache) GarbageCollect(maxSize int64, maxAge time.Duration) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	fis, err := afero.ReadDir(c.fs, c.dir)
+	if err != nil {
+		return 0, errors.Wrap(err, errReadCacheDir)
+	}
+
+	now := time.Now()
+	items := make([]os.FileInfo, 0, len(fis))
+	total := int64(0)
+	for _, fi := range fis {
+		if !fi.IsDir() && filepath.Ext(fi.Name()) == cacheTempExt && now.Sub(fi.ModTime()) > cacheTempMaxAge {
+			if err := c.fs.Remove(filepath.Join(c.dir, fi.Name())); err != nil && !os.IsNotExist(err) {
+				return 0, errors.Wrapf(err, errFmtEvictCache, fi.Name())
+			}
+			continue
+		}
+		if fi.IsDir() || filepath.Ext(fi.Name()) != cacheContentExt {
+			continue
+		}
+		items = append(items, fi)
+		total += fi.Size()
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].ModTime().Before(items[j].ModTime()) })
+
+	evicted := 0
+	for _, fi := range items {
+		expired := maxAge > 0 && now.Sub(fi.ModTime()) > maxAge
+		oversized := maxSize > 0 && total > maxSize
+		if !expired && !oversized {
+			continue
+		}
+		if err := c.fs.Remove(filepath.Join(c.dir, fi.Name())); err != nil && !os.IsNotExist(err) {
+			return evicted, errors.Wrapf(err, errFmtEvictCache, fi.Name())
+		}
+		total -= fi.Size()
+		evicted++
+	}
+	return evicted, nil
+}
+
+// A CacheGarbageCollector periodically garbage collects a FsPackageCache.
+type CacheGarbageCollector struct {
+	cache    *FsPackageCache
+	interval time.Duration
+	maxSize  int64
+	maxAge   time.Duration
+	log      logging.Logger
+}
+
+// A CacheGarbageCollectorOption configures a CacheGarbageCollector.
+type CacheGarbageCollectorOption func(gc *CacheGarbageCollector)
+
+// WithMaxSize configures the size in bytes the garbage collector keeps the
+// cache under.
+func WithMaxSize(bytes int64) CacheGarbageCollectorOption {
+	return func(gc *CacheGarbageCollector) {
+		gc.maxSize = bytes
+	}
+}
+
+// WithMaxAge configures how long cached content may go unused before the
+// garbage collector evicts it.
+func WithMaxAge(d time.Duration) CacheGarbageCollectorOption {
+	return func(gc *CacheGarbageCollector) {
+		gc.maxAge = d
+	}
+}
+
+// WithGarbageCollectorLogger configures the garbage collector's logger.
+func WithGarbageCollectorLogger(l logging.Logger) CacheGarbageCollectorOption {
+	return func(gc *CacheGarbageCollector) {
+		gc.log = l
+	}
+}
+
+// NewCacheGarbageCollector returns a garbage collector that garbage collects
+// the supplied cache at the supplied interval.
+func NewCacheGarbageCollector(c *FsPackageCache, interval time.Duration, opts ...CacheGarbageCollectorOption) *CacheGarbageCollector {
+	gc := &CacheGarbageCollector{cache: c, interval: interval, log: logging.NewNopLogger()}
+	for _, fn := range opts {
+		fn(gc)
+	}
+	return gc
+}
+
+// Start garbage collecting the cache. It blocks until the supplied context is
+// done.
+func (gc *CacheGarbageCollector) Start(ctx context.Context) error {
+	if gc.interval <= 0 {
+		return errors.New(errGCInterval)
+	}
+	t := time.NewTicker(gc.interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+			n, err := gc.cache.GarbageCollect(gc.maxSize, gc.maxAge)
+			if err != nil {
+				gc.log.Info("Cannot garbage collect package cache", "error", err)
+				continue
+			}
+			gc.log.Debug("Garbage collected package cache", "evicted", n)
+		}
+	}
+}
+
 // NopCache is a cache implementation that does not store anything and always
 // returns an error on get.
 type NopCache struct{}
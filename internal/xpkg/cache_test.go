@@ -19,14 +19,17 @@ package xpkg
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
 	"io"
 	"os"
 	"syscall"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/spf13/afero"
 
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 )
 
@@ -174,6 +177,21 @@ func TestStore(t *testing.T) {
 			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\nStore(...): -want err, +got err:\n%s", tc.reason, diff)
 			}
+
+			if err != nil {
+				return
+			}
+
+			// Only the stored content should remain; temporary files should
+			// have been renamed into place.
+			fis, _ := afero.ReadDir(fs, "/cache")
+			got := make([]string, 0, len(fis))
+			for _, fi := range fis {
+				got = append(got, fi.Name())
+			}
+			if diff := cmp.Diff([]string{tc.args.id + cacheContentExt}, got); diff != "" {
+				t.Errorf("\n%s\nStore(...): -want files, +got files:\n%s", tc.reason, diff)
+			}
 		})
 	}
 }
@@ -225,3 +243,140 @@ func TestDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestGarbageCollect(t *testing.T) {
+	now := time.Now()
+
+	// Each item is 10 bytes. The oldest is the least recently used.
+	newFs := func() afero.Fs {
+		fs := afero.NewMemMapFs()
+		for name, age := range map[string]time.Duration{
+			"/cache/old.gz":                     48 * time.Hour,
+			"/cache/older.gz":                   72 * time.Hour,
+			"/cache/new.gz":                     time.Minute,
+			"/cache/registry.io/org/pkg.gz":     96 * time.Hour,
+			"/cache/not-package-content.tar.xz": 96 * time.Hour,
+			"/cache/stale.gz.123.tmp":           2 * time.Hour,
+			"/cache/storing.gz.456.tmp":         time.Minute,
+		} {
+			_ = afero.WriteFile(fs, name, []byte("0123456789"), 0o600)
+			_ = fs.Chtimes(name, now.Add(-age), now.Add(-age))
+		}
+		return fs
+	}
+
+	type args struct {
+		maxSize int64
+		maxAge  time.Duration
+	}
+	type want struct {
+		evicted   int
+		remaining []string
+		err       error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoLimits": {
+			reason: "Nothing should be evicted if there are no limits.",
+			want: want{
+				remaining: []string{"new.gz", "old.gz", "older.gz"},
+			},
+		},
+		"MaxAge": {
+			reason: "Content that hasn't been used for longer than the maximum age should be evicted.",
+			args: args{
+				maxAge: 24 * time.Hour,
+			},
+			want: want{
+				evicted:   2,
+				remaining: []string{"new.gz"},
+			},
+		},
+		"MaxSize": {
+			reason: "The least recently used content should be evicted until the cache is under the maximum size.",
+			args: args{
+				maxSize: 25,
+			},
+			want: want{
+				evicted:   1,
+				remaining: []string{"new.gz", "old.gz"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fs := newFs()
+			c := NewFsPackageCache("/cache", fs)
+			evicted, err := c.GarbageCollect(tc.args.maxSize, tc.args.maxAge)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nGarbageCollect(...): -want err, +got err:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.evicted, evicted); diff != "" {
+				t.Errorf("\n%s\nGarbageCollect(...): -want evicted, +got evicted:\n%s", tc.reason, diff)
+			}
+
+			remaining := make([]string, 0)
+			for _, id := range []string{"new", "old", "older"} {
+				if c.Has(id) {
+					remaining = append(remaining, id+cacheContentExt)
+				}
+			}
+			if diff := cmp.Diff(tc.want.remaining, remaining); diff != "" {
+				t.Errorf("\n%s\nGarbageCollect(...): -want remaining, +got remaining:\n%s", tc.reason, diff)
+			}
+
+			// Content pre-loaded in subdirectories, and files that aren't
+			// package content, should never be evicted.
+			for _, path := range []string{"/cache/registry.io/org/pkg.gz", "/cache/not-package-content.tar.xz", "/cache/storing.gz.456.tmp"} {
+				if _, err := fs.Stat(path); err != nil {
+					t.Errorf("\n%s\nGarbageCollect(...): %s should not be evicted: %v", tc.reason, path, err)
+				}
+			}
+
+			// Stale temporary files left behind by a replica that stopped
+			// while storing content should always be removed.
+			if _, err := fs.Stat("/cache/stale.gz.123.tmp"); !os.IsNotExist(err) {
+				t.Errorf("\n%s\nGarbageCollect(...): stale temporary file should be removed", tc.reason)
+			}
+		})
+	}
+}
+
+func TestCacheGarbageCollectorStart(t *testing.T) {
+	cases := map[string]struct {
+		reason   string
+		interval time.Duration
+		want     error
+	}{
+		"ZeroInterval": {
+			reason:   "We should return an error rather than panic if the interval is zero.",
+			interval: 0,
+			want:     errors.New(errGCInterval),
+		},
+		"NegativeInterval": {
+			reason:   "We should return an error rather than panic if the interval is negative.",
+			interval: -time.Minute,
+			want:     errors.New(errGCInterval),
+		},
+		"Stopped": {
+			reason:   "We should return without error when the context is done.",
+			interval: time.Hour,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			gc := NewCacheGarbageCollector(NewFsPackageCache("/cache", afero.NewMemMapFs()), tc.interval)
+			err := gc.Start(ctx)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nStart(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
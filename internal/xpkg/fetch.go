@@ -23,6 +23,7 @@ import (
 	"io"
 	"net/http"
 
+	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/authn/k8schain"
 	"github.com/google/go-containerregistry/pkg/name"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
@@ -57,6 +58,9 @@ type K8sFetcher struct {
 	serviceAccount string
 	transport      http.RoundTripper
 	userAgent      string
+	mirrors        *Mirrors
+	rewriter       ImageRewriter
+	backoff        *RegistryBackoff
 }
 
 // FetcherOpt can be used to add optional parameters to NewK8sFetcher
@@ -106,6 +110,33 @@ func WithServiceAccount(sa string) FetcherOpt {
 	}
 }
 
+// WithMirrors is a FetcherOpt that fetches packages from registry mirrors
+// when they're available.
+func WithMirrors(m *Mirrors) FetcherOpt {
+	return func(k *K8sFetcher) error {
+		k.mirrors = m
+		return nil
+	}
+}
+
+// WithImageRewriter is a FetcherOpt that rewrites package image references
+// before they're fetched.
+func WithImageRewriter(r ImageRewriter) FetcherOpt {
+	return func(k *K8sFetcher) error {
+		k.rewriter = r
+		return nil
+	}
+}
+
+// WithRegistryBackoff is a FetcherOpt that backs off from registries that
+// rate limit requests.
+func WithRegistryBackoff(b *RegistryBackoff) FetcherOpt {
+	return func(k *K8sFetcher) error {
+		k.backoff = b
+		return nil
+	}
+}
+
 // NewK8sFetcher creates a new K8sFetcher.
 func NewK8sFetcher(client kubernetes.Interface, opts ...FetcherOpt) (*K8sFetcher, error) {
 	k := &K8sFetcher{
@@ -132,12 +163,23 @@ func (i *K8sFetcher) Fetch(ctx context.Context, ref name.Reference, secrets ...s
 	if err != nil {
 		return nil, err
 	}
-	return remote.Image(ref,
-		remote.WithAuthFromKeychain(auth),
-		remote.WithTransport(i.transport),
-		remote.WithContext(ctx),
-		remote.WithUserAgent(i.userAgent),
-	)
+	ref, err = i.rewrite(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	var img v1.Image
+	err = i.mirrors.Do(ref, func(ref name.Reference) error {
+		return i.backoff.Do(ref, func() error {
+			img, err = remote.Image(ref,
+				remote.WithAuthFromKeychain(auth),
+				remote.WithTransport(i.transport),
+				remote.WithContext(ctx),
+				remote.WithUserAgent(i.userAgent),
+			)
+			return err
+		})
+	})
+	return img, err
 }
 
 // Head fetches a package descriptor.
@@ -150,6 +192,28 @@ func (i *K8sFetcher) Head(ctx context.Context, ref name.Reference, secrets ...st
 	if err != nil {
 		return nil, err
 	}
+	ref, err = i.rewrite(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	var d *v1.Descriptor
+	err = i.mirrors.Do(ref, func(ref name.Reference) error {
+		return i.backoff.Do(ref, func() error {
+			d, err = i.head(ctx, ref, auth)
+			return err
+		})
+	})
+	return d, err
+}
+
+func (i *K8sFetcher) rewrite(ctx context.Context, ref name.Reference) (name.Reference, error) {
+	if i.rewriter == nil {
+		return ref, nil
+	}
+	return i.rewriter.Rewrite(ctx, ref)
+}
+
+func (i *K8sFetcher) head(ctx context.Context, ref name.Reference, auth authn.Keychain) (*v1.Descriptor, error) {
 	d, err := remote.Head(ref,
 		remote.WithAuthFromKeychain(auth),
 		remote.WithTransport(i.transport),
@@ -181,12 +245,23 @@ func (i *K8sFetcher) Tags(ctx context.Context, ref name.Reference, secrets ...st
 	if err != nil {
 		return nil, err
 	}
-	return remote.List(ref.Context(),
-		remote.WithAuthFromKeychain(auth),
-		remote.WithTransport(i.transport),
-		remote.WithContext(ctx),
-		remote.WithUserAgent(i.userAgent),
-	)
+	ref, err = i.rewrite(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	var tags []string
+	err = i.mirrors.Do(ref, func(ref name.Reference) error {
+		return i.backoff.Do(ref, func() error {
+			tags, err = remote.List(ref.Context(),
+				remote.WithAuthFromKeychain(auth),
+				remote.WithTransport(i.transport),
+				remote.WithContext(ctx),
+				remote.WithUserAgent(i.userAgent),
+			)
+			return err
+		})
+	})
+	return tags, err
 }
 
 // NopFetcher always returns an empty image and never returns error.
@@ -0,0 +1,231 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/parser"
+
+	pkgmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
+	pkgmetav1beta1 "github.com/crossplane/crossplane/apis/pkg/meta/v1beta1"
+)
+
+// AnnotationLicense is the package metadata annotation that declares the
+// license under which a package's source is released. Its value should be an
+// SPDX license expression, e.g. "Apache-2.0" or "MIT OR Apache-2.0".
+const AnnotationLicense = "meta.crossplane.io/license"
+
+const (
+	errNoLicense              = "package doesn't declare a license using the " + AnnotationLicense + " annotation"
+	errFmtInvalidLicense      = "invalid SPDX license expression %q"
+	errFmtLicenseNotAllowed   = "package license %q is not allowed"
+	errFmtUnknownLicense      = "unknown SPDX license identifier %q"
+	errFmtUnexpectedLicenseOp = "unexpected %q"
+)
+
+// PackageValidLicense checks that the package's license, if it declares one,
+// is a valid SPDX license expression.
+func PackageValidLicense(o runtime.Object) error {
+	l, err := license(o)
+	if err != nil || l == "" {
+		return err
+	}
+	return ValidLicense(l)
+}
+
+// PackageLicenseAllowed checks that the package declares a license, and that
+// the license is satisfied by the supplied SPDX license identifiers.
+func PackageLicenseAllowed(allowed []string) parser.ObjectLinterFn {
+	return func(o runtime.Object) error {
+		l, err := license(o)
+		if err != nil {
+			return err
+		}
+		if l == "" {
+			return errors.New(errNoLicense)
+		}
+		ok, err := LicenseAllowed(l, allowed)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errors.Errorf(errFmtLicenseNotAllowed, l)
+		}
+		return nil
+	}
+}
+
+// license returns the license declared by the supplied package metadata.
+func license(o runtime.Object) (string, error) {
+	if _, ok := TryConvertToPkg(o, &pkgmetav1.Provider{}, &pkgmetav1.Configuration{}, &pkgmetav1beta1.Function{}); !ok {
+		return "", errors.New(errNotMeta)
+	}
+	mo, ok := o.(metav1.Object)
+	if !ok {
+		return "", errors.New(errNotMeta)
+	}
+	return mo.GetAnnotations()[AnnotationLicense], nil
+}
+
+// LicenseAllowed returns true if the supplied SPDX license expression is
+// satisfied by the supplied license identifiers. Identifiers are compared
+// case-insensitively. An expression like "MIT OR Apache-2.0" is satisfied if
+// either license is allowed, while "MIT AND Apache-2.0" is satisfied only if
+// both are. A license with an exception, e.g. "GPL-2.0-only WITH
+// Classpath-exception-2.0", is satisfied if the license is allowed.
+func LicenseAllowed(expr string, allowed []string) (bool, error) {
+	a := make(map[string]bool, len(allowed))
+	for _, id := range allowed {
+		a[strings.ToLower(id)] = true
+	}
+
+	p := &licenseParser{tokens: tokenizeLicense(expr), allowed: a}
+	ok, err := p.or()
+	if err == nil && p.pos < len(p.tokens) {
+		err = errors.Errorf(errFmtUnexpectedLicenseOp, p.tokens[p.pos])
+	}
+	if err != nil {
+		return false, errors.Wrapf(err, errFmtInvalidLicense, expr)
+	}
+	return ok, nil
+}
+
+// ValidLicense returns an error if the supplied SPDX license expression isn't
+// valid. Every license in a valid expression is either on the SPDX license
+// list, or is a custom license reference like "LicenseRef-Proprietary".
+// License exceptions aren't checked.
+func ValidLicense(expr string) error {
+	p := &licenseParser{tokens: tokenizeLicense(expr)}
+	_, err := p.or()
+	if err == nil && p.pos < len(p.tokens) {
+		err = errors.Errorf(errFmtUnexpectedLicenseOp, p.tokens[p.pos])
+	}
+	for _, id := range p.ids {
+		if err != nil {
+			break
+		}
+		if !knownLicense(id) {
+			err = errors.Errorf(errFmtUnknownLicense, id)
+		}
+	}
+	return errors.Wrapf(err, errFmtInvalidLicense, expr)
+}
+
+// knownLicense returns true if the supplied license identifier is on the SPDX
+// license list, or is a custom license reference.
+func knownLicense(id string) bool {
+	l := strings.ToLower(id)
+	if strings.HasPrefix(l, "licenseref-") || strings.HasPrefix(l, "documentref-") {
+		return true
+	}
+	return spdxLicenseIDs[strings.TrimSuffix(l, "+")]
+}
+
+func tokenizeLicense(expr string) []string {
+	expr = strings.NewReplacer("(", " ( ", ")", " ) ").Replace(expr)
+	return strings.Fields(expr)
+}
+
+// licenseParser is a recursive descent parser for SPDX license expressions. It
+// evaluates whether the expression is satisfied as it parses.
+type licenseParser struct {
+	tokens  []string
+	pos     int
+	allowed map[string]bool
+
+	// ids are the license identifiers the parser has parsed.
+	ids []string
+}
+
+func (p *licenseParser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+// or := and ("OR" and)*
+func (p *licenseParser) or() (bool, error) {
+	ok, err := p.and()
+	if err != nil {
+		return false, err
+	}
+	for strings.EqualFold(p.peek(), "OR") {
+		p.pos++
+		rok, err := p.and()
+		if err != nil {
+			return false, err
+		}
+		ok = ok || rok
+	}
+	return ok, nil
+}
+
+// and := license ("AND" license)*
+func (p *licenseParser) and() (bool, error) {
+	ok, err := p.license()
+	if err != nil {
+		return false, err
+	}
+	for strings.EqualFold(p.peek(), "AND") {
+		p.pos++
+		rok, err := p.license()
+		if err != nil {
+			return false, err
+		}
+		ok = ok && rok
+	}
+	return ok, nil
+}
+
+// license := "(" or ")" | ID ["WITH" ID]
+func (p *licenseParser) license() (bool, error) {
+	t := p.peek()
+	switch {
+	case t == "":
+		return false, errors.New("expected a license")
+	case t == "(":
+		p.pos++
+		ok, err := p.or()
+		if err != nil {
+			return false, err
+		}
+		if p.peek() != ")" {
+			return false, errors.New("expected \")\"")
+		}
+		p.pos++
+		return ok, nil
+	case t == ")" || strings.EqualFold(t, "AND") || strings.EqualFold(t, "OR") || strings.EqualFold(t, "WITH"):
+		return false, errors.Errorf(errFmtUnexpectedLicenseOp, t)
+	}
+	p.pos++
+	p.ids = append(p.ids, t)
+
+	if strings.EqualFold(p.peek(), "WITH") {
+		p.pos++
+		if e := p.peek(); e == "" || e == "(" || e == ")" {
+			return false, errors.New("expected a license exception")
+		}
+		p.pos++
+	}
+	return p.allowed[strings.ToLower(t)], nil
+}
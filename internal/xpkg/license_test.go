@@ -0,0 +1,194 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	pkgmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
+)
+
+func TestLicenseAllowed(t *testing.T) {
+	allowed := []string{"Apache-2.0", "mit"}
+
+	type want struct {
+		ok  bool
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		expr   string
+		want   want
+	}{
+		"Allowed": {
+			reason: "A single allowed license should be allowed, regardless of case.",
+			expr:   "MIT",
+			want:   want{ok: true},
+		},
+		"NotAllowed": {
+			reason: "A single license that isn't allowed shouldn't be allowed.",
+			expr:   "GPL-3.0-only",
+			want:   want{ok: false},
+		},
+		"Or": {
+			reason: "An OR expression should be allowed if any license is allowed.",
+			expr:   "GPL-3.0-only OR Apache-2.0",
+			want:   want{ok: true},
+		},
+		"And": {
+			reason: "An AND expression should be allowed only if all licenses are allowed.",
+			expr:   "MIT AND GPL-3.0-only",
+			want:   want{ok: false},
+		},
+		"Precedence": {
+			reason: "AND should bind more tightly than OR.",
+			expr:   "MIT OR GPL-3.0-only AND BSD-3-Clause",
+			want:   want{ok: true},
+		},
+		"Parentheses": {
+			reason: "Parenthesized expressions should be evaluated first.",
+			expr:   "(MIT OR GPL-3.0-only) AND BSD-3-Clause",
+			want:   want{ok: false},
+		},
+		"With": {
+			reason: "A license with an exception should be allowed if the license is allowed.",
+			expr:   "Apache-2.0 WITH LLVM-exception",
+			want:   want{ok: true},
+		},
+		"Empty": {
+			reason: "An empty expression should be invalid.",
+			expr:   "",
+			want: want{
+				err: errors.Wrapf(errors.New("expected a license"), errFmtInvalidLicense, ""),
+			},
+		},
+		"Unbalanced": {
+			reason: "An expression with unbalanced parentheses should be invalid.",
+			expr:   "(MIT OR Apache-2.0",
+			want: want{
+				err: errors.Wrapf(errors.New("expected \")\""), errFmtInvalidLicense, "(MIT OR Apache-2.0"),
+			},
+		},
+		"Trailing": {
+			reason: "An expression with trailing tokens should be invalid.",
+			expr:   "MIT Apache-2.0",
+			want: want{
+				err: errors.Wrapf(errors.Errorf(errFmtUnexpectedLicenseOp, "Apache-2.0"), errFmtInvalidLicense, "MIT Apache-2.0"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ok, err := LicenseAllowed(tc.expr, allowed)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nLicenseAllowed(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.ok, ok); diff != "" {
+				t.Errorf("\n%s\nLicenseAllowed(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestValidLicense(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		expr   string
+		want   error
+	}{
+		"Valid": {
+			reason: "An expression of licenses on the SPDX license list should be valid, regardless of case.",
+			expr:   "(mit OR Apache-2.0) AND GPL-2.0-or-later WITH Classpath-exception-2.0",
+		},
+		"OrLater": {
+			reason: "A license on the SPDX license list followed by + should be valid.",
+			expr:   "LGPL-2.1+",
+		},
+		"LicenseRef": {
+			reason: "A custom license reference should be valid.",
+			expr:   "LicenseRef-Proprietary",
+		},
+		"FreeText": {
+			reason: "A free text license should be invalid.",
+			expr:   "Apache 2.0",
+			want:   errors.Wrapf(errors.Errorf(errFmtUnexpectedLicenseOp, "2.0"), errFmtInvalidLicense, "Apache 2.0"),
+		},
+		"Unknown": {
+			reason: "A license that isn't on the SPDX license list should be invalid.",
+			expr:   "MIT OR Apache-2",
+			want:   errors.Wrapf(errors.Errorf(errFmtUnknownLicense, "Apache-2"), errFmtInvalidLicense, "MIT OR Apache-2"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := ValidLicense(tc.expr)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidLicense(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPackageLicenseAllowed(t *testing.T) {
+	withLicense := func(l string) runtime.Object {
+		return &pkgmetav1.Provider{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{AnnotationLicense: l}}}
+	}
+
+	cases := map[string]struct {
+		reason string
+		obj    runtime.Object
+		err    error
+	}{
+		"Allowed": {
+			reason: "Should not return an error if the package's license is allowed.",
+			obj:    withLicense("Apache-2.0"),
+		},
+		"NotAllowed": {
+			reason: "Should return an error if the package's license isn't allowed.",
+			obj:    withLicense("GPL-3.0-only"),
+			err:    errors.Errorf(errFmtLicenseNotAllowed, "GPL-3.0-only"),
+		},
+		"NoLicense": {
+			reason: "Should return an error if the package doesn't declare a license.",
+			obj:    &pkgmetav1.Provider{},
+			err:    errors.New(errNoLicense),
+		},
+		"NotMeta": {
+			reason: "Should return an error if the object isn't package metadata.",
+			obj:    v1crd,
+			err:    errors.New(errNotMeta),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := PackageLicenseAllowed([]string{"Apache-2.0"})(tc.obj)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nPackageLicenseAllowed(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+const (
+	errStatLocalPackage  = "cannot stat local package"
+	errReadLayout        = "cannot read OCI image layout"
+	errReadLayoutImage   = "cannot read image from OCI image layout"
+	errReadXpkgFile      = "cannot read xpkg file"
+	errFmtParseDigest    = "cannot parse digest %q"
+	errFmtLayoutNoImage  = "OCI image layout has no image with digest %q"
+	errFmtLayoutNotImage = "OCI image layout manifest %q is not an image"
+	errFmtLayoutImages   = "OCI image layout contains %d manifests, a digest must be specified, e.g. path@sha256:..."
+)
+
+// LocalPackages reads package images from a local directory, for example a
+// mounted PersistentVolume, for clusters that can't reach a registry.
+type LocalPackages struct {
+	dir string
+}
+
+// NewLocalPackages returns LocalPackages that reads package images from the
+// supplied directory.
+func NewLocalPackages(dir string) *LocalPackages {
+	return &LocalPackages{dir: dir}
+}
+
+// Image returns the package image at the supplied path, which is relative to
+// the local packages directory. The path may refer to an xpkg file, or to an
+// OCI image layout directory. A layout that contains more than one image must
+// be suffixed with the digest of the image to use, e.g. path@sha256:...
+func (l *LocalPackages) Image(path string) (v1.Image, error) {
+	path, digest := splitDigest(path)
+
+	// Cleaning the path as if it were absolute ensures it can't refer to
+	// anything outside the local packages directory.
+	p := filepath.Join(l.dir, filepath.Clean("/"+path))
+
+	fi, err := os.Stat(p)
+	if err != nil {
+		return nil, errors.Wrap(err, errStatLocalPackage)
+	}
+	if !fi.IsDir() {
+		img, err := tarball.ImageFromPath(p, nil)
+		return img, errors.Wrap(err, errReadXpkgFile)
+	}
+	return layoutImage(p, digest)
+}
+
+func layoutImage(path, digest string) (v1.Image, error) {
+	lp, err := layout.FromPath(path)
+	if err != nil {
+		return nil, errors.Wrap(err, errReadLayout)
+	}
+	idx, err := lp.ImageIndex()
+	if err != nil {
+		return nil, errors.Wrap(err, errReadLayout)
+	}
+	m, err := idx.IndexManifest()
+	if err != nil {
+		return nil, errors.Wrap(err, errReadLayout)
+	}
+
+	var d *v1.Descriptor
+	switch {
+	case digest != "":
+		h, err := v1.NewHash(digest)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtParseDigest, digest)
+		}
+		for i := range m.Manifests {
+			if m.Manifests[i].Digest == h {
+				d = &m.Manifests[i]
+			}
+		}
+		if d == nil {
+			return nil, errors.Errorf(errFmtLayoutNoImage, digest)
+		}
+	case len(m.Manifests) == 1:
+		d = &m.Manifests[0]
+	default:
+		return nil, errors.Errorf(errFmtLayoutImages, len(m.Manifests))
+	}
+
+	if !d.MediaType.IsImage() {
+		return nil, errors.Errorf(errFmtLayoutNotImage, d.Digest)
+	}
+	img, err := idx.Image(d.Digest)
+	return img, errors.Wrap(err, errReadLayoutImage)
+}
+
+// splitDigest splits an optional @digest suffix from the supplied path.
+func splitDigest(path string) (string, string) {
+	i := strings.LastIndex(path, "@")
+	if i < 0 {
+		return path, ""
+	}
+	return path[:i], path[i+1:]
+}
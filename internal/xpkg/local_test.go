@@ -0,0 +1,135 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestLocalPackagesImage(t *testing.T) {
+	dir := t.TempDir()
+
+	img1, err := random.Image(64, 1)
+	if err != nil {
+		t.Fatalf("random.Image(...): %v", err)
+	}
+	img2, err := random.Image(64, 1)
+	if err != nil {
+		t.Fatalf("random.Image(...): %v", err)
+	}
+	d1, _ := img1.Digest()
+	d2, _ := img2.Digest()
+
+	if err := tarball.WriteToFile(filepath.Join(dir, "cool.xpkg"), name.MustParseReference("cool/package:v1.0.0"), img1); err != nil {
+		t.Fatalf("tarball.WriteToFile(...): %v", err)
+	}
+	single, err := layout.Write(filepath.Join(dir, "single"), empty.Index)
+	if err != nil {
+		t.Fatalf("layout.Write(...): %v", err)
+	}
+	if err := single.AppendImage(img1); err != nil {
+		t.Fatalf("AppendImage(...): %v", err)
+	}
+	multi, err := layout.Write(filepath.Join(dir, "multi"), empty.Index)
+	if err != nil {
+		t.Fatalf("layout.Write(...): %v", err)
+	}
+	for _, img := range []v1.Image{img1, img2} {
+		if err := multi.AppendImage(img); err != nil {
+			t.Fatalf("AppendImage(...): %v", err)
+		}
+	}
+
+	// A path that tries to escape the local packages directory is resolved
+	// within it, where nothing exists.
+	_, errEscape := os.Stat(filepath.Join(dir, filepath.Base(dir), "cool.xpkg"))
+
+	type want struct {
+		digest v1.Hash
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		path   string
+		want   want
+	}{
+		"XpkgFile": {
+			reason: "We should read the image from an xpkg file.",
+			path:   "cool.xpkg",
+			want:   want{digest: d1},
+		},
+		"PathEscapesDirectory": {
+			reason: "A path shouldn't be able to refer to anything outside the local packages directory.",
+			path:   "../" + filepath.Base(dir) + "/cool.xpkg",
+			want:   want{err: errors.Wrap(errEscape, errStatLocalPackage)},
+		},
+		"LayoutSingleImage": {
+			reason: "We should read the only image in an OCI image layout.",
+			path:   "single",
+			want:   want{digest: d1},
+		},
+		"LayoutAmbiguous": {
+			reason: "We should return an error if an OCI image layout contains many images and no digest is specified.",
+			path:   "multi",
+			want:   want{err: errors.Errorf(errFmtLayoutImages, 2)},
+		},
+		"LayoutDigest": {
+			reason: "We should read the image with the specified digest from an OCI image layout.",
+			path:   "multi@" + d2.String(),
+			want:   want{digest: d2},
+		},
+		"LayoutNoSuchDigest": {
+			reason: "We should return an error if an OCI image layout has no image with the specified digest.",
+			path:   "single@" + d2.String(),
+			want:   want{err: errors.Errorf(errFmtLayoutNoImage, d2.String())},
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			l := NewLocalPackages(dir)
+			img, err := l.Image(tc.path)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nImage(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			got, err := img.Digest()
+			if err != nil {
+				t.Fatalf("Digest(): %v", err)
+			}
+			if diff := cmp.Diff(tc.want.digest, got); diff != "" {
+				t.Errorf("\n%s\nImage(...): -want digest, +got digest:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+const (
+	// mirrorCooldown is how long a mirror that failed is deprioritized.
+	mirrorCooldown = 1 * time.Minute
+
+	// mirrorLatencyWeight is the weight of the latest observed latency in a
+	// mirror's exponentially weighted moving average latency.
+	mirrorLatencyWeight = 0.3
+
+	errFmtInvalidMirror = "invalid registry mirror %q, must be in the format REGISTRY=MIRROR"
+)
+
+// ParseMirrors parses registry mirrors in the format REGISTRY=MIRROR. A
+// registry may have many mirrors. Mirrors are returned keyed by the registry
+// they mirror, in the order they were supplied.
+func ParseMirrors(mirrors []string) (map[string][]string, error) {
+	out := make(map[string][]string)
+	for _, m := range mirrors {
+		registry, mirror, ok := strings.Cut(m, "=")
+		if !ok || registry == "" || mirror == "" {
+			return nil, errors.Errorf(errFmtInvalidMirror, m)
+		}
+		if _, err := name.NewRegistry(mirror); err != nil {
+			return nil, errors.Wrapf(err, errFmtInvalidMirror, m)
+		}
+		out[registry] = append(out[registry], mirror)
+	}
+	return out, nil
+}
+
+type mirrorStats struct {
+	// latency is an exponentially weighted moving average of the mirror's
+	// latency. It's zero until the mirror has been used successfully.
+	latency time.Duration
+
+	// failed is when the mirror last failed.
+	failed time.Time
+}
+
+// Mirrors selects which registry mirrors to pull packages from. It prefers the
+// healthy mirror with the lowest observed latency, falling back to other
+// mirrors and finally to the original registry if a mirror fails. Mirrors are
+// probed passively; the latency and availability of each pull is recorded.
+//
+// Mirrors is safe for concurrent use, and should be shared by all Fetchers so
+// that they share observations.
+type Mirrors struct {
+	mirrors map[string][]string
+
+	mu    sync.Mutex
+	stats map[string]*mirrorStats
+	now   func() time.Time
+
+	pulls    *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+// NewMirrors returns Mirrors that select between the supplied registry
+// mirrors, keyed by the registry they mirror.
+func NewMirrors(mirrors map[string][]string) *Mirrors {
+	return &Mirrors{
+		mirrors: mirrors,
+		stats:   make(map[string]*mirrorStats),
+		now:     time.Now,
+
+		pulls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Subsystem: "package",
+			Name:      "mirror_pulls_total",
+			Help:      "Total number of package registry requests sent to each registry mirror.",
+		}, []string{"registry", "mirror", "result"}),
+
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Subsystem: "package",
+			Name:      "mirror_pull_seconds",
+			Help:      "Histogram of package registry request latency (seconds) for each registry mirror.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"registry", "mirror"}),
+	}
+}
+
+// Describe sends the super-set of all possible descriptors of metrics
+// collected by this Collector to the provided channel and returns once
+// the last descriptor has been sent.
+func (m *Mirrors) Describe(ch chan<- *prometheus.Desc) {
+	m.pulls.Describe(ch)
+	m.duration.Describe(ch)
+}
+
+// Collect is called by the Prometheus registry when collecting
+// metrics. The implementation sends each collected metric via the
+// provided channel and returns once the last metric has been sent.
+func (m *Mirrors) Collect(ch chan<- prometheus.Metric) {
+	m.pulls.Collect(ch)
+	m.duration.Collect(ch)
+}
+
+// Candidates returns the references to try, in order, to pull the supplied
+// reference. The original reference is always the last candidate.
+func (m *Mirrors) Candidates(ref name.Reference) []name.Reference {
+	mirrors := m.mirrors[ref.Context().RegistryStr()]
+	if len(mirrors) == 0 {
+		return []name.Reference{ref}
+	}
+
+	m.mu.Lock()
+	now := m.now()
+	type candidate struct {
+		mirror  string
+		healthy bool
+		latency time.Duration
+	}
+	cs := make([]candidate, len(mirrors))
+	for i, mirror := range mirrors {
+		cs[i] = candidate{mirror: mirror, healthy: true}
+		if s, ok := m.stats[mirror]; ok {
+			cs[i].healthy = now.Sub(s.failed) > mirrorCooldown
+			cs[i].latency = s.latency
+		}
+	}
+	m.mu.Unlock()
+
+	// Prefer healthy mirrors, then lower latency. Mirrors we haven't used
+	// yet have zero latency, so they're tried (and thus probed) first. Ties
+	// keep the configured order.
+	sort.SliceStable(cs, func(i, j int) bool {
+		if cs[i].healthy != cs[j].healthy {
+			return cs[i].healthy
+		}
+		return cs[i].latency < cs[j].latency
+	})
+
+	out := make([]name.Reference, 0, len(cs)+1)
+	for _, c := range cs {
+		mref, err := mirrorReference(ref, c.mirror)
+		if err != nil {
+			continue
+		}
+		out = append(out, mref)
+	}
+	return append(out, ref)
+}
+
+// Observe records the outcome of a request to the registry of the supplied
+// reference, which may be a mirror.
+func (m *Mirrors) Observe(original, ref name.Reference, d time.Duration, err error) {
+	registry := original.Context().RegistryStr()
+	if len(m.mirrors[registry]) == 0 {
+		return
+	}
+	mirror := ref.Context().RegistryStr()
+
+	result := "Success"
+	if err != nil {
+		result = "Error"
+	}
+	m.pulls.WithLabelValues(registry, mirror, result).Inc()
+	m.duration.WithLabelValues(registry, mirror).Observe(d.Seconds())
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.stats[mirror]
+	if !ok {
+		s = &mirrorStats{}
+		m.stats[mirror] = s
+	}
+	if err != nil {
+		s.failed = m.now()
+		return
+	}
+	if s.latency == 0 {
+		s.latency = d
+		return
+	}
+	s.latency = time.Duration(mirrorLatencyWeight*float64(d) + (1-mirrorLatencyWeight)*float64(s.latency))
+}
+
+// Do calls the supplied function with each candidate reference for the
+// supplied reference until it succeeds. It returns the last error if no
+// candidate succeeds. Nil Mirrors call the function with the supplied
+// reference.
+func (m *Mirrors) Do(ref name.Reference, fn func(ref name.Reference) error) error {
+	if m == nil {
+		return fn(ref)
+	}
+	var err error
+	for _, c := range m.Candidates(ref) {
+		t := m.now()
+		err = fn(c)
+		m.Observe(ref, c, m.now().Sub(t), err)
+		if err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// mirrorReference returns the supplied reference, but pointing to the
+// supplied mirror registry.
+func mirrorReference(ref name.Reference, mirror string) (name.Reference, error) {
+	repo := mirror + "/" + ref.Context().RepositoryStr()
+	if d, ok := ref.(name.Digest); ok {
+		return name.NewDigest(repo + "@" + d.DigestStr())
+	}
+	return name.NewTag(repo + ":" + ref.Identifier())
+}
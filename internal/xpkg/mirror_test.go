@@ -0,0 +1,263 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/go-containerregistry/pkg/name"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestParseMirrors(t *testing.T) {
+	type want struct {
+		mirrors map[string][]string
+		err     error
+	}
+	cases := map[string]struct {
+		reason  string
+		mirrors []string
+		want    want
+	}{
+		"Valid": {
+			reason:  "We should group mirrors by the registry they mirror, preserving their order.",
+			mirrors: []string{"xpkg.upbound.io=mirror-a.example.org", "xpkg.upbound.io=mirror-b.example.org:5000", "ghcr.io=mirror-c.example.org"},
+			want: want{
+				mirrors: map[string][]string{
+					"xpkg.upbound.io": {"mirror-a.example.org", "mirror-b.example.org:5000"},
+					"ghcr.io":         {"mirror-c.example.org"},
+				},
+			},
+		},
+		"MissingMirror": {
+			reason:  "We should return an error if a mirror isn't in the format REGISTRY=MIRROR.",
+			mirrors: []string{"xpkg.upbound.io"},
+			want: want{
+				err: errors.Errorf(errFmtInvalidMirror, "xpkg.upbound.io"),
+			},
+		},
+		"InvalidMirror": {
+			reason:  "We should return an error if a mirror isn't a valid registry.",
+			mirrors: []string{"xpkg.upbound.io=mirror/example"},
+			want: want{
+				err: errors.Wrapf(errors.New("registries must be valid RFC 3986 URI authorities: mirror/example"), errFmtInvalidMirror, "xpkg.upbound.io=mirror/example"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseMirrors(tc.mirrors)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nParseMirrors(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.mirrors, got); diff != "" {
+				t.Errorf("\n%s\nParseMirrors(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestMirrorsCandidates(t *testing.T) {
+	now := time.Now()
+	mirrors := map[string][]string{
+		"xpkg.upbound.io": {"slow.example.org", "fast.example.org", "broken.example.org"},
+	}
+
+	type args struct {
+		stats map[string]*mirrorStats
+		ref   string
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   []string
+	}{
+		"NoMirrors": {
+			reason: "A reference to a registry without mirrors should be the only candidate.",
+			args: args{
+				ref: "ghcr.io/crossplane/provider-nop:v0.1.0",
+			},
+			want: []string{"ghcr.io/crossplane/provider-nop:v0.1.0"},
+		},
+		"NoObservations": {
+			reason: "Mirrors should be tried in the configured order before the registry if they haven't been used yet.",
+			args: args{
+				ref: "xpkg.upbound.io/crossplane/provider-nop:v0.1.0",
+			},
+			want: []string{
+				"slow.example.org/crossplane/provider-nop:v0.1.0",
+				"fast.example.org/crossplane/provider-nop:v0.1.0",
+				"broken.example.org/crossplane/provider-nop:v0.1.0",
+				"xpkg.upbound.io/crossplane/provider-nop:v0.1.0",
+			},
+		},
+		"PreferFastestHealthy": {
+			reason: "Healthy mirrors should be preferred in order of latency, followed by mirrors that recently failed.",
+			args: args{
+				stats: map[string]*mirrorStats{
+					"slow.example.org":   {latency: 2 * time.Second},
+					"fast.example.org":   {latency: 100 * time.Millisecond},
+					"broken.example.org": {latency: 10 * time.Millisecond, failed: now.Add(-10 * time.Second)},
+				},
+				ref: "xpkg.upbound.io/crossplane/provider-nop@sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d9b5ca3f0a06aa5",
+			},
+			want: []string{
+				"fast.example.org/crossplane/provider-nop@sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d9b5ca3f0a06aa5",
+				"slow.example.org/crossplane/provider-nop@sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d9b5ca3f0a06aa5",
+				"broken.example.org/crossplane/provider-nop@sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d9b5ca3f0a06aa5",
+				"xpkg.upbound.io/crossplane/provider-nop@sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d9b5ca3f0a06aa5",
+			},
+		},
+		"RecoveredAfterCooldown": {
+			reason: "A mirror that failed longer ago than the cooldown should be considered healthy again.",
+			args: args{
+				stats: map[string]*mirrorStats{
+					"slow.example.org":   {latency: 2 * time.Second},
+					"fast.example.org":   {latency: 100 * time.Millisecond},
+					"broken.example.org": {latency: 10 * time.Millisecond, failed: now.Add(-2 * mirrorCooldown)},
+				},
+				ref: "xpkg.upbound.io/crossplane/provider-nop:v0.1.0",
+			},
+			want: []string{
+				"broken.example.org/crossplane/provider-nop:v0.1.0",
+				"fast.example.org/crossplane/provider-nop:v0.1.0",
+				"slow.example.org/crossplane/provider-nop:v0.1.0",
+				"xpkg.upbound.io/crossplane/provider-nop:v0.1.0",
+			},
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			m := NewMirrors(mirrors)
+			m.now = func() time.Time { return now }
+			if tc.args.stats != nil {
+				m.stats = tc.args.stats
+			}
+
+			ref, err := name.ParseReference(tc.args.ref)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := make([]string, 0)
+			for _, c := range m.Candidates(ref) {
+				got = append(got, c.Name())
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nCandidates(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestMirrorsDo(t *testing.T) {
+	errBoom := errors.New("boom")
+	mirrors := map[string][]string{
+		"xpkg.upbound.io": {"broken.example.org", "working.example.org"},
+	}
+
+	type want struct {
+		tried  []string
+		err    error
+		failed []string
+	}
+	cases := map[string]struct {
+		reason  string
+		mirrors *Mirrors
+		fn      func(ref name.Reference) error
+		want    want
+	}{
+		"NilMirrors": {
+			reason:  "Nil Mirrors should try only the supplied reference.",
+			mirrors: nil,
+			fn:      func(_ name.Reference) error { return errBoom },
+			want: want{
+				tried: []string{"xpkg.upbound.io/crossplane/provider-nop:v0.1.0"},
+				err:   errBoom,
+			},
+		},
+		"FallBackToNextMirror": {
+			reason:  "We should fall back to the next mirror when a mirror fails, and remember that it failed.",
+			mirrors: NewMirrors(mirrors),
+			fn: func(ref name.Reference) error {
+				if ref.Context().RegistryStr() == "broken.example.org" {
+					return errBoom
+				}
+				return nil
+			},
+			want: want{
+				tried:  []string{"broken.example.org/crossplane/provider-nop:v0.1.0", "working.example.org/crossplane/provider-nop:v0.1.0"},
+				failed: []string{"broken.example.org"},
+			},
+		},
+		"AllFail": {
+			reason:  "We should return the last error if every mirror and the registry fail.",
+			mirrors: NewMirrors(mirrors),
+			fn:      func(_ name.Reference) error { return errBoom },
+			want: want{
+				tried: []string{
+					"broken.example.org/crossplane/provider-nop:v0.1.0",
+					"working.example.org/crossplane/provider-nop:v0.1.0",
+					"xpkg.upbound.io/crossplane/provider-nop:v0.1.0",
+				},
+				err:    errBoom,
+				failed: []string{"broken.example.org", "working.example.org", "xpkg.upbound.io"},
+			},
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			ref, err := name.ParseReference("xpkg.upbound.io/crossplane/provider-nop:v0.1.0")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tried := make([]string, 0)
+			err = tc.mirrors.Do(ref, func(ref name.Reference) error {
+				tried = append(tried, ref.Name())
+				return tc.fn(ref)
+			})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nDo(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.tried, tried); diff != "" {
+				t.Errorf("\n%s\nDo(...): -want tried, +got tried:\n%s", tc.reason, diff)
+			}
+
+			if tc.mirrors == nil {
+				return
+			}
+			failed := make([]string, 0)
+			for mirror, s := range tc.mirrors.stats {
+				if !s.failed.IsZero() {
+					failed = append(failed, mirror)
+				}
+			}
+			if diff := cmp.Diff(tc.want.failed, failed, cmpopts.SortSlices(func(a, b string) bool { return a < b }), cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nDo(...): -want failed, +got failed:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -133,6 +133,28 @@ func ParsePackageSourceFromReference(ref name.Reference) string {
 	return strings.TrimRight(strings.TrimSuffix(ref.String(), ref.Identifier()), identifierDelimeters)
 }
 
+// PinSource returns the supplied registry package source pinned to the
+// supplied digest, replacing any digest it was already pinned to. A tag is
+// kept, so that the source still reports the version it was pinned from, e.g.
+// xpkg.upbound.io/cool/pkg:v1.0.0@sha256:c88b938d6e7b2ed43d40b71e5a55df9c60fa653bea0c0961f3294fac46d5b56e.
+func PinSource(source, digest string) string {
+	base, _, _ := strings.Cut(source, "@")
+	return base + "@" + digest
+}
+
+// UnpinSource returns the tag a registry package source was pinned from by
+// PinSource. It returns the source unchanged if it has no tag, or isn't pinned.
+func UnpinSource(source string) string {
+	base, _, pinned := strings.Cut(source, "@")
+	if !pinned {
+		return source
+	}
+	if i := strings.LastIndex(base, "/"); strings.Contains(base[i+1:], ":") {
+		return base
+	}
+	return source
+}
+
 type metaPkg struct {
 	Metadata struct {
 		Name string `json:"name"`
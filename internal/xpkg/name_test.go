@@ -180,6 +180,76 @@ func TestSourceFromReference(t *testing.T) {
 	}
 }
 
+func TestPinSource(t *testing.T) {
+	digest := "sha256:c88b938d6e7b2ed43d40b71e5a55df9c60fa653bea0c0961f3294fac46d5b56e"
+
+	cases := map[string]struct {
+		reason string
+		source string
+		want   string
+	}{
+		"Tag": {
+			reason: "A tagged source should keep its tag when it's pinned.",
+			source: "xpkg.upbound.io/crossplane/provider-aws:v1.0.0",
+			want:   "xpkg.upbound.io/crossplane/provider-aws:v1.0.0@" + digest,
+		},
+		"RegistryPort": {
+			reason: "A registry port shouldn't be mistaken for a tag.",
+			source: "localhost:5000/crossplane/provider-aws",
+			want:   "localhost:5000/crossplane/provider-aws@" + digest,
+		},
+		"AlreadyPinned": {
+			reason: "A pinned source should be pinned to the new digest.",
+			source: "xpkg.upbound.io/crossplane/provider-aws:v1.0.0@sha256:0000000000000000000000000000000000000000000000000000000000000000",
+			want:   "xpkg.upbound.io/crossplane/provider-aws:v1.0.0@" + digest,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := PinSource(tc.source, digest)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nPinSource(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestUnpinSource(t *testing.T) {
+	digest := "sha256:c88b938d6e7b2ed43d40b71e5a55df9c60fa653bea0c0961f3294fac46d5b56e"
+
+	cases := map[string]struct {
+		reason string
+		source string
+		want   string
+	}{
+		"NotPinned": {
+			reason: "A source that isn't pinned should be returned unchanged.",
+			source: "xpkg.upbound.io/crossplane/provider-aws:v1.0.0",
+			want:   "xpkg.upbound.io/crossplane/provider-aws:v1.0.0",
+		},
+		"PinnedTag": {
+			reason: "A pinned tagged source should return its tag.",
+			source: "xpkg.upbound.io/crossplane/provider-aws:v1.0.0@" + digest,
+			want:   "xpkg.upbound.io/crossplane/provider-aws:v1.0.0",
+		},
+		"PinnedNoTag": {
+			reason: "A pinned source with no tag should be returned unchanged.",
+			source: "localhost:5000/crossplane/provider-aws@" + digest,
+			want:   "localhost:5000/crossplane/provider-aws@" + digest,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := UnpinSource(tc.source)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nUnpinSource(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestBuildPath(t *testing.T) {
 	type args struct {
 		path string
@@ -0,0 +1,197 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+const (
+	// registryBackoffBase is how long we back off from a registry the first
+	// time it rate limits us. It doubles each consecutive time.
+	registryBackoffBase = 30 * time.Second
+
+	// registryBackoffMax is the longest we back off from a registry.
+	registryBackoffMax = 10 * time.Minute
+)
+
+// A RateLimitedError indicates that a registry rate limited requests.
+type RateLimitedError struct {
+	// Registry that rate limited requests.
+	Registry string
+
+	// RetryAfter is how long to wait before sending the registry another
+	// request.
+	RetryAfter time.Duration
+
+	err error
+}
+
+// Error returns the error message.
+func (e *RateLimitedError) Error() string {
+	msg := fmt.Sprintf("registry %s is rate limiting requests, retrying in %s", e.Registry, e.RetryAfter.Round(time.Second))
+	if e.err == nil {
+		return msg
+	}
+	return msg + ": " + e.err.Error()
+}
+
+// Unwrap returns the error the registry returned, if any.
+func (e *RateLimitedError) Unwrap() error {
+	return e.err
+}
+
+// RateLimited returns true if the supplied error indicates a registry rate
+// limited a request, and how long to wait before sending it another request.
+func RateLimited(err error) (time.Duration, bool) {
+	rl := &RateLimitedError{}
+	if errors.As(err, &rl) {
+		return rl.RetryAfter, true
+	}
+	if tooManyRequests(err) {
+		return registryBackoffBase, true
+	}
+	return 0, false
+}
+
+// tooManyRequests returns true if the supplied error is an HTTP 429 (Too Many
+// Requests) response from a registry.
+func tooManyRequests(err error) bool {
+	te := &transport.Error{}
+	if !errors.As(err, &te) {
+		return false
+	}
+	if te.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	for _, d := range te.Errors {
+		if d.Code == transport.TooManyRequestsErrorCode {
+			return true
+		}
+	}
+	return false
+}
+
+type registryBackoff struct {
+	// failures is how many consecutive times the registry rate limited us.
+	failures int
+
+	// until is when we may send the registry requests again.
+	until time.Time
+}
+
+// A RegistryBackoff backs off from registries that rate limit requests. Each
+// time a registry rate limits a request the RegistryBackoff refuses to send it
+// requests for an exponentially increasing, jittered, duration. It stops
+// backing off once the registry serves a request.
+//
+// RegistryBackoff is safe for concurrent use, and should be shared by all
+// Fetchers so that packages from the same registry back off together.
+type RegistryBackoff struct {
+	base time.Duration
+	max  time.Duration
+
+	mu         sync.Mutex
+	registries map[string]*registryBackoff
+	now        func() time.Time
+	jitter     func(d time.Duration) time.Duration
+}
+
+// NewRegistryBackoff returns a RegistryBackoff.
+func NewRegistryBackoff() *RegistryBackoff {
+	return &RegistryBackoff{
+		base:       registryBackoffBase,
+		max:        registryBackoffMax,
+		registries: make(map[string]*registryBackoff),
+		now:        time.Now,
+		jitter: func(d time.Duration) time.Duration {
+			// Wait between half and all of the supplied duration, so that
+			// packages that were rate limited together don't retry together.
+			return d/2 + time.Duration(rand.Int63n(int64(d/2)+1)) //nolint:gosec // We don't need a cryptographically secure jitter.
+		},
+	}
+}
+
+// Do calls the supplied function to send a request to the registry of the
+// supplied reference, unless we're backing off from that registry. It returns
+// a *RateLimitedError if we're backing off, or if the registry rate limited
+// the request.
+func (b *RegistryBackoff) Do(ref name.Reference, fn func() error) error {
+	if b == nil {
+		return fn()
+	}
+
+	registry := ref.Context().RegistryStr()
+
+	b.mu.Lock()
+	rb, ok := b.registries[registry]
+	now := b.now()
+	if ok && now.Before(rb.until) {
+		b.mu.Unlock()
+		return &RateLimitedError{Registry: registry, RetryAfter: rb.until.Sub(now)}
+	}
+	b.mu.Unlock()
+
+	err := fn()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !tooManyRequests(err) {
+		// We only stop backing off once the registry serves a request. Other
+		// errors don't tell us whether we're still being rate limited.
+		if err == nil {
+			delete(b.registries, registry)
+		}
+		return err
+	}
+
+	rb, ok = b.registries[registry]
+	if !ok {
+		rb = &registryBackoff{}
+		b.registries[registry] = rb
+	}
+
+	// Requests that were sent before we started backing off may be rate
+	// limited too. They shouldn't make us back off for longer.
+	now = b.now()
+	if now.Before(rb.until) {
+		return &RateLimitedError{Registry: registry, RetryAfter: rb.until.Sub(now), err: err}
+	}
+
+	rb.failures++
+	d := b.base
+	for i := 1; i < rb.failures && d < b.max; i++ {
+		d *= 2
+	}
+	if d > b.max {
+		d = b.max
+	}
+	d = b.jitter(d)
+	rb.until = now.Add(d)
+
+	return &RateLimitedError{Registry: registry, RetryAfter: d, err: err}
+}
@@ -0,0 +1,224 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestRateLimited(t *testing.T) {
+	type want struct {
+		wait time.Duration
+		ok   bool
+	}
+	cases := map[string]struct {
+		reason string
+		err    error
+		want   want
+	}{
+		"NoError": {
+			reason: "A nil error doesn't indicate we were rate limited.",
+		},
+		"OtherError": {
+			reason: "An error that isn't a rate limit doesn't indicate we were rate limited.",
+			err:    errors.Wrap(&transport.Error{StatusCode: http.StatusNotFound}, "boom"),
+		},
+		"TooManyRequestsStatus": {
+			reason: "A 429 response indicates we were rate limited, and should wait the base backoff.",
+			err:    errors.Wrap(&transport.Error{StatusCode: http.StatusTooManyRequests}, "boom"),
+			want:   want{wait: registryBackoffBase, ok: true},
+		},
+		"TooManyRequestsCode": {
+			reason: "A TOOMANYREQUESTS error code indicates we were rate limited, and should wait the base backoff.",
+			err: errors.Wrap(&transport.Error{
+				StatusCode: http.StatusForbidden,
+				Errors:     []transport.Diagnostic{{Code: transport.TooManyRequestsErrorCode}},
+			}, "boom"),
+			want: want{wait: registryBackoffBase, ok: true},
+		},
+		"RateLimitedError": {
+			reason: "A RateLimitedError indicates we were rate limited, and should wait as long as it says.",
+			err:    errors.Wrap(&RateLimitedError{Registry: "index.docker.io", RetryAfter: 2 * time.Minute}, "boom"),
+			want:   want{wait: 2 * time.Minute, ok: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			wait, ok := RateLimited(tc.err)
+			if diff := cmp.Diff(tc.want.wait, wait); diff != "" {
+				t.Errorf("\n%s\nRateLimited(...): -want wait, +got wait:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.ok, ok); diff != "" {
+				t.Errorf("\n%s\nRateLimited(...): -want ok, +got ok:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRegistryBackoffDo(t *testing.T) {
+	errBoom := errors.New("boom")
+	errTooMany := &transport.Error{StatusCode: http.StatusTooManyRequests}
+	now := time.Now()
+	registry := "index.docker.io"
+
+	type want struct {
+		called     bool
+		err        error
+		registries map[string]*registryBackoff
+	}
+	cases := map[string]struct {
+		reason     string
+		noBackoff  bool
+		registries map[string]*registryBackoff
+		fnErr      error
+		want       want
+	}{
+		"NilBackoff": {
+			reason:    "A nil RegistryBackoff should always send the request.",
+			noBackoff: true,
+			fnErr:     errTooMany,
+			want: want{
+				called: true,
+				err:    errTooMany,
+			},
+		},
+		"Success": {
+			reason: "We should stop backing off from a registry once it serves a request.",
+			registries: map[string]*registryBackoff{
+				registry: {failures: 2, until: now.Add(-1 * time.Second)},
+			},
+			want: want{
+				called:     true,
+				registries: map[string]*registryBackoff{},
+			},
+		},
+		"BackingOff": {
+			reason: "We shouldn't send a request to a registry we're backing off from.",
+			registries: map[string]*registryBackoff{
+				registry: {failures: 1, until: now.Add(1 * time.Minute)},
+			},
+			want: want{
+				err: &RateLimitedError{Registry: registry, RetryAfter: 1 * time.Minute},
+				registries: map[string]*registryBackoff{
+					registry: {failures: 1, until: now.Add(1 * time.Minute)},
+				},
+			},
+		},
+		"RateLimited": {
+			reason: "We should start backing off from a registry that rate limits a request.",
+			fnErr:  errTooMany,
+			want: want{
+				called: true,
+				err:    &RateLimitedError{Registry: registry, RetryAfter: registryBackoffBase, err: errTooMany},
+				registries: map[string]*registryBackoff{
+					registry: {failures: 1, until: now.Add(registryBackoffBase)},
+				},
+			},
+		},
+		"RateLimitedAgain": {
+			reason: "We should back off for exponentially longer each consecutive time a registry rate limits a request.",
+			registries: map[string]*registryBackoff{
+				registry: {failures: 2, until: now.Add(-1 * time.Second)},
+			},
+			fnErr: errTooMany,
+			want: want{
+				called: true,
+				err:    &RateLimitedError{Registry: registry, RetryAfter: 4 * registryBackoffBase, err: errTooMany},
+				registries: map[string]*registryBackoff{
+					registry: {failures: 3, until: now.Add(4 * registryBackoffBase)},
+				},
+			},
+		},
+		"RateLimitedMax": {
+			reason: "We shouldn't back off for longer than the max backoff.",
+			registries: map[string]*registryBackoff{
+				registry: {failures: 100, until: now.Add(-1 * time.Second)},
+			},
+			fnErr: errTooMany,
+			want: want{
+				called: true,
+				err:    &RateLimitedError{Registry: registry, RetryAfter: registryBackoffMax, err: errTooMany},
+				registries: map[string]*registryBackoff{
+					registry: {failures: 101, until: now.Add(registryBackoffMax)},
+				},
+			},
+		},
+		"OtherError": {
+			reason: "Errors that aren't rate limits shouldn't change whether we're backing off.",
+			registries: map[string]*registryBackoff{
+				registry: {failures: 1, until: now.Add(-1 * time.Second)},
+			},
+			fnErr: errBoom,
+			want: want{
+				called: true,
+				err:    errBoom,
+				registries: map[string]*registryBackoff{
+					registry: {failures: 1, until: now.Add(-1 * time.Second)},
+				},
+			},
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			ref, err := name.ParseReference("crossplane/provider-nop:v0.1.0")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var b *RegistryBackoff
+			if !tc.noBackoff {
+				b = NewRegistryBackoff()
+				b.now = func() time.Time { return now }
+				b.jitter = func(d time.Duration) time.Duration { return d }
+				if tc.registries != nil {
+					b.registries = tc.registries
+				}
+			}
+
+			called := false
+			err = b.Do(ref, func() error {
+				called = true
+				return tc.fnErr
+			})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nDo(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.called, called); diff != "" {
+				t.Errorf("\n%s\nDo(...): -want called, +got called:\n%s", tc.reason, diff)
+			}
+
+			if b == nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.registries, b.registries, cmp.AllowUnexported(registryBackoff{}), cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nDo(...): -want registries, +got registries:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
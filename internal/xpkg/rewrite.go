@@ -0,0 +1,108 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+)
+
+const (
+	errListImageConfigs  = "cannot list ImageConfigs"
+	errFmtRewriteImage   = "cannot rewrite package image using ImageConfig %q"
+	errFmtRewrittenImage = "rewritten package image %q is invalid"
+)
+
+// An ImageRewriter rewrites package image references before they're pulled.
+type ImageRewriter interface {
+	Rewrite(ctx context.Context, ref name.Reference) (name.Reference, error)
+}
+
+// An ImageRewriterFn rewrites package image references before they're pulled.
+type ImageRewriterFn func(ctx context.Context, ref name.Reference) (name.Reference, error)
+
+// Rewrite the supplied package image reference.
+func (fn ImageRewriterFn) Rewrite(ctx context.Context, ref name.Reference) (name.Reference, error) {
+	return fn(ctx, ref)
+}
+
+// An ImageConfigRewriter rewrites package image references using the
+// ImageConfig that matches them.
+type ImageConfigRewriter struct {
+	client client.Reader
+}
+
+// NewImageConfigRewriter returns an ImageRewriter that rewrites package image
+// references using the ImageConfigs read by the supplied client.
+func NewImageConfigRewriter(c client.Reader) *ImageConfigRewriter {
+	return &ImageConfigRewriter{client: c}
+}
+
+// Rewrite the supplied reference by replacing the prefix matched by the
+// ImageConfig with the longest matching prefix that configures rewriting. The
+// reference is returned unchanged if no such ImageConfig matches it.
+func (r *ImageConfigRewriter) Rewrite(ctx context.Context, ref name.Reference) (name.Reference, error) {
+	l := &v1beta1.ImageConfigList{}
+	if err := r.client.List(ctx, l); err != nil {
+		return nil, errors.Wrap(err, errListImageConfigs)
+	}
+
+	repo := ref.Context().Name()
+	var match *v1beta1.ImageConfig
+	prefix := ""
+	for i := range l.Items {
+		if l.Items[i].Spec.RewriteImage == nil {
+			continue
+		}
+		for _, m := range l.Items[i].Spec.MatchImages {
+			if m.Type != "" && m.Type != v1beta1.Prefix {
+				continue
+			}
+			if strings.HasPrefix(repo, m.Prefix) && len(m.Prefix) > len(prefix) {
+				match, prefix = &l.Items[i], m.Prefix
+			}
+		}
+	}
+	if match == nil {
+		return ref, nil
+	}
+
+	rewritten, err := rewriteReference(ref, match.Spec.RewriteImage.Prefix+strings.TrimPrefix(repo, prefix))
+	return rewritten, errors.Wrapf(err, errFmtRewriteImage, match.GetName())
+}
+
+// rewriteReference returns the supplied reference with its repository
+// replaced, keeping its tag or digest.
+func rewriteReference(ref name.Reference, repo string) (name.Reference, error) {
+	var (
+		out name.Reference
+		err error
+	)
+	if d, ok := ref.(name.Digest); ok {
+		out, err = name.NewDigest(repo + "@" + d.DigestStr())
+	} else {
+		out, err = name.NewTag(repo + ":" + ref.Identifier())
+	}
+	return out, errors.Wrapf(err, errFmtRewrittenImage, repo)
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-containerregistry/pkg/name"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+)
+
+func TestImageConfigRewriterRewrite(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	ic := func(n string, rewrite *v1beta1.ImageRewrite, prefixes ...string) v1beta1.ImageConfig {
+		c := v1beta1.ImageConfig{ObjectMeta: metav1.ObjectMeta{Name: n}, Spec: v1beta1.ImageConfigSpec{RewriteImage: rewrite}}
+		for _, p := range prefixes {
+			c.Spec.MatchImages = append(c.Spec.MatchImages, v1beta1.ImageMatch{Prefix: p})
+		}
+		return c
+	}
+	list := func(ics ...v1beta1.ImageConfig) func(client.ObjectList) error {
+		return func(o client.ObjectList) error {
+			o.(*v1beta1.ImageConfigList).Items = ics
+			return nil
+		}
+	}
+
+	type args struct {
+		client client.Reader
+		ref    string
+	}
+	type want struct {
+		ref string
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ErrListImageConfigs": {
+			reason: "We should return an error if we can't list ImageConfigs.",
+			args: args{
+				client: &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+				ref:    "xpkg.upbound.io/crossplane-contrib/provider-aws:v1.0.0",
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errListImageConfigs),
+			},
+		},
+		"NoMatch": {
+			reason: "A reference that doesn't match an ImageConfig shouldn't be rewritten.",
+			args: args{
+				client: &test.MockClient{MockList: test.NewMockListFn(nil, list(
+					ic("mirror", &v1beta1.ImageRewrite{Prefix: "registry.example.org/mirror"}, "ghcr.io"),
+				))},
+				ref: "xpkg.upbound.io/crossplane-contrib/provider-aws:v1.0.0",
+			},
+			want: want{
+				ref: "xpkg.upbound.io/crossplane-contrib/provider-aws:v1.0.0",
+			},
+		},
+		"RewriteTag": {
+			reason: "A tagged reference should have its matched prefix rewritten, keeping its tag.",
+			args: args{
+				client: &test.MockClient{MockList: test.NewMockListFn(nil, list(
+					ic("mirror", &v1beta1.ImageRewrite{Prefix: "registry.example.org/mirror"}, "xpkg.upbound.io"),
+				))},
+				ref: "xpkg.upbound.io/crossplane-contrib/provider-aws:v1.0.0",
+			},
+			want: want{
+				ref: "registry.example.org/mirror/crossplane-contrib/provider-aws:v1.0.0",
+			},
+		},
+		"RewriteDigest": {
+			reason: "A digest reference should have its matched prefix rewritten, keeping its digest.",
+			args: args{
+				client: &test.MockClient{MockList: test.NewMockListFn(nil, list(
+					ic("mirror", &v1beta1.ImageRewrite{Prefix: "registry.example.org/mirror"}, "xpkg.upbound.io"),
+				))},
+				ref: "xpkg.upbound.io/crossplane-contrib/provider-aws@sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d87fd5f5b11b843",
+			},
+			want: want{
+				ref: "registry.example.org/mirror/crossplane-contrib/provider-aws@sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d87fd5f5b11b843",
+			},
+		},
+		"LongestPrefix": {
+			reason: "The ImageConfig with the longest matching prefix that configures rewriting should be used.",
+			args: args{
+				client: &test.MockClient{MockList: test.NewMockListFn(nil, list(
+					ic("registry", &v1beta1.ImageRewrite{Prefix: "registry.example.org/mirror"}, "xpkg.upbound.io"),
+					ic("contrib", &v1beta1.ImageRewrite{Prefix: "registry.example.org/contrib"}, "ghcr.io", "xpkg.upbound.io/crossplane-contrib"),
+					ic("verify-only", nil, "xpkg.upbound.io/crossplane-contrib/provider-aws"),
+				))},
+				ref: "xpkg.upbound.io/crossplane-contrib/provider-aws:v1.0.0",
+			},
+			want: want{
+				ref: "registry.example.org/contrib/provider-aws:v1.0.0",
+			},
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			ref, err := name.ParseReference(tc.args.ref)
+			if err != nil {
+				t.Fatalf("name.ParseReference(...): %v", err)
+			}
+			r := NewImageConfigRewriter(tc.args.client)
+			got, err := r.Rewrite(context.Background(), ref)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nRewrite(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.ref, got.Name()); diff != "" {
+				t.Errorf("\n%s\nRewrite(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
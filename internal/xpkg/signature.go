@@ -0,0 +1,167 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+const (
+	// CosignSignatureAnnotation is the annotation of a cosign signature
+	// layer that contains the base64 encoded signature of the layer.
+	CosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+
+	// CosignSimpleSigningMediaType is the media type of a cosign signature
+	// layer. The layer's content is the signed payload.
+	CosignSimpleSigningMediaType = "application/vnd.dev.cosign.simplesigning.v1+json"
+)
+
+const (
+	errDecodePublicKey      = "cannot decode PEM encoded public key"
+	errParsePublicKey       = "cannot parse public key"
+	errFmtUnsupportedKey    = "unsupported public key type %T"
+	errGetSignatureManifest = "cannot get signature image manifest"
+	errNoSignatures         = "image has no cosign signatures"
+	errNoValidSignature     = "image has no valid cosign signature from a trusted authority"
+
+	errFmtGetSignatureLayer  = "cannot get signature layer %s"
+	errFmtReadSignatureLayer = "cannot read signature layer %s"
+)
+
+// ParsePublicKey parses a PEM encoded ECDSA, RSA, or Ed25519 public key.
+func ParsePublicKey(data []byte) (crypto.PublicKey, error) {
+	b, _ := pem.Decode(data)
+	if b == nil {
+		return nil, errors.New(errDecodePublicKey)
+	}
+	k, err := x509.ParsePKIXPublicKey(b.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, errParsePublicKey)
+	}
+	switch k.(type) {
+	case *ecdsa.PublicKey, *rsa.PublicKey, ed25519.PublicKey:
+		return k, nil
+	default:
+		return nil, errors.Errorf(errFmtUnsupportedKey, k)
+	}
+}
+
+// CosignSignatureTag returns the tag cosign stores the signatures of the
+// image with the supplied digest under, e.g. sha256-abc123.sig.
+func CosignSignatureTag(ref name.Reference, digest v1.Hash) name.Tag {
+	return ref.Context().Tag(strings.Replace(digest.String(), ":", "-", 1) + ".sig")
+}
+
+// A cosignPayload is the payload cosign signs. It's in the "simple signing"
+// format.
+type cosignPayload struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+	} `json:"critical"`
+}
+
+// VerifyCosignSignatures verifies that the supplied cosign signature image
+// contains a signature of the image with the supplied digest, made by any of
+// the supplied public keys.
+func VerifyCosignSignatures(sigs v1.Image, digest v1.Hash, keys []crypto.PublicKey) error {
+	m, err := sigs.Manifest()
+	if err != nil {
+		return errors.Wrap(err, errGetSignatureManifest)
+	}
+
+	found := false
+	for _, l := range m.Layers {
+		if l.MediaType != CosignSimpleSigningMediaType {
+			continue
+		}
+		sig, err := base64.StdEncoding.DecodeString(l.Annotations[CosignSignatureAnnotation])
+		if err != nil || len(sig) == 0 {
+			continue
+		}
+		found = true
+
+		payload, err := layerContent(sigs, l.Digest)
+		if err != nil {
+			return err
+		}
+
+		// The payload must be for this image. Otherwise a valid signature
+		// of one image could be copied to another.
+		p := &cosignPayload{}
+		if err := json.Unmarshal(payload, p); err != nil || p.Critical.Image.DockerManifestDigest != digest.String() {
+			continue
+		}
+
+		for _, k := range keys {
+			if verifySignature(k, payload, sig) {
+				return nil
+			}
+		}
+	}
+
+	if !found {
+		return errors.New(errNoSignatures)
+	}
+	return errors.New(errNoValidSignature)
+}
+
+func layerContent(img v1.Image, h v1.Hash) ([]byte, error) {
+	l, err := img.LayerByDigest(h)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtGetSignatureLayer, h)
+	}
+	rc, err := l.Compressed()
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtReadSignatureLayer, h)
+	}
+	defer rc.Close() //nolint:errcheck // Only reading.
+	b, err := io.ReadAll(rc)
+	return b, errors.Wrapf(err, errFmtReadSignatureLayer, h)
+}
+
+// verifySignature returns true if the supplied signature of the supplied
+// payload was made by the supplied public key. cosign signs the SHA-256 digest
+// of the payload, except when using Ed25519 which signs the payload itself.
+func verifySignature(k crypto.PublicKey, payload, sig []byte) bool {
+	h := sha256.Sum256(payload)
+	switch k := k.(type) {
+	case *ecdsa.PublicKey:
+		return ecdsa.VerifyASN1(k, h[:], sig)
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(k, crypto.SHA256, h[:], sig) == nil
+	case ed25519.PublicKey:
+		return ed25519.Verify(k, payload, sig)
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestVerifyCosignSignatures(t *testing.T) {
+	trusted, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	untrusted, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	digest := v1.Hash{Algorithm: "sha256", Hex: "ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d2ddd5bd4e0ed1d"}
+	other := v1.Hash{Algorithm: "sha256", Hex: "0000000000000000000000000000000000000000000000000000000000000000"}
+
+	// signatures returns a cosign signature image with one signature of the
+	// supplied digest, made by the supplied key.
+	signatures := func(t *testing.T, k *ecdsa.PrivateKey, d v1.Hash) v1.Image {
+		t.Helper()
+		payload := []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"xpkg.upbound.io/cool/provider"},"image":{"docker-manifest-digest":%q},"type":"cosign container image signature"},"optional":null}`, d.String()))
+		h := sha256.Sum256(payload)
+		sig, err := ecdsa.SignASN1(rand.Reader, k, h[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		img, err := mutate.Append(empty.Image, mutate.Addendum{
+			Layer:       static.NewLayer(payload, types.MediaType(CosignSimpleSigningMediaType)),
+			Annotations: map[string]string{CosignSignatureAnnotation: base64.StdEncoding.EncodeToString(sig)},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return img
+	}
+
+	cases := map[string]struct {
+		reason string
+		sigs   func(t *testing.T) v1.Image
+		want   error
+	}{
+		"Valid": {
+			reason: "An image signed by a trusted key should be verified.",
+			sigs:   func(t *testing.T) v1.Image { return signatures(t, trusted, digest) },
+		},
+		"Untrusted": {
+			reason: "An image signed only by an untrusted key shouldn't be verified.",
+			sigs:   func(t *testing.T) v1.Image { return signatures(t, untrusted, digest) },
+			want:   errors.New(errNoValidSignature),
+		},
+		"OtherImage": {
+			reason: "A trusted signature of a different image shouldn't verify this one.",
+			sigs:   func(t *testing.T) v1.Image { return signatures(t, trusted, other) },
+			want:   errors.New(errNoValidSignature),
+		},
+		"NoSignatures": {
+			reason: "An image with no signatures shouldn't be verified.",
+			sigs:   func(_ *testing.T) v1.Image { return empty.Image },
+			want:   errors.New(errNoSignatures),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := VerifyCosignSignatures(tc.sigs(t), digest, []crypto.PublicKey{trusted.Public()})
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nVerifyCosignSignatures(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestParsePublicKey(t *testing.T) {
+	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(k.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type want struct {
+		key crypto.PublicKey
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		data   []byte
+		want   want
+	}{
+		"ECDSA": {
+			reason: "We should parse a PEM encoded ECDSA public key.",
+			data:   pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}),
+			want:   want{key: k.Public()},
+		},
+		"NotPEM": {
+			reason: "We should return an error if the data isn't PEM encoded.",
+			data:   []byte("cool"),
+			want:   want{err: errors.New(errDecodePublicKey)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParsePublicKey(tc.data)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nParsePublicKey(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if tc.want.key != nil && !k.PublicKey.Equal(got) {
+				t.Errorf("\n%s\nParsePublicKey(...): want the supplied public key", tc.reason)
+			}
+		})
+	}
+}
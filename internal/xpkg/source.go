@@ -0,0 +1,66 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"strings"
+)
+
+// ConfigMapSourcePrefix prefixes a package source that refers to a ConfigMap
+// in the Crossplane namespace, rather than to an OCI image. The ConfigMap's
+// StreamFile key contains the package's YAML stream, e.g.
+// configmap://my-configuration.
+const ConfigMapSourcePrefix = "configmap://"
+
+// ParseConfigMapSource returns the name of the ConfigMap the supplied package
+// source refers to. It returns false if the source doesn't refer to a
+// ConfigMap.
+func ParseConfigMapSource(source string) (string, bool) {
+	if !strings.HasPrefix(source, ConfigMapSourcePrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(source, ConfigMapSourcePrefix), true
+}
+
+// LocalSourcePrefix prefixes a package source that refers to a package in
+// Crossplane's local packages directory, rather than to an OCI image in a
+// registry. The path is relative to the local packages directory and may be
+// either an xpkg file or an OCI image layout directory, e.g.
+// file://configurations/platform.xpkg.
+const LocalSourcePrefix = "file://"
+
+// ParseLocalSource returns the path of the local package the supplied package
+// source refers to. It returns false if the source doesn't refer to a local
+// package.
+func ParseLocalSource(source string) (string, bool) {
+	if !strings.HasPrefix(source, LocalSourcePrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(source, LocalSourcePrefix), true
+}
+
+// IsRegistrySource returns true if the supplied package source refers to an
+// OCI image in a registry, rather than to a ConfigMap or a local package.
+func IsRegistrySource(source string) bool {
+	if _, ok := ParseConfigMapSource(source); ok {
+		return false
+	}
+	if _, ok := ParseLocalSource(source); ok {
+		return false
+	}
+	return true
+}
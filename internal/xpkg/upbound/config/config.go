@@ -23,6 +23,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 )
@@ -44,11 +45,77 @@ const (
 
 	errProfileNotFoundFmt = "profile not found with identifier: %s"
 	errNoProfilesFound    = "no profiles found"
+
+	errContextNotFoundFmt = "context not found with name: %s"
 )
 
 // Config is format for the up configuration file.
 type Config struct {
 	Upbound Upbound `json:"upbound"`
+
+	// CurrentContext is the context used when none is specified.
+	CurrentContext string `json:"currentContext,omitempty"`
+
+	// Contexts are named sets of defaults for the CLI's flags, for example to
+	// connect to a particular control plane and registry. Key is name of the
+	// context.
+	Contexts map[string]Context `json:"contexts,omitempty"`
+}
+
+// A Context is a named set of defaults for the CLI's flags. Flags that are
+// specified explicitly take precedence over a context.
+type Context struct {
+	// Kubeconfig is the path to the kubeconfig file used to connect to the
+	// control plane.
+	Kubeconfig string `json:"kubeconfig,omitempty"`
+
+	// KubeContext is the kubeconfig context used to connect to the control
+	// plane.
+	KubeContext string `json:"kubeContext,omitempty"`
+
+	// DefaultRegistry is the registry used for packages that don't specify
+	// one.
+	DefaultRegistry string `json:"defaultRegistry,omitempty"`
+
+	// PackagePullSecrets are the secrets the package manager should use to
+	// pull packages.
+	PackagePullSecrets []string `json:"packagePullSecrets,omitempty"`
+}
+
+// Flags returns the flag values set by this context, keyed by flag name.
+func (c Context) Flags() map[string]string {
+	f := map[string]string{}
+	if c.Kubeconfig != "" {
+		f["kubeconfig"] = c.Kubeconfig
+	}
+	if c.KubeContext != "" {
+		f["kube-context"] = c.KubeContext
+	}
+	if c.DefaultRegistry != "" {
+		f["default-registry"] = c.DefaultRegistry
+	}
+	if len(c.PackagePullSecrets) > 0 {
+		f["package-pull-secrets"] = strings.Join(c.PackagePullSecrets, ",")
+	}
+	return f
+}
+
+// GetContext gets the context with the supplied name, or the current context
+// if the name is empty. It returns an empty context if the name is empty and
+// there is no current context. If a named context does not exist an error is
+// returned.
+func (c *Config) GetContext(name string) (Context, error) {
+	if name == "" {
+		name = c.CurrentContext
+	}
+	if name == "" {
+		return Context{}, nil
+	}
+	ctx, ok := c.Contexts[name]
+	if !ok {
+		return Context{}, errors.Errorf(errContextNotFoundFmt, name)
+	}
+	return ctx, nil
 }
 
 // Extract performs extraction of configuration from the provided source.
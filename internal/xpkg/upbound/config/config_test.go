@@ -519,3 +519,78 @@ func TestBaseToJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestGetContext(t *testing.T) {
+	dev := Context{KubeContext: "kind-dev"}
+	prod := Context{KubeContext: "prod", DefaultRegistry: "registry.example.org"}
+
+	type want struct {
+		ctx Context
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		name   string
+		cfg    *Config
+		want   want
+	}{
+		"NoCurrentContext": {
+			reason: "If no name is supplied and there is no current context an empty context should be returned.",
+			cfg:    &Config{},
+			want:   want{ctx: Context{}},
+		},
+		"CurrentContext": {
+			reason: "If no name is supplied the current context should be returned.",
+			cfg: &Config{
+				CurrentContext: "dev",
+				Contexts:       map[string]Context{"dev": dev, "prod": prod},
+			},
+			want: want{ctx: dev},
+		},
+		"NamedContext": {
+			reason: "If a name is supplied the named context should be returned.",
+			name:   "prod",
+			cfg: &Config{
+				CurrentContext: "dev",
+				Contexts:       map[string]Context{"dev": dev, "prod": prod},
+			},
+			want: want{ctx: prod},
+		},
+		"ErrorContextNotExist": {
+			reason: "If the named context does not exist an error should be returned.",
+			name:   "staging",
+			cfg: &Config{
+				Contexts: map[string]Context{"dev": dev},
+			},
+			want: want{err: errors.Errorf(errContextNotFoundFmt, "staging")},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx, err := tc.cfg.GetContext(tc.name)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nGetContext(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.ctx, ctx); diff != "" {
+				t.Errorf("\n%s\nGetContext(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestContextFlags(t *testing.T) {
+	ctx := Context{
+		Kubeconfig:         "/home/cool/.kube/config",
+		KubeContext:        "kind-dev",
+		PackagePullSecrets: []string{"cool-secret", "other-secret"},
+	}
+	want := map[string]string{
+		"kubeconfig":           "/home/cool/.kube/config",
+		"kube-context":         "kind-dev",
+		"package-pull-secrets": "cool-secret,other-secret",
+	}
+	if diff := cmp.Diff(want, ctx.Flags()); diff != "" {
+		t.Errorf("Flags(): -want, +got:\n%s", diff)
+	}
+}
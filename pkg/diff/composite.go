@@ -0,0 +1,172 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diff
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+	ucomposite "github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+
+	"github.com/crossplane/crossplane/internal/controller/apiextensions/composite"
+	"github.com/crossplane/crossplane/pkg/render"
+)
+
+const (
+	errGetXR       = "cannot get composite resource from the cluster"
+	errGetComposed = "cannot get composed resource from the cluster"
+	errRender      = "cannot render composite resource"
+	errDiff        = "cannot diff composed resource"
+)
+
+// placeholderUID is the UID of an XR that doesn't exist yet.
+const placeholderUID = "00000000-0000-0000-0000-000000000000"
+
+// DiffCompositeResource renders the supplied inputs, then diffs each of the
+// resulting composed resources against the cluster. If the XR already exists
+// in the cluster its composed resources are read from the cluster and passed
+// to the Function pipeline as observed resources. Any observed resources in
+// the supplied inputs are ignored.
+func DiffCompositeResource(ctx context.Context, c client.Client, in render.Inputs) ([]ResourceDiff, error) {
+	xr := in.CompositeResource.DeepCopy()
+
+	existing, err := GetCompositeResource(ctx, c, xr)
+	if err != nil {
+		return nil, err
+	}
+	// Composed resources are rendered with a controller reference to the
+	// XR. The API server rejects applying them unless it references the
+	// existing XR's UID. An XR that doesn't exist yet has no UID, so we use
+	// a placeholder. Nothing is persisted by the dry-run apply.
+	xr.SetUID(placeholderUID)
+	if existing != nil {
+		xr.SetUID(existing.GetUID())
+		xr.SetResourceVersion(existing.GetResourceVersion())
+	}
+
+	ors, err := ObservedResources(ctx, c, existing)
+	if err != nil {
+		return nil, err
+	}
+
+	in.CompositeResource = xr
+	in.ObservedResources = ors
+	out, err := render.Render(ctx, in)
+	if err != nil {
+		return nil, errors.Wrap(err, errRender)
+	}
+
+	d := NewDiffer(c)
+	owner := composite.ComposedFieldOwnerName(xr)
+	diffs := make([]ResourceDiff, 0, len(out.ComposedResources))
+	for i := range out.ComposedResources {
+		cd := &out.ComposedResources[i]
+		name := cd.GetAnnotations()[render.AnnotationKeyCompositionResourceName]
+		rd, err := d.Diff(ctx, name, &cd.Unstructured, owner)
+		if err != nil {
+			return nil, errors.Wrapf(err, "%s %q", errDiff, name)
+		}
+		diffs = append(diffs, rd)
+	}
+	return diffs, nil
+}
+
+// GetCompositeResource returns the supplied XR, as it currently exists in the
+// cluster. It returns nil if the XR doesn't exist yet.
+func GetCompositeResource(ctx context.Context, c client.Reader, xr *ucomposite.Unstructured) (*ucomposite.Unstructured, error) {
+	existing := ucomposite.New()
+	existing.SetGroupVersionKind(xr.GroupVersionKind())
+	err := c.Get(ctx, client.ObjectKeyFromObject(xr), existing)
+	if kerrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errGetXR)
+	}
+	return existing, nil
+}
+
+// ObservedResources returns the composed resources of the supplied existing
+// XR, as they currently exist in the cluster. It returns no resources if the
+// XR is nil, i.e. doesn't exist yet.
+func ObservedResources(ctx context.Context, c client.Reader, existing *ucomposite.Unstructured) ([]composed.Unstructured, error) {
+	if existing == nil {
+		return []composed.Unstructured{}, nil
+	}
+
+	ors := make([]composed.Unstructured, 0, len(existing.GetResourceReferences()))
+	for _, ref := range existing.GetResourceReferences() {
+		cd := composed.New()
+		cd.SetAPIVersion(ref.APIVersion)
+		cd.SetKind(ref.Kind)
+		err := c.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, cd)
+		if kerrors.IsNotFound(err) {
+			// The composed resource was deleted out from under the XR. It'll
+			// be recreated, so it isn't observed.
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "%s: %s %q", errGetComposed, ref.Kind, ref.Name)
+		}
+		ors = append(ors, *cd)
+	}
+	return ors, nil
+}
+
+// PrintDiffs writes the supplied diffs to the supplied writer.
+func PrintDiffs(w io.Writer, diffs []ResourceDiff, showUnchanged bool) error {
+	for _, d := range diffs {
+		if d.Type == ChangeTypeUnchanged && !showUnchanged {
+			continue
+		}
+		id := d.Desired.GetName()
+		if id == "" {
+			id = d.Desired.GetGenerateName() + "(generated)"
+		}
+		line := fmt.Sprintf("%s %s/%s", d.Type, d.Desired.GetKind(), id)
+		if d.Name != "" {
+			line = fmt.Sprintf("%s (%s)", line, d.Name)
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+		if d.Diff == "" {
+			continue
+		}
+		if _, err := fmt.Fprintln(w, d.Diff); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Changed returns how many of the supplied diffs would change a resource.
+func Changed(diffs []ResourceDiff) int {
+	changed := 0
+	for _, d := range diffs {
+		if d.Type != ChangeTypeUnchanged {
+			changed++
+		}
+	}
+	return changed
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diff
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/structpb"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+	ucomposite "github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	fnv1beta1 "github.com/crossplane/crossplane/apis/apiextensions/fn/proto/v1beta1"
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	pkgv1beta1 "github.com/crossplane/crossplane/apis/pkg/v1beta1"
+	"github.com/crossplane/crossplane/pkg/render"
+)
+
+type mockFunctionRunner struct {
+	fnv1beta1.UnimplementedFunctionRunnerServiceServer
+
+	rsp *fnv1beta1.RunFunctionResponse
+}
+
+func (r *mockFunctionRunner) RunFunction(_ context.Context, _ *fnv1beta1.RunFunctionRequest) (*fnv1beta1.RunFunctionResponse, error) {
+	return r.rsp, nil
+}
+
+func TestDiffCompositeResource(t *testing.T) {
+	// Serve a Function that always desires the same composed resource.
+	lis, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+
+	bucket, _ := structpb.NewStruct(map[string]any{
+		"apiVersion": "example.org/v1",
+		"kind":       "Bucket",
+		"metadata":   map[string]any{"name": "cool-bucket"},
+		"spec":       map[string]any{"region": "us-west-2"},
+	})
+	srv := grpc.NewServer(grpc.Creds(insecure.NewCredentials()))
+	fnv1beta1.RegisterFunctionRunnerServiceServer(srv, &mockFunctionRunner{rsp: &fnv1beta1.RunFunctionResponse{
+		Desired: &fnv1beta1.State{
+			Resources: map[string]*fnv1beta1.Resource{"bucket": {Resource: bucket}},
+		},
+	}})
+	go srv.Serve(lis) //nolint:errcheck // This will stop when lis is closed.
+
+	pipeline := v1.CompositionModePipeline
+	in := func() render.Inputs {
+		xr := ucomposite.New()
+		xr.SetAPIVersion("example.org/v1")
+		xr.SetKind("XBucket")
+		xr.SetName("cool-xr")
+		return render.Inputs{
+			CompositeResource: xr,
+			Composition: &v1.Composition{
+				Spec: v1.CompositionSpec{
+					Mode:     &pipeline,
+					Pipeline: []v1.PipelineStep{{Step: "test", FunctionRef: v1.FunctionReference{Name: "function-test"}}},
+				},
+			},
+			Functions: []pkgv1beta1.Function{{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "function-test",
+					Annotations: map[string]string{
+						render.AnnotationKeyRuntime:                  string(render.AnnotationValueRuntimeDevelopment),
+						render.AnnotationKeyRuntimeDevelopmentTarget: lis.Addr().String(),
+					},
+				},
+			}},
+		}
+	}
+
+	// apply returns a MockPatchFn that rejects composed resources whose
+	// controller reference doesn't have the supplied UID, like the API server
+	// does.
+	apply := func(uid string) test.MockPatchFn {
+		return func(_ context.Context, obj client.Object, _ client.Patch, _ ...client.PatchOption) error {
+			if c := metav1.GetControllerOf(obj); c == nil || string(c.UID) != uid {
+				return errors.New("controller reference has the wrong UID")
+			}
+			return nil
+		}
+	}
+
+	type want struct {
+		diffs []ChangeType
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason string
+		client client.Client
+		want   want
+	}{
+		"NewXR": {
+			reason: "The composed resources of an XR that doesn't exist yet should be added, with a controller reference to a placeholder UID.",
+			client: &test.MockClient{
+				MockGet:   test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+				MockPatch: apply(placeholderUID),
+			},
+			want: want{
+				diffs: []ChangeType{ChangeTypeAdded},
+			},
+		},
+		"ExistingXR": {
+			reason: "The composed resources of an existing XR should be rendered with a controller reference to it, and diffed against the cluster.",
+			client: &test.MockClient{
+				MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+					switch o := obj.(type) {
+					case *ucomposite.Unstructured:
+						o.SetUID("xr-uid")
+						o.SetResourceVersion("42")
+						return nil
+					case *composed.Unstructured:
+						return nil
+					case *unstructured.Unstructured:
+						o.SetAPIVersion("example.org/v1")
+						o.SetKind("Bucket")
+						o.SetName("cool-bucket")
+						return unstructured.SetNestedField(o.Object, "eu-west-1", "spec", "region")
+					}
+					return nil
+				},
+				MockPatch: apply("xr-uid"),
+			},
+			want: want{
+				diffs: []ChangeType{ChangeTypeModified},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			diffs, err := DiffCompositeResource(context.Background(), tc.client, in())
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nDiffCompositeResource(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			got := make([]ChangeType, 0, len(diffs))
+			for _, d := range diffs {
+				got = append(got, d.Type)
+			}
+			if diff := cmp.Diff(tc.want.diffs, got); diff != "" {
+				t.Errorf("\n%s\nDiffCompositeResource(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package diff computes what would change in a cluster if Crossplane applied
+// rendered resources.
+//
+// The package can be imported to embed diffing in other tools. Use
+// DiffCompositeResource to render an XR and diff all of its composed resources,
+// or a Differ to diff a single resource against the cluster's copy.
+package diff
+
+import (
+	"context"
+
+	"github.com/google/go-cmp/cmp"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+const (
+	errGetCurrent   = "cannot get current state of resource"
+	errDryRun       = "cannot server-side dry-run apply resource"
+	errDryRunCreate = "cannot dry-run create resource"
+)
+
+// A ChangeType describes how a desired resource differs from the cluster.
+type ChangeType string
+
+// Change types.
+const (
+	// ChangeTypeAdded indicates the resource doesn't exist yet and would be
+	// created.
+	ChangeTypeAdded ChangeType = "Added"
+
+	// ChangeTypeModified indicates the resource exists and would be changed.
+	ChangeTypeModified ChangeType = "Modified"
+
+	// ChangeTypeUnchanged indicates the resource exists and wouldn't change.
+	ChangeTypeUnchanged ChangeType = "Unchanged"
+)
+
+// A ResourceDiff describes the change that applying a desired resource would
+// make to the cluster.
+type ResourceDiff struct {
+	// Name of the resource in the Composition pipeline.
+	Name string
+
+	// Desired state of the resource, as returned by the dry-run.
+	Desired *unstructured.Unstructured
+
+	// Type of change.
+	Type ChangeType
+
+	// Diff between the current and desired state. Empty if the resource is
+	// unchanged.
+	Diff string
+}
+
+// A Differ computes what would change in the cluster if a desired resource
+// were applied, by server-side dry-run applying it.
+type Differ struct {
+	client client.Client
+}
+
+// NewDiffer returns a Differ that uses the supplied client.
+func NewDiffer(c client.Client) *Differ {
+	return &Differ{client: c}
+}
+
+// ApplyOptions returns the options used to server-side apply a desired
+// resource as the supplied field owner. The Differ dry-runs resources with
+// these options, so a real apply should use them too for its result to match
+// the diff.
+func ApplyOptions(fieldOwner string) []client.PatchOption {
+	return []client.PatchOption{client.ForceOwnership, client.FieldOwner(fieldOwner)}
+}
+
+// Diff the supplied desired resource against the cluster. The resource is
+// server-side dry-run applied with ApplyOptions, so the diff accounts for
+// defaulting, admission webhooks, and merging with fields owned by other field
+// managers.
+func (d *Differ) Diff(ctx context.Context, name string, desired *unstructured.Unstructured, fieldOwner string) (ResourceDiff, error) {
+	// Resources that don't have a name yet will be created using
+	// generateName. Server-side apply requires a name, so we dry-run create
+	// them instead. They're always new. We don't show the name the API
+	// server generated for the dry-run, because it will generate a
+	// different one when the resource is really created.
+	if desired.GetName() == "" {
+		dry := desired.DeepCopy()
+		if err := d.client.Create(ctx, dry, client.DryRunAll, client.FieldOwner(fieldOwner)); err != nil {
+			return ResourceDiff{}, errors.Wrap(err, errDryRunCreate)
+		}
+		dry.SetName("")
+		return ResourceDiff{Name: name, Desired: dry, Type: ChangeTypeAdded, Diff: cmp.Diff(map[string]any(nil), sanitize(dry).Object)}, nil
+	}
+
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(desired.GroupVersionKind())
+	err := d.client.Get(ctx, client.ObjectKeyFromObject(desired), current)
+	if client.IgnoreNotFound(err) != nil {
+		return ResourceDiff{}, errors.Wrap(err, errGetCurrent)
+	}
+	exists := !kerrors.IsNotFound(err)
+
+	dry := desired.DeepCopy()
+	if err := d.client.Patch(ctx, dry, client.Apply, append([]client.PatchOption{client.DryRunAll}, ApplyOptions(fieldOwner)...)...); err != nil {
+		return ResourceDiff{}, errors.Wrap(err, errDryRun)
+	}
+
+	if !exists {
+		return ResourceDiff{Name: name, Desired: dry, Type: ChangeTypeAdded, Diff: cmp.Diff(map[string]any(nil), sanitize(dry).Object)}, nil
+	}
+
+	diff := cmp.Diff(sanitize(current).Object, sanitize(dry).Object)
+	if diff == "" {
+		return ResourceDiff{Name: name, Desired: dry, Type: ChangeTypeUnchanged}, nil
+	}
+	return ResourceDiff{Name: name, Desired: dry, Type: ChangeTypeModified, Diff: diff}, nil
+}
+
+// sanitize returns a copy of the supplied resource without fields that are
+// managed by the API server, or that Crossplane doesn't compose. These would
+// otherwise just be noise in the diff.
+func sanitize(u *unstructured.Unstructured) *unstructured.Unstructured {
+	s := u.DeepCopy()
+	s.SetManagedFields(nil)
+	s.SetResourceVersion("")
+	s.SetGeneration(0)
+	s.SetUID("")
+	unstructured.RemoveNestedField(s.Object, "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(s.Object, "status")
+	return s
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diff
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestDiff(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	desired := func() *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "example.org/v1",
+			"kind":       "Bucket",
+			"metadata": map[string]any{
+				"name": "cool-bucket",
+			},
+			"spec": map[string]any{
+				"region": "us-west-2",
+			},
+		}}
+	}
+
+	type args struct {
+		client  client.Client
+		desired *unstructured.Unstructured
+	}
+	type want struct {
+		typ  ChangeType
+		diff bool
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"GenerateName": {
+			reason: "A resource without a name should be dry-run created, and always added.",
+			args: args{
+				client: &test.MockClient{
+					MockCreate: test.NewMockCreateFn(nil),
+				},
+				desired: &unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": "example.org/v1",
+					"kind":       "Bucket",
+					"metadata": map[string]any{
+						"generateName": "cool-",
+					},
+				}},
+			},
+			want: want{
+				typ:  ChangeTypeAdded,
+				diff: true,
+			},
+		},
+		"GenerateNameDryRunError": {
+			reason: "We should return errors encountered dry-run creating a resource without a name.",
+			args: args{
+				client: &test.MockClient{
+					MockCreate: test.NewMockCreateFn(errBoom),
+				},
+				desired: &unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": "example.org/v1",
+					"kind":       "Bucket",
+					"metadata": map[string]any{
+						"generateName": "cool-",
+					},
+				}},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errDryRunCreate),
+			},
+		},
+		"GetError": {
+			reason: "We should return errors encountered getting the current resource.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(errBoom),
+				},
+				desired: desired(),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetCurrent),
+			},
+		},
+		"DryRunError": {
+			reason: "We should return errors encountered dry-run applying the desired resource.",
+			args: args{
+				client: &test.MockClient{
+					MockGet:   test.NewMockGetFn(nil),
+					MockPatch: test.NewMockPatchFn(errBoom),
+				},
+				desired: desired(),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errDryRun),
+			},
+		},
+		"NotFound": {
+			reason: "A resource that doesn't exist should be added.",
+			args: args{
+				client: &test.MockClient{
+					MockGet:   test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "cool-bucket")),
+					MockPatch: test.NewMockPatchFn(nil),
+				},
+				desired: desired(),
+			},
+			want: want{
+				typ:  ChangeTypeAdded,
+				diff: true,
+			},
+		},
+		"Unchanged": {
+			reason: "A resource that wouldn't change should be unchanged, ignoring server managed fields.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						u := obj.(*unstructured.Unstructured)
+						u.Object = desired().Object
+						u.SetResourceVersion("42")
+						u.SetUID("no-you-id")
+						return nil
+					}),
+					MockPatch: test.NewMockPatchFn(nil),
+				},
+				desired: desired(),
+			},
+			want: want{
+				typ: ChangeTypeUnchanged,
+			},
+		},
+		"Modified": {
+			reason: "A resource that would change should be modified.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						u := obj.(*unstructured.Unstructured)
+						u.Object = desired().Object
+						return unstructured.SetNestedField(u.Object, "eu-west-1", "spec", "region")
+					}),
+					MockPatch: test.NewMockPatchFn(nil),
+				},
+				desired: desired(),
+			},
+			want: want{
+				typ:  ChangeTypeModified,
+				diff: true,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			d := NewDiffer(tc.args.client)
+			got, err := d.Diff(context.Background(), "bucket", tc.args.desired, "owner")
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nDiff(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.typ, got.Type); diff != "" {
+				t.Errorf("\n%s\nDiff(...): -want type, +got type:\n%s", tc.reason, diff)
+			}
+			if gotDiff := got.Diff != ""; gotDiff != tc.want.diff {
+				t.Errorf("\n%s\nDiff(...): want diff %t, got diff %q", tc.reason, tc.want.diff, got.Diff)
+			}
+		})
+	}
+}
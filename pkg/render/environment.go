@@ -0,0 +1,198 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"sort"
+
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	"github.com/crossplane/crossplane/apis/apiextensions/v1alpha1"
+	"github.com/crossplane/crossplane/internal/controller/apiextensions/composite"
+)
+
+// An Environment to render an XR in, for example dev, staging, or prod.
+type Environment struct {
+	// Name of the environment.
+	Name string
+
+	// Resources are the EnvironmentConfigs, and any other resources, that
+	// make up the environment. They're passed to the Function pipeline as
+	// extra resources.
+	Resources []unstructured.Unstructured
+}
+
+// LoadEnvironment from a directory of YAML manifests. The environment is named
+// after the directory.
+func LoadEnvironment(fs afero.Fs, dir string) (Environment, error) {
+	rs, err := LoadExtraResources(fs, dir)
+	if err != nil {
+		return Environment{}, err
+	}
+	return Environment{Name: filepath.Base(filepath.Clean(dir)), Resources: rs}, nil
+}
+
+// Context returns the environment as a Function pipeline context value. Like
+// Crossplane, it merges the data of the environment's EnvironmentConfigs in
+// the order they were loaded.
+func (e Environment) Context() ([]byte, error) {
+	cfgs := make([]*v1alpha1.EnvironmentConfig, 0, len(e.Resources))
+	for i := range e.Resources {
+		if e.Resources[i].GroupVersionKind() != v1alpha1.EnvironmentConfigGroupVersionKind {
+			continue
+		}
+		cfg := &v1alpha1.EnvironmentConfig{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(e.Resources[i].Object, cfg); err != nil {
+			return nil, errors.Wrapf(err, "cannot convert EnvironmentConfig %q", e.Resources[i].GetName())
+		}
+		cfgs = append(cfgs, cfg)
+	}
+	env, err := composite.NewEnvironment(cfgs)
+	if err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(env)
+	return b, errors.Wrap(err, "cannot marshal environment to JSON")
+}
+
+// EnvironmentOutputs are the outputs of rendering an XR in an environment.
+type EnvironmentOutputs struct {
+	Environment string
+	Outputs     Outputs
+}
+
+// A Difference between how a resource rendered in an environment and how it
+// rendered in the baseline environment.
+type Difference struct {
+	// Environment the resource rendered differently in.
+	Environment string
+
+	// Resource is the Composition resource name of the composed resource
+	// that differs. It's empty if the composite resource differs.
+	Resource string
+
+	// Missing is true if the resource was rendered in the baseline
+	// environment, but not in this environment.
+	Missing bool
+
+	// Added is true if the resource was rendered in this environment, but
+	// not in the baseline environment.
+	Added bool
+
+	// Fields that differ, e.g. spec.forProvider.region.
+	Fields []string
+}
+
+// CompareEnvironments compares the composite and composed resources rendered
+// in each environment to those rendered in the first (baseline) environment.
+func CompareEnvironments(envs []EnvironmentOutputs) []Difference {
+	diffs := make([]Difference, 0)
+	if len(envs) < 2 {
+		return diffs
+	}
+
+	base := envs[0].Outputs
+	for _, e := range envs[1:] {
+		var bxr, xr map[string]any
+		if base.CompositeResource != nil {
+			bxr = base.CompositeResource.Object
+		}
+		if e.Outputs.CompositeResource != nil {
+			xr = e.Outputs.CompositeResource.Object
+		}
+		if f := diffFields("", bxr, xr); len(f) > 0 {
+			diffs = append(diffs, Difference{Environment: e.Environment, Fields: f})
+		}
+
+		bcds := composedByName(base)
+		cds := composedByName(e.Outputs)
+
+		names := make([]string, 0, len(bcds)+len(cds))
+		for n := range bcds {
+			names = append(names, n)
+		}
+		for n := range cds {
+			if _, ok := bcds[n]; !ok {
+				names = append(names, n)
+			}
+		}
+		sort.Strings(names)
+
+		for _, n := range names {
+			bcd, inBase := bcds[n]
+			cd, inEnv := cds[n]
+			switch {
+			case !inEnv:
+				diffs = append(diffs, Difference{Environment: e.Environment, Resource: n, Missing: true})
+			case !inBase:
+				diffs = append(diffs, Difference{Environment: e.Environment, Resource: n, Added: true})
+			default:
+				if f := diffFields("", bcd, cd); len(f) > 0 {
+					diffs = append(diffs, Difference{Environment: e.Environment, Resource: n, Fields: f})
+				}
+			}
+		}
+	}
+	return diffs
+}
+
+func composedByName(o Outputs) map[string]map[string]any {
+	out := make(map[string]map[string]any, len(o.ComposedResources))
+	for i := range o.ComposedResources {
+		out[o.ComposedResources[i].GetAnnotations()[AnnotationKeyCompositionResourceName]] = o.ComposedResources[i].Object
+	}
+	return out
+}
+
+// diffFields returns the paths of the leaf fields that differ between the
+// supplied values, sorted. Arrays are compared atomically.
+func diffFields(path string, a, b any) []string {
+	am, aok := a.(map[string]any)
+	bm, bok := b.(map[string]any)
+	if !aok || !bok {
+		if reflect.DeepEqual(a, b) {
+			return nil
+		}
+		return []string{path}
+	}
+
+	keys := make(map[string]bool, len(am)+len(bm))
+	for k := range am {
+		keys[k] = true
+	}
+	for k := range bm {
+		keys[k] = true
+	}
+
+	out := make([]string, 0)
+	for k := range keys {
+		p := k
+		if path != "" {
+			p = path + "." + k
+		}
+		out = append(out, diffFields(p, am[k], bm[k])...)
+	}
+	sort.Strings(out)
+	return out
+}
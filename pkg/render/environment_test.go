@@ -0,0 +1,173 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestEnvironmentContext(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	_ = afero.WriteFile(fs, "/envs/prod/a.yaml", []byte(`
+apiVersion: apiextensions.crossplane.io/v1alpha1
+kind: EnvironmentConfig
+metadata:
+  name: base
+data:
+  region: us-east-1
+  tier:
+    size: small
+    replicas: 1
+---
+apiVersion: example.org/v1
+kind: Unrelated
+metadata:
+  name: ignored
+data:
+  region: eu-west-1
+`), 0o600)
+	_ = afero.WriteFile(fs, "/envs/prod/b.yaml", []byte(`
+apiVersion: apiextensions.crossplane.io/v1alpha1
+kind: EnvironmentConfig
+metadata:
+  name: prod
+data:
+  tier:
+    size: large
+`), 0o600)
+
+	type want struct {
+		name      string
+		resources int
+		ctx       string
+		err       error
+	}
+	cases := map[string]struct {
+		reason string
+		dir    string
+		want   want
+	}{
+		"MergedEnvironmentConfigs": {
+			reason: "The data of the environment's EnvironmentConfigs should be merged in order, ignoring other resources.",
+			dir:    "/envs/prod/",
+			want: want{
+				name:      "prod",
+				resources: 3,
+				ctx:       `{"apiVersion":"internal.crossplane.io/v1alpha1","kind":"Environment","region":"us-east-1","tier":{"replicas":1,"size":"large"}}`,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			env, err := LoadEnvironment(fs, tc.dir)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nLoadEnvironment(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.name, env.Name); diff != "" {
+				t.Errorf("\n%s\nLoadEnvironment(...): -want name, +got name:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.resources, len(env.Resources)); diff != "" {
+				t.Errorf("\n%s\nLoadEnvironment(...): -want resources, +got resources:\n%s", tc.reason, diff)
+			}
+
+			ctx, err := env.Context()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want.ctx, string(ctx)); diff != "" {
+				t.Errorf("\n%s\nContext(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCompareEnvironments(t *testing.T) {
+	xr := func(region string) *composite.Unstructured {
+		xr := composite.New()
+		xr.SetAPIVersion("example.org/v1")
+		xr.SetKind("XBucket")
+		xr.SetName("cool-xr")
+		xr.Object["status"] = map[string]any{"region": region}
+		return xr
+	}
+	cd := func(name, region string) composed.Unstructured {
+		cd := composed.New()
+		cd.SetAPIVersion("example.org/v1")
+		cd.SetKind("Bucket")
+		cd.SetAnnotations(map[string]string{AnnotationKeyCompositionResourceName: name})
+		cd.Object["spec"] = map[string]any{"forProvider": map[string]any{"region": region, "tags": []any{"a"}}}
+		return *cd
+	}
+
+	cases := map[string]struct {
+		reason string
+		envs   []EnvironmentOutputs
+		want   []Difference
+	}{
+		"SingleEnvironment": {
+			reason: "There's nothing to compare with a single environment.",
+			envs: []EnvironmentOutputs{
+				{Environment: "dev", Outputs: Outputs{CompositeResource: xr("us-east-1")}},
+			},
+			want: []Difference{},
+		},
+		"Identical": {
+			reason: "Environments that render identically should have no differences.",
+			envs: []EnvironmentOutputs{
+				{Environment: "dev", Outputs: Outputs{CompositeResource: xr("us-east-1"), ComposedResources: []composed.Unstructured{cd("bucket", "us-east-1")}}},
+				{Environment: "prod", Outputs: Outputs{CompositeResource: xr("us-east-1"), ComposedResources: []composed.Unstructured{cd("bucket", "us-east-1")}}},
+			},
+			want: []Difference{},
+		},
+		"Different": {
+			reason: "We should report which resources and fields differ from the baseline environment.",
+			envs: []EnvironmentOutputs{
+				{Environment: "dev", Outputs: Outputs{
+					CompositeResource: xr("us-east-1"),
+					ComposedResources: []composed.Unstructured{cd("bucket", "us-east-1"), cd("cache", "us-east-1")},
+				}},
+				{Environment: "prod", Outputs: Outputs{
+					CompositeResource: xr("eu-west-1"),
+					ComposedResources: []composed.Unstructured{cd("bucket", "eu-west-1"), cd("replica", "eu-west-1")},
+				}},
+			},
+			want: []Difference{
+				{Environment: "prod", Fields: []string{"status.region"}},
+				{Environment: "prod", Resource: "bucket", Fields: []string{"spec.forProvider.region"}},
+				{Environment: "prod", Resource: "cache", Missing: true},
+				{Environment: "prod", Resource: "replica", Added: true},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := CompareEnvironments(tc.envs)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nCompareEnvironments(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -65,9 +65,6 @@ func LoadComposition(fs afero.Fs, file string) (*apiextensionsv1.Composition, er
 	}
 }
 
-// TODO(negz): Support optionally loading functions and observed resources from
-// a directory of manifests instead of a single stream.
-
 // LoadFunctions from a stream of YAML manifests.
 func LoadFunctions(filesys afero.Fs, file string) ([]pkgv1beta1.Function, error) {
 	stream, err := LoadYAMLStream(filesys, file)
@@ -111,7 +108,9 @@ func LoadExtraResources(fs afero.Fs, file string) ([]unstructured.Unstructured,
 	return resources, nil
 }
 
-// LoadObservedResources from a stream of YAML manifests.
+// LoadObservedResources from a stream of YAML manifests, or a directory of
+// them. Each observed resource must be annotated with the name of the
+// Composition resource it corresponds to, and no two may share a name.
 func LoadObservedResources(fs afero.Fs, file string) ([]composed.Unstructured, error) {
 	stream, err := LoadYAMLStream(fs, file)
 	if err != nil {
@@ -119,11 +118,24 @@ func LoadObservedResources(fs afero.Fs, file string) ([]composed.Unstructured, e
 	}
 
 	observed := make([]composed.Unstructured, 0, len(stream))
+	seen := make(map[string]bool, len(stream))
 	for _, y := range stream {
 		cd := composed.New()
 		if err := yaml.Unmarshal(y, cd); err != nil {
 			return nil, errors.Wrap(err, "cannot parse YAML composed resource manifest")
 		}
+		// Skip empty documents.
+		if len(cd.Object) == 0 {
+			continue
+		}
+		name := cd.GetAnnotations()[AnnotationKeyCompositionResourceName]
+		if name == "" {
+			return nil, errors.Errorf("observed resource %s %q must have the %s annotation", cd.GetKind(), cd.GetName(), AnnotationKeyCompositionResourceName)
+		}
+		if seen[name] {
+			return nil, errors.Errorf("more than one observed resource has composition resource name %q", name)
+		}
+		seen[name] = true
 		observed = append(observed, *cd)
 	}
 
@@ -242,17 +242,17 @@ func TestLoadFunctions(t *testing.T) {
 }
 
 func TestLoadObservedResources(t *testing.T) {
-	fs := afero.FromIOFS{FS: testdatafs}
-
 	type want struct {
 		ors []composed.Unstructured
 		err error
 	}
 	cases := map[string]struct {
+		fs   afero.Fs
 		file string
 		want want
 	}{
 		"Success": {
+			fs:   afero.FromIOFS{FS: testdatafs},
 			file: "testdata/observed.yaml",
 			want: want{
 				ors: []composed.Unstructured{
@@ -289,7 +289,103 @@ func TestLoadObservedResources(t *testing.T) {
 				},
 			},
 		},
+		"Directory": {
+			fs: afero.FromIOFS{FS: fstest.MapFS{
+				"observed/a.yaml": &fstest.MapFile{
+					Data: []byte(`---
+apiVersion: example.org/v1alpha1
+kind: ComposedResource
+metadata:
+  name: test-render-a
+  annotations:
+    crossplane.io/composition-resource-name: resource-a
+---
+`),
+				},
+				"observed/b.yaml": &fstest.MapFile{
+					Data: []byte(`---
+apiVersion: example.org/v1alpha1
+kind: ComposedResource
+metadata:
+  name: test-render-b
+  annotations:
+    crossplane.io/composition-resource-name: resource-b
+`),
+				},
+			}},
+			file: "observed",
+			want: want{
+				ors: []composed.Unstructured{
+					{
+						Unstructured: unstructured.Unstructured{Object: MustLoadJSON(`{
+							"apiVersion": "example.org/v1alpha1",
+							"kind": "ComposedResource",
+							"metadata": {
+								"name": "test-render-a",
+								"annotations": {
+									"crossplane.io/composition-resource-name": "resource-a"
+								}
+							}
+						}`)},
+					},
+					{
+						Unstructured: unstructured.Unstructured{Object: MustLoadJSON(`{
+							"apiVersion": "example.org/v1alpha1",
+							"kind": "ComposedResource",
+							"metadata": {
+								"name": "test-render-b",
+								"annotations": {
+									"crossplane.io/composition-resource-name": "resource-b"
+								}
+							}
+						}`)},
+					},
+				},
+			},
+		},
+		"MissingCompositionResourceName": {
+			fs: afero.FromIOFS{FS: fstest.MapFS{
+				"observed.yaml": &fstest.MapFile{
+					Data: []byte(`---
+apiVersion: example.org/v1alpha1
+kind: ComposedResource
+metadata:
+  name: test-render-a
+`),
+				},
+			}},
+			file: "observed.yaml",
+			want: want{
+				err: cmpopts.AnyError,
+			},
+		},
+		"DuplicateCompositionResourceName": {
+			fs: afero.FromIOFS{FS: fstest.MapFS{
+				"observed.yaml": &fstest.MapFile{
+					Data: []byte(`---
+apiVersion: example.org/v1alpha1
+kind: ComposedResource
+metadata:
+  name: test-render-a
+  annotations:
+    crossplane.io/composition-resource-name: resource-a
+---
+apiVersion: example.org/v1alpha1
+kind: ComposedResource
+metadata:
+  name: test-render-b
+  annotations:
+    crossplane.io/composition-resource-name: resource-a
+`),
+				},
+			}},
+			file: "observed.yaml",
+			want: want{
+				err: cmpopts.AnyError,
+			},
+		},
 		"NoSuchFile": {
+			fs:   afero.FromIOFS{FS: testdatafs},
 			file: "testdata/nonexist.yaml",
 			want: want{
 				err: cmpopts.AnyError,
@@ -299,7 +395,7 @@ func TestLoadObservedResources(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			xr, err := LoadObservedResources(fs, tc.file)
+			xr, err := LoadObservedResources(tc.fs, tc.file)
 
 			if diff := cmp.Diff(tc.want.ors, xr, test.EquateConditions()); diff != "" {
 				t.Errorf("LoadObservedResources(..), -want, +got:\n%s", diff)
@@ -14,6 +14,11 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
+// Package render implements composition rendering using composition functions.
+//
+// The package can be imported to embed rendering in other tools. Load inputs
+// using the Load functions, then call Render. The rendered Outputs can be
+// validated using the validate package.
 package render
 
 import (
@@ -90,6 +95,19 @@ type Outputs struct {
 	// appear ready?
 }
 
+// Resources returns the rendered composite resource and composed resources,
+// for example to validate them using the validate package.
+func (o Outputs) Resources() []*unstructured.Unstructured {
+	out := make([]*unstructured.Unstructured, 0, len(o.ComposedResources)+1)
+	if o.CompositeResource != nil {
+		out = append(out, &o.CompositeResource.Unstructured)
+	}
+	for i := range o.ComposedResources {
+		out = append(out, &o.ComposedResources[i].Unstructured)
+	}
+	return out
+}
+
 // Render the desired XR and composed resources, sorted by resource name, given the supplied inputs.
 func Render(ctx context.Context, in Inputs) (Outputs, error) { //nolint:gocyclo // TODO(negz): Should we refactor to break this up a bit?
 	// Run our Functions.
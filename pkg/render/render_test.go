@@ -1033,3 +1033,42 @@ func MustStructJSON(j string) *structpb.Struct {
 	}
 	return s
 }
+
+func TestOutputsResources(t *testing.T) {
+	xr := composite.New()
+	xr.SetName("test-render")
+	cd := composed.New()
+	cd.SetName("test-render-a")
+
+	cases := map[string]struct {
+		reason string
+		out    Outputs
+		want   []string
+	}{
+		"Empty": {
+			reason: "Empty outputs should have no resources.",
+			out:    Outputs{},
+			want:   []string{},
+		},
+		"CompositeAndComposed": {
+			reason: "The composite resource should be returned before the composed resources.",
+			out: Outputs{
+				CompositeResource: xr,
+				ComposedResources: []composed.Unstructured{*cd},
+			},
+			want: []string{"test-render", "test-render-a"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := make([]string, 0)
+			for _, r := range tc.out.Resources() {
+				got = append(got, r.GetName())
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("%s\nResources(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
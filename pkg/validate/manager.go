@@ -18,6 +18,7 @@ package validate
 
 import (
 	"fmt"
+	"io"
 
 	"github.com/spf13/afero"
 	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -34,8 +35,11 @@ import (
 	"github.com/crossplane/crossplane/internal/xcrd"
 )
 
+// DefaultCacheDir is the default directory, relative to the current working
+// directory, that package schemas are cached in.
+const DefaultCacheDir = ".crossplane/cache"
+
 const (
-	defaultCacheDir = ".crossplane/cache"
 	packageFileName = "package.yaml"
 	baseLayerLabel  = "base"
 
@@ -47,15 +51,18 @@ const (
 type Manager struct {
 	fetcher ImageFetcher
 	cache   Cache
+	dir     string
+	writer  io.Writer
 
 	crds  []*apiextv1.CustomResourceDefinition
 	deps  map[string]bool // One level dependency images
 	confs map[string]bool // Configuration images
 }
 
-// NewManager returns a new Manager
-func NewManager(cacheDir string, fs afero.Fs) *Manager {
-	m := &Manager{}
+// NewManager returns a new Manager. It writes progress messages, like which
+// packages it's downloading, to the supplied writer.
+func NewManager(cacheDir string, fs afero.Fs, w io.Writer) *Manager {
+	m := &Manager{dir: cacheDir, writer: w}
 
 	m.cache = &LocalCache{
 		fs:       fs,
@@ -140,9 +147,26 @@ func (m *Manager) PrepExtensions(extensions []*unstructured.Unstructured) error
 	return nil
 }
 
+// AddProviders adds the supplied provider package images as dependencies, so
+// that their CRDs are downloaded and loaded.
+func (m *Manager) AddProviders(images ...string) {
+	for _, image := range images {
+		m.deps[image] = true
+	}
+}
+
+// CRDs returns the CRDs the Manager has prepared and loaded. Resources can be
+// validated against them using Validate.
+func (m *Manager) CRDs() []*apiextv1.CustomResourceDefinition {
+	return m.crds
+}
+
 // CacheAndLoad finds and caches dependencies and loads them as CRDs
 func (m *Manager) CacheAndLoad(cleanCache bool) error {
 	if cleanCache {
+		if _, err := fmt.Fprintf(m.writer, "flushing cache directory: %s\n", m.dir); err != nil {
+			return errors.Wrap(err, errWriteOutput)
+		}
 		if err := m.cache.Flush(); err != nil {
 			return errors.Wrapf(err, "cannot flush cache directory")
 		}
@@ -218,7 +242,9 @@ func (m *Manager) cacheDependencies() error {
 			continue
 		}
 
-		fmt.Printf("package schemas does not exist, downloading: %s\n", image)
+		if _, err := fmt.Fprintf(m.writer, "package schemas does not exist, downloading: %s\n", image); err != nil {
+			return errors.Wrap(err, errWriteOutput)
+		}
 
 		layer, err := m.fetcher.FetchBaseLayer(image)
 		if err != nil {
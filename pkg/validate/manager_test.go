@@ -0,0 +1,84 @@
+package validate
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestManagerAddProviders(t *testing.T) {
+	type args struct {
+		extensions []*unstructured.Unstructured
+		providers  []string
+	}
+	type want struct {
+		deps map[string]bool
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoProviders": {
+			reason: "Adding no providers should not add any dependencies.",
+			args:   args{},
+			want: want{
+				deps: map[string]bool{},
+			},
+		},
+		"Providers": {
+			reason: "Each provider should be added as a dependency.",
+			args: args{
+				providers: []string{
+					"xpkg.upbound.io/crossplane-contrib/provider-aws:v0.44.0",
+					"xpkg.upbound.io/crossplane-contrib/provider-gcp:v0.22.0",
+				},
+			},
+			want: want{
+				deps: map[string]bool{
+					"xpkg.upbound.io/crossplane-contrib/provider-aws:v0.44.0": true,
+					"xpkg.upbound.io/crossplane-contrib/provider-gcp:v0.22.0": true,
+				},
+			},
+		},
+		"ProvidersAndExtensions": {
+			reason: "Providers should be merged with those found in the extensions.",
+			args: args{
+				extensions: []*unstructured.Unstructured{
+					{Object: map[string]any{
+						"apiVersion": "pkg.crossplane.io/v1",
+						"kind":       "Provider",
+						"metadata":   map[string]any{"name": "provider-aws"},
+						"spec":       map[string]any{"package": "xpkg.upbound.io/crossplane-contrib/provider-aws:v0.44.0"},
+					}},
+				},
+				providers: []string{
+					"xpkg.upbound.io/crossplane-contrib/provider-aws:v0.44.0",
+					"xpkg.upbound.io/crossplane-contrib/provider-gcp:v0.22.0",
+				},
+			},
+			want: want{
+				deps: map[string]bool{
+					"xpkg.upbound.io/crossplane-contrib/provider-aws:v0.44.0": true,
+					"xpkg.upbound.io/crossplane-contrib/provider-gcp:v0.22.0": true,
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := NewManager("", nil, &bytes.Buffer{})
+			if err := m.PrepExtensions(tc.args.extensions); err != nil {
+				t.Fatalf("PrepExtensions(...): %v", err)
+			}
+			m.AddProviders(tc.args.providers...)
+
+			if diff := cmp.Diff(tc.want.deps, m.deps); diff != "" {
+				t.Errorf("%s\nAddProviders(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package validate implements offline schema validation of Crossplane resources.
+//
+// The package can be imported to embed validation in other tools. Use a
+// Manager to load CRDs from XRDs, CRDs, and packages, then Validate resources
+// against them.
+package validate
+
+import (
+	"fmt"
+	"io"
+
+	ext "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	"github.com/crossplane/crossplane/internal/controller/apiextensions/composite"
+)
+
+func newValidators(crds []*extv1.CustomResourceDefinition) (map[schema.GroupVersionKind][]validation.SchemaValidator, error) {
+	validators := map[schema.GroupVersionKind][]validation.SchemaValidator{}
+
+	for i := range crds {
+		internal := &ext.CustomResourceDefinition{}
+		if err := extv1.Convert_v1_CustomResourceDefinition_To_apiextensions_CustomResourceDefinition(crds[i], internal, nil); err != nil {
+			return nil, err
+		}
+
+		// Top-level and per-version schemas are mutually exclusive. Therefore, we will use both if they are present.
+		for _, ver := range internal.Spec.Versions {
+			var sv validation.SchemaValidator
+			var err error
+
+			gvk := schema.GroupVersionKind{
+				Group:   internal.Spec.Group,
+				Version: ver.Name,
+				Kind:    internal.Spec.Names.Kind,
+			}
+
+			// Version specific validation rules
+			if ver.Schema != nil && ver.Schema.OpenAPIV3Schema != nil {
+				sv, _, err = validation.NewSchemaValidator(ver.Schema.OpenAPIV3Schema)
+				if err != nil {
+					return nil, err
+				}
+
+				validators[gvk] = append(validators[gvk], sv)
+			}
+
+			// Top level validation rules
+			if internal.Spec.Validation != nil {
+				sv, _, err = validation.NewSchemaValidator(internal.Spec.Validation.OpenAPIV3Schema)
+				if err != nil {
+					return nil, err
+				}
+
+				validators[gvk] = append(validators[gvk], sv)
+			}
+		}
+	}
+
+	return validators, nil
+}
+
+// A ResultStatus indicates whether a resource is valid.
+type ResultStatus string
+
+const (
+	errWriteOutput = "cannot write output"
+)
+
+// Result statuses.
+const (
+	// ResultValid indicates a resource passed schema validation.
+	ResultValid ResultStatus = "Valid"
+
+	// ResultInvalid indicates a resource failed schema validation.
+	ResultInvalid ResultStatus = "Invalid"
+
+	// ResultMissingSchema indicates no CRD or XRD defines the resource's
+	// kind, so it couldn't be validated.
+	ResultMissingSchema ResultStatus = "MissingSchema"
+)
+
+// A Result of validating a resource.
+type Result struct {
+	// Resource that was validated.
+	Resource *unstructured.Unstructured
+
+	// Status of the resource.
+	Status ResultStatus
+
+	// Errors encountered validating the resource. Only set if the resource
+	// is invalid.
+	Errors []error
+}
+
+// Validate the supplied resources against the supplied CRDs. A result is
+// returned for each resource, in the order the resources were supplied. It
+// doesn't print anything, so tools that embed Crossplane validation (e.g. in
+// Git hooks) can decide how to report the results.
+func Validate(resources []*unstructured.Unstructured, crds []*extv1.CustomResourceDefinition) ([]Result, error) {
+	schemaValidators, err := newValidators(crds)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot create schema validators")
+	}
+
+	results := make([]Result, len(resources))
+	for i, r := range resources {
+		results[i] = Result{Resource: r, Status: ResultValid}
+
+		resourceValidators, ok := schemaValidators[r.GetObjectKind().GroupVersionKind()]
+		if !ok {
+			results[i].Status = ResultMissingSchema
+			continue
+		}
+
+		for _, v := range resourceValidators {
+			re := v.Validate(&resources[i])
+			for _, e := range re.Errors {
+				results[i].Status = ResultInvalid
+				results[i].Errors = append(results[i].Errors, e)
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// SchemaValidation validates the resources against the given CRDs, and writes
+// the results to the supplied writer.
+func SchemaValidation(resources []*unstructured.Unstructured, crds []*extv1.CustomResourceDefinition, skipSuccessLogs bool, w io.Writer) error { //nolint:gocyclo // Mostly writing output.
+	results, err := Validate(resources, crds)
+	if err != nil {
+		return err
+	}
+
+	failure, warning := 0, 0
+
+	for _, res := range results {
+		r := res.Resource
+		switch res.Status {
+		case ResultMissingSchema:
+			warning++
+			if _, err := fmt.Fprintf(w, "[!] could not find CRD/XRD for: %s\n", r.GroupVersionKind().String()); err != nil {
+				return errors.Wrap(err, errWriteOutput)
+			}
+		case ResultInvalid:
+			failure++
+			for _, e := range res.Errors {
+				if _, err := fmt.Fprintf(w, "[x] validation error %s, %s : %s\n", r.GroupVersionKind().String(), r.GetAnnotations()[composite.AnnotationKeyCompositionResourceName], e.Error()); err != nil {
+					return errors.Wrap(err, errWriteOutput)
+				}
+			}
+		case ResultValid:
+			if skipSuccessLogs {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "[✓] %s, %s validated successfully\n", r.GroupVersionKind().String(), r.GetAnnotations()[composite.AnnotationKeyCompositionResourceName]); err != nil {
+				return errors.Wrap(err, errWriteOutput)
+			}
+		}
+	}
+
+	if _, err := fmt.Fprintf(w, "%d error, %d warning, %d success cases\n", failure, warning, len(resources)-failure-warning); err != nil {
+		return errors.Wrap(err, errWriteOutput)
+	}
+
+	if failure > 0 {
+		return errors.New("could not validate all resources")
+	}
+
+	return nil
+}
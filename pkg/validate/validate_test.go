@@ -17,6 +17,7 @@ limitations under the License.
 package validate
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -1095,7 +1096,7 @@ func TestConvertToCRDs(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			m := NewManager("", nil)
+			m := NewManager("", nil, &bytes.Buffer{})
 			err := m.PrepExtensions(tc.args.schemas)
 
 			if diff := cmp.Diff(tc.want.crd, m.crds); diff != "" {
@@ -1191,7 +1192,7 @@ func TestValidateResources(t *testing.T) {
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			got := SchemaValidation(tc.args.resources, tc.args.crds, false)
+			got := SchemaValidation(tc.args.resources, tc.args.crds, false, &bytes.Buffer{})
 
 			if diff := cmp.Diff(tc.want.err, got, test.EquateErrors()); diff != "" {
 				t.Errorf("%s\nvalidateResources(...): -want error, +got error:\n%s", tc.reason, diff)
@@ -1199,3 +1200,89 @@ func TestValidateResources(t *testing.T) {
 		})
 	}
 }
+
+func TestValidate(t *testing.T) {
+	resource := func(replicas any) *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "test.org/v1alpha1",
+				"kind":       "Test",
+				"metadata": map[string]interface{}{
+					"name": "test",
+				},
+				"spec": map[string]interface{}{
+					"replicas": replicas,
+				},
+			},
+		}
+	}
+
+	type args struct {
+		resources []*unstructured.Unstructured
+		crds      []*extv1.CustomResourceDefinition
+	}
+	type want struct {
+		statuses []ResultStatus
+		errors   []int
+		err      error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Valid": {
+			reason: "Should return a valid result for each valid resource",
+			args: args{
+				resources: []*unstructured.Unstructured{resource(1)},
+				crds:      []*extv1.CustomResourceDefinition{testCRD},
+			},
+			want: want{
+				statuses: []ResultStatus{ResultValid},
+				errors:   []int{0},
+			},
+		},
+		"Invalid": {
+			reason: "Should return an invalid result, including validation errors, for each invalid resource",
+			args: args{
+				resources: []*unstructured.Unstructured{resource(1), resource("non-integer")},
+				crds:      []*extv1.CustomResourceDefinition{testCRD},
+			},
+			want: want{
+				statuses: []ResultStatus{ResultValid, ResultInvalid},
+				errors:   []int{0, 1},
+			},
+		},
+		"MissingSchema": {
+			reason: "Should return a missing schema result for each resource without a CRD/XRD",
+			args: args{
+				resources: []*unstructured.Unstructured{resource(1)},
+			},
+			want: want{
+				statuses: []ResultStatus{ResultMissingSchema},
+				errors:   []int{0},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			results, err := Validate(tc.args.resources, tc.args.crds)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("%s\nValidate(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			statuses := make([]ResultStatus, len(results))
+			errs := make([]int, len(results))
+			for i, r := range results {
+				statuses[i] = r.Status
+				errs[i] = len(r.Errors)
+			}
+			if diff := cmp.Diff(tc.want.statuses, statuses); diff != "" {
+				t.Errorf("%s\nValidate(...): -want statuses, +got statuses:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.errors, errs); diff != "" {
+				t.Errorf("%s\nValidate(...): -want error counts, +got error counts:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -484,7 +484,7 @@ func IsValidInputForTransform(t *v1.Transform, fromType v1.TransformIOType) erro
 			if fromType != v1.TransformIOTypeString {
 				return errors.Errorf("string transform can only be used with string input types, got %s", fromType)
 			}
-		case v1.StringTransformTypeFormat:
+		case v1.StringTransformTypeFormat, v1.StringTransformTypeTemplate:
 			// any input type is valid
 		case v1.StringTransformTypeConvert:
 			if t.String.Convert == nil {
@@ -89,7 +89,7 @@ func getReadinessCheckExpectedType(r v1.ReadinessCheck) xpschema.KnownJSONType {
 		matchType = xpschema.KnownJSONTypeInteger
 	case v1.ReadinessCheckTypeMatchTrue, v1.ReadinessCheckTypeMatchFalse:
 		matchType = xpschema.KnownJSONTypeBoolean
-	case v1.ReadinessCheckTypeNone, v1.ReadinessCheckTypeNonEmpty, v1.ReadinessCheckTypeMatchCondition:
+	case v1.ReadinessCheckTypeNone, v1.ReadinessCheckTypeNonEmpty, v1.ReadinessCheckTypeMatchCondition, v1.ReadinessCheckTypeMatchExpression:
 	}
 	return matchType
 }